@@ -8,14 +8,10 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"os/exec"
-	"path/filepath"
 
 	"aahframework.org/aah.v0"
 	"aahframework.org/aruntime.v0"
 	"aahframework.org/config.v0"
-	"aahframework.org/essentials.v0"
-	"aahframework.org/log.v0"
 )
 
 // Version no. of aah framework CLI tool
@@ -38,9 +34,16 @@ var (
 	gosrcDir string
 	subCmds  commands
 
+	// global flags
+	dryRunFlag     = flag.Bool("dry-run", false, "Print what the command would do without touching the file system or network")
+	profileCLIFlag = flag.Bool("profile-cli", false, "Record CPU/heap profiles of aah CLI tool into '.aah/profile' and print stage timing")
+	statusFileFlag = flag.String("status-file", "", "Write the command status, durations, warnings and artifacts as JSON into given file for CI")
+	logFileFlag    = flag.String("log-file", "", "Mirror the CLI output into rotating log file e.g: '.aah/logs/aah.log'. Default is 'log.file' of '~/.aah/cli.conf' if 'log.enable' is true")
+	logFormatFlag  = flag.String("log-format", "", "CLI output format 'text' or 'json', json writes structured log events per line. Default is 'log.format' of '~/.aah/cli.conf' or 'text'")
+
 	// abstract it, so we can do unit test
-	fatal  = log.Fatal
-	fatalf = log.Fatalf
+	fatal  = func(v ...interface{}) { fatalExit(exitError, v...) }
+	fatalf = func(format string, v ...interface{}) { fatalExitf(exitError, format, v...) }
 	exit   = os.Exit
 )
 
@@ -52,29 +55,47 @@ func main() {
 			cfg, _ := config.ParseString(``)
 			strace := aruntime.NewStacktrace(r, cfg)
 			strace.Print(os.Stdout)
-			exit(2)
+			exit(exitError)
 		}
 	}()
 
-	// check go is installed or not
-	if !ess.LookExecutable("go") {
-		fatal("Unable to find Go executable in PATH")
-	}
-
-	var err error
+	flag.Parse()
+	args := flag.Args()
 
-	// get GOPATH, refer https://godoc.org/aahframework.org/essentials.v0#GoPath
-	if gopath, err = ess.GoPath(); err != nil {
-		fatal(err)
+	cliCfg := loadCLIConfig()
+	applyNetworkConfig(cliCfg)
+	startCLILog(args, cliCfg)
+	if isCLILog() {
+		osExit := exit
+		exit = func(code int) {
+			stopCLILog()
+			osExit(code)
+		}
 	}
-
-	if gocmd, err = exec.LookPath("go"); err != nil {
-		fatal(err)
+	startJSONLog(args, cliCfg)
+	if isJSONLog() {
+		osExit := exit
+		exit = func(code int) {
+			stopJSONLog(code)
+			osExit(code)
+		}
 	}
 
-	flag.Parse()
-	args := flag.Args()
-	gosrcDir = filepath.Join(gopath, "src")
+	startCLIProfile()
+	if isCLIProfile() {
+		osExit := exit
+		exit = func(code int) {
+			stopCLIProfile()
+			osExit(code)
+		}
+	}
+	if isStatusFile() {
+		osExit := exit
+		exit = func(code int) {
+			writeStatusFile(args, code)
+			osExit(code)
+		}
+	}
 
 	printHeader()
 	if len(args) == 0 {
@@ -87,14 +108,12 @@ func main() {
 		commandNotFound(args[0])
 	}
 
-	// Validate command arguments count
-	if len(args)-1 > cmd.ArgsCount {
-		fatal("Too many arguments given. Run 'aah help command'.\n\n")
-	}
-
 	// running command
-	cmd.Run(args[1:])
-	return
+	cmd.Exec(args[1:])
+	stopCLIProfile()
+	writeStatusFile(args, exitOK)
+	stopJSONLog(exitOK)
+	stopCLILog()
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -102,6 +121,9 @@ func main() {
 //___________________________________
 
 func printHeader() {
+	if isJSONLog() {
+		return
+	}
 	if !isWindowsOS() {
 		fmt.Fprintf(os.Stdout, fmt.Sprintf("\033[1;32m%v\033[0m\n", header), aah.Version)
 		return
@@ -114,10 +136,36 @@ func init() {
 	// which commands are printed by 'aah help'.
 	subCmds = commands{
 		newCmd,
+		initCmd,
 		runCmd,
 		buildCmd,
+		testCmd,
+		benchCmd,
+		loadtestCmd,
+		packageCmd,
+		deployCmd,
+		releaseCmd,
 		listCmd,
+		generateCmd,
+		mvCmd,
+		fmtCmd,
+		lintCmd,
+		auditCmd,
+		configCmd,
+		i18nCmd,
+		routesCmd,
+		exportCmd,
+		apiCmd,
+		depsCmd,
+		sbomCmd,
+		secretsCmd,
+		migrateCmd,
+		dbCmd,
+		docsCmd,
+		doctorCmd,
+		cacheCmd,
 		versionCmd,
+		updateCmd,
 		helpCmd,
 	}
 }
@@ -0,0 +1,18 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+var apiCmd = &command{
+	Name:      "api",
+	UsageLine: "aah api <subcommand> [arguments]",
+	Short:     "inspect application API across versions",
+	Long: `
+Inspects the application API described by the OpenAPI document of the routes
+and controllers (refer 'aah generate openapi').
+
+Example:
+    aah api diff v1.0.0
+`,
+}
@@ -0,0 +1,394 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+)
+
+var (
+	apiDiffCmdFlags   = flag.NewFlagSet("diff", flag.ContinueOnError)
+	apiDiffDomainFlag = apiDiffCmdFlags.String("domain", "", "Compare the routes of given domain only")
+	apiDiffJSONFlag   = apiDiffCmdFlags.Bool("json", false, "Print the changes as JSON")
+	apiDiffCmd        = &command{
+		Name:      "diff",
+		UsageLine: "aah api diff [-domain] [-json] <git-ref>",
+		Flags:     apiDiffCmdFlags,
+		MaxArgs:   1,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "report breaking API changes since git ref",
+		Long: `
+Generates the OpenAPI document (refer 'aah generate openapi') of the current
+tree and the given git ref (tag, branch or commit), and reports the changes
+between them. Breaking changes are:
+
+    - removed operation, i.e. route path and HTTP method
+    - changed parameter, request body or success response type
+    - added required parameter, request body or authentication
+    - parameter became required
+    - removed response or schema property, changed property type and
+      added required property
+
+Command exits with code 10 (refer 'aah help exit-codes') if there is any
+breaking change, so it can be used as CI gate.
+
+Example(s):
+    aah api diff v1.2.0
+
+    aah api diff -json origin/master
+`,
+	}
+
+	apiPathParamRegex = regexp.MustCompile(`\{[^/]+\}`)
+)
+
+// apiChange is the change of the application API between two OpenAPI
+// documents.
+type apiChange struct {
+	Breaking  bool   `json:"breaking"`
+	Operation string `json:"operation"`
+	Message   string `json:"message"`
+}
+
+func apiDiffRun(args []string) {
+	if len(args) == 0 {
+		fatalExit(exitUsage, "Git ref is required, e.g. 'aah api diff v1.2.0'. Run 'aah help api diff'.")
+		return
+	}
+	ref := args[0]
+
+	dir, err := ioutil.TempDir("", "aah-api-diff")
+	if err != nil {
+		fatal(err)
+		return
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	if err = gitExtractApp(appProject.BaseDir, ref, dir); err != nil {
+		fatalExit(exitUsage, err)
+		return
+	}
+	prev, err := appOpenAPIDoc(dir, *apiDiffDomainFlag, nil)
+	if err != nil {
+		fatalErr(newExitErr(exitCodeOf(err), fmt.Errorf("%s: %s", ref, err)))
+		return
+	}
+	cur, err := appOpenAPIDoc(appProject.BaseDir, *apiDiffDomainFlag, nil)
+	if err != nil {
+		fatalErr(err)
+		return
+	}
+
+	changes := diffOpenAPIDocs(prev, cur)
+	if *apiDiffJSONFlag {
+		if changes == nil {
+			changes = []*apiChange{}
+		}
+		b, err := json.MarshalIndent(changes, "", "  ")
+		if err != nil {
+			fatal(err)
+			return
+		}
+		fmt.Println(string(b))
+	} else {
+		writeAPIChanges(os.Stdout, ref, changes)
+	}
+
+	for _, c := range changes {
+		if c.Breaking {
+			exit(exitAPIBreaking)
+			return
+		}
+	}
+}
+
+// diffOpenAPIDocs method returns the changes from prev to cur document.
+// Operations are matched by HTTP method and path, path parameter names are
+// not significant. Breaking changes are listed first.
+func diffOpenAPIDocs(prev, cur *openAPIDoc) []*apiChange {
+	d := &apiDiff{prev: prev, cur: cur}
+
+	curOps := openAPIOperations(cur)
+	prevOps := openAPIOperations(prev)
+	for _, k := range sortedKeys(prevOps) {
+		if c, found := curOps[k]; found {
+			d.operation(prevOps[k], c)
+		} else {
+			d.add(true, prevOps[k].Key, "operation removed")
+		}
+	}
+	for _, k := range sortedKeys(curOps) {
+		if _, found := prevOps[k]; !found {
+			d.add(false, curOps[k].Key, "operation added")
+		}
+	}
+	d.schemas()
+
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		return d.changes[i].Breaking && !d.changes[j].Breaking
+	})
+	return d.changes
+}
+
+func writeAPIChanges(w io.Writer, ref string, changes []*apiChange) {
+	if len(changes) == 0 {
+		fmt.Fprintf(w, "No API changes since %s\n", ref)
+		return
+	}
+
+	width, breaking := 0, 0
+	for _, c := range changes {
+		if len(c.Operation) > width {
+			width = len(c.Operation)
+		}
+		if c.Breaking {
+			breaking++
+		}
+	}
+	for _, c := range changes {
+		kind := ""
+		if c.Breaking {
+			kind = "BREAKING"
+		}
+		fmt.Fprintf(w, "%-8s  %-*s  %s\n", kind, width, c.Operation, c.Message)
+	}
+	fmt.Fprintf(w, "\n%d breaking, %d other change(s) since %s\n", breaking, len(changes)-breaking, ref)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// apiDiff methods
+//___________________________________
+
+type (
+	apiDiff struct {
+		prev, cur *openAPIDoc
+		changes   []*apiChange
+	}
+
+	apiOperation struct {
+		Key string
+		*openAPIOperation
+	}
+)
+
+func (d *apiDiff) add(breaking bool, operation, format string, v ...interface{}) {
+	d.changes = append(d.changes, &apiChange{Breaking: breaking, Operation: operation, Message: fmt.Sprintf(format, v...)})
+}
+
+func (d *apiDiff) operation(prev, cur *apiOperation) {
+	key := prev.Key
+	prevParams, curParams := openAPIParams(prev.Parameters), openAPIParams(cur.Parameters)
+	for _, k := range sortedKeys(prevParams) {
+		p, c := prevParams[k], curParams[k]
+		switch {
+		case c == nil:
+			d.add(false, key, "%s parameter '%s' removed", p.In, p.Name)
+		case openAPITypeName(p.Schema) != openAPITypeName(c.Schema):
+			d.add(true, key, "%s parameter '%s' type changed from %s to %s", p.In, p.Name, openAPITypeName(p.Schema), openAPITypeName(c.Schema))
+		case !p.Required && c.Required:
+			d.add(true, key, "%s parameter '%s' is required now", p.In, p.Name)
+		}
+	}
+	for _, k := range sortedKeys(curParams) {
+		if c := curParams[k]; prevParams[k] == nil {
+			d.add(c.Required, key, "%s parameter '%s' added", c.In, c.Name)
+		}
+	}
+
+	prevBody, curBody := openAPIBodySchema(prev.RequestBody), openAPIBodySchema(cur.RequestBody)
+	switch {
+	case prevBody == nil && curBody != nil:
+		d.add(true, key, "request body %s added", openAPITypeName(curBody))
+	case prevBody != nil && curBody == nil:
+		d.add(false, key, "request body removed")
+	case prevBody != nil && openAPITypeName(prevBody) != openAPITypeName(curBody):
+		d.add(true, key, "request body type changed from %s to %s", openAPITypeName(prevBody), openAPITypeName(curBody))
+	}
+
+	for _, code := range sortedKeys(prev.Responses) {
+		p, c := prev.Responses[code], cur.Responses[code]
+		if c == nil {
+			d.add(strings.HasPrefix(code, "2"), key, "response %s removed", code)
+			continue
+		}
+		ps, cs := openAPIContentSchema(p.Content), openAPIContentSchema(c.Content)
+		if ps != nil && openAPITypeName(ps) != openAPITypeName(cs) {
+			d.add(strings.HasPrefix(code, "2"), key, "response %s type changed from %s to %s", code, openAPITypeName(ps), openAPITypeName(cs))
+		}
+	}
+
+	if len(prev.Security) == 0 && len(cur.Security) > 0 {
+		d.add(true, key, "authentication is required now")
+	} else if len(prev.Security) > 0 && len(cur.Security) == 0 {
+		d.add(false, key, "authentication is not required anymore")
+	}
+	if !prev.Deprecated && cur.Deprecated {
+		d.add(false, key, "operation deprecated")
+	}
+}
+
+// schemas method compares the properties of component schemas exists in
+// both documents.
+func (d *apiDiff) schemas() {
+	if d.prev.Components == nil || d.cur.Components == nil {
+		return
+	}
+	for _, name := range sortedKeys(d.prev.Components.Schemas) {
+		p, c := d.prev.Components.Schemas[name], d.cur.Components.Schemas[name]
+		if c == nil {
+			continue
+		}
+
+		key := "schema " + name
+		for _, prop := range sortedKeys(p.Properties) {
+			cp := c.Properties[prop]
+			switch {
+			case cp == nil:
+				d.add(true, key, "property '%s' removed", prop)
+			case openAPITypeName(p.Properties[prop]) != openAPITypeName(cp):
+				d.add(true, key, "property '%s' type changed from %s to %s", prop, openAPITypeName(p.Properties[prop]), openAPITypeName(cp))
+			}
+		}
+		for _, prop := range sortedKeys(c.Properties) {
+			if p.Properties[prop] == nil {
+				d.add(false, key, "property '%s' added", prop)
+			}
+		}
+		for _, prop := range c.Required {
+			if !ess.IsSliceContainsString(p.Required, prop) {
+				d.add(true, key, "property '%s' is required now", prop)
+			}
+		}
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// gitExtractApp method writes the 'config' directory and Go sources of 'app'
+// directory of the application at git ref into given directory.
+func gitExtractApp(baseDir, ref, dir string) error {
+	if _, err := gitOutput(baseDir, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err != nil {
+		return fmt.Errorf("unknown git ref '%s'", ref)
+	}
+
+	// paths of ls-tree are relative to the application base directory
+	files, err := gitOutput(baseDir, "ls-tree", "-r", "--name-only", ref, "--", "config", "app")
+	if err != nil {
+		return err
+	}
+	for _, f := range strings.Split(files, "\n") {
+		if f == "" || (strings.HasPrefix(f, "app/") && !strings.HasSuffix(f, ".go")) {
+			continue
+		}
+
+		cmd := exec.Command("git", "show", ref+":./"+f)
+		cmd.Dir = baseDir
+		b, err := cmd.Output()
+		if err != nil {
+			return fmt.Errorf("git show %s:%s: %s", ref, f, err)
+		}
+
+		file := filepath.Join(dir, filepath.FromSlash(f))
+		if err = os.MkdirAll(filepath.Dir(file), permRWXRXRX); err != nil {
+			return err
+		}
+		if err = ioutil.WriteFile(file, b, permRWRR); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openAPIOperations method returns the operations of the document keyed by
+// HTTP method and path with unnamed parameters.
+func openAPIOperations(doc *openAPIDoc) map[string]*apiOperation {
+	ops := map[string]*apiOperation{}
+	for path, methods := range doc.Paths {
+		for method, op := range methods {
+			m := strings.ToUpper(method)
+			ops[m+" "+apiPathParamRegex.ReplaceAllString(path, "{}")] = &apiOperation{Key: m + " " + path, openAPIOperation: op}
+		}
+	}
+	return ops
+}
+
+// openAPIParams method returns the parameters keyed by location and name,
+// path parameters are keyed by position.
+func openAPIParams(params []*openAPIParameter) map[string]*openAPIParameter {
+	m := map[string]*openAPIParameter{}
+	pos := 0
+	for _, p := range params {
+		if p.In == "path" {
+			m["path:"+strconv.Itoa(pos)] = p
+			pos++
+			continue
+		}
+		m[p.In+":"+p.Name] = p
+	}
+	return m
+}
+
+func openAPIBodySchema(body *openAPIRequestBody) *openAPISchema {
+	if body == nil {
+		return nil
+	}
+	return openAPIContentSchema(body.Content)
+}
+
+func openAPIContentSchema(content map[string]*openAPIMediaType) *openAPISchema {
+	if mt, found := content["application/json"]; found {
+		return mt.Schema
+	}
+	return nil
+}
+
+// openAPITypeName method returns the readable type of the schema, e.g.
+// 'integer(int64)', '[]models.User', 'map[string]string'.
+func openAPITypeName(s *openAPISchema) string {
+	switch {
+	case s == nil:
+		return "none"
+	case s.Ref != "":
+		return strings.TrimPrefix(s.Ref, "#/components/schemas/")
+	case s.Type == "array":
+		return "[]" + openAPITypeName(s.Items)
+	case s.Type == "object" && s.AdditionalProperties != nil:
+		return "map[string]" + openAPITypeName(s.AdditionalProperties)
+	case s.Format != "":
+		return s.Type + "(" + s.Format + ")"
+	}
+	return s.Type
+}
+
+// sortedKeys method returns the sorted keys of the string keyed map.
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	for _, k := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func init() {
+	apiDiffCmd.Run = apiDiffRun
+	apiCmd.Subcommands = append(apiCmd.Subcommands, apiDiffCmd)
+}
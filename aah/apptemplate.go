@@ -0,0 +1,115 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+// Custom application templates are the user provided project skeletons for
+// 'aah new -template'. It can be a local directory or a git repository, git
+// repositories are cloned into '~/.aah/templates'. Files with extension
+// '.atmpl' are rendered with application data e.g. AppName, ImportPath,
+// Author; other files are copied as-is.
+
+// resolveAppTemplate method returns the local directory of given template,
+// remote template is cloned or updated into templates cache directory.
+func resolveAppTemplate(tmpl string) (string, error) {
+	if ess.IsFileExists(tmpl) {
+		return filepath.Abs(tmpl)
+	}
+
+	gitcmd, err := exec.LookPath("git")
+	if err != nil {
+		return "", fmt.Errorf("git is required for remote template '%s'", tmpl)
+	}
+
+	repoURL := appTemplateRepoURL(tmpl)
+	tmplDir := filepath.Join(aahUserDir(), "templates", appTemplateDirName(tmpl))
+	if ess.IsFileExists(filepath.Join(tmplDir, ".git")) {
+		log.Infof("Updating template %s", repoURL)
+		if _, err = execCmd(gitcmd, []string{"-C", tmplDir, "pull", "--ff-only"}, false); err != nil {
+			log.Errorf("Unable to update template, using cached copy: %s", err)
+		}
+		return tmplDir, nil
+	}
+
+	log.Infof("Cloning template %s", repoURL)
+	if err = mkDirAll(filepath.Dir(tmplDir), permRWXRXRX); err != nil {
+		return "", err
+	}
+	if _, err = execCmd(gitcmd, []string{"clone", "--depth", "1", repoURL, tmplDir}, false); err != nil {
+		return "", err
+	}
+	return tmplDir, nil
+}
+
+// createAppFromTemplate method renders the custom template directory into
+// application directory.
+func createAppFromTemplate(appDir, tmplDir string, data map[string]interface{}) error {
+	if isDryRun() && !ess.IsFileExists(tmplDir) {
+		logDryRun("render template %s into %s", tmplDir, appDir)
+		return nil
+	}
+
+	if err := mkDirAll(appDir, permRWXRXRX); err != nil {
+		return err
+	}
+
+	return ess.Walk(tmplDir, func(srcPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		processFile(appDir, tmplDir, srcPath, data)
+		return nil
+	})
+}
+
+// appTemplateRepoURL method returns the git clone URL for given template,
+// for e.g.: 'github.com/org/aah-template-foo' becomes
+// 'https://github.com/org/aah-template-foo.git'.
+func appTemplateRepoURL(tmpl string) string {
+	if strings.Contains(tmpl, "://") || strings.HasPrefix(tmpl, "git@") {
+		return tmpl
+	}
+	return "https://" + strings.TrimSuffix(tmpl, ".git") + ".git"
+}
+
+func appTemplateDirName(tmpl string) string {
+	name := tmpl
+	if idx := strings.Index(name, "://"); idx >= 0 {
+		name = name[idx+3:]
+	}
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "git@"), ".git")
+	return strings.NewReplacer(":", "_", "/", "_", "@", "_").Replace(name)
+}
+
+// getAuthor method returns the author name from git config 'user.name'
+// otherwise from the environment.
+func getAuthor() string {
+	if gitcmd, err := exec.LookPath("git"); err == nil {
+		if output, err := exec.Command(gitcmd, "config", "user.name").Output(); err == nil {
+			if author := strings.TrimSpace(string(output)); !ess.IsStrEmpty(author) {
+				return author
+			}
+		}
+	}
+	return firstNonEmpty(os.Getenv("USER"), os.Getenv("USERNAME"))
+}
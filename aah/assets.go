@@ -0,0 +1,687 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+// Asset build stage of 'aah build' and 'aah package', it's configured via
+// 'build.assets' of 'aah.project'. JS and CSS files of 'static' directory
+// are minified and PNG, JPEG images are losslessly optimized into the dist
+// directory, packaging takes the dist file in place of the source file. Static files are fingerprinted at packaging,
+// content hashed copy of the file is added along with the asset manifest.
+// External asset compilers, such as Sass, Less or TypeScript, run ahead of
+// the stage and on their source changes during 'aah run'.
+
+const (
+	assetsStaticDir = "static"
+
+	// assetsHashLen is the length of content hash in the fingerprinted file
+	// name, e.g. 'app.3f2a9c1e.js'.
+	assetsHashLen = 8
+)
+
+// assetsConfig is the 'build.assets' section of 'aah.project'.
+type assetsConfig struct {
+	Enable              bool
+	Minify              bool
+	OptimizeImages      bool
+	Dist                string
+	Excludes            []string
+	Fingerprint         bool
+	FingerprintExcludes []string
+	Manifest            string
+}
+
+// newAssetsConfig method returns the asset build config, dist directory is
+// relative to application base directory.
+func newAssetsConfig(cfg *config.Config, appBaseDir string) (*assetsConfig, error) {
+	c := &assetsConfig{
+		Enable:         cfg.BoolDefault("build.assets.enable", false),
+		Minify:         cfg.BoolDefault("build.assets.minify", true),
+		OptimizeImages: cfg.BoolDefault("build.assets.optimize_images", false),
+		Dist:           filepath.Join(appBaseDir, filepath.FromSlash(cfg.StringDefault("build.assets.dist", "build/dist"))),
+	}
+	c.Fingerprint = c.Enable && cfg.BoolDefault("build.assets.fingerprint", false)
+	c.Manifest = path.Clean(cfg.StringDefault("build.assets.manifest", "manifest.json"))
+	c.Excludes, _ = cfg.StringList("build.assets.excludes")
+	c.FingerprintExcludes, _ = cfg.StringList("build.assets.fingerprint_excludes")
+	for key, patterns := range map[string][]string{"excludes": c.Excludes, "fingerprint_excludes": c.FingerprintExcludes} {
+		for _, p := range patterns {
+			if _, err := path.Match(p, ""); err != nil {
+				return nil, fmt.Errorf("'build.assets.%s' value '%s': %s", key, p, err)
+			}
+		}
+	}
+	return c, nil
+}
+
+// assetCompiler is the external asset compiler of 'build.assets.compilers',
+// its command is run via shell in application base directory.
+type assetCompiler struct {
+	Name    string
+	Command string
+	Watch   []string
+}
+
+// loadAssetCompilers method reads the compilers from 'aah.project' sorted
+// by name, watch patterns are relative to application base directory.
+//
+//	build.assets.compilers {
+//	  sass {
+//	    command = "sass static/scss/app.scss static/css/app.css"
+//	    watch = ["static/scss/*.scss"]
+//	  }
+//	}
+func loadAssetCompilers(cfg *config.Config) ([]*assetCompiler, error) {
+	compilersCfg, found := cfg.GetSubConfig("build.assets.compilers")
+	if !found {
+		return nil, nil
+	}
+
+	names := compilersCfg.Keys()
+	sort.Strings(names)
+	var compilers []*assetCompiler
+	for _, name := range names {
+		c := &assetCompiler{Name: name, Command: compilersCfg.StringDefault(name+".command", "")}
+		if ess.IsStrEmpty(c.Command) {
+			return nil, fmt.Errorf("'build.assets.compilers.%s.command' is required", name)
+		}
+		c.Watch, _ = compilersCfg.StringList(name + ".watch")
+		for _, p := range c.Watch {
+			if _, err := path.Match(p, ""); err != nil {
+				return nil, fmt.Errorf("'build.assets.compilers.%s.watch' value '%s': %s", name, p, err)
+			}
+		}
+		compilers = append(compilers, c)
+	}
+	return compilers, nil
+}
+
+// runAssetCompilers method runs the compilers in order, it stops at first
+// failure.
+func runAssetCompilers(compilers []*assetCompiler, appBaseDir string) error {
+	for _, c := range compilers {
+		if err := c.run(appBaseDir); err != nil {
+			return fmt.Errorf("asset compiler '%s': %s", c.Name, err)
+		}
+	}
+	return nil
+}
+
+func (c *assetCompiler) run(appBaseDir string) error {
+	name, flag := "sh", "-c"
+	if isWindowsOS() {
+		name, flag = "cmd", "/C"
+	}
+	if isDryRun() {
+		logDryRun("execute %s", c.Command)
+		return nil
+	}
+	log.Infof("Compiling assets '%s': %s", c.Name, c.Command)
+
+	cmd := exec.Command(name, flag, c.Command)
+	cmd.Dir = appBaseDir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// matches method reports whether the file, relative to application base
+// directory, matches any of the watch patterns. Pattern is matched against
+// file name and path, same as 'watch' section.
+func (c *assetCompiler) matches(rel string) bool {
+	return matchAnyPattern(c.Watch, rel)
+}
+
+// watchDirs method returns the non-pattern directory prefix of the watch
+// patterns, e.g. 'static/scss' of 'static/scss/*.scss'.
+func (c *assetCompiler) watchDirs() []string {
+	var dirs []string
+	for _, p := range c.Watch {
+		var parts []string
+		for _, part := range strings.Split(path.Dir(p), "/") {
+			if part == "." || strings.ContainsAny(part, `*?[\`) {
+				break
+			}
+			parts = append(parts, part)
+		}
+		if len(parts) > 0 {
+			dirs = append(dirs, path.Join(parts...))
+		}
+	}
+	return dirs
+}
+
+// buildAssets method runs the asset build stage, it returns the built files
+// mapped by source path relative to application base directory, e.g.
+// 'static/js/app.js'. Asset compilers run first regardless of 'enable'. It
+// returns nil if the stage is not enabled, in dry-run mode sources are
+// packaged.
+func buildAssets(cfg *config.Config, appBaseDir string) (map[string]string, error) {
+	ac, err := newAssetsConfig(cfg, appBaseDir)
+	if err != nil {
+		return nil, newExitErr(exitConfigError, err)
+	}
+	compilers, err := loadAssetCompilers(cfg)
+	if err != nil {
+		return nil, newExitErr(exitConfigError, err)
+	}
+	if len(compilers) > 0 {
+		endStage := startStage("assets compile")
+		err = runAssetCompilers(compilers, appBaseDir)
+		endStage()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	staticDir := filepath.Join(appBaseDir, assetsStaticDir)
+	if !ac.Enable || !(ac.Minify || ac.OptimizeImages) || !ess.IsFileExists(staticDir) {
+		return nil, nil
+	}
+
+	endStage := startStage("assets")
+	defer endStage()
+
+	built := map[string]string{}
+	var srcSize, distSize, images, imagesOptimized, imageSrcSize, imageDistSize int
+	err = filepath.Walk(staticDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+
+		rel, _ := filepath.Rel(appBaseDir, p)
+		rel = filepath.ToSlash(rel)
+		minify := assetMinifier(rel)
+		if !ac.Minify {
+			minify = nil
+		}
+		image := ac.OptimizeImages && isAssetImage(rel)
+		if (minify == nil && !image) || ac.excluded(strings.TrimPrefix(rel, assetsStaticDir+"/")) {
+			return nil
+		}
+
+		src, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		var result []byte
+		if image {
+			images++
+			if result, err = optimizeImage(rel, src); err != nil {
+				logWarnf("Unable to optimize image '%s', packaged as-is: %s", rel, err)
+				return nil
+			}
+			if len(result) >= len(src) {
+				return nil
+			}
+			imagesOptimized++
+			imageSrcSize += len(src)
+			imageDistSize += len(result)
+		} else {
+			result = minify(src)
+			srcSize += len(src)
+			distSize += len(result)
+		}
+
+		dest := filepath.Join(ac.Dist, filepath.FromSlash(rel))
+		if err = mkDirAll(filepath.Dir(dest), permRWXRXRX); err != nil {
+			return err
+		}
+		if err = writeFile(dest, result, permRWRR); err != nil {
+			return err
+		}
+		if !isDryRun() {
+			built[rel] = dest
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("asset build: %s", err)
+	}
+
+	if ac.Minify {
+		log.Infof("Assets minified, %d => %d bytes: %s", srcSize, distSize, ac.Dist)
+	}
+	if ac.OptimizeImages {
+		log.Infof("Images optimized, %d of %d files, %d => %d bytes (%s saved)",
+			imagesOptimized, images, imageSrcSize, imageDistSize, savedPercent(imageSrcSize, imageDistSize))
+	}
+	return built, nil
+}
+
+// fingerprintAssets method returns the fingerprinted copies of the static
+// files and the asset manifest, files are the package files with name
+// prefix 'packageName'. Manifest maps the file path relative to 'static'
+// directory to the fingerprinted path, e.g.
+//
+//	{"js/app.js": "js/app.3f2a9c1e.js"}
+//
+// It returns nil if fingerprint is not enabled.
+func fingerprintAssets(cfg *config.Config, appBaseDir, packageName string, files []*packageFile) ([]*packageFile, error) {
+	ac, err := newAssetsConfig(cfg, appBaseDir)
+	if err != nil {
+		return nil, newExitErr(exitConfigError, err)
+	}
+	if !ac.Fingerprint {
+		return nil, nil
+	}
+	return ac.fingerprint(packageName, files)
+}
+
+func (c *assetsConfig) fingerprint(packageName string, files []*packageFile) ([]*packageFile, error) {
+	prefix := path.Join(packageName, assetsStaticDir) + "/"
+	manifest := map[string]string{}
+	var result []*packageFile
+	for _, f := range files {
+		name := strings.TrimPrefix(f.Name, prefix)
+		if name == c.Manifest {
+			return nil, newExitErr(exitConfigError, fmt.Errorf("asset manifest '%s' conflicts with the static file, configure 'build.assets.manifest'", f.Name))
+		}
+		if name == f.Name || matchAnyPattern(c.FingerprintExcludes, name) {
+			continue
+		}
+
+		content, err := f.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		hashed := fingerprintName(name, content)
+		manifest[name] = hashed
+		result = append(result, &packageFile{Name: prefix + hashed, Src: f.Src, Content: f.Content, Mode: f.Mode})
+	}
+
+	b, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, &packageFile{Name: prefix + c.Manifest, Content: append(b, '\n'), Mode: permRWRR})
+	log.Infof("Assets fingerprinted, %d files: %s", len(manifest), path.Join(assetsStaticDir, c.Manifest))
+	return result, nil
+}
+
+// staticPackageFiles method returns the files of 'static' directory of the
+// base directory as package files, name is relative to the base directory.
+func staticPackageFiles(baseDir string) ([]*packageFile, error) {
+	staticDir := filepath.Join(baseDir, assetsStaticDir)
+	if !ess.IsFileExists(staticDir) {
+		return nil, nil
+	}
+
+	var files []*packageFile
+	err := filepath.Walk(staticDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		rel, _ := filepath.Rel(baseDir, p)
+		files = append(files, &packageFile{Name: filepath.ToSlash(rel), Src: p, Mode: permRWRR})
+		return nil
+	})
+	return files, err
+}
+
+// fingerprintName method returns the file name with content hash inserted
+// before the extension, e.g. 'js/app.js' becomes 'js/app.3f2a9c1e.js'.
+func fingerprintName(name string, content []byte) string {
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])[:assetsHashLen]
+	ext := path.Ext(name)
+	if ext == "" || ext == path.Base(name) {
+		return name + "." + hash
+	}
+	return strings.TrimSuffix(name, ext) + "." + hash + ext
+}
+
+// excluded method reports whether the file path relative to 'static'
+// directory matches the excludes, '*.min.js' and '*.min.css' are excluded
+// always since they are minified already.
+func (c *assetsConfig) excluded(name string) bool {
+	base := path.Base(name)
+	if strings.HasSuffix(base, ".min.js") || strings.HasSuffix(base, ".min.css") {
+		return true
+	}
+	return matchAnyPattern(c.Excludes, name)
+}
+
+// matchAnyPattern method reports whether the slash separated path or its
+// base name matches any of the patterns.
+func matchAnyPattern(patterns []string, name string) bool {
+	base := path.Base(name)
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+		if ok, _ := path.Match(p, base); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func assetMinifier(name string) func([]byte) []byte {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".js":
+		return minifyJS
+	case ".css":
+		return minifyCSS
+	}
+	return nil
+}
+
+func isAssetImage(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".png", ".jpg", ".jpeg":
+		return true
+	}
+	return false
+}
+
+func savedPercent(before, after int) string {
+	if before == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.1f%%", float64(before-after)*100/float64(before))
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Image optimizers
+//___________________________________
+
+// optimizeImage method losslessly optimizes the PNG and JPEG image via
+// 'optipng' and 'jpegoptim' if they are available in PATH, otherwise PNG is
+// recompressed with best compression and JPEG is left as-is. Result may not
+// be smaller than the source.
+func optimizeImage(name string, src []byte) ([]byte, error) {
+	ext := strings.ToLower(path.Ext(name))
+	switch ext {
+	case ".png":
+		if tool, err := exec.LookPath("optipng"); err == nil {
+			return optimizeImageTool(src, ext, tool, "-quiet", "-o2")
+		}
+		return recompressPNG(src)
+	case ".jpg", ".jpeg":
+		if tool, err := exec.LookPath("jpegoptim"); err == nil {
+			return optimizeImageTool(src, ext, tool, "--quiet", "--preserve")
+		}
+	}
+	return src, nil
+}
+
+// optimizeImageTool method runs the optimizer on the temporary copy of the
+// image, optimizer modifies the given file in place.
+func optimizeImageTool(src []byte, ext, tool string, args ...string) ([]byte, error) {
+	f, err := ioutil.TempFile("", "aah-image-")
+	if err != nil {
+		return nil, err
+	}
+	_ = f.Close()
+	file := f.Name() + ext
+	defer func() {
+		_ = os.Remove(f.Name())
+		_ = os.Remove(file)
+	}()
+
+	if err = ioutil.WriteFile(file, src, permRWRR); err != nil {
+		return nil, err
+	}
+	if out, err := exec.Command(tool, append(args, file)...).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("%s: %s %s", filepath.Base(tool), err, bytes.TrimSpace(out))
+	}
+	return ioutil.ReadFile(file)
+}
+
+// recompressPNG method encodes the PNG image with best compression, pixels
+// are kept as-is and ancillary chunks, such as text, are dropped.
+func recompressPNG(src []byte) ([]byte, error) {
+	img, err := png.Decode(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	enc := &png.Encoder{CompressionLevel: png.BestCompression}
+	if err = enc.Encode(buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Minifiers
+//___________________________________
+
+// minifyCSS method removes the comments and whitespace of CSS source,
+// string literals and '/*! ... */' license comments are kept as-is.
+func minifyCSS(src []byte) []byte {
+	buf := &bytes.Buffer{}
+	pendingSpace := false
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == '"' || c == '\'':
+			end := scanQuoted(src, i)
+			writeSpace(buf, &pendingSpace, c)
+			buf.Write(src[i:end])
+			i = end - 1
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := bytes.Index(src[i+2:], []byte("*/"))
+			if end < 0 {
+				end = len(src)
+			} else {
+				end += i + 4
+			}
+			if i+2 < len(src) && src[i+2] == '!' {
+				writeSpace(buf, &pendingSpace, c)
+				buf.Write(src[i:end])
+				buf.WriteByte('\n')
+			} else {
+				pendingSpace = buf.Len() > 0
+			}
+			i = end - 1
+		case isSpace(c):
+			pendingSpace = buf.Len() > 0
+		default:
+			if strings.IndexByte("{};,", c) >= 0 {
+				pendingSpace = false
+				if c == '}' {
+					trimTrailingByte(buf, ';')
+				}
+			}
+			writeSpace(buf, &pendingSpace, c)
+			buf.WriteByte(c)
+		}
+	}
+	return append(bytes.TrimSpace(buf.Bytes()), '\n')
+}
+
+// minifyJS method removes the comments and indentation of JavaScript source.
+// It's conservative, line breaks are kept for automatic semicolon insertion
+// and spaces are kept between identifiers and between '+' or '-'
+// operators. String, template and regular expression literals and '/*! ... */'
+// license comments are kept as-is.
+func minifyJS(src []byte) []byte {
+	buf := &bytes.Buffer{}
+	pendingSpace, pendingNewline := false, false
+	flush := func(next byte) {
+		if pendingNewline {
+			if buf.Len() > 0 {
+				buf.WriteByte('\n')
+			}
+		} else if pendingSpace && buf.Len() > 0 {
+			prev := buf.Bytes()[buf.Len()-1]
+			if (isIdentByte(prev) && isIdentByte(next)) || (isPlusMinus(prev) && isPlusMinus(next)) ||
+				(prev == '/' && next == '/') {
+				buf.WriteByte(' ')
+			}
+		}
+		pendingSpace, pendingNewline = false, false
+	}
+
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			end := scanQuoted(src, i)
+			flush(c)
+			buf.Write(src[i:end])
+			i = end - 1
+		case c == '/' && i+1 < len(src) && src[i+1] == '/':
+			end := bytes.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src)
+			} else {
+				end += i
+			}
+			i = end - 1
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := bytes.Index(src[i+2:], []byte("*/"))
+			if end < 0 {
+				end = len(src)
+			} else {
+				end += i + 4
+			}
+			if bytes.IndexByte(src[i:end], '\n') >= 0 {
+				pendingNewline = true
+			} else {
+				pendingSpace = true
+			}
+			if i+2 < len(src) && src[i+2] == '!' {
+				flush(c)
+				buf.Write(src[i:end])
+				pendingNewline = true
+			}
+			i = end - 1
+		case c == '/' && isJSRegexStart(buf.Bytes()):
+			end := scanJSRegex(src, i)
+			flush(c)
+			buf.Write(src[i:end])
+			i = end - 1
+		case c == '\n' || c == '\r':
+			pendingNewline = true
+		case isSpace(c):
+			pendingSpace = true
+		default:
+			flush(c)
+			buf.WriteByte(c)
+		}
+	}
+	return append(bytes.TrimSpace(buf.Bytes()), '\n')
+}
+
+// scanQuoted method returns the end offset of the quoted literal starts at
+// given offset, backslash escapes are honored. Template literal is scanned
+// till the closing backtick including its placeholders.
+func scanQuoted(src []byte, start int) int {
+	quote := src[start]
+	for i := start + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		case '\n':
+			if quote != '`' {
+				return i
+			}
+		}
+	}
+	return len(src)
+}
+
+// scanJSRegex method returns the end offset of the regular expression
+// literal starts at given offset, including its flags.
+func scanJSRegex(src []byte, start int) int {
+	inClass := false
+	for i := start + 1; i < len(src); i++ {
+		switch c := src[i]; {
+		case c == '\\':
+			i++
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case c == '/' && !inClass:
+			i++
+			for i < len(src) && isIdentByte(src[i]) {
+				i++
+			}
+			return i
+		case c == '\n':
+			return i
+		}
+	}
+	return len(src)
+}
+
+// isJSRegexStart method reports whether the '/' after the given output
+// starts the regular expression literal rather than division.
+func isJSRegexStart(out []byte) bool {
+	out = bytes.TrimRight(out, " \n")
+	if len(out) == 0 {
+		return true
+	}
+	prev := out[len(out)-1]
+	if strings.IndexByte("(,=:[!&|?{};+-*%<>~^", prev) >= 0 {
+		return true
+	}
+	if !isIdentByte(prev) {
+		return false
+	}
+	start := len(out)
+	for start > 0 && isIdentByte(out[start-1]) {
+		start--
+	}
+	switch string(out[start:]) {
+	case "return", "typeof", "instanceof", "in", "of", "new", "delete", "void", "throw", "case", "do", "else", "yield", "await":
+		return true
+	}
+	return false
+}
+
+func writeSpace(buf *bytes.Buffer, pending *bool, next byte) {
+	if *pending && strings.IndexByte("{};,", next) < 0 {
+		if b := buf.Bytes(); len(b) > 0 && strings.IndexByte("{};,\n", b[len(b)-1]) < 0 {
+			buf.WriteByte(' ')
+		}
+	}
+	*pending = false
+}
+
+func trimTrailingByte(buf *bytes.Buffer, c byte) {
+	if b := buf.Bytes(); len(b) > 0 && b[len(b)-1] == c {
+		buf.Truncate(len(b) - 1)
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c == '\\' || c >= 0x80 ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func isPlusMinus(c byte) bool {
+	return c == '+' || c == '-'
+}
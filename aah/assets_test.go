@@ -0,0 +1,146 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestAssetsMinifyCSS(t *testing.T) {
+	src := `/*! Theme v1.0 | MIT */
+/* page layout */
+body {
+  margin: 0;
+  font-family: "Helvetica Neue", Arial;
+}
+
+a:hover ,
+nav > a {
+  content: "a  /* b */  c";
+  color: #333;
+}
+@media (max-width: 600px) {
+  .nav { display: none; }
+}
+`
+	assert.Equal(t, `/*! Theme v1.0 | MIT */
+body{margin: 0;font-family: "Helvetica Neue",Arial}a:hover,nav > a{content: "a  /* b */  c";color: #333}@media (max-width: 600px){.nav{display: none}}
+`, string(minifyCSS([]byte(src))))
+}
+
+func TestAssetsMinifyJS(t *testing.T) {
+	src := `/*! app.js | MIT */
+// greeting
+function greet(name) {
+    var msg = 'Hello, ' + name; // inline comment
+    var re = /\/\*[^/]*\*\//g;
+    var url = "http://aahframework.org";
+    var n = a - -b + c / 2;
+    return ` + "`${msg} // not a comment`" + `;
+}
+
+/* multi
+   line */
+if (x) { y++ }
+`
+	assert.Equal(t, `/*! app.js | MIT */
+function greet(name){
+var msg='Hello, '+name;
+var re=/\/\*[^/]*\*\//g;
+var url="http://aahframework.org";
+var n=a- -b+c/2;
+return`+"`${msg} // not a comment`"+`;
+}
+if(x){y++}
+`, string(minifyJS([]byte(src))))
+}
+
+func TestAssetsExcluded(t *testing.T) {
+	c := &assetsConfig{Excludes: []string{"vendor/*", "legacy.js"}}
+	assert.True(t, c.excluded("js/jquery.min.js"))
+	assert.True(t, c.excluded("css/bootstrap.min.css"))
+	assert.True(t, c.excluded("vendor/lib.js"))
+	assert.True(t, c.excluded("js/legacy.js"))
+	assert.False(t, c.excluded("js/app.js"))
+
+	assert.NotNil(t, assetMinifier("static/js/app.JS"))
+	assert.NotNil(t, assetMinifier("static/css/aah.css"))
+	assert.Nil(t, assetMinifier("static/img/aah.png"))
+}
+
+func TestAssetsCompilerWatch(t *testing.T) {
+	c := &assetCompiler{Name: "sass", Watch: []string{"static/scss/*.scss", "*.ts", "static/*/lib/*.less"}}
+	assert.True(t, c.matches("static/scss/app.scss"))
+	assert.True(t, c.matches("static/js/app.ts"))
+	assert.True(t, c.matches("static/css/lib/theme.less"))
+	assert.False(t, c.matches("static/scss/app.css"))
+	assert.False(t, c.matches("views/common/header.html"))
+
+	assert.Equal(t, []string{"static/scss", "static"}, c.watchDirs())
+	assert.Equal(t, []string{"app", "config", "views", "static/scss"},
+		appendUnique(defaultWatchDirs, "views", "static/scss"))
+	assert.Equal(t, 3, len(defaultWatchDirs))
+}
+
+func TestAssetsRecompressPNG(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for x := 0; x < 64; x++ {
+		for y := 0; y < 64; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 4), G: 100, B: uint8(y * 4), A: 255})
+		}
+	}
+	buf := &bytes.Buffer{}
+	enc := &png.Encoder{CompressionLevel: png.NoCompression}
+	assert.Nil(t, enc.Encode(buf, img))
+
+	result, err := recompressPNG(buf.Bytes())
+	assert.Nil(t, err)
+	assert.True(t, len(result) < buf.Len())
+
+	decoded, err := png.Decode(bytes.NewReader(result))
+	assert.Nil(t, err)
+	assert.Equal(t, img.Bounds(), decoded.Bounds())
+	assert.Equal(t, img.At(10, 20), color.RGBAModel.Convert(decoded.At(10, 20)))
+
+	_, err = recompressPNG([]byte("not an image"))
+	assert.NotNil(t, err)
+
+	assert.True(t, isAssetImage("static/img/Logo.PNG"))
+	assert.True(t, isAssetImage("static/img/photo.jpeg"))
+	assert.False(t, isAssetImage("static/img/icon.svg"))
+	assert.Equal(t, "25.0%", savedPercent(400, 300))
+	assert.Equal(t, "0%", savedPercent(0, 0))
+}
+
+func TestAssetsFingerprint(t *testing.T) {
+	assert.Equal(t, "js/app.2cf24dba.js", fingerprintName("js/app.js", []byte("hello")))
+	assert.Equal(t, "js/app.min.2cf24dba.js", fingerprintName("js/app.min.js", []byte("hello")))
+	assert.Equal(t, "LICENSE.2cf24dba", fingerprintName("LICENSE", []byte("hello")))
+
+	c := &assetsConfig{Fingerprint: true, Manifest: "manifest.json", FingerprintExcludes: []string{"robots.txt"}}
+	files := []*packageFile{
+		{Name: "myapp-1.0/bin/myapp", Content: []byte("binary"), Mode: permRWXRXRX},
+		{Name: "myapp-1.0/static/css/aah.css", Content: []byte("hello"), Mode: permRWRR},
+		{Name: "myapp-1.0/static/robots.txt", Content: []byte("User-agent: *"), Mode: permRWRR},
+	}
+	result, err := c.fingerprint("myapp-1.0", files)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, "myapp-1.0/static/css/aah.2cf24dba.css", result[0].Name)
+	assert.Equal(t, "hello", string(result[0].Content))
+	assert.Equal(t, "myapp-1.0/static/manifest.json", result[1].Name)
+	assert.Equal(t, "{\n  \"css/aah.css\": \"css/aah.2cf24dba.css\"\n}\n", string(result[1].Content))
+
+	files = append(files, &packageFile{Name: "myapp-1.0/static/manifest.json", Content: []byte("{}")})
+	_, err = c.fingerprint("myapp-1.0", files)
+	assert.NotNil(t, err)
+	assert.Equal(t, exitConfigError, exitCodeOf(err))
+}
@@ -0,0 +1,280 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+var (
+	auditCmdFlags   = flag.NewFlagSet("audit", flag.ContinueOnError)
+	auditFormatFlag = auditCmdFlags.String("format", "text", "Report format 'text' or 'sarif'")
+	auditOutputFlag = auditCmdFlags.String("o", "", "Report file path. Default is stdout")
+	auditCmd        = &command{
+		Name:      "audit",
+		UsageLine: "aah audit [-format] [-o]",
+		Flags:     auditCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "audit application dependencies for licenses and vulnerabilities",
+		Long: `
+Audits the application dependencies, license policy violations are found
+same as 'aah deps licenses' and known vulnerabilities of the called code
+via 'govulncheck'. Vulnerability check is skipped with a warning if
+'govulncheck' is not installed:
+
+    go install golang.org/x/vuln/cmd/govulncheck@latest
+
+Findings are reported with file positions, license findings are located on
+'deps.licenses' of 'aah.project' and vulnerabilities on the application
+code calling the vulnerable function:
+
+    aah.project:12:7: error: dependency 'example.com/lib' license 'GPL-3.0' is denied
+    app/controllers/app.go:42:12: error: GO-2023-1571: Denial of service in net/http (golang.org/x/net@v0.6.0, fixed in v0.7.0)
+
+Report format 'sarif' is SARIF 2.1.0 JSON with a run for each check, upload
+it to GitHub or GitLab code scanning to show the findings inline.
+
+Exit code is 'audit' (refer 'aah help exit-codes') if any license policy
+violation or vulnerability is found.
+
+Example(s):
+    aah audit
+
+    aah audit -format=sarif -o=audit.sarif
+`,
+	}
+)
+
+// vulnFinding is the known vulnerability of the code called by application.
+type vulnFinding struct {
+	ID           string
+	Summary      string
+	Module       string
+	Version      string
+	FixedVersion string
+	File         string
+	Line         int
+	Col          int
+}
+
+func auditRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	format := *auditFormatFlag
+	if format != "text" && format != "sarif" {
+		fatalExitf(exitUsage, "Unsupported report format '%s', choose 'text' or 'sarif'", format)
+		return
+	}
+
+	deps, err := appDependencies(appProject.ImportPath)
+	if err != nil {
+		fatalErr(newExitErr(exitDepsError, err))
+		return
+	}
+	policy := newLicensePolicy(appProject.BuildCfg)
+	licenses := dependencyLicenses(deps, policy)
+
+	vulns, err := govulncheck(appProject.BaseDir, appProject.ImportPath)
+	if err != nil {
+		logWarnf("Vulnerability check skipped: %s", err)
+	}
+
+	licenseReport := licensesSARIF(appProject.BaseDir, policy, licenses)
+	buf := &bytes.Buffer{}
+	if format == "sarif" {
+		err = licenseReport.Merge(vulnsSARIF(appProject.BaseDir, vulns)).Write(buf)
+	} else {
+		err = writeAuditReport(buf, appProject.BaseDir, licenseReport, vulns)
+	}
+	if err != nil {
+		fatal(err)
+		return
+	}
+	if file := getNonEmptyAbsPath(*auditOutputFlag, ""); !ess.IsStrEmpty(file) {
+		if err = writeFile(file, buf.Bytes(), permRWRR); err != nil {
+			fatal(err)
+			return
+		}
+		recordArtifact(file)
+		log.Infof("Audit report written to %s", file)
+	} else {
+		_, _ = os.Stdout.Write(buf.Bytes())
+	}
+
+	violations := policy.Violations(licenses)
+	if violations > 0 || len(vulns) > 0 {
+		fatalExitf(exitAuditError, "Audit failed, %d license violation(s), %d vulnerability(ies)", violations, len(vulns))
+		return
+	}
+	log.Info("No problems found")
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// govulncheck method runs 'govulncheck' on the application packages and
+// returns the vulnerabilities of the called code.
+//
+//	govulncheck -json aah-app/import/path/app/...
+func govulncheck(baseDir, importPath string) ([]*vulnFinding, error) {
+	name, err := exec.LookPath("govulncheck")
+	if err != nil {
+		return nil, errors.New("'govulncheck' is not installed, run 'go install golang.org/x/vuln/cmd/govulncheck@latest'")
+	}
+
+	cmd := exec.Command(name, "-json", path.Join(importPath, "app", "..."))
+	cmd.Dir = baseDir
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	log.Debug("Executing ", strings.Join(cmd.Args, " "))
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("govulncheck: %s %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return parseGovulncheck(bytes.NewReader(output))
+}
+
+// parseGovulncheck method parses the JSON message stream of 'govulncheck
+// -json'. Only the findings of called functions are returned, they are
+// located on the application code, i.e. last frame of the call trace.
+func parseGovulncheck(r io.Reader) ([]*vulnFinding, error) {
+	type position struct {
+		Filename string `json:"filename"`
+		Line     int    `json:"line"`
+		Column   int    `json:"column"`
+	}
+	type frame struct {
+		Module   string    `json:"module"`
+		Version  string    `json:"version"`
+		Function string    `json:"function"`
+		Position *position `json:"position"`
+	}
+	type message struct {
+		OSV *struct {
+			ID      string `json:"id"`
+			Summary string `json:"summary"`
+		} `json:"osv"`
+		Finding *struct {
+			OSV          string   `json:"osv"`
+			FixedVersion string   `json:"fixed_version"`
+			Trace        []*frame `json:"trace"`
+		} `json:"finding"`
+	}
+
+	summaries := map[string]string{}
+	seen := map[string]bool{}
+	var vulns []*vulnFinding
+	dec := json.NewDecoder(r)
+	for {
+		var m message
+		if err := dec.Decode(&m); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if m.OSV != nil {
+			summaries[m.OSV.ID] = m.OSV.Summary
+		}
+		if m.Finding == nil || len(m.Finding.Trace) == 0 || ess.IsStrEmpty(m.Finding.Trace[0].Function) {
+			continue
+		}
+
+		v := &vulnFinding{
+			ID:           m.Finding.OSV,
+			Module:       m.Finding.Trace[0].Module,
+			Version:      m.Finding.Trace[0].Version,
+			FixedVersion: m.Finding.FixedVersion,
+		}
+		for i := len(m.Finding.Trace) - 1; i >= 0; i-- {
+			if p := m.Finding.Trace[i].Position; p != nil && !ess.IsStrEmpty(p.Filename) {
+				v.File, v.Line, v.Col = p.Filename, p.Line, p.Column
+				break
+			}
+		}
+
+		key := fmt.Sprintf("%s|%s|%d", v.ID, v.File, v.Line)
+		if !seen[key] {
+			seen[key] = true
+			vulns = append(vulns, v)
+		}
+	}
+
+	for _, v := range vulns {
+		v.Summary = summaries[v.ID]
+	}
+	sort.SliceStable(vulns, func(i, j int) bool { return vulns[i].ID < vulns[j].ID })
+	return vulns, nil
+}
+
+// Message method returns the finding description.
+func (v *vulnFinding) Message() string {
+	msg := v.ID
+	if !ess.IsStrEmpty(v.Summary) {
+		msg += ": " + v.Summary
+	}
+	msg += fmt.Sprintf(" (%s@%s", v.Module, v.Version)
+	if !ess.IsStrEmpty(v.FixedVersion) {
+		msg += ", fixed in " + v.FixedVersion
+	}
+	return msg + ")"
+}
+
+// vulnsSARIF method returns the SARIF report of the vulnerabilities, rule
+// of the finding is its vulnerability ID. Finding without position is
+// located on 'aah.project'.
+func vulnsSARIF(baseDir string, vulns []*vulnFinding) *sarifLog {
+	report := newSARIFLog("audit", baseDir)
+	for _, v := range vulns {
+		file := v.File
+		if ess.IsStrEmpty(file) {
+			file = filepath.Join(baseDir, aahProjectIdentifier)
+		}
+		report.Add(v.ID, firstNonEmpty(v.Summary, "Known vulnerability of the called code"), sarifLevelError,
+			v.Message(), file, v.Line, v.Col)
+	}
+	return report
+}
+
+// writeAuditReport method writes the license findings and vulnerabilities
+// in text format with file path relative to the base directory.
+func writeAuditReport(w io.Writer, baseDir string, licenseReport *sarifLog, vulns []*vulnFinding) error {
+	var problems []*confProblem
+	for _, r := range licenseReport.Runs[0].Results {
+		loc := r.Locations[0].PhysicalLocation
+		problems = append(problems, &confProblem{File: filepath.Join(baseDir, filepath.FromSlash(loc.ArtifactLocation.URI)),
+			Line: loc.Region.StartLine, Col: loc.Region.StartColumn, Severity: r.Level, Msg: r.Message.Text})
+	}
+	for _, v := range vulns {
+		file := v.File
+		if ess.IsStrEmpty(file) {
+			file = filepath.Join(baseDir, aahProjectIdentifier)
+		}
+		problems = append(problems, &confProblem{File: file, Line: v.Line, Col: v.Col,
+			Severity: confSeverityError, Msg: v.Message()})
+	}
+	printConfProblems(w, baseDir, problems)
+	return nil
+}
+
+func init() {
+	auditCmd.Run = auditRun
+}
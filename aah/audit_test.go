@@ -0,0 +1,55 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestAuditGovulncheck(t *testing.T) {
+	baseDir := filepath.FromSlash("/go/src/example.com/myapp")
+	appFile := filepath.Join(baseDir, "app", "controllers", "app.go")
+	stream := `{"config": {"protocol_version": "v1.0.0", "scanner_name": "govulncheck"}}
+{"progress": {"message": "Scanning your code and 42 packages across 3 dependent modules for known vulnerabilities..."}}
+{"osv": {"id": "GO-2023-1571", "summary": "Denial of service in net/http and golang.org/x/net/http2"}}
+{"osv": {"id": "GO-2022-0969", "summary": "Denial of service in net/http"}}
+{"finding": {"osv": "GO-2023-1571", "fixed_version": "v0.7.0", "trace": [{"module": "golang.org/x/net", "version": "v0.6.0"}]}}
+{"finding": {"osv": "GO-2023-1571", "fixed_version": "v0.7.0", "trace": [
+  {"module": "golang.org/x/net", "version": "v0.6.0", "package": "golang.org/x/net/http2/hpack", "function": "Decode", "receiver": "*Decoder"},
+  {"module": "example.com/myapp", "package": "example.com/myapp/app/controllers", "function": "Index", "position": {"filename": "` + filepath.ToSlash(appFile) + `", "line": 42, "column": 12}}
+]}}
+{"finding": {"osv": "GO-2023-1571", "fixed_version": "v0.7.0", "trace": [
+  {"module": "golang.org/x/net", "version": "v0.6.0", "package": "golang.org/x/net/http2/hpack", "function": "Decode", "receiver": "*Decoder"},
+  {"module": "example.com/myapp", "package": "example.com/myapp/app/controllers", "function": "Index", "position": {"filename": "` + filepath.ToSlash(appFile) + `", "line": 42, "column": 12}}
+]}}
+`
+
+	vulns, err := parseGovulncheck(strings.NewReader(stream))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(vulns))
+	assert.Equal(t, "GO-2023-1571: Denial of service in net/http and golang.org/x/net/http2 (golang.org/x/net@v0.6.0, fixed in v0.7.0)", vulns[0].Message())
+	assert.Equal(t, 42, vulns[0].Line)
+
+	_, err = parseGovulncheck(strings.NewReader(`{"finding": `))
+	assert.NotNil(t, err)
+
+	report := vulnsSARIF(baseDir, vulns)
+	assert.Equal(t, "GO-2023-1571", report.Runs[0].Tool.Driver.Rules[0].ID)
+	assert.Equal(t, "app/controllers/app.go", report.Runs[0].Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+
+	licenseReport := newSARIFLog("deps licenses", baseDir)
+	licenseReport.Add("license-denied", "Dependency license is in the deny list", sarifLevelError,
+		"dependency 'example.com/lib' license 'GPL-3.0' is denied", filepath.Join(baseDir, aahProjectIdentifier), 12, 7)
+	buf := &bytes.Buffer{}
+	assert.Nil(t, writeAuditReport(buf, baseDir, licenseReport, vulns))
+	assert.Equal(t, "aah.project:12:7: error: dependency 'example.com/lib' license 'GPL-3.0' is denied\n"+
+		"app/controllers/app.go:42:12: error: GO-2023-1571: Denial of service in net/http and golang.org/x/net/http2 (golang.org/x/net@v0.6.0, fixed in v0.7.0)\n",
+		buf.String())
+}
@@ -0,0 +1,325 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+var (
+	benchCmdFlags         = flag.NewFlagSet("bench", flag.ContinueOnError)
+	benchProfileFlag      = benchCmdFlags.String("profile", "", "Environment profile name to activate. Default is 'test'")
+	benchProfileShortFlag = benchCmdFlags.String("p", "", "Environment profile name to activate. Default is 'test'")
+	benchEnvFlag          = benchCmdFlags.String("env", "", "Run environment name from 'run.env' section of aah.project. e.g: ci")
+	benchEnvShortFlag     = benchCmdFlags.String("e", "", "Run environment name from 'run.env' section of aah.project. e.g: ci")
+	benchBenchFlag        = benchCmdFlags.String("bench", ".", "Run only the benchmarks matching the regular expression")
+	benchCountFlag        = benchCmdFlags.Int("count", 0, "Run each benchmark n times. Default is 'bench.count' of aah.project or 1")
+	benchTimeFlag         = benchCmdFlags.String("benchtime", "", "Run enough iterations of each benchmark to take given time. e.g: 2s")
+	benchThresholdFlag    = benchCmdFlags.Float64("threshold", 0, "Fail if 'ns/op' regresses more than given percent. Default is 'bench.threshold' of aah.project")
+	benchCmd              = &command{
+		Name:      "bench",
+		UsageLine: "aah bench [-p | -profile] [-e | -env] [-bench] [-count] [-benchtime] [-threshold] [packages]",
+		Flags:     benchCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "run benchmarks and compare with previous run",
+		Long: `
+Runs the application benchmarks via 'go test -bench' with memory stats,
+stores the results under '.aah/bench/' and compares them with the previous
+run. Deltas of 'ns/op', 'B/op' and 'allocs/op' are printed per benchmark
+same as 'benchstat', values are averaged if '-count' is more than 1.
+
+Default packages are './app/...' or 'test.packages' of 'aah.project',
+environment variables are same as 'aah test'.
+
+If '-threshold' (or 'bench.threshold') percent is set, 'ns/op' regression
+above the threshold fails the command with exit code 'test'.
+
+Startup time of the application is measured via 'aah bench startup'.
+
+Example(s):
+    aah bench
+
+    aah bench -bench=BenchmarkUser -count=5
+
+    aah bench -threshold=10 ./app/models/...
+`,
+	}
+)
+
+const benchDirName = "bench"
+
+var benchUnits = []string{"ns/op", "B/op", "allocs/op"}
+
+type (
+	// benchStat is the averaged values of a benchmark per unit.
+	benchStat struct {
+		Name   string
+		Values map[string]float64
+		runs   map[string]int
+	}
+
+	// benchDelta is the comparison of benchmark unit between two runs.
+	benchDelta struct {
+		Name  string
+		Unit  string
+		Old   float64
+		New   float64
+		Delta float64
+	}
+)
+
+func benchRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	buildCfg := appProject.BuildCfg
+	testEnv, _, err := appTestEnv(firstNonEmpty(*benchEnvFlag, *benchEnvShortFlag), "",
+		firstNonEmpty(*benchProfileFlag, *benchProfileShortFlag))
+	if err != nil {
+		fatalExit(exitConfigError, err)
+		return
+	}
+
+	count := *benchCountFlag
+	if count <= 0 {
+		count = buildCfg.IntDefault("bench.count", 1)
+	}
+	goArgs := []string{"test", "-run=^$", "-bench=" + *benchBenchFlag, "-benchmem",
+		"-count=" + strconv.Itoa(count)}
+	if !ess.IsStrEmpty(*benchTimeFlag) {
+		goArgs = append(goArgs, "-benchtime="+*benchTimeFlag)
+	}
+
+	pkgs := args
+	if len(pkgs) == 0 {
+		pkgs = []string{"./app/..."}
+		if packages, found := buildCfg.StringList("test.packages"); found && len(packages) > 0 {
+			pkgs = packages
+		}
+	}
+	goArgs = append(goArgs, pkgs...)
+
+	benchDir := filepath.Join(appProject.BaseDir, ".aah", benchDirName)
+	if isDryRun() {
+		logDryRun("execute %s %s", gocmd, strings.Join(goArgs, " "))
+		logDryRun("write file %s", filepath.Join(benchDir, "<timestamp>.txt"))
+		return
+	}
+
+	previous := latestBenchFile(benchDir, "*.txt")
+
+	log.Infof("Running benchmarks of '%s'", appProject.ImportPath)
+	raw := &bytes.Buffer{}
+	out := &testOutput{w: io.MultiWriter(os.Stdout, raw)}
+	endStage := startStage("bench")
+	err = runGoTest(goArgs, testEnv, out)
+	endStage()
+	out.Flush()
+
+	if err != nil {
+		printTestSummary(os.Stdout, out.results)
+		fatalExitf(exitTestError, "Benchmarks failed: %s", err)
+		return
+	}
+
+	current := parseBenchOutput(raw.Bytes())
+	if len(current) == 0 {
+		log.Info("\nNo benchmarks found")
+		return
+	}
+
+	if err = mkDirAll(benchDir, permRWXRXRX); err != nil {
+		fatal(err)
+		return
+	}
+	file := filepath.Join(benchDir, time.Now().Format("20060102-150405")+".txt")
+	if err = writeFile(file, raw.Bytes(), permRWRR); err != nil {
+		fatal(err)
+		return
+	}
+	log.Infof("\nBenchmark results saved: %s", file)
+
+	if ess.IsStrEmpty(previous) {
+		log.Info("No previous run to compare")
+		return
+	}
+
+	b, err := ioutil.ReadFile(previous)
+	if err != nil {
+		fatal(err)
+		return
+	}
+
+	log.Infof("Compared with %s", filepath.Base(previous))
+	deltas := compareBench(parseBenchOutput(b), current)
+	printBenchDeltas(os.Stdout, deltas)
+
+	threshold := *benchThresholdFlag
+	if threshold <= 0 {
+		threshold = float64(buildCfg.IntDefault("bench.threshold", 0))
+	}
+	if regressions := benchRegressions(deltas, threshold); len(regressions) > 0 {
+		fatalExitf(exitTestError, "Performance regression above %.1f%% 'ns/op':\n    %s", threshold,
+			strings.Join(regressions, "\n    "))
+	}
+}
+
+// latestBenchFile method returns the latest stored result file matching
+// the pattern, empty if none exists.
+func latestBenchFile(benchDir, pattern string) string {
+	files, _ := filepath.Glob(filepath.Join(benchDir, pattern))
+	if len(files) == 0 {
+		return ""
+	}
+	sort.Strings(files)
+	return files[len(files)-1]
+}
+
+// parseBenchOutput method parses the benchmark lines of 'go test -bench'
+// output, benchmark name is prefixed with its package:
+//
+//	BenchmarkUserFind-8   	 1000000	      1234 ns/op	      56 B/op	       2 allocs/op
+//
+// Package is from 'pkg:' line or the package result line after benchmarks.
+func parseBenchOutput(b []byte) map[string]*benchStat {
+	stats := map[string]*benchStat{}
+	var pkg string
+	addStat := func(name string, values map[string]float64) {
+		s, found := stats[name]
+		if !found {
+			s = &benchStat{Name: name, Values: map[string]float64{}, runs: map[string]int{}}
+			stats[name] = s
+		}
+		for unit, v := range values {
+			n := float64(s.runs[unit])
+			s.Values[unit] = (s.Values[unit]*n + v) / (n + 1)
+			s.runs[unit]++
+		}
+	}
+
+	type benchLine struct {
+		name   string
+		values map[string]float64
+	}
+	var lines []*benchLine
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		switch {
+		case len(fields) == 2 && fields[0] == "pkg:":
+			pkg = fields[1]
+		case len(fields) >= 2 && (fields[0] == testStatusPass || fields[0] == testStatusFail):
+			for _, l := range lines {
+				addStat(fields[1]+"."+l.name, l.values)
+			}
+			lines, pkg = nil, ""
+		case len(fields) >= 4 && strings.HasPrefix(fields[0], "Benchmark"):
+			values := map[string]float64{}
+			for i := 2; i+1 < len(fields); i += 2 {
+				if v, err := strconv.ParseFloat(fields[i], 64); err == nil {
+					values[fields[i+1]] = v
+				}
+			}
+			if ess.IsStrEmpty(pkg) {
+				lines = append(lines, &benchLine{name: fields[0], values: values})
+				continue
+			}
+			addStat(pkg+"."+fields[0], values)
+		}
+	}
+	return stats
+}
+
+// compareBench method returns the deltas of benchmarks exist in both runs,
+// sorted by unit and name.
+func compareBench(old, cur map[string]*benchStat) []*benchDelta {
+	var deltas []*benchDelta
+	for _, unit := range benchUnits {
+		var names []string
+		for name, s := range cur {
+			if _, found := s.Values[unit]; !found {
+				continue
+			}
+			if o, found := old[name]; found {
+				if _, found = o.Values[unit]; found {
+					names = append(names, name)
+				}
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			d := &benchDelta{Name: name, Unit: unit, Old: old[name].Values[unit], New: cur[name].Values[unit]}
+			if d.Old != 0 {
+				d.Delta = (d.New - d.Old) * 100 / d.Old
+			}
+			deltas = append(deltas, d)
+		}
+	}
+	return deltas
+}
+
+func printBenchDeltas(w io.Writer, deltas []*benchDelta) {
+	if len(deltas) == 0 {
+		fmt.Fprintln(w, "No common benchmarks to compare")
+		return
+	}
+
+	width := len("name")
+	for _, d := range deltas {
+		if len(d.Name) > width {
+			width = len(d.Name)
+		}
+	}
+
+	unit := ""
+	for _, d := range deltas {
+		if d.Unit != unit {
+			unit = d.Unit
+			fmt.Fprintf(w, "\n%-*s  %14s  %14s  %8s\n", width, "name", "old "+unit, "new "+unit, "delta")
+		}
+		fmt.Fprintf(w, "%-*s  %14s  %14s  %+7.2f%%\n", width, d.Name, formatBenchValue(d.Old), formatBenchValue(d.New), d.Delta)
+	}
+}
+
+// benchRegressions method returns the benchmarks which 'ns/op' regressed
+// more than threshold percent, threshold 0 disables the check.
+func benchRegressions(deltas []*benchDelta, threshold float64) []string {
+	if threshold <= 0 {
+		return nil
+	}
+
+	var regressions []string
+	for _, d := range deltas {
+		if d.Unit == "ns/op" && d.Delta > threshold {
+			regressions = append(regressions, fmt.Sprintf("%s %+.2f%%", d.Name, d.Delta))
+		}
+	}
+	return regressions
+}
+
+func formatBenchValue(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
+
+func init() {
+	benchCmd.Run = benchRun
+}
@@ -0,0 +1,315 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+var (
+	benchStartupCmdFlags         = flag.NewFlagSet("startup", flag.ContinueOnError)
+	benchStartupProfileFlag      = benchStartupCmdFlags.String("profile", "", "Environment profile name to activate. e.g: dev, qa, prod")
+	benchStartupProfileShortFlag = benchStartupCmdFlags.String("p", "", "Environment profile name to activate. e.g: dev, qa, prod")
+	benchStartupEnvFlag          = benchStartupCmdFlags.String("env", "", "Run environment name from 'run.env' section of aah.project. e.g: ci")
+	benchStartupEnvShortFlag     = benchStartupCmdFlags.String("e", "", "Run environment name from 'run.env' section of aah.project. e.g: ci")
+	benchStartupRunsFlag         = benchStartupCmdFlags.Int("runs", 0, "Number of application launches. Default is 'bench.startup_runs' of aah.project or 10")
+	benchStartupTimeoutFlag      = benchStartupCmdFlags.Duration("timeout", 30*time.Second, "Maximum time of each launch to listen")
+	benchStartupThresholdFlag    = benchStartupCmdFlags.Float64("threshold", 0, "Fail if mean startup time regresses more than given percent. Default is 'bench.startup_threshold' of aah.project")
+	benchStartupCmd              = &command{
+		Name:      "startup",
+		UsageLine: "aah bench startup [-p | -profile] [-e | -env] [-runs] [-timeout] [-threshold]",
+		Flags:     benchStartupCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "measure application startup time",
+		Long: `
+Builds the application and launches it '-runs' times, measuring the time
+from process start to the server accepting connections on a free port.
+Mean, min, max and percentiles are printed:
+
+    stat            ms
+    mean        182.4
+    p50         179.1
+    p90        195.62
+    p99        201.33
+    min        171.05
+    max        201.33
+
+Results are stored under '.aah/bench/' and compared with the previous run,
+if '-threshold' (or 'bench.startup_threshold') percent is set, mean
+regression above the threshold fails the command with exit code 'test'.
+Environment variables are same as 'aah run'.
+
+Example(s):
+    aah bench startup
+
+    aah bench startup -runs=20 -profile=prod -threshold=15
+`,
+	}
+)
+
+// startupStatNames are the reported stats of startup time, in report order.
+var startupStatNames = []string{"mean", "p50", "p90", "p99", "min", "max"}
+
+// startupResult is the stored startup time run, durations are in
+// milliseconds.
+type startupResult struct {
+	Date    time.Time          `json:"date"`
+	Runs    int                `json:"runs"`
+	Stats   map[string]float64 `json:"stats"`
+	Samples []float64          `json:"samples"`
+}
+
+func benchStartupRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	buildCfg := appProject.BuildCfg
+	env := &runEnv{}
+	if envName := firstNonEmpty(*benchStartupEnvFlag, *benchStartupEnvShortFlag); !ess.IsStrEmpty(envName) {
+		var err error
+		if env, err = loadRunEnv(buildCfg, appProject.BaseDir, envName); err != nil {
+			fatalErr(newExitErr(exitConfigError, err))
+			return
+		}
+	}
+
+	appArgs := []string{}
+	if !ess.IsStrEmpty(env.Config) {
+		appArgs = append(appArgs, "-config", env.Config)
+	}
+	profile := firstNonEmpty(*benchStartupProfileFlag, *benchStartupProfileShortFlag, env.Profile)
+	if !ess.IsStrEmpty(profile) {
+		appArgs = append(appArgs, "-profile", profile)
+	}
+
+	runs := *benchStartupRunsFlag
+	if runs <= 0 {
+		runs = buildCfg.IntDefault("bench.startup_runs", 10)
+	}
+
+	benchDir := filepath.Join(appProject.BaseDir, ".aah", benchDirName)
+	if isDryRun() {
+		logDryRun("build application and start it %d time(s) with %v", runs, appArgs)
+		logDryRun("write file %s", filepath.Join(benchDir, "startup-<timestamp>.json"))
+		return
+	}
+
+	appEnv, err := startupAppEnv(env, firstNonEmpty(profile, "dev"))
+	if err != nil {
+		fatalErr(newExitErr(exitConfigError, err))
+		return
+	}
+	port, err := freePort()
+	if err != nil {
+		fatal(err)
+		return
+	}
+	appEnv = append(appEnv, appPortEnv+"="+port)
+	addr := net.JoinHostPort(startupDialHost(aah.AppConfig().StringDefault("server.address", "")), port)
+
+	appBinary, err := compileApp(buildCfg, &compileOptions{})
+	if err != nil {
+		fatalErr(err)
+		return
+	}
+
+	log.Infof("Measuring startup time of '%s', %d run(s)", appProject.ImportPath, runs)
+	endStage := startStage("bench startup")
+	samples := make([]float64, 0, runs)
+	for i := 0; i < runs; i++ {
+		d, err := measureStartup(appBinary, appArgs, appEnv, addr, *benchStartupTimeoutFlag)
+		if err != nil {
+			endStage()
+			fatalExitf(exitTestError, "Startup run %d failed: %s", i+1, err)
+			return
+		}
+		samples = append(samples, float64(d)/float64(time.Millisecond))
+	}
+	endStage()
+
+	current := &startupResult{Date: time.Now(), Runs: runs, Stats: startupStats(samples), Samples: samples}
+	previous := latestBenchFile(benchDir, "startup-*.json")
+
+	if err = mkDirAll(benchDir, permRWXRXRX); err != nil {
+		fatal(err)
+		return
+	}
+	b, err := json.MarshalIndent(current, "", "  ")
+	if err != nil {
+		fatal(err)
+		return
+	}
+	file := filepath.Join(benchDir, "startup-"+current.Date.Format("20060102-150405")+".json")
+	if err = writeFile(file, b, permRWRR); err != nil {
+		fatal(err)
+		return
+	}
+
+	var old *startupResult
+	if !ess.IsStrEmpty(previous) {
+		if old, err = loadStartupResult(previous); err != nil {
+			logWarnf("Unable to read previous run %s: %s", previous, err)
+		}
+	}
+	fmt.Println()
+	printStartupStats(os.Stdout, old, current)
+	log.Infof("\nStartup results saved: %s", file)
+	if old == nil {
+		log.Info("No previous run to compare")
+		return
+	}
+	log.Infof("Compared with %s", filepath.Base(previous))
+
+	threshold := *benchStartupThresholdFlag
+	if threshold <= 0 {
+		threshold = float64(buildCfg.IntDefault("bench.startup_threshold", 0))
+	}
+	if delta := startupDelta(old.Stats["mean"], current.Stats["mean"]); threshold > 0 && delta > threshold {
+		fatalExitf(exitTestError, "Startup time regression above %.1f%%: mean %+.2f%%", threshold, delta)
+	}
+}
+
+// startupAppEnv method returns the application environment variables same
+// as 'aah run', '.env' files, secrets and run environment variables.
+func startupAppEnv(env *runEnv, profile string) ([]string, error) {
+	var vars []string
+	if appProject.BuildCfg.BoolDefault("run.dotenv.enable", true) {
+		dotEnv, _, err := loadDotEnv(appProject.BaseDir, profile,
+			appProject.BuildCfg.BoolDefault("run.dotenv.override", false))
+		if err != nil {
+			return nil, err
+		}
+		vars = append(vars, dotEnv...)
+	}
+
+	secrets, err := secretsEnv(appProject.BaseDir)
+	if err != nil {
+		logWarnf("Secrets are not loaded: %s", err)
+	}
+	return append(append(vars, secrets...), env.Vars...), nil
+}
+
+// startupDialHost method returns the host to reach the server address,
+// loopback for unspecified address.
+func startupDialHost(address string) string {
+	if ip := net.ParseIP(address); ess.IsStrEmpty(address) || (ip != nil && ip.IsUnspecified()) {
+		return "127.0.0.1"
+	}
+	return address
+}
+
+// measureStartup method starts the application and returns the time taken
+// to accept connections on the address, application is stopped after.
+func measureStartup(binary string, args, env []string, addr string, timeout time.Duration) (time.Duration, error) {
+	tail := newLineTail(crashTailLines)
+	start := time.Now()
+	p, err := startAppProcess(binary, args, env, ioutil.Discard, tail)
+	if err != nil {
+		return 0, err
+	}
+	defer p.Stop()
+
+	deadline := start.Add(timeout)
+	for time.Now().Before(deadline) {
+		if c, err := net.DialTimeout("tcp", addr, 100*time.Millisecond); err == nil {
+			elapsed := time.Since(start)
+			_ = c.Close()
+			return elapsed, nil
+		}
+
+		select {
+		case <-p.Done():
+			return 0, fmt.Errorf("application exited: %v\n    %s", p.Err(), strings.Join(tail.Lines(), "\n    "))
+		case <-time.After(2 * time.Millisecond):
+		}
+	}
+	return 0, errors.New("application did not listen on " + addr + " within " + timeout.String())
+}
+
+// startupStats method returns the mean, min, max and nearest-rank
+// percentiles of the samples.
+func startupStats(samples []float64) map[string]float64 {
+	sorted := append([]float64{}, samples...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, s := range sorted {
+		sum += s
+	}
+	percentile := func(p float64) float64 {
+		rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+		if rank < 0 {
+			rank = 0
+		}
+		return sorted[rank]
+	}
+
+	return map[string]float64{
+		"mean": sum / float64(len(sorted)),
+		"p50":  percentile(50),
+		"p90":  percentile(90),
+		"p99":  percentile(99),
+		"min":  sorted[0],
+		"max":  sorted[len(sorted)-1],
+	}
+}
+
+func startupDelta(old, cur float64) float64 {
+	if old == 0 {
+		return 0
+	}
+	return (cur - old) * 100 / old
+}
+
+func loadStartupResult(file string) (*startupResult, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	r := &startupResult{}
+	if err = json.Unmarshal(b, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// printStartupStats method prints the stats in milliseconds, with deltas if
+// the previous result is given.
+func printStartupStats(w io.Writer, old, cur *startupResult) {
+	if old == nil {
+		fmt.Fprintf(w, "%-6s  %10s\n", "stat", "ms")
+		for _, name := range startupStatNames {
+			fmt.Fprintf(w, "%-6s  %10s\n", name, formatBenchValue(cur.Stats[name]))
+		}
+		return
+	}
+
+	fmt.Fprintf(w, "%-6s  %10s  %10s  %8s\n", "stat", "old ms", "new ms", "delta")
+	for _, name := range startupStatNames {
+		fmt.Fprintf(w, "%-6s  %10s  %10s  %+7.2f%%\n", name, formatBenchValue(old.Stats[name]),
+			formatBenchValue(cur.Stats[name]), startupDelta(old.Stats[name], cur.Stats[name]))
+	}
+}
+
+func init() {
+	benchStartupCmd.Run = benchStartupRun
+	benchCmd.Subcommands = append(benchCmd.Subcommands, benchStartupCmd)
+}
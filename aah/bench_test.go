@@ -0,0 +1,70 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestBenchCompare(t *testing.T) {
+	// older Go, package is known from result line
+	old := parseBenchOutput([]byte("BenchmarkFind-8   \t 1000000\t      1000 ns/op\t      64 B/op\t       2 allocs/op\n" +
+		"BenchmarkFind-8   \t 1000000\t      1200 ns/op\t      64 B/op\t       2 allocs/op\n" +
+		"BenchmarkRemoved-8\t 1000\t 10 ns/op\n" +
+		"PASS\n" +
+		"ok  \tgithub.com/user/app/app/models\t2.010s\n"))
+	assert.Equal(t, float64(1100), old["github.com/user/app/app/models.BenchmarkFind-8"].Values["ns/op"])
+
+	cur := parseBenchOutput([]byte("goos: linux\ngoarch: amd64\n" +
+		"pkg: github.com/user/app/app/models\n" +
+		"BenchmarkFind-8   \t 1000000\t      1375 ns/op\t      32 B/op\t       1 allocs/op\n" +
+		"BenchmarkNew-8    \t 1000000\t      50.5 ns/op\n" +
+		"PASS\n" +
+		"ok  \tgithub.com/user/app/app/models\t1.005s\n"))
+	assert.Equal(t, 2, len(cur))
+
+	deltas := compareBench(old, cur)
+	assert.Equal(t, 3, len(deltas))
+	assert.Equal(t, &benchDelta{Name: "github.com/user/app/app/models.BenchmarkFind-8", Unit: "ns/op", Old: 1100, New: 1375, Delta: 25}, deltas[0])
+	assert.Equal(t, float64(-50), deltas[1].Delta)
+	assert.Equal(t, "allocs/op", deltas[2].Unit)
+
+	assert.Nil(t, benchRegressions(deltas, 0))
+	assert.Nil(t, benchRegressions(deltas, 30))
+	assert.Equal(t, []string{"github.com/user/app/app/models.BenchmarkFind-8 +25.00%"}, benchRegressions(deltas, 10))
+
+	buf := &bytes.Buffer{}
+	printBenchDeltas(buf, deltas)
+	assert.True(t, strings.Contains(buf.String(), "github.com/user/app/app/models.BenchmarkFind-8            1100            1375   +25.00%\n"))
+	assert.Equal(t, "50.5", formatBenchValue(50.5))
+}
+
+func TestBenchStartupStats(t *testing.T) {
+	stats := startupStats([]float64{120, 100, 110, 300, 130, 105, 115, 125, 135, 140})
+	assert.Equal(t, 138.0, stats["mean"])
+	assert.Equal(t, 120.0, stats["p50"])
+	assert.Equal(t, 140.0, stats["p90"])
+	assert.Equal(t, 300.0, stats["p99"])
+	assert.Equal(t, 100.0, stats["min"])
+	assert.Equal(t, 300.0, stats["max"])
+
+	assert.Equal(t, "127.0.0.1", startupDialHost(""))
+	assert.Equal(t, "127.0.0.1", startupDialHost("0.0.0.0"))
+	assert.Equal(t, "localhost", startupDialHost("localhost"))
+
+	buf := &bytes.Buffer{}
+	cur := &startupResult{Stats: stats}
+	printStartupStats(buf, nil, cur)
+	assert.True(t, strings.HasPrefix(buf.String(), "stat            ms\nmean           138\n"))
+
+	buf.Reset()
+	printStartupStats(buf, &startupResult{Stats: map[string]float64{"mean": 120}}, cur)
+	assert.True(t, strings.Contains(buf.String(), "mean           120         138   +15.00%\n"))
+	assert.Equal(t, 0.0, startupDelta(0, 138))
+}
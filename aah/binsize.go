@@ -0,0 +1,180 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/log.v0"
+)
+
+var (
+	byteSizeRegex = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([KMG]I?B?|B)?$`)
+	byteSizeUnits = map[string]int64{"": 1, "B": 1, "K": 1 << 10, "M": 1 << 20, "G": 1 << 30}
+)
+
+// pkgSize is the total symbol size of the package in the binary.
+type pkgSize struct {
+	Name string
+	Size int64
+}
+
+// checkBinarySize method verifies the binary size against the budget
+// 'build.max_binary_size', size report is logged if it exceeds. It returns
+// error if 'build.binary_size_action' is 'fail' (default), otherwise warns.
+func checkBinarySize(buildCfg *config.Config, binary string) error {
+	budget := buildCfg.StringDefault("build.max_binary_size", "")
+	if len(budget) == 0 {
+		return nil
+	}
+	maxSize, err := parseByteSize(budget)
+	if err != nil {
+		return newExitErr(exitConfigError, fmt.Errorf("'build.max_binary_size': %s", err))
+	}
+	action := buildCfg.StringDefault("build.binary_size_action", "fail")
+	if action != "fail" && action != "warn" {
+		return newExitErr(exitConfigError, fmt.Errorf("'build.binary_size_action' value '%s' is not supported, choose either 'fail' or 'warn'", action))
+	}
+
+	fi, err := os.Stat(binary)
+	if err != nil {
+		return err
+	}
+	if fi.Size() <= maxSize {
+		log.Infof("Binary size %s is within the budget %s", formatByteSize(fi.Size()), formatByteSize(maxSize))
+		return nil
+	}
+
+	msg := fmt.Sprintf("Binary size %s exceeds the budget 'build.max_binary_size' %s by %s",
+		formatByteSize(fi.Size()), formatByteSize(maxSize), formatByteSize(fi.Size()-maxSize))
+	if sizes, err := binaryPkgSizes(binary); err == nil {
+		msg += "\n" + binarySizeReport(sizes, 15)
+	} else {
+		log.Debugf("Unable to read symbol sizes of %s: %s", binary, err)
+	}
+
+	if action == "warn" {
+		logWarn(msg)
+		return nil
+	}
+	return newExitErr(exitSizeError, errors.New(msg))
+}
+
+// binaryPkgSizes method returns the symbol size per package of the Go
+// binary via 'go tool nm -size', in descending order of size.
+func binaryPkgSizes(binary string) ([]*pkgSize, error) {
+	out, err := exec.Command(gocmd, "tool", "nm", "-size", binary).Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseNmSizes(out), nil
+}
+
+// parseNmSizes method sums the 'go tool nm -size' symbol sizes by package,
+// e.g. symbol 'github.com/user/app/models.(*User).Save' belongs to the
+// package 'github.com/user/app/models'.
+func parseNmSizes(out []byte) []*pkgSize {
+	byPkg := map[string]int64{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		// address size type name
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		// bss and undefined symbols do not take file size
+		if t := strings.ToUpper(fields[2]); t == "B" || t == "U" {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil || size == 0 {
+			continue
+		}
+		byPkg[symbolPkg(strings.Join(fields[3:], " "))] += size
+	}
+
+	sizes := make([]*pkgSize, 0, len(byPkg))
+	for name, size := range byPkg {
+		sizes = append(sizes, &pkgSize{Name: name, Size: size})
+	}
+	sort.Slice(sizes, func(i, j int) bool {
+		if sizes[i].Size == sizes[j].Size {
+			return sizes[i].Name < sizes[j].Name
+		}
+		return sizes[i].Size > sizes[j].Size
+	})
+	return sizes
+}
+
+// symbolPkg method returns the package path of the symbol name, type
+// symbols 'type:*<pkg>.<name>' belong to the package of the type.
+func symbolPkg(name string) string {
+	name = strings.TrimLeft(strings.TrimPrefix(name, "type:"), "*")
+	if idx := strings.IndexByte(name, '['); idx > 0 {
+		name = name[:idx]
+	}
+	slash := strings.LastIndex(name, "/")
+	if dot := strings.Index(name[slash+1:], "."); dot >= 0 {
+		return name[:slash+1+dot]
+	}
+	return name
+}
+
+// binarySizeReport method returns the report of top n packages by size.
+func binarySizeReport(sizes []*pkgSize, n int) string {
+	var total int64
+	for _, s := range sizes {
+		total += s.Size
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "    %-10s %-6s %s\n", "SIZE", "%", "PACKAGE")
+	for i, s := range sizes {
+		if i == n {
+			fmt.Fprintf(buf, "    ... %d more package(s)\n", len(sizes)-n)
+			break
+		}
+		fmt.Fprintf(buf, "    %-10s %-6.1f %s\n", formatByteSize(s.Size), float64(s.Size)*100/float64(total), s.Name)
+	}
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+// parseByteSize method parses the size value, e.g. '25MB', '512K', '1.5GiB'
+// or '1048576'. Units are binary multiples, '1MB' is 1024 KB.
+func parseByteSize(v string) (int64, error) {
+	m := byteSizeRegex.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(v)))
+	if m == nil {
+		return 0, fmt.Errorf("invalid size '%s', e.g: 25MB, 512KB", v)
+	}
+	n, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size '%s': %s", v, err)
+	}
+	return int64(n * float64(byteSizeUnits[strings.TrimRight(m[2], "IB")])), nil
+}
+
+// formatByteSize method returns the size in human readable binary
+// multiples, e.g. '24.3MB'.
+func formatByteSize(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1fGB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1fKB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%dB", n)
+}
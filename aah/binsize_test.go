@@ -0,0 +1,54 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestBinarySizeParse(t *testing.T) {
+	for v, expected := range map[string]int64{
+		"25MB":    25 << 20,
+		"512k":    512 << 10,
+		"1.5GiB":  3 << 29,
+		"1048576": 1 << 20,
+		"100 B":   100,
+	} {
+		size, err := parseByteSize(v)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, size)
+	}
+
+	_, err := parseByteSize("25 mega")
+	assert.Equal(t, "invalid size '25 mega', e.g: 25MB, 512KB", err.Error())
+
+	assert.Equal(t, "24.5MB", formatByteSize(24<<20+512<<10))
+	assert.Equal(t, "1.0KB", formatByteSize(1024))
+	assert.Equal(t, "900B", formatByteSize(900))
+}
+
+func TestBinarySizeReport(t *testing.T) {
+	out := []byte(`  4a1000      30000 T runtime.mallocgc
+  4b2000      10000 T github.com/user/app/models.(*User).Save
+  4b3000      20000 R type:*github.com/user/app/models.User
+  4c4000      40000 T net/http.(*conn).serve
+  4c5000          0 U _cgo_init
+  4d6000       5000 T sort.Slice[go.shape.*uint8]
+  5e7000    8000000 B crypto/internal/drbg.memory
+`)
+	sizes := parseNmSizes(out)
+	assert.Equal(t, 4, len(sizes))
+	assert.Equal(t, "net/http", sizes[0].Name)
+	assert.Equal(t, "github.com/user/app/models", sizes[1].Name)
+	assert.Equal(t, int64(30000), sizes[1].Size)
+	assert.Equal(t, "sort", sizes[3].Name)
+
+	report := binarySizeReport(sizes, 2)
+	assert.True(t, strings.Contains(report, "    39.1KB     38.1   net/http\n"))
+	assert.True(t, strings.HasSuffix(report, "    ... 2 more package(s)"))
+}
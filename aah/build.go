@@ -9,6 +9,7 @@ import (
 	"flag"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 
 	"aahframework.org/aah.v0"
@@ -25,15 +26,21 @@ var (
 	buildArtifactPathShortFlag = buildCmdFlags.String("ap", "", "Output location application build artifact. Default location is <app-base>/aah-build")
 	buildProfileFlag           = buildCmdFlags.String("profile", "", "Environment profile name to activate. e.g: dev, qa, prod")
 	buildProfileShortFlag      = buildCmdFlags.String("p", "", "Environment profile name to activate. e.g: dev, qa, prod")
+	buildMatrixFlag            = &buildMatrix{}
 	buildCmd                   = &command{
 		Name:      "build",
-		UsageLine: "aah build [-ip | -importPath] [-ap | -artifactPath] [-p | -profile]",
+		UsageLine: "aah build [-ip | -importPath] [-ap | -artifactPath] [-p | -profile] [-matrix]",
 		Flags:     buildCmdFlags,
-		ArgsCount: 3,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
 		Short:     "build aah application for deployment",
 		Long: `
 Build the aah web/api application by importPath.
 
+Flag '-matrix' builds an artifact for each '<os>/<arch>' target, targets
+are read from 'ci.platforms' of 'aah.project' or given as flag value
+'-matrix=linux/amd64,windows/amd64'. Artifact name has the target suffix,
+e.g. 'myapp-1.0.0-windows-amd64.zip'.
+
 To know more CLI tool - https://docs.aahframework.org/aah-cli-tool.html
 
 Example(s) short and long flag:
@@ -41,6 +48,10 @@ Example(s) short and long flag:
 
     aah build -p=dev
 
+    aah build -matrix -p=prod
+
+    aah build -matrix=linux/amd64,darwin/amd64 -p=prod
+
     aah build -ip=github.com/user/appname -ap=/Users/jeeva -p=qa
 
     aah build -importPath=github.com/user/appname -artifactPath=/Users/jeeva -profile=qa
@@ -48,48 +59,112 @@ Example(s) short and long flag:
 	}
 )
 
-func buildRun(args []string) {
-	if err := buildCmdFlags.Parse(args); err != nil {
-		fatal(err)
-	}
+// buildMatrix is the value of '-matrix' flag, it is set without value to
+// build the 'ci.platforms' targets of 'aah.project'.
+type buildMatrix struct {
+	Enabled bool
+	Targets string
+}
 
-	var err error
-	importPath := firstNonEmpty(*buildImportPathFlag, *buildImportPathShortFlag)
-	if ess.IsStrEmpty(importPath) {
-		importPath = importPathRelwd()
+func (m *buildMatrix) String() string {
+	if m == nil || !m.Enabled {
+		return ""
 	}
+	return firstNonEmpty(m.Targets, "true")
+}
 
-	if !ess.IsImportPathExists(importPath) {
-		fatalf("Given import path '%s' does not exists", importPath)
+func (m *buildMatrix) Set(v string) error {
+	switch v {
+	case "true":
+		m.Enabled, m.Targets = true, ""
+	case "false":
+		m.Enabled, m.Targets = false, ""
+	default:
+		m.Enabled, m.Targets = true, v
 	}
+	return nil
+}
+
+func (m *buildMatrix) IsBoolFlag() bool {
+	return true
+}
 
-	aah.Init(importPath)
-	appBaseDir := aah.AppBaseDir()
+func buildRun(args []string) {
+	if !buildMatrixFlag.Enabled {
+		buildApp()
+		return
+	}
 
-	buildCfg, err := loadAahProjectFile(appBaseDir)
+	targets, err := parsePlatforms(ciList(appProject.BuildCfg, buildMatrixFlag.Targets, "ci.platforms", "linux/amd64"))
 	if err != nil {
-		fatalf("aah project file error: %s", err)
+		fatalErr(newExitErr(exitUsage, err))
+		return
+	}
+
+	goos, goarch := os.Getenv("GOOS"), os.Getenv("GOARCH")
+	defer func() {
+		_ = os.Setenv("GOOS", goos)
+		_ = os.Setenv("GOARCH", goarch)
+	}()
+	for _, t := range targets {
+		_ = os.Setenv("GOOS", t.OS)
+		_ = os.Setenv("GOARCH", t.Arch)
+		log.Infof("Build target %s/%s", t.OS, t.Arch)
+		buildApp()
 	}
+}
 
-	_ = log.SetLevel(buildCfg.StringDefault("build.log_level", "info"))
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// buildApp method compiles and packages the application artifact for the
+// target of 'GOOS' and 'GOARCH'.
+func buildApp() {
+	appBaseDir := appProject.BaseDir
+	buildCfg := appProject.BuildCfg
 
 	log.Infof("Build starts for '%s' [%s]", aah.AppName(), aah.AppImportPath())
 
-	appBinay, err := compileApp(buildCfg, true)
+	appBinay, err := compileApp(buildCfg, &compileOptions{Pack: true})
 	if err != nil {
-		fatal(err)
+		fatalErr(err)
 	}
 
+	archiveName := ess.StripExt(filepath.Base(appBinay)) + "-" + getAppVersion(appBaseDir, buildCfg)
+	archiveName = addTargetBuildInfo(archiveName)
+	appBuildDir := filepath.Join(appBaseDir, "build")
+	destArchiveDir := firstNonEmpty(*buildArtifactPathFlag, *buildArtifactPathShortFlag, appBuildDir)
+
+	if isDryRun() {
+		logDryRun("create application artifact %s.zip", filepath.Join(destArchiveDir, archiveName))
+		return
+	}
+
+	endStage := startStage("package")
+	defer endStage()
+
 	appProfile := firstNonEmpty(*buildProfileFlag, *buildProfileShortFlag, "prod")
 	buildBaseDir, err := copyFilesToWorkingDir(buildCfg, appBaseDir, appBinay, appProfile)
 	if err != nil {
 		fatal(err)
 	}
 
-	archiveName := ess.StripExt(filepath.Base(appBinay)) + "-" + getAppVersion(appBaseDir, buildCfg)
-	archiveName = addTargetBuildInfo(archiveName)
-	appBuildDir := filepath.Join(appBaseDir, "build")
-	destArchiveDir := firstNonEmpty(*buildArtifactPathFlag, *buildArtifactPathShortFlag, appBuildDir)
+	// remote config is bundled, startup script uses it as external config
+	remoteFile, err := fetchRemoteConfig(buildCfg, appBaseDir)
+	if err != nil {
+		fatalErr(newExitErr(exitConfigError, err))
+	}
+	if !ess.IsStrEmpty(remoteFile) {
+		content, err := ioutil.ReadFile(remoteFile)
+		if err == nil {
+			_ = ess.MkDirAll(filepath.Join(buildBaseDir, "config"), permRWXRXRX)
+			err = ioutil.WriteFile(filepath.Join(buildBaseDir, "config", remoteConfigFile), content, 0600)
+		}
+		if err != nil {
+			fatal(err)
+		}
+	}
 
 	// Creating app archive
 	destZip, err := createZipArchive(buildBaseDir, destArchiveDir, archiveName)
@@ -97,6 +172,7 @@ func buildRun(args []string) {
 		fatal(err)
 	}
 
+	recordArtifact(destZip)
 	log.Infof("Build successful for '%s' [%s]", aah.AppName(), aah.AppImportPath())
 	log.Infof("Your application artifact is here: %s", destZip)
 }
@@ -131,6 +207,12 @@ func copyFilesToWorkingDir(buildCfg *config.Config, appBaseDir, appBinary, appPr
 		fatal(err)
 	}
 
+	// compiled assets are copied along with the sources
+	assets, err := buildAssets(buildCfg, appBaseDir)
+	if err != nil {
+		return "", err
+	}
+
 	// aah application and custom directories
 	appDirs, _ := ess.DirsPath(appBaseDir, false)
 	subTreeExcludes := ess.Excludes(excludeAndCreateSlice(cfgExcludes, "app"))
@@ -146,6 +228,40 @@ func copyFilesToWorkingDir(buildCfg *config.Config, appBaseDir, appBinary, appPr
 		}
 	}
 
+	// minified assets in place of sources
+	for rel, dist := range assets {
+		dest := filepath.Join(buildBaseDir, filepath.FromSlash(rel))
+		if !ess.IsFileExists(dest) {
+			continue
+		}
+		content, err := ioutil.ReadFile(dist)
+		if err != nil {
+			return "", err
+		}
+		if err = ioutil.WriteFile(dest, content, permRWRR); err != nil {
+			return "", err
+		}
+	}
+
+	// fingerprinted assets and manifest
+	staticFiles, err := staticPackageFiles(buildBaseDir)
+	if err != nil {
+		return "", err
+	}
+	fingerprinted, err := fingerprintAssets(buildCfg, appBaseDir, "", staticFiles)
+	if err != nil {
+		return "", err
+	}
+	for _, f := range fingerprinted {
+		content, err := f.Bytes()
+		if err != nil {
+			return "", err
+		}
+		if err = ioutil.WriteFile(filepath.Join(buildBaseDir, filepath.FromSlash(f.Name)), content, permRWRR); err != nil {
+			return "", err
+		}
+	}
+
 	// startup files
 	data := map[string]string{
 		"AppName":    ess.StripExt(appBinaryName),
@@ -243,6 +359,11 @@ if [ ! -x "$APP_EXECUTABLE" ]; then
   exit 1
 fi
 
+# remote config bundled by 'aah build', if external config is not supplied
+if [ -z "$APP_EXT_CONFIG" ] && [ -f "$APP_DIR/config/remote.conf" ]; then
+  APP_EXT_CONFIG="-config=$APP_DIR/config/remote.conf"
+fi
+
 # go to application base directory
 cd "$APP_DIR"
 
@@ -264,6 +385,11 @@ start() {
     fi
   fi
 
+  # secrets of 'aah secrets', uploaded by 'aah deploy ssh'
+  if [ -f "$APP_DIR/secrets.env" ]; then
+    set -a; . "$APP_DIR/secrets.env"; set +a
+  fi
+
   nohup "$APP_EXECUTABLE" -profile="$APP_ENV_PROFILE" "$APP_EXT_CONFIG" > appstart.log 2>&1 &
   echo "$APP_NAME started."
 }
@@ -365,6 +491,11 @@ IF NOT "%3" == "" (
 REM resolve APP_DIR and set executable
 SET APP_DIR=%~dp0
 SET APP_EXECUTABLE=%APP_DIR%bin\%APP_NAME%.exe
+
+REM remote config bundled by 'aah build', if external config is not supplied
+IF %APP_EXT_CONFIG% == "" (
+  IF EXIST "%APP_DIR%config\remote.conf" SET APP_EXT_CONFIG="-config %APP_DIR%config\remote.conf"
+)
 SET APP_PID=%APP_DIR%%APP_NAME%.pid
 
 REM change directory
@@ -408,5 +539,6 @@ ENDLOCAL
 `
 
 func init() {
+	buildCmdFlags.Var(buildMatrixFlag, "matrix", "Build an artifact for each '<os>/<arch>' target. Default is 'ci.platforms' of aah.project or 'linux/amd64'")
 	buildCmd.Run = buildRun
 }
@@ -0,0 +1,40 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestBuildMatrixFlag(t *testing.T) {
+	fs := flag.NewFlagSet("build", flag.ContinueOnError)
+	m := &buildMatrix{}
+	fs.Var(m, "matrix", "")
+	assert.Nil(t, fs.Parse([]string{"-matrix", "extra"}))
+	assert.True(t, m.Enabled)
+	assert.Equal(t, "", m.Targets)
+	assert.Equal(t, []string{"extra"}, fs.Args())
+	assert.Equal(t, "true", m.String())
+
+	m = &buildMatrix{}
+	fs = flag.NewFlagSet("build", flag.ContinueOnError)
+	fs.Var(m, "matrix", "")
+	assert.Nil(t, fs.Parse([]string{"-matrix=linux/amd64,darwin/arm64"}))
+	assert.True(t, m.Enabled)
+	assert.Equal(t, "linux/amd64,darwin/arm64", m.String())
+
+	platforms, err := parsePlatforms(splitList(m.Targets))
+	assert.Nil(t, err)
+	assert.Equal(t, []ciPlatform{{"linux", "amd64"}, {"darwin", "arm64"}}, platforms)
+
+	_, err = parsePlatforms([]string{"linux"})
+	assert.Equal(t, "invalid platform 'linux', e.g: linux/amd64", err.Error())
+
+	assert.Nil(t, m.Set("false"))
+	assert.False(t, m.Enabled)
+}
@@ -0,0 +1,314 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+const (
+	cacheScopeProject = "project"
+	cacheScopeUser    = "user"
+	cacheScopeGo      = "go"
+
+	goBuildCacheName = "go-build"
+)
+
+var (
+	cacheCmd = &command{
+		Name:      "cache",
+		UsageLine: "aah cache <subcommand> [arguments]",
+		Short:     "report and prune aah CLI tool caches",
+		Long: `
+Reports and prunes the caches of aah CLI tool. Application caches are under
+'<app-base>/.aah/', it's reported if run from the application directory or
+import path is supplied:
+
+    run          interpolated config of 'aah run'
+    config       cached remote config, refer 'config.remote' of aah.project
+    coverage     coverage profiles and report of 'aah test -coverage'
+    test         JUnit report of 'aah test'
+    bench        benchmark results of 'aah bench', used for comparison
+    profile      CLI profiles of global flag '-profile-cli'
+    logs         CLI logs of global flag '-log-file'
+    deploy       deployment logs of 'aah deploy'
+
+User caches are under '~/.aah/':
+
+    templates    application templates cloned by 'aah new -template'
+
+Go build cache ('go env GOCACHE') is shared by all Go projects, so it's
+reported and cleaned only if it's named explicitly:
+
+    go-build     Go build cache, cleaned via 'go clean -cache'
+
+Example:
+    aah cache info
+    aah cache clean -all
+    aah cache clean coverage bench
+    aah cache clean -older-than=720h templates
+`,
+	}
+
+	cacheInfoCmdFlags            = flag.NewFlagSet("info", flag.ContinueOnError)
+	cacheInfoImportPathFlag      = cacheInfoCmdFlags.String("importPath", "", "Import path of aah application. Default is current directory")
+	cacheInfoImportPathShortFlag = cacheInfoCmdFlags.String("ip", "", "Import path of aah application. Default is current directory")
+	cacheInfoCmd                 = &command{
+		Name:      "info",
+		UsageLine: "aah cache info [-importPath | -ip] [<name>...]",
+		Flags:     cacheInfoCmdFlags,
+		Hooks:     []hook{validateGopath},
+		Short:     "report the size of aah CLI tool caches",
+		Long: `
+Reports the size, no. of files and location of the caches, refer 'aah help
+cache' for the cache names. Go build cache is reported if it's named.
+
+Example:
+    aah cache info
+
+    aah cache info templates go-build
+`,
+	}
+
+	cacheCleanCmdFlags            = flag.NewFlagSet("clean", flag.ContinueOnError)
+	cacheCleanAllFlag             = cacheCleanCmdFlags.Bool("all", false, "Clean all the application and user caches, Go build cache is not included")
+	cacheCleanOlderThanFlag       = cacheCleanCmdFlags.String("older-than", "", "Clean only the files older than duration e.g: 720h")
+	cacheCleanImportPathFlag      = cacheCleanCmdFlags.String("importPath", "", "Import path of aah application. Default is current directory")
+	cacheCleanImportPathShortFlag = cacheCleanCmdFlags.String("ip", "", "Import path of aah application. Default is current directory")
+	cacheCleanCmd                 = &command{
+		Name:      "clean",
+		UsageLine: "aah cache clean [-all] [-older-than] [-importPath | -ip] [<name>...]",
+		Flags:     cacheCleanCmdFlags,
+		Hooks:     []hook{validateGopath},
+		Short:     "prune aah CLI tool caches",
+		Long: `
+Prunes the named caches or all the caches with '-all', refer 'aah help
+cache' for the cache names. With '-older-than' only the files older than
+the duration are deleted, along with the directories left empty. Go build
+cache is cleaned only if it's named, '-older-than' is not applicable to it.
+
+Example:
+    aah cache clean -all
+
+    aah cache clean coverage bench
+
+    aah cache clean -older-than=720h templates
+`,
+	}
+)
+
+// cacheEntry is the cache directory of aah CLI tool.
+type cacheEntry struct {
+	Name     string
+	Scope    string
+	Dir      string
+	Explicit bool
+}
+
+func cacheInfoRun(args []string) {
+	entries, err := selectCacheEntries(cacheEntries(findAppBaseDir(firstNonEmpty(*cacheInfoImportPathFlag, *cacheInfoImportPathShortFlag))), args, false)
+	if err != nil {
+		fatalErr(newExitErr(exitUsage, err))
+		return
+	}
+
+	_ = log.SetPattern("%message")
+	defer func() {
+		_ = log.SetPattern(log.DefaultPattern)
+	}()
+
+	var totalSize, totalFiles int64
+	log.Infof("    %-10s %-8s %10s %7s  %s", "NAME", "SCOPE", "SIZE", "FILES", "LOCATION")
+	for _, e := range entries {
+		size, files := dirUsage(e.Dir)
+		totalSize += size
+		totalFiles += files
+		log.Infof("    %-10s %-8s %10s %7d  %s", e.Name, e.Scope, formatByteSize(size), files, e.Dir)
+	}
+	log.Infof("    %-10s %-8s %10s %7d", "total", "", formatByteSize(totalSize), totalFiles)
+	log.Info()
+}
+
+func cacheCleanRun(args []string) {
+	if len(args) == 0 && !*cacheCleanAllFlag {
+		fatalExitf(exitUsage, "Supply cache name(s) or '-all' flag, refer 'aah help cache'")
+		return
+	}
+
+	var olderThan time.Duration
+	if !ess.IsStrEmpty(*cacheCleanOlderThanFlag) {
+		var err error
+		if olderThan, err = time.ParseDuration(*cacheCleanOlderThanFlag); err != nil || olderThan <= 0 {
+			fatalExitf(exitUsage, "Invalid '-older-than' value '%s', e.g: 720h", *cacheCleanOlderThanFlag)
+			return
+		}
+	}
+
+	entries, err := selectCacheEntries(cacheEntries(findAppBaseDir(firstNonEmpty(*cacheCleanImportPathFlag, *cacheCleanImportPathShortFlag))), args, *cacheCleanAllFlag)
+	if err != nil {
+		fatalErr(newExitErr(exitUsage, err))
+		return
+	}
+
+	var totalSize int64
+	for _, e := range entries {
+		if !ess.IsFileExists(e.Dir) {
+			continue
+		}
+
+		if e.Name == goBuildCacheName {
+			size, _ := dirUsage(e.Dir)
+			if _, err = execCmd(gocmd, []string{"clean", "-cache"}, false); err != nil {
+				fatalf("Unable to clean Go build cache: %s", err)
+				return
+			}
+			totalSize += size
+			log.Infof("Cleaned '%s' %s", e.Name, formatByteSize(size))
+			continue
+		}
+
+		var size int64
+		if olderThan > 0 {
+			size = pruneDir(e.Dir, time.Now().Add(-olderThan))
+		} else {
+			size, _ = dirUsage(e.Dir)
+			deleteFiles(e.Dir)
+		}
+		totalSize += size
+		log.Infof("Cleaned '%s' %s", e.Name, formatByteSize(size))
+	}
+	log.Infof("Cleaned %s in total", formatByteSize(totalSize))
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// cacheEntries method returns the cache directories, application caches are
+// included if base directory is supplied.
+func cacheEntries(baseDir string) []*cacheEntry {
+	var entries []*cacheEntry
+	if !ess.IsStrEmpty(baseDir) {
+		for _, name := range []string{"run", "config", "coverage", "test", "bench", "profile", "logs", "deploy"} {
+			entries = append(entries, &cacheEntry{Name: name, Scope: cacheScopeProject,
+				Dir: filepath.Join(baseDir, ".aah", name)})
+		}
+	}
+	entries = append(entries, &cacheEntry{Name: "templates", Scope: cacheScopeUser,
+		Dir: filepath.Join(aahUserDir(), "templates")})
+
+	if !ess.IsStrEmpty(gocmd) {
+		if out, err := exec.Command(gocmd, "env", "GOCACHE").Output(); err == nil {
+			if dir := strings.TrimSpace(string(out)); !ess.IsStrEmpty(dir) && dir != "off" {
+				entries = append(entries, &cacheEntry{Name: goBuildCacheName, Scope: cacheScopeGo,
+					Dir: dir, Explicit: true})
+			}
+		}
+	}
+	return entries
+}
+
+// selectCacheEntries method returns the named cache entries, without names
+// all the entries except explicit ones are returned for info or if all is
+// true.
+func selectCacheEntries(entries []*cacheEntry, names []string, all bool) ([]*cacheEntry, error) {
+	var selected []*cacheEntry
+	if len(names) == 0 || all {
+		for _, e := range entries {
+			if !e.Explicit {
+				selected = append(selected, e)
+			}
+		}
+	}
+
+	for _, name := range names {
+		var found *cacheEntry
+		for _, e := range entries {
+			if e.Name == name {
+				found = e
+				break
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("unknown cache '%s', refer 'aah help cache'", name)
+		}
+		if !isCacheEntrySelected(selected, found) {
+			selected = append(selected, found)
+		}
+	}
+	return selected, nil
+}
+
+func isCacheEntrySelected(entries []*cacheEntry, e *cacheEntry) bool {
+	for _, s := range entries {
+		if s == e {
+			return true
+		}
+	}
+	return false
+}
+
+// dirUsage method returns the total size and no. of files in the directory.
+func dirUsage(dir string) (size, files int64) {
+	if !ess.IsFileExists(dir) {
+		return
+	}
+	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			size += info.Size()
+			files++
+		}
+		return nil
+	})
+	return
+}
+
+// pruneDir method deletes the files modified before given time and the
+// directories left empty, it returns the deleted size.
+func pruneDir(dir string, before time.Time) int64 {
+	var size int64
+	var dirs []string
+	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if path != dir {
+				dirs = append(dirs, path)
+			}
+			return nil
+		}
+		if info.ModTime().Before(before) {
+			size += info.Size()
+			deleteFiles(path)
+		}
+		return nil
+	})
+
+	if isDryRun() {
+		return size
+	}
+
+	// deepest directories first
+	for i := len(dirs) - 1; i >= 0; i-- {
+		_ = os.Remove(dirs[i])
+	}
+	return size
+}
+
+func init() {
+	cacheInfoCmd.Run = cacheInfoRun
+	cacheCleanCmd.Run = cacheCleanRun
+	cacheCmd.Subcommands = append(cacheCmd.Subcommands, cacheInfoCmd, cacheCleanCmd)
+}
@@ -0,0 +1,78 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/test.v0/assert"
+)
+
+func TestCacheEntries(t *testing.T) {
+	entries := []*cacheEntry{
+		{Name: "coverage", Scope: cacheScopeProject},
+		{Name: "templates", Scope: cacheScopeUser},
+		{Name: goBuildCacheName, Scope: cacheScopeGo, Explicit: true},
+	}
+
+	selected, err := selectCacheEntries(entries, nil, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(selected))
+
+	selected, err = selectCacheEntries(entries, []string{"templates"}, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(selected))
+	assert.Equal(t, "templates", selected[0].Name)
+
+	selected, err = selectCacheEntries(entries, []string{"coverage", goBuildCacheName}, true)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(selected))
+
+	_, err = selectCacheEntries(entries, []string{"ast"}, false)
+	assert.Equal(t, "unknown cache 'ast', refer 'aah help cache'", err.Error())
+}
+
+func TestCachePruneDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-cache")
+	assert.Nil(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	old := time.Now().Add(-48 * time.Hour)
+	for name, content := range map[string]string{
+		"bench/old/result.json": "0123456789",
+		"bench/new/result.json": "01234",
+		"bench/last.json":       "012",
+	} {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		assert.Nil(t, os.MkdirAll(filepath.Dir(file), permRWXRXRX))
+		assert.Nil(t, ioutil.WriteFile(file, []byte(content), permRWRR))
+		if name != "bench/new/result.json" {
+			assert.Nil(t, os.Chtimes(file, old, old))
+		}
+	}
+
+	benchDir := filepath.Join(dir, "bench")
+	size, files := dirUsage(benchDir)
+	assert.Equal(t, int64(18), size)
+	assert.Equal(t, int64(3), files)
+
+	assert.Equal(t, int64(13), pruneDir(benchDir, time.Now().Add(-24*time.Hour)))
+	size, files = dirUsage(benchDir)
+	assert.Equal(t, int64(5), size)
+	assert.Equal(t, int64(1), files)
+	assert.False(t, ess.IsFileExists(filepath.Join(benchDir, "old")))
+	assert.True(t, ess.IsFileExists(filepath.Join(benchDir, "new")))
+
+	size, files = dirUsage(filepath.Join(dir, "coverage"))
+	assert.Equal(t, int64(0), size)
+	assert.Equal(t, int64(0), files)
+}
@@ -0,0 +1,158 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+const (
+	statusResultSuccess = "success"
+	statusResultFailure = "failure"
+)
+
+type (
+	// cliStatus is the machine-readable status of the command written into
+	// file of global flag '-status-file', CI steps parse it to annotate the
+	// build.
+	cliStatus struct {
+		Command    string         `json:"command"`
+		Args       []string       `json:"args"`
+		Result     string         `json:"result"`
+		ExitCode   int            `json:"exit_code"`
+		ExitName   string         `json:"exit_name"`
+		Error      string         `json:"error,omitempty"`
+		DryRun     bool           `json:"dry_run"`
+		StartedAt  time.Time      `json:"started_at"`
+		DurationMs int64          `json:"duration_ms"`
+		Stages     []*statusStage `json:"stages"`
+		Warnings   []string       `json:"warnings"`
+		Artifacts  []string       `json:"artifacts"`
+	}
+
+	statusStage struct {
+		Name       string `json:"name"`
+		DurationMs int64  `json:"duration_ms"`
+	}
+)
+
+var (
+	statusWarnings  []string
+	statusArtifacts []string
+	statusError     string
+	statusWritten   bool
+)
+
+func isStatusFile() bool {
+	return statusFileFlag != nil && !ess.IsStrEmpty(*statusFileFlag)
+}
+
+// logWarn method logs the warning and records it for the status file.
+func logWarn(v ...interface{}) {
+	log.Warn(v...)
+	statusWarnings = append(statusWarnings, strings.TrimSpace(fmt.Sprint(v...)))
+}
+
+// logWarnf method logs the formatted warning and records it for the status
+// file.
+func logWarnf(format string, v ...interface{}) {
+	log.Warnf(format, v...)
+	statusWarnings = append(statusWarnings, strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+// recordArtifact method records the file produced by the command for the
+// status file, path is made absolute. Files are not produced in dry-run
+// mode, so they are not recorded.
+func recordArtifact(file string) {
+	if isDryRun() {
+		return
+	}
+	if abs, err := filepath.Abs(file); err == nil {
+		file = abs
+	}
+	for _, a := range statusArtifacts {
+		if a == file {
+			return
+		}
+	}
+	statusArtifacts = append(statusArtifacts, file)
+}
+
+// recordStatusError method records the error message of the command failure.
+func recordStatusError(msg string) {
+	statusError = strings.TrimSpace(msg)
+}
+
+// newCLIStatus method creates the status of the command with exit code,
+// command name is resolved via 'commandPath'.
+func newCLIStatus(args []string, code int) *cliStatus {
+	s := &cliStatus{
+		Args:       append([]string{}, args...),
+		Result:     statusResultSuccess,
+		ExitCode:   code,
+		DryRun:     isDryRun(),
+		StartedAt:  cliStartTime,
+		DurationMs: durationMs(time.Since(cliStartTime)),
+		Stages:     []*statusStage{},
+		Warnings:   append([]string{}, statusWarnings...),
+		Artifacts:  append([]string{}, statusArtifacts...),
+	}
+	if code != exitOK {
+		s.Result = statusResultFailure
+		s.Error = statusError
+	}
+	s.ExitName = exitName(code)
+	s.Command = commandPath(args)
+
+	for _, st := range cliStages {
+		s.Stages = append(s.Stages, &statusStage{Name: st.Name, DurationMs: durationMs(st.Duration)})
+	}
+	return s
+}
+
+// commandPath method returns the command name from the arguments along with
+// subcommands, e.g. 'release publish'.
+func commandPath(args []string) string {
+	var names []string
+	cmds := subCmds
+	for _, a := range args {
+		cmd, err := cmds.Find(a)
+		if err != nil {
+			break
+		}
+		names = append(names, cmd.Name)
+		cmds = cmd.Subcommands
+	}
+	return strings.Join(names, " ")
+}
+
+// writeStatusFile method writes the status file once if global flag
+// '-status-file' is supplied, it's written in dry-run mode too.
+func writeStatusFile(args []string, code int) {
+	if !isStatusFile() || statusWritten {
+		return
+	}
+	statusWritten = true
+
+	b, err := json.MarshalIndent(newCLIStatus(args, code), "", "  ")
+	if err == nil {
+		err = ioutil.WriteFile(*statusFileFlag, append(b, '\n'), permRWRR)
+	}
+	if err != nil {
+		log.Errorf("Unable to write status file: %s", err)
+	}
+}
+
+func durationMs(d time.Duration) int64 {
+	return int64(d / time.Millisecond)
+}
@@ -0,0 +1,56 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestCLIStatus(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-status")
+	assert.Nil(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+		*statusFileFlag = ""
+		statusWarnings, statusArtifacts, statusError, statusWritten = nil, nil, "", false
+	}()
+
+	logWarnf("Secrets are not loaded: %s", "no key")
+	recordArtifact(filepath.Join(dir, "app.zip"))
+	recordArtifact(filepath.Join(dir, "app.zip"))
+	recordStatusError("Unable to publish GitHub release: 401 Unauthorized\n")
+
+	s := newCLIStatus([]string{"release", "publish", "-github=myorg/myapp"}, exitError)
+	assert.Equal(t, "release publish", s.Command)
+	assert.Equal(t, "failure", s.Result)
+	assert.Equal(t, "error", s.ExitName)
+	assert.Equal(t, "Unable to publish GitHub release: 401 Unauthorized", s.Error)
+	assert.Equal(t, []string{"Secrets are not loaded: no key"}, s.Warnings)
+	assert.Equal(t, []string{filepath.Join(dir, "app.zip")}, s.Artifacts)
+
+	s = newCLIStatus([]string{"build"}, exitOK)
+	assert.Equal(t, "build", s.Command)
+	assert.Equal(t, "success", s.Result)
+	assert.Equal(t, "", s.Error)
+
+	file := filepath.Join(dir, "status.json")
+	*statusFileFlag = file
+	writeStatusFile([]string{"build"}, exitCompileError)
+	writeStatusFile([]string{"build"}, exitOK)
+
+	b, err := ioutil.ReadFile(file)
+	assert.Nil(t, err)
+	written := &cliStatus{}
+	assert.Nil(t, json.Unmarshal(b, written))
+	assert.Equal(t, "build", written.Command)
+	assert.Equal(t, exitCompileError, written.ExitCode)
+	assert.Equal(t, "compile", written.ExitName)
+}
@@ -0,0 +1,210 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+const (
+	cliConfigFile     = "cli.conf"
+	cliLogDefaultFile = ".aah/logs/aah.log"
+)
+
+var (
+	// cliStdout is the stdout of aah CLI tool before it's mirrored into log
+	// file, progress spinner writes into it directly.
+	cliStdout = os.Stdout
+
+	cliLog      *rotatingFile
+	cliLogWG    sync.WaitGroup
+	cliLogPipes []*os.File
+
+	ansiEscapeRegex = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+)
+
+// rotatingFile is the size based rotating log file, on rotate 'aah.log' is
+// renamed to 'aah.log.1', 'aah.log.1' to 'aah.log.2' and so on up to max
+// backups.
+type rotatingFile struct {
+	sync.Mutex
+	name       string
+	maxSize    int64
+	maxBackups int
+	size       int64
+	f          *os.File
+}
+
+// loadCLIConfig method loads the aah CLI tool config '~/.aah/cli.conf', it
+// returns empty config if file does not exists.
+//
+//	log {
+//	  format = "json"
+//	  enable = true
+//	  file = ".aah/logs/aah.log"
+//	  max_size_mb = 10
+//	  max_backups = 5
+//	}
+func loadCLIConfig() *config.Config {
+	file := filepath.Join(aahUserDir(), cliConfigFile)
+	if ess.IsFileExists(file) {
+		cfg, err := config.LoadFile(file)
+		if err == nil {
+			return cfg
+		}
+		log.Errorf("Unable to load '%s': %s", file, err)
+	}
+	cfg, _ := config.ParseString(``)
+	return cfg
+}
+
+func isCLILog() bool {
+	return cliLog != nil
+}
+
+// startCLILog method mirrors the stdout and stderr of aah CLI tool, including
+// commands executed by it, into rotating log file if flag '-log-file' is
+// supplied or 'log.enable' is true in 'cli.conf'.
+func startCLILog(args []string, cliCfg *config.Config) {
+	file := *logFileFlag
+	if ess.IsStrEmpty(file) && cliCfg.BoolDefault("log.enable", false) {
+		file = cliCfg.StringDefault("log.file", cliLogDefaultFile)
+	}
+	if ess.IsStrEmpty(file) {
+		return
+	}
+
+	var err error
+	cliLog, err = openRotatingFile(file,
+		int64(cliCfg.IntDefault("log.max_size_mb", 10))*1024*1024,
+		cliCfg.IntDefault("log.max_backups", 5))
+	if err != nil {
+		log.Errorf("Unable to open log file: %s", err)
+		return
+	}
+	fmt.Fprintf(cliLog, "\n---- %s aah %s ----\n", time.Now().Format(time.RFC3339), strings.Join(args, " "))
+
+	os.Stdout = mirrorOutput(os.Stdout)
+	os.Stderr = mirrorOutput(os.Stderr)
+	log.SetWriter(os.Stderr)
+}
+
+// stopCLILog method flushes the mirrored output and closes the log file.
+func stopCLILog() {
+	if cliLog == nil {
+		return
+	}
+
+	for _, p := range cliLogPipes {
+		_ = p.Close()
+	}
+	cliLogWG.Wait()
+	cliLogPipes = nil
+	_ = cliLog.Close()
+	cliLog = nil
+}
+
+// mirrorOutput method returns the pipe, written content goes into given
+// output and log file without ANSI escape codes.
+func mirrorOutput(out *os.File) *os.File {
+	r, w, err := os.Pipe()
+	if err != nil {
+		log.Errorf("Unable to mirror output into log file: %s", err)
+		return out
+	}
+	cliLogPipes = append(cliLogPipes, w)
+
+	cliLogWG.Add(1)
+	go func() {
+		defer cliLogWG.Done()
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := r.Read(buf)
+			if n > 0 {
+				_, _ = out.Write(buf[:n])
+				_, _ = cliLog.Write(ansiEscapeRegex.ReplaceAll(buf[:n], nil))
+			}
+			if err != nil {
+				_ = r.Close()
+				return
+			}
+		}
+	}()
+	return w
+}
+
+// openRotatingFile method opens the log file in append mode, parent
+// directories are created if not exists.
+func openRotatingFile(name string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(name), permRWXRXRX); err != nil {
+		return nil, err
+	}
+	rf := &rotatingFile{name: name, maxSize: maxSize, maxBackups: maxBackups}
+	return rf, rf.open()
+}
+
+// Write method writes into log file, file is rotated before the write if it
+// exceeds the max size.
+func (rf *rotatingFile) Write(p []byte) (int, error) {
+	rf.Lock()
+	defer rf.Unlock()
+	if rf.maxSize > 0 && rf.size > 0 && rf.size+int64(len(p)) > rf.maxSize {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := rf.f.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+// Close method closes the log file.
+func (rf *rotatingFile) Close() error {
+	rf.Lock()
+	defer rf.Unlock()
+	return rf.f.Close()
+}
+
+func (rf *rotatingFile) open() error {
+	f, err := os.OpenFile(rf.name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, permRWRR)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	rf.f, rf.size = f, info.Size()
+	return nil
+}
+
+func (rf *rotatingFile) rotate() error {
+	if err := rf.f.Close(); err != nil {
+		return err
+	}
+	if rf.maxBackups < 1 {
+		_ = os.Remove(rf.name)
+	} else {
+		_ = os.Remove(fmt.Sprintf("%s.%d", rf.name, rf.maxBackups))
+		for i := rf.maxBackups - 1; i > 0; i-- {
+			_ = os.Rename(fmt.Sprintf("%s.%d", rf.name, i), fmt.Sprintf("%s.%d", rf.name, i+1))
+		}
+		if err := os.Rename(rf.name, rf.name+".1"); err != nil {
+			return err
+		}
+	}
+	return rf.open()
+}
@@ -0,0 +1,203 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/log.v0"
+)
+
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+var (
+	cliJSONLog *jsonLog
+
+	// logLineRegex matches the log line of default pattern
+	// '%time:2006-01-02 15:04:05.000 %level:-5 %message'.
+	logLineRegex = regexp.MustCompile(`^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\.\d{3} (TRACE|DEBUG|INFO|WARN|ERROR|FATAL|PANIC)\s+(.*)$`)
+)
+
+type (
+	// logEvent is the structured log event of '-log-format=json', it's written
+	// as one JSON object per line.
+	logEvent struct {
+		Time    time.Time              `json:"time"`
+		Level   string                 `json:"level"`
+		Stage   string                 `json:"stage,omitempty"`
+		Message string                 `json:"message"`
+		Fields  map[string]interface{} `json:"fields,omitempty"`
+	}
+
+	// jsonLog converts the CLI output lines into log events, level is parsed
+	// from the log line. Lines without level, e.g. '%message' pattern or
+	// command output, are 'info' unless it continues the warning or error.
+	jsonLog struct {
+		sync.Mutex
+		w       io.Writer
+		command string
+		stage   string
+		pipes   []*os.File
+		wg      sync.WaitGroup
+	}
+)
+
+func isJSONLog() bool {
+	return cliJSONLog != nil
+}
+
+// startJSONLog method converts the stdout and stderr of aah CLI tool,
+// including commands executed by it, into JSON log events on stdout if flag
+// '-log-format' or 'log.format' of 'cli.conf' is 'json'.
+func startJSONLog(args []string, cliCfg *config.Config) {
+	format := firstNonEmpty(*logFormatFlag, cliCfg.StringDefault("log.format", logFormatText))
+	switch format {
+	case logFormatText:
+		return
+	case logFormatJSON:
+	default:
+		fatalExitf(exitUsage, "Unsupported log format '%s', supported formats are text and json", format)
+		return
+	}
+
+	jl := &jsonLog{w: os.Stdout, command: commandPath(args)}
+	stdout, err := jl.pipe("stdout")
+	if err != nil {
+		log.Errorf("Unable to start JSON log: %s", err)
+		return
+	}
+	stderr, err := jl.pipe("stderr")
+	if err != nil {
+		log.Errorf("Unable to start JSON log: %s", err)
+		_ = stdout.Close()
+		return
+	}
+
+	cliJSONLog = jl
+	os.Stdout, os.Stderr = stdout, stderr
+	log.SetWriter(os.Stderr)
+}
+
+// stopJSONLog method flushes the log events and writes the command completion
+// event with exit code and duration.
+func stopJSONLog(code int) {
+	jl := cliJSONLog
+	if jl == nil {
+		return
+	}
+	cliJSONLog = nil
+
+	for _, p := range jl.pipes {
+		_ = p.Close()
+	}
+	jl.wg.Wait()
+
+	e := &logEvent{Level: "info", Message: "command completed", Fields: map[string]interface{}{
+		"command":     jl.command,
+		"exit_code":   code,
+		"exit_name":   exitName(code),
+		"duration_ms": durationMs(time.Since(cliStartTime)),
+	}}
+	if code != exitOK {
+		e.Level, e.Message = "error", "command failed"
+	}
+	jl.emit(e)
+}
+
+// setLogStage method sets the current stage of the log events.
+func setLogStage(name string) {
+	if jl := cliJSONLog; jl != nil {
+		jl.Lock()
+		jl.stage = name
+		jl.Unlock()
+	}
+}
+
+// logStageEvent method writes the stage completion event with duration.
+func logStageEvent(name string, d time.Duration) {
+	if jl := cliJSONLog; jl != nil {
+		jl.emit(&logEvent{Level: "info", Stage: name, Message: "stage completed", Fields: map[string]interface{}{
+			"command":     jl.command,
+			"duration_ms": durationMs(d),
+		}})
+	}
+}
+
+// pipe method returns the pipe, written lines are converted into log events
+// of given stream.
+func (jl *jsonLog) pipe(stream string) (*os.File, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	jl.pipes = append(jl.pipes, w)
+
+	jl.wg.Add(1)
+	go func() {
+		defer jl.wg.Done()
+		jl.read(r, stream)
+		_ = r.Close()
+	}()
+	return w, nil
+}
+
+func (jl *jsonLog) read(r io.Reader, stream string) {
+	level := "info"
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(ansiEscapeRegex.ReplaceAllString(scanner.Text(), ""), " \r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var e *logEvent
+		e, level = parseLogLine(line, level)
+		e.Fields = map[string]interface{}{"command": jl.command, "stream": stream}
+		jl.emit(e)
+	}
+}
+
+func (jl *jsonLog) emit(e *logEvent) {
+	jl.Lock()
+	defer jl.Unlock()
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if e.Stage == "" {
+		e.Stage = jl.stage
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	_, _ = jl.w.Write(append(b, '\n'))
+}
+
+// parseLogLine method returns the log event of the line along with level
+// for the next line. Line without level continues the warning or error of
+// previous line e.g. multi-line compile error, otherwise it's 'info'.
+func parseLogLine(line, prevLevel string) (*logEvent, string) {
+	if m := logLineRegex.FindStringSubmatch(line); m != nil {
+		level := strings.ToLower(m[1])
+		return &logEvent{Level: level, Message: m[2]}, level
+	}
+	switch prevLevel {
+	case "warn", "error", "fatal", "panic":
+	default:
+		prevLevel = "info"
+	}
+	return &logEvent{Level: prevLevel, Message: line}, prevLevel
+}
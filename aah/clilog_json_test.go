@@ -0,0 +1,49 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestCLILogJSON(t *testing.T) {
+	e, level := parseLogLine("2017-06-01 10:00:00.000 WARN  Secrets are not loaded", "info")
+	assert.Equal(t, "warn", e.Level)
+	assert.Equal(t, "Secrets are not loaded", e.Message)
+	assert.Equal(t, "warn", level)
+
+	// line without level continues the warning or error
+	e, level = parseLogLine("---> github.com/go-aah/aah", "error")
+	assert.Equal(t, "error", e.Level)
+	assert.Equal(t, "error", level)
+
+	e, level = parseLogLine("Routes are valid", "debug")
+	assert.Equal(t, "info", e.Level)
+	assert.Equal(t, "info", level)
+
+	buf := &bytes.Buffer{}
+	jl := &jsonLog{w: buf, command: "build", stage: "go build"}
+	jl.read(strings.NewReader("2017-06-01 10:00:00.000 ERROR \033[31mBuild failed\033[0m\r\n\n# myapp/app/controllers\n"), "stderr")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 2, len(lines))
+	events := make([]*logEvent, len(lines))
+	for i, l := range lines {
+		events[i] = &logEvent{}
+		assert.Nil(t, json.Unmarshal([]byte(l), events[i]))
+	}
+	assert.Equal(t, "error", events[0].Level)
+	assert.Equal(t, "go build", events[0].Stage)
+	assert.Equal(t, "Build failed", events[0].Message)
+	assert.Equal(t, "stderr", events[0].Fields["stream"])
+	assert.Equal(t, "build", events[0].Fields["command"])
+	assert.Equal(t, "error", events[1].Level)
+	assert.Equal(t, "# myapp/app/controllers", events[1].Message)
+}
@@ -0,0 +1,53 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestCLILogRotatingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-clilog")
+	assert.Nil(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	name := filepath.Join(dir, "logs", "aah.log")
+	rf, err := openRotatingFile(name, 10, 2)
+	assert.Nil(t, err)
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err = rf.Write([]byte(line))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, rf.Close())
+
+	for file, content := range map[string]string{
+		name:        "fourth\n",
+		name + ".1": "third\n",
+		name + ".2": "second\n",
+	} {
+		b, err := ioutil.ReadFile(file)
+		assert.Nil(t, err)
+		assert.Equal(t, content, string(b))
+	}
+	_, err = os.Stat(name + ".3")
+	assert.True(t, os.IsNotExist(err))
+
+	// existing size is considered on reopen
+	rf, err = openRotatingFile(name, 10, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(7), rf.size)
+	assert.Nil(t, rf.Close())
+
+	assert.Equal(t, "aah framework v0.10", string(ansiEscapeRegex.ReplaceAll([]byte("\033[1;32maah framework v0.10\033[0m"), nil)))
+	assert.False(t, strings.Contains(string(ansiEscapeRegex.ReplaceAll([]byte("\r\033[K| Packaging"), nil)), "\033"))
+}
@@ -0,0 +1,119 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"time"
+
+	"aahframework.org/log.v0"
+)
+
+const cliProfileDir = ".aah/profile"
+
+type cliStage struct {
+	Name     string
+	Duration time.Duration
+}
+
+var (
+	cliStages    []*cliStage
+	cliStartTime = time.Now()
+	cliCPUFile   *os.File
+)
+
+// startStage method records the execution time of CLI stage, call the
+// returned func once the stage is completed.
+//
+//	defer startStage("compile")()
+func startStage(name string) func() {
+	stage := &cliStage{Name: name}
+	cliStages = append(cliStages, stage)
+	setLogStage(name)
+	start := time.Now()
+	return func() {
+		stage.Duration = time.Since(start)
+		logStageEvent(name, stage.Duration)
+		setLogStage("")
+	}
+}
+
+func isCLIProfile() bool {
+	return profileCLIFlag != nil && *profileCLIFlag
+}
+
+// startCLIProfile method starts the CPU profiling of aah CLI tool itself
+// if flag '-profile-cli' is supplied.
+func startCLIProfile() {
+	if !isCLIProfile() {
+		return
+	}
+
+	if err := os.MkdirAll(cliProfileDir, permRWXRXRX); err != nil {
+		log.Errorf("Unable to create CLI profile directory: %s", err)
+		return
+	}
+
+	var err error
+	cliCPUFile, err = os.Create(filepath.Join(cliProfileDir, "cpu.pprof"))
+	if err != nil {
+		log.Errorf("Unable to create CPU profile: %s", err)
+		return
+	}
+
+	if err = pprof.StartCPUProfile(cliCPUFile); err != nil {
+		log.Errorf("Unable to start CPU profile: %s", err)
+		_ = cliCPUFile.Close()
+		cliCPUFile = nil
+	}
+}
+
+// stopCLIProfile method stops the CPU profiling, writes the heap profile
+// and prints the stage timing breakdown.
+func stopCLIProfile() {
+	if !isCLIProfile() {
+		return
+	}
+
+	if cliCPUFile != nil {
+		pprof.StopCPUProfile()
+		_ = cliCPUFile.Close()
+		log.Infof("CPU profile: %s", cliCPUFile.Name())
+		cliCPUFile = nil
+	}
+
+	heapFile := filepath.Join(cliProfileDir, "heap.pprof")
+	if f, err := os.Create(heapFile); err == nil {
+		runtime.GC()
+		if err = pprof.WriteHeapProfile(f); err != nil {
+			log.Errorf("Unable to write heap profile: %s", err)
+		}
+		_ = f.Close()
+		log.Infof("Heap profile: %s", heapFile)
+	}
+
+	printStages(os.Stdout)
+}
+
+func printStages(w io.Writer) {
+	total := time.Since(cliStartTime)
+	fmt.Fprintf(w, "\nStage timing:\n")
+	for _, s := range cliStages {
+		fmt.Fprintf(w, "\t%-24s %12s %6.1f%%\n", s.Name, s.Duration, percentOf(s.Duration, total))
+	}
+	fmt.Fprintf(w, "\t%-24s %12s\n\n", "total", total)
+}
+
+func percentOf(d, total time.Duration) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(d) * 100 / float64(total)
+}
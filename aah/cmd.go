@@ -5,11 +5,15 @@
 package main
 
 import (
+	"bytes"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"sort"
 	"strings"
 
+	"aahframework.org/essentials.v0"
 	"aahframework.org/log.v0"
 )
 
@@ -18,10 +22,11 @@ type (
 	// Reference: https://github.com/golang/go/blob/master/src/cmd/go/main.go
 	command struct {
 		// Run runs the command.
-		// The args are the arguments after the command name.
+		// The args are the positional arguments after the command flags.
 		Run func(args []string)
 
-		// Flags sub commands flag arguments
+		// Flags of the command, parsed before `Run` is called. Flags with same
+		// usage text are displayed together as long and short flag.
 		Flags *flag.FlagSet
 
 		// Name of the command
@@ -30,18 +35,36 @@ type (
 		// UsageLine is the one-line usage message.
 		UsageLine string
 
-		// Total no of arguments (mandatory & optionals)
-		ArgsCount int
+		// MaxArgs is the maximum no. of positional arguments after the flags.
+		MaxArgs int
 
 		// Short is the short description shown in the 'aah help' output.
 		Short string
 
 		// Long is the long message shown in the 'aah help <this-command>' output.
 		Long string
+
+		// Hooks are executed in the order before `Run`, e.g. validate GOPATH,
+		// load aah project, etc.
+		Hooks []hook
+
+		// Subcommands of the command, e.g. 'aah docs gen'. The order here is the
+		// order in which subcommands are printed by 'aah help <this-command>'.
+		Subcommands commands
 	}
 
 	// Commands groups set of commands together and provides handy methods around it
 	commands []*command
+
+	// flagGroup holds the long and short flag names of same flag usage.
+	flagGroup struct {
+		Names   []string
+		Type    string
+		Usage   string
+		Default string
+	}
+
+	byLength []string
 )
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -50,9 +73,100 @@ type (
 
 // Usage displays the usage line and long description then exits
 func (c *command) Usage() {
-	fmt.Fprintf(os.Stderr, "Usage: %v\n\n", c.UsageLine)
+	fmt.Fprintf(os.Stderr, "%s\n\n", msg("usage.line", c.UsageLine))
 	fmt.Fprintf(os.Stderr, "%v\n\n", strings.TrimSpace(c.Long))
-	exit(2)
+	if flagsUsage := c.FlagsUsage(); !ess.IsStrEmpty(flagsUsage) {
+		fmt.Fprintf(os.Stderr, "%s\n%s\n", msg("usage.flags"), flagsUsage)
+	}
+	if len(c.Subcommands) > 0 {
+		fmt.Fprintf(os.Stderr, "%s\n", msg("usage.subcommands"))
+		for _, sub := range c.Subcommands {
+			fmt.Fprintf(os.Stderr, "\t%-12s %s\n", sub.Name, sub.ShortText())
+		}
+		fmt.Fprintln(os.Stderr)
+	}
+	exit(exitUsage)
+}
+
+// FlagsUsage method returns the flags usage text generated from the
+// registered command flags.
+func (c *command) FlagsUsage() string {
+	if c.Flags == nil {
+		return ""
+	}
+
+	var groups []*flagGroup
+	byUsage := map[string]*flagGroup{}
+	c.Flags.VisitAll(func(f *flag.Flag) {
+		typeName, usage := flag.UnquoteUsage(f)
+		if g, found := byUsage[f.Usage]; found {
+			g.Names = append(g.Names, "-"+f.Name)
+			return
+		}
+		g := &flagGroup{Names: []string{"-" + f.Name}, Type: typeName, Usage: usage, Default: f.DefValue}
+		byUsage[f.Usage] = g
+		groups = append(groups, g)
+	})
+
+	buf := &bytes.Buffer{}
+	for _, g := range groups {
+		sort.Sort(byLength(g.Names))
+		fmt.Fprintf(buf, "    %s\n", strings.TrimSpace(strings.Join(g.Names, " | ")+" "+g.Type))
+		fmt.Fprintf(buf, "        %s", g.Usage)
+		if !ess.IsStrEmpty(g.Default) && g.Default != "false" {
+			fmt.Fprintf(buf, " %s", msg("usage.default", g.Default))
+		}
+		fmt.Fprintln(buf)
+	}
+	return buf.String()
+}
+
+// Exec method parses the command flags, validates the arguments count and
+// runs the command. If the first argument is a subcommand name then execution
+// is delegated to it. Command without `Run` displays its usage.
+func (c *command) Exec(args []string) {
+	if len(args) > 0 && len(c.Subcommands) > 0 {
+		if sub, err := c.Subcommands.Find(args[0]); err == nil {
+			sub.Exec(args[1:])
+			return
+		}
+	}
+
+	if c.Run == nil {
+		c.Usage()
+		return
+	}
+
+	if c.Flags == nil {
+		c.Flags = flag.NewFlagSet(c.Name, flag.ContinueOnError)
+	}
+
+	// flag errors and usage are reported by aah CLI
+	c.Flags.SetOutput(ioutil.Discard)
+	c.Flags.Usage = func() {}
+	if err := c.Flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			c.Usage()
+			return
+		}
+		fatalExitf(exitUsage, "%s\n\n", msg("errors.bad_flag", err, c.Name))
+		return
+	}
+
+	// Validate command positional arguments count
+	if c.Flags.NArg() > c.MaxArgs {
+		fatalExitf(exitUsage, "%s\n\n", msg("errors.too_many_args", c.Name))
+		return
+	}
+
+	for _, h := range c.Hooks {
+		if err := h(c); err != nil {
+			fatalErr(err)
+			return
+		}
+	}
+
+	c.Run(c.Flags.Args())
 }
 
 // Find finds the command from command name otherwise returns error
@@ -65,22 +179,36 @@ func (c *commands) Find(name string) (*command, error) {
 	return nil, fmt.Errorf("command %v not found", name)
 }
 
+// Sort interface for sorting flag names, short flag name comes first
+func (s byLength) Len() int           { return len(s) }
+func (s byLength) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byLength) Less(i, j int) bool { return len(s[i]) < len(s[j]) }
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Helper methods for commands
 //___________________________________
 
 func displayUsage() {
-	fmt.Fprintf(os.Stderr, "Usage: aah command [arguments]\n\n")
-	fmt.Fprintf(os.Stderr, "Available commands:\n")
+	fmt.Fprintf(os.Stderr, "%s\n\n", msg("usage.aah"))
+	fmt.Fprintf(os.Stderr, "%s\n", msg("usage.commands"))
 	for _, cmd := range subCmds {
-		fmt.Fprintf(os.Stderr, "\t%-12s %s\n", cmd.Name, cmd.Short)
+		fmt.Fprintf(os.Stderr, "\t%-12s %s\n", cmd.Name, cmd.ShortText())
+	}
+	fmt.Fprintf(os.Stderr, "\n%s\n\n", msg("usage.help_command"))
+
+	globalCmd := &command{Flags: flag.CommandLine}
+	fmt.Fprintf(os.Stderr, "%s\n%s\n", msg("usage.global_flags"), globalCmd.FlagsUsage())
+
+	fmt.Fprintf(os.Stderr, "%s\n", msg("usage.topics"))
+	for _, topic := range helpTopics {
+		fmt.Fprintf(os.Stderr, "\t%-12s %s\n", topic.Name, topic.ShortText())
 	}
-	fmt.Fprintf(os.Stderr, "\nUse \"aah help [command]\" for more information about a command.\n\n")
+	fmt.Fprintf(os.Stderr, "\n%s\n\n", msg("usage.help_topic"))
 
-	exit(2)
+	exit(exitUsage)
 }
 
 func commandNotFound(name string) {
-	log.Errorf("Unknown command '%v', Run 'aah help'.\n\n", name)
-	exit(2)
+	log.Errorf("%s\n\n", msg("errors.unknown_command", name))
+	exit(exitUsage)
 }
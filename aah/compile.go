@@ -8,7 +8,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"net"
 	"path"
 	"path/filepath"
 	"strings"
@@ -24,9 +24,22 @@ import (
 // Unexported methods
 //___________________________________
 
+// compileOptions are the command specific options of application compile.
+type compileOptions struct {
+	// Pack is true when application is compiled for packaging, 'aah build'.
+	Pack bool
+
+	// BuildArgs are additional 'go build' arguments, e.g. '-gcflags'.
+	BuildArgs []string
+
+	// Output is the binary file path instead of '<app-base>/build/bin', build
+	// directory is not cleaned up. Binary name of the application is same.
+	Output string
+}
+
 // compileApp method calls Go ast parser, generates main.go and builds aah
 // application binary at Go bin directory
-func compileApp(buildCfg *config.Config, appPack bool) (string, error) {
+func compileApp(buildCfg *config.Config, opts *compileOptions) (string, error) {
 	// app variables
 	appBaseDir := aah.AppBaseDir()
 	appImportPath := aah.AppImportPath()
@@ -40,21 +53,33 @@ func compileApp(buildCfg *config.Config, appPack bool) (string, error) {
 	// excludes for Go AST processing
 	excludes, _ := buildCfg.StringList("build.ast_excludes")
 
+	// validate routes configuration before processing
+	endStage := startStage("validate routes")
+	routesErr := checkRoutesConf(appBaseDir)
+	endStage()
+	if routesErr != nil {
+		return "", routesErr
+	}
+
 	// get all configured Controllers with action info
 	registeredActions := aah.AppRouter().RegisteredActions()
 
 	// Go AST processing for Controllers
+	endStage = startStage("parse controllers")
 	prg, errs := loadProgram(appControllersPath, ess.Excludes(excludes), registeredActions)
+	endStage()
 	if len(errs) > 0 {
 		errMsgs := []string{}
 		for _, e := range errs {
 			errMsgs = append(errMsgs, e.Error())
 		}
-		return "", errors.New(strings.Join(errMsgs, "\n"))
+		return "", newExitErr(exitParseError, errors.New(strings.Join(errMsgs, "\n")))
 	}
 
 	// call the process
+	endStage = startStage("process controllers")
 	prg.Process()
+	endStage()
 
 	// Print router configuration missing/error details
 	missingActions := []string{}
@@ -93,8 +118,14 @@ func compileApp(buildCfg *config.Config, appPack bool) (string, error) {
 		buildArgs = append(buildArgs, "-tags", tags)
 	}
 
+	buildArgs = append(buildArgs, opts.BuildArgs...)
+
 	appBinary := appBinaryFile(buildCfg, appBuildDir)
 	appBinaryName := filepath.Base(appBinary)
+	cleanPath := appBuildDir
+	if !ess.IsStrEmpty(opts.Output) {
+		appBinary, cleanPath = opts.Output, opts.Output
+	}
 	buildArgs = append(buildArgs, "-o", appBinary)
 
 	// main.go location e.g. path/to/import/app
@@ -103,9 +134,47 @@ func compileApp(buildCfg *config.Config, appPack bool) (string, error) {
 	// clean previous main.go and binary file up before we start the build
 	appMainGoFile := filepath.Join(appCodeDir, "aah.go")
 	log.Debugf("Cleaning %s", appMainGoFile)
-	log.Debugf("Cleaning build directory %s", appBuildDir)
-	ess.DeleteFiles(appMainGoFile, appBuildDir)
+	log.Debugf("Cleaning %s", cleanPath)
+	deleteFiles(append(generatedAppFiles(appCodeDir), cleanPath)...)
+
+	embedMigrations := buildCfg.BoolDefault("build.embed_migrations", false)
+	if embedMigrations {
+		endStage = startStage("embed migrations")
+		em, err := loadEmbeddedMigrations(buildCfg, appBaseDir, appImportPath)
+		if err != nil {
+			endStage()
+			return "", err
+		}
+		generateSource(appCodeDir, aahMigrationsFile, aahMigrationsTemplate, map[string]interface{}{
+			"AahVersion": aah.Version,
+			"DB":         em.DB,
+			"Import":     em.Import,
+			"Migrations": em.Migrations,
+		})
+		endStage()
+		log.Infof("Embedded %d migration(s) of '%s'", len(em.Migrations), migrationsDir)
+	}
 
+	profiling := buildCfg.BoolDefault("build.profiling", false)
+	profilingAddr := buildCfg.StringDefault("build.profiling_address", "localhost:6060")
+	if _, _, err := net.SplitHostPort(profilingAddr); profiling && err != nil {
+		return "", newExitErr(exitConfigError, fmt.Errorf("'build.profiling_address' value '%s' is invalid: %s", profilingAddr, err))
+	}
+
+	metrics, err := loadMetricsConfig(buildCfg, profilingAddr)
+	if err != nil {
+		return "", err
+	}
+	if metrics.Enable {
+		generateSource(appCodeDir, aahMetricsFile, aahMetricsTemplate, map[string]interface{}{
+			"AahVersion":   aah.Version,
+			"AppVersion":   appVersion,
+			"AppBuildDate": appBuildDate,
+			"MetricsPath":  metrics.Path,
+		})
+	}
+
+	endStage = startStage("generate main")
 	generateSource(appCodeDir, "aah.go", aahMainTemplate, map[string]interface{}{
 		"AahVersion":     aah.Version,
 		"AppImportPath":  appImportPath,
@@ -114,17 +183,36 @@ func compileApp(buildCfg *config.Config, appPack bool) (string, error) {
 		"AppBinaryName":  appBinaryName,
 		"AppControllers": appControllers,
 		"AppImportPaths": appImportPaths,
-		"AppIsPackaged":  appPack,
+		"AppIsPackaged":  opts.Pack,
+		"AppMigrations":  embedMigrations,
+		"AppProfiling":   profiling,
+		"ProfilingAddr":  profilingAddr,
+		"AppMetrics":     metrics.Enable,
+		"MetricsAddr":    metrics.Address,
 	})
+	endStage()
 
 	// getting project dependencies if not exists in $GOPATH
-	if err := checkAndGetAppDeps(appImportPath, buildCfg); err != nil {
-		return "", fmt.Errorf("unable to get application dependencies: %s", err)
+	endStage = startStage("check dependencies")
+	err = checkAndGetAppDeps(appImportPath, buildCfg)
+	endStage()
+	if err != nil {
+		return "", newExitErr(exitDepsError, fmt.Errorf("unable to get application dependencies: %s", err))
 	}
 
 	// execute aah applictaion build
-	if _, err := execCmd(gocmd, buildArgs, false); err != nil {
-		return "", err
+	endStage = startStage("go build")
+	_, err = execCmd(gocmd, buildArgs, false)
+	endStage()
+	if err != nil {
+		return "", newExitErr(exitCompileError, err)
+	}
+
+	// binary size budget is for the packaged binary
+	if opts.Pack {
+		if err = checkBinarySize(buildCfg, appBinary); err != nil {
+			return "", err
+		}
 	}
 
 	log.Infof("Compile successful for '%s' [%s]", appName, appImportPath)
@@ -132,9 +220,19 @@ func compileApp(buildCfg *config.Config, appPack bool) (string, error) {
 	return appBinary, nil
 }
 
+// generatedAppFiles method returns the Go files generated into the app
+// directory by the compile, main file and the ones enabled via aah.project.
+func generatedAppFiles(appCodeDir string) []string {
+	return []string{
+		filepath.Join(appCodeDir, "aah.go"),
+		filepath.Join(appCodeDir, aahMigrationsFile),
+		filepath.Join(appCodeDir, aahMetricsFile),
+	}
+}
+
 func generateSource(dir, filename, templateSource string, templateArgs map[string]interface{}) {
 	if !ess.IsFileExists(dir) {
-		if err := ess.MkDirAll(dir, 0644); err != nil {
+		if err := mkDirAll(dir, 0644); err != nil {
 			fatal(err)
 		}
 	}
@@ -145,7 +243,12 @@ func generateSource(dir, filename, templateSource string, templateArgs map[strin
 		fatal(err)
 	}
 
-	if err := ioutil.WriteFile(file, buf.Bytes(), permRWXRXRX); err != nil {
+	content, err := addHeader(file, buf.Bytes())
+	if err != nil {
+		fatalErr(err)
+	}
+
+	if err := writeFile(file, content, permRWXRXRX); err != nil {
 		fatalf("aah '%s' file write error: %s", filename, err)
 	}
 }
@@ -159,7 +262,7 @@ func checkAndGetAppDeps(appImportPath string, cfg *config.Config) error {
 	importPath := path.Join(appImportPath, "app", "...")
 	args := []string{"list", "-f", "{{.Imports}}", importPath}
 
-	output, err := execCmd(gocmd, args, false)
+	output, err := execReadCmd(gocmd, args)
 	if err != nil {
 		return err
 	}
@@ -183,14 +286,19 @@ func checkAndGetAppDeps(appImportPath string, cfg *config.Config) error {
 
 		if cfg.BoolDefault("build.dep_get", false) && len(notExistsPkgs) > 0 {
 			log.Info("Getting application dependencies ...")
+			p := newProgress("Getting dependencies", len(notExistsPkgs))
 			for _, pkg := range notExistsPkgs {
+				p.Start(pkg)
 				args := []string{"get", pkg}
 				if _, err := execCmd(gocmd, args, false); err != nil {
+					p.Finish()
 					return err
 				}
+				p.Done()
 			}
+			p.Finish()
 		} else if len(notExistsPkgs) > 0 {
-			fatal("Below application dependencies are not exists, "+
+			fatalExit(exitDepsError, "Below application dependencies are not exists, "+
 				"enable 'build.dep_get=true' in 'aah.project' for auto fetch\n---> ",
 				strings.Join(notExistsPkgs, "\n---> "))
 		}
@@ -211,9 +319,13 @@ const aahMainTemplate = `// GENERATED CODE - DO NOT EDIT
 
 package main
 
-import (
+import ({{ if .AppProfiling }}
+	"expvar"{{ end }}
 	"flag"
-	"fmt"
+	"fmt"{{ if .AppProfiling }}
+	"net/http"
+	"net/http/pprof"{{ end }}
+	"os"
 	"reflect"
 
 	"aahframework.org/aah.v0"
@@ -229,7 +341,19 @@ var (
 	configPath = flag.String("config", "", "Absolute path of external config file.")
 	profile    = flag.String("profile", "", "Environment profile name to activate. e.g: dev, qa, prod.")
 	_          = reflect.Invalid
-)
+){{ if .AppMigrations }}
+
+// migrate flag is added by 'build.embed_migrations'
+var migrate = flag.Bool("migrate", false, "Apply pending database migrations on startup.")
+{{- end }}{{ if .AppProfiling }}
+
+// profilingAddr flag is added by 'build.profiling'
+var profilingAddr = flag.String("profiling-addr", "{{ .ProfilingAddr }}", "Address of the pprof and expvar admin server, empty disables it.")
+{{- end }}{{ if .AppMetrics }}
+
+// metricsAddr flag is added by 'build.metrics'
+var metricsAddr = flag.String("metrics-addr", "{{ .MetricsAddr }}", "Address of the Prometheus metrics server, empty disables it.")
+{{- end }}
 
 func mergeExternalConfig(e *aah.Event) {
 	externalConfig, err := config.LoadFile(*configPath)
@@ -247,6 +371,33 @@ func setAppEnvProfile(e *aah.Event) {
 	aah.AppConfig().SetString("env.active", *profile)
 }
 
+func setLiveReloadURL(e *aah.Event) {
+	aah.AppConfig().SetString("livereload.url", os.Getenv("AAH_LIVERELOAD_URL"))
+}
+
+func setServerPort(e *aah.Event) {
+	aah.AppConfig().SetString("server.port", os.Getenv("AAH_SERVER_PORT"))
+}
+{{ if .AppProfiling }}
+// startProfilingServer serves pprof and expvar handlers on the admin
+// address, apart from the application server.
+func startProfilingServer(addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	log.Infof("Profiling server listening on http://%s/debug/pprof/", addr)
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Errorf("Profiling server: %s", err)
+		}
+	}()
+}
+{{ end }}
 func main() {
 	log.Infof("aah framework v%s, requires ≥ go1.8", aah.Version)
 	flag.Parse()
@@ -277,8 +428,35 @@ func main() {
 		aah.OnInit(setAppEnvProfile)
 	}
 
-	aah.Init("{{ .AppImportPath }}")
+	// Live reload script URL, supplied by 'aah run' in 'dev' profile
+	if !ess.IsStrEmpty(os.Getenv("AAH_LIVERELOAD_URL")) {
+		aah.OnInit(setLiveReloadURL)
+	}
+
+	// Server port override, supplied by 'aah run' for proxy and multiple instances
+	if !ess.IsStrEmpty(os.Getenv("AAH_SERVER_PORT")) {
+		aah.OnInit(setServerPort)
+	}
 
+	aah.Init("{{ .AppImportPath }}")
+{{ if .AppMigrations }}
+	// Apply pending database migrations embedded in the binary
+	if *migrate {
+		if err := migrateDB(); err != nil {
+			log.Fatalf("Database migration failed: %s", err)
+		}
+	}
+{{ end }}{{ if .AppProfiling }}
+	// pprof and expvar admin server, enabled via 'build.profiling'
+	if !ess.IsStrEmpty(*profilingAddr) {
+		startProfilingServer(*profilingAddr)
+	}
+{{ end }}{{ if .AppMetrics }}
+	// Prometheus metrics server, enabled via 'build.metrics'
+	if !ess.IsStrEmpty(*metricsAddr) {
+		startMetricsServer(*metricsAddr)
+	}
+{{ end }}
 	// Adding all the controllers which refers 'aah.Context' directly
 	// or indirectly from app/controllers/** {{ range $i, $c := .AppControllers }}
 	aah.AddController(
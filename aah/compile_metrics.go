@@ -0,0 +1,194 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"aahframework.org/config.v0"
+)
+
+// aahMetricsFile is the generated Go file of the metrics endpoint, it's
+// along with 'app/aah.go'.
+const aahMetricsFile = "aah_metrics.go"
+
+// metricsConfig is the 'build.metrics' config of aah.project.
+type metricsConfig struct {
+	Enable  bool
+	Address string
+	Path    string
+}
+
+// loadMetricsConfig method reads and validates the metrics endpoint config,
+// address must not be the same as profiling address since each one has its
+// own listener.
+func loadMetricsConfig(buildCfg *config.Config, profilingAddr string) (*metricsConfig, error) {
+	mc := &metricsConfig{
+		Enable:  buildCfg.BoolDefault("build.metrics", false),
+		Address: buildCfg.StringDefault("build.metrics_address", ":9464"),
+		Path:    buildCfg.StringDefault("build.metrics_path", "/metrics"),
+	}
+	if !mc.Enable {
+		return mc, nil
+	}
+
+	if _, _, err := net.SplitHostPort(mc.Address); err != nil {
+		return nil, newExitErr(exitConfigError, fmt.Errorf("'build.metrics_address' value '%s' is invalid: %s", mc.Address, err))
+	}
+	if !strings.HasPrefix(mc.Path, "/") {
+		return nil, newExitErr(exitConfigError, fmt.Errorf("'build.metrics_path' value '%s' must start with '/'", mc.Path))
+	}
+	if buildCfg.BoolDefault("build.profiling", false) && mc.Address == profilingAddr {
+		return nil, newExitErr(exitConfigError, fmt.Errorf("'build.metrics_address' and 'build.profiling_address' must differ, both are '%s'", mc.Address))
+	}
+	return mc, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Generate Templates
+//___________________________________
+
+const aahMetricsTemplate = `// GENERATED CODE - DO NOT EDIT
+//
+// aah framework v{{.AahVersion}} - https://aahframework.org
+// FILE: aah_metrics.go
+// DESC: Prometheus metrics endpoint enabled via 'build.metrics'
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/log.v0"
+)
+
+const metricsStartKey = "_aahMetricsStart"
+
+// metricsBuckets are the upper bounds of request duration histogram, in seconds.
+var metricsBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// requestMetric is the request count and duration histogram of method and
+// status code.
+type requestMetric struct {
+	method  string
+	code    int
+	count   uint64
+	sum     float64
+	buckets []uint64
+}
+
+var requestMetrics = struct {
+	sync.Mutex
+	byKey map[string]*requestMetric
+}{byKey: map[string]*requestMetric{}}
+
+func metricsOnRequest(e *aah.Event) {
+	ctx := e.Data.(*aah.Context)
+	ctx.Set(metricsStartKey, time.Now())
+}
+
+func metricsOnAfterReply(e *aah.Event) {
+	ctx := e.Data.(*aah.Context)
+	start, ok := ctx.Get(metricsStartKey).(time.Time)
+	if !ok {
+		return
+	}
+	observeRequest(ctx.Req.Method, ctx.Res.Status(), time.Since(start).Seconds())
+}
+
+func observeRequest(method string, code int, seconds float64) {
+	requestMetrics.Lock()
+	defer requestMetrics.Unlock()
+
+	key := method + " " + strconv.Itoa(code)
+	m, found := requestMetrics.byKey[key]
+	if !found {
+		m = &requestMetric{method: method, code: code, buckets: make([]uint64, len(metricsBuckets))}
+		requestMetrics.byKey[key] = m
+	}
+	m.count++
+	m.sum += seconds
+	for i, le := range metricsBuckets {
+		if seconds <= le {
+			m.buckets[i]++
+		}
+	}
+}
+
+// writeMetrics writes the metrics in Prometheus text exposition format.
+func writeMetrics(w http.ResponseWriter, r *http.Request) {
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf, "# HELP aah_build_info Application build information.")
+	fmt.Fprintln(buf, "# TYPE aah_build_info gauge")
+	fmt.Fprintf(buf, "aah_build_info{version=%q,build_date=%q,aah_version=%q,goversion=%q} 1\n",
+		{{ printf "%q" .AppVersion }}, {{ printf "%q" .AppBuildDate }}, aah.Version, runtime.Version())
+
+	requestMetrics.Lock()
+	metrics := make([]requestMetric, 0, len(requestMetrics.byKey))
+	for _, m := range requestMetrics.byKey {
+		c := *m
+		c.buckets = append([]uint64{}, m.buckets...)
+		metrics = append(metrics, c)
+	}
+	requestMetrics.Unlock()
+	sort.Slice(metrics, func(i, j int) bool {
+		if metrics[i].method == metrics[j].method {
+			return metrics[i].code < metrics[j].code
+		}
+		return metrics[i].method < metrics[j].method
+	})
+
+	fmt.Fprintln(buf, "# HELP aah_http_requests_total Total number of HTTP requests by method and status code.")
+	fmt.Fprintln(buf, "# TYPE aah_http_requests_total counter")
+	for _, m := range metrics {
+		fmt.Fprintf(buf, "aah_http_requests_total{method=%q,code=\"%d\"} %d\n", m.method, m.code, m.count)
+	}
+
+	fmt.Fprintln(buf, "# HELP aah_http_request_duration_seconds HTTP request duration in seconds by method and status code.")
+	fmt.Fprintln(buf, "# TYPE aah_http_request_duration_seconds histogram")
+	for _, m := range metrics {
+		labels := fmt.Sprintf("method=%q,code=\"%d\"", m.method, m.code)
+		for i, le := range metricsBuckets {
+			fmt.Fprintf(buf, "aah_http_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n",
+				labels, strconv.FormatFloat(le, 'g', -1, 64), m.buckets[i])
+		}
+		fmt.Fprintf(buf, "aah_http_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, m.count)
+		fmt.Fprintf(buf, "aah_http_request_duration_seconds_sum{%s} %s\n", labels, strconv.FormatFloat(m.sum, 'g', -1, 64))
+		fmt.Fprintf(buf, "aah_http_request_duration_seconds_count{%s} %d\n", labels, m.count)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(buf.Bytes())
+}
+
+// startMetricsServer records the request metrics via server extension
+// points and serves them on the metrics address, apart from the application
+// server.
+func startMetricsServer(addr string) {
+	aah.OnRequest(metricsOnRequest)
+	aah.OnAfterReply(metricsOnAfterReply)
+
+	path := {{ printf "%q" .MetricsPath }}
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, writeMetrics)
+
+	log.Infof("Metrics server listening on http://%s%s", addr, path)
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Errorf("Metrics server: %s", err)
+		}
+	}()
+}
+`
@@ -0,0 +1,72 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestCompileMainTemplate(t *testing.T) {
+	data := map[string]interface{}{
+		"AahVersion":     "0.10",
+		"AppImportPath":  "github.com/user/app",
+		"AppVersion":     "1.0.0",
+		"AppBuildDate":   "2017-06-01T15:04:05Z",
+		"AppBinaryName":  "app",
+		"AppImportPaths": map[string]string{},
+		"AppIsPackaged":  true,
+		"ProfilingAddr":  "localhost:6060",
+	}
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, renderTmpl(buf, aahMainTemplate, data))
+	_, err := parser.ParseFile(token.NewFileSet(), "aah.go", buf.String(), 0)
+	assert.Nil(t, err)
+	assert.False(t, strings.Contains(buf.String(), "net/http/pprof"))
+	assert.False(t, strings.Contains(buf.String(), "migrateDB()"))
+
+	data["AppProfiling"] = true
+	data["AppMigrations"] = true
+	buf.Reset()
+	assert.Nil(t, renderTmpl(buf, aahMainTemplate, data))
+	out := buf.String()
+	_, err = parser.ParseFile(token.NewFileSet(), "aah.go", out, 0)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(out, "\t\"net/http/pprof\"\n"))
+	assert.True(t, strings.Contains(out, `flag.String("profiling-addr", "localhost:6060",`))
+	assert.True(t, strings.Contains(out, "\tif *migrate {\n"))
+	assert.False(t, strings.Contains(out, "startMetricsServer("))
+
+	data["AppMetrics"] = true
+	data["MetricsAddr"] = ":9464"
+	buf.Reset()
+	assert.Nil(t, renderTmpl(buf, aahMainTemplate, data))
+	out = buf.String()
+	_, err = parser.ParseFile(token.NewFileSet(), "aah.go", out, 0)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(out, `flag.String("metrics-addr", ":9464",`))
+	assert.True(t, strings.Contains(out, "\t\tstartMetricsServer(*metricsAddr)\n"))
+}
+
+func TestCompileMetricsTemplate(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.Nil(t, renderTmpl(buf, aahMetricsTemplate, map[string]interface{}{
+		"AahVersion":   "0.10",
+		"AppVersion":   "1.0.0",
+		"AppBuildDate": "2017-06-01T15:04:05Z",
+		"MetricsPath":  "/metrics",
+	}))
+	out := buf.String()
+	_, err := parser.ParseFile(token.NewFileSet(), "aah_metrics.go", out, 0)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(out, `"1.0.0", "2017-06-01T15:04:05Z", aah.Version, runtime.Version())`))
+	assert.True(t, strings.Contains(out, `path := "/metrics"`))
+}
@@ -0,0 +1,426 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// confMaxIncludeDepth guards the recursive includes.
+const confMaxIncludeDepth = 10
+
+// Kinds of config values, schema kinds are combinations of these.
+const (
+	confString confKind = 1 << iota
+	confInt
+	confFloat
+	confBool
+	confList
+	confSection
+	confNull
+	confRef
+
+	// confDuration is the schema kind of string value parsed via
+	// 'time.ParseDuration'.
+	confDuration
+)
+
+var configCmd = &command{
+	Name:      "config",
+	UsageLine: "aah config <subcommand> [arguments]",
+	Short:     "aah project and application config tools",
+	Long: `
+Tools for 'aah.project' and application config files 'config/*.conf'.
+
+Example:
+    aah config lint
+    aah config show -profile=prod
+    aah config letsencrypt -domains=example.com
+`,
+}
+
+type (
+	// confKind is the kind of config value.
+	confKind int
+
+	// confEntry is the section or value of the config file with its position,
+	// path is the dotted key path from the root of config. Offset and End are
+	// the byte offsets of the key and the end of value or section in the file.
+	confEntry struct {
+		Path   string
+		Kind   confKind
+		Value  string
+		Items  []*confEntry
+		File   string
+		Line   int
+		Col    int
+		Offset int
+		End    int
+	}
+
+	// confError is the config error with file position.
+	confError struct {
+		File string
+		Line int
+		Col  int
+		Msg  string
+	}
+
+	// confParser parses the aah config format preserving the positions,
+	// which 'aahframework.org/config.v0' doesn't provide. Included files are
+	// parsed into the section of the 'include' directive.
+	confParser struct {
+		file    string
+		src     []byte
+		pos     int
+		line    int
+		col     int
+		depth   int
+		entries []*confEntry
+	}
+)
+
+// parseConfFile method parses the config file and its includes, entries
+// are in the order they are defined.
+func parseConfFile(file string) ([]*confEntry, error) {
+	p := &confParser{}
+	if err := p.parseFile(file, "", 0); err != nil {
+		return p.entries, err
+	}
+	return p.entries, nil
+}
+
+// parseConfString method parses the config content, includes are resolved
+// relative to the directory of given file name.
+func parseConfString(file, content string) ([]*confEntry, error) {
+	p := &confParser{file: file, src: []byte(content), line: 1, col: 1}
+	err := p.parseBody("", 0, 0)
+	return p.entries, err
+}
+
+// String method returns the kind names, e.g. 'int or string'.
+func (k confKind) String() string {
+	names := []string{"string", "int", "float", "bool", "list", "section", "null", "reference", "duration"}
+	var kinds []string
+	for i, name := range names {
+		if k&(1<<uint(i)) != 0 {
+			kinds = append(kinds, name)
+		}
+	}
+	return strings.Join(kinds, " or ")
+}
+
+func (e *confError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Col, e.Msg)
+}
+
+// Pos method returns the entry position 'file:line:col'.
+func (e *confEntry) Pos() string {
+	return fmt.Sprintf("%s:%d:%d", e.File, e.Line, e.Col)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func (p *confParser) parseFile(file, prefix string, depth int) error {
+	src, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	saved := *p
+	p.file, p.src, p.pos, p.line, p.col, p.depth = file, src, 0, 1, 1, depth
+	err = p.parseBody(prefix, 0, 0)
+	entries := p.entries
+	*p = saved
+	p.entries = entries
+	return err
+}
+
+// parseBody method parses the key values until EOF or '}' of the section
+// started at given line and column.
+func (p *confParser) parseBody(prefix string, line, col int) error {
+	for {
+		p.skip(true)
+		if p.eof() {
+			if line > 0 {
+				return p.errorAt(line, col, "section is not closed, missing '}'")
+			}
+			return nil
+		}
+
+		if p.peek() == '}' {
+			if line == 0 {
+				return p.errorf("unexpected '}'")
+			}
+			p.next()
+			return nil
+		}
+
+		kline, kcol, kpos := p.line, p.col, p.pos
+		key, err := p.parseKey()
+		if err != nil {
+			return err
+		}
+
+		p.skip(false)
+		if key == "include" && (p.peek() == '"' || p.peek() == '\'') {
+			if err = p.parseInclude(prefix); err != nil {
+				return err
+			}
+			continue
+		}
+
+		path := joinConfPath(prefix, key)
+		switch p.peek() {
+		case '=', ':':
+			p.next()
+			p.skip(false)
+			if p.peek() != '{' {
+				entry, err := p.parseValue(path)
+				if err != nil {
+					return err
+				}
+				entry.Line, entry.Col, entry.Offset, entry.End = kline, kcol, kpos, p.pos
+				p.entries = append(p.entries, entry)
+				continue
+			}
+			fallthrough
+		case '{':
+			p.next()
+			section := &confEntry{Path: path, Kind: confSection, File: p.file, Line: kline, Col: kcol, Offset: kpos}
+			p.entries = append(p.entries, section)
+			if err = p.parseBody(path, kline, kcol); err != nil {
+				return err
+			}
+			section.End = p.pos
+		default:
+			return p.errorf("expected '=' or '{' after key '%s'", key)
+		}
+	}
+}
+
+func (p *confParser) parseKey() (string, error) {
+	if c := p.peek(); c == '"' || c == '\'' {
+		return p.parseString()
+	}
+
+	start := p.pos
+	for !p.eof() && isConfKeyChar(p.peek()) {
+		p.next()
+	}
+	if start == p.pos {
+		return "", p.errorf("unexpected '%c', expected key", p.peek())
+	}
+	return string(p.src[start:p.pos]), nil
+}
+
+// parseInclude method parses the included files into the section, the
+// include path is relative to the current file and it can be glob pattern.
+func (p *confParser) parseInclude(prefix string) error {
+	line, col := p.line, p.col
+	pattern, err := p.parseString()
+	if err != nil {
+		return err
+	}
+	if p.depth >= confMaxIncludeDepth {
+		return p.errorAt(line, col, "too many nested includes")
+	}
+
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(filepath.Dir(p.file), filepath.FromSlash(pattern))
+	}
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return p.errorAt(line, col, "invalid include '%s': %s", pattern, err)
+	}
+	for _, f := range files {
+		if err = p.parseFile(f, prefix, p.depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *confParser) parseValue(path string) (*confEntry, error) {
+	entry := &confEntry{Path: path, File: p.file, Line: p.line, Col: p.col}
+	switch c := p.peek(); {
+	case c == '"' || c == '\'':
+		v, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		entry.Kind, entry.Value = confString, v
+	case c == '[':
+		p.next()
+		entry.Kind = confList
+		for {
+			p.skip(true)
+			if p.eof() {
+				return nil, p.errorAt(entry.Line, entry.Col, "list is not closed, missing ']'")
+			}
+			if p.peek() == ']' {
+				p.next()
+				break
+			}
+			item, err := p.parseValue(path)
+			if err != nil {
+				return nil, err
+			}
+			entry.Items = append(entry.Items, item)
+		}
+	default:
+		start := p.pos
+		// environment variable placeholder '${VAR:-default}' may have value
+		// end characters
+		if c == '$' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '{' {
+			for !p.eof() && p.peek() != '}' && p.peek() != '\n' {
+				p.next()
+			}
+			if p.peek() != '}' {
+				return nil, p.errorAt(entry.Line, entry.Col, "placeholder '${' is not closed")
+			}
+			p.next()
+		}
+		for !p.eof() && !isConfValueEnd(p.peek()) {
+			p.next()
+		}
+		if start == p.pos {
+			return nil, p.errorf("unexpected '%c', expected value", c)
+		}
+		entry.Value = string(p.src[start:p.pos])
+		entry.Kind = bareConfKind(entry.Value)
+	}
+	return entry, nil
+}
+
+func (p *confParser) parseString() (string, error) {
+	line, col := p.line, p.col
+	quote := p.next()
+	sb := &bytes.Buffer{}
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", p.errorAt(line, col, "string is not terminated")
+		}
+
+		c := p.next()
+		switch {
+		case c == quote:
+			return sb.String(), nil
+		case c == '\\' && !p.eof():
+			e := p.next()
+			switch e {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			case '"', '\'', '\\':
+				sb.WriteByte(e)
+			default:
+				sb.WriteByte(c)
+				sb.WriteByte(e)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+}
+
+// skip method skips the whitespaces and comments, newlines and separators
+// ',' and ';' too if lines is true.
+func (p *confParser) skip(lines bool) {
+	for !p.eof() {
+		switch c := p.peek(); {
+		case c == ' ' || c == '\t' || c == '\r':
+			p.next()
+		case lines && (c == '\n' || c == ',' || c == ';'):
+			p.next()
+		case c == '#' || (c == '/' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '/'):
+			for !p.eof() && p.peek() != '\n' {
+				p.next()
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *confParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *confParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *confParser) next() byte {
+	c := p.src[p.pos]
+	p.pos++
+	if c == '\n' {
+		p.line++
+		p.col = 1
+	} else {
+		p.col++
+	}
+	return c
+}
+
+func (p *confParser) errorf(format string, args ...interface{}) error {
+	return p.errorAt(p.line, p.col, format, args...)
+}
+
+func (p *confParser) errorAt(line, col int, format string, args ...interface{}) error {
+	return &confError{File: p.file, Line: line, Col: col, Msg: fmt.Sprintf(format, args...)}
+}
+
+func joinConfPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func isConfKeyChar(c byte) bool {
+	return c == '_' || c == '-' || c == '.' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func isConfValueEnd(c byte) bool {
+	switch c {
+	case ' ', '\t', '\r', '\n', ',', ';', ']', '}', '#':
+		return true
+	}
+	return false
+}
+
+// bareConfKind method returns the kind of unquoted value.
+func bareConfKind(v string) confKind {
+	switch {
+	case v == "true" || v == "false":
+		return confBool
+	case v == "null":
+		return confNull
+	case strings.HasPrefix(v, "$"):
+		return confRef
+	}
+	if _, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return confInt
+	}
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return confFloat
+	}
+	return confString
+}
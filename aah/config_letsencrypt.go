@@ -0,0 +1,339 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+var (
+	configLECmdFlags     = flag.NewFlagSet("letsencrypt", flag.ContinueOnError)
+	configLEDomainsFlag  = configLECmdFlags.String("domains", "", "Comma separated domain names of the certificates, e.g: example.com,www.example.com")
+	configLEEmailFlag    = configLECmdFlags.String("email", "", "Contact email address of the Let's Encrypt account")
+	configLECacheDirFlag = configLECmdFlags.String("cache-dir", "", "Certificate cache directory on the server. Default is '/var/lib/<app-name>/autocert'")
+	configLEProfileFlag  = configLECmdFlags.String("profile", "prod", "Environment profile to configure")
+	configLEPortFlag     = configLECmdFlags.String("port", "443", "Server port to configure, empty value keeps the current port")
+	configLEPrintFlag    = configLECmdFlags.Bool("print", false, "Print the config snippet only, config file is not modified")
+	configLESkipDNSFlag  = configLECmdFlags.Bool("skip-dns-check", false, "Skip the DNS resolution check of the domains")
+	configLECmd          = &command{
+		Name:      "letsencrypt",
+		UsageLine: "aah config letsencrypt -domains [-email] [-cache-dir] [-profile] [-port] [-print] [-skip-dns-check]",
+		Flags:     configLECmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "configure Let's Encrypt (ACME) certificates of the application",
+		Long: `
+Configures the Let's Encrypt autocert settings 'server.ssl.lets_encrypt' of
+the application in the environment profile file 'config/env/<profile>.conf',
+default profile is 'prod'. Existing values are replaced in place and comments
+are preserved:
+
+    server.port                          443
+    server.ssl.enable                    true
+    server.ssl.lets_encrypt.enable       true
+    server.ssl.lets_encrypt.host_policy  domains of '-domains'
+    server.ssl.lets_encrypt.email        value of '-email'
+    server.ssl.lets_encrypt.cache_dir    value of '-cache-dir'
+
+Domains are checked to be resolvable via DNS, Let's Encrypt validates the
+domain ownership by connecting to the server on the resolved address, so
+the domains must point to the server and port 443 must be reachable from
+the internet. Wildcard domains are not supported by autocert.
+
+Use '-print' to get the config snippet without modifying the file, e.g. to
+add it into the external config of the server.
+
+Example(s):
+    aah config letsencrypt -domains=example.com,www.example.com -email=admin@example.com
+
+    aah config letsencrypt -domains=example.com -print
+`,
+	}
+
+	// lookupHost is used for the DNS check of domains.
+	lookupHost = net.LookupHost
+)
+
+func configLERun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	domains := splitList(*configLEDomainsFlag)
+	if err := validateLEInput(domains, *configLEEmailFlag); err != nil {
+		fatalExit(exitUsage, err)
+		return
+	}
+
+	if !*configLESkipDNSFlag {
+		if problems := checkDomainsDNS(domains); len(problems) > 0 {
+			for _, p := range problems {
+				log.Error(p)
+			}
+			fatalExit(exitConfigError, "Domains must resolve to the server, use '-skip-dns-check' to configure anyway")
+			return
+		}
+	}
+
+	cacheDir := firstNonEmpty(*configLECacheDirFlag, "/var/lib/"+strings.Replace(aah.AppName(), " ", "_", -1)+"/autocert")
+	values := letsEncryptConfValues(domains, *configLEEmailFlag, cacheDir, *configLEPortFlag)
+	profile := *configLEProfileFlag
+	if *configLEPrintFlag {
+		fmt.Print(confSnippet(values))
+		return
+	}
+
+	file := filepath.Join(appProject.BaseDir, "config", "env", profile+".conf")
+	src, err := ioutil.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		fatal(err)
+		return
+	}
+
+	prefixed := make([]*confValue, 0, len(values))
+	for _, v := range values {
+		prefixed = append(prefixed, &confValue{Path: profile + "." + v.Path, Value: v.Value})
+	}
+	rel := filepath.Join("config", "env", profile+".conf")
+	result, changes, err := upsertConfValues(rel, src, prefixed)
+	if err != nil {
+		fatalErr(newExitErr(exitConfigError, err))
+		return
+	}
+	if len(changes) == 0 {
+		log.Infof("'%s' is up to date", rel)
+		return
+	}
+
+	for _, c := range changes {
+		log.Info(c)
+	}
+	if err = writeFile(file, result, permRWRR); err != nil {
+		fatal(err)
+		return
+	}
+	log.Infof("\nLet's Encrypt is configured for profile '%s', run the application with '-profile=%s'.", profile, profile)
+	log.Infof("Make sure cache directory '%s' is writable by the application on the server.", cacheDir)
+}
+
+// letsEncryptConfValues method returns the application config values of
+// Let's Encrypt autocert, port is skipped if it's empty.
+func letsEncryptConfValues(domains []string, email, cacheDir, port string) []*confValue {
+	quoted := make([]string, 0, len(domains))
+	for _, d := range domains {
+		quoted = append(quoted, strconv.Quote(d))
+	}
+
+	var values []*confValue
+	if !ess.IsStrEmpty(port) {
+		values = append(values, &confValue{Path: "server.port", Value: strconv.Quote(port)})
+	}
+	values = append(values,
+		&confValue{Path: "server.ssl.enable", Value: "true"},
+		&confValue{Path: "server.ssl.lets_encrypt.enable", Value: "true"},
+		&confValue{Path: "server.ssl.lets_encrypt.host_policy", Value: "[" + strings.Join(quoted, ", ") + "]"},
+	)
+	if !ess.IsStrEmpty(email) {
+		values = append(values, &confValue{Path: "server.ssl.lets_encrypt.email", Value: strconv.Quote(email)})
+	}
+	return append(values, &confValue{Path: "server.ssl.lets_encrypt.cache_dir", Value: strconv.Quote(cacheDir)})
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func validateLEInput(domains []string, email string) error {
+	if len(domains) == 0 {
+		return errors.New("Domains are required, e.g. '-domains=example.com,www.example.com'")
+	}
+	for _, d := range domains {
+		switch {
+		case strings.HasPrefix(d, "*."):
+			return fmt.Errorf("Wildcard domain '%s' is not supported by Let's Encrypt autocert", d)
+		case strings.Contains(d, "://") || strings.ContainsAny(d, "/: "):
+			return fmt.Errorf("Invalid domain '%s', give the host name only e.g. 'example.com'", d)
+		case d == "localhost" || net.ParseIP(d) != nil || !strings.Contains(d, "."):
+			return fmt.Errorf("Domain '%s' is not a public domain name, Let's Encrypt issues certificates for public domains only", d)
+		}
+	}
+	if !ess.IsStrEmpty(email) && !strings.Contains(email, "@") {
+		return fmt.Errorf("Invalid email '%s'", email)
+	}
+	return nil
+}
+
+// checkDomainsDNS method returns the problems of domains which doesn't
+// resolve to a public address.
+func checkDomainsDNS(domains []string) []string {
+	var problems []string
+	for _, d := range domains {
+		addrs, err := lookupHost(d)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: DNS lookup failed: %s", d, err))
+			continue
+		}
+
+		public := false
+		for _, a := range addrs {
+			if ip := net.ParseIP(a); ip != nil && !ip.IsLoopback() && !ip.IsUnspecified() && !isPrivateIP(ip) {
+				public = true
+			}
+		}
+		if !public {
+			problems = append(problems, fmt.Sprintf("%s: resolves to non-public address(es) %s", d, strings.Join(addrs, ", ")))
+			continue
+		}
+		log.Infof("%s: resolves to %s", d, strings.Join(addrs, ", "))
+	}
+	return problems
+}
+
+// isPrivateIP method reports whether the IP is in private address range of
+// RFC 1918 and RFC 4193.
+func isPrivateIP(ip net.IP) bool {
+	for _, cidr := range []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "fc00::/7"} {
+		if _, block, _ := net.ParseCIDR(cidr); block.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	configLECmd.Run = configLERun
+	configCmd.Subcommands = append(configCmd.Subcommands, configLECmd)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Config source editing
+//___________________________________
+
+// confValue is the config key path and its value in config source form,
+// e.g. '"443"' or '["example.com"]'.
+type confValue struct {
+	Path  string
+	Value string
+}
+
+// upsertConfValues method sets the values in the config source. Existing
+// value is replaced in place, missing key is inserted into its nearest
+// existing section as nested sections. It returns the change summary lines.
+func upsertConfValues(file string, src []byte, values []*confValue) ([]byte, []string, error) {
+	var changes []string
+	var missing []*confValue
+	for _, v := range values {
+		entries, err := parseConfString(file, string(src))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		e := findConfEntry(entries, v.Path)
+		if e == nil {
+			missing = append(missing, v)
+			continue
+		}
+		if e.Kind == confSection {
+			return nil, nil, fmt.Errorf("%s: '%s' is a section", e.Pos(), v.Path)
+		}
+		if confEntrySource(e) == v.Value {
+			continue
+		}
+
+		eq := bytes.IndexAny(src[e.Offset:e.End], "=:")
+		if eq < 0 {
+			return nil, nil, fmt.Errorf("%s: unable to update '%s'", e.Pos(), v.Path)
+		}
+		start := e.Offset + eq + 1
+		src = append(src[:start:start], append([]byte(" "+v.Value), src[e.End:]...)...)
+		changes = append(changes, fmt.Sprintf("%s:%d: updated '%s' to %s", file, e.Line, v.Path, v.Value))
+	}
+
+	// inserted at the beginning of the section, so reverse order keeps the
+	// given order of keys
+	for i := len(missing) - 1; i >= 0; i-- {
+		var err error
+		if src, err = insertConfBlock(file, src, missing[i].Path, missing[i].Value); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, v := range missing {
+		changes = append(changes, fmt.Sprintf("%s: added '%s = %s'", file, v.Path, v.Value))
+	}
+	return src, changes, nil
+}
+
+// insertConfBlock method inserts the value as nested sections into the
+// nearest existing section of the key path, otherwise it's appended at the
+// end.
+func insertConfBlock(file string, src []byte, path, value string) ([]byte, error) {
+	entries, err := parseConfString(file, string(src))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := strings.Split(path, ".")
+	for i := len(keys) - 1; i > 0; i-- {
+		e := findConfEntry(entries, strings.Join(keys[:i], "."))
+		if e == nil || e.Kind != confSection {
+			continue
+		}
+
+		brace := e.Offset + bytes.IndexByte(src[e.Offset:], '{') + 1
+		indent := confLineIndent(src, e.Offset) + "  "
+		block := "\n" + confBlock(keys[i:], value, indent)
+		if rest := bytes.TrimLeft(src[brace:], " \t\r"); len(rest) > 0 && rest[0] != '\n' {
+			block += "\n" + indent
+		}
+		return append(src[:brace:brace], append([]byte(block), src[brace:]...)...), nil
+	}
+
+	buf := &bytes.Buffer{}
+	buf.Write(src)
+	if len(src) > 0 && src[len(src)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+	if len(src) > 0 {
+		buf.WriteByte('\n')
+	}
+	buf.WriteString(confBlock(keys, value, ""))
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// confBlock method returns the nested sections of the keys with the value,
+// without trailing newline.
+func confBlock(keys []string, value, indent string) string {
+	if len(keys) == 1 {
+		return indent + keys[0] + " = " + value
+	}
+	return indent + keys[0] + " {\n" + confBlock(keys[1:], value, indent+"  ") + "\n" + indent + "}"
+}
+
+// confSnippet method returns the nested config source of the values.
+func confSnippet(values []*confValue) string {
+	src, _, _ := upsertConfValues("snippet", nil, values)
+	return string(src)
+}
+
+func findConfEntry(entries []*confEntry, path string) *confEntry {
+	for _, e := range entries {
+		if e.Path == path {
+			return e
+		}
+	}
+	return nil
+}
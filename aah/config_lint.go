@@ -0,0 +1,349 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+const (
+	confSeverityError   = "error"
+	confSeverityWarning = "warning"
+)
+
+var (
+	configLintCmdFlags   = flag.NewFlagSet("lint", flag.ContinueOnError)
+	configLintStrictFlag = configLintCmdFlags.Bool("strict", false, "Treat warnings as errors")
+	configLintFormatFlag = configLintCmdFlags.String("format", "text", "Report format 'text' or 'sarif'")
+	configLintOutputFlag = configLintCmdFlags.String("o", "", "Report file path. Default is stdout")
+	configLintCmd        = &command{
+		Name:      "lint",
+		UsageLine: "aah config lint [-strict] [-format] [-o]",
+		Flags:     configLintCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "validate aah project and application config files",
+		Long: `
+Parses 'aah.project', 'config/aah.conf' (along with its includes such as
+environment profiles), 'config/routes.conf' and other 'config/*.conf' files,
+then validates the keys against the known schema of aah CLI tool and
+framework. Problems are reported with file positions:
+
+    aah.project:21:5: warning: unknown key 'build.binry_name'
+    config/aah.conf:26:3: error: 'server.port' must be int or string, found bool
+    config/routes.conf:18:5: error: 'domains.localhost.host' is required
+
+Errors are syntax errors, wrong value types, invalid durations, values not
+in the allowed list and missing required values. Routes are validated same
+as 'aah build', refer 'aah help routes.conf'. Warnings are unknown and
+duplicate keys, application custom config keys outside of the framework
+sections are allowed.
+
+Report format 'sarif' is SARIF 2.1.0 JSON, upload it to GitHub or GitLab code
+scanning to show the problems inline on the config files.
+
+Exit code is 'config' (refer 'aah help exit-codes') if any error is found,
+or any warning with '-strict'.
+
+Example(s):
+    aah config lint
+
+    aah config lint -strict
+
+    aah config lint -format=sarif -o=config-lint.sarif
+`,
+	}
+)
+
+// confProblem is the problem found by config lint.
+type confProblem struct {
+	File     string
+	Line     int
+	Col      int
+	Severity string
+	Msg      string
+	Hint     string
+}
+
+func configLintRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	format := *configLintFormatFlag
+	if format != "text" && format != "sarif" {
+		fatalExitf(exitUsage, "Unsupported report format '%s', choose 'text' or 'sarif'", format)
+		return
+	}
+
+	problems := lintAppConfigs(appProject.BaseDir)
+	buf := &bytes.Buffer{}
+	errs, warns := printConfProblems(buf, appProject.BaseDir, problems)
+	if format == "sarif" {
+		buf.Reset()
+		if err := confProblemsSARIF(appProject.BaseDir, problems).Write(buf); err != nil {
+			fatal(err)
+			return
+		}
+	}
+	if file := getNonEmptyAbsPath(*configLintOutputFlag, ""); !ess.IsStrEmpty(file) {
+		if err := writeFile(file, buf.Bytes(), permRWRR); err != nil {
+			fatal(err)
+			return
+		}
+		recordArtifact(file)
+		log.Infof("Config lint report written to %s", file)
+	} else {
+		_, _ = os.Stdout.Write(buf.Bytes())
+	}
+
+	if errs > 0 || (*configLintStrictFlag && warns > 0) {
+		fatalExitf(exitConfigError, "Config lint failed, %d error(s), %d warning(s)", errs, warns)
+		return
+	}
+	if warns > 0 {
+		log.Infof("%d warning(s)", warns)
+		return
+	}
+	log.Info("No problems found")
+}
+
+// lintAppConfigs method lints the project file and config files of the
+// application. Config file included by already linted file is not linted
+// again.
+func lintAppConfigs(baseDir string) []*confProblem {
+	projectFile := filepath.Join(baseDir, aahProjectIdentifier)
+	routesFile := filepath.Join(baseDir, "config", "routes.conf")
+	schemas := map[string]*confSchema{projectFile: projectConfSchema, routesFile: routesConfSchema}
+
+	files := []string{projectFile, filepath.Join(baseDir, "config", "aah.conf"), routesFile}
+	confFiles, _ := filepath.Glob(filepath.Join(baseDir, "config", "*.conf"))
+	sort.Strings(confFiles)
+	files = append(files, confFiles...)
+
+	linted := map[string]bool{}
+	var problems []*confProblem
+	for _, f := range files {
+		if linted[f] || !ess.IsFileExists(f) {
+			continue
+		}
+
+		entries, err := parseConfFile(f)
+		linted[f] = true
+		for _, e := range entries {
+			linted[e.File] = true
+		}
+		if err != nil {
+			problems = append(problems, confErrorProblem(f, err))
+		}
+
+		schema, found := schemas[f]
+		if !found {
+			schema = appConfSchema
+		}
+		problems = append(problems, lintConf(f, entries, schema)...)
+		if f == routesFile && err == nil {
+			problems = append(problems, checkRoutes(entries)...)
+		}
+	}
+	sortConfProblems(problems)
+	return problems
+}
+
+// lintConf method validates the config entries against the schema.
+func lintConf(file string, entries []*confEntry, schema *confSchema) []*confProblem {
+	var problems []*confProblem
+	report := func(e *confEntry, severity, format string, args ...interface{}) {
+		problems = append(problems, &confProblem{File: e.File, Line: e.Line, Col: e.Col,
+			Severity: severity, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	defined := map[string]*confEntry{}
+	for _, e := range entries {
+		if prev, found := defined[e.Path]; found && e.Kind != confSection && prev.Kind != confSection {
+			report(e, confSeverityWarning, "'%s' is defined again, it overrides the value at %s", e.Path, prev.Pos())
+		}
+		defined[e.Path] = e
+
+		rule := schema.Rule(e.Path)
+		if rule == nil {
+			if schema.isKnownParent(e.Path) {
+				report(e, confSeverityWarning, "unknown key '%s'", e.Path)
+			}
+			continue
+		}
+
+		if msg := rule.check(e); !ess.IsStrEmpty(msg) {
+			report(e, confSeverityError, "'%s' %s", e.Path, msg)
+		}
+	}
+
+	// required keys of the sections
+	for _, rule := range schema.Rules {
+		if !rule.Required {
+			continue
+		}
+
+		parent := rule.Parent()
+		if ess.IsStrEmpty(parent) {
+			if e, found := defined[rule.Key()]; !found || (e.Kind == confString && ess.IsStrEmpty(e.Value)) {
+				report(&confEntry{File: file, Line: 1, Col: 1}, confSeverityError, "'%s' is required", rule.Key())
+			}
+			continue
+		}
+
+		for _, e := range entries {
+			if e.Kind != confSection || defined[e.Path] != e || !schema.Rule(e.Path).matchPattern(parent) {
+				continue
+			}
+			key := e.Path + "." + rule.Key()
+			if v, found := defined[key]; !found || (v.Kind == confString && ess.IsStrEmpty(v.Value)) {
+				report(e, confSeverityError, "'%s' is required", key)
+			}
+		}
+	}
+
+	return problems
+}
+
+// printConfProblems method prints the problems with file path relative to
+// the base directory and returns the no. of errors and warnings.
+func printConfProblems(w io.Writer, baseDir string, problems []*confProblem) (int, int) {
+	var errs, warns int
+	for _, p := range problems {
+		file := p.File
+		if rel, err := filepath.Rel(baseDir, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = filepath.ToSlash(rel)
+		}
+		fmt.Fprintf(w, "%s:%d:%d: %s: %s\n", file, p.Line, p.Col, p.Severity, p.Msg)
+		if !ess.IsStrEmpty(p.Hint) {
+			fmt.Fprintf(w, "\thint: %s\n", p.Hint)
+		}
+		if p.Severity == confSeverityError {
+			errs++
+		} else {
+			warns++
+		}
+	}
+	return errs, warns
+}
+
+// confProblemsSARIF method returns the SARIF report of the problems, rule
+// of the problem is its severity.
+func confProblemsSARIF(baseDir string, problems []*confProblem) *sarifLog {
+	report := newSARIFLog("config lint", baseDir)
+	for _, p := range problems {
+		msg := p.Msg
+		if !ess.IsStrEmpty(p.Hint) {
+			msg += ", hint: " + p.Hint
+		}
+		if p.Severity == confSeverityError {
+			report.Add("config-error", "Config error, e.g. syntax error, wrong value type or missing required value", sarifLevelError, msg, p.File, p.Line, p.Col)
+		} else {
+			report.Add("config-warning", "Config warning, e.g. unknown or duplicate key", sarifLevelWarning, msg, p.File, p.Line, p.Col)
+		}
+	}
+	return report
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// sortConfProblems method sorts the problems by file and position.
+func sortConfProblems(problems []*confProblem) {
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].File != problems[j].File {
+			return problems[i].File < problems[j].File
+		}
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+		return problems[i].Col < problems[j].Col
+	})
+}
+
+func confErrorProblem(file string, err error) *confProblem {
+	if ce, ok := err.(*confError); ok {
+		return &confProblem{File: ce.File, Line: ce.Line, Col: ce.Col, Severity: confSeverityError, Msg: ce.Msg}
+	}
+	return &confProblem{File: file, Line: 1, Col: 1, Severity: confSeverityError, Msg: err.Error()}
+}
+
+// isKnownParent method reports whether the unknown key is in the section
+// defined by the schema, keys of open and unknown sections are not reported.
+func (s *confSchema) isKnownParent(path string) bool {
+	idx := strings.LastIndex(path, ".")
+	if idx < 0 {
+		return !s.Open
+	}
+
+	parent := s.Rule(path[:idx])
+	return parent != nil && parent.Kind == confSection && !parent.Open
+}
+
+// check method validates the entry kind and value against the rule, it
+// returns the problem message or empty string.
+func (r *confRule) check(e *confEntry) string {
+	// value of environment variable placeholder is known at load time
+	if e.Kind == confRef || strings.Contains(e.Value, "${") {
+		return ""
+	}
+
+	kind := r.Kind
+	if kind&confDuration != 0 {
+		kind |= confString
+	}
+	if e.Kind&kind == 0 {
+		return fmt.Sprintf("must be %s, found %s", r.Kind, e.Kind)
+	}
+
+	if r.Kind&confDuration != 0 && e.Kind == confString {
+		if _, err := time.ParseDuration(e.Value); err != nil {
+			return fmt.Sprintf("is invalid duration '%s', valid units are 'ms', 's', 'm' and 'h'", e.Value)
+		}
+	}
+
+	if len(r.Values) > 0 && e.Kind != confList && e.Kind != confSection {
+		found := false
+		for _, v := range r.Values {
+			if v == e.Value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Sprintf("value '%s' is not one of '%s'", e.Value, strings.Join(r.Values, "', '"))
+		}
+	}
+
+	if r.Elem != 0 {
+		for _, item := range e.Items {
+			if item.Kind&r.Elem == 0 && item.Kind != confRef {
+				return fmt.Sprintf("items must be %s, found %s", r.Elem, item.Kind)
+			}
+		}
+	}
+	return ""
+}
+
+// matchPattern method reports whether the rule is of given pattern.
+func (r *confRule) matchPattern(pattern string) bool {
+	return r != nil && r.Pattern == pattern
+}
+
+func init() {
+	configLintCmd.Run = configLintRun
+	configCmd.Subcommands = append(configCmd.Subcommands, configLintCmd)
+}
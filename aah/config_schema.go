@@ -0,0 +1,444 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+type (
+	// confSchema is the known keys of the config file. Unknown keys of open
+	// schema root, such as application custom config in 'aah.conf', are
+	// allowed.
+	confSchema struct {
+		Name  string
+		Open  bool
+		Rules []*confRule
+	}
+
+	// confRule is the key pattern and its kind. Pattern is dotted key path,
+	// '*' matches one key and '{...}' group repeats zero or more times, e.g.
+	// 'domains.*.routes.*{.routes.*}.path' matches nested routes path.
+	confRule struct {
+		Pattern  string
+		Kind     confKind
+		Elem     confKind
+		Values   []string
+		Required bool
+		Open     bool
+		re       *regexp.Regexp
+	}
+)
+
+var (
+	logLevels = []string{"trace", "debug", "info", "warn", "error"}
+
+	// projectConfSchema is the schema of 'aah.project'.
+	projectConfSchema = newConfSchema("aah.project", false,
+		confSec("project"),
+		confKey("project.header_file", confString),
+
+		confSec("build"),
+		confKey("build.binary_name", confString),
+		confKey("build.version", confString),
+		confKey("build.version_scheme", confString).values("semver", "calver"),
+		confKey("build.calver_format", confString),
+		confKey("build.version_metadata", confString),
+		confKey("build.dep_get", confBool),
+		confKey("build.log_level", confString).values(logLevels...),
+		confKey("build.flags", confList).elem(confString),
+		confKey("build.ldflags", confString),
+		confKey("build.tags", confString),
+		confKey("build.ast_excludes", confList).elem(confString),
+		confKey("build.excludes", confList).elem(confString),
+		confKey("build.embed_migrations", confBool),
+		confKey("build.profiling", confBool),
+		confKey("build.profiling_address", confString),
+		confKey("build.metrics", confBool),
+		confKey("build.metrics_address", confString),
+		confKey("build.metrics_path", confString),
+		confKey("build.max_binary_size", confString),
+		confKey("build.binary_size_action", confString).values("fail", "warn"),
+		confSec("build.assets"),
+		confKey("build.assets.enable", confBool),
+		confKey("build.assets.minify", confBool),
+		confKey("build.assets.optimize_images", confBool),
+		confKey("build.assets.dist", confString),
+		confKey("build.assets.excludes", confList).elem(confString),
+		confKey("build.assets.fingerprint", confBool),
+		confKey("build.assets.fingerprint_excludes", confList).elem(confString),
+		confKey("build.assets.manifest", confString),
+		confSec("build.assets.compilers"),
+		confSec("build.assets.compilers.*"),
+		confKey("build.assets.compilers.*.command", confString).required(),
+		confKey("build.assets.compilers.*.watch", confList).elem(confString),
+
+		confSec("run"),
+		confKey("run.shutdown_timeout", confDuration),
+		confKey("run.reload_signal", confString),
+		confKey("run.crash_restart", confBool),
+		confKey("run.crash_restart_max_delay", confDuration),
+		confSec("run.env"),
+		confSec("run.env.*"),
+		confKey("run.env.*.profile", confString),
+		confKey("run.env.*.config", confString),
+		confKey("run.env.*.env_file", confString),
+		confSec("run.env.*.vars").open(),
+		confSec("run.dotenv"),
+		confKey("run.dotenv.enable", confBool),
+		confKey("run.dotenv.override", confBool),
+		confSec("run.api_docs"),
+		confKey("run.api_docs.enable", confBool),
+		confKey("run.api_docs.path", confString),
+
+		confSec("watch"),
+		confKey("watch.dirs", confList).elem(confString),
+		confKey("watch.includes", confList).elem(confString),
+		confKey("watch.excludes", confList).elem(confString),
+		confKey("watch.debounce", confDuration),
+		confKey("watch.poll", confBool),
+		confKey("watch.poll_interval", confDuration),
+
+		confSec("livereload"),
+		confKey("livereload.enable", confBool),
+		confKey("livereload.port", confInt|confString),
+
+		confSec("proxy"),
+		confKey("proxy.enable", confBool),
+		confKey("proxy.hold_timeout", confDuration),
+
+		confSec("test"),
+		confKey("test.packages", confList).elem(confString),
+		confKey("test.timeout", confDuration),
+		confKey("test.race", confBool),
+		confKey("test.flags", confList).elem(confString),
+		confKey("test.coverage_min", confInt|confFloat),
+		confKey("test.junit_file", confString),
+
+		confSec("bench"),
+		confKey("bench.count", confInt),
+		confKey("bench.threshold", confInt|confFloat),
+		confKey("bench.startup_runs", confInt),
+		confKey("bench.startup_threshold", confInt|confFloat),
+
+		confSec("migration"),
+		confKey("migration.dialect", confString).values("postgres", "mysql", "sqlite"),
+		confKey("migration.config_key", confString),
+		confKey("migration.table", confString),
+		confKey("migration.driver_import", confString),
+
+		confSec("compose"),
+		confKey("compose.go_image", confString),
+		confSec("compose.env").open(),
+
+		confSec("ci"),
+		confKey("ci.go_versions", confList).elem(confString),
+		confKey("ci.profiles", confList).elem(confString),
+		confKey("ci.platforms", confList).elem(confString),
+
+		confSec("k8s"),
+		confKey("k8s.image", confString),
+		confKey("k8s.namespace", confString),
+		confKey("k8s.replicas", confInt),
+		confKey("k8s.app_dir", confString),
+		confKey("k8s.health_path", confString),
+		confSec("k8s.resources"),
+		confSec("k8s.resources.requests"),
+		confKey("k8s.resources.requests.cpu", confString),
+		confKey("k8s.resources.requests.memory", confString),
+		confSec("k8s.resources.limits"),
+		confKey("k8s.resources.limits.cpu", confString),
+		confKey("k8s.resources.limits.memory", confString),
+
+		confSec("package"),
+		confKey("package.dir", confString),
+		confKey("package.user", confString),
+		confKey("package.maintainer", confString),
+		confKey("package.description", confString),
+		confKey("package.homepage", confString),
+		confSec("package.secret_scan"),
+		confKey("package.secret_scan.mode", confString),
+		confKey("package.secret_scan.patterns", confList).elem(confString),
+		confKey("package.secret_scan.allow", confList).elem(confString),
+		confKey("package.secret_scan.excludes", confList).elem(confString),
+
+		confSec("deploy"),
+		confKey("deploy.os", confString),
+		confKey("deploy.arch", confString),
+		confKey("deploy.keep_releases", confInt),
+		confSec("deploy.targets"),
+		confSec("deploy.targets.*"),
+		confKey("deploy.targets.*.host", confString).required(),
+		confKey("deploy.targets.*.port", confInt|confString),
+		confKey("deploy.targets.*.user", confString),
+		confKey("deploy.targets.*.identity_file", confString),
+		confKey("deploy.targets.*.dir", confString).required(),
+		confKey("deploy.targets.*.restart", confString),
+		confKey("deploy.targets.*.keep_releases", confInt),
+		confSec("deploy.heroku"),
+		confKey("deploy.heroku.app", confString).required(),
+		confSec("deploy.cloudfoundry"),
+		confKey("deploy.cloudfoundry.app", confString).required(),
+		confKey("deploy.cloudfoundry.memory", confString),
+		confKey("deploy.cloudfoundry.instances", confInt|confString),
+		confSec("deploy.elasticbeanstalk"),
+		confKey("deploy.elasticbeanstalk.application", confString).required(),
+		confKey("deploy.elasticbeanstalk.environment", confString).required(),
+		confKey("deploy.elasticbeanstalk.bucket", confString).required(),
+		confKey("deploy.elasticbeanstalk.region", confString),
+
+		confSec("release"),
+		confKey("release.github", confString),
+		confKey("release.download_url", confString),
+		confKey("release.tag_prefix", confString),
+		confKey("release.bump", confString).values("major", "minor", "patch"),
+		confKey("release.changelog", confString),
+		confKey("release.platforms", confList).elem(confString),
+		confKey("release.publish", confBool),
+		confKey("release.git_remote", confString),
+		confSec("release.sign"),
+		confKey("release.sign.enable", confBool),
+		confKey("release.sign.key", confString),
+
+		confSec("config"),
+		confSec("config.remote"),
+		confKey("config.remote.url", confString).required(),
+		confKey("config.remote.timeout", confDuration),
+		confKey("config.remote.cache_file", confString),
+		confSec("config.remote.headers").open(),
+
+		confSec("secrets"),
+		confKey("secrets.file", confString),
+		confKey("secrets.key_file", confString),
+
+		confSec("deps"),
+		confSec("deps.licenses"),
+		confKey("deps.licenses.allow", confList).elem(confString),
+		confKey("deps.licenses.deny", confList).elem(confString),
+		confKey("deps.licenses.ignore", confList).elem(confString),
+		confKey("deps.licenses.fail_on_unknown", confBool),
+	)
+
+	// appConfRules are the framework keys of application config, these are
+	// valid in the environment profiles too.
+	appConfRules = []*confRule{
+		confKey("name", confString),
+		confKey("desc", confString),
+
+		confSec("server"),
+		confKey("server.address", confString),
+		confKey("server.port", confInt|confString),
+		confSec("server.timeout"),
+		confKey("server.timeout.read", confDuration),
+		confKey("server.timeout.write", confDuration),
+		confKey("server.timeout.grace_shutdown", confDuration),
+		confKey("server.max_header_bytes", confString),
+		confKey("server.keep_alive", confBool),
+		confSec("server.ssl"),
+		confKey("server.ssl.enable", confBool),
+		confKey("server.ssl.cert", confString),
+		confKey("server.ssl.key", confString),
+		confKey("server.ssl.disable_http2", confBool),
+		confSec("server.ssl.lets_encrypt"),
+		confKey("server.ssl.lets_encrypt.enable", confBool),
+		confKey("server.ssl.lets_encrypt.host_policy", confList).elem(confString),
+		confKey("server.ssl.lets_encrypt.renew_before", confInt),
+		confKey("server.ssl.lets_encrypt.email", confString),
+		confKey("server.ssl.lets_encrypt.force_rsa", confBool),
+		confKey("server.ssl.lets_encrypt.cache_dir", confString),
+
+		confSec("request"),
+		confSec("request.id"),
+		confKey("request.id.enable", confBool),
+		confKey("request.id.header", confString),
+		confKey("request.multipart_size", confString),
+
+		confSec("i18n").open(),
+		confKey("i18n.default", confString),
+
+		confSec("format"),
+		confKey("format.date", confString),
+		confKey("format.datetime", confString),
+
+		confSec("runtime"),
+		confSec("runtime.debug"),
+		confKey("runtime.debug.stack_buffer_size", confString),
+		confKey("runtime.debug.all_goroutines", confBool),
+		confSec("runtime.pooling"),
+		confKey("runtime.pooling.global", confInt),
+		confKey("runtime.pooling.buffer", confInt),
+
+		confSec("render"),
+		confKey("render.default", confString).values("html", "json", "xml", "text"),
+		confKey("render.pretty", confBool),
+		confSec("render.gzip"),
+		confKey("render.gzip.enable", confBool),
+		confKey("render.gzip.level", confInt).values("1", "2", "3", "4", "5", "6", "7", "8", "9"),
+
+		confSec("view"),
+		confKey("view.engine", confString),
+		confKey("view.ext", confString),
+		confKey("view.case_sensitive", confBool),
+		confKey("view.delimiters", confString),
+		confKey("view.default_layout", confBool),
+
+		confSec("log").open(),
+		confKey("log.receiver", confString).values("console", "file"),
+		confKey("log.level", confString).values(logLevels...),
+		confKey("log.file", confString),
+
+		confSec("security").open(),
+		confSec("security.session").open(),
+		confKey("security.session.mode", confString).values("stateless", "stateful"),
+		confSec("security.session.store").open(),
+		confKey("security.session.store.type", confString),
+		confKey("security.session.id_length", confInt),
+		confKey("security.session.ttl", confDuration),
+		confKey("security.session.http_only", confBool),
+		confKey("security.session.secure", confBool),
+		confKey("security.session.cleanup_interval", confDuration),
+	}
+
+	// appConfSchema is the schema of 'config/aah.conf' and other config
+	// files, environment profiles are included into section 'env'.
+	appConfSchema = newConfSchema("application config", true,
+		append(appConfRules, envConfRules(appConfRules)...)...)
+
+	// routesConfSchema is the schema of 'config/routes.conf'.
+	routesConfSchema = newConfSchema("routes.conf", false,
+		confSec("domains"),
+		confSec("domains.*"),
+		confKey("domains.*.name", confString),
+		confKey("domains.*.host", confString).required(),
+		confKey("domains.*.port", confInt|confString),
+		confKey("domains.*.redirect_trailing_slash", confBool),
+		confKey("domains.*.method_not_allowed", confBool),
+		confKey("domains.*.auto_options", confBool),
+		confKey("domains.*.default_auth", confString),
+		confKey("domains.*.anti_csrf_check", confBool),
+		confSec("domains.*.not_found"),
+		confKey("domains.*.not_found.controller", confString).required(),
+		confKey("domains.*.not_found.action", confString),
+		confSec("domains.*.static"),
+		confSec("domains.*.static.*"),
+		confKey("domains.*.static.*.path", confString).required(),
+		confKey("domains.*.static.*.dir", confString),
+		confKey("domains.*.static.*.file", confString),
+		confKey("domains.*.static.*.list", confBool),
+		confSec("domains.*.routes"),
+		confSec("domains.*.routes.*{.routes.*}"),
+		confKey("domains.*.routes.*{.routes.*}.path", confString).required(),
+		confKey("domains.*.routes.*{.routes.*}.method", confString),
+		confKey("domains.*.routes.*{.routes.*}.controller", confString),
+		confKey("domains.*.routes.*{.routes.*}.action", confString),
+		confKey("domains.*.routes.*{.routes.*}.auth", confString),
+		confKey("domains.*.routes.*{.routes.*}.max_body_size", confString),
+		confKey("domains.*.routes.*{.routes.*}.anti_csrf_check", confBool),
+		confSec("domains.*.routes.*{.routes.*}.routes"),
+	)
+)
+
+func newConfSchema(name string, open bool, rules ...*confRule) *confSchema {
+	for _, r := range rules {
+		r.re = regexp.MustCompile("^" + confPatternRegexp(r.Pattern) + "$")
+	}
+	return &confSchema{Name: name, Open: open, Rules: rules}
+}
+
+func confKey(pattern string, kind confKind) *confRule {
+	return &confRule{Pattern: pattern, Kind: kind}
+}
+
+func confSec(pattern string) *confRule {
+	return &confRule{Pattern: pattern, Kind: confSection}
+}
+
+// Rule returns the first rule matching the key path, nil if key is unknown.
+func (s *confSchema) Rule(path string) *confRule {
+	for _, r := range s.Rules {
+		if r.re.MatchString(path) {
+			return r
+		}
+	}
+	return nil
+}
+
+// Match method reports whether the key path matches the rule pattern.
+func (r *confRule) Match(path string) bool {
+	return r.re.MatchString(path)
+}
+
+// Parent method returns the pattern of parent section, empty for root keys.
+func (r *confRule) Parent() string {
+	if idx := strings.LastIndex(r.Pattern, "."); idx > 0 {
+		return r.Pattern[:idx]
+	}
+	return ""
+}
+
+// Key method returns the last key of the pattern.
+func (r *confRule) Key() string {
+	return r.Pattern[strings.LastIndex(r.Pattern, ".")+1:]
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func (r *confRule) required() *confRule {
+	r.Required = true
+	return r
+}
+
+func (r *confRule) open() *confRule {
+	r.Open = true
+	return r
+}
+
+func (r *confRule) elem(kind confKind) *confRule {
+	r.Elem = kind
+	return r
+}
+
+func (r *confRule) values(values ...string) *confRule {
+	r.Values = values
+	return r
+}
+
+// envConfRules method returns the application config rules for the
+// environment profiles 'env.<profile>'. Profile may have application custom
+// config, so it's open.
+func envConfRules(rules []*confRule) []*confRule {
+	envRules := []*confRule{
+		confSec("env").open(),
+		confKey("env.active", confString),
+		confSec("env.*").open(),
+	}
+	for _, r := range rules {
+		er := *r
+		er.Pattern = "env.*." + r.Pattern
+		er.Required = false
+		envRules = append(envRules, &er)
+	}
+	return envRules
+}
+
+func confPatternRegexp(pattern string) string {
+	var buf []string
+	for _, c := range pattern {
+		switch c {
+		case '*':
+			buf = append(buf, `[^.]+`)
+		case '{':
+			buf = append(buf, `(?:`)
+		case '}':
+			buf = append(buf, `)*`)
+		default:
+			buf = append(buf, regexp.QuoteMeta(string(c)))
+		}
+	}
+	return strings.Join(buf, "")
+}
@@ -0,0 +1,372 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+const (
+	configFormatHOCON = "hocon"
+	configFormatJSON  = "json"
+)
+
+var (
+	configShowCmdFlags        = flag.NewFlagSet("show", flag.ContinueOnError)
+	configShowProfileFlag     = configShowCmdFlags.String("profile", "", "Environment profile name. Default is 'env.active' or 'dev'")
+	configShowProfileShortFlg = configShowCmdFlags.String("p", "", "Environment profile name. Default is 'env.active' or 'dev'")
+	configShowConfigFlag      = configShowCmdFlags.String("config", "", "External config file to merge, same as 'aah run -config'")
+	configShowConfigShortFlag = configShowCmdFlags.String("c", "", "External config file to merge, same as 'aah run -config'")
+	configShowEnvFlag         = configShowCmdFlags.String("env", "", "Run environment name of 'run.env', same as 'aah run -env'")
+	configShowEnvShortFlag    = configShowCmdFlags.String("e", "", "Run environment name of 'run.env', same as 'aah run -env'")
+	configShowFormatFlag      = configShowCmdFlags.String("format", configFormatHOCON, "Output format 'hocon' or 'json'")
+	configShowCmd             = &command{
+		Name:      "show",
+		UsageLine: "aah config show [-p | -profile] [-c | -config] [-e | -env] [-format] [key]",
+		Flags:     configShowCmdFlags,
+		MaxArgs:   1,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "print the effective merged configuration",
+		Long: `
+Prints the effective configuration the application runs with, 'project' is
+the 'aah.project' and 'app' is the application config merged in order:
+
+    1. 'config/aah.conf' along with its includes
+    2. environment profile section 'env.<profile>'
+    3. external config of '-config', environment variable placeholders
+       '${VAR:-default}' are replaced same as 'aah run'
+    4. environment overrides, 'env.active' and 'AAH_SERVER_PORT'
+
+References '$key' are resolved. Key argument prints only the given key,
+e.g: 'app.server' or 'project.build'.
+
+Example(s):
+    aah config show
+
+    aah config show -profile=prod -format=json
+
+    aah config show -p=prod -c=/etc/myapp/external.conf app.server
+
+    aah config show -e=staging
+`,
+	}
+)
+
+type (
+	// confTree is the config values tree, sections are confTree too.
+	confTree map[string]interface{}
+
+	// confRefValue is the unresolved reference '$key' of the config.
+	confRefValue string
+)
+
+func configShowRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	format := strings.ToLower(*configShowFormatFlag)
+	if format != configFormatHOCON && format != configFormatJSON {
+		fatalExitf(exitUsage, "Unsupported format '%s', supported formats are 'hocon' and 'json'", *configShowFormatFlag)
+		return
+	}
+
+	env := &runEnv{}
+	if envName := firstNonEmpty(*configShowEnvFlag, *configShowEnvShortFlag); !ess.IsStrEmpty(envName) {
+		var err error
+		if env, err = loadRunEnv(appProject.BuildCfg, appProject.BaseDir, envName); err != nil {
+			fatalErr(newExitErr(exitConfigError, err))
+			return
+		}
+	}
+
+	tree, err := effectiveConfig(appProject.BaseDir,
+		firstNonEmpty(*configShowProfileFlag, *configShowProfileShortFlg, env.Profile),
+		firstNonEmpty(getNonEmptyAbsPath(*configShowConfigFlag, *configShowConfigShortFlag), env.Config))
+	if err != nil {
+		fatalErr(newExitErr(exitConfigError, err))
+		return
+	}
+
+	var value interface{} = tree
+	if len(args) > 0 {
+		var found bool
+		if value, found = tree.Get(args[0]); !found {
+			fatalExitf(exitConfigError, "Config key '%s' does not exists", args[0])
+			return
+		}
+	}
+
+	if format == configFormatJSON {
+		b, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			fatal(err)
+			return
+		}
+		fmt.Println(string(b))
+		return
+	}
+
+	buf := &bytes.Buffer{}
+	if t, ok := value.(confTree); ok {
+		t.writeHOCON(buf, 0)
+	} else {
+		buf.WriteString(hoconValue(value) + "\n")
+	}
+	fmt.Print(buf.String())
+}
+
+// effectiveConfig method returns the merged 'aah.project' and application
+// config of the profile, profile is 'env.active' or 'dev' if empty.
+func effectiveConfig(baseDir, profile, externalFile string) (confTree, error) {
+	project, err := loadConfTree(filepath.Join(baseDir, aahProjectIdentifier), baseDir, true)
+	if err != nil {
+		return nil, err
+	}
+
+	app, err := loadConfTree(filepath.Join(baseDir, "config", "aah.conf"), baseDir, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if ess.IsStrEmpty(profile) {
+		profile = "dev"
+		if v, found := app.Get("env.active"); found {
+			profile = fmt.Sprint(v)
+		}
+	}
+	envCfg, _ := app.Get("env." + profile)
+	if !isConfTree(envCfg) {
+		return nil, fmt.Errorf("environment profile '%s' does not exists in 'config/aah.conf'", profile)
+	}
+	app.Merge(envCfg.(confTree))
+	app.Set("env.active", profile)
+
+	if !ess.IsStrEmpty(externalFile) {
+		external, err := loadConfTree(externalFile, baseDir, true)
+		if err != nil {
+			return nil, fmt.Errorf("external config: %s", err)
+		}
+		app.Merge(external)
+	}
+
+	if port := os.Getenv(appPortEnv); !ess.IsStrEmpty(port) {
+		app.Set("server.port", port)
+	}
+
+	project.resolveRefs(project)
+	app.resolveRefs(app)
+	return confTree{"project": project, "app": app}, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// confTree methods
+//___________________________________
+
+// Get method returns the value of dotted key path.
+func (t confTree) Get(path string) (interface{}, bool) {
+	var v interface{} = t
+	for _, k := range strings.Split(path, ".") {
+		sub, ok := v.(confTree)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = sub[k]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// Set method sets the value of dotted key path, sections are created as
+// needed.
+func (t confTree) Set(path string, value interface{}) {
+	keys := strings.Split(path, ".")
+	for _, k := range keys[:len(keys)-1] {
+		sub, ok := t[k].(confTree)
+		if !ok {
+			sub = confTree{}
+			t[k] = sub
+		}
+		t = sub
+	}
+	t[keys[len(keys)-1]] = value
+}
+
+// Merge method merges the other tree into it, sections are merged deeply
+// and values are replaced.
+func (t confTree) Merge(other confTree) {
+	for k, v := range other {
+		if sub, ok := v.(confTree); ok {
+			if existing, ok := t[k].(confTree); ok {
+				existing.Merge(sub)
+				continue
+			}
+			cp := confTree{}
+			cp.Merge(sub)
+			v = cp
+		}
+		t[k] = v
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// loadConfTree method parses the config file along with its includes, the
+// environment variable placeholders are replaced if interpolate is true.
+func loadConfTree(file, baseDir string, interpolate bool) (confTree, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(b)
+	if interpolate && strings.Contains(content, "${") {
+		if content, err = interpolateConfig(file, content, baseDir); err != nil {
+			return nil, err
+		}
+	}
+
+	entries, err := parseConfString(file, content)
+	if err != nil {
+		return nil, err
+	}
+	return newConfTree(entries), nil
+}
+
+// newConfTree method creates the tree from config entries, the later entry
+// overrides the value.
+func newConfTree(entries []*confEntry) confTree {
+	t := confTree{}
+	for _, e := range entries {
+		if e.Kind == confSection {
+			if v, _ := t.Get(e.Path); !isConfTree(v) {
+				t.Set(e.Path, confTree{})
+			}
+			continue
+		}
+		t.Set(e.Path, confEntryValue(e))
+	}
+	return t
+}
+
+func isConfTree(v interface{}) bool {
+	_, ok := v.(confTree)
+	return ok
+}
+
+func confEntryValue(e *confEntry) interface{} {
+	switch e.Kind {
+	case confInt:
+		v, _ := strconv.ParseInt(e.Value, 10, 64)
+		return v
+	case confFloat:
+		v, _ := strconv.ParseFloat(e.Value, 64)
+		return v
+	case confBool:
+		return e.Value == "true"
+	case confNull:
+		return nil
+	case confRef:
+		return confRefValue(strings.TrimPrefix(e.Value, "$"))
+	case confList:
+		items := make([]interface{}, 0, len(e.Items))
+		for _, item := range e.Items {
+			items = append(items, confEntryValue(item))
+		}
+		return items
+	}
+	return e.Value
+}
+
+// resolveRefs method replaces the references with the referred values of
+// root, unresolved reference is kept as '$key' string.
+func (t confTree) resolveRefs(root confTree) {
+	for k, v := range t {
+		t[k] = resolveRef(root, v, 0)
+	}
+}
+
+func resolveRef(root confTree, v interface{}, depth int) interface{} {
+	switch tv := v.(type) {
+	case confRefValue:
+		if rv, found := root.Get(string(tv)); found && depth < confMaxIncludeDepth {
+			return resolveRef(root, rv, depth+1)
+		}
+		return "$" + string(tv)
+	case confTree:
+		tv.resolveRefs(root)
+	case []interface{}:
+		for i := range tv {
+			tv[i] = resolveRef(root, tv[i], depth)
+		}
+	}
+	return v
+}
+
+// writeHOCON method writes the tree in aah config format with sorted keys.
+func (t confTree) writeHOCON(buf *bytes.Buffer, indent int) {
+	keys := make([]string, 0, len(t))
+	for k := range t {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pad := strings.Repeat("  ", indent)
+	for _, k := range keys {
+		key := k
+		if strings.Contains(k, ".") || strings.IndexFunc(k, func(r rune) bool { return r > 127 || !isConfKeyChar(byte(r)) }) >= 0 {
+			key = strconv.Quote(k)
+		}
+
+		if sub, ok := t[k].(confTree); ok {
+			fmt.Fprintf(buf, "%s%s {\n", pad, key)
+			sub.writeHOCON(buf, indent+1)
+			fmt.Fprintf(buf, "%s}\n", pad)
+			continue
+		}
+		fmt.Fprintf(buf, "%s%s = %s\n", pad, key, hoconValue(t[k]))
+	}
+}
+
+func hoconValue(v interface{}) string {
+	switch tv := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(tv)
+	case []interface{}:
+		items := make([]string, 0, len(tv))
+		for _, item := range tv {
+			items = append(items, hoconValue(item))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case confTree:
+		buf := &bytes.Buffer{}
+		buf.WriteString("{\n")
+		tv.writeHOCON(buf, 1)
+		buf.WriteString("}")
+		return buf.String()
+	}
+	return fmt.Sprint(v)
+}
+
+func init() {
+	configShowCmd.Run = configShowRun
+	configCmd.Subcommands = append(configCmd.Subcommands, configShowCmd)
+}
@@ -0,0 +1,250 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestConfigParse(t *testing.T) {
+	entries, err := parseConfString("aah.conf", `# comment
+name = "sample"
+server {
+  port = 8080 // comment
+  timeout { read = "90s", write = '60s' }
+  ssl.enable = true
+}
+list = ["a", 1, $server.port]
+`)
+	assert.Nil(t, err)
+
+	var paths []string
+	for _, e := range entries {
+		paths = append(paths, e.Path+":"+e.Kind.String())
+	}
+	assert.Equal(t, []string{"name:string", "server:section", "server.port:int",
+		"server.timeout:section", "server.timeout.read:string", "server.timeout.write:string",
+		"server.ssl.enable:bool", "list:list"}, paths)
+	assert.Equal(t, "aah.conf:4:3", entries[2].Pos())
+	assert.Equal(t, "60s", entries[5].Value)
+	assert.Equal(t, 3, len(entries[7].Items))
+	assert.Equal(t, confRef, entries[7].Items[2].Kind)
+
+	_, err = parseConfString("aah.conf", "server {\n  port = 8080\n")
+	assert.Equal(t, "aah.conf:1:1: section is not closed, missing '}'", err.Error())
+
+	_, err = parseConfString("aah.conf", "name = \"sample\n")
+	assert.Equal(t, "aah.conf:1:8: string is not terminated", err.Error())
+
+	_, err = parseConfString("aah.conf", "server port = 80\n")
+	assert.Equal(t, "aah.conf:1:8: expected '=' or '{' after key 'server'", err.Error())
+}
+
+func TestConfigLint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-config-lint")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	files := map[string]string{
+		"aah.project": "build {\n  binry_name = \"app\"\n  dep_get = \"yes\"\n}\n" +
+			"watch { debounce = \"300\" }\n" +
+			"deploy { targets { web1 { host = \"web1\" } } }\n",
+		"config/aah.conf": "name = \"app\"\nname = \"app2\"\ncustom { anything = 1 }\n" +
+			"server { prot = 8080 }\nrender { default = \"yaml\" }\n" +
+			"env {\n  include \"./env/*.conf\"\n}\n",
+		"config/env/dev.conf": "dev {\n  log { level = \"verbose\" }\n  my_key = true\n}\n",
+		"config/routes.conf": "domains {\n  localhost {\n    routes {\n" +
+			"      users {\n        path = \"/users\"\n        controller = \"User\"\n" +
+			"        routes { show { controller = \"User\" } }\n      }\n" +
+			"    }\n  }\n}\n",
+		"config/security.conf": "security {\n  session { ttl = \"1x\" }\n",
+	}
+	for name, content := range files {
+		f := filepath.Join(dir, filepath.FromSlash(name))
+		assert.Nil(t, os.MkdirAll(filepath.Dir(f), 0755))
+		assert.Nil(t, ioutil.WriteFile(f, []byte(content), 0644))
+	}
+
+	buf := &bytes.Buffer{}
+	errs, warns := printConfProblems(buf, dir, lintAppConfigs(dir))
+	assert.Equal(t, "aah.project:2:3: warning: unknown key 'build.binry_name'\n"+
+		"aah.project:3:3: error: 'build.dep_get' must be bool, found string\n"+
+		"aah.project:5:9: error: 'watch.debounce' is invalid duration '300', valid units are 'ms', 's', 'm' and 'h'\n"+
+		"aah.project:6:20: error: 'deploy.targets.web1.dir' is required\n"+
+		"config/aah.conf:2:1: warning: 'name' is defined again, it overrides the value at "+
+		filepath.Join(dir, "config", "aah.conf")+":1:1\n"+
+		"config/aah.conf:4:10: warning: unknown key 'server.prot'\n"+
+		"config/aah.conf:5:10: error: 'render.default' value 'yaml' is not one of 'html', 'json', 'xml', 'text'\n"+
+		"config/env/dev.conf:2:9: error: 'env.dev.log.level' value 'verbose' is not one of 'trace', 'debug', 'info', 'warn', 'error'\n"+
+		"config/routes.conf:2:3: error: 'domains.localhost.host' is required\n"+
+		"config/routes.conf:7:18: error: 'domains.localhost.routes.users.routes.show.path' is required\n"+
+		"config/security.conf:1:1: error: section is not closed, missing '}'\n"+
+		"config/security.conf:2:13: error: 'security.session.ttl' is invalid duration '1x', valid units are 'ms', 's', 'm' and 'h'\n",
+		buf.String())
+	assert.Equal(t, 9, errs)
+	assert.Equal(t, 3, warns)
+	assert.True(t, strings.HasPrefix(buf.String(), "aah.project:"))
+
+	sr := confProblemsSARIF(dir, lintAppConfigs(dir))
+	run := sr.Runs[0]
+	assert.Equal(t, 12, len(run.Results))
+	assert.Equal(t, 2, len(run.Tool.Driver.Rules))
+	assert.Equal(t, "config-warning", run.Results[0].RuleID)
+	assert.Equal(t, "warning", run.Results[0].Level)
+	assert.Equal(t, "aah.project", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 2, run.Results[0].Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Equal(t, "config/env/dev.conf", run.Results[7].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+
+	buf.Reset()
+	assert.Nil(t, sr.Write(buf))
+	assert.True(t, strings.Contains(buf.String(), `"version": "2.1.0"`))
+	assert.True(t, strings.Contains(buf.String(), `"ruleId": "config-error"`))
+}
+
+func TestConfigShow(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-config-show")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	files := map[string]string{
+		"aah.project": "build { binary_name = \"${AAH_TEST_BINARY:-myapp}\" }\n",
+		"config/aah.conf": "name = \"app\"\nserver { port = 8080, timeout { read = \"90s\" } }\n" +
+			"home = $server.port\nenv {\n  active = \"dev\"\n  include \"./env/*.conf\"\n}\n",
+		"config/env/prod.conf": "prod {\n  server.timeout.read = \"30s\"\n  log { level = \"warn\" }\n}\n",
+		"external.conf":        "server { port = ${AAH_TEST_PORT:-80} }\nhosts = [\"a\", \"b\"]\n",
+	}
+	for name, content := range files {
+		f := filepath.Join(dir, filepath.FromSlash(name))
+		assert.Nil(t, os.MkdirAll(filepath.Dir(f), 0755))
+		assert.Nil(t, ioutil.WriteFile(f, []byte(content), 0644))
+	}
+
+	tree, err := effectiveConfig(dir, "prod", filepath.Join(dir, "external.conf"))
+	assert.Nil(t, err)
+
+	for key, expected := range map[string]interface{}{
+		"project.build.binary_name": "myapp",
+		"app.env.active":            "prod",
+		"app.server.port":           int64(80),
+		"app.server.timeout.read":   "30s",
+		"app.log.level":             "warn",
+		"app.home":                  int64(80),
+	} {
+		v, found := tree.Get(key)
+		assert.True(t, found)
+		assert.Equal(t, expected, v)
+	}
+
+	buf := &bytes.Buffer{}
+	server, _ := tree.Get("app.server")
+	server.(confTree).writeHOCON(buf, 0)
+	assert.Equal(t, "port = 80\ntimeout {\n  read = \"30s\"\n}\n", buf.String())
+	assert.Equal(t, `["a", "b"]`, hoconValue(tree["app"].(confTree)["hosts"]))
+
+	_, err = effectiveConfig(dir, "qa", "")
+	assert.Equal(t, "environment profile 'qa' does not exists in 'config/aah.conf'", err.Error())
+}
+
+func TestConfigLetsEncrypt(t *testing.T) {
+	assert.NotNil(t, validateLEInput(nil, ""))
+	assert.NotNil(t, validateLEInput([]string{"*.example.com"}, ""))
+	assert.NotNil(t, validateLEInput([]string{"https://example.com"}, ""))
+	assert.NotNil(t, validateLEInput([]string{"localhost"}, ""))
+	assert.NotNil(t, validateLEInput([]string{"example.com"}, "admin"))
+	assert.Nil(t, validateLEInput([]string{"example.com", "www.example.com"}, "admin@example.com"))
+
+	defer func() { lookupHost = net.LookupHost }()
+	lookupHost = func(host string) ([]string, error) {
+		switch host {
+		case "example.com":
+			return []string{"93.184.216.34"}, nil
+		case "internal.example.com":
+			return []string{"10.0.0.5", "127.0.0.1"}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+	assert.Nil(t, checkDomainsDNS([]string{"example.com"}))
+	assert.Equal(t, []string{
+		"internal.example.com: resolves to non-public address(es) 10.0.0.5, 127.0.0.1",
+		"missing.example.com: DNS lookup failed: no such host",
+	}, checkDomainsDNS([]string{"internal.example.com", "missing.example.com"}))
+
+	values := letsEncryptConfValues([]string{"example.com", "www.example.com"}, "admin@example.com", "/var/lib/myapp/autocert", "443")
+	assert.Equal(t, `server {
+  port = "443"
+  ssl {
+    enable = true
+    lets_encrypt {
+      enable = true
+      host_policy = ["example.com", "www.example.com"]
+      email = "admin@example.com"
+      cache_dir = "/var/lib/myapp/autocert"
+    }
+  }
+}
+`, confSnippet(values))
+
+	src := `# Production
+prod {
+  server {
+    # keep this comment
+    port = 8080
+    ssl {
+      enable = false
+    }
+  }
+
+  log {
+    level = "warn"
+  }
+}
+`
+	var prefixed []*confValue
+	for _, v := range letsEncryptConfValues([]string{"example.com"}, "", "/srv/autocert", "443") {
+		prefixed = append(prefixed, &confValue{Path: "prod." + v.Path, Value: v.Value})
+	}
+	result, changes, err := upsertConfValues("prod.conf", []byte(src), prefixed)
+	assert.Nil(t, err)
+	assert.Equal(t, `# Production
+prod {
+  server {
+    # keep this comment
+    port = "443"
+    ssl {
+      lets_encrypt {
+        enable = true
+        host_policy = ["example.com"]
+        cache_dir = "/srv/autocert"
+      }
+      enable = true
+    }
+  }
+
+  log {
+    level = "warn"
+  }
+}
+`, string(result))
+	assert.Equal(t, []string{
+		`prod.conf:5: updated 'prod.server.port' to "443"`,
+		`prod.conf:7: updated 'prod.server.ssl.enable' to true`,
+		`prod.conf: added 'prod.server.ssl.lets_encrypt.enable = true'`,
+		`prod.conf: added 'prod.server.ssl.lets_encrypt.host_policy = ["example.com"]'`,
+		`prod.conf: added 'prod.server.ssl.lets_encrypt.cache_dir = "/srv/autocert"'`,
+	}, changes)
+
+	_, changes, err = upsertConfValues("prod.conf", result, prefixed)
+	assert.Nil(t, err)
+	assert.Nil(t, changes)
+}
@@ -0,0 +1,280 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// interceptorPrefixes are the controller interceptor method prefixes in the
+// execution order, e.g. 'Before' and 'Before<Action>'.
+var interceptorPrefixes = []string{"Before", "After", "Panic", "Finally"}
+
+type (
+	// appController is the controller of 'app/controllers', it's inspected
+	// from the Go source for route tooling. Name is the route controller
+	// name, it's prefixed with the sub package path e.g. 'v1/User'.
+	appController struct {
+		Name     string
+		Type     string
+		Package  string
+		Doc      string
+		File     string
+		Embedded []string
+		Methods  map[string]*appAction
+		byName   map[string]*appController
+	}
+
+	// appAction is the exported method of the controller, interceptors are
+	// actions too.
+	appAction struct {
+		Name   string
+		Doc    string
+		File   string
+		Line   int
+		Params []*appActionParam
+	}
+
+	// appActionParam is the action parameter with its Go type expression.
+	appActionParam struct {
+		Name string
+		Type string
+	}
+)
+
+// loadAppControllers method inspects the controllers of the directory and its
+// sub directories, test files are skipped. Controllers are keyed by route
+// controller name.
+func loadAppControllers(controllersDir string) (map[string]*appController, error) {
+	controllers := map[string]*appController{}
+	err := filepath.Walk(controllersDir, func(dir string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return err
+		}
+		if dir != controllersDir && (strings.HasPrefix(info.Name(), ".") || info.Name() == "testdata") {
+			return filepath.SkipDir
+		}
+
+		fset := token.NewFileSet()
+		pkgs, err := parser.ParseDir(fset, dir, func(f os.FileInfo) bool {
+			return !strings.HasSuffix(f.Name(), "_test.go")
+		}, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		rel, _ := filepath.Rel(controllersDir, dir)
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			rel = ""
+		}
+		for _, pkg := range pkgs {
+			inspectControllers(controllers, fset, pkg, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range controllers {
+		c.byName = controllers
+	}
+	return controllers, nil
+}
+
+// lookupController method returns the controller of route controller name,
+// name without package path matches the controller of any package.
+func lookupController(controllers map[string]*appController, name string) *appController {
+	if c, found := controllers[name]; found {
+		return c
+	}
+	if strings.Contains(name, "/") {
+		return nil
+	}
+
+	names := make([]string, 0, len(controllers))
+	for k := range controllers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		if controllers[k].Type == name {
+			return controllers[k]
+		}
+	}
+	return nil
+}
+
+// Action method returns the action of the controller including the embedded
+// controllers, nil if it's not found.
+func (c *appController) Action(name string) *appAction {
+	return c.action(name, 0)
+}
+
+// Interceptors method returns the interceptors of the action in execution
+// order: 'Before', 'Before<Action>', 'After<Action>', 'After',
+// 'Panic<Action>', 'Panic', 'Finally<Action>' and 'Finally'.
+func (c *appController) Interceptors(action string) []string {
+	var names []string
+	for _, prefix := range interceptorPrefixes {
+		candidates := []string{prefix, prefix + action}
+		if prefix != "Before" {
+			candidates = []string{prefix + action, prefix}
+		}
+		for _, name := range candidates {
+			if c.Action(name) != nil {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// Actions method returns the sorted action names of the controller and its
+// embedded controllers, interceptors are excluded.
+func (c *appController) Actions() []string {
+	seen := map[string]bool{}
+	var names []string
+	var collect func(ctrl *appController, depth int)
+	collect = func(ctrl *appController, depth int) {
+		for name := range ctrl.Methods {
+			if !seen[name] && !isInterceptorActioName(name) {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+		for _, e := range ctrl.Embedded {
+			if ec := ctrl.embedded(e); ec != nil && depth < 10 {
+				collect(ec, depth+1)
+			}
+		}
+	}
+	collect(c, 0)
+	sort.Strings(names)
+	return names
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func (c *appController) action(name string, depth int) *appAction {
+	if a, found := c.Methods[name]; found {
+		return a
+	}
+	if depth > 10 {
+		return nil
+	}
+	for _, e := range c.Embedded {
+		if ec := c.embedded(e); ec != nil {
+			if a := ec.action(name, depth+1); a != nil {
+				return a
+			}
+		}
+	}
+	return nil
+}
+
+// embedded method returns the embedded controller of the type name, type of
+// other package is matched by its name.
+func (c *appController) embedded(typeName string) *appController {
+	if idx := strings.LastIndex(typeName, "."); idx >= 0 {
+		typeName = typeName[idx+1:]
+		for _, ec := range c.byName {
+			if ec.Type == typeName && ec != c {
+				return ec
+			}
+		}
+		return nil
+	}
+
+	for _, ec := range c.byName {
+		if ec.Type == typeName && ec.Package == c.Package && ec != c {
+			return ec
+		}
+	}
+	return nil
+}
+
+// inspectControllers method collects the struct types with their exported
+// methods without results, types which don't have methods or embedded types
+// are not controllers.
+func inspectControllers(controllers map[string]*appController, fset *token.FileSet, pkg *ast.Package, rel string) {
+	types := map[string]*appController{}
+	fileNames := make([]string, 0, len(pkg.Files))
+	for name := range pkg.Files {
+		fileNames = append(fileNames, name)
+	}
+	sort.Strings(fileNames)
+
+	for _, fileName := range fileNames {
+		for _, decl := range pkg.Files[fileName].Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				ts := spec.(*ast.TypeSpec)
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok || !ts.Name.IsExported() {
+					continue
+				}
+
+				c := &appController{Type: ts.Name.Name, Package: rel, File: fileName, Methods: map[string]*appAction{}}
+				c.Name = strings.TrimPrefix(rel+"/"+c.Type, "/")
+				c.Doc = strings.TrimSpace(firstNonEmpty(ts.Doc.Text(), genDecl.Doc.Text()))
+				for _, f := range st.Fields.List {
+					if len(f.Names) == 0 {
+						c.Embedded = append(c.Embedded, strings.TrimPrefix(goExprString(fset, f.Type), "*"))
+					}
+				}
+				types[c.Type] = c
+			}
+		}
+	}
+
+	for _, fileName := range fileNames {
+		for _, decl := range pkg.Files[fileName].Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || !fn.Name.IsExported() || fn.Type.Results != nil {
+				continue
+			}
+
+			c := types[getName(fn.Recv.List[0].Type)]
+			if c == nil {
+				continue
+			}
+			a := &appAction{Name: fn.Name.Name, Doc: strings.TrimSpace(fn.Doc.Text()),
+				File: fileName, Line: fset.Position(fn.Pos()).Line}
+			for _, field := range fn.Type.Params.List {
+				for _, name := range field.Names {
+					a.Params = append(a.Params, &appActionParam{Name: name.Name, Type: goExprString(fset, field.Type)})
+				}
+			}
+			c.Methods[a.Name] = a
+		}
+	}
+
+	for _, c := range types {
+		if len(c.Methods) > 0 || len(c.Embedded) > 0 {
+			controllers[c.Name] = c
+		}
+	}
+}
+
+func goExprString(fset *token.FileSet, expr ast.Expr) string {
+	buf := &bytes.Buffer{}
+	_ = printer.Fprint(buf, fset, expr)
+	return buf.String()
+}
@@ -0,0 +1,20 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+var dbCmd = &command{
+	Name:      "db",
+	UsageLine: "aah db <subcommand> [arguments]",
+	Short:     "application database tools",
+	Long: `
+Tools for the application database, connection is read from app config same
+as 'aah migrate', refer 'aah help migrate'. Schema migrations are managed
+via 'aah migrate'.
+
+Example:
+    aah db seed
+    aah db seed -only=users,roles -profile=qa
+`,
+}
@@ -0,0 +1,225 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+// Seeds are SQL or Go files under 'db/seeds', seeds of the profile directory
+// 'db/seeds/<profile>' are run along with them. Go seed file belongs to the
+// package of its directory and declares the func 'Seed<Name>' of signature
+// 'func(*sql.Tx) error', for e.g.: '02_roles.go' declares 'Seed02Roles'.
+const seedsDir = "db/seeds"
+
+var (
+	dbSeedCmdFlags         = flag.NewFlagSet("seed", flag.ContinueOnError)
+	dbSeedProfileFlag      = dbSeedCmdFlags.String("profile", "", "Environment profile of the seeds and database config. Default is 'env.active' of app config")
+	dbSeedProfileShortFlag = dbSeedCmdFlags.String("p", "", "Environment profile of the seeds and database config. Default is 'env.active' of app config")
+	dbSeedOnlyFlag         = dbSeedCmdFlags.String("only", "", "Comma separated seed names to run, e.g: 01_users,02_roles. Default is all")
+	dbSeedCmd              = &command{
+		Name:      "seed",
+		UsageLine: "aah db seed [-p | -profile] [-only]",
+		Flags:     dbSeedCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "run database seed files of the profile",
+		Long: `
+Runs the seed files of 'db/seeds' and the profile directory
+'db/seeds/<profile>' in name order, each seed within a transaction. Seed of
+the profile directory takes the place of the seed with the same name.
+
+    db/seeds/01_roles.sql
+    db/seeds/dev/02_users.sql
+    db/seeds/dev/03_orders.go
+
+Seed is SQL file or Go file of the package of its directory, Go file
+declares the func 'Seed<Name>' of signature 'func(tx *sql.Tx) error', e.g.
+'03_orders.go' declares 'Seed03Orders'. Seed name is the file name without
+extension, select the seeds via '-only'.
+
+Seeds are not recorded, write them re-runnable, e.g. 'INSERT ... ON CONFLICT
+DO NOTHING'. Use global flag '-dry-run' to list the seeds without running.
+
+Example(s):
+    aah db seed
+
+    aah db seed -only=02_users -profile=qa
+`,
+	}
+
+	seedNameRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// dbSeed is the single seed, Go seed is referred via runner package alias
+// 'Pkg'.
+type dbSeed struct {
+	Name string
+	File string
+	Func string
+	Pkg  string
+}
+
+func dbSeedRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	profile := activeProfile(firstNonEmpty(*dbSeedProfileFlag, *dbSeedProfileShortFlag))
+	seeds, imports, err := loadDBSeeds(appProject.BaseDir, appProject.ImportPath, profile)
+	if err != nil {
+		fatalErr(newExitErr(exitMigrationError, err))
+		return
+	}
+	if seeds, err = selectDBSeeds(seeds, splitList(*dbSeedOnlyFlag)); err != nil {
+		fatalErr(newExitErr(exitUsage, err))
+		return
+	}
+	if len(seeds) == 0 {
+		log.Infof("No seeds found in '%s' for profile '%s'", seedsDir, profile)
+		return
+	}
+
+	if isDryRun() {
+		for _, sd := range seeds {
+			logDryRun("seed %s (%s)", sd.Name, relPath(appProject.BaseDir, sd.File))
+		}
+		return
+	}
+
+	r := openMigrationRunner(profile, &migrationRunnerData{Imports: imports, Seeds: seeds})
+	defer r.Close()
+	if err = r.Seed(seeds); err != nil {
+		r.Close()
+		fatalErr(newExitErr(exitMigrationError, err))
+		return
+	}
+	log.Infof("%d seed(s) completed", len(seeds))
+}
+
+// loadDBSeeds method reads the seeds of 'db/seeds' and the profile
+// directory sorted by name, it returns the seeds and the package imports
+// of Go seeds by alias.
+func loadDBSeeds(baseDir, importPath, profile string) ([]*dbSeed, map[string]string, error) {
+	byName := map[string]*dbSeed{}
+	imports := map[string]string{}
+	dirs := []struct{ Rel, Pkg string }{
+		{seedsDir, "seeds"},
+		{path.Join(seedsDir, profile), "profileseeds"},
+	}
+	for _, d := range dirs {
+		dir := filepath.Join(baseDir, filepath.FromSlash(d.Rel))
+		if !ess.IsFileExists(dir) {
+			continue
+		}
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		found := map[string]bool{}
+		for _, f := range files {
+			ext := filepath.Ext(f.Name())
+			name := strings.TrimSuffix(f.Name(), ext)
+			if f.IsDir() || (ext != ".sql" && ext != ".go") || strings.HasSuffix(name, "_test") || !seedNameRegex.MatchString(name) {
+				continue
+			}
+			if found[name] {
+				return nil, nil, fmt.Errorf("seed '%s' has both SQL and Go files in '%s'", name, d.Rel)
+			}
+			found[name] = true
+
+			sd := &dbSeed{Name: name, File: filepath.Join(dir, f.Name())}
+			if ext == ".go" {
+				if sd.Func, err = goSeedFunc(sd.File, name); err != nil {
+					return nil, nil, err
+				}
+				sd.Pkg = d.Pkg
+				imports[d.Pkg] = path.Join(importPath, d.Rel)
+			}
+			byName[name] = sd
+		}
+	}
+
+	seeds := make([]*dbSeed, 0, len(byName))
+	for _, sd := range byName {
+		seeds = append(seeds, sd)
+	}
+	sort.Slice(seeds, func(i, j int) bool { return seeds[i].Name < seeds[j].Name })
+
+	// overridden Go seed package is not referred
+	for alias := range imports {
+		used := false
+		for _, sd := range seeds {
+			used = used || sd.Pkg == alias
+		}
+		if !used {
+			delete(imports, alias)
+		}
+	}
+	return seeds, imports, nil
+}
+
+// goSeedFunc method returns the seed func name declared in the Go seed
+// file.
+func goSeedFunc(file, name string) (string, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), file, nil, 0)
+	if err != nil {
+		return "", err
+	}
+
+	fnName := "Seed" + toExported(name)
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == fnName {
+			return fnName, nil
+		}
+	}
+	return "", fmt.Errorf("%s: func '%s(tx *sql.Tx) error' is not declared", filepath.Base(file), fnName)
+}
+
+// selectDBSeeds method returns the seeds of given names in seeds order, all
+// seeds if names are empty.
+func selectDBSeeds(seeds []*dbSeed, names []string) ([]*dbSeed, error) {
+	if len(names) == 0 {
+		return seeds, nil
+	}
+
+	selected := map[string]bool{}
+	for _, n := range names {
+		selected[n] = true
+	}
+	var result []*dbSeed
+	for _, sd := range seeds {
+		if selected[sd.Name] {
+			result = append(result, sd)
+			delete(selected, sd.Name)
+		}
+	}
+	if len(selected) > 0 {
+		var unknown []string
+		for n := range selected {
+			unknown = append(unknown, n)
+		}
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("seed(s) '%s' not found, run 'aah -dry-run db seed' to list the seeds", strings.Join(unknown, "', '"))
+	}
+	return result, nil
+}
+
+func init() {
+	dbSeedCmd.Run = dbSeedRun
+	dbCmd.Subcommands = append(dbCmd.Subcommands, dbSeedCmd)
+}
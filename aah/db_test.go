@@ -0,0 +1,68 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestDBSeeds(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-db-seed")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	files := map[string]string{
+		"01_roles.sql":       "INSERT INTO roles (name) VALUES ('admin');",
+		"02_users.sql":       "INSERT INTO users (name) VALUES ('jeeva');",
+		"03_orders.go":       "package seeds\n\nimport \"database/sql\"\n\nfunc Seed03Orders(tx *sql.Tx) error { return nil }\n",
+		"README.md":          "seeds",
+		"dev/02_users.sql":   "INSERT INTO users (name) VALUES ('dev');",
+		"dev/04-fixtures.go": "package dev\n\nimport \"database/sql\"\n\nfunc Seed04Fixtures(tx *sql.Tx) error { return nil }\n",
+		"prod/01_roles.sql":  "-- prod roles",
+	}
+	seedsBase := filepath.Join(dir, filepath.FromSlash(seedsDir))
+	for name, content := range files {
+		file := filepath.Join(seedsBase, filepath.FromSlash(name))
+		assert.Nil(t, os.MkdirAll(filepath.Dir(file), permRWXRXRX))
+		assert.Nil(t, ioutil.WriteFile(file, []byte(content), permRWRR))
+	}
+
+	seeds, imports, err := loadDBSeeds(dir, "github.com/user/app", "dev")
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(seeds))
+	assert.Equal(t, "01_roles", seeds[0].Name)
+	assert.Equal(t, filepath.Join(seedsBase, "dev", "02_users.sql"), seeds[1].File)
+	assert.Equal(t, "Seed03Orders", seeds[2].Func)
+	assert.Equal(t, "seeds", seeds[2].Pkg)
+	assert.Equal(t, "Seed04Fixtures", seeds[3].Func)
+	assert.Equal(t, "profileseeds", seeds[3].Pkg)
+	assert.Equal(t, map[string]string{
+		"seeds":        "github.com/user/app/db/seeds",
+		"profileseeds": "github.com/user/app/db/seeds/dev",
+	}, imports)
+
+	seeds, imports, err = loadDBSeeds(dir, "github.com/user/app", "prod")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(seeds))
+	assert.Equal(t, filepath.Join(seedsBase, "prod", "01_roles.sql"), seeds[0].File)
+	assert.Equal(t, 1, len(imports))
+
+	selected, err := selectDBSeeds(seeds, []string{"03_orders", "01_roles"})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(selected))
+	assert.Equal(t, "01_roles", selected[0].Name)
+
+	_, err = selectDBSeeds(seeds, []string{"01_roles", "99_missing"})
+	assert.Equal(t, "seed(s) '99_missing' not found, run 'aah -dry-run db seed' to list the seeds", err.Error())
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(seedsBase, "05_tags.go"), []byte("package seeds\n"), permRWRR))
+	_, _, err = loadDBSeeds(dir, "github.com/user/app", "dev")
+	assert.Equal(t, "05_tags.go: func 'Seed05Tags(tx *sql.Tx) error' is not declared", err.Error())
+}
@@ -0,0 +1,283 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+var (
+	deployCmdFlags         = flag.NewFlagSet("deploy", flag.ContinueOnError)
+	deployProviderFlag     = deployCmdFlags.String("provider", "", "Cloud platform provider name")
+	deployArchiveFlag      = deployCmdFlags.String("archive", "", "Existing package archive (tar.gz) to deploy instead of packaging")
+	deployProfileFlag      = deployCmdFlags.String("profile", "", "Environment profile name of application. Default is 'prod'")
+	deployProfileShortFlag = deployCmdFlags.String("p", "", "Environment profile name of application. Default is 'prod'")
+	deployCmd              = &command{
+		Name:      "deploy",
+		UsageLine: "aah deploy -provider <name> [-archive] [-p | -profile]",
+		Flags:     deployCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "deploy aah application package to the targets",
+		Long: `
+Deploys the aah application package (refer 'aah package') to the cloud
+platform provider or to the targets of subcommand, configured in 'deploy'
+section of 'aah.project'.
+
+Provider translates the package into the platform artifact with 'Procfile'
+which starts the application on platform assigned '$PORT', then pushes it
+via platform CLI. Output is written into '<app-base>/.aah/deploy/<provider>.log'.
+
+    heroku             'heroku builds:create' source tarball, requires
+                       'heroku-builds' plugin and null buildpack
+    cloudfoundry       'cf push' zip with 'binary_buildpack'
+    elasticbeanstalk   'aws' CLI, zip source bundle uploaded to S3 bucket,
+                       new application version and environment update
+
+Providers are configured in 'deploy' section of 'aah.project', for e.g.:
+
+    deploy {
+      heroku {
+        app = "myapp"
+      }
+      cloudfoundry {
+        app = "myapp"
+        memory = "256M"
+        instances = 2
+      }
+      elasticbeanstalk {
+        application = "myapp"
+        environment = "myapp-prod"
+        bucket = "myapp-releases"
+        region = "us-east-1"
+      }
+    }
+
+Example:
+    aah deploy -provider=heroku
+
+    aah deploy -provider=elasticbeanstalk -archive=build/myapp-1.0.0-linux-amd64.tar.gz
+
+    aah deploy ssh -t=web1,web2
+`,
+	}
+)
+
+func deployRun(args []string) {
+	if ess.IsStrEmpty(*deployProviderFlag) {
+		deployCmd.Usage()
+		return
+	}
+
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	provider, found := deployProviders[*deployProviderFlag]
+	if !found {
+		fatalExitf(exitUsage, "Unsupported deploy provider '%s', choose one of %s",
+			*deployProviderFlag, strings.Join(deployProviderNames(), ", "))
+		return
+	}
+
+	buildCfg := appProject.BuildCfg
+	profile := firstNonEmpty(*deployProfileFlag, *deployProfileShortFlag, "prod")
+	archive, err := deployArchive(*deployArchiveFlag, profile)
+	if err != nil {
+		fatalErr(err)
+		return
+	}
+
+	artifact, err := createProviderArtifact(archive, provider.Format(), profile)
+	if err != nil {
+		fatalf("Unable to create deploy artifact: %s", err)
+		return
+	}
+
+	w, closeFn, err := deployTargetWriter(provider.Name())
+	if err != nil {
+		fatal(err)
+		return
+	}
+	defer closeFn()
+
+	err = provider.Deploy(w, &deployInfo{
+		Artifact: artifact,
+		Version:  getAppVersion(appProject.BaseDir, buildCfg),
+		Cfg:      buildCfg,
+	})
+	if err != nil {
+		fatalExitf(exitDeployError, "Deploy failed on provider '%s': %s", provider.Name(), err)
+		return
+	}
+
+	log.Infof("Deploy successful, provider '%s'", provider.Name())
+}
+
+type deployTarget struct {
+	Name         string
+	Host         string
+	Port         string
+	User         string
+	IdentityFile string
+	Dir          string
+	Restart      string
+	Keep         int
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// deployArchive method returns the given package archive or creates the
+// package for 'deploy.os' and 'deploy.arch'.
+func deployArchive(archive, profile string) (string, error) {
+	if !ess.IsStrEmpty(archive) {
+		archive, _ = filepath.Abs(archive)
+		if !strings.HasSuffix(archive, "."+packageFormatTarGz) {
+			return "", newExitErr(exitUsage, fmt.Errorf("package archive '%s' is not a tar.gz", archive))
+		}
+		if !ess.IsFileExists(archive) {
+			return "", newExitErr(exitUsage, fmt.Errorf("package archive '%s' does not exists", archive))
+		}
+
+		scanner, err := newSecretScanner(appProject.BuildCfg)
+		if err != nil {
+			return "", newExitErr(exitConfigError, err)
+		}
+		findings, err := scanner.ScanArchive(archive)
+		if err != nil {
+			return "", fmt.Errorf("unable to scan package archive '%s': %s", archive, err)
+		}
+		return archive, scanner.Report(findings)
+	}
+
+	buildCfg := appProject.BuildCfg
+	return createPackage(&packageOptions{
+		OS:      buildCfg.StringDefault("deploy.os", "linux"),
+		Arch:    buildCfg.StringDefault("deploy.arch", "amd64"),
+		Format:  packageFormatTarGz,
+		Profile: profile,
+	})
+}
+
+// deployTargets method returns the targets from 'deploy.targets' section of
+// 'aah.project' sorted by name. If names are given then only those targets
+// are returned in the given order.
+func deployTargets(buildCfg *config.Config, names []string) ([]*deployTarget, error) {
+	targetsCfg, found := buildCfg.GetSubConfig("deploy.targets")
+	if !found || len(targetsCfg.Keys()) == 0 {
+		return nil, fmt.Errorf("'deploy.targets' is not configured in 'aah.project'")
+	}
+
+	if len(names) == 0 {
+		names = targetsCfg.Keys()
+		sort.Strings(names)
+	}
+
+	keep := buildCfg.IntDefault("deploy.keep_releases", 5)
+	var targets []*deployTarget
+	for _, name := range names {
+		if !targetsCfg.IsExists(name) {
+			return nil, fmt.Errorf("'deploy.targets.%s' does not exists", name)
+		}
+
+		t := &deployTarget{
+			Name:         name,
+			Host:         targetsCfg.StringDefault(name+".host", ""),
+			Port:         targetsCfg.StringDefault(name+".port", "22"),
+			User:         targetsCfg.StringDefault(name+".user", ""),
+			IdentityFile: targetsCfg.StringDefault(name+".identity_file", ""),
+			Dir:          targetsCfg.StringDefault(name+".dir", ""),
+			Restart:      targetsCfg.StringDefault(name+".restart", ""),
+			Keep:         targetsCfg.IntDefault(name+".keep_releases", keep),
+		}
+		if t.Keep < 1 {
+			t.Keep = 1
+		}
+		if ess.IsStrEmpty(t.Host) || ess.IsStrEmpty(t.Dir) {
+			return nil, fmt.Errorf("'deploy.targets.%s': 'host' and 'dir' are required", name)
+		}
+		targets = append(targets, t)
+	}
+
+	return targets, nil
+}
+
+// Address method returns the SSH address 'user@host' of the target.
+func (t *deployTarget) Address() string {
+	if ess.IsStrEmpty(t.User) {
+		return t.Host
+	}
+	return t.User + "@" + t.Host
+}
+
+// deployLogFile method creates the per target deploy log file
+// '<app-base>/.aah/deploy/<target>.log'.
+func deployLogFile(baseDir, name string) (io.WriteCloser, error) {
+	if isDryRun() {
+		return nopWriteCloser{ioutil.Discard}, nil
+	}
+
+	dir := filepath.Join(baseDir, ".aah", "deploy")
+	if err := ess.MkDirAll(dir, permRWXRXRX); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(filepath.Join(dir, name+".log"), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, permRWRR)
+}
+
+// deployTargetWriter method returns the writer of target output, it writes
+// to stdout with target name prefix and to the target log file. Call the
+// returned func once the target is completed.
+func deployTargetWriter(name string) (io.Writer, func(), error) {
+	logFile, err := deployLogFile(appProject.BaseDir, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	w := io.MultiWriter(newPrefixWriter("["+name+"] ", os.Stdout, &sync.Mutex{}), logFile)
+	return w, func() { _ = logFile.Close() }, nil
+}
+
+// runDeployCmd method runs the command and writes its output into given
+// writer, in dry-run mode it just logs.
+func runDeployCmd(w io.Writer, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	if isDryRun() {
+		logDryRun("execute %s", strings.Join(cmd.Args, " "))
+		return nil
+	}
+	log.Debug("Executing ", strings.Join(cmd.Args, " "))
+
+	cmd.Stdout = w
+	cmd.Stderr = w
+	return cmd.Run()
+}
+
+// shellQuote method quotes the value for POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func init() {
+	deployCmd.Run = deployRun
+}
@@ -0,0 +1,244 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+)
+
+// deployProvider is the cloud platform deploy adapter of 'aah deploy
+// -provider'. Provider receives the artifact translated from the package
+// archive in its 'Format' and pushes it to the platform.
+type deployProvider interface {
+	// Name returns the provider name used in '-provider' flag and
+	// 'deploy.<name>' section of 'aah.project'.
+	Name() string
+
+	// Format returns the artifact archive format 'tar.gz' or 'zip'.
+	Format() string
+
+	// Deploy pushes the artifact to the platform.
+	Deploy(w io.Writer, d *deployInfo) error
+}
+
+// deployInfo holds the provider artifact and configuration.
+type deployInfo struct {
+	Artifact string
+	Version  string
+	Cfg      *config.Config
+}
+
+var deployProviders = map[string]deployProvider{}
+
+// registerDeployProvider method adds the provider to the registry, it's
+// called from provider's init.
+func registerDeployProvider(p deployProvider) {
+	deployProviders[p.Name()] = p
+}
+
+func deployProviderNames() []string {
+	var names []string
+	for name := range deployProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// createProviderArtifact method translates the package archive into the
+// provider artifact. Package directory prefix is stripped, so the application
+// is at the artifact root, and the 'Procfile' is added which starts the
+// binary on platform assigned '$PORT'.
+func createProviderArtifact(archive, format, profile string) (string, error) {
+	artifact := strings.TrimSuffix(archive, "."+packageFormatTarGz) + "-deploy." + format
+	if isDryRun() {
+		logDryRun("create deploy artifact %s from %s", artifact, archive)
+		return artifact, nil
+	}
+
+	files, err := readPackageArchive(archive)
+	if err != nil {
+		return "", err
+	}
+
+	binary := ""
+	for _, f := range files {
+		if idx := strings.Index(f.Name, "/"); idx >= 0 {
+			f.Name = f.Name[idx+1:]
+		}
+		if strings.HasPrefix(f.Name, "bin/") {
+			binary = f.Name[len("bin/"):]
+		}
+	}
+	if ess.IsStrEmpty(binary) {
+		return "", fmt.Errorf("application binary not found in package '%s'", archive)
+	}
+
+	files = append(files, &packageFile{
+		Name:    "Procfile",
+		Content: []byte(fmt.Sprintf("web: %s=$PORT ./bin/%s -profile=%s\n", appPortEnv, binary, profile)),
+		Mode:    permRWRR,
+	})
+
+	return artifact, writePackageArchive(artifact, format, files)
+}
+
+// readPackageArchive method reads the files of 'tar.gz' package archive.
+func readPackageArchive(archive string) ([]*packageFile, error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer ess.CloseQuietly(f)
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*packageFile
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeRegA {
+			continue
+		}
+
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &packageFile{Name: hdr.Name, Content: content, Mode: os.FileMode(hdr.Mode)})
+	}
+
+	return files, nil
+}
+
+// requiredCfg method returns the provider config value, error if it's empty.
+func requiredCfg(cfg *config.Config, key string) (string, error) {
+	v := cfg.StringDefault(key, "")
+	if ess.IsStrEmpty(v) {
+		return "", fmt.Errorf("'%s' is not configured in 'aah.project'", key)
+	}
+	return v, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Heroku provider
+//___________________________________
+
+// herokuProvider deploys the source tarball via 'heroku builds:create',
+// requires 'heroku-builds' CLI plugin and null buildpack on the app.
+type herokuProvider struct{}
+
+func (herokuProvider) Name() string   { return "heroku" }
+func (herokuProvider) Format() string { return packageFormatTarGz }
+
+func (herokuProvider) Deploy(w io.Writer, d *deployInfo) error {
+	app, err := requiredCfg(d.Cfg, "deploy.heroku.app")
+	if err != nil {
+		return err
+	}
+	return runDeployCmd(w, "heroku", "builds:create", "--source-tar", d.Artifact, "-a", app)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Cloud Foundry provider
+//___________________________________
+
+// cloudFoundryProvider pushes the zip artifact with binary buildpack via
+// 'cf push'.
+type cloudFoundryProvider struct{}
+
+func (cloudFoundryProvider) Name() string   { return "cloudfoundry" }
+func (cloudFoundryProvider) Format() string { return packageFormatZip }
+
+func (cloudFoundryProvider) Deploy(w io.Writer, d *deployInfo) error {
+	app, err := requiredCfg(d.Cfg, "deploy.cloudfoundry.app")
+	if err != nil {
+		return err
+	}
+
+	args := []string{"push", app, "-p", d.Artifact, "-b", "binary_buildpack"}
+	if memory := d.Cfg.StringDefault("deploy.cloudfoundry.memory", ""); !ess.IsStrEmpty(memory) {
+		args = append(args, "-m", memory)
+	}
+	if instances := d.Cfg.StringDefault("deploy.cloudfoundry.instances", ""); !ess.IsStrEmpty(instances) {
+		args = append(args, "-i", instances)
+	}
+	return runDeployCmd(w, "cf", args...)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Elastic Beanstalk provider
+//___________________________________
+
+// elasticBeanstalkProvider uploads the zip source bundle to S3, creates the
+// application version and updates the environment via 'aws' CLI.
+type elasticBeanstalkProvider struct{}
+
+func (elasticBeanstalkProvider) Name() string   { return "elasticbeanstalk" }
+func (elasticBeanstalkProvider) Format() string { return packageFormatZip }
+
+func (elasticBeanstalkProvider) Deploy(w io.Writer, d *deployInfo) error {
+	var app, env, bucket string
+	var err error
+	if app, err = requiredCfg(d.Cfg, "deploy.elasticbeanstalk.application"); err != nil {
+		return err
+	}
+	if env, err = requiredCfg(d.Cfg, "deploy.elasticbeanstalk.environment"); err != nil {
+		return err
+	}
+	if bucket, err = requiredCfg(d.Cfg, "deploy.elasticbeanstalk.bucket"); err != nil {
+		return err
+	}
+
+	var region []string
+	if r := d.Cfg.StringDefault("deploy.elasticbeanstalk.region", ""); !ess.IsStrEmpty(r) {
+		region = []string{"--region", r}
+	}
+
+	key := app + "/" + filepath.Base(d.Artifact)
+	if err = runDeployCmd(w, "aws", append([]string{"s3", "cp", d.Artifact,
+		"s3://" + bucket + "/" + key}, region...)...); err != nil {
+		return err
+	}
+
+	if err = runDeployCmd(w, "aws", append([]string{"elasticbeanstalk", "create-application-version",
+		"--application-name", app, "--version-label", d.Version,
+		"--source-bundle", "S3Bucket=" + bucket + ",S3Key=" + key}, region...)...); err != nil {
+		return err
+	}
+
+	return runDeployCmd(w, "aws", append([]string{"elasticbeanstalk", "update-environment",
+		"--environment-name", env, "--version-label", d.Version}, region...)...)
+}
+
+func init() {
+	registerDeployProvider(herokuProvider{})
+	registerDeployProvider(cloudFoundryProvider{})
+	registerDeployProvider(elasticBeanstalkProvider{})
+}
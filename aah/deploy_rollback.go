@@ -0,0 +1,104 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+var (
+	deployRollbackCmdFlags        = flag.NewFlagSet("rollback", flag.ContinueOnError)
+	deployRollbackTargetFlag      = deployRollbackCmdFlags.String("target", "", "Comma separated target names. Default is all targets")
+	deployRollbackTargetShortFlag = deployRollbackCmdFlags.String("t", "", "Comma separated target names. Default is all targets")
+	deployRollbackToFlag          = deployRollbackCmdFlags.String("to", "", "Release version to switch. Default is the previous release")
+	deployRollbackCmd             = &command{
+		Name:      "rollback",
+		UsageLine: "aah deploy rollback [-t | -target] [-to]",
+		Flags:     deployRollbackCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "switch SSH targets back to the previous release",
+		Long: `
+Switches the 'current' symlink of the SSH targets (refer 'aah deploy ssh')
+back to the release deployed before the current one, or to the given
+version with '-to', and executes the 'restart' command.
+
+Deploy keeps the last 'keep_releases' unpacked in '<dir>/releases', only
+those releases are available for rollback. Available releases are printed
+if the release is not found.
+
+Example:
+    aah deploy rollback
+
+    aah deploy rollback -t=web1 -to=v1.2.0
+`,
+	}
+)
+
+func deployRollbackRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	targets, err := deployTargets(appProject.BuildCfg,
+		splitList(firstNonEmpty(*deployRollbackTargetFlag, *deployRollbackTargetShortFlag)))
+	if err != nil {
+		fatalErr(newExitErr(exitConfigError, err))
+		return
+	}
+
+	for _, t := range targets {
+		if err = rollbackSSHTarget(t, *deployRollbackToFlag); err != nil {
+			fatalExitf(exitDeployError, "Rollback failed on target '%s': %s", t.Name, err)
+			return
+		}
+	}
+
+	log.Infof("Rollback successful, %d target(s)", len(targets))
+}
+
+// rollbackSSHTarget method switches the target to given release version,
+// empty version means the previous release.
+func rollbackSSHTarget(t *deployTarget, version string) error {
+	w, closeFn, err := deployTargetWriter(t.Name)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	fmt.Fprintf(w, "Rolling back %s:%s %s\n", t.Address(), t.Dir, version)
+	return runDeployCmd(w, "ssh", sshArgs(t, deployRollbackScript(t, version))...)
+}
+
+// deployRollbackScript method returns the remote shell script which finds
+// the release and swaps the 'current' symlink. Previous release is the next
+// one after current in deploy order 'ls -t'.
+func deployRollbackScript(t *deployTarget, version string) string {
+	find := `release="$(ls -1t releases | awk -v cur="$(basename "$(readlink current)")" 'found { print; exit } $0 == cur { found = 1 }')"`
+	if !ess.IsStrEmpty(version) {
+		find = `release="$(ls -1t releases | grep -F -- ` + shellQuote("-"+version+"-") + ` | head -n 1)"`
+	}
+
+	lines := []string{
+		"set -e",
+		"cd " + shellQuote(t.Dir),
+		find,
+		`if [ -z "$release" ] || [ ! -d "releases/$release" ]; then`,
+		`  echo "release not found, available releases:"; ls -1t releases; exit 1`,
+		"fi",
+		`ln -sfn "releases/$release" current.tmp`,
+		"mv -Tf current.tmp current",
+		`echo "current -> releases/$release"`,
+	}
+	return strings.Join(append(lines, restartLines(t)...), "\n")
+}
+
+func init() {
+	deployRollbackCmd.Run = deployRollbackRun
+	deployCmd.Subcommands = append(deployCmd.Subcommands, deployRollbackCmd)
+}
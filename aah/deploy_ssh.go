@@ -0,0 +1,221 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+var (
+	deploySSHCmdFlags         = flag.NewFlagSet("ssh", flag.ContinueOnError)
+	deploySSHTargetFlag       = deploySSHCmdFlags.String("target", "", "Comma separated target names. Default is all targets")
+	deploySSHTargetShortFlag  = deploySSHCmdFlags.String("t", "", "Comma separated target names. Default is all targets")
+	deploySSHArchiveFlag      = deploySSHCmdFlags.String("archive", "", "Existing package archive (tar.gz) to deploy instead of packaging")
+	deploySSHProfileFlag      = deploySSHCmdFlags.String("profile", "", "Environment profile name of startup script. Default is 'prod'")
+	deploySSHProfileShortFlag = deploySSHCmdFlags.String("p", "", "Environment profile name of startup script. Default is 'prod'")
+	deploySSHRollbackFlag     = deploySSHCmdFlags.Bool("rollback", false, "Switch back to the previous release, same as 'aah deploy rollback'")
+	deploySSHCmd              = &command{
+		Name:      "ssh",
+		UsageLine: "aah deploy ssh [-t | -target] [-archive] [-p | -profile] [-rollback]",
+		Flags:     deploySSHCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "deploy package to the SSH targets",
+		Long: `
+Packages the application for the targets platform (refer 'aah package') and
+deploys it to the SSH targets one by one, stops at the first failure. It uses
+'ssh' and 'scp' commands, so the SSH agent and '~/.ssh/config' are honored.
+
+On each target:
+    1. Package is copied into '<dir>/releases' and unpacked, secrets of
+       'aah secrets' are copied as 'secrets.env' of the release
+    2. 'current' symlink is swapped to the new release
+    3. 'restart' command is executed, if configured
+    4. Releases other than last 'keep_releases' are removed
+
+Rollback with '-rollback' switches 'current' symlink back to the previous
+release and executes the 'restart' command, refer 'aah deploy rollback'.
+
+Output of every target is prefixed with the target name and written into
+'<app-base>/.aah/deploy/<target>.log'.
+
+Targets are configured in 'deploy' section of 'aah.project', for e.g.:
+
+    deploy {
+      # Targets platform. Default is 'linux' and 'amd64'.
+      os = "linux"
+      arch = "amd64"
+
+      targets {
+        web1 {
+          host = "web1.example.com"
+          port = 22
+          user = "deploy"
+          identity_file = "~/.ssh/deploy_rsa"
+          dir = "/opt/myapp"
+          restart = "sudo systemctl restart myapp"
+
+          # Default is 'deploy.keep_releases' or 5.
+          keep_releases = 5
+        }
+      }
+    }
+
+Example:
+    aah deploy ssh
+
+    aah deploy ssh -t=web1 -p=qa
+
+    aah deploy ssh -t=web1,web2 -rollback
+`,
+	}
+)
+
+func deploySSHRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	buildCfg := appProject.BuildCfg
+	targets, err := deployTargets(buildCfg,
+		splitList(firstNonEmpty(*deploySSHTargetFlag, *deploySSHTargetShortFlag)))
+	if err != nil {
+		fatalErr(newExitErr(exitConfigError, err))
+		return
+	}
+
+	archive := ""
+	var secrets []string
+	if !*deploySSHRollbackFlag {
+		archive, err = deployArchive(*deploySSHArchiveFlag, firstNonEmpty(*deploySSHProfileFlag, *deploySSHProfileShortFlag))
+		if err != nil {
+			fatalErr(err)
+			return
+		}
+		if secrets, err = secretsEnv(appProject.BaseDir); err != nil {
+			fatalErr(newExitErr(exitConfigError, err))
+			return
+		}
+	}
+
+	for _, t := range targets {
+		if *deploySSHRollbackFlag {
+			err = rollbackSSHTarget(t, "")
+		} else {
+			err = deploySSHTarget(t, archive, secrets)
+		}
+		if err != nil {
+			fatalExitf(exitDeployError, "Deploy failed on target '%s': %s", t.Name, err)
+			return
+		}
+	}
+
+	log.Infof("Deploy successful, %d target(s)", len(targets))
+}
+
+// deploySSHTarget method deploys the archive to given target, secrets are
+// uploaded as 'secrets.env' of the release.
+func deploySSHTarget(t *deployTarget, archive string, secrets []string) error {
+	w, closeFn, err := deployTargetWriter(t.Name)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	fmt.Fprintf(w, "Deploying %s to %s:%s\n", filepath.Base(archive), t.Address(), t.Dir)
+	releasesDir := path.Join(t.Dir, "releases")
+	if err = runDeployCmd(w, "ssh", sshArgs(t, "mkdir -p "+shellQuote(releasesDir))...); err != nil {
+		return err
+	}
+
+	if err = runDeployCmd(w, "scp", scpArgs(t, archive, releasesDir)...); err != nil {
+		return err
+	}
+
+	if len(secrets) > 0 {
+		if err = uploadSecretsEnv(w, t, archive, secrets); err != nil {
+			return err
+		}
+	}
+
+	return runDeployCmd(w, "ssh", sshArgs(t, deploySwapScript(t, filepath.Base(archive)))...)
+}
+
+// uploadSecretsEnv method uploads the decrypted secrets next to the archive
+// via temporary file, it's readable only by the owner.
+func uploadSecretsEnv(w io.Writer, t *deployTarget, archive string, secrets []string) error {
+	tmpDir, err := ioutil.TempDir("", "aah-deploy-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	file := filepath.Join(tmpDir, strings.TrimSuffix(filepath.Base(archive), "."+packageFormatTarGz)+"."+secretsEnvFile)
+	if err = ioutil.WriteFile(file, secretsEnvContent(secrets), 0600); err != nil {
+		return err
+	}
+	return runDeployCmd(w, "scp", scpArgs(t, file, path.Join(t.Dir, "releases"))...)
+}
+
+func sshArgs(t *deployTarget, script string) []string {
+	args := []string{"-p", t.Port, "-o", "BatchMode=yes"}
+	if !ess.IsStrEmpty(t.IdentityFile) {
+		args = append(args, "-i", t.IdentityFile)
+	}
+	return append(args, t.Address(), script)
+}
+
+func scpArgs(t *deployTarget, src, dstDir string) []string {
+	args := []string{"-P", t.Port, "-o", "BatchMode=yes"}
+	if !ess.IsStrEmpty(t.IdentityFile) {
+		args = append(args, "-i", t.IdentityFile)
+	}
+	return append(args, src, t.Address()+":"+dstDir+"/")
+}
+
+// deploySwapScript method returns the remote shell script which unpacks
+// the archive, swaps the 'current' symlink atomically and removes the old
+// releases. Release directory is touched, so 'ls -t' gives deploy order.
+func deploySwapScript(t *deployTarget, archiveName string) string {
+	release := "releases/" + strings.TrimSuffix(archiveName, "."+packageFormatTarGz)
+	lines := []string{
+		"set -e",
+		"cd " + shellQuote(t.Dir),
+		"tar -xzf " + shellQuote("releases/"+archiveName) + " -C releases",
+		"rm -f " + shellQuote("releases/"+archiveName),
+		"if [ -f " + shellQuote(release+"."+secretsEnvFile) + " ]; then mv -f " +
+			shellQuote(release+"."+secretsEnvFile) + " " + shellQuote(release+"/"+secretsEnvFile) + "; fi",
+		"touch " + shellQuote(release),
+		"ln -sfn " + shellQuote(release) + " current.tmp",
+		"mv -Tf current.tmp current",
+		`echo "current -> $(readlink current)"`,
+	}
+	lines = append(lines, restartLines(t)...)
+	return strings.Join(append(lines,
+		fmt.Sprintf(`ls -1t releases | tail -n +%d | while read r; do`, t.Keep+1),
+		`  if [ "releases/$r" != "$(readlink current)" ]; then echo "Removing release $r"; rm -rf "releases/$r"; fi`,
+		"done",
+	), "\n")
+}
+
+func restartLines(t *deployTarget) []string {
+	if ess.IsStrEmpty(t.Restart) {
+		return nil
+	}
+	return []string{"echo " + shellQuote("Restarting: "+t.Restart), t.Restart}
+}
+
+func init() {
+	deploySSHCmd.Run = deploySSHRun
+	deployCmd.Subcommands = append(deployCmd.Subcommands, deploySSHCmd)
+}
@@ -0,0 +1,101 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestDeployUsage(t *testing.T) {
+	defer func() { exit = os.Exit }()
+	var exitCode int
+	exit = func(code int) { exitCode = code }
+
+	*deployProviderFlag = ""
+	deployRun(nil)
+	assert.Equal(t, exitUsage, exitCode)
+}
+
+func TestDeployShellQuote(t *testing.T) {
+	assert.Equal(t, "'/opt/my app'", shellQuote("/opt/my app"))
+	assert.Equal(t, `'it'\''s'`, shellQuote("it's"))
+}
+
+func TestDeploySSHArgs(t *testing.T) {
+	target := &deployTarget{Name: "web1", Host: "web1.example.com", Port: "2222", User: "deploy",
+		IdentityFile: "/keys/deploy_rsa", Dir: "/opt/myapp"}
+
+	assert.Equal(t, []string{"-p", "2222", "-o", "BatchMode=yes", "-i", "/keys/deploy_rsa",
+		"deploy@web1.example.com", "uptime"}, sshArgs(target, "uptime"))
+	assert.Equal(t, []string{"-P", "2222", "-o", "BatchMode=yes", "-i", "/keys/deploy_rsa",
+		"/build/app.tar.gz", "deploy@web1.example.com:/opt/myapp/releases/"},
+		scpArgs(target, "/build/app.tar.gz", "/opt/myapp/releases"))
+
+	target.User, target.IdentityFile = "", ""
+	assert.Equal(t, "web1.example.com", target.Address())
+}
+
+func TestDeploySSHScripts(t *testing.T) {
+	target := &deployTarget{Name: "web1", Host: "web1", Dir: "/opt/myapp", Restart: "sudo systemctl restart myapp", Keep: 3}
+
+	script := deploySwapScript(target, "myapp-1.0.0-linux-amd64.tar.gz")
+	assert.True(t, strings.HasPrefix(script, "set -e\ncd '/opt/myapp'\n"))
+	assert.True(t, strings.Contains(script, "tar -xzf 'releases/myapp-1.0.0-linux-amd64.tar.gz' -C releases\n"))
+	assert.True(t, strings.Contains(script, "mv -f 'releases/myapp-1.0.0-linux-amd64.secrets.env' 'releases/myapp-1.0.0-linux-amd64/secrets.env'"))
+	assert.True(t, strings.Contains(script, "ln -sfn 'releases/myapp-1.0.0-linux-amd64' current.tmp\nmv -Tf current.tmp current\n"))
+	assert.True(t, strings.Contains(script, "\nsudo systemctl restart myapp\n"))
+	assert.True(t, strings.Contains(script, "ls -1t releases | tail -n +4 | while read r; do\n"))
+
+	target.Restart = ""
+	script = deployRollbackScript(target, "")
+	assert.True(t, strings.Contains(script, "$0 == cur { found = 1 }"))
+	assert.True(t, strings.HasSuffix(script, `echo "current -> releases/$release"`))
+
+	script = deployRollbackScript(target, "v1.0.0")
+	assert.True(t, strings.Contains(script, "grep -F -- '-v1.0.0-' | head -n 1"))
+}
+
+func TestDeployProviderArtifact(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-deploy")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	archive := filepath.Join(dir, "myapp-1.0.0-linux-amd64.tar.gz")
+	assert.Nil(t, writePackageArchive(archive, packageFormatTarGz, []*packageFile{
+		{Name: "myapp-1.0.0-linux-amd64/bin/myapp", Content: []byte("binary"), Mode: permRWXRXRX},
+		{Name: "myapp-1.0.0-linux-amd64/config/aah.conf", Content: []byte(""), Mode: permRWRR},
+	}))
+
+	artifact, err := createProviderArtifact(archive, packageFormatZip, "prod")
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(dir, "myapp-1.0.0-linux-amd64-deploy.zip"), artifact)
+
+	zr, err := zip.OpenReader(artifact)
+	assert.Nil(t, err)
+	defer func() { _ = zr.Close() }()
+
+	names := map[string]*zip.File{}
+	for _, f := range zr.File {
+		names[f.Name] = f
+	}
+	assert.NotNil(t, names["bin/myapp"])
+	assert.NotNil(t, names["config/aah.conf"])
+	assert.Equal(t, "-rwxr-xr-x", names["bin/myapp"].Mode().String())
+
+	rc, err := names["Procfile"].Open()
+	assert.Nil(t, err)
+	procfile, _ := ioutil.ReadAll(rc)
+	_ = rc.Close()
+	assert.Equal(t, "web: AAH_SERVER_PORT=$PORT ./bin/myapp -profile=prod\n", string(procfile))
+
+	assert.Equal(t, []string{"cloudfoundry", "elasticbeanstalk", "heroku"}, deployProviderNames())
+}
@@ -0,0 +1,138 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var depsCmd = &command{
+	Name:      "deps",
+	UsageLine: "aah deps <subcommand> [arguments]",
+	Short:     "inspect application dependencies",
+	Long: `
+Inspects the third-party dependencies of the application, i.e. non standard
+library packages imported by 'app' packages directly or transitively.
+
+Example:
+    aah deps licenses
+`,
+}
+
+// appDependency is the third-party dependency of the application, packages
+// of the same repository are one dependency. Import path of vendored
+// dependency is without the vendor prefix.
+type appDependency struct {
+	ImportPath string
+	Dir        string
+	Packages   []string
+}
+
+// appDependencies method returns the dependencies of the application import
+// path sorted by import path. It uses go list command.
+//
+//	go list -f '{{ join .Deps "\n" }}' aah-app/import/path/app/...
+func appDependencies(appImportPath string) ([]*appDependency, error) {
+	output, err := goListOutput("-f", `{{ join .Deps "\n" }}`, path.Join(appImportPath, "app", "..."))
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var pkgs []string
+	for _, p := range strings.Fields(output) {
+		if !seen[p] && p != "C" && !strings.HasPrefix(p, appImportPath+"/") {
+			seen[p] = true
+			pkgs = append(pkgs, p)
+		}
+	}
+	if len(pkgs) == 0 {
+		return nil, nil
+	}
+
+	output, err = goListOutput(append([]string{"-f", "{{ if not .Standard }}{{ .ImportPath }}|{{ .Dir }}{{ end }}"}, pkgs...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	deps := map[string]*appDependency{}
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(strings.TrimSpace(line), "|", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		importPath := parts[0]
+		if idx := strings.LastIndex(importPath, "/vendor/"); idx >= 0 {
+			importPath = importPath[idx+len("/vendor/"):]
+		}
+		root, dir := dependencyRoot(importPath, parts[1])
+		if deps[root] == nil {
+			deps[root] = &appDependency{ImportPath: root, Dir: dir}
+		}
+		deps[root].Packages = append(deps[root].Packages, importPath)
+	}
+
+	result := make([]*appDependency, 0, len(deps))
+	for _, d := range deps {
+		sort.Strings(d.Packages)
+		result = append(result, d)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ImportPath < result[j].ImportPath })
+	return result, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// dependencyRoot method returns the repository root import path and
+// directory of the package. Nearest parent directory having the license file
+// is the root, otherwise it's derived from the well-known hosts e.g.
+// 'github.com/<owner>/<repo>'.
+func dependencyRoot(importPath, dir string) (string, string) {
+	for ip, d := importPath, dir; strings.Contains(ip, "/"); ip, d = path.Dir(ip), filepath.Dir(d) {
+		if len(findLicenseFiles(d)) > 0 {
+			return ip, d
+		}
+	}
+
+	parts := strings.Split(importPath, "/")
+	n := 1
+	switch parts[0] {
+	case "github.com", "gitlab.com", "bitbucket.org", "golang.org":
+		n = 3
+	case "google.golang.org":
+		n = 2
+	case "gopkg.in":
+		n = 2
+		if len(parts) > 2 && !strings.Contains(parts[1], ".v") {
+			n = 3
+		}
+	}
+	if n > len(parts) {
+		n = len(parts)
+	}
+	for i := len(parts); i > n; i-- {
+		dir = filepath.Dir(dir)
+	}
+	return strings.Join(parts[:n], "/"), dir
+}
+
+// goListOutput method runs the read-only go list command and returns the
+// output. It runs in dry-run mode too.
+func goListOutput(args ...string) (string, error) {
+	cmd := exec.Command(gocmd, append([]string{"list"}, args...)...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("go list: %s", strings.TrimSpace(string(output)))
+	}
+	return string(output), nil
+}
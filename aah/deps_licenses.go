@@ -0,0 +1,393 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+const (
+	licenseUnknown = "Unknown"
+	licenseNone    = "None"
+
+	licenseStatusAllowed    = "allowed"
+	licenseStatusDenied     = "denied"
+	licenseStatusNotAllowed = "not-allowed"
+	licenseStatusUnknown    = "unknown"
+	licenseStatusIgnored    = "ignored"
+)
+
+var (
+	depsLicensesCmdFlags   = flag.NewFlagSet("licenses", flag.ContinueOnError)
+	depsLicensesFormatFlag = depsLicensesCmdFlags.String("format", "text", "Report format 'text', 'json', 'csv' or 'sarif'")
+	depsLicensesOutputFlag = depsLicensesCmdFlags.String("o", "", "Report file path. Default is stdout")
+	depsLicensesCmd        = &command{
+		Name:      "licenses",
+		UsageLine: "aah deps licenses [-format] [-o]",
+		Flags:     depsLicensesCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "report licenses of application dependencies",
+		Long: `
+Detects the license of each application dependency from its license file
+('LICENSE', 'LICENCE', 'COPYING', etc.) and checks it against the allow and
+deny list of 'deps.licenses' in 'aah.project'. License is SPDX identifier,
+e.g. 'MIT', 'Apache-2.0', 'BSD-3-Clause'. Dependency status is one of:
+
+    allowed       license is allowed, or no allow list is configured
+    denied        license is in the deny list
+    not-allowed   license is not in the allow list
+    unknown       license file is missing or not recognized, review it
+    ignored       dependency is in the ignore list
+
+Report format 'sarif' is SARIF 2.1.0 JSON of the policy violations and
+unknown licenses located on 'deps.licenses' of 'aah.project', upload it to
+GitHub or GitLab code scanning to show them inline.
+
+Command exits with code 11 (refer 'aah help exit-codes') if any dependency
+is denied or not allowed, and unknown too with 'fail_on_unknown = true'.
+
+    deps {
+      licenses {
+        allow = ["MIT", "Apache-2.0", "BSD-2-Clause", "BSD-3-Clause", "ISC"]
+        deny = ["GPL-2.0", "GPL-3.0", "AGPL-3.0"]
+        ignore = ["example.com/internal/..."]
+      }
+    }
+
+Example(s):
+    aah deps licenses
+
+    aah deps licenses -format=csv -o=licenses.csv
+
+    aah deps licenses -format=sarif -o=licenses.sarif
+`,
+	}
+
+	// licenseSignatures are the license text phrases used to detect the
+	// license. Title is looked up in the beginning of the text, since GPL
+	// text refers the LGPL and AGPL too.
+	licenseSignatures = []struct {
+		ID      string
+		Title   string
+		Phrases []string
+	}{
+		{"AGPL-3.0", "gnu affero general public license", []string{"version 3"}},
+		{"LGPL-3.0", "gnu lesser general public license", []string{"version 3"}},
+		{"LGPL-2.1", "gnu lesser general public license", []string{"version 2.1"}},
+		{"LGPL-2.0", "gnu library general public license", []string{"version 2"}},
+		{"GPL-3.0", "gnu general public license", []string{"version 3"}},
+		{"GPL-2.0", "gnu general public license", []string{"version 2"}},
+		{"MPL-2.0", "mozilla public license", []string{"2.0"}},
+		{"EPL-2.0", "eclipse public license", []string{"2.0"}},
+		{"Apache-2.0", "apache license", []string{"version 2.0"}},
+		{"BSD-3-Clause", "", []string{"redistribution and use in source and binary forms", "neither the name"}},
+		{"BSD-2-Clause", "", []string{"redistribution and use in source and binary forms"}},
+		{"MIT", "", []string{"permission is hereby granted, free of charge"}},
+		{"ISC", "", []string{"permission to use, copy, modify, and/or distribute this software for any purpose"}},
+		{"Unlicense", "", []string{"this is free and unencumbered software released into the public domain"}},
+		{"CC0-1.0", "", []string{"cc0 1.0 universal"}},
+		{"Zlib", "", []string{"this software is provided 'as-is', without any express or implied"}},
+	}
+
+	licenseFileRegex = regexp.MustCompile(`(?i)^(LICEN[CS]E|COPYING|UNLICENSE)([.-].*)?$`)
+	spaceRegex       = regexp.MustCompile(`\s+`)
+)
+
+type (
+	// depLicense is the license report entry of the dependency.
+	depLicense struct {
+		Dependency string `json:"dependency"`
+		License    string `json:"license"`
+		File       string `json:"file,omitempty"`
+		Status     string `json:"status"`
+	}
+
+	// licensePolicy is the 'deps.licenses' section of 'aah.project'.
+	licensePolicy struct {
+		Allow         []string
+		Deny          []string
+		Ignore        []string
+		FailOnUnknown bool
+	}
+)
+
+func depsLicensesRun(args []string) {
+	format := *depsLicensesFormatFlag
+	if format != "text" && format != "json" && format != "csv" && format != "sarif" {
+		fatalExitf(exitUsage, "Unsupported report format '%s', choose 'text', 'json', 'csv' or 'sarif'", format)
+		return
+	}
+
+	deps, err := appDependencies(appProject.ImportPath)
+	if err != nil {
+		fatalErr(newExitErr(exitDepsError, err))
+		return
+	}
+
+	policy := newLicensePolicy(appProject.BuildCfg)
+	report := dependencyLicenses(deps, policy)
+
+	buf := &bytes.Buffer{}
+	if format == "sarif" {
+		err = licensesSARIF(appProject.BaseDir, policy, report).Write(buf)
+	} else {
+		err = writeLicenseReport(buf, format, report)
+	}
+	if err != nil {
+		fatal(err)
+		return
+	}
+	if file := getNonEmptyAbsPath(*depsLicensesOutputFlag, ""); !ess.IsStrEmpty(file) {
+		if err = writeFile(file, buf.Bytes(), permRWRR); err != nil {
+			fatal(err)
+			return
+		}
+		recordArtifact(file)
+		log.Infof("License report written to %s", file)
+	} else {
+		_, _ = os.Stdout.Write(buf.Bytes())
+	}
+
+	if n := policy.Violations(report); n > 0 {
+		fatalExitf(exitLicenseError, "%d dependency license(s) violate the 'deps.licenses' policy of 'aah.project'", n)
+	}
+}
+
+// newLicensePolicy method returns the license policy of 'deps.licenses'
+// section, license identifiers are matched case insensitive.
+func newLicensePolicy(cfg *config.Config) *licensePolicy {
+	p := &licensePolicy{FailOnUnknown: cfg.BoolDefault("deps.licenses.fail_on_unknown", false)}
+	p.Allow, _ = cfg.StringList("deps.licenses.allow")
+	p.Deny, _ = cfg.StringList("deps.licenses.deny")
+	p.Ignore, _ = cfg.StringList("deps.licenses.ignore")
+	return p
+}
+
+// dependencyLicenses method detects the license of the dependencies and
+// returns the report entries with their policy status.
+func dependencyLicenses(deps []*appDependency, policy *licensePolicy) []*depLicense {
+	report := make([]*depLicense, 0, len(deps))
+	for _, d := range deps {
+		license, file := detectLicense(d.Dir)
+		report = append(report, &depLicense{
+			Dependency: d.ImportPath,
+			License:    license,
+			File:       file,
+			Status:     policy.Status(d.ImportPath, license),
+		})
+	}
+	return report
+}
+
+// Status method returns the status of the dependency license.
+func (p *licensePolicy) Status(importPath, license string) string {
+	for _, pattern := range p.Ignore {
+		if importPath == pattern || (strings.HasSuffix(pattern, "/...") &&
+			(importPath == strings.TrimSuffix(pattern, "/...") || strings.HasPrefix(importPath, strings.TrimSuffix(pattern, "...")))) {
+			return licenseStatusIgnored
+		}
+	}
+
+	switch {
+	case containsFold(p.Deny, license):
+		return licenseStatusDenied
+	case license == licenseUnknown || license == licenseNone:
+		return licenseStatusUnknown
+	case len(p.Allow) > 0 && !containsFold(p.Allow, license):
+		return licenseStatusNotAllowed
+	}
+	return licenseStatusAllowed
+}
+
+// Violations method returns the count of report entries violates the policy.
+func (p *licensePolicy) Violations(report []*depLicense) int {
+	count := 0
+	for _, e := range report {
+		if e.Status == licenseStatusDenied || e.Status == licenseStatusNotAllowed ||
+			(p.FailOnUnknown && e.Status == licenseStatusUnknown) {
+			count++
+		}
+	}
+	return count
+}
+
+// detectLicense method returns the license identifier of the dependency
+// directory and the license file name it's detected from.
+func detectLicense(dir string) (string, string) {
+	files := findLicenseFiles(dir)
+	if len(files) == 0 {
+		return licenseNone, ""
+	}
+
+	for _, f := range files {
+		b, err := ioutil.ReadFile(filepath.Join(dir, f))
+		if err != nil {
+			continue
+		}
+		if id := licenseOfText(string(b)); id != licenseUnknown {
+			return id, f
+		}
+	}
+	return licenseUnknown, files[0]
+}
+
+// licenseOfText method returns the license identifier of the license text.
+func licenseOfText(text string) string {
+	text = spaceRegex.ReplaceAllString(strings.ToLower(text), " ")
+	head := text
+	if len(head) > 100 {
+		head = head[:100]
+	}
+	for _, sig := range licenseSignatures {
+		matched := strings.Contains(head, sig.Title)
+		for _, phrase := range sig.Phrases {
+			if !matched {
+				break
+			}
+			matched = strings.Contains(text, phrase)
+		}
+		if matched {
+			return sig.ID
+		}
+	}
+	return licenseUnknown
+}
+
+func writeLicenseReport(w io.Writer, format string, report []*depLicense) error {
+	switch format {
+	case "json":
+		b, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	case "csv":
+		cw := csv.NewWriter(w)
+		_ = cw.Write([]string{"dependency", "license", "file", "status"})
+		for _, e := range report {
+			_ = cw.Write([]string{e.Dependency, e.License, e.File, e.Status})
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+
+	if len(report) == 0 {
+		_, err := fmt.Fprintln(w, "No third-party dependencies found")
+		return err
+	}
+
+	width := len("DEPENDENCY")
+	counts := map[string]int{}
+	for _, e := range report {
+		if len(e.Dependency) > width {
+			width = len(e.Dependency)
+		}
+		counts[e.Status]++
+	}
+	fmt.Fprintf(w, "%-*s  %-12s  %s\n", width, "DEPENDENCY", "LICENSE", "STATUS")
+	for _, e := range report {
+		fmt.Fprintf(w, "%-*s  %-12s  %s\n", width, e.Dependency, e.License, e.Status)
+	}
+
+	statuses := make([]string, 0, len(counts))
+	for s, n := range counts {
+		statuses = append(statuses, fmt.Sprintf("%d %s", n, s))
+	}
+	sort.Strings(statuses)
+	_, err := fmt.Fprintf(w, "\n%d dependencies: %s\n", len(report), strings.Join(statuses, ", "))
+	return err
+}
+
+// licensesSARIF method returns the SARIF report of the policy violations and
+// unknown licenses, they are located on the policy key of 'aah.project'.
+func licensesSARIF(baseDir string, policy *licensePolicy, report []*depLicense) *sarifLog {
+	projectFile := filepath.Join(baseDir, aahProjectIdentifier)
+	entries, _ := parseConfFile(projectFile)
+	position := func(keys ...string) (int, int) {
+		for _, k := range keys {
+			for _, e := range entries {
+				if e.Path == k {
+					return e.Line, e.Col
+				}
+			}
+		}
+		return 1, 1
+	}
+
+	sr := newSARIFLog("deps licenses", baseDir)
+	for _, e := range report {
+		switch e.Status {
+		case licenseStatusDenied:
+			line, col := position("deps.licenses.deny", "deps.licenses")
+			sr.Add("license-denied", "Dependency license is in the deny list", sarifLevelError,
+				fmt.Sprintf("dependency '%s' license '%s' is denied", e.Dependency, e.License), projectFile, line, col)
+		case licenseStatusNotAllowed:
+			line, col := position("deps.licenses.allow", "deps.licenses")
+			sr.Add("license-not-allowed", "Dependency license is not in the allow list", sarifLevelError,
+				fmt.Sprintf("dependency '%s' license '%s' is not allowed", e.Dependency, e.License), projectFile, line, col)
+		case licenseStatusUnknown:
+			level := sarifLevelWarning
+			if policy.FailOnUnknown {
+				level = sarifLevelError
+			}
+			line, col := position("deps.licenses.fail_on_unknown", "deps.licenses")
+			sr.Add("license-unknown", "Dependency license is missing or not recognized", level,
+				fmt.Sprintf("dependency '%s' license is %s, review it", e.Dependency, strings.ToLower(e.License)), projectFile, line, col)
+		}
+	}
+	return sr
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// findLicenseFiles method returns the license file names of the directory,
+// 'LICENSE' and 'COPYING' files are sorted first.
+func findLicenseFiles(dir string) []string {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var files []string
+	for _, info := range infos {
+		if !info.IsDir() && licenseFileRegex.MatchString(info.Name()) {
+			files = append(files, info.Name())
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool { return len(files[i]) < len(files[j]) })
+	return files
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	depsLicensesCmd.Run = depsLicensesRun
+	depsCmd.Subcommands = append(depsCmd.Subcommands, depsLicensesCmd)
+}
@@ -0,0 +1,165 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestDepsLicenseDetect(t *testing.T) {
+	assert.Equal(t, "MIT", licenseOfText(`The MIT License (MIT)
+
+Copyright (c) Jeevanandam M.
+
+Permission is hereby granted, free of charge, to any person obtaining a copy`))
+	assert.Equal(t, "Apache-2.0", licenseOfText("\n  Apache License\n  Version 2.0, January 2004\n  http://www.apache.org/licenses/"))
+	assert.Equal(t, "BSD-3-Clause", licenseOfText(`Redistribution and use in source and binary forms, with or without
+modification, are permitted ... * Neither the name of Google Inc. nor the names of its`))
+	assert.Equal(t, "BSD-2-Clause", licenseOfText("Redistribution and use in source and binary\n forms, with or without modification"))
+	assert.Equal(t, "GPL-3.0", licenseOfText(`GNU GENERAL PUBLIC LICENSE
+Version 3, 29 June 2007
+
+Copyright (C) 2007 Free Software Foundation, Inc. <https://fsf.org/>
+Everyone is permitted to copy and distribute verbatim copies
+... use the GNU Lesser General Public License instead of this License ...
+13. Use with the GNU Affero General Public License.`))
+	assert.Equal(t, "LGPL-3.0", licenseOfText("GNU LESSER GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007"))
+	assert.Equal(t, "Unknown", licenseOfText("All rights reserved."))
+
+	dir, err := ioutil.TempDir("", "aah-deps")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	repo := filepath.Join(dir, "src", "github.com", "go-aah", "aah")
+	pkg := filepath.Join(repo, "router", "internal")
+	assert.Nil(t, os.MkdirAll(pkg, 0755))
+	license, file := detectLicense(repo)
+	assert.Equal(t, "None", license)
+	assert.Equal(t, "", file)
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(repo, "LICENSE.md"), []byte("Permission is hereby granted, free of charge"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(repo, "license_test.go"), []byte("package aah"), 0644))
+	license, file = detectLicense(repo)
+	assert.Equal(t, "MIT", license)
+	assert.Equal(t, "LICENSE.md", file)
+
+	root, rootDir := dependencyRoot("github.com/go-aah/aah/router/internal", pkg)
+	assert.Equal(t, "github.com/go-aah/aah", root)
+	assert.Equal(t, repo, rootDir)
+
+	root, rootDir = dependencyRoot("gopkg.in/yaml.v2/parser", filepath.Join(dir, "src", "gopkg.in", "yaml.v2", "parser"))
+	assert.Equal(t, "gopkg.in/yaml.v2", root)
+	assert.Equal(t, filepath.Join(dir, "src", "gopkg.in", "yaml.v2"), rootDir)
+}
+
+func TestDepsLicensePolicy(t *testing.T) {
+	p := &licensePolicy{
+		Allow:  []string{"MIT", "apache-2.0"},
+		Deny:   []string{"GPL-3.0"},
+		Ignore: []string{"example.com/internal/...", "example.com/tool"},
+	}
+	assert.Equal(t, "allowed", p.Status("github.com/a/b", "Apache-2.0"))
+	assert.Equal(t, "denied", p.Status("github.com/a/b", "GPL-3.0"))
+	assert.Equal(t, "not-allowed", p.Status("github.com/a/b", "MPL-2.0"))
+	assert.Equal(t, "unknown", p.Status("github.com/a/b", "None"))
+	assert.Equal(t, "ignored", p.Status("example.com/internal", "GPL-3.0"))
+	assert.Equal(t, "ignored", p.Status("example.com/internal/auth", "Unknown"))
+	assert.Equal(t, "ignored", p.Status("example.com/tool", "Unknown"))
+	assert.Equal(t, "unknown", p.Status("example.com/tools", "Unknown"))
+	assert.Equal(t, "allowed", (&licensePolicy{}).Status("github.com/a/b", "MPL-2.0"))
+
+	report := []*depLicense{
+		{Dependency: "github.com/a/b", License: "MIT", File: "LICENSE", Status: "allowed"},
+		{Dependency: "github.com/c/d", License: "None", Status: "unknown"},
+		{Dependency: "github.com/e/f", License: "GPL-3.0", File: "COPYING", Status: "denied"},
+	}
+	assert.Equal(t, 1, p.Violations(report))
+	p.FailOnUnknown = true
+	assert.Equal(t, 2, p.Violations(report))
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, writeLicenseReport(buf, "csv", report[:2]))
+	assert.Equal(t, "dependency,license,file,status\ngithub.com/a/b,MIT,LICENSE,allowed\ngithub.com/c/d,None,,unknown\n", buf.String())
+
+	buf.Reset()
+	assert.Nil(t, writeLicenseReport(buf, "text", report))
+	assert.Equal(t, `DEPENDENCY      LICENSE       STATUS
+github.com/a/b  MIT           allowed
+github.com/c/d  None          unknown
+github.com/e/f  GPL-3.0       denied
+
+3 dependencies: 1 allowed, 1 denied, 1 unknown
+`, buf.String())
+
+	dir, err := ioutil.TempDir("", "aah-deps-sarif")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "aah.project"),
+		[]byte("deps {\n  licenses {\n    deny = [\"GPL-3.0\"]\n  }\n}\n"), 0644))
+
+	run := licensesSARIF(dir, p, report).Runs[0]
+	assert.Equal(t, 2, len(run.Results))
+	assert.Equal(t, "license-unknown", run.Results[0].RuleID)
+	assert.Equal(t, "error", run.Results[0].Level)
+	assert.Equal(t, 2, run.Results[0].Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Equal(t, "license-denied", run.Results[1].RuleID)
+	assert.Equal(t, "aah.project", run.Results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 3, run.Results[1].Locations[0].PhysicalLocation.Region.StartLine)
+}
+
+func TestDepsSBOM(t *testing.T) {
+	info := &sbomInfo{
+		Name:      "myapp",
+		Version:   "v1.0.0",
+		SHA256:    "e3b0c442",
+		Timestamp: "2017-10-08T10:00:00Z",
+		Components: []*sbomComponent{
+			{Name: "github.com/go-sql-driver/mysql", Version: "v1.3", License: "MPL-2.0", SHA256: "a1b2"},
+			{Name: "gopkg.in/yaml.v2", Version: "v2", License: "Unknown", SHA256: "c3d4"},
+		},
+	}
+
+	bom := newCycloneDXBOM(info)
+	assert.Equal(t, "CycloneDX", bom.BOMFormat)
+	assert.Equal(t, "pkg:golang/myapp@v1.0.0", bom.Metadata.Component.BOMRef)
+	assert.Equal(t, 2, len(bom.Components))
+	assert.Equal(t, "pkg:golang/github.com/go-sql-driver/mysql@v1.3", bom.Components[0].PURL)
+	assert.Equal(t, "MPL-2.0", bom.Components[0].Licenses[0].License.ID)
+	assert.Equal(t, 0, len(bom.Components[1].Licenses))
+	assert.Equal(t, 2, len(bom.Dependencies[0].DependsOn))
+
+	doc := newSPDXDocument(info)
+	assert.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+	assert.Equal(t, 3, len(doc.Packages))
+	assert.Equal(t, "SPDXRef-Package-myapp", doc.Packages[0].SPDXID)
+	assert.Equal(t, "SPDXRef-Package-1-github.com-go-sql-driver-mysql", doc.Packages[1].SPDXID)
+	assert.Equal(t, "https://github.com/go-sql-driver/mysql", doc.Packages[1].DownloadLocation)
+	assert.Equal(t, "MPL-2.0", doc.Packages[1].LicenseDeclared)
+	assert.Equal(t, "NOASSERTION", doc.Packages[2].LicenseDeclared)
+	assert.Equal(t, "DESCRIBES", doc.Relationships[0].RelationshipType)
+	assert.Equal(t, "DEPENDS_ON", doc.Relationships[2].RelationshipType)
+
+	dir, err := ioutil.TempDir("", "aah-sbom")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, ".git"), 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "a.go"), []byte("package a"), 0644))
+	sum1, err := sha256Dir(dir)
+	assert.Nil(t, err)
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("changed"), 0644))
+	sum2, _ := sha256Dir(dir)
+	assert.Equal(t, sum1, sum2)
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "a.go"), []byte("package b"), 0644))
+	sum3, _ := sha256Dir(dir)
+	assert.False(t, sum1 == sum3)
+}
@@ -0,0 +1,232 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+const (
+	docsFormatMan      = "man"
+	docsFormatMarkdown = "md"
+)
+
+var (
+	docsGenCmdFlags        = flag.NewFlagSet("gen", flag.ContinueOnError)
+	docsGenFormatFlag      = docsGenCmdFlags.String("format", "", "Documentation format 'man' or 'md'. Default is 'md'")
+	docsGenFormatShortFlag = docsGenCmdFlags.String("f", "", "Documentation format 'man' or 'md'. Default is 'md'")
+	docsGenOutputFlag      = docsGenCmdFlags.String("output", "", "Output directory of generated documentation. Default is current directory")
+	docsGenOutputShortFlag = docsGenCmdFlags.String("o", "", "Output directory of generated documentation. Default is current directory")
+	docsGenCmd             = &command{
+		Name:      "gen",
+		UsageLine: "aah docs gen [-f | -format] [-o | -output]",
+		Flags:     docsGenCmdFlags,
+		Short:     "generate man pages or markdown reference of aah CLI commands",
+		Long: `
+Generates aah CLI tool reference documentation from the command registry as
+man(1) pages or markdown files, one file per command and subcommand.
+
+Example(s) short and long flag:
+    aah docs gen
+
+    aah docs gen -f=man -o=/usr/local/share/man/man1
+
+    aah docs gen -format=md -output=docs/cli
+`,
+	}
+	docsCmd = &command{
+		Name:        "docs",
+		UsageLine:   "aah docs <subcommand>",
+		Short:       "aah CLI tool documentation",
+		Subcommands: commands{docsGenCmd},
+		Long: `
+Documentation related commands of aah CLI tool and the application.
+
+Example:
+    aah docs gen -format=man
+
+    aah docs api
+`,
+	}
+)
+
+type docsCommand struct {
+	Name      string
+	FullName  string
+	UsageLine string
+	Short     string
+	Long      string
+	Flags     string
+	Date      string
+	Version   string
+	Commands  []*docsCommand
+}
+
+func docsGenRun(args []string) {
+	format := firstNonEmpty(*docsGenFormatFlag, *docsGenFormatShortFlag, docsFormatMarkdown)
+	if format != docsFormatMan && format != docsFormatMarkdown {
+		fatalExitf(exitUsage, "Unsupported docs format '%s', choose either 'man' or 'md'", format)
+	}
+
+	outputDir := getNonEmptyAbsPath(*docsGenOutputFlag, *docsGenOutputShortFlag)
+	if ess.IsStrEmpty(outputDir) {
+		outputDir = getNonEmptyAbsPath(".", "")
+	}
+
+	if err := mkDirAll(outputDir, permRWXRXRX); err != nil {
+		fatal(err)
+	}
+
+	tmpl, ext := docsMarkdownTemplate, ".md"
+	if format == docsFormatMan {
+		tmpl, ext = docsManTemplate, ".1"
+	}
+
+	cmds := docsCommands("aah", subCmds, format)
+	index := &docsCommand{
+		Name:     "aah",
+		FullName: "aah",
+		Short:    "aah framework CLI tool",
+		Long:     docsEscape("aah framework - A scalable, performant, rapid development Web framework for Go.", format),
+		Date:     time.Now().Format("January 2006"),
+		Version:  Version,
+		Commands: cmds,
+	}
+
+	files := []string{writeDocsFile(outputDir, ext, tmpl, index)}
+	for _, c := range flattenDocsCommands(cmds) {
+		files = append(files, writeDocsFile(outputDir, ext, tmpl, c))
+	}
+
+	_ = log.SetPattern("%message")
+	log.Infof("Generated %d '%s' documentation files at %s", len(files), format, outputDir)
+	_ = log.SetPattern(log.DefaultPattern)
+}
+
+func docsCommands(parent string, cmds commands, format string) []*docsCommand {
+	var result []*docsCommand
+	for _, c := range cmds {
+		fullName := parent + "-" + c.Name
+		result = append(result, &docsCommand{
+			Name:      c.Name,
+			FullName:  fullName,
+			UsageLine: docsEscape(c.UsageLine, format),
+			Short:     docsEscape(c.Short, format),
+			Long:      docsEscape(strings.TrimSpace(c.Long), format),
+			Flags:     docsEscape(strings.TrimRight(c.FlagsUsage(), "\n"), format),
+			Date:      time.Now().Format("January 2006"),
+			Version:   Version,
+			Commands:  docsCommands(fullName, c.Subcommands, format),
+		})
+	}
+	return result
+}
+
+func flattenDocsCommands(cmds []*docsCommand) []*docsCommand {
+	var result []*docsCommand
+	for _, c := range cmds {
+		result = append(result, c)
+		result = append(result, flattenDocsCommands(c.Commands)...)
+	}
+	return result
+}
+
+func writeDocsFile(outputDir, ext, tmpl string, c *docsCommand) string {
+	buf := &bytes.Buffer{}
+	if err := renderTmpl(buf, tmpl, c); err != nil {
+		fatal(err)
+	}
+
+	file := filepath.Join(outputDir, c.FullName+ext)
+	if err := writeFile(file, buf.Bytes(), permRWRWRW); err != nil {
+		fatalf("Unable to write docs file '%s': %s", file, err)
+	}
+	log.Debugf("Generated %s", file)
+	return file
+}
+
+// docsEscape method escapes the roff control characters for man format,
+// markdown content is returned as-is.
+func docsEscape(s, format string) string {
+	if format != docsFormatMan {
+		return s
+	}
+
+	s = strings.Replace(s, `\`, `\e`, -1)
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if strings.HasPrefix(l, ".") || strings.HasPrefix(l, "'") {
+			lines[i] = `\&` + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func init() {
+	docsGenCmd.Run = docsGenRun
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Docs Templates
+//___________________________________
+
+const docsManTemplate = `.TH "{{ .FullName }}" 1 "{{ .Date }}" "aah CLI v{{ .Version }}" "aah framework manual"
+.SH NAME
+{{ .FullName }} \- {{ .Short }}
+{{ if .UsageLine }}.SH SYNOPSIS
+.B {{ .UsageLine }}
+{{ end }}.SH DESCRIPTION
+.nf
+{{ .Long }}
+.fi
+{{ if .Flags }}.SH FLAGS
+.nf
+{{ .Flags }}
+.fi
+{{ end }}{{ if .Commands }}.SH COMMANDS
+{{ range .Commands }}.TP
+.B {{ .Name }}
+{{ .Short }}. See {{ .FullName }}(1).
+{{ end }}{{ end }}.SH SEE ALSO
+aah(1)
+`
+
+const docsMarkdownTemplate = `# {{ .FullName }}
+
+{{ .Short }}
+{{ if .UsageLine }}
+## Usage
+
+` + "```" + `
+{{ .UsageLine }}
+` + "```" + `
+{{ end }}
+## Description
+
+` + "```" + `
+{{ .Long }}
+` + "```" + `
+{{ if .Flags }}
+## Flags
+
+` + "```" + `
+{{ .Flags }}
+` + "```" + `
+{{ end }}{{ if .Commands }}
+## Commands
+
+| Command | Description |
+|---------|-------------|
+{{ range .Commands }}| [{{ .Name }}]({{ .FullName }}.md) | {{ .Short }} |
+{{ end }}{{ end }}
+_Generated by aah CLI v{{ .Version }} on {{ .Date }}_
+`
@@ -0,0 +1,320 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+var (
+	docsAPICmdFlags   = flag.NewFlagSet("api", flag.ContinueOnError)
+	docsAPIOutputFlag = docsAPICmdFlags.String("o", "", "Output directory of markdown pages. Default is 'docs/api'")
+	docsAPIDomainFlag = docsAPICmdFlags.String("domain", "", "Document the routes of given domain only")
+	docsAPICmd        = &command{
+		Name:      "api",
+		UsageLine: "aah docs api [-o] [-domain]",
+		Flags:     docsAPICmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "generate markdown API reference of application controllers",
+		Long: `
+Generates the markdown API reference of the application, one page per routed
+controller and an index page 'README.md', written to 'docs/api' by default.
+Page of the controller is made of:
+
+    - controller doc comment and source file
+    - actions with the doc comment, routes (HTTP method, path, route name
+      and auth scheme), parameters, request body, responses and the Go
+      signature
+
+Action doc comment annotations are same as 'aah generate openapi', action
+with '@ignore' annotation is not documented. Pages are plain markdown, so
+they can be committed to the repository or published to the wiki.
+
+Example(s):
+    aah docs api
+
+    aah docs api -o=wiki/api -domain=api.example.com
+`,
+	}
+)
+
+type (
+	// docsAPIController is the data of the controller markdown page.
+	docsAPIController struct {
+		Name    string
+		Type    string
+		Doc     string
+		File    string
+		Page    string
+		Summary string
+		Actions []*docsAPIAction
+	}
+
+	docsAPIAction struct {
+		Name        string
+		Summary     string
+		Description string
+		Deprecated  bool
+		Routes      []*appRoute
+		Params      []*docsAPIParam
+		Body        string
+		Responses   []*actionDocResponse
+		Signature   string
+		Source      string
+	}
+
+	docsAPIParam struct {
+		Name string
+		Type string
+		In   string
+	}
+)
+
+func docsAPIRun(args []string) {
+	baseDir := appProject.BaseDir
+	routes, err := loadAppRoutes(baseDir)
+	if err != nil {
+		fatalErr(newExitErr(exitRouteError, err))
+		return
+	}
+	if domain := *docsAPIDomainFlag; !ess.IsStrEmpty(domain) {
+		filtered := routes[:0]
+		for _, r := range routes {
+			if r.Domain == domain || r.Host == domain {
+				filtered = append(filtered, r)
+			}
+		}
+		routes = filtered
+	}
+
+	controllers, err := loadAppControllers(filepath.Join(baseDir, "app", "controllers"))
+	if err != nil {
+		fatalErr(newExitErr(exitParseError, err))
+		return
+	}
+
+	outputDir := firstNonEmpty(getNonEmptyAbsPath(*docsAPIOutputFlag, ""), filepath.Join(baseDir, "docs", "api"))
+	if err = mkDirAll(outputDir, permRWXRXRX); err != nil {
+		fatal(err)
+		return
+	}
+
+	pages := newDocsAPIControllers(routes, controllers, baseDir)
+	for _, c := range pages {
+		writeDocsAPIFile(filepath.Join(outputDir, c.Page), docsAPIControllerTemplate, c)
+	}
+	writeDocsAPIFile(filepath.Join(outputDir, "README.md"), docsAPIIndexTemplate, map[string]interface{}{
+		"AppName":     aah.AppName(),
+		"Controllers": pages,
+	})
+
+	_ = log.SetPattern("%message")
+	log.Infof("Generated API docs of %d controller(s) at %s", len(pages), outputDir)
+	_ = log.SetPattern(log.DefaultPattern)
+}
+
+// newDocsAPIControllers method returns the page data of the routed
+// controllers sorted by name. Actions without route are documented too,
+// source file path is relative to the base directory.
+func newDocsAPIControllers(routes []*appRoute, controllers map[string]*appController, baseDir string) []*docsAPIController {
+	byCtrl := map[*appController][]*appRoute{}
+	for _, r := range routes {
+		if c := lookupController(controllers, r.Controller); c != nil {
+			byCtrl[c] = append(byCtrl[c], r)
+		}
+	}
+
+	var pages []*docsAPIController
+	for c, ctrlRoutes := range byCtrl {
+		cd := parseActionDoc(c.Doc)
+		page := &docsAPIController{
+			Name:    c.Name,
+			Type:    c.Type,
+			Doc:     strings.TrimSpace(c.Doc),
+			File:    relPath(baseDir, c.File),
+			Page:    docsAPIPageName(c.Name),
+			Summary: docsTableCell(cd.Summary),
+		}
+
+		for _, name := range c.Actions() {
+			action := c.Action(name)
+			ad := parseActionDoc(action.Doc)
+			if ad.Ignore {
+				continue
+			}
+
+			a := &docsAPIAction{
+				Name:        name,
+				Summary:     ad.Summary,
+				Description: ad.Description,
+				Deprecated:  ad.Deprecated,
+				Body:        ad.Body,
+				Signature:   docsAPISignature(c.Type, action),
+				Source:      fmt.Sprintf("%s:%d", relPath(baseDir, action.File), action.Line),
+			}
+			for _, resp := range ad.Responses {
+				a.Responses = append(a.Responses, &actionDocResponse{Code: resp.Code, Type: resp.Type,
+					Description: docsTableCell(firstNonEmpty(resp.Description, httpStatusText(resp.Code)))})
+			}
+			for _, r := range ctrlRoutes {
+				if r.Action == name {
+					a.Routes = append(a.Routes, r)
+				}
+			}
+			a.Params, a.Body = docsAPIParams(a.Routes, action, a.Body)
+			page.Actions = append(page.Actions, a)
+		}
+		pages = append(pages, page)
+	}
+
+	sort.Slice(pages, func(i, j int) bool { return pages[i].Name < pages[j].Name })
+	return pages
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// docsAPIParams method returns the action parameters with its location same
+// as 'aah generate openapi', first route of the action decides the location.
+// Struct type parameter is the request body unless '@body' is annotated.
+func docsAPIParams(routes []*appRoute, action *appAction, body string) ([]*docsAPIParam, string) {
+	inPath := map[string]bool{}
+	bodyMethod := false
+	if len(routes) > 0 {
+		for _, seg := range strings.Split(routes[0].Path, "/") {
+			if strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+				inPath[seg[1:]] = true
+			}
+		}
+		bodyMethod = openAPIBodyMethods[routes[0].Method]
+	}
+
+	var params []*docsAPIParam
+	for _, ap := range action.Params {
+		p := &docsAPIParam{Name: ap.Name, Type: docsTableCell(ap.Type), In: "query"}
+		switch {
+		case inPath[ap.Name]:
+			p.In = "path"
+		case !isGoBasicType(strings.TrimPrefix(ap.Type, "[]")):
+			if bodyMethod && ess.IsStrEmpty(body) {
+				body = strings.TrimLeft(ap.Type, "*")
+			}
+			p.In = "body"
+		}
+		params = append(params, p)
+	}
+	return params, body
+}
+
+func docsAPISignature(ctrlType string, action *appAction) string {
+	params := make([]string, 0, len(action.Params))
+	for _, p := range action.Params {
+		params = append(params, p.Name+" "+p.Type)
+	}
+	return fmt.Sprintf("func (c *%s) %s(%s)", ctrlType, action.Name, strings.Join(params, ", "))
+}
+
+// docsAPIPageName method returns the markdown file name of the controller,
+// e.g. 'v1/UserController' is 'v1_user_controller.md'.
+func docsAPIPageName(name string) string {
+	parts := strings.Split(name, "/")
+	for i, p := range parts {
+		parts[i] = toSnakeCase(p)
+	}
+	return strings.Join(parts, "_") + ".md"
+}
+
+// docsTableCell method escapes the text for markdown table cell.
+func docsTableCell(s string) string {
+	return strings.Replace(strings.Replace(s, "|", `\|`, -1), "\n", " ", -1)
+}
+
+func relPath(baseDir, file string) string {
+	if rel, err := filepath.Rel(baseDir, file); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return file
+}
+
+func writeDocsAPIFile(file, tmpl string, data interface{}) {
+	buf := &bytes.Buffer{}
+	if err := renderTmpl(buf, tmpl, data); err != nil {
+		fatal(err)
+	}
+	if err := writeFile(file, buf.Bytes(), permRWRR); err != nil {
+		fatalf("Unable to write docs file '%s': %s", file, err)
+	}
+	log.Debugf("Generated %s", file)
+}
+
+func init() {
+	docsAPICmd.Run = docsAPIRun
+	docsCmd.Subcommands = append(docsCmd.Subcommands, docsAPICmd)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Docs API Templates
+//___________________________________
+
+const docsAPIIndexTemplate = `# {{ .AppName }} API Reference
+
+| Controller | Actions | Description |
+|------------|---------|-------------|
+{{ range .Controllers }}| [{{ .Name }}]({{ .Page }}) | {{ len .Actions }} | {{ .Summary }} |
+{{ end }}
+_Generated by aah CLI, do not edit._
+`
+
+const docsAPIControllerTemplate = `# {{ .Name }}
+{{ if .Doc }}
+{{ .Doc }}
+{{ end }}
+Source: ` + "`{{ .File }}`" + `
+{{ range .Actions }}
+## {{ .Name }}
+{{ if .Deprecated }}
+> **Deprecated**
+{{ end }}{{ if .Summary }}
+{{ .Summary }}
+{{ end }}{{ if .Description }}
+{{ .Description }}
+{{ end }}
+{{ if .Routes }}| Method | Path | Route | Auth |
+|--------|------|-------|------|
+{{ range .Routes }}| {{ .Method }} | ` + "`{{ .Path }}`" + ` | {{ .Name }} | {{ .Auth }} |
+{{ end }}{{ else }}_Not routed._
+{{ end }}{{ if .Params }}
+**Parameters**
+
+| Name | Type | In |
+|------|------|----|
+{{ range .Params }}| {{ .Name }} | ` + "`{{ .Type }}`" + ` | {{ .In }} |
+{{ end }}{{ end }}{{ if .Body }}
+**Request body:** ` + "`{{ .Body }}`" + `
+{{ end }}{{ if .Responses }}
+**Responses**
+
+| Code | Type | Description |
+|------|------|-------------|
+{{ range .Responses }}| {{ .Code }} | {{ if .Type }}` + "`{{ .Type }}`" + `{{ end }} | {{ .Description }} |
+{{ end }}{{ end }}
+` + "```go" + `
+{{ .Signature }}
+` + "```" + `
+
+Source: ` + "`{{ .Source }}`" + `
+{{ end }}
+_Generated by aah CLI, do not edit._
+`
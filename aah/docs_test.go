@@ -0,0 +1,96 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestDocsAPIControllers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-docs-api")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	ctrlDir := filepath.Join(dir, "app", "controllers")
+	assert.Nil(t, os.MkdirAll(ctrlDir, 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(ctrlDir, "user.go"), []byte(`package controllers
+
+import "example.com/myapp/app/models"
+
+// UserController manages the users.
+type UserController struct{}
+
+// Show returns the user.
+//
+// @response 200 models.User the user
+// @response 404
+func (c *UserController) Show(id int64, fields []string) {}
+
+// Create creates the user.
+//
+// @deprecated
+func (c *UserController) Create(user *models.User) {}
+
+// Export is not routed.
+func (c *UserController) Export() {}
+
+// Internal is hidden.
+//
+// @ignore
+func (c *UserController) Internal() {}
+
+func (c *UserController) Before() {}
+
+// AdminController is not routed.
+type AdminController struct{}
+
+func (c *AdminController) Index() {}
+`), 0644))
+
+	controllers, err := loadAppControllers(ctrlDir)
+	assert.Nil(t, err)
+	routes := []*appRoute{
+		{Domain: "localhost", Name: "user_show", Path: "/users/:id", Method: "GET", Controller: "UserController", Action: "Show", Auth: "form_auth"},
+		{Domain: "localhost", Name: "user_create", Path: "/users", Method: "POST", Controller: "UserController", Action: "Create"},
+	}
+
+	pages := newDocsAPIControllers(routes, controllers, dir)
+	assert.Equal(t, 1, len(pages))
+	page := pages[0]
+	assert.Equal(t, "user_controller.md", page.Page)
+	assert.Equal(t, "app/controllers/user.go", page.File)
+	assert.Equal(t, "UserController manages the users", page.Summary)
+	assert.Equal(t, 3, len(page.Actions))
+
+	create, export, show := page.Actions[0], page.Actions[1], page.Actions[2]
+	assert.True(t, create.Deprecated)
+	assert.Equal(t, "models.User", create.Body)
+	assert.Equal(t, &docsAPIParam{Name: "user", Type: "*models.User", In: "body"}, create.Params[0])
+	assert.Equal(t, 0, len(export.Routes))
+	assert.Equal(t, "func (c *UserController) Show(id int64, fields []string)", show.Signature)
+	assert.Equal(t, []*docsAPIParam{{Name: "id", Type: "int64", In: "path"}, {Name: "fields", Type: "[]string", In: "query"}}, show.Params)
+	assert.True(t, strings.HasPrefix(show.Source, "app/controllers/user.go:"))
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, renderTmpl(buf, docsAPIControllerTemplate, page))
+	md := buf.String()
+	assert.True(t, strings.Contains(md, "# UserController\n\nUserController manages the users."))
+	assert.True(t, strings.Contains(md, "| GET | `/users/:id` | user_show | form_auth |"))
+	assert.True(t, strings.Contains(md, "| 200 | `models.User` | the user |\n| 404 |  | Not Found |"))
+	assert.True(t, strings.Contains(md, "## Export\n\nExport is not routed\n\n_Not routed._"))
+	assert.False(t, strings.Contains(md, "Internal"))
+
+	buf.Reset()
+	assert.Nil(t, renderTmpl(buf, docsAPIIndexTemplate, map[string]interface{}{"AppName": "myapp", "Controllers": pages}))
+	assert.True(t, strings.Contains(buf.String(), "| [UserController](user_controller.md) | 3 | UserController manages the users |"))
+	assert.Equal(t, "v1_user_controller.md", docsAPIPageName("v1/UserController"))
+}
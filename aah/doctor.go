@@ -0,0 +1,432 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+const (
+	doctorPass = "PASS"
+	doctorWarn = "WARN"
+	doctorFail = "FAIL"
+	doctorSkip = "SKIP"
+
+	// minGoVersion is the minimum Go version required by aah framework.
+	minGoVersion = "1.8"
+
+	// minWatchLimit is the recommended inotify watches limit for 'aah run'.
+	minWatchLimit = 8192
+	inotifyFile   = "/proc/sys/fs/inotify/max_user_watches"
+)
+
+var (
+	doctorCmdFlags            = flag.NewFlagSet("doctor", flag.ContinueOnError)
+	doctorImportPathFlag      = doctorCmdFlags.String("importPath", "", "Import path of aah application. Default is current directory")
+	doctorImportPathShortFlag = doctorCmdFlags.String("ip", "", "Import path of aah application. Default is current directory")
+	doctorCmd                 = &command{
+		Name:      "doctor",
+		UsageLine: "aah doctor [-importPath | -ip]",
+		Flags:     doctorCmdFlags,
+		Short:     "diagnose the environment of aah CLI tool and application",
+		Long: `
+Checks the environment and prints pass or fail of each check along with the
+fix:
+
+    Go            Go is installed and version is go` + minGoVersion + ` or later
+    GOPATH        GOPATH is set and Go module mode is off, aah framework v0
+                  builds in GOPATH mode
+    git           git is installed, it's used by 'aah release', 'aah deps'
+                  and application templates
+    aah version   aah framework in GOPATH (or application 'vendor') is
+                  compatible with aah CLI tool
+    layout        application has 'aah.project', 'app', 'config/aah.conf'
+                  and 'config/routes.conf', 'aah.project' is parsable
+    permissions   output directories 'build', '.aah' and temp directory are
+                  writable
+    watcher       inotify watches limit is enough for the directories
+                  watched by 'aah run' (Linux only)
+
+Application checks are done if it's run from the application directory or
+import path is supplied. Exit code is 'error' if any check fails, warnings
+don't fail.
+
+Example(s):
+    aah doctor
+
+    aah doctor -importPath=github.com/user/appname
+`,
+	}
+)
+
+type (
+	// doctorCheck is the result of the doctor check.
+	doctorCheck struct {
+		Name   string
+		Status string
+		Detail string
+		Fix    string
+	}
+
+	// doctorEnv holds the values resolved by the checks for the subsequent
+	// checks.
+	doctorEnv struct {
+		GoVersion string
+		BaseDir   string
+		BuildCfg  *config.Config
+	}
+)
+
+func doctorRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() {
+		_ = log.SetPattern(log.DefaultPattern)
+	}()
+
+	env := &doctorEnv{}
+	checks := []*doctorCheck{env.checkGo(), env.checkGopath(), checkGit()}
+	env.BaseDir = findAppBaseDir(firstNonEmpty(*doctorImportPathFlag, *doctorImportPathShortFlag))
+	checks = append(checks, env.checkAahVersion(), env.checkLayout(), env.checkPermissions(), env.checkWatcher())
+
+	log.Info("Checking environment:")
+	failed, warned := 0, 0
+	for _, c := range checks {
+		line := fmt.Sprintf("    [%s] %-12s %s", c.Status, c.Name, c.Detail)
+		switch c.Status {
+		case doctorFail:
+			failed++
+			log.Error(line)
+		case doctorWarn:
+			warned++
+			logWarn(line)
+		default:
+			log.Info(line)
+		}
+		if !ess.IsStrEmpty(c.Fix) {
+			log.Infof("           %-12s fix: %s", "", c.Fix)
+		}
+	}
+	log.Info()
+	log.Infof("%d passed, %d warning(s), %d failed", len(checks)-failed-warned, warned, failed)
+
+	if failed > 0 {
+		fatalExitf(exitError, "aah doctor found %d problem(s)", failed)
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Checks
+//___________________________________
+
+func (e *doctorEnv) checkGo() *doctorCheck {
+	c := &doctorCheck{Name: "Go"}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		c.Status, c.Detail = doctorFail, "Unable to find Go executable in PATH"
+		c.Fix = "install go" + minGoVersion + " or later from https://golang.org/dl and add it to PATH"
+		return c
+	}
+	gocmd = goBin
+
+	out, err := exec.Command(goBin, "version").Output()
+	if err != nil {
+		c.Status, c.Detail = doctorFail, fmt.Sprintf("Unable to get Go version: %s", err)
+		c.Fix = "verify the Go installation via 'go version'"
+		return c
+	}
+	e.GoVersion = parseGoVersion(string(out))
+
+	c.Status, c.Detail = doctorPass, fmt.Sprintf("go%s (%s)", e.GoVersion, goBin)
+	switch {
+	case ess.IsStrEmpty(e.GoVersion):
+		c.Status, c.Detail = doctorWarn, fmt.Sprintf("unknown Go version '%s'", strings.TrimSpace(string(out)))
+	case compareVersion(e.GoVersion, minGoVersion) < 0:
+		c.Status = doctorFail
+		c.Fix = "upgrade Go to go" + minGoVersion + " or later"
+	}
+	return c
+}
+
+func (e *doctorEnv) checkGopath() *doctorCheck {
+	c := &doctorCheck{Name: "GOPATH"}
+	var err error
+	if gopath, err = ess.GoPath(); err != nil {
+		c.Status, c.Detail = doctorFail, err.Error()
+		c.Fix = "set GOPATH environment variable, e.g. 'export GOPATH=$HOME/go'"
+		return c
+	}
+	gosrcDir = filepath.Join(gopath, "src")
+
+	mode := os.Getenv("GO111MODULE")
+	if goModuleMode(e.GoVersion, mode, ess.IsFileExists("go.mod")) {
+		c.Status = doctorFail
+		c.Detail = fmt.Sprintf("%s, Go module mode is on (GO111MODULE=%s), aah framework v0 builds in GOPATH mode", gopath, mode)
+		c.Fix = "export GO111MODULE=off"
+		return c
+	}
+
+	c.Status, c.Detail = doctorPass, gopath
+	return c
+}
+
+func checkGit() *doctorCheck {
+	c := &doctorCheck{Name: "git"}
+	out, err := exec.Command("git", "--version").Output()
+	if err != nil {
+		c.Status, c.Detail = doctorWarn, "Unable to find git executable in PATH"
+		c.Fix = "install git from https://git-scm.com, it's required for 'aah release', 'aah deps' and templates"
+		return c
+	}
+
+	c.Status, c.Detail = doctorPass, strings.TrimSpace(string(out))
+	return c
+}
+
+func (e *doctorEnv) checkAahVersion() *doctorCheck {
+	c := &doctorCheck{Name: "aah version"}
+	if ess.IsStrEmpty(gosrcDir) {
+		c.Status, c.Detail = doctorSkip, "GOPATH is not available"
+		return c
+	}
+
+	var dirs []string
+	if !ess.IsStrEmpty(e.BaseDir) {
+		dirs = append(dirs, filepath.Join(e.BaseDir, "vendor", "aahframework.org", "aah.v0"))
+	}
+	dirs = append(dirs, filepath.Join(gosrcDir, "aahframework.org", "aah.v0"))
+
+	for _, dir := range dirs {
+		version := readAahVersion(dir)
+		if ess.IsStrEmpty(version) {
+			continue
+		}
+		c.Status, c.Detail = doctorPass, fmt.Sprintf("aah framework v%s, aah CLI tool is built with v%s", version, aah.Version)
+		if !isCompatibleVersion(version, aah.Version) {
+			c.Status = doctorFail
+			c.Fix = "update aah framework and CLI tool together via 'go get -u aahframework.org/aah.v0 aahframework.org/tools.v0/aah'"
+		}
+		return c
+	}
+
+	c.Status, c.Detail = doctorFail, "aah framework is not found in GOPATH"
+	c.Fix = "go get aahframework.org/aah.v0"
+	return c
+}
+
+func (e *doctorEnv) checkLayout() *doctorCheck {
+	c := &doctorCheck{Name: "layout"}
+	if ess.IsStrEmpty(e.BaseDir) {
+		c.Status, c.Detail = doctorSkip, "not inside aah application"
+		c.Fix = "run it from the application directory or supply '-importPath'"
+		return c
+	}
+
+	var missing []string
+	for _, p := range []string{aahProjectIdentifier, "app", "config/aah.conf", "config/routes.conf"} {
+		if !ess.IsFileExists(filepath.Join(e.BaseDir, filepath.FromSlash(p))) {
+			missing = append(missing, p)
+		}
+	}
+	if len(missing) > 0 {
+		c.Status, c.Detail = doctorFail, fmt.Sprintf("missing %s", strings.Join(missing, ", "))
+		c.Fix = "restore the files, refer application created via 'aah new'"
+		return c
+	}
+
+	var err error
+	if e.BuildCfg, err = loadProjectConfig(filepath.Join(e.BaseDir, aahProjectIdentifier), e.BaseDir); err != nil {
+		c.Status, c.Detail = doctorFail, fmt.Sprintf("'aah.project' error: %s", err)
+		c.Fix = "correct the 'aah.project', 'aah config lint' reports the problems"
+		return c
+	}
+
+	c.Status, c.Detail = doctorPass, e.BaseDir
+	return c
+}
+
+func (e *doctorEnv) checkPermissions() *doctorCheck {
+	c := &doctorCheck{Name: "permissions"}
+	if isDryRun() {
+		c.Status, c.Detail = doctorSkip, "write check is not done in dry-run mode"
+		return c
+	}
+
+	dirs := []string{os.TempDir()}
+	if !ess.IsStrEmpty(e.BaseDir) {
+		dirs = append(dirs, filepath.Join(e.BaseDir, "build"), filepath.Join(e.BaseDir, ".aah"))
+	}
+
+	var notWritable []string
+	for _, dir := range dirs {
+		if !isDirWritable(dir) {
+			notWritable = append(notWritable, dir)
+		}
+	}
+	if len(notWritable) > 0 {
+		c.Status, c.Detail = doctorFail, fmt.Sprintf("not writable %s", strings.Join(notWritable, ", "))
+		c.Fix = "grant write permission to the user, e.g. 'chmod u+w <dir>'"
+		return c
+	}
+
+	c.Status, c.Detail = doctorPass, strings.Join(dirs, ", ")
+	return c
+}
+
+func (e *doctorEnv) checkWatcher() *doctorCheck {
+	c := &doctorCheck{Name: "watcher"}
+	if runtime.GOOS != "linux" {
+		c.Status, c.Detail = doctorSkip, "inotify limit is applicable on linux"
+		return c
+	}
+
+	b, err := ioutil.ReadFile(inotifyFile)
+	if err != nil {
+		c.Status, c.Detail = doctorSkip, fmt.Sprintf("Unable to read inotify limit: %s", err)
+		return c
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil {
+		c.Status, c.Detail = doctorSkip, fmt.Sprintf("Unable to read inotify limit: %s", err)
+		return c
+	}
+
+	dirs := 0
+	if e.BuildCfg != nil {
+		wc, err := loadWatchConfig(e.BuildCfg)
+		if err != nil {
+			c.Status, c.Detail = doctorFail, err.Error()
+			c.Fix = "correct the 'watch' section of 'aah.project'"
+			return c
+		}
+		if wc.Poll {
+			c.Status, c.Detail = doctorPass, "'watch.poll' is enabled, inotify is not used"
+			return c
+		}
+		w := &watcher{cfg: wc, baseDir: e.BaseDir}
+		for _, d := range w.dirs() {
+			_ = w.walkDirs(d, func(string) error {
+				dirs++
+				return nil
+			})
+		}
+	}
+	return watchLimitCheck(c, limit, dirs)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+var (
+	goVersionRegex  = regexp.MustCompile(`\bgo(\d+(\.\d+)*)`)
+	aahVersionRegex = regexp.MustCompile(`Version\s*=\s*"([^"]+)"`)
+)
+
+// parseGoVersion method returns the version from 'go version' output, e.g.
+// 'go version go1.10.3 linux/amd64' returns '1.10.3'. It returns empty for
+// development builds.
+func parseGoVersion(out string) string {
+	if m := goVersionRegex.FindStringSubmatch(out); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// readAahVersion method returns the value of 'Version' constant from aah
+// framework source directory, empty if it's not found.
+func readAahVersion(dir string) string {
+	for _, name := range []string{"version.go", "aah.go"} {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		if m := aahVersionRegex.FindSubmatch(b); m != nil {
+			return string(m[1])
+		}
+	}
+	return ""
+}
+
+// isCompatibleVersion method reports whether both versions have same major
+// and minor, e.g. '0.10.1' and '0.10'.
+func isCompatibleVersion(a, b string) bool {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	return versionSegment(as, 0) == versionSegment(bs, 0) &&
+		versionSegment(as, 1) == versionSegment(bs, 1)
+}
+
+// goModuleMode method reports whether Go builds in module mode for given Go
+// version and 'GO111MODULE' value. Module mode is default since go1.16, in
+// 'auto' mode of go1.11 to go1.15 it's on if 'go.mod' exists.
+func goModuleMode(goVersion, mode string, hasGoMod bool) bool {
+	switch mode {
+	case "on":
+		return true
+	case "off":
+		return false
+	}
+	switch {
+	case ess.IsStrEmpty(goVersion), compareVersion(goVersion, "1.11") < 0:
+		return false
+	case compareVersion(goVersion, "1.16") >= 0:
+		return true
+	}
+	return hasGoMod
+}
+
+// watchLimitCheck method checks the no. of watched directories against
+// inotify limit, other programs use the watches too so half of the limit is
+// considered as warning.
+func watchLimitCheck(c *doctorCheck, limit, dirs int) *doctorCheck {
+	c.Status, c.Detail = doctorPass, fmt.Sprintf("%d directories watched, inotify limit is %d", dirs, limit)
+	switch {
+	case dirs >= limit:
+		c.Status = doctorFail
+	case dirs > limit/2 || limit < minWatchLimit:
+		c.Status = doctorWarn
+	}
+	if c.Status != doctorPass {
+		c.Fix = "increase the limit via 'sudo sysctl -w fs.inotify.max_user_watches=524288' or enable 'watch.poll' in 'aah.project'"
+	}
+	return c
+}
+
+// isDirWritable method reports whether the file can be created in the
+// directory, nearest existing parent is checked if it does not exists.
+func isDirWritable(dir string) bool {
+	for !ess.IsFileExists(dir) {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+
+	f, err := ioutil.TempFile(dir, ".aah-doctor")
+	if err != nil {
+		return false
+	}
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+	return true
+}
+
+func init() {
+	doctorCmd.Run = doctorRun
+}
@@ -0,0 +1,50 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestDoctorChecks(t *testing.T) {
+	assert.Equal(t, "1.10.3", parseGoVersion("go version go1.10.3 linux/amd64"))
+	assert.Equal(t, "1.9", parseGoVersion("go version go1.9 darwin/amd64"))
+	assert.Equal(t, "", parseGoVersion("go version devel +5fae09b Fri Jun 1 10:00:00 2018 linux/amd64"))
+
+	assert.True(t, isCompatibleVersion("0.10.1", "0.10"))
+	assert.False(t, isCompatibleVersion("0.9", "0.10"))
+
+	assert.False(t, goModuleMode("1.10.3", "", true))
+	assert.False(t, goModuleMode("1.12", "auto", false))
+	assert.True(t, goModuleMode("1.12", "", true))
+	assert.True(t, goModuleMode("1.16", "", false))
+	assert.True(t, goModuleMode("1.9", "on", false))
+	assert.False(t, goModuleMode("1.16", "off", true))
+
+	c := watchLimitCheck(&doctorCheck{}, 524288, 120)
+	assert.Equal(t, doctorPass, c.Status)
+	assert.Equal(t, "", c.Fix)
+	assert.Equal(t, doctorWarn, watchLimitCheck(&doctorCheck{}, 4096, 120).Status)
+	assert.Equal(t, doctorWarn, watchLimitCheck(&doctorCheck{}, 8192, 5000).Status)
+	c = watchLimitCheck(&doctorCheck{}, 8192, 8192)
+	assert.Equal(t, doctorFail, c.Status)
+	assert.True(t, c.Fix != "")
+
+	dir, err := ioutil.TempDir("", "aah-doctor")
+	assert.Nil(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	assert.True(t, isDirWritable(filepath.Join(dir, "build", "bin")))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "aah.go"), []byte("package aah\n\n// Version no. of aah framework\nconst Version = \"0.10.1\"\n"), 0644))
+	assert.Equal(t, "0.10.1", readAahVersion(dir))
+	assert.Equal(t, "", readAahVersion(filepath.Join(dir, "vendor")))
+}
@@ -0,0 +1,70 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+// Dry-run mode is enabled via global flag '-dry-run'. File system changes
+// and command executions go through these methods, so destructive commands
+// print what they would do without touching the file system or network.
+
+func isDryRun() bool {
+	return dryRunFlag != nil && *dryRunFlag
+}
+
+func logDryRun(format string, v ...interface{}) {
+	log.Infof("[dry-run] %s", fmt.Sprintf(format, v...))
+}
+
+// writeFile method writes the data into file, in dry-run mode it just logs.
+func writeFile(file string, data []byte, perm os.FileMode) error {
+	if isDryRun() {
+		logDryRun("write file %s (%d bytes)", file, len(data))
+		return nil
+	}
+	return ioutil.WriteFile(file, data, perm)
+}
+
+// mkDirAll method creates the directory tree, in dry-run mode it just logs.
+func mkDirAll(dir string, perm os.FileMode) error {
+	if isDryRun() {
+		if !ess.IsFileExists(dir) {
+			logDryRun("create directory %s", dir)
+		}
+		return nil
+	}
+	return ess.MkDirAll(dir, perm)
+}
+
+// deleteFiles method deletes the given files and directories, in dry-run
+// mode it just logs.
+func deleteFiles(files ...string) {
+	if isDryRun() {
+		for _, f := range files {
+			if ess.IsFileExists(f) {
+				logDryRun("delete %s", f)
+			}
+		}
+		return
+	}
+	ess.DeleteFiles(files...)
+}
+
+// renameFile method renames (moves) the file or directory, in dry-run mode
+// it just logs.
+func renameFile(oldPath, newPath string) error {
+	if isDryRun() {
+		logDryRun("move %s => %s", oldPath, newPath)
+		return nil
+	}
+	return os.Rename(oldPath, newPath)
+}
@@ -0,0 +1,129 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+
+	"aahframework.org/log.v0"
+)
+
+// Exit codes of aah CLI tool. These values are stable across releases, so
+// CI scripts can branch on them. Do not reorder, only append.
+const (
+	exitOK = iota
+	exitError
+	exitUsage
+	exitConfigError
+	exitParseError
+	exitCompileError
+	exitRouteError
+	exitDepsError
+	exitDeployError
+	exitTestError
+	exitAPIBreaking
+	exitLicenseError
+	exitSecretError
+	exitI18nError
+	exitMigrationError
+	exitSizeError
+	exitLintError
+	exitAuditError
+)
+
+type (
+	// exitCodeInfo holds the exit code and it's description, used by
+	// 'aah help exit-codes'.
+	exitCodeInfo struct {
+		Code int
+		Name string
+		Desc string
+	}
+
+	// exitErr wraps the error with exit code, so the command can decide
+	// how to exit from the error it receives from underlying methods.
+	exitErr struct {
+		code int
+		err  error
+	}
+)
+
+var exitCodes = []exitCodeInfo{
+	{Code: exitOK, Name: "ok", Desc: "command completed successfully"},
+	{Code: exitError, Name: "error", Desc: "general or unexpected error"},
+	{Code: exitUsage, Name: "usage", Desc: "invalid command, arguments or flags"},
+	{Code: exitConfigError, Name: "config", Desc: "'aah.project' or application config error"},
+	{Code: exitParseError, Name: "parse", Desc: "Go source parsing error"},
+	{Code: exitCompileError, Name: "compile", Desc: "application compilation error"},
+	{Code: exitRouteError, Name: "route", Desc: "route configuration validation failure"},
+	{Code: exitDepsError, Name: "deps", Desc: "application dependencies are missing or cannot be fetched"},
+	{Code: exitDeployError, Name: "deploy", Desc: "deployment to one or more targets failed"},
+	{Code: exitTestError, Name: "test", Desc: "one or more application tests failed"},
+	{Code: exitAPIBreaking, Name: "api", Desc: "breaking API changes found by 'aah api diff'"},
+	{Code: exitLicenseError, Name: "license", Desc: "dependency license violates the 'deps.licenses' policy"},
+	{Code: exitSecretError, Name: "secret", Desc: "likely secrets found in the package contents"},
+	{Code: exitI18nError, Name: "i18n", Desc: "message keys missing in locale files found by 'aah i18n check'"},
+	{Code: exitMigrationError, Name: "migration", Desc: "database migration or seed failed, or migrations are inconsistent"},
+	{Code: exitSizeError, Name: "size", Desc: "application binary exceeds the 'build.max_binary_size' budget"},
+	{Code: exitLintError, Name: "lint", Desc: "vet or config problems found by 'aah lint'"},
+	{Code: exitAuditError, Name: "audit", Desc: "license violations or vulnerabilities found by 'aah audit'"},
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// exitErr methods
+//___________________________________
+
+func (e *exitErr) Error() string {
+	return e.err.Error()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func newExitErr(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitErr{code: code, err: err}
+}
+
+// exitCodeOf method returns the exit code for given error. Error created
+// via `newExitErr` gets its code otherwise `exitError`.
+func exitCodeOf(err error) int {
+	if e, ok := err.(*exitErr); ok {
+		return e.code
+	}
+	return exitError
+}
+
+// exitName method returns the name of exit code e.g. 'compile'.
+func exitName(code int) string {
+	for _, e := range exitCodes {
+		if e.Code == code {
+			return e.Name
+		}
+	}
+	return ""
+}
+
+// fatalExit method logs the given values and exits with given code.
+func fatalExit(code int, v ...interface{}) {
+	log.Error(v...)
+	recordStatusError(fmt.Sprint(v...))
+	exit(code)
+}
+
+// fatalExitf method logs the formatted message and exits with given code.
+func fatalExitf(code int, format string, v ...interface{}) {
+	log.Errorf(format, v...)
+	recordStatusError(fmt.Sprintf(format, v...))
+	exit(code)
+}
+
+// fatalErr method logs the error and exits with code derived from the error.
+func fatalErr(err error) {
+	fatalExit(exitCodeOf(err), err)
+}
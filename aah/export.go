@@ -0,0 +1,17 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+var exportCmd = &command{
+	Name:      "export",
+	UsageLine: "aah export <subcommand> [arguments]",
+	Short:     "export application routes to other tools",
+	Long: `
+Exports the application routes into the formats of other tools.
+
+Example:
+    aah export postman
+`,
+}
@@ -0,0 +1,322 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/log.v0"
+)
+
+const postmanSchemaURL = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+var (
+	exportPostmanCmdFlags   = flag.NewFlagSet("postman", flag.ContinueOnError)
+	exportPostmanOutputFlag = exportPostmanCmdFlags.String("o", "", "Output file path. Default is '<app-name>.postman_collection.json'")
+	exportPostmanDomainFlag = exportPostmanCmdFlags.String("domain", "", "Export the routes of given domain only")
+	exportPostmanURLFlag    = exportPostmanCmdFlags.String("base-url", "", "Value of 'baseUrl' variable. Default is 'http://localhost:<server.port>'")
+	exportPostmanCmd        = &command{
+		Name:      "postman",
+		UsageLine: "aah export postman [-o] [-domain] [-base-url]",
+		Flags:     exportPostmanCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "export routes as Postman collection",
+		Long: `
+Exports the application routes as Postman collection v2.1, requests are
+grouped into folders by the controller or '@tags' annotation. It's made of
+the same route and controller details as 'aah generate openapi':
+
+    - path parameters ':id' are Postman path variables with example values
+    - query parameters are added disabled with example values
+    - JSON request body is an example of the request struct type
+    - routes with 'auth' use collection variables, 'basic' auth scheme uses
+      '{{username}}' and '{{password}}', 'form' and 'oauth2' send session
+      cookie '{{authToken}}', others use bearer token '{{authToken}}'
+
+Collection variable 'baseUrl' is the application URL, override it or the
+auth variables via Postman environment for QA, staging, etc.
+
+Example(s):
+    aah export postman
+
+    aah export postman -base-url=https://staging.example.com -o=qa.postman_collection.json
+`,
+	}
+)
+
+type (
+	postmanCollection struct {
+		Info     *postmanInfo       `json:"info"`
+		Item     []*postmanItem     `json:"item"`
+		Variable []*postmanVariable `json:"variable"`
+	}
+
+	postmanInfo struct {
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+		Schema      string `json:"schema"`
+	}
+
+	postmanItem struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description,omitempty"`
+		Item        []*postmanItem  `json:"item,omitempty"`
+		Request     *postmanRequest `json:"request,omitempty"`
+	}
+
+	postmanRequest struct {
+		Method string       `json:"method"`
+		Header []*postmanKV `json:"header"`
+		URL    *postmanURL  `json:"url"`
+		Body   *postmanBody `json:"body,omitempty"`
+		Auth   *postmanAuth `json:"auth,omitempty"`
+	}
+
+	postmanURL struct {
+		Raw      string       `json:"raw"`
+		Host     []string     `json:"host"`
+		Path     []string     `json:"path"`
+		Query    []*postmanKV `json:"query,omitempty"`
+		Variable []*postmanKV `json:"variable,omitempty"`
+	}
+
+	postmanKV struct {
+		Key      string `json:"key"`
+		Value    string `json:"value"`
+		Disabled bool   `json:"disabled,omitempty"`
+	}
+
+	postmanBody struct {
+		Mode    string                 `json:"mode"`
+		Raw     string                 `json:"raw"`
+		Options map[string]interface{} `json:"options,omitempty"`
+	}
+
+	postmanAuth struct {
+		Type   string       `json:"type"`
+		Basic  []*postmanKV `json:"basic,omitempty"`
+		Bearer []*postmanKV `json:"bearer,omitempty"`
+	}
+
+	postmanVariable struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}
+)
+
+func exportPostmanRun(args []string) {
+	doc, err := appOpenAPIDoc(appProject.BaseDir, *exportPostmanDomainFlag, nil)
+	if err != nil {
+		fatal(err)
+		return
+	}
+
+	baseURL := firstNonEmpty(*exportPostmanURLFlag, "http://localhost:"+aah.AppConfig().StringDefault("server.port", "8080"))
+	b, err := json.MarshalIndent(newPostmanCollection(doc, baseURL), "", "  ")
+	if err != nil {
+		fatal(err)
+		return
+	}
+
+	file := firstNonEmpty(getNonEmptyAbsPath(*exportPostmanOutputFlag, ""),
+		filepath.Join(appProject.BaseDir, strings.Replace(aah.AppName(), " ", "_", -1)+".postman_collection.json"))
+	if err = writeFile(file, append(b, '\n'), permRWRR); err != nil {
+		fatal(err)
+		return
+	}
+	recordArtifact(file)
+	log.Infof("Exported %s", file)
+}
+
+// newPostmanCollection method creates the Postman collection of the
+// OpenAPI document, folders and requests are sorted by name and path.
+func newPostmanCollection(doc *openAPIDoc, baseURL string) *postmanCollection {
+	c := &postmanCollection{
+		Info: &postmanInfo{Name: doc.Info.Title, Description: doc.Info.Description, Schema: postmanSchemaURL},
+		Variable: []*postmanVariable{
+			{Key: "baseUrl", Value: strings.TrimSuffix(baseURL, "/")},
+		},
+	}
+
+	var schemas map[string]*openAPISchema
+	security := map[string]*openAPISecurityScheme{}
+	if doc.Components != nil {
+		schemas, security = doc.Components.Schemas, doc.Components.SecuritySchemes
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for p := range doc.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	folders := map[string]*postmanItem{}
+	authVars := map[string]bool{}
+	for _, p := range paths {
+		for _, method := range sortedOperationMethods(doc.Paths[p]) {
+			op := doc.Paths[p][method]
+			item := &postmanItem{Name: firstNonEmpty(op.Summary, op.OperationID), Description: op.Description,
+				Request: newPostmanRequest(strings.ToUpper(method), p, op, schemas)}
+
+			for _, sec := range op.Security {
+				for name := range sec {
+					setPostmanAuth(item.Request, security[name], authVars)
+				}
+			}
+
+			folder := "default"
+			if len(op.Tags) > 0 {
+				folder = op.Tags[0]
+			}
+			if folders[folder] == nil {
+				folders[folder] = &postmanItem{Name: folder}
+				c.Item = append(c.Item, folders[folder])
+			}
+			folders[folder].Item = append(folders[folder].Item, item)
+		}
+	}
+	sort.Slice(c.Item, func(i, j int) bool { return c.Item[i].Name < c.Item[j].Name })
+
+	for _, v := range []string{"username", "password", "authToken"} {
+		if authVars[v] {
+			c.Variable = append(c.Variable, &postmanVariable{Key: v})
+		}
+	}
+	return c
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func newPostmanRequest(method, path string, op *openAPIOperation, schemas map[string]*openAPISchema) *postmanRequest {
+	u := &postmanURL{Host: []string{"{{baseUrl}}"}}
+	for _, seg := range strings.Split(strings.Trim(path, "/"), "/") {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			seg = ":" + seg[1:len(seg)-1]
+		}
+		u.Path = append(u.Path, seg)
+	}
+	u.Raw = "{{baseUrl}}/" + strings.Join(u.Path, "/")
+
+	var query []string
+	for _, p := range op.Parameters {
+		value := schemaExampleString(p.Schema, schemas)
+		switch p.In {
+		case "path":
+			u.Variable = append(u.Variable, &postmanKV{Key: p.Name, Value: value})
+		case "query":
+			u.Query = append(u.Query, &postmanKV{Key: p.Name, Value: value, Disabled: !p.Required})
+			if p.Required {
+				query = append(query, p.Name+"="+value)
+			}
+		}
+	}
+	if len(query) > 0 {
+		u.Raw += "?" + strings.Join(query, "&")
+	}
+
+	req := &postmanRequest{Method: method, Header: []*postmanKV{}, URL: u}
+	if op.RequestBody != nil {
+		if mt, found := op.RequestBody.Content["application/json"]; found {
+			raw, _ := json.MarshalIndent(schemaExample(mt.Schema, schemas, 0), "", "  ")
+			req.Header = append(req.Header, &postmanKV{Key: "Content-Type", Value: "application/json"})
+			req.Body = &postmanBody{Mode: "raw", Raw: string(raw),
+				Options: map[string]interface{}{"raw": map[string]string{"language": "json"}}}
+		}
+	}
+	return req
+}
+
+// setPostmanAuth method sets the request auth of the security scheme, session
+// cookie of form and oauth2 schemes is sent as header. Used variables are
+// marked.
+func setPostmanAuth(req *postmanRequest, scheme *openAPISecurityScheme, vars map[string]bool) {
+	switch {
+	case scheme != nil && scheme.Type == "http" && scheme.Scheme == "basic":
+		vars["username"], vars["password"] = true, true
+		req.Auth = &postmanAuth{Type: "basic", Basic: []*postmanKV{
+			{Key: "username", Value: "{{username}}"},
+			{Key: "password", Value: "{{password}}"},
+		}}
+	case scheme != nil && scheme.In == "cookie":
+		vars["authToken"] = true
+		req.Header = append(req.Header, &postmanKV{Key: "Cookie", Value: scheme.Name + "={{authToken}}"})
+	default:
+		vars["authToken"] = true
+		req.Auth = &postmanAuth{Type: "bearer", Bearer: []*postmanKV{{Key: "token", Value: "{{authToken}}"}}}
+	}
+}
+
+// schemaExample method returns the example value of the schema, referenced
+// schemas are resolved up to few levels.
+func schemaExample(s *openAPISchema, schemas map[string]*openAPISchema, depth int) interface{} {
+	if s == nil || depth > 5 {
+		return nil
+	}
+	if s.Ref != "" {
+		return schemaExample(schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")], schemas, depth+1)
+	}
+
+	switch s.Type {
+	case "string":
+		switch s.Format {
+		case "date-time":
+			return "2006-01-02T15:04:05Z"
+		case "byte":
+			return "c2FtcGxl"
+		}
+		return "sample"
+	case "integer":
+		return 1
+	case "number":
+		return 1.5
+	case "boolean":
+		return true
+	case "array":
+		if item := schemaExample(s.Items, schemas, depth+1); item != nil {
+			return []interface{}{item}
+		}
+		return []interface{}{}
+	case "object":
+		obj := map[string]interface{}{}
+		for name, ps := range s.Properties {
+			obj[name] = schemaExample(ps, schemas, depth+1)
+		}
+		return obj
+	}
+	return nil
+}
+
+func schemaExampleString(s *openAPISchema, schemas map[string]*openAPISchema) string {
+	switch v := schemaExample(s, schemas, 0).(type) {
+	case string:
+		return v
+	case nil:
+		return "sample"
+	default:
+		b, _ := json.Marshal(v)
+		return strings.Trim(string(b), "[]")
+	}
+}
+
+func sortedOperationMethods(ops map[string]*openAPIOperation) []string {
+	methods := make([]string, 0, len(ops))
+	for m := range ops {
+		methods = append(methods, m)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+func init() {
+	exportPostmanCmd.Run = exportPostmanRun
+	exportCmd.Subcommands = append(exportCmd.Subcommands, exportPostmanCmd)
+}
@@ -0,0 +1,129 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+var (
+	fmtHeadersCmdFlags  = flag.NewFlagSet("headers", flag.ContinueOnError)
+	fmtHeadersCheckFlag = fmtHeadersCmdFlags.Bool("check", false, "Report files missing the license header without modifying them, exits non-zero")
+	fmtHeadersExtFlag   = fmtHeadersCmdFlags.String("ext", ".go", "Comma separated file extensions to process, e.g. '.go,.sql'")
+	fmtHeadersCmd       = &command{
+		Name:      "headers",
+		UsageLine: "aah fmt headers [-check] [-ext]",
+		Flags:     fmtHeadersCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp},
+		Short:     "add license header to existing application files",
+		Long: `
+Prepends the license header configured via 'project.header_file' in
+'aah.project' to the application files which don't have it yet. The same
+header is added to the files generated by aah CLI tool, such as 'app/aah.go'
+and 'aah generate' scaffolds.
+
+    project {
+      header_file = "LICENSE_HEADER.txt"
+    }
+
+Header text is written as line comments of the file type, unless it's already
+a comment. Directories 'vendor', 'build' and dot directories are skipped.
+
+Example:
+    aah fmt headers
+
+    aah fmt headers -check
+
+    aah fmt headers -ext=.go,.sql,.conf
+`,
+	}
+	fmtCmd = &command{
+		Name:        "fmt",
+		UsageLine:   "aah fmt <subcommand>",
+		Short:       "format aah application files",
+		Subcommands: commands{fmtHeadersCmd},
+		Long: `
+Formatting related commands of aah application.
+
+Example:
+    aah fmt headers
+`,
+	}
+)
+
+func fmtHeadersRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	header, err := projectHeader()
+	if err != nil {
+		fatalErr(err)
+	}
+
+	if ess.IsStrEmpty(header) {
+		fatalErr(newExitErr(exitConfigError, errors.New("'project.header_file' is not configured in 'aah.project'")))
+	}
+
+	exts := splitList(*fmtHeadersExtFlag)
+	baseDir := appProject.BaseDir
+	var updated []string
+	err = ess.Walk(baseDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name := info.Name()
+		if info.IsDir() {
+			if path != baseDir && (strings.HasPrefix(name, ".") || name == "vendor" || name == "build") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !isStringInSlice(exts, filepath.Ext(name)) {
+			return nil
+		}
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		withHeader, err := addHeader(path, content)
+		if err != nil || len(withHeader) == len(content) {
+			return err
+		}
+
+		updated = append(updated, path)
+		if *fmtHeadersCheckFlag {
+			log.Infof("Missing header: %s", path)
+			return nil
+		}
+
+		log.Infof("Added header: %s", path)
+		return writeFile(path, withHeader, info.Mode())
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	if *fmtHeadersCheckFlag && len(updated) > 0 {
+		fatalExitf(exitError, "%d file(s) are missing the license header, run 'aah fmt headers'", len(updated))
+	}
+
+	log.Infof("\n%d file(s) updated with license header", len(updated))
+}
+
+func init() {
+	fmtHeadersCmd.Run = fmtHeadersRun
+}
@@ -0,0 +1,142 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+var generateCmd = &command{
+	Name:      "generate",
+	UsageLine: "aah generate <subcommand> [arguments]",
+	Short:     "generate source code and files for aah application",
+	Long: `
+Generates source code, configuration and other files for aah application.
+Generators run within the application base directory or use '-ip' flag
+where it's supported.
+
+Example:
+    aah generate controller User -actions=Index,Show
+`,
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Generator helper methods
+//___________________________________
+
+// writeGenFile method renders the template and writes into given file. Go
+// source files are formatted via 'go/format' and project license header is
+// prepended. It doesn't overwrite the existing file unless force is true.
+func writeGenFile(file, tmpl string, data interface{}, force bool) error {
+	if ess.IsFileExists(file) && !force {
+		return fmt.Errorf("file '%s' already exists, use '-force' to overwrite", file)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := renderTmpl(buf, tmpl, data); err != nil {
+		return err
+	}
+
+	return writeGenContent(file, buf.Bytes(), force)
+}
+
+// writeGenContent method writes the content into given file same as
+// 'writeGenFile' without template rendering, it's used for the files which
+// are templates themselves, e.g. Helm chart templates.
+func writeGenContent(file string, content []byte, force bool) error {
+	if ess.IsFileExists(file) && !force {
+		return fmt.Errorf("file '%s' already exists, use '-force' to overwrite", file)
+	}
+
+	if strings.HasSuffix(file, ".go") {
+		formatted, err := format.Source(content)
+		if err != nil {
+			return fmt.Errorf("generated source '%s' is invalid: %s", file, err)
+		}
+		content = formatted
+	}
+
+	content, err := addHeader(file, content)
+	if err != nil {
+		return err
+	}
+
+	if err := mkDirAll(filepath.Dir(file), permRWXRXRX); err != nil {
+		return err
+	}
+
+	if err := writeFile(file, content, permRWRWRW); err != nil {
+		return err
+	}
+
+	recordArtifact(file)
+	log.Infof("Generated %s", file)
+	return nil
+}
+
+// splitPkgName method splits the generator name into package and type
+// name, for e.g.: 'v1.User' or 'v1/User' becomes 'v1' and 'User'.
+func splitPkgName(name string) (string, string) {
+	name = strings.Replace(name, "/", ".", -1)
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		return strings.ToLower(name[:idx]), name[idx+1:]
+	}
+	return "", name
+}
+
+// toExported method returns the exported Go identifier for given name, for
+// e.g.: 'user_profile' becomes 'UserProfile'.
+func toExported(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-' || r == ' '
+	})
+
+	buf := &bytes.Buffer{}
+	for _, p := range parts {
+		r := []rune(p)
+		r[0] = unicode.ToUpper(r[0])
+		buf.WriteString(string(r))
+	}
+	return buf.String()
+}
+
+// toSnakeCase method returns the snake case of given name, for e.g.:
+// 'UserProfile' becomes 'user_profile'.
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	buf := &bytes.Buffer{}
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				buf.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		} else if r == '-' || r == ' ' {
+			r = '_'
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
+
+// splitList method splits the comma separated values and trims the spaces.
+func splitList(v string) []string {
+	var result []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); !ess.IsStrEmpty(s) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,132 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"path"
+	"path/filepath"
+)
+
+var (
+	genAssetsCmdFlags   = flag.NewFlagSet("assets", flag.ContinueOnError)
+	genAssetsPrefixFlag = genAssetsCmdFlags.String("prefix", "/static", "URL path prefix of the static files route of 'config/routes.conf'")
+	genAssetsForceFlag  = genAssetsCmdFlags.Bool("force", false, "Overwrite the existing generated file")
+	genAssetsCmd        = &command{
+		Name:      "assets",
+		UsageLine: "aah generate assets [-prefix] [-force]",
+		Flags:     genAssetsCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "generate helper to resolve fingerprinted static file names",
+		Long: `
+Generates the package 'app/assets' which resolves the static file name to
+its fingerprinted name via the asset manifest, created by 'aah package' with
+'build.assets.fingerprint = true' (refer 'aah help package'). Package adds
+the template func 'asset', file name is relative to 'static' directory:
+
+    <script src="{{ asset "js/app.js" }}"></script>
+    <!-- /static/js/app.3f2a9c1e.js -->
+
+Import the package once to register the template func, e.g. in controller:
+
+    import _ "<app-import-path>/app/assets"
+
+File name is resolved as-is if the manifest doesn't exist or the file is not
+in the manifest, e.g. 'aah run'. Fingerprinted files never change, serve
+them with far-future cache headers.
+
+Example:
+    aah generate assets
+
+    aah generate assets -prefix=/assets
+`,
+	}
+)
+
+type genAssets struct {
+	Prefix   string
+	Manifest string
+}
+
+func genAssetsRun(args []string) {
+	ac, err := newAssetsConfig(appProject.BuildCfg, appProject.BaseDir)
+	if err != nil {
+		fatalExit(exitConfigError, err)
+		return
+	}
+
+	data := &genAssets{
+		Prefix:   path.Join("/", *genAssetsPrefixFlag),
+		Manifest: path.Join(assetsStaticDir, ac.Manifest),
+	}
+	file := filepath.Join(appProject.BaseDir, "app", "assets", "assets.go")
+	if err = writeGenFile(file, genAssetsTemplate, data, *genAssetsForceFlag); err != nil {
+		fatal(err)
+	}
+}
+
+func init() {
+	genAssetsCmd.Run = genAssetsRun
+	generateCmd.Subcommands = append(generateCmd.Subcommands, genAssetsCmd)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Generator templates
+//___________________________________
+
+const genAssetsTemplate = `// Code generated by 'aah generate assets'. DO NOT EDIT.
+
+// Package assets resolves the static file names to the fingerprinted names
+// of the asset manifest '{{ .Manifest }}', created by 'aah package'.
+package assets
+
+import (
+	"encoding/json"
+	"html/template"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sync"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/log.v0"
+)
+
+// ManifestFile is the asset manifest path, relative to application base
+// directory.
+const ManifestFile = "{{ .Manifest }}"
+
+// Prefix is the URL path prefix of the static files route.
+const Prefix = "{{ .Prefix }}"
+
+var (
+	manifest     map[string]string
+	manifestOnce sync.Once
+)
+
+func init() {
+	aah.AddTemplateFunc(template.FuncMap{"asset": Path})
+}
+
+// Path returns the URL path of the static file, name is relative to 'static'
+// directory, e.g. 'js/app.js' returns '{{ .Prefix }}/js/app.3f2a9c1e.js'.
+func Path(name string) string {
+	manifestOnce.Do(loadManifest)
+	if hashed, found := manifest[name]; found {
+		name = hashed
+	}
+	return path.Join(Prefix, name)
+}
+
+func loadManifest() {
+	b, err := ioutil.ReadFile(filepath.Join(aah.AppBaseDir(), filepath.FromSlash(ManifestFile)))
+	if err != nil {
+		return
+	}
+	if err = json.Unmarshal(b, &manifest); err != nil {
+		log.Errorf("assets: unable to parse '%s': %s", ManifestFile, err)
+	}
+}
+`
@@ -0,0 +1,375 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+)
+
+var (
+	genCICmdFlags        = flag.NewFlagSet("ci", flag.ContinueOnError)
+	genCIProviderFlag    = genCICmdFlags.String("provider", "github", "CI provider 'github', 'gitlab' or 'jenkins'. Default is 'github'")
+	genCIGoFlag          = genCICmdFlags.String("go", "", "Comma separated Go versions. Default is 'ci.go_versions' of aah.project or '1.9.x'")
+	genCIProfilesFlag    = genCICmdFlags.String("profiles", "", "Comma separated environment profiles. Default is 'ci.profiles' of aah.project or 'prod'")
+	genCIPlatformsFlag   = genCICmdFlags.String("platforms", "", "Comma separated '<os>/<arch>' build matrix. Default is 'ci.platforms' of aah.project or 'linux/amd64'")
+	genCIOutputFlag      = genCICmdFlags.String("output", "", "Output file. Default is provider's workflow file, e.g: <app-base>/.github/workflows/aah.yml")
+	genCIOutputShortFlag = genCICmdFlags.String("o", "", "Output file. Default is provider's workflow file, e.g: <app-base>/.github/workflows/aah.yml")
+	genCIForceFlag       = genCICmdFlags.Bool("force", false, "Overwrite the existing file")
+	genCICmd             = &command{
+		Name:      "ci",
+		UsageLine: "aah generate ci [-provider] [-go] [-profiles] [-platforms] [-o | -output] [-force]",
+		Flags:     genCICmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "generate CI workflow for aah application",
+		Long: `
+Generates the CI workflow of the provider which runs 'aah test' for each Go
+version and profile, then 'aah build' for each profile and '<os>/<arch>' of
+the build matrix and uploads the build artifacts. Application is checked out
+into the GOPATH of the CI workspace.
+
+Supported providers:
+    github    GitHub Actions workflow '.github/workflows/aah.yml'
+    gitlab    GitLab CI pipeline '.gitlab-ci.yml'
+    jenkins   Jenkins declarative pipeline 'Jenkinsfile', it requires
+              Docker Pipeline and Matrix support
+
+GitLab and Jenkins run the jobs in 'golang' Docker image of the Go version,
+wildcard patch '.x' is trimmed for the image tag, e.g. '1.9.x' is
+'golang:1.9'.
+
+Go versions, profiles and platforms are read from 'ci' section of
+'aah.project', for e.g.:
+
+    ci {
+      go_versions = ["1.9.x", "1.10.x"]
+      profiles = ["qa", "prod"]
+      platforms = ["linux/amd64", "windows/amd64"]
+    }
+
+Example:
+    aah generate ci
+
+    aah generate ci -provider=github -go=1.9.x,1.10.x -profiles=qa,prod
+
+    aah generate ci -provider=gitlab -platforms=linux/amd64,windows/amd64
+`,
+	}
+
+	ciProviders = map[string]*ciProvider{
+		"github":  {File: filepath.Join(".github", "workflows", "aah.yml"), Template: genCIGitHubTemplate},
+		"gitlab":  {File: ".gitlab-ci.yml", Template: genCIGitLabTemplate},
+		"jenkins": {File: "Jenkinsfile", Template: genCIJenkinsTemplate},
+	}
+)
+
+type ciProvider struct {
+	File     string
+	Template string
+}
+
+type genCI struct {
+	Name       string
+	ImportPath string
+	GoVersions []string
+	BuildGo    string
+	Profiles   []string
+	Platforms  []ciPlatform
+}
+
+type ciPlatform struct {
+	OS   string
+	Arch string
+}
+
+func genCIRun(args []string) {
+	provider, found := ciProviders[*genCIProviderFlag]
+	if !found {
+		fatalExitf(exitUsage, "Unsupported CI provider '%s', choose one of 'github', 'gitlab' or 'jenkins'", *genCIProviderFlag)
+		return
+	}
+
+	ci, err := newGenCI(appProject.BuildCfg, *genCIGoFlag, *genCIProfilesFlag, *genCIPlatformsFlag)
+	if err != nil {
+		fatalErr(newExitErr(exitUsage, err))
+		return
+	}
+
+	file := firstNonEmpty(getNonEmptyAbsPath(*genCIOutputFlag, *genCIOutputShortFlag),
+		filepath.Join(appProject.BaseDir, provider.File))
+	if err := writeGenFile(file, provider.Template, ci, *genCIForceFlag); err != nil {
+		fatal(err)
+	}
+}
+
+// newGenCI method creates the CI workflow data, flag values take precedence
+// over 'ci' section of 'aah.project'. Build job uses the last Go version.
+func newGenCI(buildCfg *config.Config, goVersions, profiles, platforms string) (*genCI, error) {
+	ci := &genCI{
+		Name:       strings.ToLower(strings.Replace(aah.AppName(), " ", "_", -1)),
+		ImportPath: aah.AppImportPath(),
+		GoVersions: ciList(buildCfg, goVersions, "ci.go_versions", "1.9.x"),
+		Profiles:   ciList(buildCfg, profiles, "ci.profiles", "prod"),
+	}
+	ci.BuildGo = ci.GoVersions[len(ci.GoVersions)-1]
+
+	var err error
+	if ci.Platforms, err = parsePlatforms(ciList(buildCfg, platforms, "ci.platforms", "linux/amd64")); err != nil {
+		return nil, err
+	}
+	return ci, nil
+}
+
+// Matrix method returns the platforms as '-matrix' flag value of 'aah build'.
+func (ci *genCI) Matrix() string {
+	var targets []string
+	for _, p := range ci.Platforms {
+		targets = append(targets, p.OS+"/"+p.Arch)
+	}
+	return strings.Join(targets, ",")
+}
+
+// GoTags method returns the Go versions as 'golang' Docker image tags.
+func (ci *genCI) GoTags() []string {
+	var tags []string
+	for _, v := range ci.GoVersions {
+		tags = append(tags, goImageTag(v))
+	}
+	return tags
+}
+
+// BuildGoTag method returns the build Go version as 'golang' Docker image tag.
+func (ci *genCI) BuildGoTag() string {
+	return goImageTag(ci.BuildGo)
+}
+
+// goImageTag method trims the wildcard patch of the Go version, e.g.
+// '1.9.x' is '1.9'.
+func goImageTag(version string) string {
+	return strings.TrimSuffix(version, ".x")
+}
+
+// ciList method returns the comma separated flag values, otherwise list of
+// 'aah.project' key or the default value.
+// parsePlatforms method parses the '<os>/<arch>' values.
+func parsePlatforms(values []string) ([]ciPlatform, error) {
+	var platforms []ciPlatform
+	for _, p := range values {
+		parts := strings.Split(p, "/")
+		if len(parts) != 2 || ess.IsStrEmpty(parts[0]) || ess.IsStrEmpty(parts[1]) {
+			return nil, fmt.Errorf("invalid platform '%s', e.g: linux/amd64", p)
+		}
+		platforms = append(platforms, ciPlatform{OS: parts[0], Arch: parts[1]})
+	}
+	return platforms, nil
+}
+
+func ciList(buildCfg *config.Config, flagValue, key, defaultValue string) []string {
+	values := splitList(flagValue)
+	if len(values) == 0 {
+		values, _ = buildCfg.StringList(key)
+	}
+	if len(values) == 0 {
+		values = []string{defaultValue}
+	}
+	return values
+}
+
+func init() {
+	genCICmd.Run = genCIRun
+	generateCmd.Subcommands = append(generateCmd.Subcommands, genCICmd)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Generator templates
+//___________________________________
+
+// genCIGitHubTemplate is the GitHub Actions workflow, expressions '${{ }}'
+// are written via template actions.
+const genCIGitHubTemplate = `# Generated by 'aah generate ci' for GitHub Actions.
+name: aah
+
+on:
+  push:
+  pull_request:
+
+env:
+  GOPATH: {{ "${{" }} github.workspace }}
+  GO111MODULE: "off"
+
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    strategy:
+      matrix:
+        go: [{{ range $i, $v := .GoVersions }}{{ if $i }}, {{ end }}"{{ $v }}"{{ end }}]
+        profile: [{{ range $i, $v := .Profiles }}{{ if $i }}, {{ end }}"{{ $v }}"{{ end }}]
+    defaults:
+      run:
+        working-directory: src/{{ .ImportPath }}
+    steps:
+      - uses: actions/setup-go@v2
+        with:
+          go-version: {{ "${{" }} matrix.go }}
+      - uses: actions/checkout@v2
+        with:
+          path: src/{{ .ImportPath }}
+      - name: Install aah CLI
+        run: |
+          go get aahframework.org/tools.v0/aah
+          echo "$GOPATH/bin" >> $GITHUB_PATH
+      - name: Test
+        run: aah test -p={{ "${{" }} matrix.profile }}
+
+  build:
+    needs: test
+    runs-on: ubuntu-latest
+    strategy:
+      matrix:
+        profile: [{{ range $i, $v := .Profiles }}{{ if $i }}, {{ end }}"{{ $v }}"{{ end }}]
+    defaults:
+      run:
+        working-directory: src/{{ .ImportPath }}
+    steps:
+      - uses: actions/setup-go@v2
+        with:
+          go-version: "{{ .BuildGo }}"
+      - uses: actions/checkout@v2
+        with:
+          path: src/{{ .ImportPath }}
+      - name: Install aah CLI
+        run: |
+          go get aahframework.org/tools.v0/aah
+          echo "$GOPATH/bin" >> $GITHUB_PATH
+      - name: Build
+        run: aah build -matrix={{ .Matrix }} -p={{ "${{" }} matrix.profile }} -ap=build/{{ "${{" }} matrix.profile }}
+      - uses: actions/upload-artifact@v2
+        with:
+          name: {{ .Name }}-{{ "${{" }} matrix.profile }}
+          path: src/{{ .ImportPath }}/build/{{ "${{" }} matrix.profile }}/*.zip
+`
+
+// genCIGitLabTemplate is the GitLab CI pipeline, project directory is linked
+// into the GOPATH of 'golang' image.
+const genCIGitLabTemplate = `# Generated by 'aah generate ci' for GitLab CI.
+stages:
+  - test
+  - build
+
+variables:
+  GO111MODULE: "off"
+  APP_DIR: /go/src/{{ .ImportPath }}
+
+.aah: &aah
+  before_script:
+    - mkdir -p $(dirname $APP_DIR)
+    - ln -sfn $CI_PROJECT_DIR $APP_DIR
+    - cd $APP_DIR
+    - go get aahframework.org/tools.v0/aah
+
+test:
+  <<: *aah
+  stage: test
+  image: golang:$GO_VERSION
+  parallel:
+    matrix:
+      - GO_VERSION: [{{ range $i, $v := .GoTags }}{{ if $i }}, {{ end }}"{{ $v }}"{{ end }}]
+        PROFILE: [{{ range $i, $v := .Profiles }}{{ if $i }}, {{ end }}"{{ $v }}"{{ end }}]
+  script:
+    - aah test -p=$PROFILE
+
+build:
+  <<: *aah
+  stage: build
+  image: golang:{{ .BuildGoTag }}
+  parallel:
+    matrix:
+      - PROFILE: [{{ range $i, $v := .Profiles }}{{ if $i }}, {{ end }}"{{ $v }}"{{ end }}]
+  script:
+    - aah build -matrix={{ .Matrix }} -p=$PROFILE -ap=$CI_PROJECT_DIR/build/$PROFILE
+  artifacts:
+    name: "{{ .Name }}-$PROFILE"
+    paths:
+      - build/$PROFILE/*.zip
+`
+
+// genCIJenkinsTemplate is the Jenkins declarative pipeline, GOPATH is the
+// sibling directory '<workspace>@gopath' of the workspace.
+const genCIJenkinsTemplate = `// Generated by 'aah generate ci' for Jenkins.
+pipeline {
+  agent none
+  environment {
+    GO111MODULE = 'off'
+    APP_PKG = '{{ .ImportPath }}'
+  }
+  stages {
+    stage('Test') {
+      matrix {
+        axes {
+          axis {
+            name 'GO_VERSION'
+            values {{ range $i, $v := .GoTags }}{{ if $i }}, {{ end }}'{{ $v }}'{{ end }}
+          }
+          axis {
+            name 'PROFILE'
+            values {{ range $i, $v := .Profiles }}{{ if $i }}, {{ end }}'{{ $v }}'{{ end }}
+          }
+        }
+        agent {
+          docker { image "golang:${GO_VERSION}" }
+        }
+        stages {
+          stage('aah test') {
+            steps {
+              sh '''
+                export GOPATH="$WORKSPACE@gopath" PATH="$WORKSPACE@gopath/bin:$PATH"
+                mkdir -p "$(dirname "$GOPATH/src/$APP_PKG")"
+                ln -sfn "$WORKSPACE" "$GOPATH/src/$APP_PKG"
+                cd "$GOPATH/src/$APP_PKG"
+                go get aahframework.org/tools.v0/aah
+                aah test -p=$PROFILE
+              '''
+            }
+          }
+        }
+      }
+    }
+    stage('Build') {
+      matrix {
+        axes {
+          axis {
+            name 'PROFILE'
+            values {{ range $i, $v := .Profiles }}{{ if $i }}, {{ end }}'{{ $v }}'{{ end }}
+          }
+        }
+        agent {
+          docker { image 'golang:{{ .BuildGoTag }}' }
+        }
+        stages {
+          stage('aah build') {
+            steps {
+              sh '''
+                export GOPATH="$WORKSPACE@gopath" PATH="$WORKSPACE@gopath/bin:$PATH"
+                mkdir -p "$(dirname "$GOPATH/src/$APP_PKG")"
+                ln -sfn "$WORKSPACE" "$GOPATH/src/$APP_PKG"
+                cd "$GOPATH/src/$APP_PKG"
+                go get aahframework.org/tools.v0/aah
+                aah build -matrix={{ .Matrix }} -p=$PROFILE -ap="$WORKSPACE/build/$PROFILE"
+              '''
+              archiveArtifacts artifacts: "build/${PROFILE}/*.zip"
+            }
+          }
+        }
+      }
+    }
+  }
+}
+`
@@ -0,0 +1,230 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+)
+
+var (
+	genComposeCmdFlags        = flag.NewFlagSet("compose", flag.ContinueOnError)
+	genComposeDBFlag          = genComposeCmdFlags.String("db", "", "Database service 'postgres' or 'mysql'")
+	genComposeRedisFlag       = genComposeCmdFlags.Bool("redis", false, "Add redis service")
+	genComposeOutputFlag      = genComposeCmdFlags.String("output", "", "Output file. Default is <app-base>/docker-compose.yml")
+	genComposeOutputShortFlag = genComposeCmdFlags.String("o", "", "Output file. Default is <app-base>/docker-compose.yml")
+	genComposeForceFlag       = genComposeCmdFlags.Bool("force", false, "Overwrite the existing file")
+	genComposeCmd             = &command{
+		Name:      "compose",
+		UsageLine: "aah generate compose [-db] [-redis] [-o | -output] [-force]",
+		Flags:     genComposeCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "generate docker-compose.yml for local development",
+		Long: `
+Generates the 'docker-compose.yml' which runs the application via 'aah run'
+in a Go container. Application directory is mounted into the container's
+GOPATH, so the changes are hot-reloaded same as 'aah run' on the host. File
+events may not propagate on bind mounts (e.g. Docker for Mac/Windows), set
+'watch.poll = true' in 'aah.project' in that case.
+
+Optional services:
+    -db=postgres    PostgreSQL service with 'DB_*' environment variables
+    -db=mysql       MySQL service with 'DB_*' environment variables
+    -redis          Redis service with 'REDIS_ADDR' environment variable
+
+Application environment variables are mapped from app config values via
+'compose.env' section of 'aah.project', for e.g.:
+
+    compose {
+      # Default value is 'golang:1.9'
+      go_image = "golang:1.9"
+
+      # Environment variable name = app config key
+      env {
+        APP_SESSION_MODE = "security.session.mode"
+      }
+    }
+
+Example:
+    aah generate compose
+
+    aah generate compose -db=postgres -redis
+`,
+	}
+
+	composeDBServices = map[string]*composeService{
+		"postgres": {
+			Name:      "postgres",
+			Image:     "postgres:10-alpine",
+			Port:      "5432",
+			Data:      "/var/lib/postgresql/data",
+			DBNameEnv: "POSTGRES_DB",
+			Env: []composeEnv{
+				{"POSTGRES_USER", "aah"},
+				{"POSTGRES_PASSWORD", "aah"},
+			},
+		},
+		"mysql": {
+			Name:      "mysql",
+			Image:     "mysql:5.7",
+			Port:      "3306",
+			Data:      "/var/lib/mysql",
+			DBNameEnv: "MYSQL_DATABASE",
+			Env: []composeEnv{
+				{"MYSQL_USER", "aah"},
+				{"MYSQL_PASSWORD", "aah"},
+				{"MYSQL_ROOT_PASSWORD", "aah"},
+			},
+		},
+	}
+
+	composeRedisService = &composeService{
+		Name:  "redis",
+		Image: "redis:4-alpine",
+		Port:  "6379",
+		Data:  "/data",
+	}
+)
+
+type genCompose struct {
+	Name       string
+	ImportPath string
+	GoImage    string
+	Port       string
+	Env        []composeEnv
+	Services   []*composeService
+}
+
+type composeService struct {
+	Name      string
+	Image     string
+	Port      string
+	Data      string
+	DBNameEnv string
+	Env       []composeEnv
+}
+
+type composeEnv struct {
+	Name  string
+	Value string
+}
+
+func genComposeRun(args []string) {
+	db := *genComposeDBFlag
+	if !ess.IsStrEmpty(db) {
+		if _, found := composeDBServices[db]; !found {
+			fatalExitf(exitUsage, "Unsupported database service '%s', choose either 'postgres' or 'mysql'", db)
+			return
+		}
+	}
+
+	appCfg := aah.AppConfig()
+	compose, err := newGenCompose(appProject.BuildCfg, appCfg, db, *genComposeRedisFlag)
+	if err != nil {
+		fatalErr(newExitErr(exitConfigError, err))
+		return
+	}
+
+	file := firstNonEmpty(getNonEmptyAbsPath(*genComposeOutputFlag, *genComposeOutputShortFlag),
+		filepath.Join(appProject.BaseDir, "docker-compose.yml"))
+	if err := writeGenFile(file, genComposeTemplate, compose, *genComposeForceFlag); err != nil {
+		fatal(err)
+	}
+}
+
+// newGenCompose method creates the compose data from 'aah.project' and the
+// app config. App environment variables are sorted by name for stable output.
+func newGenCompose(buildCfg, appCfg *config.Config, db string, redis bool) (*genCompose, error) {
+	name := strings.ToLower(strings.Replace(aah.AppName(), " ", "_", -1))
+	compose := &genCompose{
+		Name:       name,
+		ImportPath: aah.AppImportPath(),
+		GoImage:    buildCfg.StringDefault("compose.go_image", "golang:1.9"),
+		Port:       appCfg.StringDefault("server.port", "8080"),
+	}
+
+	compose.Env = append(compose.Env, composeEnv{appPortEnv, compose.Port})
+	if envCfg, found := buildCfg.GetSubConfig("compose.env"); found {
+		keys := envCfg.Keys()
+		sort.Strings(keys)
+		for _, k := range keys {
+			cfgKey := envCfg.StringDefault(k, "")
+			v, found := appCfg.Get(cfgKey)
+			if !found {
+				return nil, fmt.Errorf("'compose.env.%s': app config key '%s' does not exists", k, cfgKey)
+			}
+			compose.Env = append(compose.Env, composeEnv{k, fmt.Sprint(v)})
+		}
+	}
+
+	if svc, found := composeDBServices[db]; found {
+		dbService := &composeService{Name: svc.Name, Image: svc.Image, Port: svc.Port, Data: svc.Data}
+		dbService.Env = append([]composeEnv{{svc.DBNameEnv, name}}, svc.Env...)
+
+		compose.Services = append(compose.Services, dbService)
+		compose.Env = append(compose.Env,
+			composeEnv{"DB_HOST", svc.Name},
+			composeEnv{"DB_PORT", svc.Port},
+			composeEnv{"DB_NAME", name},
+			composeEnv{"DB_USER", "aah"},
+			composeEnv{"DB_PASSWORD", "aah"},
+		)
+	}
+
+	if redis {
+		compose.Services = append(compose.Services, composeRedisService)
+		compose.Env = append(compose.Env, composeEnv{"REDIS_ADDR", "redis:" + composeRedisService.Port})
+	}
+
+	return compose, nil
+}
+
+func init() {
+	genComposeCmd.Run = genComposeRun
+	generateCmd.Subcommands = append(generateCmd.Subcommands, genComposeCmd)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Generator templates
+//___________________________________
+
+const genComposeTemplate = `# Generated by 'aah generate compose' for local development.
+version: "3"
+
+services:
+  app:
+    image: {{ .GoImage }}
+    working_dir: /go/src/{{ .ImportPath }}
+    command: sh -c "go get aahframework.org/tools.v0/aah && aah run"
+    ports:
+      - "{{ .Port }}:{{ .Port }}"
+    environment:{{ range .Env }}
+      {{ .Name }}: "{{ .Value }}"{{ end }}
+    volumes:
+      - .:/go/src/{{ .ImportPath }}
+      - gopkg:/go/pkg{{ if .Services }}
+    depends_on:{{ range .Services }}
+      - {{ .Name }}{{ end }}{{ end }}
+{{ range .Services }}
+  {{ .Name }}:
+    image: {{ .Image }}
+    ports:
+      - "{{ .Port }}:{{ .Port }}"{{ if .Env }}
+    environment:{{ range .Env }}
+      {{ .Name }}: "{{ .Value }}"{{ end }}{{ end }}
+    volumes:
+      - {{ .Name }}-data:{{ .Data }}
+{{ end }}
+volumes:
+  gopkg:{{ range .Services }}
+  {{ .Name }}-data:{{ end }}
+`
@@ -0,0 +1,229 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"aahframework.org/log.v0"
+)
+
+const routesEndMarker = "} # end - routes"
+
+var (
+	genControllerCmdFlags   = flag.NewFlagSet("controller", flag.ContinueOnError)
+	genControllerActionFlag = genControllerCmdFlags.String("actions", "Index", "Comma separated controller action names")
+	genControllerRoutesFlag = genControllerCmdFlags.Bool("routes", false, "Append matching route entries to 'config/routes.conf'")
+	genControllerNoTestFlag = genControllerCmdFlags.Bool("notest", false, "Do not generate controller test file")
+	genControllerForceFlag  = genControllerCmdFlags.Bool("force", false, "Overwrite the existing files")
+	genControllerCmd        = &command{
+		Name:      "controller",
+		UsageLine: "aah generate controller <Name> [-actions] [-routes] [-notest] [-force]",
+		Flags:     genControllerCmdFlags,
+		MaxArgs:   1,
+		Hooks:     []hook{validateGopath, requireInsideApp},
+		Short:     "generate controller with stub actions",
+		Long: `
+Generates the controller 'app/controllers/<name>.go' embedding 'aah.Context'
+with stub actions and a matching test file. Controller name can have package
+prefix, for e.g.: 'v1.User' generates 'app/controllers/v1/user.go'.
+
+With '-routes' flag route entries are appended to 'config/routes.conf'
+    Index     GET     /<name>
+    Show      GET     /<name>/:id
+    Create    POST    /<name>
+    Update    PUT     /<name>/:id
+    Delete    DELETE  /<name>/:id
+    Other     GET     /<name>/<action>
+
+Example:
+    aah generate controller User
+
+    aah generate controller v1.User -actions=Index,Show,Create -routes
+`,
+	}
+)
+
+type (
+	genController struct {
+		Package    string
+		Name       string
+		Controller string
+		RoutePath  string
+		Actions    []*genAction
+	}
+
+	genAction struct {
+		Name      string
+		Method    string
+		Path      string
+		RouteName string
+	}
+)
+
+func genControllerRun(args []string) {
+	if len(args) == 0 {
+		fatalExit(exitUsage, "Controller name is required. Run 'aah help generate controller'.")
+		return
+	}
+
+	pkg, name := splitPkgName(args[0])
+	ctrl := newGenController(pkg, toExported(name), splitList(*genControllerActionFlag))
+	ctrlDir := filepath.Join(appProject.BaseDir, "app", "controllers", filepath.FromSlash(strings.Replace(pkg, ".", "/", -1)))
+	fileName := toSnakeCase(ctrl.Name)
+
+	force := *genControllerForceFlag
+	if err := writeGenFile(filepath.Join(ctrlDir, fileName+".go"), genControllerTemplate, ctrl, force); err != nil {
+		fatal(err)
+	}
+
+	if !*genControllerNoTestFlag {
+		if err := writeGenFile(filepath.Join(ctrlDir, fileName+"_test.go"), genControllerTestTemplate, ctrl, force); err != nil {
+			fatal(err)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := renderTmpl(buf, genRoutesTemplate, ctrl); err != nil {
+		fatal(err)
+	}
+
+	if *genControllerRoutesFlag {
+		routesFile := filepath.Join(appProject.BaseDir, "config", "routes.conf")
+		if err := appendRoutes(routesFile, buf.String()); err != nil {
+			log.Errorf("Unable to append routes: %s", err)
+			log.Infof("Add below routes into 'routes' section of %s\n%s", routesFile, buf.String())
+			return
+		}
+		log.Infof("Routes appended to %s", routesFile)
+		return
+	}
+
+	log.Infof("Add below routes into 'routes' section of 'config/routes.conf'\n%s", buf.String())
+}
+
+func newGenController(pkg, name string, actions []string) *genController {
+	ctrl := &genController{
+		Package:    "controllers",
+		Name:       name,
+		Controller: name,
+		RoutePath:  "/" + toSnakeCase(strings.TrimSuffix(name, "Controller")),
+	}
+
+	routePrefix := toSnakeCase(strings.TrimSuffix(name, "Controller"))
+	if len(pkg) > 0 {
+		ctrl.Package = path.Base(strings.Replace(pkg, ".", "/", -1))
+		ctrl.Controller = pkg + "." + name
+		ctrl.RoutePath = "/" + strings.Replace(pkg, ".", "/", -1) + ctrl.RoutePath
+		routePrefix = strings.Replace(pkg, ".", "_", -1) + "_" + routePrefix
+	}
+
+	for _, a := range actions {
+		action := &genAction{
+			Name:      toExported(a),
+			Method:    "GET",
+			RouteName: routePrefix + "_" + toSnakeCase(toExported(a)),
+		}
+
+		switch action.Name {
+		case "Index":
+			action.Path = ctrl.RoutePath
+		case "Show":
+			action.Path = ctrl.RoutePath + "/:id"
+		case "Create":
+			action.Method, action.Path = "POST", ctrl.RoutePath
+		case "Update":
+			action.Method, action.Path = "PUT", ctrl.RoutePath+"/:id"
+		case "Delete":
+			action.Method, action.Path = "DELETE", ctrl.RoutePath+"/:id"
+		default:
+			action.Path = ctrl.RoutePath + "/" + toSnakeCase(action.Name)
+		}
+		ctrl.Actions = append(ctrl.Actions, action)
+	}
+
+	return ctrl
+}
+
+// appendRoutes method inserts the routes before the end of 'routes' section,
+// it's identified by marker '} # end - routes' from aah application template.
+func appendRoutes(routesFile, routes string) error {
+	content, err := ioutil.ReadFile(routesFile)
+	if err != nil {
+		return err
+	}
+
+	idx := bytes.Index(content, []byte(routesEndMarker))
+	if idx == -1 {
+		return fmt.Errorf("marker '%s' not found", routesEndMarker)
+	}
+
+	// insert at the beginning of the marker line
+	lineStart := bytes.LastIndexByte(content[:idx], '\n') + 1
+
+	buf := &bytes.Buffer{}
+	buf.Write(content[:lineStart])
+	buf.WriteString(routes)
+	buf.WriteString("\n")
+	buf.Write(content[lineStart:])
+
+	return writeFile(routesFile, buf.Bytes(), permRWRWRW)
+}
+
+func init() {
+	genControllerCmd.Run = genControllerRun
+	generateCmd.Subcommands = append(generateCmd.Subcommands, genControllerCmd)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Generate Templates
+//___________________________________
+
+const genControllerTemplate = `package {{ .Package }}
+
+import (
+	"aahframework.org/aah.v0"
+)
+
+// {{ .Name }} struct is the {{ .Name }} controller.
+type {{ .Name }} struct {
+	*aah.Context
+}
+{{ range .Actions }}
+// {{ .Name }} method handles '{{ .Method }} {{ .Path }}'.
+func (c *{{ $.Name }}) {{ .Name }}() {
+	c.Reply().Ok().JSON(aah.Data{
+		"message": "{{ $.Controller }}.{{ .Name }}",
+	})
+}
+{{ end }}`
+
+const genControllerTestTemplate = `package {{ .Package }}
+
+import (
+	"testing"
+)
+{{ range .Actions }}
+func Test{{ $.Name }}{{ .Name }}(t *testing.T) {
+	t.Skip("TODO: test {{ .Method }} {{ .Path }} - {{ $.Controller }}.{{ .Name }}")
+}
+{{ end }}`
+
+const genRoutesTemplate = `      # {{ .Controller }} controller routes
+{{- range .Actions }}
+      {{ .RouteName }} {
+        path = "{{ .Path }}"
+        method = "{{ .Method }}"
+        controller = "{{ $.Controller }}"
+        action = "{{ .Name }}"
+      }
+{{- end }}
+`
@@ -0,0 +1,262 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"path/filepath"
+	"strings"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/essentials.v0"
+)
+
+var (
+	genHelmCmdFlags        = flag.NewFlagSet("helm", flag.ContinueOnError)
+	genHelmImageFlag       = genHelmCmdFlags.String("image", "", "Container image. Default is '<app-name>:<app-version>'")
+	genHelmOutputFlag      = genHelmCmdFlags.String("output", "", "Output directory. Default is <app-base>/helm/<app-name>")
+	genHelmOutputShortFlag = genHelmCmdFlags.String("o", "", "Output directory. Default is <app-base>/helm/<app-name>")
+	genHelmForceFlag       = genHelmCmdFlags.Bool("force", false, "Overwrite the existing files")
+	genHelmCmd             = &command{
+		Name:      "helm",
+		UsageLine: "aah generate helm [-image] [-o | -output] [-force]",
+		Flags:     genHelmCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "generate Helm chart",
+		Long: `
+Generates the Helm chart into '<app-base>/helm/<app-name>' directory:
+
+    Chart.yaml                  Chart with application name and version
+    values.yaml                 Image, replicas, service port, ingress,
+                                health path and resources
+    templates/_helpers.tpl      Chart name and labels helpers
+    templates/deployment.yaml   Deployment with resources and health probes
+    templates/service.yaml      Service on application 'server.port'
+    templates/ingress.yaml      Ingress, enabled via 'ingress.enabled'
+
+Default values are taken from 'k8s' section of 'aah.project', same as
+'aah generate k8s'. Chart 'appVersion' and image tag are application version.
+
+Example:
+    aah generate helm
+
+    aah generate helm -image=registry.example.com/myapp:1.0.0
+
+    helm install ./helm/myapp --set ingress.enabled=true,ingress.host=myapp.example.com
+`,
+	}
+)
+
+type genHelm struct {
+	*genK8s
+	ImageRepository string
+	ImageTag        string
+}
+
+func genHelmRun(args []string) {
+	k8s := newGenK8s(appProject.BuildCfg, aah.AppConfig())
+	if !ess.IsStrEmpty(*genHelmImageFlag) {
+		k8s.Image = *genHelmImageFlag
+	}
+
+	helm := &genHelm{genK8s: k8s}
+	helm.ImageRepository, helm.ImageTag = splitImage(k8s.Image)
+	if ess.IsStrEmpty(helm.ImageTag) {
+		helm.ImageTag = k8s.Version
+	}
+
+	chartDir := firstNonEmpty(getNonEmptyAbsPath(*genHelmOutputFlag, *genHelmOutputShortFlag),
+		filepath.Join(appProject.BaseDir, "helm", k8s.Name))
+	force := *genHelmForceFlag
+
+	if err := writeGenFile(filepath.Join(chartDir, "Chart.yaml"), genHelmChartTemplate, helm, force); err != nil {
+		fatal(err)
+	}
+	if err := writeGenFile(filepath.Join(chartDir, "values.yaml"), genHelmValuesTemplate, helm, force); err != nil {
+		fatal(err)
+	}
+
+	// chart templates are Helm templates, written as-is
+	templates := []struct {
+		file    string
+		content string
+	}{
+		{"_helpers.tpl", genHelmHelpersContent},
+		{"deployment.yaml", genHelmDeploymentContent},
+		{"service.yaml", genHelmServiceContent},
+		{"ingress.yaml", genHelmIngressContent},
+	}
+	for _, t := range templates {
+		file := filepath.Join(chartDir, "templates", t.file)
+		if err := writeGenContent(file, []byte(t.content), force); err != nil {
+			fatal(err)
+		}
+	}
+}
+
+// splitImage method splits the container image into repository and tag,
+// for e.g.: 'localhost:5000/myapp:1.0' becomes 'localhost:5000/myapp' and '1.0'.
+func splitImage(image string) (string, string) {
+	idx := strings.LastIndex(image, ":")
+	if idx == -1 || strings.Contains(image[idx+1:], "/") {
+		return image, ""
+	}
+	return image[:idx], image[idx+1:]
+}
+
+func init() {
+	genHelmCmd.Run = genHelmRun
+	generateCmd.Subcommands = append(generateCmd.Subcommands, genHelmCmd)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Generator templates
+//___________________________________
+
+const genHelmChartTemplate = `# Generated by 'aah generate helm'.
+apiVersion: v1
+name: {{ .Name }}
+description: Helm chart of aah application '{{ .Name }}'
+version: 0.1.0
+appVersion: "{{ .Version }}"
+`
+
+const genHelmValuesTemplate = `# Generated by 'aah generate helm'.
+replicaCount: {{ .Replicas }}
+
+image:
+  repository: {{ .ImageRepository }}
+  tag: "{{ .ImageTag }}"
+  pullPolicy: IfNotPresent
+
+service:
+  type: ClusterIP
+  port: {{ .Port }}
+
+# Health endpoint path of readiness and liveness probes
+healthPath: {{ .HealthPath }}
+
+ingress:
+  enabled: false
+  host: ""
+  annotations: {}
+
+resources:
+  requests:
+    cpu: {{ .Resources.RequestsCPU }}
+    memory: {{ .Resources.RequestsMemory }}
+  limits:
+    cpu: {{ .Resources.LimitsCPU }}
+    memory: {{ .Resources.LimitsMemory }}
+`
+
+const genHelmHelpersContent = `{{/* Generated by 'aah generate helm'. */}}
+
+{{- define "app.fullname" -}}
+{{- if contains .Chart.Name .Release.Name -}}
+{{- .Release.Name | trunc 63 | trimSuffix "-" -}}
+{{- else -}}
+{{- printf "%s-%s" .Release.Name .Chart.Name | trunc 63 | trimSuffix "-" -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "app.labels" -}}
+app: {{ .Chart.Name }}
+release: {{ .Release.Name }}
+version: {{ .Chart.AppVersion | quote }}
+{{- end -}}
+
+{{- define "app.selectorLabels" -}}
+app: {{ .Chart.Name }}
+release: {{ .Release.Name }}
+{{- end -}}
+`
+
+const genHelmDeploymentContent = `# Generated by 'aah generate helm'.
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ include "app.fullname" . }}
+  labels:
+{{ include "app.labels" . | indent 4 }}
+spec:
+  replicas: {{ .Values.replicaCount }}
+  selector:
+    matchLabels:
+{{ include "app.selectorLabels" . | indent 6 }}
+  template:
+    metadata:
+      labels:
+{{ include "app.labels" . | indent 8 }}
+    spec:
+      containers:
+        - name: {{ .Chart.Name }}
+          image: "{{ .Values.image.repository }}:{{ .Values.image.tag }}"
+          imagePullPolicy: {{ .Values.image.pullPolicy }}
+          ports:
+            - name: http
+              containerPort: {{ .Values.service.port }}
+          env:
+            - name: AAH_SERVER_PORT
+              value: {{ .Values.service.port | quote }}
+          readinessProbe:
+            httpGet:
+              path: {{ .Values.healthPath }}
+              port: http
+            initialDelaySeconds: 5
+            periodSeconds: 10
+          livenessProbe:
+            httpGet:
+              path: {{ .Values.healthPath }}
+              port: http
+            initialDelaySeconds: 15
+            periodSeconds: 20
+          resources:
+{{ toYaml .Values.resources | indent 12 }}
+`
+
+const genHelmServiceContent = `# Generated by 'aah generate helm'.
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ include "app.fullname" . }}
+  labels:
+{{ include "app.labels" . | indent 4 }}
+spec:
+  type: {{ .Values.service.type }}
+  selector:
+{{ include "app.selectorLabels" . | indent 4 }}
+  ports:
+    - name: http
+      port: {{ .Values.service.port }}
+      targetPort: http
+`
+
+const genHelmIngressContent = `# Generated by 'aah generate helm'.
+{{- if .Values.ingress.enabled }}
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: {{ include "app.fullname" . }}
+  labels:
+{{ include "app.labels" . | indent 4 }}
+{{- with .Values.ingress.annotations }}
+  annotations:
+{{ toYaml . | indent 4 }}
+{{- end }}
+spec:
+  rules:
+    - host: {{ .Values.ingress.host | quote }}
+      http:
+        paths:
+          - path: /
+            pathType: Prefix
+            backend:
+              service:
+                name: {{ include "app.fullname" . }}
+                port:
+                  name: http
+{{- end }}
+`
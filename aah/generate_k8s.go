@@ -0,0 +1,327 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+)
+
+var (
+	genK8sCmdFlags        = flag.NewFlagSet("k8s", flag.ContinueOnError)
+	genK8sImageFlag       = genK8sCmdFlags.String("image", "", "Container image. Default is '<app-name>:<app-version>'")
+	genK8sReplicasFlag    = genK8sCmdFlags.Int("replicas", 0, "Deployment replicas. Default is 1")
+	genK8sIngressFlag     = genK8sCmdFlags.String("ingress", "", "Ingress host, Ingress manifest is generated only if supplied")
+	genK8sHealthFlag      = genK8sCmdFlags.String("health", "", "Health endpoint path for probes. Default is '/'")
+	genK8sOutputFlag      = genK8sCmdFlags.String("output", "", "Output directory. Default is <app-base>/k8s")
+	genK8sOutputShortFlag = genK8sCmdFlags.String("o", "", "Output directory. Default is <app-base>/k8s")
+	genK8sForceFlag       = genK8sCmdFlags.Bool("force", false, "Overwrite the existing files")
+	genK8sCmd             = &command{
+		Name:      "k8s",
+		UsageLine: "aah generate k8s [-image] [-replicas] [-ingress] [-health] [-o | -output] [-force]",
+		Flags:     genK8sCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "generate Kubernetes manifests",
+		Long: `
+Generates the Kubernetes manifests into '<app-base>/k8s' directory:
+
+    configmap.yaml     Application config files from 'config' directory
+    deployment.yaml    Deployment with resources and health probes
+    service.yaml       Service on application 'server.port'
+    ingress.yaml       Ingress for the given host, only with '-ingress'
+
+ConfigMap is mounted on '<app_dir>/config' of the container, so the config
+changes don't require an image rebuild. Readiness and liveness probes are
+wired to the application 'server.port' and health endpoint path.
+
+Defaults are configured via 'k8s' section of 'aah.project', for e.g.:
+
+    k8s {
+      image = "registry.example.com/myapp:1.0.0"
+      namespace = "default"
+      replicas = 2
+      app_dir = "/app"
+      health_path = "/health"
+
+      resources {
+        requests { cpu = "100m", memory = "128Mi" }
+        limits { cpu = "500m", memory = "256Mi" }
+      }
+    }
+
+Example:
+    aah generate k8s
+
+    aah generate k8s -image=registry.example.com/myapp:1.0.0 -ingress=myapp.example.com
+`,
+	}
+)
+
+type genK8s struct {
+	Name        string
+	Namespace   string
+	Version     string
+	Image       string
+	Replicas    int
+	Port        string
+	AppDir      string
+	HealthPath  string
+	IngressHost string
+	Resources   k8sResources
+	ConfigFiles []*k8sConfigFile
+}
+
+type k8sResources struct {
+	RequestsCPU    string
+	RequestsMemory string
+	LimitsCPU      string
+	LimitsMemory   string
+}
+
+type k8sConfigFile struct {
+	Key     string
+	Path    string
+	Content string
+}
+
+func genK8sRun(args []string) {
+	appCfg := aah.AppConfig()
+	k8s := newGenK8s(appProject.BuildCfg, appCfg)
+
+	if !ess.IsStrEmpty(*genK8sImageFlag) {
+		k8s.Image = *genK8sImageFlag
+	}
+	if *genK8sReplicasFlag > 0 {
+		k8s.Replicas = *genK8sReplicasFlag
+	}
+	if !ess.IsStrEmpty(*genK8sHealthFlag) {
+		k8s.HealthPath = *genK8sHealthFlag
+	}
+	k8s.IngressHost = *genK8sIngressFlag
+
+	configFiles, err := k8sConfigFiles(filepath.Join(appProject.BaseDir, "config"))
+	if err != nil {
+		fatalErr(newExitErr(exitConfigError, err))
+		return
+	}
+	k8s.ConfigFiles = configFiles
+
+	outDir := firstNonEmpty(getNonEmptyAbsPath(*genK8sOutputFlag, *genK8sOutputShortFlag),
+		filepath.Join(appProject.BaseDir, "k8s"))
+	manifests := []struct {
+		file string
+		tmpl string
+	}{
+		{"configmap.yaml", genK8sConfigMapTemplate},
+		{"deployment.yaml", genK8sDeploymentTemplate},
+		{"service.yaml", genK8sServiceTemplate},
+	}
+	if !ess.IsStrEmpty(k8s.IngressHost) {
+		manifests = append(manifests, struct {
+			file string
+			tmpl string
+		}{"ingress.yaml", genK8sIngressTemplate})
+	}
+
+	for _, m := range manifests {
+		if err := writeGenFile(filepath.Join(outDir, m.file), m.tmpl, k8s, *genK8sForceFlag); err != nil {
+			fatal(err)
+		}
+	}
+}
+
+// newGenK8s method creates the manifests data from 'k8s' section of
+// 'aah.project' and the app config.
+func newGenK8s(buildCfg, appCfg *config.Config) *genK8s {
+	name := strings.ToLower(strings.Replace(aah.AppName(), " ", "-", -1))
+	version := versionLabel(getAppVersion(appProject.BaseDir, buildCfg))
+	return &genK8s{
+		Name:       name,
+		Namespace:  buildCfg.StringDefault("k8s.namespace", ""),
+		Version:    version,
+		Image:      buildCfg.StringDefault("k8s.image", name+":"+version),
+		Replicas:   buildCfg.IntDefault("k8s.replicas", 1),
+		Port:       appCfg.StringDefault("server.port", "8080"),
+		AppDir:     buildCfg.StringDefault("k8s.app_dir", "/app"),
+		HealthPath: buildCfg.StringDefault("k8s.health_path", "/"),
+		Resources: k8sResources{
+			RequestsCPU:    buildCfg.StringDefault("k8s.resources.requests.cpu", "100m"),
+			RequestsMemory: buildCfg.StringDefault("k8s.resources.requests.memory", "128Mi"),
+			LimitsCPU:      buildCfg.StringDefault("k8s.resources.limits.cpu", "500m"),
+			LimitsMemory:   buildCfg.StringDefault("k8s.resources.limits.memory", "256Mi"),
+		},
+	}
+}
+
+// k8sConfigFiles method reads the application config files for ConfigMap.
+// ConfigMap keys can't have '/', so the relative path is flattened for e.g.:
+// 'env/prod.conf' becomes 'env_prod.conf' and mapped back via volume items.
+func k8sConfigFiles(configDir string) ([]*k8sConfigFile, error) {
+	var files []*k8sConfigFile
+	err := filepath.Walk(configDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() || filepath.Ext(p) != ".conf" {
+			return nil
+		}
+
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		rel, _ := filepath.Rel(configDir, p)
+		rel = filepath.ToSlash(rel)
+		files = append(files, &k8sConfigFile{
+			Key:     strings.Replace(rel, "/", "_", -1),
+			Path:    rel,
+			Content: indentLines(string(b), "    "),
+		})
+		return nil
+	})
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Key < files[j].Key })
+	return files, err
+}
+
+// indentLines method prefixes every non-empty line with given indent, it's
+// used for YAML block scalars.
+func indentLines(s, indent string) string {
+	lines := strings.Split(strings.TrimRight(s, "\r\n"), "\n")
+	for i, l := range lines {
+		l = strings.TrimRight(l, "\r")
+		if !ess.IsStrEmpty(l) {
+			l = indent + l
+		}
+		lines[i] = l
+	}
+	return strings.Join(lines, "\n")
+}
+
+func init() {
+	genK8sCmd.Run = genK8sRun
+	generateCmd.Subcommands = append(generateCmd.Subcommands, genK8sCmd)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Generator templates
+//___________________________________
+
+const genK8sMetadataTemplate = `  name: {{ .Name }}{{ if .Namespace }}
+  namespace: {{ .Namespace }}{{ end }}
+  labels:
+    app: {{ .Name }}
+    version: "{{ .Version }}"`
+
+const genK8sConfigMapTemplate = `# Generated by 'aah generate k8s'.
+apiVersion: v1
+kind: ConfigMap
+metadata:
+` + genK8sMetadataTemplate + `
+data:{{ range .ConfigFiles }}
+  {{ .Key }}: |
+{{ .Content }}{{ end }}
+`
+
+const genK8sDeploymentTemplate = `# Generated by 'aah generate k8s'.
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+` + genK8sMetadataTemplate + `
+spec:
+  replicas: {{ .Replicas }}
+  selector:
+    matchLabels:
+      app: {{ .Name }}
+  template:
+    metadata:
+      labels:
+        app: {{ .Name }}
+        version: "{{ .Version }}"
+    spec:
+      containers:
+        - name: {{ .Name }}
+          image: {{ .Image }}
+          ports:
+            - name: http
+              containerPort: {{ .Port }}
+          env:
+            - name: AAH_SERVER_PORT
+              value: "{{ .Port }}"
+          resources:
+            requests:
+              cpu: {{ .Resources.RequestsCPU }}
+              memory: {{ .Resources.RequestsMemory }}
+            limits:
+              cpu: {{ .Resources.LimitsCPU }}
+              memory: {{ .Resources.LimitsMemory }}
+          readinessProbe:
+            httpGet:
+              path: {{ .HealthPath }}
+              port: http
+            initialDelaySeconds: 5
+            periodSeconds: 10
+          livenessProbe:
+            httpGet:
+              path: {{ .HealthPath }}
+              port: http
+            initialDelaySeconds: 15
+            periodSeconds: 20
+          volumeMounts:
+            - name: config
+              mountPath: {{ .AppDir }}/config
+              readOnly: true
+      volumes:
+        - name: config
+          configMap:
+            name: {{ .Name }}
+            items:{{ range .ConfigFiles }}
+              - key: {{ .Key }}
+                path: {{ .Path }}{{ end }}
+`
+
+const genK8sServiceTemplate = `# Generated by 'aah generate k8s'.
+apiVersion: v1
+kind: Service
+metadata:
+` + genK8sMetadataTemplate + `
+spec:
+  type: ClusterIP
+  selector:
+    app: {{ .Name }}
+  ports:
+    - name: http
+      port: {{ .Port }}
+      targetPort: http
+`
+
+const genK8sIngressTemplate = `# Generated by 'aah generate k8s'.
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+` + genK8sMetadataTemplate + `
+spec:
+  rules:
+    - host: {{ .IngressHost }}
+      http:
+        paths:
+          - path: /
+            pathType: Prefix
+            backend:
+              service:
+                name: {{ .Name }}
+                port:
+                  name: http
+`
@@ -0,0 +1,110 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"path/filepath"
+
+	"aahframework.org/log.v0"
+)
+
+var (
+	genMiddlewareCmdFlags = flag.NewFlagSet("middleware", flag.ContinueOnError)
+	genMiddlewareForce    = genMiddlewareCmdFlags.Bool("force", false, "Overwrite the existing file")
+	genMiddlewareCmd      = &command{
+		Name:      "middleware",
+		UsageLine: "aah generate middleware <Name> [-force]",
+		Flags:     genMiddlewareCmdFlags,
+		MaxArgs:   1,
+		Hooks:     []hook{validateGopath, requireInsideApp},
+		Short:     "generate middleware skeleton",
+		Long: `
+Generates the middleware 'app/middleware/<name>.go' compatible with aah
+framework middleware signature 'func(ctx *aah.Context, m *aah.Middleware)'
+and prints the registration guidance.
+
+Example:
+    aah generate middleware RequestTimer
+`,
+	}
+)
+
+type genMiddleware struct {
+	Name          string
+	AppImportPath string
+}
+
+func genMiddlewareRun(args []string) {
+	if len(args) == 0 {
+		fatalExit(exitUsage, "Middleware name is required. Run 'aah help generate middleware'.")
+		return
+	}
+
+	mw := &genMiddleware{Name: toExported(args[0]), AppImportPath: appProject.ImportPath}
+	file := filepath.Join(appProject.BaseDir, "app", "middleware", toSnakeCase(mw.Name)+".go")
+	if err := writeGenFile(file, genMiddlewareTemplate, mw, *genMiddlewareForce); err != nil {
+		fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := renderTmpl(buf, genMiddlewareGuideTemplate, mw); err != nil {
+		fatal(err)
+	}
+
+	_ = log.SetPattern("%message")
+	log.Info(buf.String())
+	_ = log.SetPattern(log.DefaultPattern)
+}
+
+func init() {
+	genMiddlewareCmd.Run = genMiddlewareRun
+	generateCmd.Subcommands = append(generateCmd.Subcommands, genMiddlewareCmd)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Generate Templates
+//___________________________________
+
+const genMiddlewareTemplate = `package middleware
+
+import (
+	"aahframework.org/aah.v0"
+)
+
+// {{ .Name }} middleware.
+//
+// Register it via 'aah.Middlewares({{ .Name }})' from application init.
+func {{ .Name }}(ctx *aah.Context, m *aah.Middleware) {
+	// Before: code here executes before the next middleware and the
+	// controller action. Skip 'm.Next(ctx)' to abort the chain, for e.g.
+	// after ctx.Reply().Forbidden().
+
+	m.Next(ctx)
+
+	// After: code here executes after the next middleware and the controller
+	// action returns.
+}
+`
+
+const genMiddlewareGuideTemplate = `
+Register the middleware in your application init, for e.g.: 'app/init.go'
+
+    package app
+
+    import (
+        "aahframework.org/aah.v0"
+        "{{ .AppImportPath }}/app/middleware"
+    )
+
+    func init() {
+        aah.Middlewares(middleware.{{ .Name }})
+    }
+
+Middlewares are executed in the registered order for every request. For
+controller specific logic use interceptors 'Before', 'After', 'Finally',
+'Panic' or 'Before<Action>' methods on the controller instead.
+`
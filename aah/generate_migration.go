@@ -0,0 +1,122 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"aahframework.org/essentials.v0"
+)
+
+var (
+	genMigrationCmdFlags    = flag.NewFlagSet("migration", flag.ContinueOnError)
+	genMigrationDialectFlag = genMigrationCmdFlags.String("dialect", "", "SQL dialect 'postgres', 'mysql' or 'sqlite'. Default is 'migration.dialect' from aah.project")
+	genMigrationCmd         = &command{
+		Name:      "migration",
+		UsageLine: "aah generate migration <name> [-dialect]",
+		Flags:     genMigrationCmdFlags,
+		MaxArgs:   1,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "generate timestamped up/down migration files",
+		Long: `
+Generates the timestamped migration file pair under 'db/migrations'
+    <timestamp>_<name>.up.sql
+    <timestamp>_<name>.down.sql
+
+Migration files have dialect header, dialect is from '-dialect' flag or
+'migration.dialect' in 'aah.project', default is 'postgres'. Migration name
+must be unique within 'db/migrations'.
+
+Example:
+    aah generate migration create_users
+
+    aah generate migration AddEmailToUsers -dialect=mysql
+`,
+	}
+)
+
+type genMigration struct {
+	Version     string
+	Name        string
+	Dialect     string
+	DialectName string
+	Direction   string
+	Created     string
+}
+
+func genMigrationRun(args []string) {
+	if len(args) == 0 {
+		fatalExit(exitUsage, "Migration name is required. Run 'aah help generate migration'.")
+		return
+	}
+
+	name := migrationName(args[0])
+	if ess.IsStrEmpty(name) {
+		fatalExitf(exitUsage, "Invalid migration name '%s'", args[0])
+		return
+	}
+
+	dialect := firstNonEmpty(*genMigrationDialectFlag,
+		appProject.BuildCfg.StringDefault("migration.dialect", ""), "postgres")
+	dialectName, found := migrationDialects[dialect]
+	if !found {
+		fatalExitf(exitUsage, "Unsupported migration dialect '%s', choose either 'postgres', 'mysql' or 'sqlite'", dialect)
+		return
+	}
+
+	dir := filepath.Join(appProject.BaseDir, filepath.FromSlash(migrationsDir))
+	if ess.IsFileExists(dir) {
+		existing, err := loadMigrations(dir)
+		if err != nil {
+			fatalErr(newExitErr(exitConfigError, err))
+			return
+		}
+		for _, m := range existing {
+			if m.Name == name {
+				fatalExitf(exitUsage, "Migration '%s' already exists, version %s", name, m.Version)
+				return
+			}
+		}
+	}
+
+	now := time.Now().UTC()
+	for _, direction := range []string{migrationUp, migrationDown} {
+		mg := &genMigration{
+			Version:     now.Format(migrationVersionTime),
+			Name:        name,
+			Dialect:     dialect,
+			DialectName: dialectName,
+			Direction:   direction,
+			Created:     now.Format(time.RFC3339),
+		}
+
+		file := filepath.Join(dir, fmt.Sprintf("%s_%s.%s.sql", mg.Version, mg.Name, direction))
+		if err := writeGenFile(file, genMigrationTemplate, mg, false); err != nil {
+			fatal(err)
+		}
+	}
+}
+
+func init() {
+	genMigrationCmd.Run = genMigrationRun
+	generateCmd.Subcommands = append(generateCmd.Subcommands, genMigrationCmd)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Generate Templates
+//___________________________________
+
+const genMigrationTemplate = `-- aah migration: {{ .Name }} ({{ .Direction }})
+-- version: {{ .Version }}
+-- dialect: {{ .Dialect }} ({{ .DialectName }})
+-- created: {{ .Created }}
+{{ if eq .Direction "up" }}
+-- Write your {{ .DialectName }} statements to apply the migration.
+{{ else }}
+-- Write your {{ .DialectName }} statements to revert the 'up' migration.
+{{ end }}`
@@ -0,0 +1,210 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+var (
+	genModelCmdFlags     = flag.NewFlagSet("model", flag.ContinueOnError)
+	genModelFieldsFlag   = genModelCmdFlags.String("fields", "", "Comma separated fields 'name:type', e.g: \"name:string,age:int\"")
+	genModelTableFlag    = genModelCmdFlags.String("table", "", "Database table name, adds 'db' struct tags and TableName method")
+	genModelNoCommonFlag = genModelCmdFlags.Bool("nocommon", false, "Do not add common fields ID, CreatedAt and UpdatedAt")
+	genModelTagsFlag     = genModelCmdFlags.String("tags", "json", "Comma separated struct tag keys, e.g: json,xml,db")
+	genModelForceFlag    = genModelCmdFlags.Bool("force", false, "Overwrite the existing file")
+	genModelCmd          = &command{
+		Name:      "model",
+		UsageLine: "aah generate model <Name> [-fields] [-table] [-tags] [-nocommon] [-force]",
+		Flags:     genModelCmdFlags,
+		MaxArgs:   1,
+		Hooks:     []hook{validateGopath, requireInsideApp},
+		Short:     "generate model struct with constructor",
+		Long: `
+Generates the model struct 'app/models/<name>.go' with common fields
+(ID, CreatedAt, UpdatedAt), given fields, struct tags and a constructor.
+
+Field types are Go types, few aliases are supported too:
+    text -> string, integer -> int, bigint -> int64, float -> float64,
+    decimal -> float64, bool/boolean -> bool, time/datetime -> time.Time
+
+Example:
+    aah generate model User -fields="name:string,age:int,email:string"
+
+    aah generate model Order -fields="total:decimal,paid_at:time" -table=orders -tags=json,xml
+`,
+	}
+
+	modelTypeAliases = map[string]string{
+		"text":     "string",
+		"integer":  "int",
+		"bigint":   "int64",
+		"float":    "float64",
+		"decimal":  "float64",
+		"boolean":  "bool",
+		"time":     "time.Time",
+		"datetime": "time.Time",
+	}
+)
+
+type (
+	genModel struct {
+		Name       string
+		Table      string
+		Fields     []*genModelField
+		NeedsTime  bool
+		HasCommon  bool
+		ParamsDecl string
+	}
+
+	genModelField struct {
+		Name   string
+		Param  string
+		Type   string
+		Tag    string
+		Common bool
+	}
+)
+
+func genModelRun(args []string) {
+	if len(args) == 0 {
+		fatalExit(exitUsage, "Model name is required. Run 'aah help generate model'.")
+		return
+	}
+
+	model, err := newGenModel(toExported(args[0]), *genModelFieldsFlag, *genModelTableFlag,
+		splitList(*genModelTagsFlag), !*genModelNoCommonFlag)
+	if err != nil {
+		fatalExit(exitUsage, err)
+		return
+	}
+
+	file := filepath.Join(appProject.BaseDir, "app", "models", toSnakeCase(model.Name)+".go")
+	if err = writeGenFile(file, genModelTemplate, model, *genModelForceFlag); err != nil {
+		fatal(err)
+	}
+}
+
+func newGenModel(name, fields, table string, tags []string, common bool) (*genModel, error) {
+	model := &genModel{Name: name, Table: table, HasCommon: common}
+	if len(table) > 0 && !isStringInSlice(tags, "db") {
+		tags = append(tags, "db")
+	}
+
+	if common {
+		model.Fields = append(model.Fields,
+			&genModelField{Name: "ID", Type: "int64", Tag: modelFieldTag(tags, "id"), Common: true},
+			&genModelField{Name: "CreatedAt", Type: "time.Time", Tag: modelFieldTag(tags, "created_at"), Common: true},
+			&genModelField{Name: "UpdatedAt", Type: "time.Time", Tag: modelFieldTag(tags, "updated_at"), Common: true},
+		)
+		model.NeedsTime = true
+	}
+
+	var params []string
+	for _, f := range splitList(fields) {
+		parts := strings.SplitN(f, ":", 2)
+		if len(parts) != 2 || len(strings.TrimSpace(parts[0])) == 0 || len(strings.TrimSpace(parts[1])) == 0 {
+			return nil, fmt.Errorf("invalid field '%s', expected format is 'name:type'", f)
+		}
+
+		fieldName, fieldType := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if alias, found := modelTypeAliases[strings.ToLower(fieldType)]; found {
+			fieldType = alias
+		}
+		if strings.Contains(fieldType, "time.") {
+			model.NeedsTime = true
+		}
+
+		snake := toSnakeCase(fieldName)
+		field := &genModelField{
+			Name:  toExported(snake),
+			Param: lowerFirst(toExported(snake)),
+			Type:  fieldType,
+			Tag:   modelFieldTag(tags, snake),
+		}
+		model.Fields = append(model.Fields, field)
+		params = append(params, field.Param+" "+field.Type)
+	}
+	model.ParamsDecl = strings.Join(params, ", ")
+
+	return model, nil
+}
+
+func modelFieldTag(tags []string, name string) string {
+	var values []string
+	for _, t := range tags {
+		values = append(values, fmt.Sprintf(`%s:"%s"`, t, name))
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	return "`" + strings.Join(values, " ") + "`"
+}
+
+func lowerFirst(s string) string {
+	if len(s) == 0 {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	if v := string(r); !isGoKeyword(v) {
+		return v
+	}
+	return string(r) + "Value"
+}
+
+func isGoKeyword(s string) bool {
+	switch s {
+	case "break", "case", "chan", "const", "continue", "default", "defer", "else",
+		"fallthrough", "for", "func", "go", "goto", "if", "import", "interface",
+		"map", "package", "range", "return", "select", "struct", "switch", "type", "var":
+		return true
+	}
+	return false
+}
+
+func init() {
+	genModelCmd.Run = genModelRun
+	generateCmd.Subcommands = append(generateCmd.Subcommands, genModelCmd)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Generate Templates
+//___________________________________
+
+const genModelTemplate = `package models
+{{ if .NeedsTime }}
+import (
+	"time"
+)
+{{ end }}
+// {{ .Name }} model.
+type {{ .Name }} struct {
+{{- range .Fields }}
+	{{ .Name }} {{ .Type }} {{ .Tag }}
+{{- end }}
+}
+
+// New{{ .Name }} method creates a new {{ .Name }} model.
+func New{{ .Name }}({{ .ParamsDecl }}) *{{ .Name }} {
+	return &{{ .Name }}{
+{{- range .Fields }}{{ if not .Common }}
+		{{ .Name }}: {{ .Param }},
+{{- end }}{{ end }}{{ if .HasCommon }}
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+{{- end }}
+	}
+}
+{{ if .Table }}
+// TableName method returns the database table name of {{ .Name }}.
+func ({{ .Name }}) TableName() string {
+	return "{{ .Table }}"
+}
+{{ end }}`
@@ -0,0 +1,285 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"aahframework.org/log.v0"
+)
+
+var (
+	genModelsCmdFlags         = flag.NewFlagSet("models", flag.ContinueOnError)
+	genModelsFromDBFlag       = genModelsCmdFlags.Bool("from-db", false, "Introspect the tables of the configured database")
+	genModelsProfileFlag      = genModelsCmdFlags.String("profile", "", "Environment profile of the database config. Default is 'env.active' of app config")
+	genModelsProfileShortFlag = genModelsCmdFlags.String("p", "", "Environment profile of the database config. Default is 'env.active' of app config")
+	genModelsTablesFlag       = genModelsCmdFlags.String("tables", "", "Comma separated table names, e.g: users,orders. Default is all")
+	genModelsTagsFlag         = genModelsCmdFlags.String("tags", "json", "Comma separated struct tag keys, 'db' is always added")
+	genModelsControllersFlag  = genModelsCmdFlags.Bool("controllers", false, "Generate CRUD controller stubs of the models")
+	genModelsForceFlag        = genModelsCmdFlags.Bool("force", false, "Overwrite the existing files")
+	genModelsCmd              = &command{
+		Name:      "models",
+		UsageLine: "aah generate models -from-db [-p | -profile] [-tables] [-tags] [-controllers] [-force]",
+		Flags:     genModelsCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "generate model structs from database tables",
+		Long: `
+Generates the model struct 'app/models/<name>.go' for each table of the
+configured database, refer 'aah help migrate' for the database config. Model
+name is the singular of the table name, e.g. 'order_items' becomes
+'OrderItem'. Migrations table is skipped.
+
+Column types are mapped to Go types, nullable columns become pointers:
+    int/integer -> int, bigint -> int64, tinyint(1)/bool -> bool,
+    float/real/double/numeric/decimal -> float64, date/time/timestamp -> time.Time,
+    blob/bytea/binary -> []byte, others -> string
+
+With '-controllers' flag CRUD controller stubs 'app/controllers/<name>.go'
+are generated with test files, add the logged routes into 'config/routes.conf'.
+
+Example:
+    aah generate models -from-db
+
+    aah generate models -from-db -tables=users,orders -tags=json,xml -controllers
+`,
+	}
+
+	sqlIntRegex = regexp.MustCompile(`^(tiny|small|medium)?int(eger)?\b|^(small)?serial\b`)
+)
+
+type (
+	// dbTable is the database table read by the migration runner 'schema'
+	// action.
+	dbTable struct {
+		Name    string
+		Columns []*dbColumn
+	}
+
+	dbColumn struct {
+		Name       string
+		Type       string
+		Nullable   bool
+		PrimaryKey bool
+	}
+)
+
+func genModelsRun(args []string) {
+	if !*genModelsFromDBFlag {
+		fatalExit(exitUsage, "Source of the models is required, use '-from-db'. Run 'aah help generate models'.")
+		return
+	}
+
+	profile := activeProfile(firstNonEmpty(*genModelsProfileFlag, *genModelsProfileShortFlag))
+	data := &migrationRunnerData{}
+	r := openMigrationRunner(profile, data)
+	out, err := r.Schema()
+	r.Close()
+	if err != nil {
+		fatalErr(newExitErr(exitMigrationError, err))
+		return
+	}
+
+	tables, err := selectDBTables(parseDBSchema(out, data.DB.Table), splitList(*genModelsTablesFlag))
+	if err != nil {
+		fatalErr(newExitErr(exitUsage, err))
+		return
+	}
+	if len(tables) == 0 {
+		log.Infof("No tables found in the database of profile '%s'", profile)
+		return
+	}
+
+	force := *genModelsForceFlag
+	tags := splitList(*genModelsTagsFlag)
+	routes := &bytes.Buffer{}
+	for _, t := range tables {
+		model := newGenModelFromTable(t, tags)
+		file := filepath.Join(appProject.BaseDir, "app", "models", toSnakeCase(model.Name)+".go")
+		if err = writeGenFile(file, genModelTemplate, model, force); err != nil {
+			fatal(err)
+		}
+
+		if !*genModelsControllersFlag {
+			continue
+		}
+		ctrl := newGenController("", model.Name, []string{"Index", "Show", "Create", "Update", "Delete"})
+		ctrlFile := filepath.Join(appProject.BaseDir, "app", "controllers", toSnakeCase(ctrl.Name))
+		if err = writeGenFile(ctrlFile+".go", genControllerTemplate, ctrl, force); err != nil {
+			fatal(err)
+		}
+		if err = writeGenFile(ctrlFile+"_test.go", genControllerTestTemplate, ctrl, force); err != nil {
+			fatal(err)
+		}
+		if err = renderTmpl(routes, genRoutesTemplate, ctrl); err != nil {
+			fatal(err)
+		}
+	}
+
+	if routes.Len() > 0 {
+		log.Infof("Add below routes into 'routes' section of 'config/routes.conf'\n%s", routes.String())
+	}
+}
+
+// parseDBSchema method parses the runner 'schema' output into tables in
+// the output order, migrations table is skipped. Each line is tab separated
+// table, column, type, nullable (YES or NO) and primary key (1 or 0).
+func parseDBSchema(out []byte, migrationTable string) []*dbTable {
+	var tables []*dbTable
+	byName := map[string]*dbTable{}
+	migrationTable = migrationTable[strings.LastIndex(migrationTable, ".")+1:]
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		parts := strings.Split(scanner.Text(), "\t")
+		if len(parts) != 5 || parts[0] == migrationTable {
+			continue
+		}
+
+		t, found := byName[parts[0]]
+		if !found {
+			t = &dbTable{Name: parts[0]}
+			byName[t.Name] = t
+			tables = append(tables, t)
+		}
+		t.Columns = append(t.Columns, &dbColumn{
+			Name:       parts[1],
+			Type:       strings.ToLower(strings.TrimSpace(parts[2])),
+			Nullable:   strings.EqualFold(parts[3], "YES"),
+			PrimaryKey: parts[4] == "1",
+		})
+	}
+	return tables
+}
+
+// selectDBTables method returns the tables of given names in tables order,
+// all tables if names are empty.
+func selectDBTables(tables []*dbTable, names []string) ([]*dbTable, error) {
+	if len(names) == 0 {
+		return tables, nil
+	}
+
+	var result []*dbTable
+	for _, n := range names {
+		found := false
+		for _, t := range tables {
+			if t.Name == n {
+				result = append(result, t)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("table '%s' not found in the database", n)
+		}
+	}
+	return result, nil
+}
+
+// newGenModelFromTable method creates the model of the table, primary key
+// and 'created_at', 'updated_at' columns are common fields.
+func newGenModelFromTable(t *dbTable, tags []string) *genModel {
+	if !isStringInSlice(tags, "db") {
+		tags = append(tags, "db")
+	}
+
+	model := &genModel{Name: singularize(toExported(toSnakeCase(t.Name))), Table: t.Name}
+	var params []string
+	timestamps := 0
+	for _, c := range t.Columns {
+		field := &genModelField{
+			Name:  toGoFieldName(c.Name),
+			Param: lowerFirst(toExported(toSnakeCase(c.Name))),
+			Type:  sqlGoType(c.Type, c.Nullable),
+			Tag:   modelFieldTag(tags, c.Name),
+		}
+		switch c.Name {
+		case "created_at", "updated_at":
+			field.Common = true
+			if field.Type == "time.Time" {
+				timestamps++
+			}
+		default:
+			field.Common = c.PrimaryKey
+		}
+		if strings.Contains(field.Type, "time.") {
+			model.NeedsTime = true
+		}
+		if !field.Common {
+			params = append(params, field.Param+" "+field.Type)
+		}
+		model.Fields = append(model.Fields, field)
+	}
+	model.HasCommon = timestamps == 2
+	model.ParamsDecl = strings.Join(params, ", ")
+	return model
+}
+
+// sqlGoType method returns the Go type of the SQL column type, nullable
+// column is a pointer type except '[]byte'.
+func sqlGoType(sqlType string, nullable bool) string {
+	var goType string
+	switch t := strings.ToLower(sqlType); {
+	case strings.HasPrefix(t, "tinyint(1)"), strings.HasPrefix(t, "bool"):
+		goType = "bool"
+	case strings.HasPrefix(t, "bigint"), strings.HasPrefix(t, "bigserial"), strings.HasPrefix(t, "int8"):
+		goType = "int64"
+	case sqlIntRegex.MatchString(t), strings.HasPrefix(t, "int2"), strings.HasPrefix(t, "int4"):
+		goType = "int"
+	case strings.HasPrefix(t, "float"), strings.HasPrefix(t, "real"), strings.HasPrefix(t, "double"),
+		strings.HasPrefix(t, "numeric"), strings.HasPrefix(t, "decimal"):
+		goType = "float64"
+	case strings.HasPrefix(t, "date"), strings.HasPrefix(t, "time"):
+		goType = "time.Time"
+	case strings.Contains(t, "blob"), strings.HasPrefix(t, "bytea"), strings.Contains(t, "binary"):
+		return "[]byte"
+	default:
+		goType = "string"
+	}
+
+	if nullable {
+		return "*" + goType
+	}
+	return goType
+}
+
+// toGoFieldName method returns the exported field name of the column with
+// Go initialisms, for e.g.: 'user_id' becomes 'UserID'.
+func toGoFieldName(column string) string {
+	parts := strings.Split(toSnakeCase(column), "_")
+	for i, p := range parts {
+		switch p {
+		case "id", "url", "uri", "api", "ip", "uuid", "json", "xml", "html", "http", "sql":
+			parts[i] = strings.ToUpper(p)
+		}
+	}
+	return toExported(strings.Join(parts, "_"))
+}
+
+// singularize method returns the singular of the English plural name, for
+// e.g.: 'Categories' becomes 'Category', 'Addresses' becomes 'Address'.
+func singularize(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "ies") && len(name) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"),
+		strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "shes"):
+		return name[:len(name)-2]
+	case strings.HasSuffix(lower, "s") && !strings.HasSuffix(lower, "ss") &&
+		!strings.HasSuffix(lower, "us") && len(name) > 1:
+		return name[:len(name)-1]
+	}
+	return name
+}
+
+func init() {
+	genModelsCmd.Run = genModelsRun
+	generateCmd.Subcommands = append(generateCmd.Subcommands, genModelsCmd)
+}
@@ -0,0 +1,154 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"path/filepath"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+var (
+	genOpenAPICmdFlags   = flag.NewFlagSet("openapi", flag.ContinueOnError)
+	genOpenAPIOutputFlag = genOpenAPICmdFlags.String("o", "", "Output file path. Default is 'static/openapi.json'")
+	genOpenAPIDomainFlag = genOpenAPICmdFlags.String("domain", "", "Document the routes of given domain only")
+	genOpenAPIServerFlag = genOpenAPICmdFlags.String("server", "", "Comma separated server URLs of the document, e.g: https://api.example.com")
+	genOpenAPICmd        = &command{
+		Name:      "openapi",
+		UsageLine: "aah generate openapi [-o] [-domain] [-server]",
+		Flags:     genOpenAPICmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "generate OpenAPI 3 document from routes and controllers",
+		Long: `
+Generates the OpenAPI 3 document (JSON) of the application routes, it's
+written to 'static/openapi.json' by default. Document is made of:
+
+    - paths and operations from 'config/routes.conf', path parameters
+      ':id' and '*path' become '{id}' and '{path}'
+    - operation ID is the route name, tag is the controller name
+    - path parameter types from the controller action parameters, other
+      action parameters of basic type are query parameters, struct type
+      parameter is the JSON request body of POST, PUT and PATCH
+    - summary and description from the action doc comment
+    - request and response schemas from the struct types of 'app'
+      packages, property name is taken from 'json' tag and field with
+      'validate:"required"' tag is required
+    - security from route 'auth' and 'config/security.conf' auth schemes
+
+Action doc comment annotations:
+
+    // Show returns the user of given ID.
+    //
+    // @summary Get user
+    // @tags users
+    // @body models.UserInput
+    // @response 200 models.User the user
+    // @response 404 user not found
+    // @deprecated
+
+Action with '@ignore' annotation is not documented. Response type of other
+package is qualified, e.g. 'models.User' or '[]models.User'.
+
+Example(s):
+    aah generate openapi
+
+    aah generate openapi -o=docs/openapi.json -server=https://api.example.com
+`,
+	}
+)
+
+func genOpenAPIRun(args []string) {
+	doc, err := appOpenAPIDoc(appProject.BaseDir, *genOpenAPIDomainFlag, splitList(*genOpenAPIServerFlag))
+	if err != nil {
+		fatal(err)
+		return
+	}
+
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		fatal(err)
+		return
+	}
+
+	file := firstNonEmpty(getNonEmptyAbsPath(*genOpenAPIOutputFlag, ""), filepath.Join(appProject.BaseDir, "static", "openapi.json"))
+	if err = mkDirAll(filepath.Dir(file), permRWXRXRX); err != nil {
+		fatal(err)
+		return
+	}
+	if err = writeFile(file, append(b, '\n'), permRWRR); err != nil {
+		fatal(err)
+		return
+	}
+	recordArtifact(file)
+	log.Infof("Generated %s, %d path(s)", file, len(doc.Paths))
+}
+
+// appOpenAPIDoc method creates the OpenAPI document of the application
+// routes, all domains are documented if domain is empty.
+func appOpenAPIDoc(baseDir, domain string, servers []string) (*openAPIDoc, error) {
+	routes, err := loadAppRoutes(baseDir)
+	if err != nil {
+		return nil, newExitErr(exitRouteError, err)
+	}
+	if !ess.IsStrEmpty(domain) {
+		filtered := routes[:0]
+		for _, r := range routes {
+			if r.Domain == domain || r.Host == domain {
+				filtered = append(filtered, r)
+			}
+		}
+		if routes = filtered; len(routes) == 0 {
+			return nil, newExitErr(exitRouteError, fmt.Errorf("no routes found for domain '%s'", domain))
+		}
+	}
+
+	appDir := filepath.Join(baseDir, "app")
+	controllers, err := loadAppControllers(filepath.Join(appDir, "controllers"))
+	if err != nil {
+		return nil, newExitErr(exitParseError, err)
+	}
+	types, err := loadAppStructTypes(appDir)
+	if err != nil {
+		return nil, newExitErr(exitParseError, err)
+	}
+
+	info := &openAPIInfo{
+		Title:       aah.AppName(),
+		Description: aah.AppConfig().StringDefault("desc", ""),
+		Version:     firstNonEmpty(getAppVersion(baseDir, appProject.BuildCfg), "0.0.0"),
+	}
+	doc := newOpenAPIDoc(info, routes, controllers, types, loadAuthSchemeKinds(baseDir))
+	for _, s := range servers {
+		doc.Servers = append(doc.Servers, &openAPIServer{URL: s})
+	}
+	return doc, nil
+}
+
+// loadAuthSchemeKinds method returns the auth scheme kind of the auth
+// scheme names of 'config/security.conf', e.g. 'form_auth' is 'form'.
+func loadAuthSchemeKinds(baseDir string) map[string]string {
+	kinds := map[string]string{}
+	cfg, err := config.LoadFile(filepath.Join(baseDir, "config", "security.conf"))
+	if err != nil {
+		return kinds
+	}
+	if schemesCfg, found := cfg.GetSubConfig("security.auth_schemes"); found {
+		for _, name := range schemesCfg.Keys() {
+			kinds[name] = schemesCfg.StringDefault(name+".scheme", "")
+		}
+	}
+	return kinds
+}
+
+func init() {
+	genOpenAPICmd.Run = genOpenAPIRun
+	generateCmd.Subcommands = append(generateCmd.Subcommands, genOpenAPICmd)
+}
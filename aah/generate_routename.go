@@ -0,0 +1,227 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"aahframework.org/essentials.v0"
+)
+
+var (
+	genRouteNameCmdFlags  = flag.NewFlagSet("routename", flag.ContinueOnError)
+	genRouteNameForceFlag = genRouteNameCmdFlags.Bool("force", false, "Overwrite the existing generated file")
+	genRouteNameCmd       = &command{
+		Name:      "routename",
+		UsageLine: "aah generate routename [-force]",
+		Flags:     genRouteNameCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "generate route name constants from routes.conf",
+		Long: `
+Generates the package 'app/routename' from the named routes of
+'config/routes.conf', so controllers reference the routes via constants
+instead of strings and renamed or removed route fails at compile time:
+
+    c.Reply().Redirect(c.RouteURL(routename.Index))
+
+    c.RouteURLNamedArgs(routename.UserShowArgs(id))
+
+Constant name is the exported route name, e.g. 'user_show' becomes
+'UserShow'. Route with path parameters has function '<Name>Args' which
+takes the parameters as typed arguments, type is taken from the controller
+action parameter of same name, otherwise 'string'. Route name which exists
+in more than one domain is prefixed with the domain name.
+
+Run 'aah generate routename -force' after route changes.
+
+Example:
+    aah generate routename
+`,
+	}
+)
+
+type (
+	genRouteName struct {
+		Routes []*genRouteNameRoute
+	}
+
+	genRouteNameRoute struct {
+		Const  string
+		Name   string
+		Domain string
+		Path   string
+		Params []*genRouteParam
+	}
+
+	genRouteParam struct {
+		Name  string
+		Ident string
+		Type  string
+	}
+)
+
+func genRouteNameRun(args []string) {
+	routes, err := loadAppRoutes(appProject.BaseDir)
+	if err != nil {
+		fatalExit(exitRouteError, err)
+		return
+	}
+
+	controllers, err := loadAppControllers(filepath.Join(appProject.BaseDir, "app", "controllers"))
+	if err != nil {
+		fatalExit(exitParseError, err)
+		return
+	}
+
+	gen := newGenRouteName(routes, controllers)
+	if len(gen.Routes) == 0 {
+		fatalExit(exitRouteError, errors.New("no named routes found in 'config/routes.conf'"))
+		return
+	}
+
+	file := filepath.Join(appProject.BaseDir, "app", "routename", "routename.go")
+	if err = writeGenFile(file, genRouteNameTemplate, gen, *genRouteNameForceFlag); err != nil {
+		fatal(err)
+	}
+}
+
+// newGenRouteName method creates the route name data of the routes, route
+// with multiple methods is generated once.
+func newGenRouteName(routes []*appRoute, controllers map[string]*appController) *genRouteName {
+	domains := map[string]map[string]bool{}
+	for _, r := range routes {
+		if domains[r.Name] == nil {
+			domains[r.Name] = map[string]bool{}
+		}
+		domains[r.Name][r.Domain] = true
+	}
+
+	gen := &genRouteName{}
+	seen := map[string]bool{}
+	for _, r := range routes {
+		if ess.IsStrEmpty(r.Name) || seen[r.Domain+"."+r.Name] {
+			continue
+		}
+		seen[r.Domain+"."+r.Name] = true
+
+		gr := &genRouteNameRoute{Const: toGoIdent(r.Name, true), Name: r.Name, Domain: r.Domain, Path: r.Path}
+		if len(domains[r.Name]) > 1 {
+			gr.Const = toGoIdent(r.Domain, true) + gr.Const
+		}
+		gr.Params = routePathParams(r, controllers)
+		gen.Routes = append(gen.Routes, gr)
+	}
+	return gen
+}
+
+// routePathParams method returns the path parameters of the route, type is
+// taken from the controller action parameter of same name if it's a Go
+// basic type.
+func routePathParams(r *appRoute, controllers map[string]*appController) []*genRouteParam {
+	var action *appAction
+	if c := lookupController(controllers, r.Controller); c != nil {
+		action = c.Action(r.Action)
+	}
+
+	var params []*genRouteParam
+	for _, seg := range strings.Split(r.Path, "/") {
+		if !strings.HasPrefix(seg, ":") && !strings.HasPrefix(seg, "*") {
+			continue
+		}
+
+		p := &genRouteParam{Name: seg[1:], Ident: toGoIdent(seg[1:], false), Type: "string"}
+		if action != nil {
+			for _, ap := range action.Params {
+				if ap.Name == p.Name && isGoBasicType(ap.Type) {
+					p.Type = ap.Type
+				}
+			}
+		}
+		params = append(params, p)
+	}
+	return params
+}
+
+// ParamList method returns the parameters as Go function parameter list.
+func (r *genRouteNameRoute) ParamList() string {
+	list := make([]string, 0, len(r.Params))
+	for _, p := range r.Params {
+		list = append(list, p.Ident+" "+p.Type)
+	}
+	return strings.Join(list, ", ")
+}
+
+// toGoIdent method returns the Go identifier of given name, it's prefixed
+// with '_' if it doesn't start with letter and suffixed with '_' if it's
+// Go keyword.
+func toGoIdent(name string, exported bool) string {
+	ident := toExported(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, name))
+	if ess.IsStrEmpty(ident) {
+		ident = "X"
+	}
+	if !exported {
+		r := []rune(ident)
+		r[0] = unicode.ToLower(r[0])
+		ident = string(r)
+	}
+	if !unicode.IsLetter([]rune(ident)[0]) {
+		ident = "_" + ident
+	}
+	if token.Lookup(ident).IsKeyword() {
+		ident += "_"
+	}
+	return ident
+}
+
+func isGoBasicType(t string) bool {
+	switch t {
+	case "string", "bool", "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+		return true
+	}
+	return false
+}
+
+func init() {
+	genRouteNameCmd.Run = genRouteNameRun
+	generateCmd.Subcommands = append(generateCmd.Subcommands, genRouteNameCmd)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Generator templates
+//___________________________________
+
+const genRouteNameTemplate = `// Code generated by 'aah generate routename' from 'config/routes.conf'. DO NOT EDIT.
+
+// Package routename provides the route names of 'config/routes.conf' for
+// reverse URL.
+package routename
+
+// Route names of 'config/routes.conf'.
+const ( {{- range .Routes }}
+	// {{ .Const }} is route '{{ .Name }}' path '{{ .Path }}' of domain '{{ .Domain }}'.
+	{{ .Const }} = "{{ .Name }}"
+{{ end -}}
+)
+{{ range .Routes }}{{ if .Params }}
+// {{ .Const }}Args returns the route name and path parameters of route
+// '{{ .Name }}' for 'RouteURLNamedArgs'.
+func {{ .Const }}Args({{ .ParamList }}) (string, map[string]interface{}) {
+	return {{ .Const }}, map[string]interface{}{ {{- range .Params }}
+		"{{ .Name }}": {{ .Ident }},{{ end }}
+	}
+}
+{{ end }}{{ end -}}
+`
@@ -0,0 +1,177 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"path/filepath"
+	"strings"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+)
+
+var (
+	genServiceCmdFlags         = flag.NewFlagSet("service", flag.ContinueOnError)
+	genServicePlatformFlag     = genServiceCmdFlags.String("platform", "", "Service platform 'windows' or 'macos'")
+	genServiceDirFlag          = genServiceCmdFlags.String("dir", "", "Application install directory on the host")
+	genServiceProfileFlag      = genServiceCmdFlags.String("profile", "", "Environment profile name. Default is 'prod'")
+	genServiceProfileShortFlag = genServiceCmdFlags.String("p", "", "Environment profile name. Default is 'prod'")
+	genServiceOutputFlag       = genServiceCmdFlags.String("output", "", "Output directory. Default is <app-base>/service")
+	genServiceOutputShortFlag  = genServiceCmdFlags.String("o", "", "Output directory. Default is <app-base>/service")
+	genServiceForceFlag        = genServiceCmdFlags.Bool("force", false, "Overwrite the existing file")
+	genServiceCmd              = &command{
+		Name:      "service",
+		UsageLine: "aah generate service -platform <windows|macos> [-dir] [-p | -profile] [-o | -output] [-force]",
+		Flags:     genServiceCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "generate Windows service wrapper or macOS launchd plist",
+		Long: `
+Generates the service definition to run the packaged application (refer
+'aah package') as a managed service on non-Linux hosts.
+
+Platform 'windows' generates '<binary>-service.xml' for WinSW service
+wrapper (https://github.com/kohsuke/winsw). Application is started with
+the profile and it's restarted on failure. Install:
+
+    1. Download WinSW executable into the '-dir' directory
+    2. Rename it to '<binary>-service.exe', next to the generated XML
+    3. Run '<binary>-service.exe install' and then 'sc.exe start <binary>'
+
+Platform 'macos' generates '<label>.plist' for launchd. Label is derived
+from application import path, for e.g.: 'github.com/user/myapp' becomes
+'com.github.user.myapp'. Install:
+
+    sudo cp <label>.plist /Library/LaunchDaemons/
+    sudo launchctl load -w /Library/LaunchDaemons/<label>.plist
+
+Default install directory is 'C:\<binary>' on Windows and
+'/usr/local/<binary>' on macOS.
+
+Example:
+    aah generate service -platform=windows
+
+    aah generate service -platform=macos -dir=/opt/myapp -p=qa
+`,
+	}
+)
+
+type genService struct {
+	Name    string
+	Label   string
+	Binary  string
+	Dir     string
+	Profile string
+}
+
+func genServiceRun(args []string) {
+	platform := strings.ToLower(*genServicePlatformFlag)
+	if platform != "windows" && platform != "macos" {
+		fatalExitf(exitUsage, "Unsupported service platform '%s', choose either 'windows' or 'macos'", platform)
+		return
+	}
+
+	svc := newGenService(appProject.BuildCfg, platform)
+	if !ess.IsStrEmpty(*genServiceDirFlag) {
+		svc.Dir = *genServiceDirFlag
+	}
+	svc.Profile = firstNonEmpty(*genServiceProfileFlag, *genServiceProfileShortFlag, "prod")
+
+	outDir := firstNonEmpty(getNonEmptyAbsPath(*genServiceOutputFlag, *genServiceOutputShortFlag),
+		filepath.Join(appProject.BaseDir, "service"))
+	file, tmpl := filepath.Join(outDir, svc.Label+".plist"), genLaunchdTemplate
+	if platform == "windows" {
+		file, tmpl = filepath.Join(outDir, svc.Binary+"-service.xml"), genWinSWTemplate
+	}
+
+	if err := writeGenFile(file, tmpl, svc, *genServiceForceFlag); err != nil {
+		fatal(err)
+	}
+}
+
+// newGenService method creates the service data for given platform, binary
+// name is 'build.binary_name' same as 'aah build' and 'aah package'.
+func newGenService(buildCfg *config.Config, platform string) *genService {
+	binary := buildCfg.StringDefault("build.binary_name", strings.Replace(aah.AppName(), " ", "_", -1))
+	svc := &genService{
+		Name:   aah.AppName(),
+		Label:  launchdLabel(aah.AppImportPath()),
+		Binary: binary,
+		Dir:    "/usr/local/" + binary,
+	}
+	if platform == "windows" {
+		svc.Dir = `C:\` + binary
+	}
+	return svc
+}
+
+// launchdLabel method returns the reverse domain label from import path,
+// for e.g.: 'github.com/user/myapp' becomes 'com.github.user.myapp'.
+func launchdLabel(importPath string) string {
+	parts := strings.Split(strings.ToLower(importPath), "/")
+	domain := strings.Split(parts[0], ".")
+	for i, j := 0, len(domain)-1; i < j; i, j = i+1, j-1 {
+		domain[i], domain[j] = domain[j], domain[i]
+	}
+	return strings.Join(append(domain, parts[1:]...), ".")
+}
+
+func init() {
+	genServiceCmd.Run = genServiceRun
+	generateCmd.Subcommands = append(generateCmd.Subcommands, genServiceCmd)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Generator templates
+//___________________________________
+
+const genWinSWTemplate = `<!-- Generated by 'aah generate service' for WinSW service wrapper. -->
+<service>
+  <id>{{ .Binary }}</id>
+  <name>{{ .Name }}</name>
+  <description>aah application '{{ .Name }}'</description>
+  <executable>{{ .Dir }}\bin\{{ .Binary }}.exe</executable>
+  <arguments>-profile={{ .Profile }}</arguments>
+  <workingdirectory>{{ .Dir }}</workingdirectory>
+  <startmode>Automatic</startmode>
+  <stoptimeout>15 sec</stoptimeout>
+  <onfailure action="restart" delay="10 sec"/>
+  <logpath>{{ .Dir }}\logs</logpath>
+  <log mode="roll-by-size">
+    <sizeThreshold>10240</sizeThreshold>
+    <keepFiles>8</keepFiles>
+  </log>
+</service>
+`
+
+const genLaunchdTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<!-- Generated by 'aah generate service' for launchd. -->
+<plist version="1.0">
+<dict>
+  <key>Label</key>
+  <string>{{ .Label }}</string>
+  <key>ProgramArguments</key>
+  <array>
+    <string>{{ .Dir }}/bin/{{ .Binary }}</string>
+    <string>-profile={{ .Profile }}</string>
+  </array>
+  <key>WorkingDirectory</key>
+  <string>{{ .Dir }}</string>
+  <key>RunAtLoad</key>
+  <true/>
+  <key>KeepAlive</key>
+  <dict>
+    <key>SuccessfulExit</key>
+    <false/>
+  </dict>
+  <key>StandardOutPath</key>
+  <string>{{ .Dir }}/logs/{{ .Binary }}.out.log</string>
+  <key>StandardErrorPath</key>
+  <string>{{ .Dir }}/logs/{{ .Binary }}.err.log</string>
+</dict>
+</plist>
+`
@@ -0,0 +1,229 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"path/filepath"
+	"strings"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/essentials.v0"
+)
+
+var (
+	genSmokeCmdFlags  = flag.NewFlagSet("smoketest", flag.ContinueOnError)
+	genSmokeForceFlag = genSmokeCmdFlags.Bool("force", false, "Overwrite the existing test file")
+	genSmokeCmd       = &command{
+		Name:      "smoketest",
+		UsageLine: "aah generate smoketest [-force]",
+		Flags:     genSmokeCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "generate route smoke test from routes.conf",
+		Long: `
+Generates the smoke test 'app/smoke/routes_smoke_test.go' from the route
+table of 'config/routes.conf'. Test starts the application binary (refer
+'aah build') on a free port and issues a request against every GET route,
+it asserts the responses are not 5xx. Path parameters are filled with
+sample values. Run 'aah generate smoketest -force' after route changes.
+
+Application runs with profile 'AAH_APP_PROFILE' (set by 'aah test') if its
+'config/env/<profile>.conf' exists, otherwise 'dev'. Set 'AAH_SMOKE_BINARY'
+to use different binary or 'AAH_SMOKE_URL' to test already running
+application.
+
+Example:
+    aah generate smoketest
+
+    aah build && aah test ./app/smoke
+`,
+	}
+)
+
+type (
+	genSmoke struct {
+		Binary string
+		Routes []*smokeRoute
+	}
+
+	smokeRoute struct {
+		Name string
+		Host string
+		Path string
+	}
+)
+
+func genSmokeRun(args []string) {
+	routes, err := loadAppRoutes(appProject.BaseDir)
+	if err != nil {
+		fatalExit(exitRouteError, err)
+		return
+	}
+
+	smoke := newGenSmoke(strings.Replace(aah.AppName(), " ", "_", -1), routes)
+	if len(smoke.Routes) == 0 {
+		fatalExit(exitRouteError, errors.New("no GET routes found in 'config/routes.conf'"))
+		return
+	}
+	smoke.Binary = filepath.Base(appBinaryFile(appProject.BuildCfg, ""))
+
+	file := filepath.Join(appProject.BaseDir, "app", "smoke", "routes_smoke_test.go")
+	if err = writeGenFile(file, genSmokeTestTemplate, smoke, *genSmokeForceFlag); err != nil {
+		fatal(err)
+	}
+}
+
+// newGenSmoke method creates smoke test data of GET routes, duplicate
+// request targets are tested once.
+func newGenSmoke(binary string, routes []*appRoute) *genSmoke {
+	smoke := &genSmoke{Binary: binary}
+	seen := map[string]bool{}
+	for _, r := range routes {
+		if r.Method != "GET" || ess.IsStrEmpty(r.Path) {
+			continue
+		}
+
+		sr := &smokeRoute{Name: r.Domain + "/" + r.Name, Host: r.Host, Path: routeSamplePath(r.Path)}
+		if key := sr.Host + sr.Path; !seen[key] {
+			seen[key] = true
+			smoke.Routes = append(smoke.Routes, sr)
+		}
+	}
+	return smoke
+}
+
+func init() {
+	genSmokeCmd.Run = genSmokeRun
+	generateCmd.Subcommands = append(generateCmd.Subcommands, genSmokeCmd)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Generator templates
+//___________________________________
+
+const genSmokeTestTemplate = `// Code generated by 'aah generate smoketest' from 'config/routes.conf'.
+
+package smoke
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smokeRoutes are the GET routes of 'config/routes.conf', path parameters
+// are filled with sample values.
+var smokeRoutes = []struct {
+	name string
+	host string
+	path string
+}{ {{- range .Routes }}
+	{"{{ .Name }}", "{{ .Host }}", "{{ .Path }}"},{{ end }}
+}
+
+func TestRoutesSmoke(t *testing.T) {
+	baseURL, stop := startApp(t)
+	defer stop()
+
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	for _, r := range smokeRoutes {
+		r := r
+		t.Run(r.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, baseURL+r.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if port := req.URL.Port(); port != "" {
+				req.Host = r.host + ":" + port
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("GET %s: %s", r.path, err)
+			}
+			_ = resp.Body.Close()
+
+			if resp.StatusCode >= http.StatusInternalServerError {
+				t.Errorf("GET %s: %s", r.path, resp.Status)
+			}
+		})
+	}
+}
+
+// startApp starts the application binary on free port and waits until it
+// accepts the connections, it returns the base URL and stop func.
+func startApp(t *testing.T) (string, func()) {
+	if u := os.Getenv("AAH_SMOKE_URL"); u != "" {
+		return strings.TrimSuffix(u, "/"), func() {}
+	}
+
+	appDir := os.Getenv("AAH_APP_DIR")
+	if appDir == "" {
+		appDir = filepath.Join("..", "..")
+	}
+
+	binary := os.Getenv("AAH_SMOKE_BINARY")
+	if binary == "" {
+		binary = filepath.Join(appDir, "build", "bin", "{{ .Binary }}")
+	}
+	if _, err := os.Stat(binary); err != nil {
+		t.Skipf("application binary '%s' not found, run 'aah build' first", binary)
+	}
+
+	profile := os.Getenv("AAH_APP_PROFILE")
+	if _, err := os.Stat(filepath.Join(appDir, "config", "env", profile+".conf")); profile == "" || err != nil {
+		profile = "dev"
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	_ = l.Close()
+	_, port, _ := net.SplitHostPort(addr)
+
+	cmd := exec.Command(binary, "-profile="+profile)
+	cmd.Dir = appDir
+	cmd.Env = append(os.Environ(), "AAH_SERVER_PORT="+port)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err = cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	stop := func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}
+
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		conn, err := net.DialTimeout("tcp", addr, time.Second)
+		if err == nil {
+			_ = conn.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			stop()
+			t.Fatalf("application did not start on %s: %s", addr, err)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	return "http://" + addr, stop
+}
+`
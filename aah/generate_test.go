@@ -0,0 +1,499 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestGenerateNames(t *testing.T) {
+	assert.Equal(t, "user_profile", toSnakeCase("UserProfile"))
+	assert.Equal(t, "http_server", toSnakeCase("HTTPServer"))
+	assert.Equal(t, "user_id", toSnakeCase("UserID"))
+	assert.Equal(t, "UserProfile", toExported("user_profile"))
+	assert.Equal(t, "User", toExported("user"))
+
+	pkg, name := splitPkgName("v1.User")
+	assert.Equal(t, "v1", pkg)
+	assert.Equal(t, "User", name)
+
+	pkg, name = splitPkgName("User")
+	assert.Equal(t, "", pkg)
+	assert.Equal(t, "User", name)
+
+	assert.Equal(t, []string{"Index", "Show"}, splitList(" Index, ,Show "))
+}
+
+func TestGenerateController(t *testing.T) {
+	ctrl := newGenController("v1", "User", []string{"Index", "show", "Delete", "Archive"})
+	assert.Equal(t, "v1", ctrl.Package)
+	assert.Equal(t, "v1.User", ctrl.Controller)
+	assert.Equal(t, "/v1/user", ctrl.Actions[0].Path)
+	assert.Equal(t, "/v1/user/:id", ctrl.Actions[1].Path)
+	assert.Equal(t, "Show", ctrl.Actions[1].Name)
+	assert.Equal(t, "DELETE", ctrl.Actions[2].Method)
+	assert.Equal(t, "/v1/user/archive", ctrl.Actions[3].Path)
+	assert.Equal(t, "v1_user_archive", ctrl.Actions[3].RouteName)
+}
+
+func TestGenerateModel(t *testing.T) {
+	_, err := newGenModel("User", "name", "", []string{"json"}, true)
+	assert.Equal(t, "invalid field 'name', expected format is 'name:type'", err.Error())
+
+	model, err := newGenModel("User", "name:string,age:integer,paid_at:datetime,type:text", "users", []string{"json"}, true)
+	assert.Nil(t, err)
+	assert.Equal(t, "name string, age int, paidAt time.Time, typeValue string", model.ParamsDecl)
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, renderTmpl(buf, genModelTemplate, model))
+	assert.Equal(t, []string{"User", "NewUser", "TableName"}, goDeclNames(t, buf.Bytes()))
+	out := buf.String()
+	assert.True(t, strings.Contains(out, "\t\"time\"\n"))
+	assert.True(t, strings.Contains(out, "\tID int64 `json:\"id\" db:\"id\"`\n"))
+	assert.True(t, strings.Contains(out, "\tPaidAt time.Time `json:\"paid_at\" db:\"paid_at\"`\n"))
+	assert.True(t, strings.Contains(out, "\t\tType: typeValue,\n"))
+	assert.True(t, strings.Contains(out, "\treturn \"users\"\n"))
+
+	model, err = newGenModel("Tag", "label:string", "", nil, false)
+	assert.Nil(t, err)
+	buf.Reset()
+	assert.Nil(t, renderTmpl(buf, genModelTemplate, model))
+	assert.Equal(t, []string{"Tag", "NewTag"}, goDeclNames(t, buf.Bytes()))
+	assert.False(t, strings.Contains(buf.String(), "time"))
+}
+
+func TestGenerateMiddleware(t *testing.T) {
+	mw := &genMiddleware{Name: toExported("request_timer"), AppImportPath: "github.com/user/sample"}
+	assert.Equal(t, "RequestTimer", mw.Name)
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, renderTmpl(buf, genMiddlewareTemplate, mw))
+	assert.Equal(t, []string{"RequestTimer"}, goDeclNames(t, buf.Bytes()))
+	out := buf.String()
+	assert.True(t, strings.Contains(out, "func RequestTimer(ctx *aah.Context, m *aah.Middleware) {\n"))
+	assert.True(t, strings.Contains(out, "\tm.Next(ctx)\n"))
+
+	buf.Reset()
+	assert.Nil(t, renderTmpl(buf, genMiddlewareGuideTemplate, mw))
+	out = buf.String()
+	assert.True(t, strings.Contains(out, "        \"github.com/user/sample/app/middleware\"\n"))
+	assert.True(t, strings.Contains(out, "        aah.Middlewares(middleware.RequestTimer)\n"))
+}
+
+func TestGenerateMigration(t *testing.T) {
+	assert.Equal(t, "create_users", migrationName("CreateUsers"))
+	assert.Equal(t, "add_email_index", migrationName(" add-email index "))
+
+	dir, err := ioutil.TempDir("", "gen-migration")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	for _, direction := range []string{migrationUp, migrationDown} {
+		mg := &genMigration{
+			Version:     "20170601150405",
+			Name:        "create_users",
+			Dialect:     "mysql",
+			DialectName: migrationDialects["mysql"],
+			Direction:   direction,
+			Created:     "2017-06-01T15:04:05Z",
+		}
+		buf := &bytes.Buffer{}
+		assert.Nil(t, renderTmpl(buf, genMigrationTemplate, mg))
+		out := buf.String()
+		assert.True(t, strings.HasPrefix(out, "-- aah migration: create_users ("+direction+")\n-- version: 20170601150405\n-- dialect: mysql (MySQL)\n"))
+		assert.True(t, strings.Contains(out, "-- created: 2017-06-01T15:04:05Z\n"))
+		if direction == migrationUp {
+			assert.True(t, strings.Contains(out, "-- Write your MySQL statements to apply the migration.\n"))
+		} else {
+			assert.True(t, strings.Contains(out, "-- Write your MySQL statements to revert the 'up' migration.\n"))
+		}
+
+		file := filepath.Join(dir, mg.Version+"_"+mg.Name+"."+direction+".sql")
+		assert.Nil(t, ioutil.WriteFile(file, buf.Bytes(), permRWRR))
+	}
+
+	all, err := loadMigrations(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(all))
+	assert.Equal(t, "create_users", all[0].Name)
+	assert.Equal(t, filepath.Join(dir, "20170601150405_create_users.up.sql"), all[0].UpFile)
+	assert.Equal(t, filepath.Join(dir, "20170601150405_create_users.down.sql"), all[0].DownFile)
+}
+
+func TestGenerateComposeTemplate(t *testing.T) {
+	compose := &genCompose{
+		Name:       "sample",
+		ImportPath: "github.com/user/sample",
+		GoImage:    "golang:1.9",
+		Port:       "8080",
+		Env:        []composeEnv{{"AAH_SERVER_PORT", "8080"}, {"DB_HOST", "postgres"}},
+		Services: []*composeService{
+			{Name: "postgres", Image: "postgres:10-alpine", Port: "5432", Data: "/var/lib/postgresql/data",
+				Env: []composeEnv{{"POSTGRES_DB", "sample"}}},
+			composeRedisService,
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, renderTmpl(buf, genComposeTemplate, compose))
+	out := buf.String()
+	assert.True(t, strings.Contains(out, "      - .:/go/src/github.com/user/sample\n"))
+	assert.True(t, strings.Contains(out, "      AAH_SERVER_PORT: \"8080\"\n"))
+	assert.True(t, strings.Contains(out, "    depends_on:\n      - postgres\n      - redis\n"))
+	assert.True(t, strings.Contains(out, "      POSTGRES_DB: \"sample\"\n"))
+	assert.True(t, strings.Contains(out, "      - redis-data:/data\n"))
+	assert.True(t, strings.Contains(out, "volumes:\n  gopkg:\n  postgres-data:\n  redis-data:\n"))
+}
+
+func TestGenerateCITemplate(t *testing.T) {
+	ci := &genCI{
+		Name:       "sample",
+		ImportPath: "github.com/user/sample",
+		GoVersions: []string{"1.9.x", "1.10.x"},
+		BuildGo:    "1.10.x",
+		Profiles:   []string{"qa", "prod"},
+		Platforms:  []ciPlatform{{"linux", "amd64"}, {"windows", "amd64"}},
+	}
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, renderTmpl(buf, genCIGitHubTemplate, ci))
+	out := buf.String()
+	assert.True(t, strings.Contains(out, "  GOPATH: ${{ github.workspace }}\n"))
+	assert.True(t, strings.Contains(out, "        go: [\"1.9.x\", \"1.10.x\"]\n"))
+	assert.True(t, strings.Contains(out, "        profile: [\"qa\", \"prod\"]\n"))
+	assert.True(t, strings.Contains(out, "          go-version: \"1.10.x\"\n"))
+	assert.True(t, strings.Contains(out, "        run: aah build -matrix=linux/amd64,windows/amd64 -p=${{ matrix.profile }} -ap=build/${{ matrix.profile }}\n"))
+	assert.True(t, strings.Contains(out, "          path: src/github.com/user/sample/build/${{ matrix.profile }}/*.zip\n"))
+
+	assert.Equal(t, []string{"1.9", "1.10"}, ci.GoTags())
+	buf.Reset()
+	assert.Nil(t, renderTmpl(buf, genCIGitLabTemplate, ci))
+	out = buf.String()
+	assert.True(t, strings.Contains(out, "  APP_DIR: /go/src/github.com/user/sample\n"))
+	assert.True(t, strings.Contains(out, "      - GO_VERSION: [\"1.9\", \"1.10\"]\n"))
+	assert.True(t, strings.Contains(out, "  image: golang:1.10\n"))
+	assert.True(t, strings.Contains(out, "    - aah build -matrix=linux/amd64,windows/amd64 -p=$PROFILE -ap=$CI_PROJECT_DIR/build/$PROFILE\n"))
+
+	buf.Reset()
+	assert.Nil(t, renderTmpl(buf, genCIJenkinsTemplate, ci))
+	out = buf.String()
+	assert.True(t, strings.Contains(out, "    APP_PKG = 'github.com/user/sample'\n"))
+	assert.True(t, strings.Contains(out, "            values '1.9', '1.10'\n"))
+	assert.True(t, strings.Contains(out, "                aah build -matrix=linux/amd64,windows/amd64 -p=$PROFILE -ap=\"$WORKSPACE/build/$PROFILE\"\n"))
+	assert.True(t, strings.Contains(out, "          docker { image 'golang:1.10' }\n"))
+}
+
+func TestGenerateK8sTemplate(t *testing.T) {
+	k8s := &genK8s{
+		Name:        "sample",
+		Version:     "1.0.0",
+		Image:       "sample:1.0.0",
+		Replicas:    2,
+		Port:        "8080",
+		AppDir:      "/app",
+		HealthPath:  "/health",
+		IngressHost: "sample.example.com",
+		Resources:   k8sResources{"100m", "128Mi", "500m", "256Mi"},
+		ConfigFiles: []*k8sConfigFile{
+			{Key: "env_prod.conf", Path: "env/prod.conf", Content: indentLines("prod {\n\n}\n", "    ")},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, renderTmpl(buf, genK8sConfigMapTemplate, k8s))
+	assert.True(t, strings.Contains(buf.String(), "data:\n  env_prod.conf: |\n    prod {\n\n    }\n"))
+
+	buf.Reset()
+	assert.Nil(t, renderTmpl(buf, genK8sDeploymentTemplate, k8s))
+	out := buf.String()
+	assert.True(t, strings.Contains(out, "  replicas: 2\n"))
+	assert.True(t, strings.Contains(out, "              containerPort: 8080\n"))
+	assert.True(t, strings.Contains(out, "              path: /health\n"))
+	assert.True(t, strings.Contains(out, "              mountPath: /app/config\n"))
+	assert.True(t, strings.Contains(out, "              - key: env_prod.conf\n                path: env/prod.conf\n"))
+
+	buf.Reset()
+	assert.Nil(t, renderTmpl(buf, genK8sIngressTemplate, k8s))
+	assert.True(t, strings.Contains(buf.String(), "    - host: sample.example.com\n"))
+}
+
+func TestGenerateHelm(t *testing.T) {
+	repo, tag := splitImage("localhost:5000/sample:1.0.0")
+	assert.Equal(t, "localhost:5000/sample", repo)
+	assert.Equal(t, "1.0.0", tag)
+
+	repo, tag = splitImage("localhost:5000/sample")
+	assert.Equal(t, "localhost:5000/sample", repo)
+	assert.Equal(t, "", tag)
+
+	helm := &genHelm{
+		genK8s:          &genK8s{Name: "sample", Version: "1.0.0", Replicas: 1, Port: "8080", HealthPath: "/"},
+		ImageRepository: "sample",
+		ImageTag:        "1.0.0",
+	}
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, renderTmpl(buf, genHelmChartTemplate, helm))
+	assert.True(t, strings.Contains(buf.String(), "name: sample\n"))
+	assert.True(t, strings.Contains(buf.String(), "appVersion: \"1.0.0\"\n"))
+
+	buf.Reset()
+	assert.Nil(t, renderTmpl(buf, genHelmValuesTemplate, helm))
+	assert.True(t, strings.Contains(buf.String(), "  repository: sample\n  tag: \"1.0.0\"\n"))
+	assert.True(t, strings.Contains(buf.String(), "  port: 8080\n"))
+}
+
+func TestGenerateService(t *testing.T) {
+	assert.Equal(t, "com.github.user.myapp", launchdLabel("github.com/user/myapp"))
+	assert.Equal(t, "myapp", launchdLabel("myapp"))
+
+	svc := &genService{Name: "myapp", Label: "com.github.user.myapp", Binary: "myapp",
+		Dir: "/usr/local/myapp", Profile: "prod"}
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, renderTmpl(buf, genLaunchdTemplate, svc))
+	assert.True(t, strings.Contains(buf.String(), "    <string>/usr/local/myapp/bin/myapp</string>\n    <string>-profile=prod</string>\n"))
+
+	svc.Dir = `C:\myapp`
+	buf.Reset()
+	assert.Nil(t, renderTmpl(buf, genWinSWTemplate, svc))
+	assert.True(t, strings.Contains(buf.String(), `<executable>C:\myapp\bin\myapp.exe</executable>`))
+}
+
+func TestGenerateControllerTest(t *testing.T) {
+	baseDir, err := ioutil.TempDir("", "aah-gen")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(baseDir) }()
+
+	ctrlDir := filepath.Join(baseDir, "src", "github.com", "user", "app", "app", "controllers")
+	assert.Nil(t, os.MkdirAll(filepath.Join(ctrlDir, "v1"), permRWXRXRX))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(ctrlDir, "v1", "user.go"), []byte(`package v1
+
+import "aahframework.org/aah.v0"
+
+type User struct {
+	*aah.Context
+}
+
+func (c *User) Before() {}
+func (c *User) Index(page int, active bool) {}
+func (c *User) Show(id int64) {}
+func (c *User) Create(user *UserForm) {}
+func (c *User) Delete() {}
+func (c *User) helper() {}
+
+type UserForm struct{}
+`), permRWRR))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(ctrlDir, "v1", "user_test.go"), []byte("package v1_test\n"), permRWRR))
+
+	_, _, err = findController(ctrlDir, []string{"*_test.go"}, "v1", "Account")
+	assert.NotNil(t, err)
+
+	ctrlType, pkgDir, err := findController(ctrlDir, []string{"*_test.go"}, "v1", "User")
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(ctrlDir, "v1"), pkgDir)
+
+	ctrlTest := newGenControllerTest("v1", ctrlType)
+	assert.Equal(t, "v1.User", ctrlTest.Controller)
+	assert.Equal(t, 4, len(ctrlTest.Actions))
+	assert.Equal(t, &genTestAction{Name: "Create", Method: "POST", Path: "/v1/user", Body: "{}"}, ctrlTest.Actions[0])
+	assert.Equal(t, &genTestAction{Name: "Delete", Method: "DELETE", Path: "/v1/user/1"}, ctrlTest.Actions[1])
+	assert.Equal(t, &genTestAction{Name: "Index", Method: "GET", Path: "/v1/user?active=true&page=1"}, ctrlTest.Actions[2])
+	assert.Equal(t, &genTestAction{Name: "Show", Method: "GET", Path: "/v1/user/1"}, ctrlTest.Actions[3])
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, renderTmpl(buf, genControllerTableTestTemplate, ctrlTest))
+	_, err = format.Source(buf.Bytes())
+	assert.Nil(t, err)
+	out := buf.String()
+	assert.True(t, strings.Contains(out, "func newUserRequest(method, target, body string) *http.Request {"))
+	assert.True(t, strings.Contains(out, `req:    newUserRequest("POST", "/v1/user", "{}"),`))
+	assert.True(t, strings.Contains(out, "func TestUserShow(t *testing.T) {"))
+}
+
+func TestGenerateSmokeTest(t *testing.T) {
+	assert.Equal(t, "/users/1/files/sample", routeSamplePath("/users/:id/files/*filepath"))
+	assert.Equal(t, "/v1/users/:id", joinRoutePath("/v1/users", "/:id"))
+	assert.Equal(t, "/v1/users/", joinRoutePath("/v1", "users/"))
+	assert.Equal(t, "Update", defaultRouteAction("PATCH"))
+	assert.Equal(t, "Index", defaultRouteAction("GET"))
+
+	smoke := newGenSmoke("myapp", []*appRoute{
+		{Domain: "localhost", Host: "localhost", Name: "index", Path: "/", Method: "GET"},
+		{Domain: "localhost", Host: "localhost", Name: "user_create", Path: "/users", Method: "POST"},
+		{Domain: "localhost", Host: "localhost", Name: "user_show", Path: "/users/:id", Method: "GET"},
+		{Domain: "localhost", Host: "localhost", Name: "user_show_alias", Path: "/users/:uid", Method: "GET"},
+		{Domain: "api", Host: "api.localhost", Name: "index", Path: "/", Method: "GET"},
+	})
+	assert.Equal(t, 3, len(smoke.Routes))
+	assert.Equal(t, &smokeRoute{Name: "localhost/user_show", Host: "localhost", Path: "/users/1"}, smoke.Routes[1])
+	assert.Equal(t, "api.localhost", smoke.Routes[2].Host)
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, renderTmpl(buf, genSmokeTestTemplate, smoke))
+	_, err := format.Source(buf.Bytes())
+	assert.Nil(t, err)
+	out := buf.String()
+	assert.True(t, strings.Contains(out, "\t{\"localhost/user_show\", \"localhost\", \"/users/1\"},\n"))
+	assert.True(t, strings.Contains(out, `binary = filepath.Join(appDir, "build", "bin", "myapp")`))
+}
+
+func TestGenerateRouteName(t *testing.T) {
+	assert.Equal(t, "UserShow", toGoIdent("user_show", true))
+	assert.Equal(t, "type_", toGoIdent("type", false))
+	assert.Equal(t, "_404", toGoIdent("404", false))
+
+	controllers := map[string]*appController{
+		"User": {Name: "User", Type: "User", Methods: map[string]*appAction{
+			"Show": {Name: "Show", Params: []*appActionParam{{Name: "id", Type: "int64"}, {Name: "opts", Type: "*Options"}}},
+		}},
+	}
+	gen := newGenRouteName([]*appRoute{
+		{Domain: "localhost", Name: "index", Path: "/", Method: "GET", Controller: "App", Action: "Index"},
+		{Domain: "localhost", Name: "user_show", Path: "/users/:id/files/*filepath", Method: "GET", Controller: "User", Action: "Show"},
+		{Domain: "localhost", Name: "user_show", Path: "/users/:id/files/*filepath", Method: "HEAD", Controller: "User", Action: "Show"},
+		{Domain: "api", Name: "index", Path: "/", Method: "GET", Controller: "App", Action: "Index"},
+	}, controllers)
+	assert.Equal(t, 3, len(gen.Routes))
+	assert.Equal(t, "LocalhostIndex", gen.Routes[0].Const)
+	assert.Equal(t, "ApiIndex", gen.Routes[2].Const)
+	assert.Equal(t, "id int64, filepath string", gen.Routes[1].ParamList())
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, renderTmpl(buf, genRouteNameTemplate, gen))
+	src, err := format.Source(buf.Bytes())
+	assert.Nil(t, err)
+	out := string(src)
+	assert.True(t, strings.Contains(out, "\tUserShow = \"user_show\"\n"))
+	assert.True(t, strings.Contains(out, "func UserShowArgs(id int64, filepath string) (string, map[string]interface{}) {\n"+
+		"\treturn UserShow, map[string]interface{}{\n"+
+		"\t\t\"id\":       id,\n"+
+		"\t\t\"filepath\": filepath,\n"+
+		"\t}\n}\n"))
+	assert.False(t, strings.Contains(out, "IndexArgs"))
+}
+
+func TestGenerateURLs(t *testing.T) {
+	controllers := map[string]*appController{
+		"User": {Name: "User", Type: "User", Methods: map[string]*appAction{
+			"Show": {Name: "Show", Params: []*appActionParam{{Name: "id", Type: "int"}}},
+		}},
+	}
+	gen := newGenRouteName([]*appRoute{
+		{Domain: "localhost", Name: "index", Path: "/", Controller: "App", Action: "Index"},
+		{Domain: "localhost", Name: "user_show", Path: "/users/:id", Controller: "User", Action: "Show"},
+		{Domain: "localhost", Name: "user_file", Path: "/users/:id/files/*filepath", Controller: "User", Action: "File"},
+		{Domain: "localhost", Name: "user_edit", Path: "/users/:id/edit/", Controller: "User", Action: "Show"},
+	}, controllers)
+	assert.Equal(t, `"/"`, gen.Routes[0].PathExpr())
+	assert.Equal(t, `"/users/" + pathParam(id)`, gen.Routes[1].PathExpr())
+	assert.Equal(t, `"/users/" + pathParam(id) + "/files/" + catchAllParam(filepath)`, gen.Routes[2].PathExpr())
+	assert.Equal(t, `"/users/" + pathParam(id) + "/edit/"`, gen.Routes[3].PathExpr())
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, renderTmpl(buf, genURLsTemplate, gen))
+	src, err := format.Source(buf.Bytes())
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(string(src), "func UserShowURL(id int) string {\n\treturn \"/users/\" + pathParam(id)\n}\n"))
+	assert.True(t, strings.Contains(string(src), "func UserFileURL(id string, filepath string) string {\n"))
+}
+
+func TestGenerateAssetsTemplate(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.Nil(t, renderTmpl(buf, genAssetsTemplate, &genAssets{Prefix: "/static", Manifest: "static/manifest.json"}))
+	_, err := format.Source(buf.Bytes())
+	assert.Nil(t, err)
+	out := buf.String()
+	assert.True(t, strings.Contains(out, `const ManifestFile = "static/manifest.json"`))
+	assert.True(t, strings.Contains(out, `const Prefix = "/static"`))
+	assert.True(t, strings.Contains(out, `aah.AddTemplateFunc(template.FuncMap{"asset": Path})`))
+}
+
+func TestGenerateModelsFromDB(t *testing.T) {
+	out := []byte("order_items\tid\tbigint\tNO\t1\n" +
+		"order_items\tproduct_url\tvarchar(255)\tYES\t0\n" +
+		"order_items\tqty\tinteger\tNO\t0\n" +
+		"order_items\tcreated_at\ttimestamp without time zone\tNO\t0\n" +
+		"order_items\tupdated_at\ttimestamp without time zone\tNO\t0\n" +
+		"schema_migrations\tversion\tvarchar(14)\tNO\t1\n" +
+		"categories\tname\ttext\tNO\t0\n")
+	tables := parseDBSchema(out, "public.schema_migrations")
+	assert.Equal(t, 2, len(tables))
+	assert.Equal(t, "order_items", tables[0].Name)
+	assert.Equal(t, 5, len(tables[0].Columns))
+	assert.True(t, tables[0].Columns[0].PrimaryKey)
+	assert.True(t, tables[0].Columns[1].Nullable)
+
+	selected, err := selectDBTables(tables, []string{"categories"})
+	assert.Nil(t, err)
+	assert.Equal(t, "categories", selected[0].Name)
+	_, err = selectDBTables(tables, []string{"users"})
+	assert.Equal(t, "table 'users' not found in the database", err.Error())
+
+	assert.Equal(t, "int", sqlGoType("integer", false))
+	assert.Equal(t, "int64", sqlGoType("bigint(20) unsigned", false))
+	assert.Equal(t, "bool", sqlGoType("tinyint(1)", false))
+	assert.Equal(t, "*float64", sqlGoType("decimal(10,2)", true))
+	assert.Equal(t, "time.Time", sqlGoType("datetime", false))
+	assert.Equal(t, "[]byte", sqlGoType("bytea", true))
+	assert.Equal(t, "string", sqlGoType("interval", false))
+
+	assert.Equal(t, "Category", singularize("Category"))
+	assert.Equal(t, "Category", singularize("Categories"))
+	assert.Equal(t, "Address", singularize("Addresses"))
+	assert.Equal(t, "Status", singularize("Status"))
+
+	model := newGenModelFromTable(tables[0], []string{"json"})
+	assert.Equal(t, "OrderItem", model.Name)
+	assert.Equal(t, "ProductURL", model.Fields[1].Name)
+	assert.Equal(t, "`json:\"product_url\" db:\"product_url\"`", model.Fields[1].Tag)
+	assert.True(t, model.Fields[0].Common)
+	assert.True(t, model.HasCommon)
+	assert.Equal(t, "productUrl *string, qty int", model.ParamsDecl)
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, renderTmpl(buf, genModelTemplate, model))
+	_, err = format.Source(buf.Bytes())
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(buf.String(), "\t\tCreatedAt: time.Now(),\n"))
+	assert.True(t, strings.Contains(buf.String(), "return \"order_items\""))
+}
+
+// goDeclNames method parses the generated Go source and returns its top
+// level type and func names in the declared order.
+func goDeclNames(t *testing.T, src []byte) []string {
+	f, err := parser.ParseFile(token.NewFileSet(), "gen.go", src, 0)
+	assert.Nil(t, err)
+	if err != nil {
+		t.Log(string(src))
+		return nil
+	}
+
+	var names []string
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			names = append(names, d.Name.Name)
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok {
+					names = append(names, ts.Name.Name)
+				}
+			}
+		}
+	}
+	return names
+}
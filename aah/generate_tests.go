@@ -0,0 +1,227 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+)
+
+var (
+	genTestCmdFlags  = flag.NewFlagSet("test", flag.ContinueOnError)
+	genTestForceFlag = genTestCmdFlags.Bool("force", false, "Overwrite the existing test file")
+	genTestCmd       = &command{
+		Name:      "test",
+		UsageLine: "aah generate test <Controller> [-force]",
+		Flags:     genTestCmdFlags,
+		MaxArgs:   1,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "generate table-driven test skeleton of controller",
+		Long: `
+Generates the table-driven test skeleton '<name>_test.go' next to the
+controller for each action discovered from the controller source code.
+Controller name can have package prefix, for e.g.: 'v1.User'.
+
+Test file has the request construction helper of the controller and one
+test per action, request method and path follows the route convention of
+'aah generate controller', action parameters are filled as path or query
+parameters with sample values, non built-in types as JSON request body.
+Adjust the requests to match 'config/routes.conf'.
+
+Example:
+    aah generate test User
+
+    aah generate test v1.User -force
+`,
+	}
+)
+
+type (
+	genControllerTest struct {
+		Package    string
+		Name       string
+		Controller string
+		Actions    []*genTestAction
+	}
+
+	genTestAction struct {
+		Name   string
+		Method string
+		Path   string
+		Body   string
+	}
+)
+
+func genTestRun(args []string) {
+	if len(args) == 0 {
+		fatalExit(exitUsage, "Controller name is required. Run 'aah help generate test'.")
+		return
+	}
+
+	pkg, name := splitPkgName(args[0])
+	excludes, _ := appProject.BuildCfg.StringList("build.ast_excludes")
+	ctrlDir := filepath.Join(appProject.BaseDir, "app", "controllers")
+	ctrlType, pkgDir, err := findController(ctrlDir, append(excludes, "*_test.go"), pkg, toExported(name))
+	if err != nil {
+		fatalErr(err)
+		return
+	}
+
+	ctrlTest := newGenControllerTest(pkg, ctrlType)
+	file := filepath.Join(pkgDir, toSnakeCase(ctrlType.Name)+"_test.go")
+	if err = writeGenFile(file, genControllerTableTestTemplate, ctrlTest, *genTestForceFlag); err != nil {
+		fatal(err)
+	}
+}
+
+// findController method finds the controller type of given package and name
+// via Go AST and returns it along with package directory.
+func findController(ctrlDir string, excludes []string, pkg, name string) (*typeInfo, string, error) {
+	prg, errs := loadProgram(ctrlDir, ess.Excludes(excludes), map[string]map[string]uint8{})
+	if len(errs) > 0 {
+		msgs := make([]string, 0, len(errs))
+		for _, e := range errs {
+			msgs = append(msgs, e.Error())
+		}
+		return nil, "", newExitErr(exitParseError, errors.New(strings.Join(msgs, "\n")))
+	}
+	prg.Process()
+
+	pkgDir := filepath.Join(ctrlDir, filepath.FromSlash(strings.Replace(pkg, ".", "/", -1)))
+	for _, p := range prg.Packages {
+		if filepath.Clean(p.FilePath) != filepath.Clean(pkgDir) {
+			continue
+		}
+		if t, found := p.Types[name]; found {
+			if len(t.Methods) == 0 {
+				return nil, "", fmt.Errorf("controller '%s' has no actions", name)
+			}
+			return t, p.FilePath, nil
+		}
+	}
+
+	return nil, "", newExitErr(exitUsage, fmt.Errorf("controller '%s' not found in '%s'", name, pkgDir))
+}
+
+// newGenControllerTest method creates the test data of controller actions,
+// method and path are from 'aah generate controller' route convention.
+func newGenControllerTest(pkg string, t *typeInfo) *genControllerTest {
+	methods := append([]*methodInfo{}, t.Methods...)
+	sort.Slice(methods, func(i, j int) bool { return methods[i].Name < methods[j].Name })
+
+	var names []string
+	for _, m := range methods {
+		names = append(names, m.Name)
+	}
+	ctrl := newGenController(pkg, t.Name, names)
+
+	ctrlTest := &genControllerTest{Package: ctrl.Package, Name: ctrl.Name, Controller: ctrl.Controller}
+	for i, a := range ctrl.Actions {
+		action := &genTestAction{Name: a.Name, Method: a.Method, Path: a.Path}
+		query := url.Values{}
+		for _, p := range methods[i].Parameters {
+			if !p.Type.IsBuiltIn {
+				action.Body = "{}"
+				continue
+			}
+
+			value := sampleParamValue(p.Type.Expr)
+			if segment := ":" + p.Name; strings.Contains(action.Path, segment) {
+				action.Path = strings.Replace(action.Path, segment, value, 1)
+				continue
+			}
+			query.Add(p.Name, value)
+		}
+
+		// path parameter without action parameter, e.g. ':id'
+		if base := path.Base(action.Path); strings.HasPrefix(base, ":") {
+			action.Path = path.Dir(action.Path) + "/1"
+		}
+		if len(query) > 0 {
+			action.Path += "?" + query.Encode()
+		}
+		ctrlTest.Actions = append(ctrlTest.Actions, action)
+	}
+	return ctrlTest
+}
+
+func sampleParamValue(typeName string) string {
+	typeName = strings.TrimPrefix(strings.TrimPrefix(typeName, "[]"), "*")
+	switch {
+	case typeName == "bool":
+		return "true"
+	case typeName == "string":
+		return "value"
+	case strings.HasPrefix(typeName, "float"):
+		return "1.5"
+	default:
+		return "1"
+	}
+}
+
+func init() {
+	genTestCmd.Run = genTestRun
+	generateCmd.Subcommands = append(generateCmd.Subcommands, genTestCmd)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Generator templates
+//___________________________________
+
+const genControllerTableTestTemplate = `package {{ .Package }}
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// new{{ .Name }}Request creates the request of {{ .Controller }} action, JSON
+// content type is set if body is supplied.
+func new{{ .Name }}Request(method, target, body string) *http.Request {
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+
+	req := httptest.NewRequest(method, target, r)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	return req
+}
+{{ range .Actions }}
+func Test{{ $.Name }}{{ .Name }}(t *testing.T) {
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+	}{
+		{
+			name:   "success",
+			req:    new{{ $.Name }}Request("{{ .Method }}", "{{ .Path }}", {{ printf "%q" .Body }}),
+			status: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Skipf("TODO: send %s %s to {{ $.Controller }}.{{ .Name }} and assert status %d",
+				tc.req.Method, tc.req.URL, tc.status)
+		})
+	}
+}
+{{ end }}`
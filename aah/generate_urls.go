@@ -0,0 +1,148 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var (
+	genURLsCmdFlags  = flag.NewFlagSet("urls", flag.ContinueOnError)
+	genURLsForceFlag = genURLsCmdFlags.Bool("force", false, "Overwrite the existing generated file")
+	genURLsCmd       = &command{
+		Name:      "urls",
+		UsageLine: "aah generate urls [-force]",
+		Flags:     genURLsCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "generate typed URL builder functions from routes.conf",
+		Long: `
+Generates the package 'app/routes' with URL builder function of every named
+route of 'config/routes.conf', path parameters are the typed arguments:
+
+    // routes.conf: user_show { path = "/users/:id", controller = "User", action = "Show" }
+    // controller:  func (c *User) Show(id int64)
+    routes.UserShowURL(42) // "/users/42"
+
+Function name is the exported route name with suffix 'URL', argument type is
+taken from the controller action parameter of same name if it's a Go basic
+type, otherwise 'string'. So the changed route path or action signature
+fails at compile time once the package is regenerated. Parameter values are
+path escaped, catch-all parameter keeps the '/'.
+
+URL is the path of the route domain, route name which exists in more than
+one domain is prefixed with the domain name, same as 'aah generate routename'.
+
+Run 'aah generate urls -force' after route or action changes.
+
+Example:
+    aah generate urls
+`,
+	}
+)
+
+func genURLsRun(args []string) {
+	routes, err := loadAppRoutes(appProject.BaseDir)
+	if err != nil {
+		fatalExit(exitRouteError, err)
+		return
+	}
+
+	controllers, err := loadAppControllers(filepath.Join(appProject.BaseDir, "app", "controllers"))
+	if err != nil {
+		fatalExit(exitParseError, err)
+		return
+	}
+
+	gen := newGenRouteName(routes, controllers)
+	if len(gen.Routes) == 0 {
+		fatalExit(exitRouteError, errors.New("no named routes found in 'config/routes.conf'"))
+		return
+	}
+
+	file := filepath.Join(appProject.BaseDir, "app", "routes", "urls.go")
+	if err = writeGenFile(file, genURLsTemplate, gen, *genURLsForceFlag); err != nil {
+		fatal(err)
+	}
+}
+
+// PathExpr method returns the Go string expression which builds the route
+// path from the parameters, e.g. '"/users/" + pathParam(id)'.
+func (r *genRouteNameRoute) PathExpr() string {
+	params := map[string]*genRouteParam{}
+	for _, p := range r.Params {
+		params[p.Name] = p
+	}
+
+	var parts []string
+	literal := ""
+	for i, seg := range strings.Split(r.Path, "/") {
+		if i > 0 {
+			literal += "/"
+		}
+
+		p, isParam := params[strings.TrimLeft(seg, ":*")]
+		if !isParam || (!strings.HasPrefix(seg, ":") && !strings.HasPrefix(seg, "*")) {
+			literal += seg
+			continue
+		}
+
+		if literal != "" {
+			parts = append(parts, strconv.Quote(literal))
+			literal = ""
+		}
+		fn := "pathParam"
+		if strings.HasPrefix(seg, "*") {
+			fn = "catchAllParam"
+		}
+		parts = append(parts, fn+"("+p.Ident+")")
+	}
+	if literal != "" || len(parts) == 0 {
+		parts = append(parts, strconv.Quote(literal))
+	}
+	return strings.Join(parts, " + ")
+}
+
+func init() {
+	genURLsCmd.Run = genURLsRun
+	generateCmd.Subcommands = append(generateCmd.Subcommands, genURLsCmd)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Generator templates
+//___________________________________
+
+const genURLsTemplate = `// Code generated by 'aah generate urls' from 'config/routes.conf'. DO NOT EDIT.
+
+// Package routes provides the typed URL builder functions of the named
+// routes of 'config/routes.conf'.
+package routes
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+{{ range .Routes }}
+// {{ .Const }}URL returns the URL path of route '{{ .Name }}' path
+// '{{ .Path }}' of domain '{{ .Domain }}'.
+func {{ .Const }}URL({{ .ParamList }}) string {
+	return {{ .PathExpr }}
+}
+{{ end }}
+// pathParam returns the path escaped value of the path parameter.
+func pathParam(v interface{}) string {
+	return url.PathEscape(fmt.Sprint(v))
+}
+
+// catchAllParam returns the path escaped value of the catch-all parameter,
+// the '/' of the value is kept.
+func catchAllParam(v interface{}) string {
+	return strings.Replace(url.PathEscape(strings.TrimPrefix(fmt.Sprint(v), "/")), "%2F", "/", -1)
+}
+`
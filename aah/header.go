@@ -0,0 +1,118 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+)
+
+// headerCommentPrefix is the line comment prefix by file extension, files
+// with other extensions do not get the license header.
+var headerCommentPrefix = map[string]string{
+	".go":      "//",
+	".sql":     "--",
+	".conf":    "#",
+	".project": "#",
+	".sh":      "#",
+	".yml":     "#",
+	".yaml":    "#",
+}
+
+var (
+	headerText   string
+	headerLoaded bool
+)
+
+// projectHeader method returns the license header from the file configured
+// via 'project.header_file' in 'aah.project', path is relative to the
+// application base directory. Empty string if it's not configured.
+func projectHeader() (string, error) {
+	if headerLoaded {
+		return headerText, nil
+	}
+
+	cfg := appProject.BuildCfg
+	if cfg == nil {
+		projectFile := filepath.Join(appProject.BaseDir, aahProjectIdentifier)
+		if ess.IsStrEmpty(appProject.BaseDir) || !ess.IsFileExists(projectFile) {
+			return "", nil
+		}
+
+		var err error
+		if cfg, err = loadProjectConfig(projectFile, appProject.BaseDir); err != nil {
+			return "", newExitErr(exitConfigError, fmt.Errorf("aah project file error: %s", err))
+		}
+	}
+
+	if headerFile := cfg.StringDefault("project.header_file", ""); !ess.IsStrEmpty(headerFile) {
+		if !filepath.IsAbs(headerFile) {
+			headerFile = filepath.Join(appProject.BaseDir, headerFile)
+		}
+
+		b, err := ioutil.ReadFile(headerFile)
+		if err != nil {
+			return "", newExitErr(exitConfigError, fmt.Errorf("unable to read 'project.header_file': %s", err))
+		}
+		headerText = strings.TrimSpace(string(b))
+	}
+
+	headerLoaded = true
+	return headerText, nil
+}
+
+// commentHeader method returns the header as line comments for given file,
+// empty string if the file type is not supported. Header which is already
+// written as comments is used as-is.
+func commentHeader(header, file string) string {
+	prefix, found := headerCommentPrefix[filepath.Ext(file)]
+	if !found || ess.IsStrEmpty(header) {
+		return ""
+	}
+
+	if strings.HasPrefix(header, prefix) || (prefix == "//" && strings.HasPrefix(header, "/*")) {
+		return header
+	}
+
+	lines := strings.Split(header, "\n")
+	for i, l := range lines {
+		if l = strings.TrimRight(l, " \t\r"); ess.IsStrEmpty(l) {
+			lines[i] = prefix
+		} else {
+			lines[i] = prefix + " " + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// hasHeader method reports whether the content starts with given
+// commented header.
+func hasHeader(content []byte, commented string) bool {
+	return bytes.HasPrefix(bytes.TrimLeft(content, "\r\n"), []byte(commented))
+}
+
+// addHeader method prepends the project license header to the content if
+// it's configured and not present already.
+func addHeader(file string, content []byte) ([]byte, error) {
+	header, err := projectHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	commented := commentHeader(header, file)
+	if ess.IsStrEmpty(commented) || hasHeader(content, commented) {
+		return content, nil
+	}
+
+	buf := bytes.NewBufferString(commented)
+	buf.WriteString("\n\n")
+	buf.Write(bytes.TrimLeft(content, "\r\n"))
+	return buf.Bytes(), nil
+}
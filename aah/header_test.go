@@ -0,0 +1,32 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestHeaderComment(t *testing.T) {
+	header := "Copyright (c) Example Inc.\n\nLicensed under MIT."
+	assert.Equal(t, "// Copyright (c) Example Inc.\n//\n// Licensed under MIT.", commentHeader(header, "main.go"))
+	assert.Equal(t, "-- Copyright (c) Example Inc.\n--\n-- Licensed under MIT.", commentHeader(header, "1_init.up.sql"))
+	assert.Equal(t, "", commentHeader(header, "index.html"))
+	assert.Equal(t, "/* Copyright */", commentHeader("/* Copyright */", "main.go"))
+}
+
+func TestHeaderAdd(t *testing.T) {
+	headerText, headerLoaded = "Copyright (c) Example Inc.", true
+	defer func() { headerText, headerLoaded = "", false }()
+
+	content, err := addHeader("main.go", []byte("package main\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, "// Copyright (c) Example Inc.\n\npackage main\n", string(content))
+
+	again, err := addHeader("main.go", content)
+	assert.Nil(t, err)
+	assert.Equal(t, string(content), string(again))
+}
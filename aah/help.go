@@ -4,13 +4,15 @@
 
 package main
 
+import "os"
+
 var helpCmd = &command{
 	Name:      "help",
-	UsageLine: "aah help [command]",
-	ArgsCount: 1,
+	UsageLine: "aah help [command [subcommand] | topic]",
+	MaxArgs:   2,
 	Short:     "to learn about aah command",
 	Long: `
-'aah help' displays the command usage.
+'aah help' displays the command usage or help topic documentation.
 
 Go to https://docs.aahframework.org/aah-cli-tool.html to learn more.
 
@@ -18,6 +20,14 @@ Example:
     aah help
 
     aah help [command-name]
+
+    aah help [command-name] [subcommand-name]
+
+    aah help [topic-name]
+
+    aah help aah.project
+
+    aah help routes.conf
 `,
 	Run: func(args []string) {
 		if len(args) == 0 {
@@ -25,11 +35,39 @@ Example:
 			return
 		}
 
-		cmd, err := subCmds.Find(args[0])
-		if err != nil {
-			commandNotFound(args[0])
+		cmd, isTopic, name := findHelp(args)
+		if cmd == nil {
+			commandNotFound(name)
+			return
 		}
 
+		if isTopic {
+			printTopic(os.Stdout, cmd)
+			return
+		}
 		cmd.Usage()
 	},
 }
+
+// findHelp method returns the command or help topic for given args, commands
+// are looked up first, so a command can't be shadowed by a topic. Unknown
+// name is returned if it's not found.
+func findHelp(args []string) (*command, bool, string) {
+	cmd, err := subCmds.Find(args[0])
+	if err != nil {
+		if topic, err := helpTopics.Find(args[0]); err == nil {
+			return topic, true, ""
+		}
+		return nil, false, args[0]
+	}
+
+	// help for subcommand, e.g. 'aah help docs gen'
+	for _, name := range args[1:] {
+		sub, err := cmd.Subcommands.Find(name)
+		if err != nil {
+			return nil, false, name
+		}
+		cmd = sub
+	}
+	return cmd, false, ""
+}
@@ -0,0 +1,38 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestHelpFind(t *testing.T) {
+	cmd, isTopic, name := findHelp([]string{"routes"})
+	assert.Equal(t, routesCmd, cmd)
+	assert.False(t, isTopic)
+	assert.Equal(t, "", name)
+
+	cmd, isTopic, _ = findHelp([]string{"routes", "list"})
+	assert.Equal(t, routesListCmd, cmd)
+	assert.False(t, isTopic)
+
+	cmd, isTopic, _ = findHelp([]string{"routes.conf"})
+	assert.Equal(t, "routes.conf", cmd.Name)
+	assert.True(t, isTopic)
+
+	cmd, isTopic, _ = findHelp([]string{"aah.project"})
+	assert.Equal(t, "aah.project", cmd.Name)
+	assert.True(t, isTopic)
+
+	cmd, _, name = findHelp([]string{"routes", "unknown"})
+	assert.Nil(t, cmd)
+	assert.Equal(t, "unknown", name)
+
+	cmd, _, name = findHelp([]string{"unknown"})
+	assert.Nil(t, cmd)
+	assert.Equal(t, "unknown", name)
+}
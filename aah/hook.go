@@ -0,0 +1,121 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+type (
+	// hook is a pre-run step of the command, declared via `command.Hooks`.
+	// Hooks are executed in the declared order after the flags are parsed,
+	// returning an error aborts the command execution.
+	hook func(c *command) error
+
+	// projectInfo holds the aah application details resolved by the hooks
+	// `requireInsideApp` and `loadProject`.
+	projectInfo struct {
+		ImportPath string
+		BaseDir    string
+		BuildCfg   *config.Config
+	}
+)
+
+// appProject is current aah application, available to the command `Run` once
+// the hooks are executed.
+var appProject = &projectInfo{}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Hooks
+//___________________________________
+
+// validateGopath hook checks Go is installed and GOPATH is available.
+func validateGopath(c *command) error {
+	// check go is installed or not
+	if !ess.LookExecutable("go") {
+		return errors.New("Unable to find Go executable in PATH")
+	}
+
+	var err error
+
+	// get GOPATH, refer https://godoc.org/aahframework.org/essentials.v0#GoPath
+	if gopath, err = ess.GoPath(); err != nil {
+		return err
+	}
+
+	if gocmd, err = exec.LookPath("go"); err != nil {
+		return err
+	}
+
+	gosrcDir = filepath.Join(gopath, "src")
+	return nil
+}
+
+// requireInsideApp hook resolves the aah application import path from the
+// command flag 'importPath' (or 'ip') otherwise from current directory and
+// ensures it's an aah application.
+func requireInsideApp(c *command) error {
+	importPath := flagValue(c, "importPath", "ip")
+	if ess.IsStrEmpty(importPath) {
+		importPath = importPathRelwd()
+	}
+
+	if !ess.IsImportPathExists(importPath) {
+		return fmt.Errorf("Given import path '%s' does not exists", importPath)
+	}
+
+	baseDir := filepath.Join(gosrcDir, filepath.FromSlash(importPath))
+	if !ess.IsFileExists(filepath.Join(baseDir, aahProjectIdentifier)) {
+		return newExitErr(exitConfigError,
+			errors.New("Missing 'aah.project' file, not a valid aah framework application."))
+	}
+
+	appProject.ImportPath = importPath
+	appProject.BaseDir = baseDir
+	return nil
+}
+
+// loadProject hook initializes the aah application and loads the
+// 'aah.project' file.
+func loadProject(c *command) error {
+	aah.Init(appProject.ImportPath)
+	appProject.BaseDir = aah.AppBaseDir()
+
+	buildCfg, err := loadAahProjectFile(appProject.BaseDir)
+	if err != nil {
+		return newExitErr(exitConfigError, fmt.Errorf("aah project file error: %s", err))
+	}
+
+	_ = log.SetLevel(buildCfg.StringDefault("build.log_level", "info"))
+	appProject.BuildCfg = buildCfg
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// flagValue method returns the first non-empty value of given command flag
+// names.
+func flagValue(c *command, names ...string) string {
+	if c.Flags == nil {
+		return ""
+	}
+
+	for _, name := range names {
+		if f := c.Flags.Lookup(name); f != nil && !ess.IsStrEmpty(f.Value.String()) {
+			return f.Value.String()
+		}
+	}
+	return ""
+}
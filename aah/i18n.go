@@ -0,0 +1,224 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+)
+
+var (
+	i18nCmd = &command{
+		Name:      "i18n",
+		UsageLine: "aah i18n <subcommand> [arguments]",
+		Short:     "application i18n message tools",
+		Long: `
+Tools for the application i18n message files 'i18n/<name>.<locale>', e.g.
+'i18n/messages.en' and 'i18n/messages.en-US'.
+
+Message keys are referenced via translation calls:
+
+    views       {{ i18n . "label.pages.app.index.title" }}
+    Go sources  ctx.Msg("label.welcome"), ctx.Msgl(locale, "label.welcome")
+
+Only string literal keys are recognized, dynamic keys are not.
+
+Example:
+    aah i18n extract
+    aah i18n check -fail-on-missing
+`,
+	}
+
+	// i18nViewKeyRegex matches the view translation call, e.g.
+	// '{{ i18n . "label.title" }}'.
+	i18nViewKeyRegex = regexp.MustCompile(`\bi18n\s+\S+\s+"([^"]+)"`)
+
+	i18nKeyRegex    = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)*$`)
+	i18nLocaleRegex = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})?$`)
+
+	// i18nGoCalls are the Go translation methods and its key argument
+	// index.
+	i18nGoCalls = map[string]int{"Msg": 0, "Msgl": 1}
+)
+
+type (
+	// i18nKeyRef is the message key reference in the view or Go source.
+	i18nKeyRef struct {
+		Key  string
+		File string
+		Line int
+	}
+
+	// i18nLocaleFile is the message file of the locale and its keys.
+	i18nLocaleFile struct {
+		File   string
+		Locale string
+		Keys   []string
+	}
+)
+
+// findI18nKeyRefs method returns the message key references of the views
+// directory and Go sources of app directory, sorted by key and position.
+func findI18nKeyRefs(baseDir string) ([]*i18nKeyRef, error) {
+	var refs []*i18nKeyRef
+	viewsDir := filepath.Join(baseDir, "views")
+	if ess.IsFileExists(viewsDir) {
+		err := filepath.Walk(viewsDir, func(p string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			b, err := ioutil.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			refs = append(refs, viewI18nKeyRefs(p, string(b))...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fset := token.NewFileSet()
+	err := filepath.Walk(filepath.Join(baseDir, "app"), func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == "vendor" || strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(p, ".go") {
+			return nil
+		}
+		f, err := parser.ParseFile(fset, p, nil, 0)
+		if err != nil {
+			return err
+		}
+		refs = append(refs, goI18nKeyRefs(fset, f)...)
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	sort.SliceStable(refs, func(i, j int) bool {
+		if refs[i].Key != refs[j].Key {
+			return refs[i].Key < refs[j].Key
+		}
+		if refs[i].File != refs[j].File {
+			return refs[i].File < refs[j].File
+		}
+		return refs[i].Line < refs[j].Line
+	})
+	return refs, nil
+}
+
+func viewI18nKeyRefs(file, content string) []*i18nKeyRef {
+	var refs []*i18nKeyRef
+	for _, m := range i18nViewKeyRegex.FindAllStringSubmatchIndex(content, -1) {
+		refs = append(refs, &i18nKeyRef{
+			Key:  content[m[2]:m[3]],
+			File: file,
+			Line: strings.Count(content[:m[0]], "\n") + 1,
+		})
+	}
+	return refs
+}
+
+func goI18nKeyRefs(fset *token.FileSet, f *ast.File) []*i18nKeyRef {
+	var refs []*i18nKeyRef
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		idx, found := i18nGoCalls[sel.Sel.Name]
+		if !found || len(call.Args) <= idx {
+			return true
+		}
+		lit, ok := call.Args[idx].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		if key, err := strconv.Unquote(lit.Value); err == nil {
+			pos := fset.Position(lit.Pos())
+			refs = append(refs, &i18nKeyRef{Key: key, File: pos.Filename, Line: pos.Line})
+		}
+		return true
+	})
+	return refs
+}
+
+// i18nLocaleFiles method returns the message files of i18n directory sorted
+// by file path. Locale is the file extension, files without locale extension
+// are skipped.
+func i18nLocaleFiles(baseDir string) ([]*i18nLocaleFile, error) {
+	i18nDir := filepath.Join(baseDir, "i18n")
+	if !ess.IsFileExists(i18nDir) {
+		return nil, nil
+	}
+
+	var files []*i18nLocaleFile
+	err := filepath.Walk(i18nDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		locale := strings.TrimPrefix(filepath.Ext(p), ".")
+		if !i18nLocaleRegex.MatchString(locale) {
+			return nil
+		}
+		entries, err := parseConfFile(p)
+		if err != nil {
+			return err
+		}
+		files = append(files, &i18nLocaleFile{File: p, Locale: locale, Keys: i18nMessageKeys(entries)})
+		return nil
+	})
+	return files, err
+}
+
+// i18nMessageKeys method returns the sorted message keys of the config
+// entries, i.e. value paths.
+func i18nMessageKeys(entries []*confEntry) []string {
+	var keys []string
+	for _, e := range entries {
+		if e.Kind != confSection {
+			keys = append(keys, e.Path)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// uniqueI18nKeys method returns the unique keys of the references in the
+// order of references.
+func uniqueI18nKeys(refs []*i18nKeyRef) []string {
+	var keys []string
+	seen := map[string]bool{}
+	for _, r := range refs {
+		if !seen[r.Key] {
+			seen[r.Key] = true
+			keys = append(keys, r.Key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,177 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"aahframework.org/log.v0"
+)
+
+var (
+	i18nCheckCmdFlags          = flag.NewFlagSet("check", flag.ContinueOnError)
+	i18nCheckFailOnMissingFlag = i18nCheckCmdFlags.Bool("fail-on-missing", false, "Exit with code 'i18n' if any message key is missing in a locale")
+	i18nCheckCmd               = &command{
+		Name:      "check",
+		UsageLine: "aah i18n check [-fail-on-missing]",
+		Flags:     i18nCheckCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "report missing and unused message keys of locale files",
+		Long: `
+Compares the message keys across the locale files 'i18n/<name>.<locale>' and
+the keys referenced by views and Go sources (refer 'aah help i18n'), reports:
+
+    missing   key is referenced or defined in other locale, but not defined
+              in the locale. Key of the language locale, e.g. 'en' counts
+              for the region locale 'en-US' too, same as aah message lookup.
+    unused    key is defined in locale files, but never referenced. Keys
+              used via dynamic key are reported too, review them.
+
+Command exits with code 13 (refer 'aah help exit-codes') with '-fail-on-missing'
+if any key is missing, use it in CI.
+
+Example(s):
+    aah i18n check
+
+    aah i18n check -fail-on-missing
+`,
+	}
+)
+
+// i18nCheckReport is the missing and unused message keys report, missing
+// keys are per locale.
+type i18nCheckReport struct {
+	Locales []string
+	Keys    int
+	Missing map[string][]string
+	Unused  []string
+}
+
+func i18nCheckRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	baseDir := appProject.BaseDir
+	refs, err := findI18nKeyRefs(baseDir)
+	if err != nil {
+		fatalErr(newExitErr(exitParseError, err))
+		return
+	}
+
+	files, err := i18nLocaleFiles(baseDir)
+	if err != nil {
+		fatalErr(newExitErr(exitConfigError, err))
+		return
+	}
+	if len(files) == 0 {
+		fatalExit(exitUsage, "No locale files found in 'i18n' directory, e.g. 'i18n/messages.en'")
+		return
+	}
+
+	report := checkI18nKeys(files, uniqueI18nKeys(refs))
+	writeI18nCheckReport(os.Stdout, report)
+
+	if n := report.MissingCount(); n > 0 && *i18nCheckFailOnMissingFlag {
+		fatalExitf(exitI18nError, "%d message key(s) missing in locale files", n)
+	}
+}
+
+// checkI18nKeys method compares the keys of locale files and referenced
+// keys. Keys of multiple files of the same locale are merged.
+func checkI18nKeys(files []*i18nLocaleFile, refKeys []string) *i18nCheckReport {
+	localeKeys := map[string]map[string]bool{}
+	all := map[string]bool{}
+	for _, f := range files {
+		if localeKeys[f.Locale] == nil {
+			localeKeys[f.Locale] = map[string]bool{}
+		}
+		for _, k := range f.Keys {
+			localeKeys[f.Locale][k] = true
+			all[k] = true
+		}
+	}
+
+	referenced := map[string]bool{}
+	for _, k := range refKeys {
+		referenced[k] = true
+		all[k] = true
+	}
+
+	report := &i18nCheckReport{Keys: len(all), Missing: map[string][]string{}}
+	for locale := range localeKeys {
+		report.Locales = append(report.Locales, locale)
+	}
+	sort.Strings(report.Locales)
+
+	keys := make([]string, 0, len(all))
+	for k := range all {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if !referenced[k] {
+			report.Unused = append(report.Unused, k)
+		}
+		for _, locale := range report.Locales {
+			if localeKeys[locale][k] {
+				continue
+			}
+			if lang := localeLanguage(locale); lang != locale && localeKeys[lang][k] {
+				continue
+			}
+			report.Missing[locale] = append(report.Missing[locale], k)
+		}
+	}
+	return report
+}
+
+// MissingCount method returns the count of missing keys of all locales.
+func (r *i18nCheckReport) MissingCount() int {
+	count := 0
+	for _, keys := range r.Missing {
+		count += len(keys)
+	}
+	return count
+}
+
+func writeI18nCheckReport(w io.Writer, r *i18nCheckReport) {
+	if r.MissingCount() > 0 {
+		width := len("MISSING")
+		for _, keys := range r.Missing {
+			for _, k := range keys {
+				if len(k) > width {
+					width = len(k)
+				}
+			}
+		}
+		fmt.Fprintf(w, "%-*s  %s\n", width, "MISSING", "LOCALE")
+		for _, locale := range r.Locales {
+			for _, k := range r.Missing[locale] {
+				fmt.Fprintf(w, "%-*s  %s\n", width, k, locale)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	if len(r.Unused) > 0 {
+		fmt.Fprintln(w, "UNUSED")
+		for _, k := range r.Unused {
+			fmt.Fprintln(w, k)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "%d message keys in %d locales (%s): %d missing, %d unused\n",
+		r.Keys, len(r.Locales), strings.Join(r.Locales, ", "), r.MissingCount(), len(r.Unused))
+}
+
+func init() {
+	i18nCheckCmd.Run = i18nCheckRun
+	i18nCmd.Subcommands = append(i18nCmd.Subcommands, i18nCheckCmd)
+}
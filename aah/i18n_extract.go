@@ -0,0 +1,172 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"strings"
+
+	"aahframework.org/log.v0"
+)
+
+var (
+	i18nExtractCmdFlags   = flag.NewFlagSet("extract", flag.ContinueOnError)
+	i18nExtractLocaleFlag = i18nExtractCmdFlags.String("locale", "", "Comma separated locales to update, e.g: en,en-US. Default is all locales")
+	i18nExtractCmd        = &command{
+		Name:      "extract",
+		UsageLine: "aah i18n extract [-locale]",
+		Flags:     i18nExtractCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "extract message keys of views and Go sources into locale files",
+		Long: `
+Walks the views and Go sources of the application for translation calls (refer
+'aah help i18n') and adds the keys not defined in the locale files with empty
+value "", into their nearest existing section. Existing messages, comments
+and formatting of the locale files are kept as-is. Newly added keys are
+reported per locale file, fill in the translations afterwards.
+
+Keys that conflict with the existing message or section, e.g. 'label.title'
+is a message and 'label.title.short' is referenced, are reported and skipped.
+
+Use global flag '-dry-run' to get the report without modifying the files.
+
+Example(s):
+    aah i18n extract
+
+    aah i18n extract -locale=en,de
+`,
+	}
+)
+
+func i18nExtractRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	baseDir := appProject.BaseDir
+	refs, err := findI18nKeyRefs(baseDir)
+	if err != nil {
+		fatalErr(newExitErr(exitParseError, err))
+		return
+	}
+
+	files, err := i18nLocaleFiles(baseDir)
+	if err != nil {
+		fatalErr(newExitErr(exitConfigError, err))
+		return
+	}
+	files = filterI18nLocaleFiles(files, splitList(*i18nExtractLocaleFlag))
+	if len(files) == 0 {
+		fatalExit(exitUsage, "No locale files found in 'i18n' directory, e.g. 'i18n/messages.en'")
+		return
+	}
+
+	for _, r := range refs {
+		if !i18nKeyRegex.MatchString(r.Key) {
+			logWarnf("%s:%d: invalid message key '%s', skipped", relPath(baseDir, r.File), r.Line, r.Key)
+		}
+	}
+
+	total := 0
+	for _, f := range files {
+		src, err := ioutil.ReadFile(f.File)
+		if err != nil {
+			fatal(err)
+			return
+		}
+
+		result, added, conflicts, err := mergeI18nKeys(f.File, src, uniqueI18nKeys(refs))
+		if err != nil {
+			fatalErr(newExitErr(exitConfigError, err))
+			return
+		}
+		for _, key := range conflicts {
+			logWarnf("%s: key '%s' conflicts with existing message or section, skipped", relPath(baseDir, f.File), key)
+		}
+		if len(added) == 0 {
+			continue
+		}
+
+		if err = writeFile(f.File, result, permRWRR); err != nil {
+			fatal(err)
+			return
+		}
+		log.Infof("%s: %d new keys", relPath(baseDir, f.File), len(added))
+		for _, key := range added {
+			log.Infof("    %s", key)
+		}
+		total += len(added)
+	}
+	log.Infof("\n%d message keys referenced, %d new keys added into %d locale files", len(uniqueI18nKeys(refs)), total, len(files))
+}
+
+// mergeI18nKeys method adds the missing keys into the locale file source
+// with empty value. It returns the result source, added and conflicting
+// keys. Invalid keys are ignored.
+func mergeI18nKeys(file string, src []byte, keys []string) ([]byte, []string, []string, error) {
+	entries, err := parseConfString(file, string(src))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var added, conflicts []string
+	var values []*confValue
+	for _, key := range keys {
+		if !i18nKeyRegex.MatchString(key) {
+			continue
+		}
+		if e := findConfEntry(entries, key); e != nil {
+			if e.Kind == confSection {
+				conflicts = append(conflicts, key)
+			}
+			continue
+		}
+		if isI18nKeyConflict(entries, key) {
+			conflicts = append(conflicts, key)
+			continue
+		}
+		values = append(values, &confValue{Path: key, Value: `""`})
+		added = append(added, key)
+	}
+	if len(values) == 0 {
+		return src, nil, conflicts, nil
+	}
+
+	result, _, err := upsertConfValues(file, src, values)
+	return result, added, conflicts, err
+}
+
+// isI18nKeyConflict method reports whether the parent path of the key is
+// defined as message.
+func isI18nKeyConflict(entries []*confEntry, key string) bool {
+	parts := strings.Split(key, ".")
+	for i := 1; i < len(parts); i++ {
+		if e := findConfEntry(entries, strings.Join(parts[:i], ".")); e != nil && e.Kind != confSection {
+			return true
+		}
+	}
+	return false
+}
+
+func filterI18nLocaleFiles(files []*i18nLocaleFile, locales []string) []*i18nLocaleFile {
+	if len(locales) == 0 {
+		return files
+	}
+	var result []*i18nLocaleFile
+	for _, f := range files {
+		for _, l := range locales {
+			if strings.EqualFold(f.Locale, l) {
+				result = append(result, f)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func init() {
+	i18nExtractCmd.Run = i18nExtractRun
+	i18nCmd.Subcommands = append(i18nCmd.Subcommands, i18nExtractCmd)
+}
@@ -0,0 +1,129 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestI18nKeyRefs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-i18n")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "views", "pages", "app"), 0755))
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "app", "controllers"), 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "views", "pages", "app", "index.html"), []byte(`<title>{{ i18n . "label.pages.app.index.title" }}</title>
+<p>{{ i18n . "label.welcome" .User.Name }}</p>`), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "app", "controllers", "app.go"), []byte(`package controllers
+
+func (c *AppController) Index() {
+	c.Msg("label.welcome", "aah")
+	c.Msgl(c.Req.Locale, "msg.greet")
+	c.Msg(key)
+}
+`), 0644))
+
+	refs, err := findI18nKeyRefs(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(refs))
+	assert.Equal(t, "label.pages.app.index.title", refs[0].Key)
+	assert.Equal(t, 1, refs[0].Line)
+	assert.Equal(t, "label.welcome", refs[1].Key)
+	assert.Equal(t, filepath.Join(dir, "app", "controllers", "app.go"), refs[1].File)
+	assert.Equal(t, 4, refs[1].Line)
+	assert.Equal(t, "label.welcome", refs[2].Key)
+	assert.Equal(t, 2, refs[2].Line)
+	assert.Equal(t, "msg.greet", refs[3].Key)
+	assert.Equal(t, []string{"label.pages.app.index.title", "label.welcome", "msg.greet"}, uniqueI18nKeys(refs))
+
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "i18n"), 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "i18n", "messages.en"), []byte("label {\n  title = \"Home\"\n}\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "i18n", "messages.de-DE"), []byte("label {\n}\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "i18n", ".gitkeep"), []byte(""), 0644))
+	files, err := i18nLocaleFiles(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(files))
+	assert.Equal(t, "de-DE", files[0].Locale)
+	assert.Equal(t, "en", files[1].Locale)
+	assert.Equal(t, []string{"label.title"}, files[1].Keys)
+	assert.Equal(t, 1, len(filterI18nLocaleFiles(files, []string{"EN"})))
+}
+
+func TestI18nMergeKeys(t *testing.T) {
+	src := []byte(`# messages
+label {
+  # page title
+  title = "Home"
+
+  pages {
+    index = "Index"
+  }
+}
+`)
+	keys := []string{"label.title", "label.pages.about", "label.pages", "label.title.short", "msg.greet", "label.bad key"}
+	result, added, conflicts, err := mergeI18nKeys("messages.en", src, keys)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"label.pages.about", "msg.greet"}, added)
+	assert.Equal(t, []string{"label.pages", "label.title.short"}, conflicts)
+	assert.Equal(t, `# messages
+label {
+  # page title
+  title = "Home"
+
+  pages {
+    about = ""
+    index = "Index"
+  }
+}
+
+msg {
+  greet = ""
+}
+`, string(result))
+
+	result, added, _, err = mergeI18nKeys("messages.en", result, keys)
+	assert.Nil(t, err)
+	assert.Nil(t, added)
+
+	entries, err := parseConfString("messages.en", string(result))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"label.pages.about", "label.pages.index", "label.title", "msg.greet"}, i18nMessageKeys(entries))
+}
+
+func TestI18nCheck(t *testing.T) {
+	files := []*i18nLocaleFile{
+		{File: "i18n/messages.de", Locale: "de", Keys: []string{"label.title"}},
+		{File: "i18n/messages.en", Locale: "en", Keys: []string{"label.old", "label.title", "label.welcome"}},
+		{File: "i18n/errors.en", Locale: "en", Keys: []string{"error.notfound"}},
+		{File: "i18n/messages.en-US", Locale: "en-US", Keys: []string{"label.color"}},
+	}
+	report := checkI18nKeys(files, []string{"label.color", "label.title", "label.welcome", "msg.greet"})
+	assert.Equal(t, []string{"de", "en", "en-US"}, report.Locales)
+	assert.Equal(t, 6, report.Keys)
+	assert.Equal(t, []string{"error.notfound", "label.color", "label.old", "label.welcome", "msg.greet"}, report.Missing["de"])
+	assert.Equal(t, []string{"label.color", "msg.greet"}, report.Missing["en"])
+	assert.Equal(t, []string{"msg.greet"}, report.Missing["en-US"])
+	assert.Equal(t, []string{"error.notfound", "label.old"}, report.Unused)
+	assert.Equal(t, 8, report.MissingCount())
+
+	buf := &bytes.Buffer{}
+	writeI18nCheckReport(buf, checkI18nKeys(files[:2], []string{"label.title", "label.welcome"}))
+	assert.Equal(t, `MISSING        LOCALE
+label.old      de
+label.welcome  de
+
+UNUSED
+label.old
+
+3 message keys in 2 locales (de, en): 2 missing, 1 unused
+`, buf.String())
+}
@@ -0,0 +1,417 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+const initReportFile = "aah-init-report.md"
+
+var (
+	initCmdFlags            = flag.NewFlagSet("init", flag.ContinueOnError)
+	initImportPathFlag      = initCmdFlags.String("importPath", "", "Import path of existing Go project. Default is current directory")
+	initImportPathShortFlag = initCmdFlags.String("ip", "", "Import path of existing Go project. Default is current directory")
+	initTypeFlag            = initCmdFlags.String("type", "", "Application type 'web', 'api' or 'websocket'. Default is 'api'")
+	initTypeShortFlag       = initCmdFlags.String("t", "", "Application type 'web', 'api' or 'websocket'. Default is 'api'")
+	initForceFlag           = initCmdFlags.Bool("force", false, "Overwrite existing 'aah.project' and report file")
+	initCmd                 = &command{
+		Name:      "init",
+		UsageLine: "aah init [-ip | -importPath] [-t | -type] [-force]",
+		Flags:     initCmdFlags,
+		Hooks:     []hook{validateGopath},
+		Short:     "adopt an existing Go HTTP project into aah",
+		Long: `
+Inspects an existing Go HTTP project, generates the 'aah.project' file and
+writes the migration report '` + initReportFile + `' with proposed controllers
+layout and routes, i.e. what must move where.
+
+It detects 'net/http' style handlers 'func(http.ResponseWriter, *http.Request)'
+and route registrations such as 'http.HandleFunc("/path", handler)' or
+'r.Get("/path", handler)'. Project sources are not modified.
+
+Example(s) short and long flag:
+    aah init
+
+    aah init -ip=github.com/user/legacyapp -t=web
+
+    aah -dry-run init
+`,
+	}
+
+	initRouteMethods = map[string]string{
+		"Handle":     "GET",
+		"HandleFunc": "GET",
+		"Get":        "GET",
+		"Post":       "POST",
+		"Put":        "PUT",
+		"Patch":      "PATCH",
+		"Delete":     "DELETE",
+		"Head":       "HEAD",
+		"Options":    "OPTIONS",
+	}
+
+	initKnownRouters = map[string]string{
+		"github.com/gorilla/mux":              "gorilla/mux",
+		"github.com/julienschmidt/httprouter": "httprouter",
+		"github.com/go-chi/chi":               "chi",
+		"github.com/pressly/chi":              "chi",
+		"github.com/gin-gonic/gin":            "gin",
+		"github.com/labstack/echo":            "echo",
+	}
+)
+
+type (
+	initReport struct {
+		AppName     string
+		ImportPath  string
+		AppType     string
+		Routers     []string
+		Handlers    []*initHandler
+		Routes      []*initRoute
+		Controllers []*initController
+		Errors      []string
+	}
+
+	initHandler struct {
+		Name       string
+		File       string
+		Line       int
+		Controller string
+		Action     string
+	}
+
+	initRoute struct {
+		Path    string
+		Method  string
+		Handler string
+		File    string
+		Line    int
+		Target  string
+	}
+
+	initController struct {
+		Name    string
+		File    string
+		Actions []string
+	}
+)
+
+func initRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	importPath := firstNonEmpty(*initImportPathFlag, *initImportPathShortFlag, importPathRelwd())
+	appType := firstNonEmpty(*initTypeFlag, *initTypeShortFlag, typeAPI)
+	if !isValidAppType(appType) {
+		fatalExitf(exitUsage, "Unsupported application type '%s', choose either 'web', 'api' or 'websocket'", appType)
+		return
+	}
+
+	baseDir := filepath.Join(gosrcDir, filepath.FromSlash(importPath))
+	if strings.HasPrefix(importPath, "..") || !ess.IsFileExists(baseDir) {
+		fatalExitf(exitUsage, "Given import path '%s' does not exists within GOPATH", importPath)
+		return
+	}
+
+	projectFile := filepath.Join(baseDir, aahProjectIdentifier)
+	if ess.IsFileExists(projectFile) && !*initForceFlag {
+		fatalExitf(exitUsage, "'%s' is already an aah application, use '-force' to regenerate", importPath)
+		return
+	}
+
+	report := analyzeGoProject(baseDir)
+	report.AppName = filepath.Base(baseDir)
+	report.ImportPath = importPath
+	report.AppType = appType
+
+	tmplDir := appTemplateDir()
+	processFile(baseDir, tmplDir, filepath.Join(tmplDir, "aah.project.atmpl"), map[string]interface{}{
+		"AppName": report.AppName,
+		"AppType": appType,
+	})
+	log.Infof("Generated %s", projectFile)
+
+	if err := writeGenFile(filepath.Join(baseDir, initReportFile), initReportTemplate, report, *initForceFlag); err != nil {
+		fatal(err)
+	}
+
+	log.Infof("\nFound %d handler(s) and %d route(s), proposed %d controller(s)",
+		len(report.Handlers), len(report.Routes), len(report.Controllers))
+	for _, e := range report.Errors {
+		logWarnf("Skipped %s", e)
+	}
+	log.Infof("Review '%s' to move your project into aah application structure.\n", initReportFile)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// analyzeGoProject method inspects the Go source files of given directory
+// for HTTP handlers and route registrations.
+func analyzeGoProject(baseDir string) *initReport {
+	report := &initReport{}
+	_ = ess.Walk(baseDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			report.Errors = append(report.Errors, err.Error())
+			return nil
+		}
+
+		name := info.Name()
+		if info.IsDir() {
+			if path != baseDir && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(baseDir, path)
+		if err = analyzeGoSource(report, filepath.ToSlash(rel), nil, path); err != nil {
+			report.Errors = append(report.Errors, err.Error())
+		}
+		return nil
+	})
+
+	report.link()
+	return report
+}
+
+// analyzeGoSource method inspects single Go source, src is read from path
+// if it's nil.
+func analyzeGoSource(report *initReport, relFile string, src interface{}, path string) error {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, src, 0)
+	if err != nil {
+		return err
+	}
+
+	httpAlias := ""
+	for _, imp := range f.Imports {
+		p, _ := strconv.Unquote(imp.Path.Value)
+		if p == "net/http" {
+			httpAlias = "http"
+			if imp.Name != nil {
+				httpAlias = imp.Name.Name
+			}
+		}
+		for prefix, router := range initKnownRouters {
+			if p == prefix || strings.HasPrefix(p, prefix+"/") {
+				if !isStringInSlice(report.Routers, router) {
+					report.Routers = append(report.Routers, router)
+				}
+			}
+		}
+	}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.FuncDecl:
+			if x.Recv == nil && !ess.IsStrEmpty(httpAlias) && isHTTPHandlerFunc(x.Type, httpAlias) {
+				report.Handlers = append(report.Handlers, &initHandler{
+					Name: x.Name.Name,
+					File: relFile,
+					Line: fset.Position(x.Pos()).Line,
+				})
+			}
+		case *ast.CallExpr:
+			if r := parseRouteCall(x); r != nil {
+				r.File = relFile
+				r.Line = fset.Position(x.Pos()).Line
+				report.Routes = append(report.Routes, r)
+			}
+		}
+		return true
+	})
+
+	return nil
+}
+
+// isHTTPHandlerFunc method reports whether the func signature is
+// 'func(http.ResponseWriter, *http.Request)'.
+func isHTTPHandlerFunc(ft *ast.FuncType, httpAlias string) bool {
+	var params []ast.Expr
+	for _, p := range ft.Params.List {
+		n := len(p.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			params = append(params, p.Type)
+		}
+	}
+
+	if len(params) != 2 || (ft.Results != nil && len(ft.Results.List) > 0) {
+		return false
+	}
+
+	star, ok := params[1].(*ast.StarExpr)
+	return ok && isSelector(params[0], httpAlias, "ResponseWriter") &&
+		isSelector(star.X, httpAlias, "Request")
+}
+
+func isSelector(expr ast.Expr, pkg, name string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && ident.Name == pkg && sel.Sel.Name == name
+}
+
+// parseRouteCall method returns the route if call expression is route
+// registration such as 'mux.HandleFunc("/path", handler)'.
+func parseRouteCall(call *ast.CallExpr) *initRoute {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || len(call.Args) < 2 {
+		return nil
+	}
+
+	name := sel.Sel.Name
+	if name == strings.ToUpper(name) {
+		// for e.g.: gin and echo 'r.GET("/path", handler)'
+		name = toExported(strings.ToLower(name))
+	}
+
+	method, found := initRouteMethods[name]
+	if !found {
+		return nil
+	}
+
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return nil
+	}
+
+	path, err := strconv.Unquote(lit.Value)
+	if err != nil || !strings.HasPrefix(path, "/") {
+		return nil
+	}
+
+	return &initRoute{
+		Path:    path,
+		Method:  method,
+		Handler: handlerName(call.Args[len(call.Args)-1]),
+	}
+}
+
+func handlerName(expr ast.Expr) string {
+	switch x := expr.(type) {
+	case *ast.Ident:
+		return x.Name
+	case *ast.SelectorExpr:
+		return x.Sel.Name
+	case *ast.CallExpr:
+		// for e.g.: http.HandlerFunc(handler)
+		if len(x.Args) == 1 {
+			return handlerName(x.Args[0])
+		}
+	}
+	return ""
+}
+
+// link method proposes the controllers layout, handlers of a source file
+// become actions of one controller, then routes are mapped to it.
+func (r *initReport) link() {
+	controllers := map[string]*initController{}
+	for _, h := range r.Handlers {
+		base := strings.TrimSuffix(filepath.Base(h.File), ".go")
+		if base == "main" {
+			base = "app"
+		}
+
+		h.Controller = toExported(base)
+		h.Action = toExported(strings.TrimSuffix(strings.TrimSuffix(h.Name, "Handler"), "handler"))
+		if ess.IsStrEmpty(h.Action) {
+			h.Action = toExported(h.Name)
+		}
+
+		c, found := controllers[h.Controller]
+		if !found {
+			c = &initController{Name: h.Controller, File: "app/controllers/" + toSnakeCase(h.Controller) + ".go"}
+			controllers[h.Controller] = c
+			r.Controllers = append(r.Controllers, c)
+		}
+		c.Actions = append(c.Actions, h.Action)
+	}
+
+	for _, rt := range r.Routes {
+		rt.Target = "manual"
+		for _, h := range r.Handlers {
+			if h.Name == rt.Handler {
+				rt.Target = h.Controller + "." + h.Action
+				break
+			}
+		}
+	}
+
+	sort.Slice(r.Controllers, func(i, j int) bool { return r.Controllers[i].Name < r.Controllers[j].Name })
+	sort.Strings(r.Routers)
+}
+
+func init() {
+	initCmd.Run = initRun
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Init Templates
+//___________________________________
+
+const initReportTemplate = `# aah migration report - {{ .AppName }}
+
+Import path: ` + "`{{ .ImportPath }}`" + `, application type: ` + "`{{ .AppType }}`" + `
+{{ if .Routers }}
+Detected routers: {{ range $i, $r := .Routers }}{{ if $i }}, {{ end }}{{ $r }}{{ end }}. Their routes
+are replaced by 'config/routes.conf'.
+{{ end }}
+## Proposed controllers
+
+Each handler becomes an action of controller that embeds ` + "`*aah.Context`" + `,
+use ` + "`ctx.Req.Raw`" + ` and ` + "`ctx.Res`" + ` until handler is rewritten with ` + "`ctx.Reply()`" + `.
+
+| Controller | File | Actions |
+|------------|------|---------|
+{{ range .Controllers }}| {{ .Name }} | {{ .File }} | {{ range $i, $a := .Actions }}{{ if $i }}, {{ end }}{{ $a }}{{ end }} |
+{{ end }}
+## What moves where
+
+| Handler | Source | Move to |
+|---------|--------|---------|
+{{ range .Handlers }}| {{ .Name }} | {{ .File }}:{{ .Line }} | {{ .Controller }}.{{ .Action }} |
+{{ end }}
+## Routes
+
+Add these routes into 'config/routes.conf', 'manual' routes need your attention.
+
+| Method | Path | Handler | Source | Route target |
+|--------|------|---------|--------|--------------|
+{{ range .Routes }}| {{ .Method }} | {{ .Path }} | {{ .Handler }} | {{ .File }}:{{ .Line }} | {{ .Target }} |
+{{ end }}
+## Next steps
+
+1. Create 'config/aah.conf' and 'config/routes.conf', refer to 'aah new' generated application.
+2. Move handlers into proposed controllers under 'app/controllers'.
+3. Remove 'http.ListenAndServe' from 'main' package, aah generates the main and server.
+4. Run 'aah run' and verify the routes.
+{{ if .Errors }}
+## Skipped files
+{{ range .Errors }}
+- {{ . }}{{ end }}
+{{ end }}`
@@ -0,0 +1,56 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestInitAnalyzeGoSource(t *testing.T) {
+	src := `package main
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func listUsersHandler(w http.ResponseWriter, r *http.Request) {}
+
+func createUser(w http.ResponseWriter, r *http.Request) {}
+
+func helper(w http.ResponseWriter) {}
+
+func main() {
+	r := mux.NewRouter()
+	r.HandleFunc("/users", listUsersHandler)
+	r.Post("/users", http.HandlerFunc(createUser))
+	r.GET("/health", func(w http.ResponseWriter, r *http.Request) {})
+	_ = http.ListenAndServe(":8080", r)
+}
+`
+
+	report := &initReport{}
+	err := analyzeGoSource(report, "users.go", src, "users.go")
+	assert.Nil(t, err)
+	report.link()
+
+	assert.Equal(t, []string{"gorilla/mux"}, report.Routers)
+	assert.Equal(t, 2, len(report.Handlers))
+	assert.Equal(t, "Users", report.Handlers[0].Controller)
+	assert.Equal(t, "ListUsers", report.Handlers[0].Action)
+
+	assert.Equal(t, 1, len(report.Controllers))
+	assert.Equal(t, "app/controllers/users.go", report.Controllers[0].File)
+
+	assert.Equal(t, 3, len(report.Routes))
+	assert.Equal(t, "Users.ListUsers", report.Routes[0].Target)
+	assert.Equal(t, "POST", report.Routes[1].Method)
+	assert.Equal(t, "Users.CreateUser", report.Routes[1].Target)
+	assert.Equal(t, "GET", report.Routes[2].Method)
+	assert.Equal(t, "manual", report.Routes[2].Target)
+}
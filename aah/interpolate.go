@@ -0,0 +1,210 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+)
+
+// envLookup is the environment variable lookup of interpolation.
+type envLookup func(name string) (string, bool)
+
+// interpolateEnv method replaces the environment variable placeholders of
+// the config content, same as shell parameter expansion:
+//
+//	${VAR}            value of VAR, empty if VAR is not set
+//	${VAR:-default}   default if VAR is not set or empty
+//	${VAR-default}    default if VAR is not set
+//	${VAR:?message}   error with message if VAR is not set or empty
+//	$${VAR}           literal '${VAR}'
+//
+// Comment lines are not interpolated. It returns the content and the names
+// of referenced variables which are not set and don't have default value.
+func interpolateEnv(content string, lookup envLookup) (string, []string, error) {
+	buf := &bytes.Buffer{}
+	var missing []string
+	line := 1
+	for i := 0; i < len(content); i++ {
+		// comment lines are kept as-is
+		if i == 0 || content[i-1] == '\n' {
+			if rest := strings.TrimLeft(content[i:], " \t"); strings.HasPrefix(rest, "#") || strings.HasPrefix(rest, "//") {
+				end := strings.IndexByte(content[i:], '\n')
+				if end < 0 {
+					buf.WriteString(content[i:])
+					break
+				}
+				buf.WriteString(content[i : i+end])
+				i += end - 1
+				continue
+			}
+		}
+
+		c := content[i]
+		if c == '\n' {
+			line++
+		}
+		if c != '$' || i+1 >= len(content) {
+			buf.WriteByte(c)
+			continue
+		}
+
+		if strings.HasPrefix(content[i:], "$${") {
+			buf.WriteString("${")
+			i += 2
+			continue
+		}
+		if content[i+1] != '{' {
+			buf.WriteByte(c)
+			continue
+		}
+
+		end := strings.IndexAny(content[i+2:], "}\n")
+		if end < 0 || content[i+2+end] != '}' {
+			return "", nil, fmt.Errorf("line %d: placeholder '${' is not closed", line)
+		}
+		expr := content[i+2 : i+2+end]
+		i += 2 + end
+
+		value, ok, err := expandEnvExpr(expr, lookup)
+		if err != nil {
+			return "", nil, fmt.Errorf("line %d: %s", line, err)
+		}
+		if !ok {
+			missing = append(missing, expr)
+		}
+		buf.WriteString(value)
+	}
+	return buf.String(), missing, nil
+}
+
+// loadProjectConfig method loads the config file with environment variable
+// placeholders, the variables are looked up from the environment and then
+// '.env' file of the base directory. Config file without placeholders is
+// loaded as-is, so its relative includes keep working.
+func loadProjectConfig(file, baseDir string) (*config.Config, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Contains(b, []byte("${")) {
+		return config.LoadFile(file)
+	}
+
+	content, err := interpolateConfig(file, string(b), baseDir)
+	if err != nil {
+		return nil, err
+	}
+	return config.ParseString(content)
+}
+
+// interpolateConfigFile method writes the interpolated config file into the
+// directory and returns its path, the file is returned as-is if it has no
+// placeholders. It's used for external config passed to the application.
+func interpolateConfigFile(file, baseDir, dstDir string) (string, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	if !bytes.Contains(b, []byte("${")) {
+		return file, nil
+	}
+
+	content, err := interpolateConfig(file, string(b), baseDir)
+	if err != nil {
+		return "", err
+	}
+	if err = mkDirAll(dstDir, permRWXRXRX); err != nil {
+		return "", err
+	}
+
+	// interpolated values may have secrets, so it's readable only by the owner
+	dstFile := filepath.Join(dstDir, filepath.Base(file))
+	if err = writeFile(dstFile, []byte(content), 0600); err != nil {
+		return "", err
+	}
+	return dstFile, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func interpolateConfig(file, content, baseDir string) (string, error) {
+	content, missing, err := interpolateEnv(content, projectEnvLookup(baseDir))
+	if err != nil {
+		return "", fmt.Errorf("%s: %s", file, err)
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		logWarnf("%s: environment variable(s) '%s' are not set, replaced with empty value",
+			filepath.Base(file), strings.Join(missing, "', '"))
+	}
+	return content, nil
+}
+
+// projectEnvLookup method returns the lookup of environment variables and
+// then '.env' file of the base directory.
+func projectEnvLookup(baseDir string) envLookup {
+	dotEnv := map[string]string{}
+	if b, err := ioutil.ReadFile(filepath.Join(baseDir, ".env")); err == nil {
+		if vars, err := parseEnvFile(b); err == nil {
+			dotEnv = vars
+		}
+	}
+
+	return func(name string) (string, bool) {
+		if v, found := os.LookupEnv(name); found {
+			return v, true
+		}
+		v, found := dotEnv[name]
+		return v, found
+	}
+}
+
+// expandEnvExpr method expands the placeholder expression, ok is false if
+// variable is not set and there is no default value.
+func expandEnvExpr(expr string, lookup envLookup) (string, bool, error) {
+	name, op, arg := expr, "", ""
+	if idx := strings.IndexAny(expr, ":-"); idx >= 0 {
+		name, op = expr[:idx], expr[idx:]
+		switch {
+		case strings.HasPrefix(op, ":-"), strings.HasPrefix(op, ":?"):
+			op, arg = op[:2], op[2:]
+		case strings.HasPrefix(op, "-"):
+			op, arg = op[:1], op[1:]
+		default:
+			return "", false, fmt.Errorf("invalid placeholder '${%s}'", expr)
+		}
+	}
+	if !envNameRegex.MatchString(name) {
+		return "", false, fmt.Errorf("invalid variable name in placeholder '${%s}'", expr)
+	}
+
+	value, found := lookup(name)
+	switch op {
+	case ":-":
+		if ess.IsStrEmpty(value) {
+			return arg, true, nil
+		}
+	case "-":
+		if !found {
+			return arg, true, nil
+		}
+	case ":?":
+		if ess.IsStrEmpty(value) {
+			return "", false, fmt.Errorf("%s: %s", name, firstNonEmpty(arg, "is not set"))
+		}
+	}
+	return value, found, nil
+}
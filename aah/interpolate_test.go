@@ -0,0 +1,53 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestInterpolateEnv(t *testing.T) {
+	vars := map[string]string{"DB_HOST": "db.example.com", "EMPTY": ""}
+	lookup := func(name string) (string, bool) {
+		v, found := vars[name]
+		return v, found
+	}
+
+	content, missing, err := interpolateEnv(`# ${NOT_INTERPOLATED}
+host = "${DB_HOST}"
+port = ${DB_PORT:-5432}
+user = "${EMPTY:-aah}"
+pass = "${EMPTY-none}"
+name = "${DB_NAME}"
+tmpl = "$${DB_HOST} $ref"
+`, lookup)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"DB_NAME"}, missing)
+	assert.Equal(t, `# ${NOT_INTERPOLATED}
+host = "db.example.com"
+port = 5432
+user = "aah"
+pass = ""
+name = ""
+tmpl = "${DB_HOST} $ref"
+`, content)
+
+	_, _, err = interpolateEnv("\nkey = \"${DB_PASSWORD:?required for prod}\"", lookup)
+	assert.Equal(t, "line 2: DB_PASSWORD: required for prod", err.Error())
+
+	_, _, err = interpolateEnv(`key = "${DB_HOST"`, lookup)
+	assert.Equal(t, "line 1: placeholder '${' is not closed", err.Error())
+
+	_, _, err = interpolateEnv(`key = "${DB HOST}"`, lookup)
+	assert.NotNil(t, err)
+
+	entries, err := parseConfString("aah.project", "build {\n  port = ${PORT:-8080}, name = \"${APP:-x}\"\n}\n")
+	assert.Nil(t, err)
+	assert.Equal(t, "${PORT:-8080}", entries[1].Value)
+	assert.Equal(t, confRef, entries[1].Kind)
+	assert.Equal(t, "build.name", entries[2].Path)
+}
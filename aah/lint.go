@@ -0,0 +1,160 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+var (
+	lintCmdFlags   = flag.NewFlagSet("lint", flag.ContinueOnError)
+	lintStrictFlag = lintCmdFlags.Bool("strict", false, "Treat config warnings as errors")
+	lintFormatFlag = lintCmdFlags.String("format", "text", "Report format 'text' or 'sarif'")
+	lintOutputFlag = lintCmdFlags.String("o", "", "Report file path. Default is stdout")
+	lintCmd        = &command{
+		Name:      "lint",
+		UsageLine: "aah lint [-strict] [-format] [-o]",
+		Flags:     lintCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "vet application sources and lint config files",
+		Long: `
+Runs 'go vet' on the application packages and 'aah config lint' on the
+project and config files, findings are reported with file positions:
+
+    app/controllers/app.go:42:3: error: vet: fmt.Sprintf format %d has arg name of wrong type string
+    config/aah.conf:26:3: error: 'server.port' must be int or string, found bool
+
+Report format 'sarif' is SARIF 2.1.0 JSON with a run for each tool, upload it
+to GitHub or GitLab code scanning to show the findings inline.
+
+Exit code is 'lint' (refer 'aah help exit-codes') if any vet finding or
+config error is found, or any config warning with '-strict'.
+
+Example(s):
+    aah lint
+
+    aah lint -format=sarif -o=lint.sarif
+`,
+	}
+
+	vetLineRegex = regexp.MustCompile(`^(.+?\.go):(\d+)(?::(\d+))?: (.+)$`)
+)
+
+func lintRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	format := *lintFormatFlag
+	if format != "text" && format != "sarif" {
+		fatalExitf(exitUsage, "Unsupported report format '%s', choose 'text' or 'sarif'", format)
+		return
+	}
+
+	vetProblems, err := goVet(appProject.BaseDir, appProject.ImportPath)
+	if err != nil {
+		fatal(err)
+		return
+	}
+	confProblems := lintAppConfigs(appProject.BaseDir)
+
+	buf := &bytes.Buffer{}
+	vets, _ := printConfProblems(buf, appProject.BaseDir, vetProblems)
+	errs, warns := printConfProblems(buf, appProject.BaseDir, confProblems)
+	if format == "sarif" {
+		buf.Reset()
+		report := vetProblemsSARIF(appProject.BaseDir, vetProblems).Merge(confProblemsSARIF(appProject.BaseDir, confProblems))
+		if err = report.Write(buf); err != nil {
+			fatal(err)
+			return
+		}
+	}
+	if file := getNonEmptyAbsPath(*lintOutputFlag, ""); !ess.IsStrEmpty(file) {
+		if err = writeFile(file, buf.Bytes(), permRWRR); err != nil {
+			fatal(err)
+			return
+		}
+		recordArtifact(file)
+		log.Infof("Lint report written to %s", file)
+	} else {
+		_, _ = os.Stdout.Write(buf.Bytes())
+	}
+
+	if vets > 0 || errs > 0 || (*lintStrictFlag && warns > 0) {
+		fatalExitf(exitLintError, "Lint failed, %d vet finding(s), %d config error(s), %d config warning(s)", vets, errs, warns)
+		return
+	}
+	if warns > 0 {
+		log.Infof("%d config warning(s)", warns)
+		return
+	}
+	log.Info("No problems found")
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// goVet method runs 'go vet' on the application packages and returns its
+// findings. Vet doesn't change anything, so it runs in dry-run mode too.
+//
+//	go vet aah-app/import/path/app/...
+func goVet(baseDir, importPath string) ([]*confProblem, error) {
+	cmd := exec.Command(gocmd, "vet", path.Join(importPath, "app", "..."))
+	cmd.Dir = baseDir
+	log.Debug("Executing ", strings.Join(cmd.Args, " "))
+	output, err := cmd.CombinedOutput()
+	problems := parseVetOutput(baseDir, output)
+	if err != nil && len(problems) == 0 {
+		return nil, errors.New("go vet: " + strings.TrimSpace(string(output)))
+	}
+	return problems, nil
+}
+
+// parseVetOutput method returns the findings of 'go vet' output, relative
+// file path is resolved from the base directory.
+func parseVetOutput(baseDir string, output []byte) []*confProblem {
+	var problems []*confProblem
+	for _, line := range strings.Split(string(output), "\n") {
+		m := vetLineRegex.FindStringSubmatch(strings.TrimPrefix(strings.TrimSpace(line), "vet: "))
+		if m == nil {
+			continue
+		}
+		file := filepath.FromSlash(m[1])
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(baseDir, file)
+		}
+		ln, _ := strconv.Atoi(m[2])
+		col, _ := strconv.Atoi(m[3])
+		problems = append(problems, &confProblem{File: file, Line: ln, Col: col,
+			Severity: confSeverityError, Msg: "vet: " + m[4]})
+	}
+	return problems
+}
+
+// vetProblemsSARIF method returns the SARIF report of the vet findings.
+func vetProblemsSARIF(baseDir string, problems []*confProblem) *sarifLog {
+	report := newSARIFLog("lint", baseDir)
+	for _, p := range problems {
+		report.Add("go-vet", "Suspicious construct reported by 'go vet'", sarifLevelError,
+			strings.TrimPrefix(p.Msg, "vet: "), p.File, p.Line, p.Col)
+	}
+	return report
+}
+
+func init() {
+	lintCmd.Run = lintRun
+}
@@ -0,0 +1,45 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestLintVetOutput(t *testing.T) {
+	baseDir := filepath.FromSlash("/go/src/example.com/myapp")
+	output := []byte(`# example.com/myapp/app/controllers
+app/controllers/app.go:42:3: fmt.Sprintf format %d has arg name of wrong type string
+vet: app/models/user.go:10: unreachable code
+`)
+
+	problems := parseVetOutput(baseDir, output)
+	assert.Equal(t, 2, len(problems))
+	assert.Equal(t, filepath.Join(baseDir, "app", "controllers", "app.go"), problems[0].File)
+	assert.Equal(t, 42, problems[0].Line)
+	assert.Equal(t, 3, problems[0].Col)
+	assert.Equal(t, "vet: fmt.Sprintf format %d has arg name of wrong type string", problems[0].Msg)
+	assert.Equal(t, 0, problems[1].Col)
+
+	buf := &bytes.Buffer{}
+	errs, _ := printConfProblems(buf, baseDir, problems)
+	assert.Equal(t, 2, errs)
+	assert.True(t, strings.HasPrefix(buf.String(), "app/controllers/app.go:42:3: error: vet: fmt.Sprintf"))
+
+	report := vetProblemsSARIF(baseDir, problems).Merge(confProblemsSARIF(baseDir, []*confProblem{
+		{File: filepath.Join(baseDir, "config", "aah.conf"), Line: 26, Col: 3, Severity: confSeverityError, Msg: "'server.port' must be int"},
+	}))
+	assert.Equal(t, 2, len(report.Runs))
+	assert.Equal(t, "aah lint", report.Runs[0].Tool.Driver.Name)
+	assert.Equal(t, "go-vet", report.Runs[0].Results[0].RuleID)
+	assert.Equal(t, "app/models/user.go", report.Runs[0].Results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, "aah config lint", report.Runs[1].Tool.Driver.Name)
+	assert.Equal(t, "config-error", report.Runs[1].Results[0].RuleID)
+}
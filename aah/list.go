@@ -19,6 +19,7 @@ var (
 	listCmd = &command{
 		Name:      "list",
 		UsageLine: "aah list",
+		Hooks:     []hook{validateGopath},
 		Short:     "list all aah projects in GOPATH",
 		Long: `
 		List command allows you to view all projects that are making use of aah in your GOPATH.
@@ -0,0 +1,140 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/log.v0"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	livereloadEnvURL      = "AAH_LIVERELOAD_URL"
+	livereloadDefaultPort = 35729
+	livereloadScriptPath  = "/livereload.js"
+	livereloadWSPath      = "/livereload"
+)
+
+// liveReload serves the livereload script and websocket endpoint during
+// 'aah run'. Browser gets refreshed after every successful rebuild.
+type liveReload struct {
+	port    int
+	mu      sync.Mutex
+	clients map[*websocket.Conn]bool
+}
+
+// newLiveReload method returns the livereload server if it's enabled in
+// 'aah.project' and active profile is 'dev', otherwise nil.
+//
+//	livereload {
+//	  enable = true
+//	  port = 35729
+//	}
+func newLiveReload(cfg *config.Config, profile string) *liveReload {
+	if !cfg.BoolDefault("livereload.enable", true) {
+		return nil
+	}
+
+	if profile != "" && profile != "dev" {
+		log.Debugf("Live reload is disabled for profile '%s'", profile)
+		return nil
+	}
+
+	return &liveReload{
+		port:    cfg.IntDefault("livereload.port", livereloadDefaultPort),
+		clients: map[*websocket.Conn]bool{},
+	}
+}
+
+// Start method starts the livereload server, it's non-blocking.
+func (lr *liveReload) Start() error {
+	l, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", lr.port))
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(livereloadScriptPath, lr.serveScript)
+	mux.Handle(livereloadWSPath, websocket.Handler(lr.serveWebSocket))
+	go func() {
+		if err := http.Serve(l, mux); err != nil {
+			log.Errorf("Live reload server stopped: %s", err)
+		}
+	}()
+	return nil
+}
+
+// ScriptURL method returns the livereload script URL, supplied to the
+// application via environment variable 'AAH_LIVERELOAD_URL'.
+func (lr *liveReload) ScriptURL() string {
+	return fmt.Sprintf("http://localhost:%d%s", lr.port, livereloadScriptPath)
+}
+
+// Reload method notifies the connected browsers to refresh the page.
+func (lr *liveReload) Reload() {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	for c := range lr.clients {
+		if err := websocket.Message.Send(c, "reload"); err != nil {
+			delete(lr.clients, c)
+			_ = c.Close()
+		}
+	}
+	log.Debugf("Live reload notified %d browser(s)", len(lr.clients))
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func (lr *liveReload) serveScript(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/javascript; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	wsURL := "ws://" + r.Host + livereloadWSPath
+	_, _ = fmt.Fprint(w, strings.Replace(livereloadScript, "{{WS_URL}}", wsURL, 1))
+}
+
+func (lr *liveReload) serveWebSocket(c *websocket.Conn) {
+	lr.mu.Lock()
+	lr.clients[c] = true
+	lr.mu.Unlock()
+
+	// wait until browser closes the connection
+	var msg string
+	for websocket.Message.Receive(c, &msg) == nil {
+	}
+
+	lr.mu.Lock()
+	delete(lr.clients, c)
+	lr.mu.Unlock()
+	_ = c.Close()
+}
+
+// livereloadScript waits for the restarted application to respond before
+// refreshing the page and reconnects if 'aah run' is restarted.
+const livereloadScript = `(function() {
+  function waitAndReload(attempt) {
+    fetch(window.location.href, {method: 'HEAD', cache: 'no-store'}).then(function() {
+      window.location.reload();
+    }).catch(function() {
+      if (attempt < 40) { setTimeout(function() { waitAndReload(attempt + 1); }, 250); }
+    });
+  }
+
+  function connect() {
+    var ws = new WebSocket('{{WS_URL}}');
+    ws.onmessage = function(e) { if (e.data === 'reload') { waitAndReload(0); } };
+    ws.onclose = function() { setTimeout(connect, 2000); };
+  }
+
+  connect();
+})();
+`
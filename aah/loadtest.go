@@ -0,0 +1,300 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+var (
+	loadtestCmdFlags       = flag.NewFlagSet("loadtest", flag.ContinueOnError)
+	loadtestURLFlag        = loadtestCmdFlags.String("url", "/", "Request path of the local application or absolute URL")
+	loadtestMethodFlag     = loadtestCmdFlags.String("method", "GET", "HTTP method of the request")
+	loadtestDurationFlag   = loadtestCmdFlags.Duration("duration", 30*time.Second, "Load test duration")
+	loadtestConcurrentFlag = loadtestCmdFlags.Int("concurrency", 10, "No. of concurrent connections")
+	loadtestTimeoutFlag    = loadtestCmdFlags.Duration("timeout", 10*time.Second, "Request timeout")
+	loadtestCmd            = &command{
+		Name:      "loadtest",
+		UsageLine: "aah loadtest [-url] [-method] [-duration] [-concurrency] [-timeout]",
+		Flags:     loadtestCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "run HTTP load test against running application",
+		Long: `
+Drives HTTP load against the locally running application (refer 'aah run')
+and reports the throughput, error rate, status codes and latency
+percentiles, so basic capacity checks don't require external tools.
+
+Request path is sent to 'http://localhost:<server.port>' of application
+config, absolute URL is used as-is. Requests with transport error or '5xx'
+status are counted as errors. Press Ctrl+C to stop early and report.
+
+Example(s):
+    aah loadtest -url=/api/users -duration=30s -concurrency=50
+
+    aah loadtest -url=https://staging.example.com/ -duration=1m
+`,
+	}
+)
+
+type (
+	// loadTester sends the requests from concurrent workers until the
+	// duration elapses or stopped.
+	loadTester struct {
+		URL         string
+		Method      string
+		Concurrency int
+		Duration    time.Duration
+		client      *http.Client
+	}
+
+	// loadTestResult is the outcome of the load test.
+	loadTestResult struct {
+		Requests  int
+		Errors    int
+		Elapsed   time.Duration
+		Statuses  map[int]int
+		Latencies []time.Duration
+		errMsgs   map[string]int
+	}
+)
+
+func loadtestRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	target := *loadtestURLFlag
+	if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+		port := aah.AppConfig().StringDefault("server.port", "8080")
+		target = fmt.Sprintf("http://localhost:%s/%s", port, strings.TrimPrefix(target, "/"))
+	}
+
+	if *loadtestConcurrentFlag <= 0 || *loadtestDurationFlag <= 0 {
+		fatalExitf(exitUsage, "Flags '-concurrency' and '-duration' must be greater than zero")
+		return
+	}
+
+	lt := &loadTester{
+		URL:         target,
+		Method:      strings.ToUpper(*loadtestMethodFlag),
+		Concurrency: *loadtestConcurrentFlag,
+		Duration:    *loadtestDurationFlag,
+		client: &http.Client{
+			Timeout:   *loadtestTimeoutFlag,
+			Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, MaxIdleConnsPerHost: *loadtestConcurrentFlag},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+
+	if isDryRun() {
+		logDryRun("send %s %s with %d connections for %s", lt.Method, lt.URL, lt.Concurrency, lt.Duration)
+		return
+	}
+
+	// fail fast, if application is not reachable
+	resp, err := lt.client.Get(lt.URL)
+	if err != nil {
+		fatalExitf(exitError, "Unable to reach %s, is the application running? %s", lt.URL, err)
+		return
+	}
+	ess.CloseQuietly(resp.Body)
+
+	stop := make(chan struct{})
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc, os.Interrupt)
+	defer signal.Stop(sc)
+	go func() {
+		if _, ok := <-sc; ok {
+			close(stop)
+		}
+	}()
+
+	log.Infof("Load testing %s %s with %d connections for %s", lt.Method, lt.URL, lt.Concurrency, lt.Duration)
+	endStage := startStage("loadtest")
+	result := lt.Run(stop)
+	endStage()
+
+	result.Print(os.Stdout)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// loadTester methods
+//___________________________________
+
+// Run method runs the load test until the duration elapses or stop is
+// closed.
+func (lt *loadTester) Run(stop <-chan struct{}) *loadTestResult {
+	results := make([]*loadTestResult, lt.Concurrency)
+	deadline := time.Now().Add(lt.Duration)
+	start := time.Now()
+
+	wg := sync.WaitGroup{}
+	for i := range results {
+		results[i] = newLoadTestResult()
+		wg.Add(1)
+		go func(r *loadTestResult) {
+			defer wg.Done()
+			for time.Now().Before(deadline) {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				lt.send(r)
+			}
+		}(results[i])
+	}
+	wg.Wait()
+
+	total := newLoadTestResult()
+	total.Elapsed = time.Since(start)
+	for _, r := range results {
+		total.Requests += r.Requests
+		total.Errors += r.Errors
+		total.Latencies = append(total.Latencies, r.Latencies...)
+		for code, n := range r.Statuses {
+			total.Statuses[code] += n
+		}
+		for msg, n := range r.errMsgs {
+			total.errMsgs[msg] += n
+		}
+	}
+	sort.Slice(total.Latencies, func(i, j int) bool { return total.Latencies[i] < total.Latencies[j] })
+	return total
+}
+
+func (lt *loadTester) send(r *loadTestResult) {
+	req, err := http.NewRequest(lt.Method, lt.URL, nil)
+	if err != nil {
+		r.addError(err)
+		return
+	}
+
+	start := time.Now()
+	resp, err := lt.client.Do(req)
+	if err != nil {
+		r.addError(err)
+		return
+	}
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	ess.CloseQuietly(resp.Body)
+
+	r.Requests++
+	r.Latencies = append(r.Latencies, time.Since(start))
+	r.Statuses[resp.StatusCode]++
+	if resp.StatusCode >= http.StatusInternalServerError {
+		r.Errors++
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// loadTestResult methods
+//___________________________________
+
+func newLoadTestResult() *loadTestResult {
+	return &loadTestResult{Statuses: map[int]int{}, errMsgs: map[string]int{}}
+}
+
+func (r *loadTestResult) addError(err error) {
+	r.Requests++
+	r.Errors++
+	r.errMsgs[err.Error()]++
+}
+
+// Percentile method returns the latency of given percentile, latencies must
+// be sorted.
+func (r *loadTestResult) Percentile(p float64) time.Duration {
+	if len(r.Latencies) == 0 {
+		return 0
+	}
+	idx := int(float64(len(r.Latencies))*p/100+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(r.Latencies) {
+		idx = len(r.Latencies) - 1
+	}
+	return r.Latencies[idx]
+}
+
+// Mean method returns the average latency.
+func (r *loadTestResult) Mean() time.Duration {
+	if len(r.Latencies) == 0 {
+		return 0
+	}
+	var sum time.Duration
+	for _, l := range r.Latencies {
+		sum += l
+	}
+	return sum / time.Duration(len(r.Latencies))
+}
+
+// Print method writes the load test report.
+func (r *loadTestResult) Print(w io.Writer) {
+	rps, errRate := 0.0, 0.0
+	if r.Elapsed > 0 {
+		rps = float64(r.Requests) / r.Elapsed.Seconds()
+	}
+	if r.Requests > 0 {
+		errRate = float64(r.Errors) * 100 / float64(r.Requests)
+	}
+
+	fmt.Fprintf(w, "\nRequests      %d in %s, %.1f req/s\n", r.Requests, r.Elapsed-r.Elapsed%time.Millisecond, rps)
+	fmt.Fprintf(w, "Errors        %d (%.2f%%)\n", r.Errors, errRate)
+
+	if len(r.Statuses) > 0 {
+		var codes []int
+		for code := range r.Statuses {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		var parts []string
+		for _, code := range codes {
+			parts = append(parts, fmt.Sprintf("%d: %d", code, r.Statuses[code]))
+		}
+		fmt.Fprintf(w, "Status codes  %s\n", strings.Join(parts, ", "))
+	}
+
+	if len(r.Latencies) > 0 {
+		fmt.Fprintf(w, "\nLatency\n")
+		fmt.Fprintf(w, "    min       %s\n", r.Latencies[0])
+		fmt.Fprintf(w, "    mean      %s\n", r.Mean())
+		for _, p := range []float64{50, 90, 95, 99} {
+			fmt.Fprintf(w, "    p%-8.0f %s\n", p, r.Percentile(p))
+		}
+		fmt.Fprintf(w, "    max       %s\n", r.Latencies[len(r.Latencies)-1])
+	}
+
+	if len(r.errMsgs) > 0 {
+		var msgs []string
+		for msg := range r.errMsgs {
+			msgs = append(msgs, msg)
+		}
+		sort.Strings(msgs)
+		fmt.Fprintf(w, "\nTransport errors\n")
+		for _, msg := range msgs {
+			fmt.Fprintf(w, "    %d x %s\n", r.errMsgs[msg], msg)
+		}
+	}
+}
+
+func init() {
+	loadtestCmd.Run = loadtestRun
+}
@@ -0,0 +1,53 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestLoadTestRun(t *testing.T) {
+	var count int64
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt64(&count, 1)%4 == 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	lt := &loadTester{URL: ts.URL, Method: "GET", Concurrency: 4, Duration: 200 * time.Millisecond, client: &http.Client{}}
+	result := lt.Run(make(chan struct{}))
+	assert.True(t, result.Requests > 0)
+	assert.Equal(t, result.Requests, len(result.Latencies))
+	assert.Equal(t, result.Statuses[http.StatusInternalServerError], result.Errors)
+	assert.Equal(t, result.Requests, result.Statuses[http.StatusOK]+result.Errors)
+
+	buf := &bytes.Buffer{}
+	result.Print(buf)
+	assert.True(t, strings.Contains(buf.String(), "Status codes  200: "))
+	assert.True(t, strings.Contains(buf.String(), "    p99      "))
+}
+
+func TestLoadTestPercentile(t *testing.T) {
+	r := newLoadTestResult()
+	for i := 1; i <= 100; i++ {
+		r.Latencies = append(r.Latencies, time.Duration(i)*time.Millisecond)
+	}
+	assert.Equal(t, 50*time.Millisecond, r.Percentile(50))
+	assert.Equal(t, 99*time.Millisecond, r.Percentile(99))
+	assert.Equal(t, 100*time.Millisecond, r.Percentile(100))
+	assert.Equal(t, 50500*time.Microsecond, r.Mean())
+	assert.Equal(t, time.Duration(0), newLoadTestResult().Percentile(90))
+}
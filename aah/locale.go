@@ -0,0 +1,161 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+// CLI message catalog follows the aah framework i18n approach, message
+// files are in aah config format per locale and lookup falls back from
+// 'language-region' to 'language' then to default locale 'en'.
+
+const cliDefaultLocale = "en"
+
+var (
+	cliMessages   map[string]*config.Config
+	cliLocale     string
+	cliLocaleOnce sync.Once
+
+	msgKeyReplacer = strings.NewReplacer(".", "_", "-", "_")
+
+	// cliMessageFiles are embedded message files, key is the locale.
+	cliMessageFiles = map[string]string{
+		"en": cliMessagesEN,
+		"de": cliMessagesDE,
+	}
+)
+
+// msg method returns the localized message for given key, formatted with
+// given args. If key is not found then it returns the key itself.
+func msg(key string, args ...interface{}) string {
+	cliLocaleOnce.Do(initCLIMessages)
+	for _, locale := range []string{cliLocale, localeLanguage(cliLocale), cliDefaultLocale} {
+		if m, found := cliMessages[locale]; found {
+			if v, found := m.String(key); found {
+				if len(args) == 0 {
+					return v
+				}
+				return fmt.Sprintf(v, args...)
+			}
+		}
+	}
+	return key
+}
+
+// ShortText method returns the localized short description of the command,
+// falls back to `command.Short`.
+func (c *command) ShortText() string {
+	key := "commands." + msgKeyReplacer.Replace(c.Name)
+	if v := msg(key); v != key {
+		return v
+	}
+	return c.Short
+}
+
+func initCLIMessages() {
+	cliLocale = detectLocale()
+	cliMessages = map[string]*config.Config{}
+	for locale, content := range cliMessageFiles {
+		cfg, err := config.ParseString(content)
+		if err != nil {
+			log.Errorf("Unable to parse CLI messages '%s': %s", locale, err)
+			continue
+		}
+		cliMessages[locale] = cfg
+	}
+}
+
+// detectLocale method detects the locale from environment variables
+// 'LC_ALL', 'LC_MESSAGES' and 'LANG' in that order. For e.g.: 'de_DE.UTF-8'
+// becomes 'de-de'.
+func detectLocale() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(name)
+		if ess.IsStrEmpty(v) || v == "C" || v == "POSIX" {
+			continue
+		}
+
+		if idx := strings.IndexAny(v, ".@"); idx > 0 {
+			v = v[:idx]
+		}
+		return strings.ToLower(strings.Replace(v, "_", "-", -1))
+	}
+	return cliDefaultLocale
+}
+
+func localeLanguage(locale string) string {
+	if idx := strings.IndexByte(locale, '-'); idx > 0 {
+		return locale[:idx]
+	}
+	return locale
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Message files
+//___________________________________
+
+const cliMessagesEN = `
+usage {
+  line = "Usage: %v"
+  aah = "Usage: aah [global flags] command [arguments]"
+  flags = "Flags:"
+  global_flags = "Global flags:"
+  commands = "Available commands:"
+  subcommands = "Available subcommands:"
+  topics = "Additional help topics:"
+  help_command = "Use 'aah help [command]' for more information about a command."
+  help_topic = "Use 'aah help [topic]' for more information about that topic."
+  default = "(default %v)"
+}
+
+errors {
+  unknown_command = "Unknown command '%v', Run 'aah help'."
+  too_many_args = "Too many arguments given. Run 'aah help %v'."
+  bad_flag = "%v. Run 'aah help %v'."
+}
+`
+
+const cliMessagesDE = `
+usage {
+  line = "Verwendung: %v"
+  aah = "Verwendung: aah [globale Flags] Befehl [Argumente]"
+  flags = "Flags:"
+  global_flags = "Globale Flags:"
+  commands = "Verfügbare Befehle:"
+  subcommands = "Verfügbare Unterbefehle:"
+  topics = "Weitere Hilfethemen:"
+  help_command = "Verwende 'aah help [Befehl]' für weitere Informationen zu einem Befehl."
+  help_topic = "Verwende 'aah help [Thema]' für weitere Informationen zu diesem Thema."
+  default = "(Standard %v)"
+}
+
+errors {
+  unknown_command = "Unbekannter Befehl '%v', führe 'aah help' aus."
+  too_many_args = "Zu viele Argumente angegeben. Führe 'aah help %v' aus."
+  bad_flag = "%v. Führe 'aah help %v' aus."
+}
+
+commands {
+  new = "neue aah 'web'-, 'api'- oder 'websocket'-Anwendung erstellen"
+  run = "aah Anwendung ausführen"
+  build = "aah Anwendung für das Deployment bauen"
+  list = "alle aah Projekte im GOPATH auflisten"
+  docs = "Dokumentation des aah CLI Tools"
+  version = "aah Framework-, Go- und Bibliotheksversionen ausgeben"
+  help = "Hilfe zu aah Befehlen"
+  aah_project = "aah Projektdatei und Build-Konfiguration"
+  routes = "Routen-Konfiguration 'config/routes.conf'"
+  profiles = "Umgebungsprofile und externe Konfiguration"
+  exit_codes = "Exit-Codes des aah CLI Tools"
+}
+`
@@ -0,0 +1,33 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestLocaleDetect(t *testing.T) {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		_ = os.Unsetenv(name)
+	}
+	assert.Equal(t, "en", detectLocale())
+
+	_ = os.Setenv("LANG", "de_DE.UTF-8")
+	assert.Equal(t, "de-de", detectLocale())
+	assert.Equal(t, "de", localeLanguage(detectLocale()))
+
+	_ = os.Setenv("LC_ALL", "C")
+	assert.Equal(t, "de-de", detectLocale())
+
+	_ = os.Setenv("LC_MESSAGES", "fr_FR@euro")
+	assert.Equal(t, "fr-fr", detectLocale())
+
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		_ = os.Unsetenv(name)
+	}
+}
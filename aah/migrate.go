@@ -0,0 +1,53 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+var migrateCmd = &command{
+	Name:      "migrate",
+	UsageLine: "aah migrate <subcommand> [arguments]",
+	Short:     "migrate database schema and aah project files",
+	Long: `
+Applies the database migrations of 'db/migrations' and migrates the aah
+application project files to the current aah CLI tool.
+
+Migrations are timestamped SQL file pairs, generated by 'aah generate
+migration', or Go file of the package 'db/migrations' declaring the funcs
+'Up<version>' and optionally 'Down<version>' of signature
+'func(tx *sql.Tx) error':
+
+    db/migrations/20170601150405_create_users.up.sql
+    db/migrations/20170601150405_create_users.down.sql
+    db/migrations/20170602093000_seed_roles.go
+
+Database connection is read from the app config section 'database' (refer
+'migration.config_key' of 'aah.project'), profile value 'env.<profile>'
+takes precedence. URL may refer environment variables, e.g. "${DB_URL}":
+
+    database {
+      url = "postgres://localhost/app?sslmode=disable"
+    }
+
+Applied versions are recorded in the table 'schema_migrations' (refer
+'migration.table'). Migrations are run by a temporary Go program built
+with the driver package of 'migration.dialect', e.g. 'github.com/lib/pq',
+it must be available in GOPATH. MySQL URL requires 'multiStatements=true'
+for migrations with multiple statements.
+
+With 'build.embed_migrations = true' of 'aah.project' the up migrations are
+embedded into the application binary, run it with '-migrate' flag to apply
+the pending migrations on startup, e.g. './bin/myapp -profile=prod -migrate'.
+
+Example:
+    aah migrate up
+
+    aah migrate down -steps=2
+
+    aah migrate status -profile=prod
+
+    aah migrate redo
+
+    aah migrate project
+`,
+}
@@ -0,0 +1,707 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+var (
+	migrateUpCmdFlags         = flag.NewFlagSet("up", flag.ContinueOnError)
+	migrateUpProfileFlag      = migrateUpCmdFlags.String("profile", "", "Environment profile of the database config. Default is 'env.active' of app config")
+	migrateUpProfileShortFlag = migrateUpCmdFlags.String("p", "", "Environment profile of the database config. Default is 'env.active' of app config")
+	migrateUpStepsFlag        = migrateUpCmdFlags.Int("steps", 0, "Number of pending migrations to apply. Default is all")
+	migrateUpCmd              = &command{
+		Name:      "up",
+		UsageLine: "aah migrate up [-p | -profile] [-steps]",
+		Flags:     migrateUpCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "apply pending database migrations",
+		Long: `
+Applies the pending migrations of 'db/migrations' in version order, refer
+'aah help migrate' for migration files and database config. Each migration
+is applied and recorded in the migrations table within a transaction, it
+stops at first failure.
+
+Example(s):
+    aah migrate up
+
+    aah migrate up -steps=1 -profile=qa
+`,
+	}
+
+	migrateDownCmdFlags         = flag.NewFlagSet("down", flag.ContinueOnError)
+	migrateDownProfileFlag      = migrateDownCmdFlags.String("profile", "", "Environment profile of the database config. Default is 'env.active' of app config")
+	migrateDownProfileShortFlag = migrateDownCmdFlags.String("p", "", "Environment profile of the database config. Default is 'env.active' of app config")
+	migrateDownStepsFlag        = migrateDownCmdFlags.Int("steps", 1, "Number of applied migrations to revert, 0 reverts all")
+	migrateDownCmd              = &command{
+		Name:      "down",
+		UsageLine: "aah migrate down [-p | -profile] [-steps]",
+		Flags:     migrateDownCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "revert applied database migrations",
+		Long: `
+Reverts the latest applied migration, or '-steps' count of migrations in
+reverse version order. Reverted migration is removed from the migrations
+table.
+
+Example(s):
+    aah migrate down
+
+    aah migrate down -steps=3
+`,
+	}
+
+	migrateRedoCmdFlags         = flag.NewFlagSet("redo", flag.ContinueOnError)
+	migrateRedoProfileFlag      = migrateRedoCmdFlags.String("profile", "", "Environment profile of the database config. Default is 'env.active' of app config")
+	migrateRedoProfileShortFlag = migrateRedoCmdFlags.String("p", "", "Environment profile of the database config. Default is 'env.active' of app config")
+	migrateRedoCmd              = &command{
+		Name:      "redo",
+		UsageLine: "aah migrate redo [-p | -profile]",
+		Flags:     migrateRedoCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "revert and apply again the latest database migration",
+		Long: `
+Reverts the latest applied migration and applies it again, use it while
+writing the migration.
+
+Example(s):
+    aah migrate redo
+`,
+	}
+
+	migrateStatusCmdFlags         = flag.NewFlagSet("status", flag.ContinueOnError)
+	migrateStatusProfileFlag      = migrateStatusCmdFlags.String("profile", "", "Environment profile of the database config. Default is 'env.active' of app config")
+	migrateStatusProfileShortFlag = migrateStatusCmdFlags.String("p", "", "Environment profile of the database config. Default is 'env.active' of app config")
+	migrateStatusCmd              = &command{
+		Name:      "status",
+		UsageLine: "aah migrate status [-p | -profile]",
+		Flags:     migrateStatusCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "show applied and pending database migrations",
+		Long: `
+Lists the migrations of 'db/migrations' with their status, applied
+migrations which do not exist in 'db/migrations' are listed as missing.
+
+    VERSION         NAME            STATUS
+    20170601150405  create_users    applied
+    20170602093000  add_email       pending
+
+Example(s):
+    aah migrate status -profile=prod
+`,
+	}
+
+	migrationTableRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+)
+
+type (
+	// migrationDBConfig is the database connection of the migrations, URL
+	// is read from app config '<migration.config_key>.url' of the profile.
+	migrationDBConfig struct {
+		Dialect      string
+		Driver       string
+		DriverImport string
+		ConfigKey    string
+		URL          string
+		Table        string
+	}
+
+	// migrationRunnerData is the runner template data, Go migrations and
+	// seeds are referred via the package alias of 'Imports'.
+	migrationRunnerData struct {
+		DB         *migrationDBConfig
+		Imports    map[string]string
+		Migrations migrations
+		Seeds      []*dbSeed
+	}
+
+	// migrationRunner is the temporary Go program built with the database
+	// driver, Go migrations and seeds of the application, aah CLI tool
+	// doesn't bundle the database drivers.
+	migrationRunner struct {
+		dir    string
+		binary string
+		env    []string
+	}
+)
+
+func migrateUpRun(args []string) {
+	migrateDBRun(migrationUp, *migrateUpStepsFlag, firstNonEmpty(*migrateUpProfileFlag, *migrateUpProfileShortFlag))
+}
+
+func migrateDownRun(args []string) {
+	migrateDBRun(migrationDown, *migrateDownStepsFlag, firstNonEmpty(*migrateDownProfileFlag, *migrateDownProfileShortFlag))
+}
+
+func migrateRedoRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	all, r := openMigrations(firstNonEmpty(*migrateRedoProfileFlag, *migrateRedoProfileShortFlag))
+	defer r.Close()
+
+	plan := planMigrationsOrExit(all, r, migrationDown, 1)
+	if len(plan) == 0 {
+		log.Info("No applied migrations to redo")
+		return
+	}
+	applyMigrations(r, migrationDown, plan)
+	applyMigrations(r, migrationUp, plan)
+}
+
+func migrateStatusRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	all, r := openMigrations(firstNonEmpty(*migrateStatusProfileFlag, *migrateStatusProfileShortFlag))
+	defer r.Close()
+
+	applied, err := r.Applied()
+	if err != nil {
+		r.Close()
+		fatalErr(newExitErr(exitMigrationError, err))
+		return
+	}
+	writeMigrationStatus(os.Stdout, all, applied)
+}
+
+func migrateDBRun(direction string, steps int, profile string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	if steps < 0 {
+		fatalExit(exitUsage, "'-steps' must be 0 or more")
+		return
+	}
+
+	all, r := openMigrations(profile)
+	defer r.Close()
+
+	plan := planMigrationsOrExit(all, r, direction, steps)
+	if len(plan) == 0 {
+		log.Infof("No migrations to %s", map[string]string{migrationUp: "apply", migrationDown: "revert"}[direction])
+		return
+	}
+	applyMigrations(r, direction, plan)
+}
+
+// openMigrations method loads the migrations and builds the runner, it
+// exits on error.
+func openMigrations(profile string) (migrations, *migrationRunner) {
+	dir := filepath.Join(appProject.BaseDir, filepath.FromSlash(migrationsDir))
+	if !ess.IsFileExists(dir) {
+		fatalExitf(exitUsage, "Migrations directory '%s' does not exists, refer 'aah help generate migration'", migrationsDir)
+	}
+	all, err := loadMigrations(dir)
+	if err != nil {
+		fatalErr(newExitErr(exitMigrationError, err))
+	}
+
+	data := &migrationRunnerData{Imports: map[string]string{}, Migrations: all}
+	for _, mg := range all {
+		if !ess.IsStrEmpty(mg.GoFile) {
+			data.Imports["migrations"] = path.Join(appProject.ImportPath, migrationsDir)
+		}
+	}
+	return all, openMigrationRunner(activeProfile(profile), data)
+}
+
+// openMigrationRunner method reads the database config of the profile and
+// builds the runner, it exits on error.
+func openMigrationRunner(profile string, data *migrationRunnerData) *migrationRunner {
+	var err error
+	data.DB, err = loadMigrationDBConfig(appProject.BuildCfg, aah.AppConfig(), profile, projectEnvLookup(appProject.BaseDir))
+	if err != nil {
+		fatalErr(newExitErr(exitConfigError, err))
+	}
+	log.Infof("Database: %s (%s), profile '%s'", migrationDialects[data.DB.Dialect], data.DB.Driver, profile)
+
+	r, err := buildMigrationRunner(appProject.BuildCfg, data)
+	if err != nil {
+		fatalErr(err)
+	}
+	return r
+}
+
+// activeProfile method returns the given profile, otherwise 'env.active' of
+// app config.
+func activeProfile(profile string) string {
+	return firstNonEmpty(profile, aah.AppConfig().StringDefault("env.active", "dev"))
+}
+
+func planMigrationsOrExit(all migrations, r *migrationRunner, direction string, steps int) migrations {
+	applied, err := r.Applied()
+	if err == nil {
+		var plan migrations
+		if plan, err = planMigrations(all, applied, direction, steps); err == nil {
+			return plan
+		}
+	}
+	r.Close()
+	fatalErr(newExitErr(exitMigrationError, err))
+	return nil
+}
+
+func applyMigrations(r *migrationRunner, direction string, plan migrations) {
+	if isDryRun() {
+		for _, mg := range plan {
+			logDryRun("migrate %s %s_%s", direction, mg.Version, mg.Name)
+		}
+		return
+	}
+	if err := r.Apply(direction, plan); err != nil {
+		r.Close()
+		fatalErr(newExitErr(exitMigrationError, err))
+	}
+}
+
+// loadMigrationDBConfig method reads the database config, URL is looked up
+// from the profile 'env.<profile>.<key>.url' and then '<key>.url' of app
+// config, environment variable placeholders of the URL are expanded.
+func loadMigrationDBConfig(buildCfg, appCfg *config.Config, profile string, lookup envLookup) (*migrationDBConfig, error) {
+	c, err := loadMigrationDialect(buildCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	key := c.ConfigKey
+	profileValue := func(name string) string {
+		return firstNonEmpty(appCfg.StringDefault(strings.Join([]string{"env", profile, key, name}, "."), ""),
+			appCfg.StringDefault(key+"."+name, ""))
+	}
+	c.Driver = firstNonEmpty(profileValue("driver"), c.Driver)
+
+	url, missing, err := interpolateEnv(profileValue("url"), lookup)
+	if err != nil {
+		return nil, fmt.Errorf("app config '%s.url': %s", key, err)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("app config '%s.url': environment variable(s) '%s' are not set", key, strings.Join(missing, "', '"))
+	}
+	if ess.IsStrEmpty(url) {
+		return nil, fmt.Errorf("app config '%s.url' is required for profile '%s'", key, profile)
+	}
+	c.URL = url
+	return c, nil
+}
+
+// loadMigrationDialect method reads the 'migration' section of the project
+// config, driver is the default of the dialect and URL is not read.
+func loadMigrationDialect(buildCfg *config.Config) (*migrationDBConfig, error) {
+	c := &migrationDBConfig{
+		Dialect:   buildCfg.StringDefault("migration.dialect", "postgres"),
+		ConfigKey: buildCfg.StringDefault("migration.config_key", "database"),
+		Table:     buildCfg.StringDefault("migration.table", "schema_migrations"),
+	}
+	driver, found := migrationDrivers[c.Dialect]
+	if !found {
+		return nil, fmt.Errorf("'migration.dialect' value '%s' is not supported, choose either 'postgres', 'mysql' or 'sqlite'", c.Dialect)
+	}
+	if !migrationTableRegex.MatchString(c.Table) {
+		return nil, fmt.Errorf("'migration.table' value '%s' is not a valid table name", c.Table)
+	}
+	c.Driver = driver.Name
+	c.DriverImport = buildCfg.StringDefault("migration.driver_import", driver.Import)
+	return c, nil
+}
+
+// buildMigrationRunner method generates and builds the runner in temporary
+// directory, call `Close` to remove it.
+func buildMigrationRunner(buildCfg *config.Config, data *migrationRunnerData) (*migrationRunner, error) {
+	dir, err := ioutil.TempDir("", "aah-migrate-")
+	if err != nil {
+		return nil, err
+	}
+	r := &migrationRunner{
+		dir:    dir,
+		binary: filepath.Join(dir, "migrate"),
+		env:    append(os.Environ(), "AAH_MIGRATE_URL="+data.DB.URL),
+	}
+	if isWindowsOS() {
+		r.binary += ".exe"
+	}
+
+	buf := &bytes.Buffer{}
+	if err = renderTmpl(buf, migrationRunnerTemplate, data); err != nil {
+		r.Close()
+		return nil, err
+	}
+	mainFile := filepath.Join(dir, "main.go")
+	if err = ioutil.WriteFile(mainFile, buf.Bytes(), permRWRR); err != nil {
+		r.Close()
+		return nil, err
+	}
+
+	args := []string{"build", "-o", r.binary}
+	if tags := buildCfg.StringDefault("build.tags", ""); !ess.IsStrEmpty(tags) {
+		args = append(args, "-tags", tags)
+	}
+	cmd := exec.Command(gocmd, append(args, mainFile)...)
+	cmd.Dir = appProject.BaseDir
+	log.Debug("Executing ", strings.Join(cmd.Args, " "))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		r.Close()
+		return nil, newExitErr(exitCompileError, fmt.Errorf("unable to build migration runner, get the driver via 'go get %s'\n%s%s",
+			data.DB.DriverImport, out, err))
+	}
+	return r, nil
+}
+
+// Applied method returns the applied migration versions, migrations table
+// is created if it does not exists.
+func (r *migrationRunner) Applied() ([]string, error) {
+	cmd := r.command("applied")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read applied migrations: %s", err)
+	}
+
+	var versions []string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if v := strings.TrimSpace(scanner.Text()); !ess.IsStrEmpty(v) {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
+// Apply method applies the migrations in the direction and order, runner
+// reports each migration on stdout.
+func (r *migrationRunner) Apply(direction string, plan migrations) error {
+	args := []string{direction}
+	for _, mg := range plan {
+		args = append(args, mg.Version)
+	}
+	cmd := r.command(args...)
+	cmd.Stdout = os.Stdout
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("migrate %s failed: %s", direction, err)
+	}
+	return nil
+}
+
+// Seed method runs the seeds in given order, runner reports each seed on
+// stdout.
+func (r *migrationRunner) Seed(seeds []*dbSeed) error {
+	args := []string{"seed"}
+	for _, sd := range seeds {
+		args = append(args, sd.Name)
+	}
+	cmd := r.command(args...)
+	cmd.Stdout = os.Stdout
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("seed failed: %s", err)
+	}
+	return nil
+}
+
+// Schema method returns the table columns of the database, refer
+// `parseDBSchema`.
+func (r *migrationRunner) Schema() ([]byte, error) {
+	out, err := r.command("schema").Output()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read database schema: %s", err)
+	}
+	return out, nil
+}
+
+// Close method removes the runner.
+func (r *migrationRunner) Close() {
+	_ = os.RemoveAll(r.dir)
+}
+
+func (r *migrationRunner) command(args ...string) *exec.Cmd {
+	cmd := exec.Command(r.binary, args...)
+	cmd.Dir = appProject.BaseDir
+	cmd.Env = r.env
+	cmd.Stderr = os.Stderr
+	return cmd
+}
+
+func writeMigrationStatus(w io.Writer, all migrations, applied []string) {
+	isApplied := map[string]bool{}
+	for _, v := range applied {
+		isApplied[v] = true
+	}
+
+	width := len("NAME")
+	for _, mg := range all {
+		if len(mg.Name) > width {
+			width = len(mg.Name)
+		}
+	}
+
+	fmt.Fprintf(w, "%-14s  %-*s  %s\n", "VERSION", width, "NAME", "STATUS")
+	known := map[string]bool{}
+	pending := 0
+	for _, mg := range all {
+		known[mg.Version] = true
+		status := "applied"
+		if !isApplied[mg.Version] {
+			status = "pending"
+			pending++
+		}
+		fmt.Fprintf(w, "%-14s  %-*s  %s\n", mg.Version, width, mg.Name, status)
+	}
+	for _, v := range applied {
+		if !known[v] {
+			fmt.Fprintf(w, "%-14s  %-*s  %s\n", v, width, "?", "applied, file missing")
+		}
+	}
+	fmt.Fprintf(w, "\n%d migrations: %d applied, %d pending\n", len(all), len(applied), pending)
+}
+
+func init() {
+	migrateUpCmd.Run = migrateUpRun
+	migrateDownCmd.Run = migrateDownRun
+	migrateRedoCmd.Run = migrateRedoRun
+	migrateStatusCmd.Run = migrateStatusRun
+	migrateCmd.Subcommands = append(migrateCmd.Subcommands, migrateUpCmd, migrateDownCmd, migrateRedoCmd, migrateStatusCmd)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Migration runner template
+//___________________________________
+
+const migrationRunnerTemplate = `// Code generated by 'aah migrate'. DO NOT EDIT.
+
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	_ "{{ .DB.DriverImport }}"
+{{- range $alias, $path := .Imports }}
+	{{ $alias }} "{{ $path }}"
+{{- end }}
+)
+
+type migration struct {
+	Version  string
+	Name     string
+	UpFile   string
+	DownFile string
+	Up       func(*sql.Tx) error
+	Down     func(*sql.Tx) error
+}
+
+type seed struct {
+	Name string
+	File string
+	Run  func(*sql.Tx) error
+}
+
+var migrationList = map[string]*migration{
+{{- range .Migrations }}
+	"{{ .Version }}": {Version: "{{ .Version }}", Name: "{{ .Name }}", UpFile: {{ printf "%q" .UpFile }}, DownFile: {{ printf "%q" .DownFile }}
+		{{- if .GoUp }}, Up: migrations.{{ .GoUp }}{{ end }}{{ if .GoDown }}, Down: migrations.{{ .GoDown }}{{ end }}},
+{{- end }}
+}
+
+var seedList = map[string]*seed{
+{{- range .Seeds }}
+	"{{ .Name }}": {Name: "{{ .Name }}", File: {{ printf "%q" .File }}{{ if .Func }}, Run: {{ .Pkg }}.{{ .Func }}{{ end }}},
+{{- end }}
+}
+
+func main() {
+	db, err := sql.Open("{{ .DB.Driver }}", os.Getenv("AAH_MIGRATE_URL"))
+	if err != nil {
+		fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+
+	switch os.Args[1] {
+	case "seed":
+		for _, name := range os.Args[2:] {
+			s := seedList[name]
+			if err = inTx(db, func(tx *sql.Tx) error { return run(tx, s.File, s.Run) }); err != nil {
+				fatal(fmt.Errorf("%s: %s", name, err))
+			}
+			fmt.Printf("Seeded %s\n", name)
+		}
+		return
+	case "schema":
+		if err = schema(db); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	if _, err = db.Exec("CREATE TABLE IF NOT EXISTS {{ .DB.Table }} (version VARCHAR(14) NOT NULL PRIMARY KEY, applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP)"); err != nil {
+		fatal(err)
+	}
+
+	switch direction := os.Args[1]; direction {
+	case "applied":
+		rows, err := db.Query("SELECT version FROM {{ .DB.Table }} ORDER BY version")
+		if err != nil {
+			fatal(err)
+		}
+		defer func() { _ = rows.Close() }()
+		for rows.Next() {
+			var version string
+			if err = rows.Scan(&version); err != nil {
+				fatal(err)
+			}
+			fmt.Println(version)
+		}
+		if err = rows.Err(); err != nil {
+			fatal(err)
+		}
+	case "up", "down":
+		for _, version := range os.Args[2:] {
+			m := migrationList[version]
+			if err = apply(db, m, direction); err != nil {
+				fatal(fmt.Errorf("%s_%s: %s", m.Version, m.Name, err))
+			}
+			fmt.Printf("Migrated %-4s %s_%s\n", direction, m.Version, m.Name)
+		}
+	}
+}
+
+func apply(db *sql.DB, m *migration, direction string) error {
+	file, fn := m.UpFile, m.Up
+	record := "INSERT INTO {{ .DB.Table }} (version) VALUES ('" + m.Version + "')"
+	if direction == "down" {
+		file, fn = m.DownFile, m.Down
+		record = "DELETE FROM {{ .DB.Table }} WHERE version = '" + m.Version + "'"
+	}
+
+	return inTx(db, func(tx *sql.Tx) error {
+		if err := run(tx, file, fn); err != nil {
+			return err
+		}
+		_, err := tx.Exec(record)
+		return err
+	})
+}
+
+// schema prints the columns of the tables, one per line:
+// table, column, type, nullable (YES or NO) and primary key (1 or 0).
+func schema(db *sql.DB) error {
+{{- if eq .DB.Dialect "sqlite" }}
+	var tables []string
+	rows, err := db.Query("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		tables = append(tables, name)
+	}
+	_ = rows.Close()
+
+	for _, table := range tables {
+		rows, err := db.Query("PRAGMA table_info(\"" + table + "\")")
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var cid, notNull, pk int
+			var name, typ string
+			var dflt interface{}
+			if err = rows.Scan(&cid, &name, &typ, &notNull, &dflt, &pk); err != nil {
+				_ = rows.Close()
+				return err
+			}
+			nullable := "YES"
+			if notNull == 1 || pk > 0 {
+				nullable = "NO"
+			}
+			if pk > 0 {
+				pk = 1
+			}
+			fmt.Printf("%s\t%s\t%s\t%s\t%d\n", table, name, typ, nullable, pk)
+		}
+		_ = rows.Close()
+	}
+	return nil
+{{- else }}
+	rows, err := db.Query({{ if eq .DB.Dialect "mysql" }}` + "`" + `SELECT c.table_name, c.column_name, c.column_type, c.is_nullable,
+		CASE WHEN c.column_key = 'PRI' THEN 1 ELSE 0 END
+		FROM information_schema.columns c JOIN information_schema.tables t
+		ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+		WHERE c.table_schema = DATABASE() AND t.table_type = 'BASE TABLE'
+		ORDER BY c.table_name, c.ordinal_position` + "`" + `{{ else }}` + "`" + `SELECT c.table_name, c.column_name, c.data_type, c.is_nullable,
+		CASE WHEN EXISTS (SELECT 1 FROM information_schema.table_constraints tc
+			JOIN information_schema.key_column_usage k
+			ON k.constraint_name = tc.constraint_name AND k.table_schema = tc.table_schema
+			WHERE tc.constraint_type = 'PRIMARY KEY' AND k.table_schema = c.table_schema
+			AND k.table_name = c.table_name AND k.column_name = c.column_name) THEN 1 ELSE 0 END
+		FROM information_schema.columns c JOIN information_schema.tables t
+		ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+		WHERE c.table_schema = current_schema() AND t.table_type = 'BASE TABLE'
+		ORDER BY c.table_name, c.ordinal_position` + "`" + `{{ end }})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var table, name, typ, nullable string
+		var pk int
+		if err = rows.Scan(&table, &name, &typ, &nullable, &pk); err != nil {
+			return err
+		}
+		fmt.Printf("%s\t%s\t%s\t%s\t%d\n", table, name, typ, nullable, pk)
+	}
+	return rows.Err()
+{{- end }}
+}
+
+func inTx(db *sql.DB, fn func(*sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if err = fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func run(tx *sql.Tx, file string, fn func(*sql.Tx) error) error {
+	if fn != nil {
+		return fn(tx)
+	}
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(string(b))
+	return err
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+`
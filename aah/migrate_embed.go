@@ -0,0 +1,176 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+)
+
+// aahMigrationsFile is the generated Go file of the embedded migrations,
+// it's along with 'app/aah.go'.
+const aahMigrationsFile = "aah_migrations.go"
+
+type (
+	// embeddedMigrations are the migrations to generate 'aah_migrations.go',
+	// Go migrations are referred via package alias 'migrations'.
+	embeddedMigrations struct {
+		DB         *migrationDBConfig
+		Import     string
+		Migrations []*embeddedMigration
+	}
+
+	embeddedMigration struct {
+		Version string
+		Name    string
+		SQL     string
+		GoUp    string
+	}
+)
+
+// loadEmbeddedMigrations method reads the up migrations of 'db/migrations'
+// for embedding into the application binary.
+func loadEmbeddedMigrations(buildCfg *config.Config, baseDir, importPath string) (*embeddedMigrations, error) {
+	db, err := loadMigrationDialect(buildCfg)
+	if err != nil {
+		return nil, newExitErr(exitConfigError, err)
+	}
+
+	dir := filepath.Join(baseDir, filepath.FromSlash(migrationsDir))
+	if !ess.IsFileExists(dir) {
+		return nil, newExitErr(exitConfigError, fmt.Errorf("'build.embed_migrations' is enabled, however migrations directory '%s' does not exists", migrationsDir))
+	}
+	all, err := loadMigrations(dir)
+	if err != nil {
+		return nil, newExitErr(exitMigrationError, err)
+	}
+
+	em := &embeddedMigrations{DB: db}
+	for _, mg := range all {
+		m := &embeddedMigration{Version: mg.Version, Name: mg.Name, GoUp: mg.GoUp}
+		if ess.IsStrEmpty(mg.GoFile) {
+			b, err := ioutil.ReadFile(mg.UpFile)
+			if err != nil {
+				return nil, newExitErr(exitMigrationError, err)
+			}
+			m.SQL = string(b)
+		} else {
+			em.Import = path.Join(importPath, migrationsDir)
+		}
+		em.Migrations = append(em.Migrations, m)
+	}
+	return em, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Generate Templates
+//___________________________________
+
+const aahMigrationsTemplate = `// GENERATED CODE - DO NOT EDIT
+//
+// aah framework v{{.AahVersion}} - https://aahframework.org
+// FILE: aah_migrations.go
+// DESC: database migrations embedded via 'build.embed_migrations'
+
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/log.v0"
+
+	_ "{{ .DB.DriverImport }}"
+{{- if .Import }}
+	migrations "{{ .Import }}"
+{{- end }}
+)
+
+// embeddedMigrations are the up migrations of 'db/migrations' in version order.
+var embeddedMigrations = []struct {
+	Version string
+	Name    string
+	SQL     string
+	Up      func(*sql.Tx) error
+}{
+{{- range .Migrations }}
+	{Version: "{{ .Version }}", Name: "{{ .Name }}", {{ if .GoUp }}Up: migrations.{{ .GoUp }}{{ else }}SQL: {{ printf "%q" .SQL }}{{ end }}},
+{{- end }}
+}
+
+// migrateDB applies the pending embedded migrations to the database of app
+// config '{{ .DB.ConfigKey }}.url', each within a transaction. Environment
+// variables of the URL are expanded.
+func migrateDB() error {
+	url := os.ExpandEnv(aah.AppConfig().StringDefault("{{ .DB.ConfigKey }}.url", ""))
+	if url == "" {
+		return fmt.Errorf("app config '{{ .DB.ConfigKey }}.url' is required")
+	}
+
+	db, err := sql.Open(aah.AppConfig().StringDefault("{{ .DB.ConfigKey }}.driver", "{{ .DB.Driver }}"), url)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	if _, err = db.Exec("CREATE TABLE IF NOT EXISTS {{ .DB.Table }} (version VARCHAR(14) NOT NULL PRIMARY KEY, applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP)"); err != nil {
+		return err
+	}
+
+	applied := map[string]bool{}
+	rows, err := db.Query("SELECT version FROM {{ .DB.Table }}")
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var version string
+		if err = rows.Scan(&version); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		applied[version] = true
+	}
+	_ = rows.Close()
+
+	count := 0
+	for _, m := range embeddedMigrations {
+		if applied[m.Version] {
+			continue
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if m.Up != nil {
+			err = m.Up(tx)
+		} else {
+			_, err = tx.Exec(m.SQL)
+		}
+		if err == nil {
+			_, err = tx.Exec("INSERT INTO {{ .DB.Table }} (version) VALUES ('" + m.Version + "')")
+		}
+		if err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("%s_%s: %s", m.Version, m.Name, err)
+		}
+		if err = tx.Commit(); err != nil {
+			return fmt.Errorf("%s_%s: %s", m.Version, m.Name, err)
+		}
+		log.Infof("Migrated up %s_%s", m.Version, m.Name)
+		count++
+	}
+
+	log.Infof("Database migrations applied: %d, total: %d", count, len(embeddedMigrations))
+	return nil
+}
+`
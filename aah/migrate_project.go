@@ -0,0 +1,276 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+var (
+	migrateProjectCmd = &command{
+		Name:      "project",
+		UsageLine: "aah migrate project",
+		Hooks:     []hook{validateGopath, requireInsideApp},
+		Short:     "upgrade 'aah.project' to the current layout",
+		Long: `
+Upgrades the older 'aah.project' layouts and keys to the current layout,
+the file is rewritten in place and comments are preserved. Renamed value is
+moved into its new section, it's created at the end of file if it does not
+exist. Summary of renamed and removed keys is printed:
+
+    aah.project:32: renamed 'hot_reload.watch.dirs' to 'watch.dirs'
+    aah.project:30: removed 'hot_reload.enable', file watch is enabled by default, use 'aah run -nowatch' to disable it
+
+Existing value of the new key is kept. Run 'aah config lint' afterwards to
+validate the project file, use global flag '-dry-run' to see the summary
+without writing the file.
+
+Example(s):
+    aah migrate project
+
+    aah -dry-run migrate project
+`,
+	}
+
+	// projectMigrations are the renamed and removed keys of 'aah.project',
+	// key without 'To' is removed.
+	projectMigrations = []*projectMigration{
+		{From: "hot_reload.enable", Note: "file watch is enabled by default, use 'aah run -nowatch' to disable it"},
+		{From: "hot_reload.watch.dirs", To: "watch.dirs"},
+		{From: "hot_reload.watch.file.excludes", To: "watch.excludes"},
+		{From: "hot_reload.watch.dir.excludes", Note: "directories are excluded via 'watch.excludes' patterns"},
+		{From: "hot_reload.watch.force_reload", Note: "application is rebuilt on any watched change, refer 'watch.includes'"},
+	}
+
+	// legacyProjectSections are removed once they are empty after migration,
+	// child section is listed first.
+	legacyProjectSections = []string{"hot_reload.watch.file", "hot_reload.watch.dir", "hot_reload.watch", "hot_reload"}
+)
+
+// projectMigration is the key migration of 'aah.project'.
+type projectMigration struct {
+	From string
+	To   string
+	Note string
+}
+
+func migrateProjectRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	projectFile := filepath.Join(appProject.BaseDir, aahProjectIdentifier)
+	src, err := ioutil.ReadFile(projectFile)
+	if err != nil {
+		fatal(err)
+		return
+	}
+
+	result, changes, err := migrateProject(aahProjectIdentifier, src)
+	if err != nil {
+		fatalErr(newExitErr(exitConfigError, err))
+		return
+	}
+	if len(changes) == 0 {
+		log.Infof("'%s' is up to date", aahProjectIdentifier)
+		return
+	}
+
+	for _, c := range changes {
+		log.Info(c)
+	}
+	if err = writeFile(projectFile, result, permRWRR); err != nil {
+		fatal(err)
+		return
+	}
+	log.Infof("Migrated '%s', %d change(s). Run 'aah config lint' to validate it", aahProjectIdentifier, len(changes))
+}
+
+// migrateProject method migrates the 'aah.project' content and returns it
+// along with the change summary lines.
+func migrateProject(file string, src []byte) ([]byte, []string, error) {
+	entries, err := parseConfString(file, string(src))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defined := map[string]*confEntry{}
+	for _, e := range entries {
+		defined[e.Path] = e
+	}
+
+	type insert struct{ path, value string }
+	var removals []*confEntry
+	var inserts []insert
+	var changes []string
+	for _, m := range projectMigrations {
+		e, found := defined[m.From]
+		if !found {
+			continue
+		}
+		for _, d := range entries {
+			if d.Path == m.From {
+				removals = append(removals, d)
+			}
+		}
+
+		switch {
+		case ess.IsStrEmpty(m.To):
+			changes = append(changes, fmt.Sprintf("%s:%d: removed '%s', %s", file, e.Line, m.From, m.Note))
+		case defined[m.To] != nil:
+			changes = append(changes, fmt.Sprintf("%s:%d: removed '%s', existing '%s' is kept", file, e.Line, m.From, m.To))
+		default:
+			inserts = append(inserts, insert{m.To, confEntrySource(e)})
+			changes = append(changes, fmt.Sprintf("%s:%d: renamed '%s' to '%s'", file, e.Line, m.From, m.To))
+		}
+	}
+	if len(changes) == 0 {
+		return src, nil, nil
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return confChangeLine(changes[i]) < confChangeLine(changes[j]) })
+
+	sort.Slice(removals, func(i, j int) bool { return removals[i].Offset > removals[j].Offset })
+	for _, e := range removals {
+		src = removeConfSpan(src, e.Offset, e.End)
+	}
+
+	if src, err = removeEmptySections(file, src, legacyProjectSections); err != nil {
+		return nil, nil, err
+	}
+
+	for _, in := range inserts {
+		if src, err = insertConfValue(file, src, in.path, in.value); err != nil {
+			return nil, nil, err
+		}
+	}
+	return src, changes, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// removeEmptySections method removes the given sections if they don't have
+// any values.
+func removeEmptySections(file string, src []byte, sections []string) ([]byte, error) {
+	for _, section := range sections {
+		entries, err := parseConfString(file, string(src))
+		if err != nil {
+			return nil, err
+		}
+
+		var remove []*confEntry
+		hasChild := false
+		for _, e := range entries {
+			if e.Path == section && e.Kind == confSection {
+				remove = append(remove, e)
+			} else if strings.HasPrefix(e.Path, section+".") {
+				hasChild = true
+			}
+		}
+		if hasChild {
+			continue
+		}
+		for i := len(remove) - 1; i >= 0; i-- {
+			src = removeConfSpan(src, remove[i].Offset, remove[i].End)
+		}
+	}
+	return src, nil
+}
+
+// insertConfValue method inserts the value into the nearest existing
+// section of the key path, otherwise new section is appended at the end.
+func insertConfValue(file string, src []byte, path, value string) ([]byte, error) {
+	entries, err := parseConfString(file, string(src))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := strings.Split(path, ".")
+	for i := len(keys) - 1; i > 0; i-- {
+		parent := strings.Join(keys[:i], ".")
+		for _, e := range entries {
+			if e.Path != parent || e.Kind != confSection {
+				continue
+			}
+
+			brace := e.Offset + bytes.IndexByte(src[e.Offset:], '{') + 1
+			indent := confLineIndent(src, e.Offset) + "  "
+			line := "\n" + indent + strings.Join(keys[i:], ".") + " = " + value
+			if rest := bytes.TrimLeft(src[brace:], " \t\r"); len(rest) > 0 && rest[0] != '\n' {
+				line += "\n" + indent
+			}
+			return append(src[:brace], append([]byte(line), src[brace:]...)...), nil
+		}
+	}
+
+	buf := bytes.NewBuffer(src)
+	if len(src) > 0 && src[len(src)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+	if len(keys) == 1 {
+		fmt.Fprintf(buf, "\n%s = %s\n", path, value)
+	} else {
+		fmt.Fprintf(buf, "\n%s {\n  %s = %s\n}\n", keys[0], strings.Join(keys[1:], "."), value)
+	}
+	return buf.Bytes(), nil
+}
+
+// removeConfSpan method removes the bytes of the entry, the line is removed
+// if nothing else is left on it.
+func removeConfSpan(src []byte, start, end int) []byte {
+	lineStart := bytes.LastIndexByte(src[:start], '\n') + 1
+	lineEnd := len(src)
+	if idx := bytes.IndexByte(src[end:], '\n'); idx >= 0 {
+		lineEnd = end + idx + 1
+	}
+
+	if len(bytes.TrimSpace(src[lineStart:start])) == 0 && len(bytes.TrimSpace(src[end:lineEnd])) == 0 {
+		start, end = lineStart, lineEnd
+	}
+	return append(src[:start:start], src[end:]...)
+}
+
+// confChangeLine method returns the line number of change summary line
+// 'file:line: message'.
+func confChangeLine(change string) int {
+	parts := strings.SplitN(change, ":", 3)
+	n, _ := strconv.Atoi(parts[1])
+	return n
+}
+
+// confLineIndent method returns the leading whitespaces of the line.
+func confLineIndent(src []byte, offset int) string {
+	line := src[bytes.LastIndexByte(src[:offset], '\n')+1 : offset]
+	return string(line[:len(line)-len(bytes.TrimLeft(line, " \t"))])
+}
+
+// confEntrySource method returns the config source of entry value.
+func confEntrySource(e *confEntry) string {
+	switch e.Kind {
+	case confString:
+		return strconv.Quote(e.Value)
+	case confList:
+		items := make([]string, 0, len(e.Items))
+		for _, item := range e.Items {
+			items = append(items, confEntrySource(item))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	return e.Value
+}
+
+func init() {
+	migrateProjectCmd.Run = migrateProjectRun
+	migrateCmd.Subcommands = append(migrateCmd.Subcommands, migrateProjectCmd)
+}
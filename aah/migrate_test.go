@@ -0,0 +1,261 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/test.v0/assert"
+)
+
+func TestMigrateProject(t *testing.T) {
+	src := `# aah project
+build {
+  # binary name
+  binary_name = "myapp"
+}
+
+# Hot reload section
+hot_reload {
+  enable = true
+
+  watch {
+    # force reload on Go changes
+    force_reload = ["*.go"]
+
+    # directories to watch
+    dirs = ["app", "config"]
+
+    file { excludes = ["*.tmp"] }
+    dir {
+      excludes = [".*"]
+    }
+  }
+}
+
+watch {
+  debounce = "500ms"
+}
+`
+	result, changes, err := migrateProject("aah.project", []byte(src))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"aah.project:9: removed 'hot_reload.enable', file watch is enabled by default, use 'aah run -nowatch' to disable it",
+		"aah.project:13: removed 'hot_reload.watch.force_reload', application is rebuilt on any watched change, refer 'watch.includes'",
+		"aah.project:16: renamed 'hot_reload.watch.dirs' to 'watch.dirs'",
+		"aah.project:18: renamed 'hot_reload.watch.file.excludes' to 'watch.excludes'",
+		"aah.project:20: removed 'hot_reload.watch.dir.excludes', directories are excluded via 'watch.excludes' patterns",
+	}, changes)
+	assert.Equal(t, `# aah project
+build {
+  # binary name
+  binary_name = "myapp"
+}
+
+# Hot reload section
+
+watch {
+  excludes = ["*.tmp"]
+  dirs = ["app", "config"]
+  debounce = "500ms"
+}
+`, string(result))
+
+	result, changes, err = migrateProject("aah.project", result)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(changes))
+
+	result, _, err = migrateProject("aah.project", []byte("hot_reload { watch { dirs = [\"app\"] } }\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, "\nwatch {\n  dirs = [\"app\"]\n}\n", string(result))
+}
+
+func TestMigrateDBPlan(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-migrate")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	files := map[string]string{
+		"20170601150405_create_users.up.sql":   "CREATE TABLE users (id INT);",
+		"20170601150405_create_users.down.sql": "DROP TABLE users;",
+		"20170602093000_add_email.up.sql":      "ALTER TABLE users ADD email TEXT;",
+		"20170603120000_seed_roles.go": `package migrations
+
+import "database/sql"
+
+func Up20170603120000(tx *sql.Tx) error   { return nil }
+func Down20170603120000(tx *sql.Tx) error { return nil }
+`,
+		"README.md": "migrations",
+	}
+	for name, content := range files {
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), permRWRR))
+	}
+
+	all, err := loadMigrations(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(all))
+	assert.Equal(t, "create_users", all[0].Name)
+	assert.Equal(t, "", all[1].DownFile)
+	assert.Equal(t, "Up20170603120000", all[2].GoUp)
+	assert.Equal(t, "Down20170603120000", all[2].GoDown)
+
+	plan, err := planMigrations(all, []string{"20170601150405"}, migrationUp, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(plan))
+	assert.Equal(t, "add_email", plan[0].Name)
+
+	plan, err = planMigrations(all, nil, migrationUp, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(plan))
+	assert.Equal(t, "create_users", plan[0].Name)
+
+	plan, err = planMigrations(all, []string{"20170601150405", "20170603120000"}, migrationDown, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(plan))
+	assert.Equal(t, "seed_roles", plan[0].Name)
+
+	_, err = planMigrations(all, []string{"20170601150405", "20170602093000"}, migrationDown, 1)
+	assert.Equal(t, "migration '20170602093000_add_email' has no down migration", err.Error())
+
+	_, err = planMigrations(all, []string{"20170501000000"}, migrationDown, 0)
+	assert.Equal(t, "applied migration '20170501000000' does not exists in 'db/migrations'", err.Error())
+
+	buf := &bytes.Buffer{}
+	writeMigrationStatus(buf, all, []string{"20170501000000", "20170601150405"})
+	assert.Equal(t, `VERSION         NAME          STATUS
+20170601150405  create_users  applied
+20170602093000  add_email     pending
+20170603120000  seed_roles    pending
+20170501000000  ?             applied, file missing
+
+3 migrations: 2 applied, 2 pending
+`, buf.String())
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "20170602093000_add_email.go"), []byte("package migrations\n"), permRWRR))
+	_, err = loadMigrations(dir)
+	assert.Equal(t, "20170602093000_add_email.go: func 'Up20170602093000(tx *sql.Tx) error' is not declared", err.Error())
+}
+
+func TestMigrateDBConfig(t *testing.T) {
+	buildCfg, _ := config.ParseString(`migration { dialect = "mysql" }`)
+	appCfg, _ := config.ParseString(`
+database {
+  url = "root@/app"
+}
+env {
+  prod {
+    database {
+      url = "${DB_URL}?multiStatements=true"
+    }
+  }
+}`)
+	lookup := func(name string) (string, bool) {
+		if name == "DB_URL" {
+			return "app@tcp(db:3306)/app", true
+		}
+		return "", false
+	}
+
+	c, err := loadMigrationDBConfig(buildCfg, appCfg, "dev", lookup)
+	assert.Nil(t, err)
+	assert.Equal(t, "root@/app", c.URL)
+	assert.Equal(t, "mysql", c.Driver)
+	assert.Equal(t, "github.com/go-sql-driver/mysql", c.DriverImport)
+	assert.Equal(t, "schema_migrations", c.Table)
+
+	c, err = loadMigrationDBConfig(buildCfg, appCfg, "prod", lookup)
+	assert.Nil(t, err)
+	assert.Equal(t, "app@tcp(db:3306)/app?multiStatements=true", c.URL)
+
+	emptyCfg, _ := config.ParseString(``)
+	_, err = loadMigrationDBConfig(emptyCfg, emptyCfg, "dev", lookup)
+	assert.Equal(t, "app config 'database.url' is required for profile 'dev'", err.Error())
+
+	badCfg, _ := config.ParseString(`migration { table = "migrations; DROP" }`)
+	_, err = loadMigrationDBConfig(badCfg, appCfg, "dev", lookup)
+	assert.NotNil(t, err)
+}
+
+func TestMigrateDBRunnerTemplate(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := renderTmpl(buf, migrationRunnerTemplate, &migrationRunnerData{
+		DB:      &migrationDBConfig{Driver: "postgres", DriverImport: "github.com/lib/pq", Table: "schema_migrations"},
+		Imports: map[string]string{"migrations": "github.com/user/app/db/migrations", "seeds": "github.com/user/app/db/seeds"},
+		Migrations: migrations{
+			{Version: "20170601150405", Name: "create_users", UpFile: "/app/db/migrations/20170601150405_create_users.up.sql"},
+			{Version: "20170603120000", Name: "seed_roles", GoUp: "Up20170603120000"},
+		},
+		Seeds: []*dbSeed{
+			{Name: "01_users", File: "/app/db/seeds/01_users.sql"},
+			{Name: "02_roles", File: "/app/db/seeds/02_roles.go", Func: "Seed02Roles", Pkg: "seeds"},
+		},
+	})
+	assert.Nil(t, err)
+
+	out := buf.String()
+	_, err = parser.ParseFile(token.NewFileSet(), "main.go", out, 0)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(out, `migrations "github.com/user/app/db/migrations"`))
+	assert.True(t, strings.Contains(out, `Up: migrations.Up20170603120000},`))
+	assert.True(t, strings.Contains(out, `seeds "github.com/user/app/db/seeds"`))
+	assert.True(t, strings.Contains(out, `Run: seeds.Seed02Roles},`))
+	assert.True(t, strings.Contains(out, `sql.Open("postgres", os.Getenv("AAH_MIGRATE_URL"))`))
+}
+
+func TestMigrateEmbed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-migrate-embed")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	buildCfg, _ := config.ParseString(`migration { dialect = "sqlite", config_key = "db" }`)
+	_, err = loadEmbeddedMigrations(buildCfg, dir, "github.com/user/app")
+	assert.NotNil(t, err)
+
+	migrationsPath := filepath.Join(dir, "db", "migrations")
+	assert.Nil(t, os.MkdirAll(migrationsPath, permRWXRXRX))
+	files := map[string]string{
+		"20170601150405_create_users.up.sql":   "CREATE TABLE users (name TEXT DEFAULT \"\");",
+		"20170601150405_create_users.down.sql": "DROP TABLE users;",
+		"20170603120000_seed_roles.go": `package migrations
+
+import "database/sql"
+
+func Up20170603120000(tx *sql.Tx) error { return nil }
+`,
+	}
+	for name, content := range files {
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(migrationsPath, name), []byte(content), permRWRR))
+	}
+
+	em, err := loadEmbeddedMigrations(buildCfg, dir, "github.com/user/app")
+	assert.Nil(t, err)
+	assert.Equal(t, "github.com/user/app/db/migrations", em.Import)
+	assert.Equal(t, 2, len(em.Migrations))
+	assert.Equal(t, "CREATE TABLE users (name TEXT DEFAULT \"\");", em.Migrations[0].SQL)
+	assert.Equal(t, "Up20170603120000", em.Migrations[1].GoUp)
+
+	buf := &bytes.Buffer{}
+	err = renderTmpl(buf, aahMigrationsTemplate, map[string]interface{}{
+		"AahVersion": "0.10", "DB": em.DB, "Import": em.Import, "Migrations": em.Migrations,
+	})
+	assert.Nil(t, err)
+
+	out := buf.String()
+	_, err = parser.ParseFile(token.NewFileSet(), "aah_migrations.go", out, 0)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(out, `_ "github.com/mattn/go-sqlite3"`))
+	assert.True(t, strings.Contains(out, `SQL: "CREATE TABLE users (name TEXT DEFAULT \"\");"},`))
+	assert.True(t, strings.Contains(out, `Up: migrations.Up20170603120000},`))
+	assert.True(t, strings.Contains(out, `aah.AppConfig().StringDefault("db.driver", "sqlite3")`))
+}
@@ -0,0 +1,218 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+)
+
+// Migrations are timestamped SQL file pairs under 'db/migrations', for e.g.:
+//
+//	20170601150405_create_users.up.sql
+//	20170601150405_create_users.down.sql
+//
+// or Go file of the package 'db/migrations' with funcs 'Up<version>' and
+// 'Down<version>' of signature 'func(*sql.Tx) error', for e.g.:
+//
+//	20170601150405_seed_roles.go
+const (
+	migrationsDir        = "db/migrations"
+	migrationVersionTime = "20060102150405"
+	migrationUp          = "up"
+	migrationDown        = "down"
+)
+
+var (
+	migrationFileRegex   = regexp.MustCompile(`^(\d{14})_([a-z0-9_]+)\.(up|down)\.sql$`)
+	migrationGoFileRegex = regexp.MustCompile(`^(\d{14})_([a-z0-9_]+)\.go$`)
+
+	migrationDialects = map[string]string{
+		"postgres": "PostgreSQL",
+		"mysql":    "MySQL",
+		"sqlite":   "SQLite",
+	}
+
+	// migrationDrivers are the default 'database/sql' driver name and its
+	// package of the dialects.
+	migrationDrivers = map[string]migrationDriver{
+		"postgres": {Name: "postgres", Import: "github.com/lib/pq"},
+		"mysql":    {Name: "mysql", Import: "github.com/go-sql-driver/mysql"},
+		"sqlite":   {Name: "sqlite3", Import: "github.com/mattn/go-sqlite3"},
+	}
+)
+
+type (
+	// migration holds the single migration version details.
+	migration struct {
+		Version  string
+		Name     string
+		UpFile   string
+		DownFile string
+		GoFile   string
+		GoUp     string
+		GoDown   string
+	}
+
+	migrations []*migration
+
+	migrationDriver struct {
+		Name   string
+		Import string
+	}
+)
+
+// loadMigrations method reads the migrations from given directory sorted
+// by version.
+func loadMigrations(dir string) (migrations, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[string]*migration{}
+	var result migrations
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+
+		m := migrationFileRegex.FindStringSubmatch(f.Name())
+		if m == nil {
+			m = migrationGoFileRegex.FindStringSubmatch(f.Name())
+		}
+		if m == nil {
+			continue
+		}
+
+		mg, found := byVersion[m[1]]
+		if !found {
+			mg = &migration{Version: m[1], Name: m[2]}
+			byVersion[m[1]] = mg
+			result = append(result, mg)
+		} else if mg.Name != m[2] {
+			return nil, fmt.Errorf("migration version '%s' is used by '%s' and '%s'", m[1], mg.Name, m[2])
+		}
+
+		file := filepath.Join(dir, f.Name())
+		switch {
+		case len(m) == 3:
+			if mg.GoUp, mg.GoDown, err = goMigrationFuncs(file, mg.Version); err != nil {
+				return nil, err
+			}
+			mg.GoFile = file
+		case m[3] == migrationUp:
+			mg.UpFile = file
+		default:
+			mg.DownFile = file
+		}
+	}
+
+	for _, mg := range result {
+		if !ess.IsStrEmpty(mg.GoFile) && (!ess.IsStrEmpty(mg.UpFile) || !ess.IsStrEmpty(mg.DownFile)) {
+			return nil, fmt.Errorf("migration version '%s' has both SQL and Go files", mg.Version)
+		}
+	}
+
+	sort.Sort(result)
+	return result, nil
+}
+
+// goMigrationFuncs method returns the 'Up<version>' and 'Down<version>' func
+// names declared in the Go migration file, 'Up' func is required.
+func goMigrationFuncs(file, version string) (string, string, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), file, nil, 0)
+	if err != nil {
+		return "", "", err
+	}
+
+	var up, down string
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil {
+			continue
+		}
+		switch fn.Name.Name {
+		case "Up" + version:
+			up = fn.Name.Name
+		case "Down" + version:
+			down = fn.Name.Name
+		}
+	}
+	if ess.IsStrEmpty(up) {
+		return "", "", fmt.Errorf("%s: func 'Up%s(tx *sql.Tx) error' is not declared", filepath.Base(file), version)
+	}
+	return up, down, nil
+}
+
+// planMigrations method returns the migrations to apply in the direction,
+// 'up' applies the pending migrations in version order and 'down' reverts
+// the applied migrations in reverse order. Steps limits the count, zero
+// means all.
+func planMigrations(all migrations, applied []string, direction string, steps int) (migrations, error) {
+	byVersion := map[string]*migration{}
+	for _, mg := range all {
+		byVersion[mg.Version] = mg
+	}
+	isApplied := map[string]bool{}
+	for _, v := range applied {
+		isApplied[v] = true
+	}
+
+	var plan migrations
+	if direction == migrationUp {
+		for _, mg := range all {
+			if isApplied[mg.Version] {
+				continue
+			}
+			if ess.IsStrEmpty(mg.UpFile) && ess.IsStrEmpty(mg.GoUp) {
+				return nil, fmt.Errorf("migration '%s_%s' has no up migration", mg.Version, mg.Name)
+			}
+			plan = append(plan, mg)
+		}
+	} else {
+		versions := append([]string{}, applied...)
+		sort.Sort(sort.Reverse(sort.StringSlice(versions)))
+		for _, v := range versions {
+			mg, found := byVersion[v]
+			if !found {
+				return nil, fmt.Errorf("applied migration '%s' does not exists in '%s'", v, migrationsDir)
+			}
+			if ess.IsStrEmpty(mg.DownFile) && ess.IsStrEmpty(mg.GoDown) {
+				return nil, fmt.Errorf("migration '%s_%s' has no down migration", mg.Version, mg.Name)
+			}
+			plan = append(plan, mg)
+			if steps > 0 && len(plan) == steps {
+				break
+			}
+		}
+	}
+
+	if steps > 0 && len(plan) > steps {
+		plan = plan[:steps]
+	}
+	return plan, nil
+}
+
+// migrationName method returns the normalized migration name, for e.g.:
+// 'CreateUsers' or 'create-users' becomes 'create_users'.
+func migrationName(name string) string {
+	name = toSnakeCase(strings.TrimSpace(name))
+	return strings.Trim(regexp.MustCompile(`[^a-z0-9_]+`).ReplaceAllString(name, "_"), "_")
+}
+
+// Sort interface for migrations by version
+func (m migrations) Len() int           { return len(m) }
+func (m migrations) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+func (m migrations) Less(i, j int) bool { return m[i].Version < m[j].Version }
@@ -0,0 +1,260 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+var mvCmd = &command{
+	Name:      "mv",
+	UsageLine: "aah mv <old-import-path> <new-import-path>",
+	MaxArgs:   2,
+	Hooks:     []hook{validateGopath},
+	Short:     "move aah application to new import path",
+	Long: `
+Moves the aah application to new import path within GOPATH. It rewrites
+the import statements of application Go source files, import path
+references in 'aah.project' and config files (such as routes namespaces),
+verifies the rewritten Go sources still parse, moves the application
+directory and then writes the rewritten files.
+
+Nothing is changed if any of the rewritten Go source fails to parse, move
+is rolled back if writing the rewritten files fails.
+
+Example:
+    aah mv github.com/user/oldapp github.com/user/newapp
+
+    aah -dry-run mv github.com/user/oldapp github.com/org/oldapp
+`,
+}
+
+// mvFile holds the rewritten content of application file, original content
+// is kept to roll back the move.
+type mvFile struct {
+	Path     string
+	Content  []byte
+	Original []byte
+	Mode     os.FileMode
+}
+
+func mvRun(args []string) {
+	if len(args) != 2 {
+		fatalExit(exitUsage, "Old and new import path are required. Run 'aah help mv'.")
+		return
+	}
+
+	oldPath, newPath := strings.Trim(args[0], "/"), strings.Trim(args[1], "/")
+	if oldPath == newPath {
+		fatalExitf(exitUsage, "Old and new import path are same '%s'", oldPath)
+		return
+	}
+
+	if strings.HasPrefix(newPath, oldPath+"/") {
+		fatalExitf(exitUsage, "New import path '%s' cannot be inside the old import path '%s'", newPath, oldPath)
+		return
+	}
+
+	oldDir := filepath.Join(gosrcDir, filepath.FromSlash(oldPath))
+	newDir := filepath.Join(gosrcDir, filepath.FromSlash(newPath))
+	if !ess.IsFileExists(filepath.Join(oldDir, aahProjectIdentifier)) {
+		fatalExitf(exitConfigError, "'%s' is not a valid aah framework application, missing 'aah.project' file", oldPath)
+		return
+	}
+
+	if ess.IsFileExists(newDir) {
+		fatalExitf(exitUsage, "New import path '%s' already exists", newPath)
+		return
+	}
+
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	files, errs := rewriteAppImportPath(oldDir, oldPath, newPath)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			log.Error(err)
+		}
+		fatalExitf(exitParseError, "Import path rewrite failed, application '%s' is unchanged", oldPath)
+		return
+	}
+
+	if err := moveApp(oldDir, newDir, files); err != nil {
+		fatalf("Unable to move application, application '%s' is unchanged: %s", oldPath, err)
+	}
+
+	log.Infof("\nApplication moved from '%s' to '%s', %d file(s) updated", oldPath, newPath, len(files))
+	log.Info("Update the import path in your version control and CI configuration if needed.")
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// moveApp method moves the application directory and then writes the
+// rewritten files under new directory. Move is rolled back if any of the
+// write fails, so application stays in old directory with its original
+// content.
+func moveApp(oldDir, newDir string, files []*mvFile) error {
+	if err := mkDirAll(filepath.Dir(newDir), permRWXRXRX); err != nil {
+		return err
+	}
+
+	if err := renameFile(oldDir, newDir); err != nil {
+		return err
+	}
+
+	for i, f := range files {
+		rel, _ := filepath.Rel(oldDir, f.Path)
+		file := filepath.Join(newDir, rel)
+		if err := writeFile(file, f.Content, f.Mode); err != nil {
+			rollbackMoveApp(oldDir, newDir, files[:i])
+			return err
+		}
+		log.Infof("Rewritten %s", file)
+	}
+	return nil
+}
+
+// rollbackMoveApp method restores the original content of written files and
+// moves the application directory back.
+func rollbackMoveApp(oldDir, newDir string, written []*mvFile) {
+	for _, f := range written {
+		rel, _ := filepath.Rel(oldDir, f.Path)
+		if err := writeFile(filepath.Join(newDir, rel), f.Original, f.Mode); err != nil {
+			log.Errorf("Unable to restore %s: %s", f.Path, err)
+		}
+	}
+	if err := renameFile(newDir, oldDir); err != nil {
+		log.Errorf("Unable to move application back to %s: %s", oldDir, err)
+	}
+}
+
+// rewriteAppImportPath method rewrites the import path in Go source files
+// and config files of the application. It doesn't write anything, it
+// returns the changed files with content.
+func rewriteAppImportPath(baseDir, oldPath, newPath string) ([]*mvFile, []error) {
+	var (
+		files []*mvFile
+		errs  []error
+	)
+
+	err := ess.Walk(baseDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+
+		name := info.Name()
+		if info.IsDir() {
+			if path != baseDir && (strings.HasPrefix(name, ".") || name == "vendor" || name == "build") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		isGo := strings.HasSuffix(name, ".go")
+		if !isGo && name != aahProjectIdentifier && !strings.HasSuffix(name, ".conf") {
+			return nil
+		}
+
+		src, err := ioutil.ReadFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+
+		var content []byte
+		if isGo {
+			content, err = rewriteGoImports(path, src, oldPath, newPath)
+		} else {
+			content = rewriteImportPathText(src, oldPath, newPath)
+		}
+
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+
+		if !bytes.Equal(src, content) {
+			files = append(files, &mvFile{Path: path, Content: content, Original: src, Mode: info.Mode()})
+		}
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return files, errs
+}
+
+// rewriteGoImports method rewrites the import statements of given Go source
+// from old import path to new one, including its sub-packages. Rewritten
+// source is parsed again to ensure it's still valid.
+func rewriteGoImports(filename string, src []byte, oldPath, newPath string) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := false
+	for _, imp := range f.Imports {
+		p, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid import %s", filename, imp.Path.Value)
+		}
+
+		if p == oldPath || strings.HasPrefix(p, oldPath+"/") {
+			imp.Path.Value = strconv.Quote(newPath + p[len(oldPath):])
+			changed = true
+		}
+	}
+
+	if !changed {
+		return src, nil
+	}
+
+	buf := &bytes.Buffer{}
+	if err = format.Node(buf, fset, f); err != nil {
+		return nil, fmt.Errorf("%s: %s", filename, err)
+	}
+
+	// format source sorts the rewritten imports and validates the source
+	content, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%s: rewritten source does not parse: %s", filename, err)
+	}
+
+	if _, err = parser.ParseFile(token.NewFileSet(), filename, content, 0); err != nil {
+		return nil, fmt.Errorf("%s: rewritten source does not parse: %s", filename, err)
+	}
+	return content, nil
+}
+
+// rewriteImportPathText method replaces the old import path with new one
+// in the text content such as 'aah.project' and config files. Only the
+// complete import path (or its sub-package) is replaced.
+func rewriteImportPathText(src []byte, oldPath, newPath string) []byte {
+	re := regexp.MustCompile(`(^|[^\w.\-/])` + regexp.QuoteMeta(oldPath) + `(/|[^\w.\-/]|$)`)
+	return re.ReplaceAll(src, []byte("${1}"+newPath+"${2}"))
+}
+
+func init() {
+	mvCmd.Run = mvRun
+}
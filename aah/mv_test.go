@@ -0,0 +1,85 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/test.v0/assert"
+)
+
+func TestMvRewriteGoImports(t *testing.T) {
+	src := `package controllers
+
+import (
+	"github.com/user/oldapp/app/models"
+	"github.com/user/oldapplication/app/util"
+
+	"aahframework.org/aah.v0"
+)
+
+var _ = models.User{}
+`
+
+	content, err := rewriteGoImports("app.go", []byte(src), "github.com/user/oldapp", "github.com/org/newapp")
+	assert.Nil(t, err)
+	assert.Equal(t, `package controllers
+
+import (
+	"github.com/org/newapp/app/models"
+	"github.com/user/oldapplication/app/util"
+
+	"aahframework.org/aah.v0"
+)
+
+var _ = models.User{}
+`, string(content))
+
+	_, err = rewriteGoImports("bad.go", []byte("package main\nfunc {"), "a", "b")
+	assert.NotNil(t, err)
+}
+
+func TestMvRewriteImportPathText(t *testing.T) {
+	src := `ldflags = "-X github.com/user/oldapp/app.Name=x"
+namespace = "github.com/user/oldapp"
+other = "github.com/user/oldapplication"
+`
+	assert.Equal(t, `ldflags = "-X github.com/org/newapp/app.Name=x"
+namespace = "github.com/org/newapp"
+other = "github.com/user/oldapplication"
+`, string(rewriteImportPathText([]byte(src), "github.com/user/oldapp", "github.com/org/newapp")))
+}
+
+func TestMvMoveApp(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-mv")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	oldDir := filepath.Join(dir, "user", "oldapp")
+	newDir := filepath.Join(dir, "org", "newapp")
+	assert.Nil(t, os.MkdirAll(filepath.Join(oldDir, "app"), 0755))
+	appFile := filepath.Join(oldDir, "app", "init.go")
+	assert.Nil(t, ioutil.WriteFile(appFile, []byte("old"), 0644))
+
+	files := []*mvFile{
+		{Path: appFile, Content: []byte("new"), Original: []byte("old"), Mode: 0644},
+		{Path: filepath.Join(oldDir, "missing", "x.go"), Content: []byte("new"), Mode: 0644},
+	}
+	assert.NotNil(t, moveApp(oldDir, newDir, files))
+	assert.False(t, ess.IsFileExists(newDir))
+	content, err := ioutil.ReadFile(appFile)
+	assert.Nil(t, err)
+	assert.Equal(t, "old", string(content))
+
+	assert.Nil(t, moveApp(oldDir, newDir, files[:1]))
+	assert.False(t, ess.IsFileExists(oldDir))
+	content, err = ioutil.ReadFile(filepath.Join(newDir, "app", "init.go"))
+	assert.Nil(t, err)
+	assert.Equal(t, "new", string(content))
+}
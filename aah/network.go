@@ -0,0 +1,170 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+)
+
+// systemCAFiles are the well-known CA bundle locations, it's combined with
+// 'network.ca_bundle' for the commands executed by aah CLI tool.
+var systemCAFiles = []string{
+	"/etc/ssl/certs/ca-certificates.crt",
+	"/etc/pki/tls/certs/ca-bundle.crt",
+	"/etc/ssl/ca-bundle.pem",
+	"/etc/pki/tls/cacert.pem",
+	"/etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem",
+	"/etc/ssl/cert.pem",
+}
+
+// cliTransport is the HTTP transport of aah CLI tool requests, it's nil
+// (default transport) unless 'network.ca_bundle' is configured.
+var cliTransport *http.Transport
+
+// applyNetworkConfig method applies the 'network' section of 'cli.conf', so
+// all network operations of aah CLI tool honor the corporate proxy and CA
+// bundle. Proxy values are exported as environment values if they are not
+// set already, commands executed by aah CLI tool (go get, git clone) inherit
+// them. CA bundle is added to the system roots of CLI requests, for the
+// commands it's combined with system CA bundle and exported as
+// 'SSL_CERT_FILE' and 'GIT_SSL_CAINFO' if they are not set already.
+//
+//	network {
+//	  http_proxy = "http://proxy.example.com:3128"
+//	  https_proxy = "http://proxy.example.com:3128"
+//	  no_proxy = "localhost,127.0.0.1,.example.com"
+//	  ca_bundle = "/etc/ssl/certs/example-corp.pem"
+//	}
+func applyNetworkConfig(cliCfg *config.Config) {
+	for k, v := range networkEnv(cliCfg, os.Getenv) {
+		_ = os.Setenv(k, v)
+	}
+
+	caBundle := cliCfg.StringDefault("network.ca_bundle", "")
+	if ess.IsStrEmpty(caBundle) {
+		return
+	}
+
+	pem, err := ioutil.ReadFile(caBundle)
+	if err != nil {
+		logWarnf("Unable to read 'network.ca_bundle': %s", err)
+		return
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		logWarnf("No certificates found in 'network.ca_bundle': %s", caBundle)
+		return
+	}
+	cliTransport = newCLITransport(&tls.Config{RootCAs: pool})
+
+	if isDryRun() || (!ess.IsStrEmpty(os.Getenv("SSL_CERT_FILE")) && !ess.IsStrEmpty(os.Getenv("GIT_SSL_CAINFO"))) {
+		return
+	}
+	file := filepath.Join(aahUserDir(), "certs", "ca-bundle.pem")
+	if err = writeCABundle(file, systemCAFiles, pem); err != nil {
+		logWarnf("Unable to write combined CA bundle: %s", err)
+		return
+	}
+	for _, k := range []string{"SSL_CERT_FILE", "GIT_SSL_CAINFO"} {
+		if ess.IsStrEmpty(os.Getenv(k)) {
+			_ = os.Setenv(k, file)
+		}
+	}
+}
+
+// newHTTPClient method returns the HTTP client for aah CLI tool requests,
+// it honors the proxy environment values and 'network.ca_bundle'.
+func newHTTPClient(timeout time.Duration) *http.Client {
+	c := &http.Client{Timeout: timeout}
+	if cliTransport != nil {
+		c.Transport = cliTransport
+	}
+	return c
+}
+
+// networkEnv method returns the proxy environment values of 'cli.conf' which
+// are not set in the environment. Both upper and lower case names are
+// returned, since tools differ in which one they read.
+func networkEnv(cliCfg *config.Config, getenv func(string) string) map[string]string {
+	env := map[string]string{}
+	for _, p := range []struct {
+		key   string
+		names []string
+	}{
+		{"network.http_proxy", []string{"HTTP_PROXY", "http_proxy"}},
+		{"network.https_proxy", []string{"HTTPS_PROXY", "https_proxy"}},
+		{"network.no_proxy", []string{"NO_PROXY", "no_proxy"}},
+	} {
+		value := cliCfg.StringDefault(p.key, "")
+		if ess.IsStrEmpty(value) {
+			continue
+		}
+		set := false
+		for _, n := range p.names {
+			if !ess.IsStrEmpty(getenv(n)) {
+				set = true
+			}
+		}
+		if set {
+			continue
+		}
+		for _, n := range p.names {
+			env[n] = value
+		}
+	}
+	return env
+}
+
+// writeCABundle method writes the first existing system CA bundle along with
+// given PEM into file, file is not rewritten if it's unchanged.
+func writeCABundle(file string, systemFiles []string, pem []byte) error {
+	buf := &bytes.Buffer{}
+	for _, f := range systemFiles {
+		if b, err := ioutil.ReadFile(f); err == nil {
+			_, _ = buf.Write(bytes.TrimSpace(b))
+			_ = buf.WriteByte('\n')
+			break
+		}
+	}
+	_, _ = buf.Write(bytes.TrimSpace(pem))
+	_ = buf.WriteByte('\n')
+
+	if existing, err := ioutil.ReadFile(file); err == nil && bytes.Equal(existing, buf.Bytes()) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(file), permRWXRXRX); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, buf.Bytes(), permRWRR)
+}
+
+func newCLITransport(tlsCfg *tls.Config) *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       tlsCfg,
+	}
+}
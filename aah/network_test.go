@@ -0,0 +1,62 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/test.v0/assert"
+)
+
+func TestNetworkEnv(t *testing.T) {
+	cfg, err := config.ParseString(`network {
+  http_proxy = "http://proxy.example.com:3128"
+  https_proxy = "http://proxy.example.com:3128"
+  no_proxy = "localhost,.example.com"
+}`)
+	assert.Nil(t, err)
+
+	env := networkEnv(cfg, func(name string) string {
+		if name == "https_proxy" {
+			return "http://other.example.com:8080"
+		}
+		return ""
+	})
+	assert.Equal(t, map[string]string{
+		"HTTP_PROXY": "http://proxy.example.com:3128",
+		"http_proxy": "http://proxy.example.com:3128",
+		"NO_PROXY":   "localhost,.example.com",
+		"no_proxy":   "localhost,.example.com",
+	}, env)
+
+	emptyCfg, _ := config.ParseString(``)
+	assert.Equal(t, 0, len(networkEnv(emptyCfg, os.Getenv)))
+
+	c := newHTTPClient(time.Minute)
+	assert.Equal(t, time.Minute, c.Timeout)
+	assert.Nil(t, c.Transport)
+}
+
+func TestNetworkCABundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-network")
+	assert.Nil(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	system := filepath.Join(dir, "ca-certificates.crt")
+	assert.Nil(t, ioutil.WriteFile(system, []byte("-----SYSTEM-----\n\n"), permRWRR))
+
+	file := filepath.Join(dir, "certs", "ca-bundle.pem")
+	assert.Nil(t, writeCABundle(file, []string{filepath.Join(dir, "missing.crt"), system}, []byte("-----CORP-----\n")))
+	b, err := ioutil.ReadFile(file)
+	assert.Nil(t, err)
+	assert.Equal(t, "-----SYSTEM-----\n-----CORP-----\n", string(b))
+}
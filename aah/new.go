@@ -6,11 +6,13 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"go/build"
 	"io"
 	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -19,41 +21,105 @@ import (
 )
 
 const (
-	typeWeb     = "web"
-	typeAPI     = "api"
-	storeCookie = "cookie"
-	storeFile   = "file"
-	aahTmplExt  = ".atmpl"
+	typeWeb       = "web"
+	typeAPI       = "api"
+	typeWebSocket = "websocket"
+	storeCookie   = "cookie"
+	storeFile     = "file"
+	aahTmplExt    = ".atmpl"
 )
 
 var (
-	newCmd = &command{
+	newCmdFlags              = flag.NewFlagSet("new", flag.ContinueOnError)
+	newImportPathFlag        = newCmdFlags.String("importPath", "", "Import path of new aah application")
+	newImportPathShortFlag   = newCmdFlags.String("ip", "", "Import path of new aah application")
+	newTypeFlag              = newCmdFlags.String("type", "", "Application type 'web', 'api' or 'websocket'")
+	newTypeShortFlag         = newCmdFlags.String("t", "", "Application type 'web', 'api' or 'websocket'")
+	newSessionStoreFlag      = newCmdFlags.String("sessionStore", "", "Session store 'cookie' or 'file', applicable to 'web' type")
+	newSessionStoreShortFlag = newCmdFlags.String("ss", "", "Session store 'cookie' or 'file', applicable to 'web' type")
+	newTemplateFlag          = newCmdFlags.String("template", "", "Custom application template, git repository or local directory")
+	newTemplateShortFlag     = newCmdFlags.String("tmpl", "", "Custom application template, git repository or local directory")
+	newAuthorFlag            = newCmdFlags.String("author", "", "Author name for custom template. Default is git 'user.name'")
+	newCmd                   = &command{
 		Name:      "new",
-		UsageLine: "aah new",
-		Short:     "create new aah 'web' or 'api' application (interactive)",
+		UsageLine: "aah new [app-name] [-ip | -importPath] [-t | -type] [-ss | -sessionStore] [-tmpl | -template]",
+		Flags:     newCmdFlags,
+		MaxArgs:   1,
+		Hooks:     []hook{validateGopath},
+		Short:     "create new aah 'web', 'api' or 'websocket' application",
 		Long: `
-'aah new' command is an interactive program to assist you to quick start aah application.
+'aah new' command assists you to quick start aah application. It generates the
+base structure tuned to chosen application type to kick start your development.
 
-Just provide your inputs based on your use case to generate base structure to kick
-start your development.
+Application types:
+    web          controllers, config, routes, views and static files
+    api          controllers, config and routes with stateless session
+    websocket    api structure with WebSocket endpoint
+
+When import path and type are given via flags, application is created without
+prompts. Otherwise it's an interactive program. Import path is inferred from
+current directory and given app name, e.g. within '$GOPATH/src/github.com/user'
+'aah new myapp' infers 'github.com/user/myapp'.
+
+Example(s) short and long flag:
+    aah new
+
+    aah new myapp -t=api
+
+    aah new -ip=github.com/user/myapp -t=web -ss=file
+
+    aah new -importPath=github.com/user/myapp -type=websocket
+
+Custom application template, it can be a git repository or a local directory.
+Files with '.atmpl' extension are rendered with variables such as AppName,
+ImportPath, AppType and Author; other files are copied as-is.
+    aah new myapp -tmpl=github.com/org/aah-template-foo
+
+    aah new -ip=github.com/user/myapp -template=/path/to/template -author="Jane Doe"
 
 Go to https://docs.aahframework.org to learn more and customize your aah application.
 `,
 	}
 	reader = bufio.NewReader(os.Stdin)
+
+	// typeOnlyFiles are the template files applicable only to given
+	// application type.
+	typeOnlyFiles = map[string]string{
+		"app/controllers/websocket.go.atmpl": typeWebSocket,
+	}
 )
 
 func newRun(args []string) {
 	_ = log.SetPattern("%message")
-	log.Info("\nWelcome to interactive way to create your aah application, press ^C to exit :)")
-	log.Info()
-	log.Info("Based on your inputs, aah CLI tool generates the aah application structure")
-	log.Info("for you.")
+
+	importPath := firstNonEmpty(*newImportPathFlag, *newImportPathShortFlag)
+	appType := firstNonEmpty(*newTypeFlag, *newTypeShortFlag)
+	sessionStore := firstNonEmpty(*newSessionStoreFlag, *newSessionStoreShortFlag)
+	appTemplate := firstNonEmpty(*newTemplateFlag, *newTemplateShortFlag)
+	if !ess.IsStrEmpty(appTemplate) {
+		appType = firstNonEmpty(appType, typeWeb)
+	}
+	if ess.IsStrEmpty(importPath) && len(args) > 0 {
+		importPath = inferImportPath(args[0])
+	}
 
 	// Collect data
-	importPath := getImportPath(reader)
-	appType := getAppType(reader)
-	sessionScope, sessionStore := getSessionInfo(reader, appType)
+	var sessionScope string
+	if !ess.IsStrEmpty(importPath) && !ess.IsStrEmpty(appType) {
+		if err := validateNewAppInput(importPath, appType, sessionStore); err != nil {
+			fatalExit(exitUsage, err)
+		}
+		sessionScope, sessionStore = sessionInfo(appType, sessionStore)
+	} else {
+		log.Info("\nWelcome to interactive way to create your aah application, press ^C to exit :)")
+		log.Info()
+		log.Info("Based on your inputs, aah CLI tool generates the aah application structure")
+		log.Info("for you.")
+
+		importPath = getImportPath(reader, importPath)
+		appType = getAppType(reader, appType)
+		sessionScope, sessionStore = getSessionInfo(reader, appType)
+	}
 
 	// Process it
 	appDir := filepath.Join(gosrcDir, filepath.FromSlash(importPath))
@@ -71,8 +137,21 @@ func newRun(args []string) {
 		"TmplDemils":              "{{.}}",
 	}
 
-	if err := createAahApp(appDir, appType, data); err != nil {
-		fatal(err)
+	if ess.IsStrEmpty(appTemplate) {
+		if err := createAahApp(appDir, appType, data); err != nil {
+			fatal(err)
+		}
+	} else {
+		tmplDir, err := resolveAppTemplate(appTemplate)
+		if err != nil {
+			fatalf("Unable to get application template '%s': %s", appTemplate, err)
+		}
+
+		data["ImportPath"] = importPath
+		data["Author"] = firstNonEmpty(*newAuthorFlag, getAuthor())
+		if err = createAppFromTemplate(appDir, tmplDir, data); err != nil {
+			fatal(err)
+		}
 	}
 
 	log.Infof("\nYour aah %s application was created successfully at '%s'", appType, appDir)
@@ -91,14 +170,20 @@ func readInput(reader *bufio.Reader, prompt string) string {
 	return strings.TrimSpace(input)
 }
 
-func getImportPath(reader *bufio.Reader) string {
+func getImportPath(reader *bufio.Reader, defaultImportPath string) string {
+	prompt := "\nEnter your application import path: "
+	if !ess.IsStrEmpty(defaultImportPath) {
+		prompt = fmt.Sprintf("\nEnter your application import path, default is '%s': ", defaultImportPath)
+	}
+
 	var importPath string
 	for {
-		importPath = filepath.ToSlash(readInput(reader, "\nEnter your application import path: "))
+		importPath = filepath.ToSlash(firstNonEmpty(readInput(reader, prompt), defaultImportPath))
 		if !ess.IsStrEmpty(importPath) {
 			if ess.IsImportPathExists(importPath) {
 				log.Errorf("Given import path '%s' is already exists", importPath)
-				importPath = ""
+				importPath, defaultImportPath = "", ""
+				prompt = "\nEnter your application import path: "
 				continue
 			}
 			break
@@ -107,14 +192,17 @@ func getImportPath(reader *bufio.Reader) string {
 	return importPath
 }
 
-func getAppType(reader *bufio.Reader) string {
-	var appType string
+func getAppType(reader *bufio.Reader, appType string) string {
+	if isValidAppType(appType) {
+		return appType
+	}
+
 	for {
-		appType = readInput(reader, "\nChoose your application type (web or api), default is 'web': ")
-		if ess.IsStrEmpty(appType) || appType == typeWeb || appType == typeAPI {
+		appType = readInput(reader, "\nChoose your application type (web, api or websocket), default is 'web': ")
+		if ess.IsStrEmpty(appType) || isValidAppType(appType) {
 			break
 		} else {
-			log.Error("Unsupported new aah application type, choose either 'web or 'api")
+			log.Error("Unsupported new aah application type, choose either 'web', 'api' or 'websocket'")
 			appType = ""
 		}
 	}
@@ -152,16 +240,50 @@ func getSessionInfo(reader *bufio.Reader, appType string) (string, string) {
 	return sessionScope, sessionStore
 }
 
-func createAahApp(appDir, appType string, data map[string]interface{}) error {
-	aahToolsPath, err := build.Import(aahCLIImportPath, "", build.FindOnly)
-	if err != nil {
-		fatal(err)
+// sessionInfo method returns the session scope and store for given
+// application type, used when application is created without prompts.
+func sessionInfo(appType, sessionStore string) (string, string) {
+	if appType != typeWeb {
+		return "stateless", storeCookie
+	}
+	return "stateful", firstNonEmpty(sessionStore, storeCookie)
+}
+
+func validateNewAppInput(importPath, appType, sessionStore string) error {
+	if ess.IsImportPathExists(importPath) {
+		return fmt.Errorf("Given import path '%s' is already exists", importPath)
+	}
+
+	if !isValidAppType(appType) {
+		return fmt.Errorf("Unsupported new aah application type '%s', choose either 'web', 'api' or 'websocket'", appType)
+	}
+
+	if !ess.IsStrEmpty(sessionStore) && sessionStore != storeCookie && sessionStore != storeFile {
+		return fmt.Errorf("Unsupported session store type '%s', choose either 'cookie' or 'file'", sessionStore)
+	}
+	return nil
+}
+
+func isValidAppType(appType string) bool {
+	return appType == typeWeb || appType == typeAPI || appType == typeWebSocket
+}
+
+// inferImportPath method infers the import path for given application name
+// from current directory, if current directory is not within GOPATH then
+// name is returned as-is.
+func inferImportPath(name string) string {
+	pwd, _ := os.Getwd()
+	if !strings.HasPrefix(pwd, gosrcDir+string(filepath.Separator)) {
+		return filepath.ToSlash(name)
 	}
+	return path.Join(importPathRelwd(), filepath.ToSlash(name))
+}
 
-	appTemplatePath := filepath.Join(aahToolsPath.Dir, "app-template")
+func createAahApp(appDir, appType string, data map[string]interface{}) error {
+	appTemplatePath := appTemplateDir()
 
 	// app directory creation
-	if err := ess.MkDirAll(appDir, permRWXRXRX); err != nil {
+	if err := mkDirAll(appDir, permRWXRXRX); err != nil {
 		fatal(err)
 	}
 
@@ -191,15 +313,33 @@ func createAahApp(appDir, appType string, data map[string]interface{}) error {
 	return nil
 }
 
+// appTemplateDir method returns the aah CLI built-in application template
+// directory.
+func appTemplateDir() string {
+	aahToolsPath, err := build.Import(aahCLIImportPath, "", build.FindOnly)
+	if err != nil {
+		fatal(err)
+	}
+	return filepath.Join(aahToolsPath.Dir, "app-template")
+}
+
 func processSection(destDir, srcDir, dir string, data map[string]interface{}) {
 	files, _ := ess.FilesPath(filepath.Join(srcDir, dir), true)
 	for _, v := range files {
+		if appType, found := typeOnlyFiles[filepath.ToSlash(v[len(srcDir)+1:])]; found && appType != data["AppType"] {
+			continue
+		}
 		processFile(destDir, srcDir, v, data)
 	}
 }
 
 func processFile(destDir, srcDir, f string, data map[string]interface{}) {
 	dfPath := getDestPath(destDir, srcDir, f)
+	if isDryRun() {
+		logDryRun("create file %s", dfPath)
+		return
+	}
+
 	dfDir := filepath.Dir(dfPath)
 	if !ess.IsFileExists(dfDir) {
 		_ = ess.MkDirAll(dfDir, permRWXRXRX)
@@ -0,0 +1,577 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+)
+
+var (
+	openAPITypeRegex = regexp.MustCompile(`^[\[\]*]*(?:map\[string\])?[\[\]*]*[A-Za-z_]\w*(?:\.[A-Za-z_]\w*)?$`)
+
+	openAPIBodyMethods = map[string]bool{"POST": true, "PUT": true, "PATCH": true}
+)
+
+type (
+	// openAPIDoc is the OpenAPI 3 document, fields are in the order of
+	// specification.
+	openAPIDoc struct {
+		OpenAPI    string                                  `json:"openapi"`
+		Info       *openAPIInfo                            `json:"info"`
+		Servers    []*openAPIServer                        `json:"servers,omitempty"`
+		Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+		Components *openAPIComponents                      `json:"components,omitempty"`
+	}
+
+	openAPIInfo struct {
+		Title       string `json:"title"`
+		Description string `json:"description,omitempty"`
+		Version     string `json:"version"`
+	}
+
+	openAPIServer struct {
+		URL string `json:"url"`
+	}
+
+	openAPIComponents struct {
+		Schemas         map[string]*openAPISchema         `json:"schemas,omitempty"`
+		SecuritySchemes map[string]*openAPISecurityScheme `json:"securitySchemes,omitempty"`
+	}
+
+	openAPISecurityScheme struct {
+		Type   string `json:"type"`
+		Scheme string `json:"scheme,omitempty"`
+		In     string `json:"in,omitempty"`
+		Name   string `json:"name,omitempty"`
+	}
+
+	openAPIOperation struct {
+		Tags        []string                    `json:"tags,omitempty"`
+		Summary     string                      `json:"summary,omitempty"`
+		Description string                      `json:"description,omitempty"`
+		OperationID string                      `json:"operationId"`
+		Parameters  []*openAPIParameter         `json:"parameters,omitempty"`
+		RequestBody *openAPIRequestBody         `json:"requestBody,omitempty"`
+		Responses   map[string]*openAPIResponse `json:"responses"`
+		Deprecated  bool                        `json:"deprecated,omitempty"`
+		Security    []map[string][]string       `json:"security,omitempty"`
+	}
+
+	openAPIParameter struct {
+		Name        string         `json:"name"`
+		In          string         `json:"in"`
+		Description string         `json:"description,omitempty"`
+		Required    bool           `json:"required,omitempty"`
+		Schema      *openAPISchema `json:"schema"`
+	}
+
+	openAPIRequestBody struct {
+		Description string                       `json:"description,omitempty"`
+		Required    bool                         `json:"required,omitempty"`
+		Content     map[string]*openAPIMediaType `json:"content"`
+	}
+
+	openAPIResponse struct {
+		Description string                       `json:"description"`
+		Content     map[string]*openAPIMediaType `json:"content,omitempty"`
+	}
+
+	openAPIMediaType struct {
+		Schema *openAPISchema `json:"schema"`
+	}
+
+	openAPISchema struct {
+		Ref                  string                    `json:"$ref,omitempty"`
+		Type                 string                    `json:"type,omitempty"`
+		Format               string                    `json:"format,omitempty"`
+		Description          string                    `json:"description,omitempty"`
+		Items                *openAPISchema            `json:"items,omitempty"`
+		Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+		AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+		Required             []string                  `json:"required,omitempty"`
+	}
+
+	// actionDoc is the doc comment of controller action with its
+	// annotations:
+	//
+	//	// Show returns the user of given ID.
+	//	//
+	//	// @summary Get user
+	//	// @tags users
+	//	// @body models.UserInput
+	//	// @response 200 models.User the user
+	//	// @response 404 user not found
+	//	// @deprecated
+	//
+	// Action with '@ignore' is not documented.
+	actionDoc struct {
+		Summary     string
+		Description string
+		Tags        []string
+		Body        string
+		Responses   []*actionDocResponse
+		Deprecated  bool
+		Ignore      bool
+	}
+
+	actionDocResponse struct {
+		Code        string
+		Type        string
+		Description string
+	}
+
+	// appStructType is the struct type of application sources used for
+	// OpenAPI schemas.
+	appStructType struct {
+		Package string
+		Name    string
+		Doc     string
+		Fields  []*appStructField
+	}
+
+	appStructField struct {
+		Name     string
+		Type     string
+		Tag      string
+		Doc      string
+		Embedded bool
+	}
+
+	// openAPIBuilder builds the OpenAPI document, referenced struct types
+	// are added to the component schemas.
+	openAPIBuilder struct {
+		types   map[string]*appStructType
+		schemas map[string]*openAPISchema
+	}
+)
+
+// newOpenAPIDoc method creates the OpenAPI 3 document of the routes, the
+// operations are described with controller action signature and doc comment.
+// Auth scheme names are mapped to their kind of 'config/security.conf'.
+func newOpenAPIDoc(info *openAPIInfo, routes []*appRoute, controllers map[string]*appController,
+	types map[string]*appStructType, authSchemes map[string]string) *openAPIDoc {
+	b := &openAPIBuilder{types: types, schemas: map[string]*openAPISchema{}}
+	doc := &openAPIDoc{OpenAPI: "3.0.3", Info: info, Paths: map[string]map[string]*openAPIOperation{}}
+	security := map[string]*openAPISecurityScheme{}
+
+	for _, r := range routes {
+		var action *appAction
+		ctrlName := r.Controller
+		if c := lookupController(controllers, r.Controller); c != nil {
+			action = c.Action(r.Action)
+			ctrlName = c.Type
+		}
+
+		ad := &actionDoc{}
+		if action != nil {
+			ad = parseActionDoc(action.Doc)
+		}
+		if ad.Ignore {
+			continue
+		}
+
+		op := &openAPIOperation{
+			Tags:        ad.Tags,
+			Summary:     ad.Summary,
+			Description: ad.Description,
+			OperationID: r.Name,
+			Responses:   map[string]*openAPIResponse{},
+			Deprecated:  ad.Deprecated,
+		}
+		if len(op.Tags) == 0 {
+			op.Tags = []string{strings.TrimSuffix(ctrlName, "Controller")}
+		}
+		if routeNameCount(routes, r.Name) > 1 {
+			op.OperationID = r.Name + "_" + strings.ToLower(r.Method)
+		}
+
+		op.Parameters, op.RequestBody = b.actionParams(r, action, ad)
+		for _, resp := range ad.Responses {
+			or := &openAPIResponse{Description: firstNonEmpty(resp.Description, httpStatusText(resp.Code), resp.Code)}
+			if !ess.IsStrEmpty(resp.Type) {
+				or.Content = map[string]*openAPIMediaType{"application/json": {Schema: b.schema(resp.Type, "")}}
+			}
+			op.Responses[resp.Code] = or
+		}
+		if len(op.Responses) == 0 {
+			op.Responses["200"] = &openAPIResponse{Description: "OK"}
+		}
+
+		if !ess.IsStrEmpty(r.Auth) && r.Auth != "anonymous" {
+			op.Security = []map[string][]string{{r.Auth: {}}}
+			security[r.Auth] = openAPISecuritySchemeOf(authSchemes[r.Auth])
+		}
+
+		p := openAPIPath(r.Path)
+		if doc.Paths[p] == nil {
+			doc.Paths[p] = map[string]*openAPIOperation{}
+		}
+		doc.Paths[p][strings.ToLower(r.Method)] = op
+	}
+
+	if len(b.schemas) > 0 || len(security) > 0 {
+		doc.Components = &openAPIComponents{Schemas: b.schemas, SecuritySchemes: security}
+	}
+	return doc
+}
+
+// parseActionDoc method parses the doc comment of the action, summary is the
+// first sentence and description is rest of the comment if not annotated.
+func parseActionDoc(doc string) *actionDoc {
+	ad := &actionDoc{}
+	var text []string
+	for _, line := range strings.Split(doc, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "@") {
+			text = append(text, line)
+			continue
+		}
+
+		fields := strings.Fields(line)
+		arg := strings.TrimSpace(strings.TrimPrefix(line, fields[0]))
+		switch fields[0] {
+		case "@summary":
+			ad.Summary = arg
+		case "@description":
+			ad.Description = arg
+		case "@tags":
+			ad.Tags = splitList(arg)
+		case "@body":
+			ad.Body = arg
+		case "@response", "@success", "@failure":
+			if len(fields) < 2 {
+				continue
+			}
+			resp := &actionDocResponse{Code: fields[1]}
+			rest := fields[2:]
+			if len(rest) > 0 && isOpenAPIType(rest[0]) {
+				resp.Type, rest = rest[0], rest[1:]
+			}
+			resp.Description = strings.Join(rest, " ")
+			ad.Responses = append(ad.Responses, resp)
+		case "@deprecated":
+			ad.Deprecated = true
+		case "@ignore":
+			ad.Ignore = true
+		}
+	}
+
+	body := strings.TrimSpace(strings.Join(text, "\n"))
+	if ess.IsStrEmpty(ad.Summary) && !ess.IsStrEmpty(body) {
+		ad.Summary = body
+		if idx := strings.IndexAny(body, ".\n"); idx >= 0 {
+			ad.Summary = strings.TrimSpace(body[:idx])
+			body = strings.TrimSpace(body[idx+1:])
+		} else {
+			body = ""
+		}
+	}
+	if ess.IsStrEmpty(ad.Description) {
+		ad.Description = body
+	}
+	return ad
+}
+
+// loadAppStructTypes method parses the struct types of the application
+// sources, keyed by 'package.Type'. Test files are skipped.
+func loadAppStructTypes(appDir string) (map[string]*appStructType, error) {
+	types := map[string]*appStructType{}
+	err := filepath.Walk(appDir, func(dir string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return err
+		}
+		if dir != appDir && (strings.HasPrefix(info.Name(), ".") || info.Name() == "testdata") {
+			return filepath.SkipDir
+		}
+
+		fset := token.NewFileSet()
+		pkgs, err := parser.ParseDir(fset, dir, func(f os.FileInfo) bool {
+			return !strings.HasSuffix(f.Name(), "_test.go")
+		}, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		for _, pkg := range pkgs {
+			for _, f := range pkg.Files {
+				inspectStructTypes(types, fset, pkg.Name, f)
+			}
+		}
+		return nil
+	})
+	return types, err
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// actionParams method returns the path, query parameters and request body of
+// the route. Path parameters type is taken from the action parameters, basic
+// type action parameters are query parameters and struct type parameter is
+// the request body of POST, PUT and PATCH.
+func (b *openAPIBuilder) actionParams(r *appRoute, action *appAction, ad *actionDoc) ([]*openAPIParameter, *openAPIRequestBody) {
+	actionParams := map[string]*appActionParam{}
+	if action != nil {
+		for _, p := range action.Params {
+			actionParams[p.Name] = p
+		}
+	}
+
+	var params []*openAPIParameter
+	inPath := map[string]bool{}
+	for _, seg := range strings.Split(r.Path, "/") {
+		if !strings.HasPrefix(seg, ":") && !strings.HasPrefix(seg, "*") {
+			continue
+		}
+		name := seg[1:]
+		inPath[name] = true
+		typ := "string"
+		if ap, found := actionParams[name]; found && isGoBasicType(ap.Type) {
+			typ = ap.Type
+		}
+		params = append(params, &openAPIParameter{Name: name, In: "path", Required: true, Schema: b.schema(typ, "")})
+	}
+
+	var body *openAPIRequestBody
+	if !ess.IsStrEmpty(ad.Body) {
+		body = &openAPIRequestBody{Required: true, Content: map[string]*openAPIMediaType{
+			"application/json": {Schema: b.schema(ad.Body, "")}}}
+	}
+	if action == nil {
+		return params, body
+	}
+
+	for _, ap := range action.Params {
+		if inPath[ap.Name] {
+			continue
+		}
+		elem := strings.TrimPrefix(ap.Type, "[]")
+		if isGoBasicType(elem) {
+			params = append(params, &openAPIParameter{Name: ap.Name, In: "query", Schema: b.schema(ap.Type, "")})
+			continue
+		}
+		if body == nil && openAPIBodyMethods[r.Method] {
+			body = &openAPIRequestBody{Required: true, Content: map[string]*openAPIMediaType{
+				"application/json": {Schema: b.schema(ap.Type, "")}}}
+		}
+	}
+	return params, body
+}
+
+// schema method returns the schema of Go type expression, struct type is
+// added to component schemas and referenced. Unqualified type is looked up
+// in the package qualifier first.
+func (b *openAPIBuilder) schema(typ, pkg string) *openAPISchema {
+	typ = strings.TrimLeft(typ, "*")
+	switch {
+	case strings.HasPrefix(typ, "[]"):
+		if typ == "[]byte" {
+			return &openAPISchema{Type: "string", Format: "byte"}
+		}
+		return &openAPISchema{Type: "array", Items: b.schema(typ[2:], pkg)}
+	case strings.HasPrefix(typ, "map["):
+		if idx := strings.Index(typ, "]"); idx > 0 {
+			return &openAPISchema{Type: "object", AdditionalProperties: b.schema(typ[idx+1:], pkg)}
+		}
+	}
+
+	switch typ {
+	case "string":
+		return &openAPISchema{Type: "string"}
+	case "bool":
+		return &openAPISchema{Type: "boolean"}
+	case "int", "int8", "int16", "int32", "uint", "uint8", "uint16", "uint32":
+		return &openAPISchema{Type: "integer", Format: "int32"}
+	case "int64", "uint64":
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	case "float32":
+		return &openAPISchema{Type: "number", Format: "float"}
+	case "float64":
+		return &openAPISchema{Type: "number", Format: "double"}
+	case "time.Time":
+		return &openAPISchema{Type: "string", Format: "date-time"}
+	case "time.Duration":
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	}
+
+	st := b.lookupType(typ, pkg)
+	if st == nil {
+		return &openAPISchema{}
+	}
+	name := st.Package + "." + st.Name
+	ref := &openAPISchema{Ref: "#/components/schemas/" + name}
+	if _, found := b.schemas[name]; found {
+		return ref
+	}
+
+	// registered before fields, so recursive types are referenced
+	s := &openAPISchema{Type: "object", Description: st.Doc, Properties: map[string]*openAPISchema{}}
+	b.schemas[name] = s
+	b.addFields(s, st, 0)
+	return ref
+}
+
+// addFields method adds the exported fields of struct type into the schema,
+// fields of embedded struct are promoted. Field name is taken from 'json'
+// tag, field with 'validate:"required"' tag is required.
+func (b *openAPIBuilder) addFields(s *openAPISchema, st *appStructType, depth int) {
+	for _, f := range st.Fields {
+		tag := reflect.StructTag(f.Tag)
+		jsonName := strings.Split(tag.Get("json"), ",")[0]
+		if jsonName == "-" {
+			continue
+		}
+
+		if f.Embedded && ess.IsStrEmpty(jsonName) {
+			if et := b.lookupType(strings.TrimLeft(f.Type, "*"), st.Package); et != nil && depth < 10 {
+				b.addFields(s, et, depth+1)
+			}
+			continue
+		}
+		if !ast.IsExported(f.Name) {
+			continue
+		}
+
+		name := firstNonEmpty(jsonName, f.Name)
+		fs := b.schema(f.Type, st.Package)
+		if !ess.IsStrEmpty(f.Doc) && ess.IsStrEmpty(fs.Ref) {
+			fs.Description = f.Doc
+		}
+		s.Properties[name] = fs
+		for _, rule := range strings.Split(tag.Get("validate"), ",") {
+			if rule == "required" {
+				s.Required = append(s.Required, name)
+			}
+		}
+	}
+}
+
+// lookupType method returns the struct type of 'package.Type' or 'Type',
+// unqualified type is looked up in given package first and then any package.
+func (b *openAPIBuilder) lookupType(typ, pkg string) *appStructType {
+	if st, found := b.types[typ]; found {
+		return st
+	}
+	if strings.Contains(typ, ".") {
+		return nil
+	}
+	if st, found := b.types[pkg+"."+typ]; found {
+		return st
+	}
+
+	keys := make([]string, 0, len(b.types))
+	for k := range b.types {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if b.types[k].Name == typ {
+			return b.types[k]
+		}
+	}
+	return nil
+}
+
+func inspectStructTypes(types map[string]*appStructType, fset *token.FileSet, pkgName string, f *ast.File) {
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok || !ts.Name.IsExported() {
+				continue
+			}
+
+			t := &appStructType{Package: pkgName, Name: ts.Name.Name,
+				Doc: strings.TrimSpace(firstNonEmpty(ts.Doc.Text(), genDecl.Doc.Text()))}
+			for _, field := range st.Fields.List {
+				sf := &appStructField{Type: goExprString(fset, field.Type),
+					Doc: strings.TrimSpace(firstNonEmpty(field.Doc.Text(), field.Comment.Text()))}
+				if field.Tag != nil {
+					sf.Tag, _ = strconv.Unquote(field.Tag.Value)
+				}
+				if len(field.Names) == 0 {
+					sf.Embedded = true
+					sf.Name = sf.Type[strings.LastIndex(sf.Type, ".")+1:]
+					t.Fields = append(t.Fields, sf)
+					continue
+				}
+				for _, name := range field.Names {
+					nf := *sf
+					nf.Name = name.Name
+					t.Fields = append(t.Fields, &nf)
+				}
+			}
+			types[pkgName+"."+t.Name] = t
+		}
+	}
+}
+
+// openAPIPath method converts the route path parameters into OpenAPI path
+// template, e.g. '/users/:id' becomes '/users/{id}'.
+func openAPIPath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// openAPISecuritySchemeOf method returns the security scheme of aah auth
+// scheme kind 'basic', 'form', 'oauth2' or 'generic'.
+func openAPISecuritySchemeOf(kind string) *openAPISecurityScheme {
+	switch kind {
+	case "basic":
+		return &openAPISecurityScheme{Type: "http", Scheme: "basic"}
+	case "form", "oauth2":
+		return &openAPISecurityScheme{Type: "apiKey", In: "cookie", Name: "aah_session"}
+	}
+	return &openAPISecurityScheme{Type: "http", Scheme: "bearer"}
+}
+
+func routeNameCount(routes []*appRoute, name string) int {
+	count := 0
+	for _, r := range routes {
+		if r.Name == name {
+			count++
+		}
+	}
+	return count
+}
+
+// isOpenAPIType method reports whether the annotation word is Go type, type
+// of other package is qualified e.g. 'models.User', '[]models.User'.
+func isOpenAPIType(s string) bool {
+	if !openAPITypeRegex.MatchString(s) {
+		return false
+	}
+	elem := strings.TrimLeft(strings.TrimPrefix(strings.TrimLeft(s, "[]*"), "map[string]"), "[]*")
+	return strings.Contains(elem, ".") || isGoBasicType(elem)
+}
+
+func httpStatusText(code string) string {
+	n, err := strconv.Atoi(code)
+	if err != nil {
+		return ""
+	}
+	return http.StatusText(n)
+}
@@ -0,0 +1,261 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestOpenAPIActionDoc(t *testing.T) {
+	ad := parseActionDoc(`Show returns the user of given ID. User is
+looked up in the cache first.
+
+@tags users, admin
+@response 200 models.User the user
+@response 404 user not found
+@response 500
+@deprecated
+`)
+	assert.Equal(t, "Show returns the user of given ID", ad.Summary)
+	assert.Equal(t, "User is\nlooked up in the cache first.", ad.Description)
+	assert.Equal(t, []string{"users", "admin"}, ad.Tags)
+	assert.Equal(t, &actionDocResponse{Code: "200", Type: "models.User", Description: "the user"}, ad.Responses[0])
+	assert.Equal(t, &actionDocResponse{Code: "404", Description: "user not found"}, ad.Responses[1])
+	assert.True(t, ad.Deprecated)
+	assert.False(t, ad.Ignore)
+
+	assert.True(t, isOpenAPIType("[]*models.User"))
+	assert.True(t, isOpenAPIType("map[string]int"))
+	assert.False(t, isOpenAPIType("User"))
+	assert.Equal(t, "/users/{id}/files/{path}", openAPIPath("/users/:id/files/*path"))
+}
+
+func TestOpenAPIDoc(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-openapi")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "controllers"), 0755))
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "models"), 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "controllers", "user.go"), []byte(`package controllers
+
+import "example.com/myapp/app/models"
+
+type UserController struct{}
+
+// Show returns the user.
+//
+// @response 200 models.User
+// @response 404
+func (c *UserController) Show(id int64, fields []string) {}
+
+// Create creates the user.
+func (c *UserController) Create(user *models.User) {}
+
+// Internal is not public.
+//
+// @ignore
+func (c *UserController) Internal() {}
+`), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "models", "user.go"), []byte(`package models
+
+import "time"
+
+type Base struct {
+	ID        int64     `+"`json:\"id\"`"+`
+	CreatedAt time.Time `+"`json:\"created_at\"`"+`
+}
+
+// User is the application user.
+type User struct {
+	Base
+	Name     string            `+"`json:\"name\" validate:\"required\"`"+`
+	Password string            `+"`json:\"-\"`"+`
+	Manager  *User             `+"`json:\"manager,omitempty\"`"+`
+	Labels   map[string]string `+"`json:\"labels\"`"+`
+	// Score of the user
+	Score    float64
+	internal bool
+}
+`), 0644))
+
+	controllers, err := loadAppControllers(filepath.Join(dir, "controllers"))
+	assert.Nil(t, err)
+	types, err := loadAppStructTypes(dir)
+	assert.Nil(t, err)
+
+	routes := []*appRoute{
+		{Domain: "localhost", Name: "user_show", Path: "/users/:id", Method: "GET", Controller: "UserController", Action: "Show", Auth: "form_auth"},
+		{Domain: "localhost", Name: "user_create", Path: "/users", Method: "POST", Controller: "UserController", Action: "Create"},
+		{Domain: "localhost", Name: "internal", Path: "/internal", Method: "GET", Controller: "UserController", Action: "Internal"},
+	}
+	doc := newOpenAPIDoc(&openAPIInfo{Title: "myapp", Version: "1.0.0"}, routes, controllers, types,
+		map[string]string{"form_auth": "form"})
+
+	b, err := json.Marshal(doc)
+	assert.Nil(t, err)
+	var m map[string]interface{}
+	assert.Nil(t, json.Unmarshal(b, &m))
+	assert.Equal(t, "3.0.3", m["openapi"])
+	assert.Nil(t, doc.Paths["/internal"])
+
+	show := doc.Paths["/users/{id}"]["get"]
+	assert.Equal(t, "user_show", show.OperationID)
+	assert.Equal(t, []string{"User"}, show.Tags)
+	assert.Equal(t, "Show returns the user", show.Summary)
+	assert.Equal(t, &openAPIParameter{Name: "id", In: "path", Required: true, Schema: &openAPISchema{Type: "integer", Format: "int64"}}, show.Parameters[0])
+	assert.Equal(t, "query", show.Parameters[1].In)
+	assert.Equal(t, "array", show.Parameters[1].Schema.Type)
+	assert.Equal(t, "#/components/schemas/models.User", show.Responses["200"].Content["application/json"].Schema.Ref)
+	assert.Equal(t, "Not Found", show.Responses["404"].Description)
+	assert.Equal(t, []map[string][]string{{"form_auth": {}}}, show.Security)
+	assert.Equal(t, "cookie", doc.Components.SecuritySchemes["form_auth"].In)
+
+	create := doc.Paths["/users"]["post"]
+	assert.Equal(t, "#/components/schemas/models.User", create.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Equal(t, "OK", create.Responses["200"].Description)
+
+	user := doc.Components.Schemas["models.User"]
+	assert.Equal(t, "User is the application user.", user.Description)
+	assert.Equal(t, []string{"name"}, user.Required)
+	assert.Equal(t, "date-time", user.Properties["created_at"].Format)
+	assert.Equal(t, "#/components/schemas/models.User", user.Properties["manager"].Ref)
+	assert.Equal(t, "string", user.Properties["labels"].AdditionalProperties.Type)
+	assert.Equal(t, "Score of the user", user.Properties["Score"].Description)
+	assert.Nil(t, user.Properties["Password"])
+	assert.Nil(t, user.Properties["internal"])
+	assert.Equal(t, 6, len(user.Properties))
+}
+
+func TestExportPostmanCollection(t *testing.T) {
+	doc := &openAPIDoc{
+		Info: &openAPIInfo{Title: "myapp", Version: "1.0.0"},
+		Paths: map[string]map[string]*openAPIOperation{
+			"/users/{id}": {
+				"get": {Tags: []string{"User"}, Summary: "Show returns the user", OperationID: "user_show",
+					Parameters: []*openAPIParameter{
+						{Name: "id", In: "path", Required: true, Schema: &openAPISchema{Type: "integer", Format: "int64"}},
+						{Name: "fields", In: "query", Schema: &openAPISchema{Type: "array", Items: &openAPISchema{Type: "string"}}},
+					},
+					Security: []map[string][]string{{"basic_auth": {}}}},
+			},
+			"/users": {
+				"post": {Tags: []string{"User"}, OperationID: "user_create",
+					RequestBody: &openAPIRequestBody{Content: map[string]*openAPIMediaType{
+						"application/json": {Schema: &openAPISchema{Ref: "#/components/schemas/models.User"}},
+					}},
+					Security: []map[string][]string{{"form_auth": {}}}},
+			},
+			"/": {"get": {OperationID: "index"}},
+		},
+		Components: &openAPIComponents{
+			Schemas: map[string]*openAPISchema{
+				"models.User": {Type: "object", Properties: map[string]*openAPISchema{
+					"id":      {Type: "integer"},
+					"name":    {Type: "string"},
+					"manager": {Ref: "#/components/schemas/models.User"},
+				}},
+			},
+			SecuritySchemes: map[string]*openAPISecurityScheme{
+				"basic_auth": openAPISecuritySchemeOf("basic"),
+				"form_auth":  openAPISecuritySchemeOf("form"),
+			},
+		},
+	}
+
+	c := newPostmanCollection(doc, "http://localhost:8080/")
+	assert.Equal(t, "myapp", c.Info.Name)
+	assert.Equal(t, postmanSchemaURL, c.Info.Schema)
+	assert.Equal(t, []*postmanVariable{{Key: "baseUrl", Value: "http://localhost:8080"},
+		{Key: "username"}, {Key: "password"}, {Key: "authToken"}}, c.Variable)
+
+	assert.Equal(t, 2, len(c.Item))
+	assert.Equal(t, "User", c.Item[0].Name)
+	assert.Equal(t, "index", c.Item[1].Item[0].Name)
+
+	create := c.Item[0].Item[0].Request
+	assert.Equal(t, "POST", create.Method)
+	assert.Equal(t, "{{baseUrl}}/users", create.URL.Raw)
+	assert.Equal(t, &postmanKV{Key: "Cookie", Value: "aah_session={{authToken}}"}, create.Header[1])
+	var body map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(create.Body.Raw), &body))
+	assert.Equal(t, "sample", body["name"])
+	assert.Equal(t, float64(1), body["id"])
+	assert.NotNil(t, body["manager"])
+
+	show := c.Item[0].Item[1].Request
+	assert.Equal(t, "{{baseUrl}}/users/:id", show.URL.Raw)
+	assert.Equal(t, []string{"users", ":id"}, show.URL.Path)
+	assert.Equal(t, []*postmanKV{{Key: "id", Value: "1"}}, show.URL.Variable)
+	assert.Equal(t, []*postmanKV{{Key: "fields", Value: `"sample"`, Disabled: true}}, show.URL.Query)
+	assert.Equal(t, "basic", show.Auth.Type)
+}
+
+func TestAPIDiff(t *testing.T) {
+	user := &openAPISchema{Ref: "#/components/schemas/models.User"}
+	prev := &openAPIDoc{
+		Paths: map[string]map[string]*openAPIOperation{
+			"/users/{id}": {
+				"get": {Parameters: []*openAPIParameter{
+					{Name: "id", In: "path", Required: true, Schema: &openAPISchema{Type: "integer", Format: "int64"}},
+					{Name: "fields", In: "query", Schema: &openAPISchema{Type: "string"}},
+				}, Responses: map[string]*openAPIResponse{
+					"200": {Content: map[string]*openAPIMediaType{"application/json": {Schema: user}}},
+				}},
+				"delete": {},
+			},
+		},
+		Components: &openAPIComponents{Schemas: map[string]*openAPISchema{
+			"models.User": {Type: "object", Properties: map[string]*openAPISchema{
+				"id": {Type: "integer"}, "name": {Type: "string"}, "email": {Type: "string"},
+			}},
+		}},
+	}
+	cur := &openAPIDoc{
+		Paths: map[string]map[string]*openAPIOperation{
+			"/users/{userId}": {
+				"get": {Parameters: []*openAPIParameter{
+					{Name: "userId", In: "path", Required: true, Schema: &openAPISchema{Type: "string"}},
+					{Name: "page", In: "query", Schema: &openAPISchema{Type: "integer"}},
+				}, Responses: map[string]*openAPIResponse{
+					"200": {Content: map[string]*openAPIMediaType{"application/json": {Schema: user}}},
+				}, Security: []map[string][]string{{"form_auth": {}}}},
+			},
+			"/users": {"post": {RequestBody: &openAPIRequestBody{Content: map[string]*openAPIMediaType{
+				"application/json": {Schema: &openAPISchema{Type: "array", Items: user}},
+			}}}},
+		},
+		Components: &openAPIComponents{Schemas: map[string]*openAPISchema{
+			"models.User": {Type: "object", Properties: map[string]*openAPISchema{
+				"id": {Type: "string"}, "name": {Type: "string"}, "phone": {Type: "string"},
+			}},
+		}},
+	}
+
+	assert.Equal(t, []*apiChange{
+		{Breaking: true, Operation: "DELETE /users/{id}", Message: "operation removed"},
+		{Breaking: true, Operation: "GET /users/{id}", Message: "path parameter 'id' type changed from integer(int64) to string"},
+		{Breaking: true, Operation: "GET /users/{id}", Message: "authentication is required now"},
+		{Breaking: true, Operation: "schema models.User", Message: "property 'email' removed"},
+		{Breaking: true, Operation: "schema models.User", Message: "property 'id' type changed from integer to string"},
+		{Operation: "GET /users/{id}", Message: "query parameter 'fields' removed"},
+		{Operation: "GET /users/{id}", Message: "query parameter 'page' added"},
+		{Operation: "POST /users", Message: "operation added"},
+		{Operation: "schema models.User", Message: "property 'phone' added"},
+	}, diffOpenAPIDocs(prev, cur))
+	assert.Equal(t, "[]models.User", openAPITypeName(cur.Paths["/users"]["post"].RequestBody.Content["application/json"].Schema))
+	assert.Nil(t, diffOpenAPIDocs(prev, prev))
+
+	var buf bytes.Buffer
+	writeAPIChanges(&buf, "v1.0.0", diffOpenAPIDocs(prev, cur)[:1])
+	assert.Equal(t, "BREAKING  DELETE /users/{id}  operation removed\n\n1 breaking, 0 other change(s) since v1.0.0\n", buf.String())
+}
@@ -0,0 +1,548 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+const (
+	packageFormatTarGz = "tar.gz"
+	packageFormatZip   = "zip"
+	packageManifest    = "manifest.json"
+	permRWRR           = 0644
+)
+
+var (
+	packageCmdFlags         = flag.NewFlagSet("package", flag.ContinueOnError)
+	packageImportPathFlag   = packageCmdFlags.String("importPath", "", "Import path of aah application")
+	packageImportPathShort  = packageCmdFlags.String("ip", "", "Import path of aah application")
+	packageOSFlag           = packageCmdFlags.String("os", "", "Target operating system. Default is current GOOS")
+	packageArchFlag         = packageCmdFlags.String("arch", "", "Target architecture. Default is current GOARCH")
+	packageFormatFlag       = packageCmdFlags.String("format", "", "Package format 'tar.gz', 'zip', 'deb' or 'rpm'. Default is 'zip' for windows otherwise 'tar.gz'")
+	packageOutputFlag       = packageCmdFlags.String("output", "", "Output directory of the archive. Default is <app-base>/build")
+	packageOutputShortFlag  = packageCmdFlags.String("o", "", "Output directory of the archive. Default is <app-base>/build")
+	packageProfileFlag      = packageCmdFlags.String("profile", "", "Environment profile name of startup script. Default is 'prod'")
+	packageProfileShortFlag = packageCmdFlags.String("p", "", "Environment profile name of startup script. Default is 'prod'")
+	packageUploadFlag       = packageCmdFlags.String("upload", "", "Upload the package and checksum to object storage, e.g: s3://bucket/path")
+	packageCmd              = &command{
+		Name:      "package",
+		UsageLine: "aah package [-ip | -importPath] [-os] [-arch] [-format] [-o | -output] [-p | -profile] [-upload]",
+		Flags:     packageCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "package aah application as deployable archive",
+		Long: `
+Builds the aah application for the target platform and assembles the
+deployable archive named '<app>-<version>-<os>-<arch>.tar.gz' (or '.zip').
+
+Archive contents:
+    bin/<binary>     application binary (0755)
+    config/          application configuration
+    views/           view templates, if exists
+    static/          static files, if exists
+    i18n/            message files, if exists
+    aah.sh           startup script (0755), 'aah.cmd' for windows
+    manifest.json    app version, aah version, build date and size, SHA256
+                     of every file in the archive
+
+Files matching 'build.excludes' of 'aah.project' are skipped. Directories
+are packaged with 0755 and files with 0644 permissions.
+
+JS and CSS files of 'static' are minified into 'build.assets.dist' and
+packaged in place of the sources, if 'build.assets.enable' is true. Files
+'*.min.js', '*.min.css' and matching 'build.assets.excludes' are packaged
+as-is. PNG and JPEG images are losslessly optimized if
+'build.assets.optimize_images' is true, via 'optipng' and 'jpegoptim' if
+they are in PATH, otherwise PNG is recompressed and JPEG is kept as-is.
+Optimized image is packaged only if it's smaller, savings are reported.
+
+Static files are fingerprinted if 'build.assets.fingerprint' is true, copy
+named with content hash, e.g. 'static/js/app.3f2a9c1e.js' is packaged along
+with the file and 'static/manifest.json' maps the file names to the
+fingerprinted names. Use 'aah generate assets' helper to resolve them in
+templates, fingerprinted files can be served with far-future cache headers.
+
+Package contents are scanned for likely secrets (AWS keys, private keys,
+bearer tokens, GitHub, Slack, Google and Stripe keys) before the archive is
+created, packaging fails if any is found. Line with 'aah:allow-secret'
+comment is skipped. Configure 'package.secret_scan' in 'aah.project':
+    mode         'fail' (default), 'warn' or 'off'
+    patterns     additional regular expressions of secrets
+    allow        regular expressions of allowed findings, e.g. test keys
+    excludes     files skipped, e.g. ["static/vendor/*"]
+
+Format 'deb' and 'rpm' create the native Linux package, application is
+installed into 'package.dir' (default '/opt/<app>') with systemd unit
+'<app>.service' instead of startup script. Config files are marked as
+config files, so the changes are preserved on upgrade. Maintainer scripts
+create the service user, enable and restart the service. 'rpm' requires
+'rpmbuild' command.
+
+Templates are overridable per project, place the file in '<app-base>/packaging':
+    systemd.service                  systemd unit
+    deb/control, deb/conffiles       Debian control files
+    deb/postinst, deb/prerm, deb/postrm
+                                     Debian maintainer scripts
+    rpm/spec                         RPM spec file
+
+Templates are Go 'text/template' with data '.Name', '.Binary', '.Version',
+'.Arch', '.Profile', '.Dir', '.User', '.Maintainer', '.Description',
+'.Homepage', '.DataFiles' and '.ConfFiles'.
+
+Package and its checksum file '<package>.sha256' are uploaded to the object
+storage with '-upload' via the storage CLI, so credentials are resolved from
+the standard environment variables and config files of the CLI:
+    s3://bucket/path           Amazon S3, 'aws' CLI
+    gs://bucket/path           Google Cloud Storage, 'gsutil' CLI
+    az://container/path        Azure Blob Storage, 'az' CLI, storage account
+                               from 'AZURE_STORAGE_ACCOUNT' environment variable
+
+Example(s):
+    aah package
+
+    aah package -os=linux -arch=amd64
+
+    aah package -os=windows -arch=amd64 -format=zip -o=/tmp/artifacts
+
+    aah package -os=linux -arch=amd64 -format=deb
+
+    aah package -upload=s3://myorg-artifacts/myapp
+`,
+	}
+
+	packageDirs = []string{"config", "views", "static", "i18n"}
+)
+
+type packageFile struct {
+	Name    string // slash separated path within archive
+	Src     string // source file, empty when Content is used
+	Content []byte
+	Mode    os.FileMode
+}
+
+type packageManifestInfo struct {
+	Name       string                 `json:"name"`
+	Version    string                 `json:"version"`
+	AahVersion string                 `json:"aah_version"`
+	BuildDate  string                 `json:"build_date"`
+	OS         string                 `json:"os"`
+	Arch       string                 `json:"arch"`
+	Files      []*packageManifestFile `json:"files"`
+}
+
+type packageManifestFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	Mode   string `json:"mode"`
+	SHA256 string `json:"sha256"`
+}
+
+type packageOptions struct {
+	OS        string
+	Arch      string
+	Format    string
+	OutputDir string
+	Profile   string
+}
+
+func packageRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	opts := &packageOptions{
+		OS:        *packageOSFlag,
+		Arch:      *packageArchFlag,
+		Format:    *packageFormatFlag,
+		OutputDir: getNonEmptyAbsPath(*packageOutputFlag, *packageOutputShortFlag),
+		Profile:   firstNonEmpty(*packageProfileFlag, *packageProfileShortFlag),
+	}
+
+	var dest *uploadDest
+	if !ess.IsStrEmpty(*packageUploadFlag) {
+		var err error
+		if dest, err = parseUploadDest(*packageUploadFlag); err != nil {
+			fatalExitf(exitUsage, "%s", err)
+			return
+		}
+	}
+
+	archive, err := createPackage(opts)
+	if err != nil {
+		fatalErr(err)
+		return
+	}
+
+	if dest != nil {
+		if err = uploadPackage(dest, archive); err != nil {
+			fatalf("Unable to upload package: %s", err)
+		}
+	}
+}
+
+// createPackage method builds the application for the target platform and
+// creates the package archive, it returns the archive path. It's the common
+// packaging pipeline of 'aah package' and 'aah deploy'.
+func createPackage(opts *packageOptions) (string, error) {
+	goos := firstNonEmpty(opts.OS, getGOOS())
+	goarch := firstNonEmpty(opts.Arch, getGOARCH())
+	setTargetPlatform(goos, goarch)
+
+	format := opts.Format
+	if ess.IsStrEmpty(format) {
+		format = packageFormatTarGz
+		if goos == "windows" {
+			format = packageFormatZip
+		}
+	}
+	if format != packageFormatTarGz && format != packageFormatZip && !isNativeFormat(format) {
+		return "", newExitErr(exitUsage, fmt.Errorf("unsupported package format '%s', choose one of 'tar.gz', 'zip', 'deb' or 'rpm'", format))
+	}
+	if isNativeFormat(format) && goos != "linux" {
+		return "", newExitErr(exitUsage, fmt.Errorf("'%s' package is supported only for 'linux', not '%s'", format, goos))
+	}
+
+	// same build date for binary and package manifest
+	buildDate := getBuildDate()
+	_ = os.Setenv("AAH_APP_BUILD_DATE", buildDate)
+
+	buildCfg := appProject.BuildCfg
+	log.Infof("Packaging '%s' [%s] for %s/%s", aah.AppName(), aah.AppImportPath(), goos, goarch)
+
+	appBinary, err := compileApp(buildCfg, &compileOptions{Pack: true})
+	if err != nil {
+		return "", err
+	}
+
+	appName := ess.StripExt(filepath.Base(appBinary))
+	version := getAppVersion(appProject.BaseDir, buildCfg)
+	packageName := fmt.Sprintf("%s-%s-%s-%s", appName, version, goos, goarch)
+
+	profile := firstNonEmpty(opts.Profile, "prod")
+	files, err := packageFiles(appProject.BaseDir, appBinary, packageName, profile, goos)
+	if err != nil {
+		return "", err
+	}
+
+	scanner, err := newSecretScanner(buildCfg)
+	if err != nil {
+		return "", newExitErr(exitConfigError, err)
+	}
+	findings, err := scanner.ScanPackage(packageName, files)
+	if err != nil {
+		return "", err
+	}
+	if err = scanner.Report(findings); err != nil {
+		return "", err
+	}
+
+	manifest, err := createPackageManifest(&packageManifestInfo{
+		Name:       appName,
+		Version:    version,
+		AahVersion: aah.Version,
+		BuildDate:  buildDate,
+		OS:         goos,
+		Arch:       goarch,
+	}, packageName, files)
+	if err != nil {
+		return "", err
+	}
+	files = append(files, manifest)
+
+	outputDir := firstNonEmpty(opts.OutputDir, filepath.Join(appProject.BaseDir, "build"))
+	if isNativeFormat(format) {
+		return createNativePackage(outputDir, format, appName, version, goarch, profile, files)
+	}
+
+	archive := filepath.Join(outputDir, packageName+"."+format)
+	if isDryRun() {
+		for _, f := range files {
+			logDryRun("package %s (%s)", f.Name, f.Mode)
+		}
+		logDryRun("create archive %s", archive)
+		return archive, nil
+	}
+
+	endStage := startStage("package")
+	defer endStage()
+	if err = mkDirAll(outputDir, permRWXRXRX); err != nil {
+		return "", err
+	}
+	if err = writePackageArchive(archive, format, files); err != nil {
+		return "", fmt.Errorf("unable to create package archive: %s", err)
+	}
+
+	recordArtifact(archive)
+	log.Infof("Package successful, %d files: %s", len(files), archive)
+	return archive, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// setTargetPlatform method sets the GOOS and GOARCH for 'go build'. Cgo is
+// disabled for cross compile unless it's explicitly enabled.
+func setTargetPlatform(goos, goarch string) {
+	if goos != runtime.GOOS || goarch != runtime.GOARCH {
+		if ess.IsStrEmpty(os.Getenv("CGO_ENABLED")) {
+			_ = os.Setenv("CGO_ENABLED", "0")
+		}
+	}
+	_ = os.Setenv("GOOS", goos)
+	_ = os.Setenv("GOARCH", goarch)
+}
+
+// packageFiles method returns the files of the package, all of them are
+// within the top-level directory of package name.
+func packageFiles(appBaseDir, appBinary, packageName, profile, goos string) ([]*packageFile, error) {
+	cfgExcludes, _ := appProject.BuildCfg.StringList("build.excludes")
+	excludes := ess.Excludes(excludeAndCreateSlice(cfgExcludes, "app"))
+	if err := excludes.Validate(); err != nil {
+		return nil, err
+	}
+
+	assets, err := buildAssets(appProject.BuildCfg, appBaseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []*packageFile{{
+		Name: path.Join(packageName, "bin", filepath.Base(appBinary)),
+		Src:  appBinary,
+		Mode: permRWXRXRX,
+	}}
+
+	for _, dir := range packageDirs {
+		srcDir := filepath.Join(appBaseDir, dir)
+		if !ess.IsFileExists(srcDir) {
+			continue
+		}
+
+		err := filepath.Walk(srcDir, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if p != srcDir && excludes.Match(info.Name()) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if !info.Mode().IsRegular() {
+				return nil
+			}
+
+			rel, _ := filepath.Rel(appBaseDir, p)
+			rel = filepath.ToSlash(rel)
+			files = append(files, &packageFile{
+				Name: path.Join(packageName, rel),
+				Src:  firstNonEmpty(assets[rel], p),
+				Mode: permRWRR,
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fingerprinted, err := fingerprintAssets(appProject.BuildCfg, appBaseDir, packageName, files)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, fingerprinted...)
+
+	// startup script
+	data := map[string]string{
+		"AppName":    ess.StripExt(filepath.Base(appBinary)),
+		"AppProfile": profile,
+		"Backtick":   "`",
+	}
+	script, tmpl := "aah.sh", aahBashStartupTemplate
+	if goos == "windows" {
+		script, tmpl = "aah.cmd", aahCmdStartupTemplate
+	}
+
+	buf := &bytes.Buffer{}
+	if err := renderTmpl(buf, tmpl, data); err != nil {
+		return nil, err
+	}
+	files = append(files, &packageFile{
+		Name:    path.Join(packageName, script),
+		Content: buf.Bytes(),
+		Mode:    permRWXRXRX,
+	})
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files, nil
+}
+
+// createPackageManifest method computes size and SHA256 of package files and
+// returns the 'manifest.json' file of the package. Paths are relative to the
+// package directory.
+func createPackageManifest(info *packageManifestInfo, packageName string, files []*packageFile) (*packageFile, error) {
+	info.Files = make([]*packageManifestFile, 0, len(files))
+	for _, f := range files {
+		content, err := f.Bytes()
+		if err != nil {
+			return nil, err
+		}
+
+		sum := sha256.Sum256(content)
+		info.Files = append(info.Files, &packageManifestFile{
+			Path:   strings.TrimPrefix(f.Name, packageName+"/"),
+			Size:   int64(len(content)),
+			Mode:   fmt.Sprintf("%04o", f.Mode.Perm()),
+			SHA256: hex.EncodeToString(sum[:]),
+		})
+	}
+
+	b, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return &packageFile{
+		Name:    path.Join(packageName, packageManifest),
+		Content: append(b, '\n'),
+		Mode:    permRWRR,
+	}, nil
+}
+
+// writePackageArchive method writes the files into tar.gz or zip archive
+// with the file mode of package file, not the mode of source file.
+func writePackageArchive(archive, format string, files []*packageFile) error {
+	f, err := os.Create(archive)
+	if err != nil {
+		return err
+	}
+
+	p := newProgress("Packaging", len(files))
+	if format == packageFormatZip {
+		err = writeZip(f, files, p)
+	} else {
+		err = writeTarGz(f, files, p)
+	}
+	p.Finish()
+
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		ess.DeleteFiles(archive)
+	}
+	return err
+}
+
+// writeTarGz method writes the files into tar.gz archive, progress is
+// optional.
+func writeTarGz(w io.Writer, files []*packageFile, p *progress) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	now := time.Now()
+	dirs := map[string]bool{}
+	for _, f := range files {
+		p.Start(f.Name)
+		for _, d := range parentDirs(f.Name) {
+			if dirs[d] {
+				continue
+			}
+			dirs[d] = true
+			if err := tw.WriteHeader(&tar.Header{Name: d + "/", Mode: permRWXRXRX,
+				Typeflag: tar.TypeDir, ModTime: now}); err != nil {
+				return err
+			}
+		}
+
+		content, err := f.Bytes()
+		if err != nil {
+			return err
+		}
+
+		if err = tw.WriteHeader(&tar.Header{Name: f.Name, Mode: int64(f.Mode),
+			Size: int64(len(content)), Typeflag: tar.TypeReg, ModTime: now}); err != nil {
+			return err
+		}
+		if _, err = tw.Write(content); err != nil {
+			return err
+		}
+		p.Done()
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// writeZip method writes the files into zip archive, progress is optional.
+func writeZip(w io.Writer, files []*packageFile, p *progress) error {
+	zw := zip.NewWriter(w)
+	for _, f := range files {
+		p.Start(f.Name)
+		content, err := f.Bytes()
+		if err != nil {
+			return err
+		}
+
+		hdr := &zip.FileHeader{Name: f.Name, Method: zip.Deflate}
+		hdr.SetModTime(time.Now())
+		hdr.SetMode(f.Mode)
+		fw, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		if _, err = fw.Write(content); err != nil {
+			return err
+		}
+		p.Done()
+	}
+	return zw.Close()
+}
+
+// Bytes method returns the package file content.
+func (f *packageFile) Bytes() ([]byte, error) {
+	if f.Content != nil {
+		return f.Content, nil
+	}
+	return ioutil.ReadFile(f.Src)
+}
+
+// parentDirs method returns the parent directories of slash separated path,
+// top-level first.
+func parentDirs(name string) []string {
+	var dirs []string
+	for d := path.Dir(name); d != "." && d != "/"; d = path.Dir(d) {
+		dirs = append([]string{d}, dirs...)
+	}
+	return dirs
+}
+
+func init() {
+	packageCmd.Run = packageRun
+}
@@ -0,0 +1,431 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+const (
+	packageFormatDeb = "deb"
+	packageFormatRpm = "rpm"
+
+	// nativePackageDir is the project directory of native package template
+	// overrides, e.g. 'packaging/deb/control', 'packaging/rpm/spec'.
+	nativePackageDir = "packaging"
+)
+
+var (
+	debArch = map[string]string{"amd64": "amd64", "386": "i386", "arm64": "arm64", "arm": "armhf"}
+	rpmArch = map[string]string{"amd64": "x86_64", "386": "i386", "arm64": "aarch64", "arm": "armv7hl"}
+)
+
+// nativePackage holds the data of native package templates.
+type nativePackage struct {
+	Name        string
+	Binary      string
+	Version     string
+	Arch        string
+	Profile     string
+	Dir         string
+	User        string
+	Maintainer  string
+	Description string
+	Homepage    string
+	Unit        string
+	Files       []*packageFile
+	DataFiles   []string
+	ConfFiles   []string
+}
+
+func isNativeFormat(format string) bool {
+	return format == packageFormatDeb || format == packageFormatRpm
+}
+
+// createNativePackage method creates the 'deb' or 'rpm' package from the
+// package files.
+func createNativePackage(outputDir, format, appName, version, goarch, profile string,
+	files []*packageFile) (string, error) {
+	np, err := newNativePackage(appProject.BuildCfg, format, appName, version, goarch, profile, files)
+	if err != nil {
+		return "", newExitErr(exitUsage, err)
+	}
+
+	file := filepath.Join(outputDir, np.FileName(format))
+	if isDryRun() {
+		for _, f := range np.Files {
+			logDryRun("package /%s (%s)", f.Name, f.Mode)
+		}
+		logDryRun("create %s package %s", format, file)
+		return file, nil
+	}
+
+	endStage := startStage("package")
+	defer endStage()
+	if err = mkDirAll(outputDir, permRWXRXRX); err != nil {
+		return "", err
+	}
+	if file, err = writeNativePackage(appProject.BaseDir, outputDir, format, np); err != nil {
+		return "", fmt.Errorf("unable to create %s package: %s", format, err)
+	}
+
+	recordArtifact(file)
+	log.Infof("Package successful, %d files: %s", len(np.Files), file)
+	return file, nil
+}
+
+// newNativePackage method creates the native package data from 'package'
+// section of 'aah.project'. Application is installed into 'package.dir',
+// startup scripts are replaced with systemd unit.
+func newNativePackage(buildCfg *config.Config, format, appName, version, goarch, profile string,
+	files []*packageFile) (*nativePackage, error) {
+	archs := debArch
+	if format == packageFormatRpm {
+		archs = rpmArch
+	}
+	arch, found := archs[goarch]
+	if !found {
+		return nil, fmt.Errorf("architecture '%s' is not supported for '%s' package", goarch, format)
+	}
+
+	if ess.IsStrEmpty(strings.TrimPrefix(strings.TrimSpace(version), "v")) {
+		logWarnf("Application version is empty, '%s' package version is 0.0.0", format)
+	}
+
+	name := strings.ToLower(strings.Replace(appName, "_", "-", -1))
+	np := &nativePackage{
+		Name:        name,
+		Binary:      appName,
+		Version:     nativeVersion(version, format),
+		Arch:        arch,
+		Profile:     profile,
+		Dir:         buildCfg.StringDefault("package.dir", "/opt/"+name),
+		User:        buildCfg.StringDefault("package.user", name),
+		Maintainer:  buildCfg.StringDefault("package.maintainer", name+" <root@localhost>"),
+		Description: buildCfg.StringDefault("package.description", "aah application "+name),
+		Homepage:    buildCfg.StringDefault("package.homepage", ""),
+		Unit:        "lib/systemd/system/" + name + ".service",
+	}
+
+	for _, f := range files {
+		rel := f.Name[strings.Index(f.Name, "/")+1:]
+		if rel == "aah.sh" || rel == "aah.cmd" {
+			continue
+		}
+
+		nf := &packageFile{Name: path.Join(strings.TrimPrefix(np.Dir, "/"), rel),
+			Src: f.Src, Content: f.Content, Mode: f.Mode}
+		np.Files = append(np.Files, nf)
+		if strings.HasPrefix(rel, "config/") {
+			np.ConfFiles = append(np.ConfFiles, "/"+nf.Name)
+		} else {
+			np.DataFiles = append(np.DataFiles, "/"+nf.Name)
+		}
+	}
+
+	return np, nil
+}
+
+// FileName method returns the package file name as per distribution naming
+// convention.
+func (np *nativePackage) FileName(format string) string {
+	if format == packageFormatRpm {
+		return fmt.Sprintf("%s-%s-1.%s.rpm", np.Name, np.Version, np.Arch)
+	}
+	return fmt.Sprintf("%s_%s_%s.deb", np.Name, np.Version, np.Arch)
+}
+
+// render method renders the native package template, project override
+// '<app-base>/packaging/<name>' is used if exists.
+func (np *nativePackage) render(baseDir, name, tmpl string) ([]byte, error) {
+	if override := filepath.Join(baseDir, nativePackageDir, filepath.FromSlash(name)); ess.IsFileExists(override) {
+		b, err := ioutil.ReadFile(override)
+		if err != nil {
+			return nil, err
+		}
+		tmpl = string(b)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := renderTmpl(buf, tmpl, np); err != nil {
+		return nil, fmt.Errorf("native package template '%s': %s", name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeNativePackage method writes the native package file into output
+// directory and returns its path.
+func writeNativePackage(baseDir, outputDir, format string, np *nativePackage) (string, error) {
+	unit, err := np.render(baseDir, "systemd.service", nativeSystemdTemplate)
+	if err != nil {
+		return "", err
+	}
+	np.Files = append(np.Files, &packageFile{Name: np.Unit, Content: unit, Mode: permRWRR})
+	np.DataFiles = append(np.DataFiles, "/"+np.Unit)
+
+	file := filepath.Join(outputDir, np.FileName(format))
+	if format == packageFormatRpm {
+		return file, writeRpm(baseDir, outputDir, np)
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return "", err
+	}
+	err = writeDeb(f, baseDir, np)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		ess.DeleteFiles(file)
+	}
+	return file, err
+}
+
+// writeDeb method writes the Debian package, an 'ar' archive of
+// 'debian-binary', 'control.tar.gz' and 'data.tar.gz'.
+func writeDeb(w io.Writer, baseDir string, np *nativePackage) error {
+	controlFiles := []*packageFile{}
+	for _, c := range []struct {
+		name string
+		tmpl string
+		mode os.FileMode
+	}{
+		{"control", debControlTemplate, permRWRR},
+		{"conffiles", debConfFilesTemplate, permRWRR},
+		{"postinst", nativePostInstallTemplate, permRWXRXRX},
+		{"prerm", nativePreRemoveTemplate, permRWXRXRX},
+		{"postrm", nativePostRemoveTemplate, permRWXRXRX},
+	} {
+		content, err := np.render(baseDir, "deb/"+c.name, c.tmpl)
+		if err != nil {
+			return err
+		}
+		controlFiles = append(controlFiles, &packageFile{Name: c.name, Content: content, Mode: c.mode})
+	}
+
+	control, data := &bytes.Buffer{}, &bytes.Buffer{}
+	if err := writeTarGz(control, controlFiles, nil); err != nil {
+		return err
+	}
+	pg := newProgress("Packaging", len(np.Files))
+	err := writeTarGz(data, np.Files, pg)
+	pg.Finish()
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, "!<arch>\n"); err != nil {
+		return err
+	}
+	for _, e := range []struct {
+		name    string
+		content []byte
+	}{
+		{"debian-binary", []byte("2.0\n")},
+		{"control.tar.gz", control.Bytes()},
+		{"data.tar.gz", data.Bytes()},
+	} {
+		if err := writeArEntry(w, e.name, e.content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeArEntry method writes the 'ar' archive entry, data is padded to even
+// size.
+func writeArEntry(w io.Writer, name string, content []byte) error {
+	hdr := fmt.Sprintf("%-16s%-12d%-6d%-6d%-8o%-10d`\n", name, time.Now().Unix(), 0, 0, permRWRR, len(content))
+	if _, err := io.WriteString(w, hdr); err != nil {
+		return err
+	}
+	if _, err := w.Write(content); err != nil {
+		return err
+	}
+	if len(content)%2 != 0 {
+		_, err := w.Write([]byte("\n"))
+		return err
+	}
+	return nil
+}
+
+// writeRpm method builds the RPM package via 'rpmbuild', files are staged
+// into temporary directory and copied into build root by the spec.
+func writeRpm(baseDir, outputDir string, np *nativePackage) error {
+	rpmbuild, err := exec.LookPath("rpmbuild")
+	if err != nil {
+		return fmt.Errorf("'rpmbuild' is required to create rpm package: %s", err)
+	}
+
+	stageDir, err := ioutil.TempDir("", "aah-rpm-")
+	if err != nil {
+		return err
+	}
+	defer ess.DeleteFiles(stageDir)
+
+	root := filepath.Join(stageDir, "root")
+	for _, f := range np.Files {
+		content, err := f.Bytes()
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(root, filepath.FromSlash(f.Name))
+		if err = ess.MkDirAll(filepath.Dir(dst), permRWXRXRX); err != nil {
+			return err
+		}
+		if err = ioutil.WriteFile(dst, content, f.Mode); err != nil {
+			return err
+		}
+	}
+
+	spec, err := np.render(baseDir, "rpm/spec", rpmSpecTemplate)
+	if err != nil {
+		return err
+	}
+	specFile := filepath.Join(stageDir, np.Name+".spec")
+	if err = ioutil.WriteFile(specFile, spec, permRWRR); err != nil {
+		return err
+	}
+
+	_, err = execCmd(rpmbuild, []string{"-bb",
+		"--define", "_topdir " + filepath.Join(stageDir, "rpmbuild"),
+		"--define", "_rpmdir " + outputDir,
+		"--define", "_build_name_fmt %%{NAME}-%%{VERSION}-%%{RELEASE}.%%{ARCH}.rpm",
+		"--define", "_aah_stage " + root,
+		specFile}, false)
+	return err
+}
+
+// nativeVersion method returns the version as per package format rules, 'v'
+// prefix is removed and '-' of 'git describe' is replaced. Empty version
+// is '0.0.0', deb and rpm tools reject the package without version.
+func nativeVersion(version, format string) string {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if ess.IsStrEmpty(version) {
+		return "0.0.0"
+	}
+	if format == packageFormatRpm {
+		return strings.Replace(version, "-", "_", -1)
+	}
+	return strings.Replace(version, "-", "+", -1)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Native package templates
+//___________________________________
+
+const nativeSystemdTemplate = `[Unit]
+Description={{ .Description }}
+After=network.target
+
+[Service]
+Type=simple
+User={{ .User }}
+WorkingDirectory={{ .Dir }}
+ExecStart={{ .Dir }}/bin/{{ .Binary }} -profile={{ .Profile }}
+Restart=on-failure
+RestartSec=5
+
+[Install]
+WantedBy=multi-user.target
+`
+
+const debControlTemplate = `Package: {{ .Name }}
+Version: {{ .Version }}
+Architecture: {{ .Arch }}
+Maintainer: {{ .Maintainer }}
+Section: web
+Priority: optional{{ if .Homepage }}
+Homepage: {{ .Homepage }}{{ end }}
+Description: {{ .Description }}
+`
+
+const debConfFilesTemplate = `{{ range .ConfFiles }}{{ . }}
+{{ end }}`
+
+const nativePostInstallTemplate = `#!/bin/sh
+set -e
+if ! id -u {{ .User }} >/dev/null 2>&1; then
+  useradd --system --no-create-home --home-dir {{ .Dir }} --shell /bin/false {{ .User }}
+fi
+mkdir -p {{ .Dir }}/logs
+chown -R {{ .User }} {{ .Dir }}/logs
+if command -v systemctl >/dev/null 2>&1; then
+  systemctl daemon-reload
+  systemctl enable {{ .Name }}.service
+  systemctl restart {{ .Name }}.service
+fi
+`
+
+const nativePreRemoveTemplate = `#!/bin/sh
+set -e
+if command -v systemctl >/dev/null 2>&1; then
+  systemctl stop {{ .Name }}.service || true
+  systemctl disable {{ .Name }}.service || true
+fi
+`
+
+const nativePostRemoveTemplate = `#!/bin/sh
+set -e
+if command -v systemctl >/dev/null 2>&1; then
+  systemctl daemon-reload || true
+fi
+`
+
+const rpmSpecTemplate = `Name: {{ .Name }}
+Version: {{ .Version }}
+Release: 1
+Summary: {{ .Description }}
+License: Proprietary{{ if .Homepage }}
+URL: {{ .Homepage }}{{ end }}
+Packager: {{ .Maintainer }}
+BuildArch: {{ .Arch }}
+AutoReqProv: no
+
+%description
+{{ .Description }}
+
+%install
+cp -a %{_aah_stage}/. %{buildroot}/
+
+%post
+if [ "$1" = "1" ]; then
+  if ! id -u {{ .User }} >/dev/null 2>&1; then
+    useradd --system --no-create-home --home-dir {{ .Dir }} --shell /bin/false {{ .User }}
+  fi
+fi
+mkdir -p {{ .Dir }}/logs
+chown -R {{ .User }} {{ .Dir }}/logs
+systemctl daemon-reload >/dev/null 2>&1 || true
+systemctl enable {{ .Name }}.service >/dev/null 2>&1 || true
+systemctl restart {{ .Name }}.service >/dev/null 2>&1 || true
+
+%preun
+if [ "$1" = "0" ]; then
+  systemctl stop {{ .Name }}.service >/dev/null 2>&1 || true
+  systemctl disable {{ .Name }}.service >/dev/null 2>&1 || true
+fi
+
+%postun
+systemctl daemon-reload >/dev/null 2>&1 || true
+
+%files
+{{ range .DataFiles }}{{ . }}
+{{ end }}{{ range .ConfFiles }}%config(noreplace) {{ . }}
+{{ end }}`
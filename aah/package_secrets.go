@@ -0,0 +1,266 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/log.v0"
+)
+
+const (
+	secretScanFail = "fail"
+	secretScanWarn = "warn"
+	secretScanOff  = "off"
+
+	// secretAllowMarker on the line skips the findings of the line.
+	secretAllowMarker = "aah:allow-secret"
+
+	// secretScanMaxSize is the maximum file size scanned, larger files are
+	// assets or data files.
+	secretScanMaxSize = 10 << 20
+)
+
+// secretRules are the built-in patterns of likely secrets, they are high
+// confidence patterns to keep the false positives low.
+var secretRules = []*secretRule{
+	{Name: "AWS access key ID", Regex: regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{Name: "AWS secret access key", Regex: regexp.MustCompile(`(?i)aws.{0,20}secret.{0,20}['"=:\s][0-9a-zA-Z/+]{40}\b`)},
+	{Name: "private key", Regex: regexp.MustCompile(`-----BEGIN ((RSA|DSA|EC|OPENSSH|PGP|ENCRYPTED) )?PRIVATE KEY( BLOCK)?-----`)},
+	{Name: "bearer token", Regex: regexp.MustCompile(`(?i)\bbearer\s+[a-z0-9\-._~+/]{20,}=*`)},
+	{Name: "GitHub token", Regex: regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+	{Name: "Slack token", Regex: regexp.MustCompile(`\bxox[abprs]-[0-9A-Za-z-]{10,}`)},
+	{Name: "Google API key", Regex: regexp.MustCompile(`\bAIza[0-9A-Za-z\-_]{35}\b`)},
+	{Name: "Stripe secret key", Regex: regexp.MustCompile(`\b[rs]k_live_[0-9a-zA-Z]{24,}\b`)},
+}
+
+type (
+	secretRule struct {
+		Name  string
+		Regex *regexp.Regexp
+	}
+
+	// secretScanner scans the package contents for likely secrets, it's
+	// configured via 'package.secret_scan' of 'aah.project'.
+	secretScanner struct {
+		Mode     string
+		Rules    []*secretRule
+		Allow    []*regexp.Regexp
+		Excludes []string
+	}
+
+	secretFinding struct {
+		File  string
+		Line  int
+		Rule  string
+		Match string
+	}
+)
+
+// newSecretScanner method returns the secret scanner of 'aah.project'
+// config, custom patterns are added to the built-in rules.
+func newSecretScanner(cfg *config.Config) (*secretScanner, error) {
+	s := &secretScanner{Mode: cfg.StringDefault("package.secret_scan.mode", secretScanFail), Rules: secretRules}
+	if s.Mode != secretScanFail && s.Mode != secretScanWarn && s.Mode != secretScanOff {
+		return nil, fmt.Errorf("'package.secret_scan.mode' value '%s' is invalid, choose 'fail', 'warn' or 'off'", s.Mode)
+	}
+
+	patterns, _ := cfg.StringList("package.secret_scan.patterns")
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("'package.secret_scan.patterns' value '%s': %s", p, err)
+		}
+		s.Rules = append(s.Rules, &secretRule{Name: "pattern " + p, Regex: re})
+	}
+
+	allow, _ := cfg.StringList("package.secret_scan.allow")
+	for _, p := range allow {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("'package.secret_scan.allow' value '%s': %s", p, err)
+		}
+		s.Allow = append(s.Allow, re)
+	}
+
+	s.Excludes, _ = cfg.StringList("package.secret_scan.excludes")
+	for _, p := range s.Excludes {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("'package.secret_scan.excludes' value '%s': %s", p, err)
+		}
+	}
+	return s, nil
+}
+
+// ScanPackage method scans the package files, file name is relative to the
+// package directory.
+func (s *secretScanner) ScanPackage(packageName string, files []*packageFile) ([]*secretFinding, error) {
+	var findings []*secretFinding
+	for _, f := range files {
+		name := strings.TrimPrefix(f.Name, packageName+"/")
+		if s.Mode == secretScanOff || s.excluded(name) {
+			continue
+		}
+		if f.Content == nil {
+			if info, err := os.Stat(f.Src); err != nil || info.Size() > secretScanMaxSize {
+				continue
+			}
+		}
+
+		content, err := f.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, s.Scan(name, content)...)
+	}
+	return findings, nil
+}
+
+// ScanArchive method scans the files of tar.gz package archive, file name is
+// relative to the package directory.
+func (s *secretScanner) ScanArchive(archive string) ([]*secretFinding, error) {
+	if s.Mode == secretScanOff {
+		return nil, nil
+	}
+
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gr)
+
+	var findings []*secretFinding
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name := hdr.Name
+		if idx := strings.IndexByte(name, '/'); idx >= 0 {
+			name = name[idx+1:]
+		}
+		if hdr.Typeflag != tar.TypeReg || hdr.Size > secretScanMaxSize || s.excluded(name) {
+			continue
+		}
+
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, s.Scan(name, content)...)
+	}
+	return findings, nil
+}
+
+// Scan method returns the findings of the file content, binary content is
+// skipped.
+func (s *secretScanner) Scan(name string, content []byte) []*secretFinding {
+	head := content
+	if len(head) > 8000 {
+		head = head[:8000]
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return nil
+	}
+
+	var findings []*secretFinding
+	for i, line := range strings.Split(string(content), "\n") {
+		if strings.Contains(line, secretAllowMarker) {
+			continue
+		}
+		for _, r := range s.Rules {
+			match := r.Regex.FindString(line)
+			if match == "" || s.allowed(match) {
+				continue
+			}
+			findings = append(findings, &secretFinding{File: name, Line: i + 1, Rule: r.Name, Match: redactSecret(match)})
+		}
+	}
+	return findings
+}
+
+// Report method logs the findings and returns the error if mode is 'fail'.
+func (s *secretScanner) Report(findings []*secretFinding) error {
+	if len(findings) == 0 {
+		return nil
+	}
+
+	logf := log.Errorf
+	if s.Mode == secretScanWarn {
+		logf = logWarnf
+	}
+	for _, f := range findings {
+		logf("%s:%d: likely %s '%s'", f.File, f.Line, f.Rule, f.Match)
+	}
+
+	msg := fmt.Sprintf("%d likely secret(s) found in the package contents. Remove them, mark the line with "+
+		"'%s' or configure 'package.secret_scan' in 'aah.project'", len(findings), secretAllowMarker)
+	if s.Mode == secretScanWarn {
+		logWarn(msg)
+		return nil
+	}
+	return newExitErr(exitSecretError, fmt.Errorf("%s", msg))
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func (s *secretScanner) excluded(name string) bool {
+	for _, p := range s.Excludes {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+		if ok, _ := path.Match(p, path.Base(name)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *secretScanner) allowed(match string) bool {
+	for _, re := range s.Allow {
+		if re.MatchString(match) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactSecret method keeps the first few characters of the secret, so the
+// finding can be identified without printing the secret.
+func redactSecret(s string) string {
+	keep := 4
+	if strings.HasPrefix(s, "-----") {
+		return s
+	}
+	if idx := strings.LastIndexAny(s, " \t"); idx >= 0 && idx < len(s)-1 {
+		keep = idx + 1 + 4
+	}
+	if len(s) <= keep {
+		return s
+	}
+	return s[:keep] + strings.Repeat("*", len(s)-keep)
+}
@@ -0,0 +1,104 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+// uploadDest is the object storage location of 'aah package -upload'.
+type uploadDest struct {
+	Scheme string // s3, gs or az
+	Bucket string // bucket or container name
+	Prefix string // object key prefix, without leading and trailing slash
+}
+
+// parseUploadDest method parses the upload destination URL, i.e.
+// 's3://bucket/path', 'gs://bucket/path' or 'az://container/path'.
+func parseUploadDest(s string) (*uploadDest, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upload destination '%s': %s", s, err)
+	}
+
+	switch u.Scheme {
+	case "s3", "gs", "az":
+	default:
+		return nil, fmt.Errorf("unsupported upload destination '%s', supported schemes are 's3://', 'gs://' and 'az://'", s)
+	}
+	if ess.IsStrEmpty(u.Host) {
+		return nil, fmt.Errorf("bucket name is missing in upload destination '%s'", s)
+	}
+
+	return &uploadDest{Scheme: u.Scheme, Bucket: u.Host, Prefix: strings.Trim(u.Path, "/")}, nil
+}
+
+// Key method returns the object key of the file.
+func (d *uploadDest) Key(file string) string {
+	return path.Join(d.Prefix, filepath.Base(file))
+}
+
+// Command method returns the storage CLI command which uploads the file.
+// CLI resolves the credentials from its standard environment variables
+// and config files.
+func (d *uploadDest) Command(file string) (string, []string) {
+	switch d.Scheme {
+	case "gs":
+		return "gsutil", []string{"cp", file, "gs://" + d.Bucket + "/" + d.Key(file)}
+	case "az":
+		return "az", []string{"storage", "blob", "upload", "--overwrite",
+			"--container-name", d.Bucket, "--name", d.Key(file), "--file", file}
+	default:
+		return "aws", []string{"s3", "cp", file, "s3://" + d.Bucket + "/" + d.Key(file)}
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// uploadPackage method writes the checksum file '<archive>.sha256' and
+// uploads the archive along with checksum file to the destination.
+func uploadPackage(dest *uploadDest, archive string) error {
+	if dest.Scheme == "az" && ess.IsStrEmpty(os.Getenv("AZURE_STORAGE_ACCOUNT")) &&
+		ess.IsStrEmpty(os.Getenv("AZURE_STORAGE_CONNECTION_STRING")) {
+		return errors.New("Azure storage account is required, set 'AZURE_STORAGE_ACCOUNT' environment variable")
+	}
+
+	checksumFile := archive + ".sha256"
+	if isDryRun() {
+		logDryRun("create %s", checksumFile)
+	} else {
+		sum, err := fileSHA256(archive)
+		if err != nil {
+			return err
+		}
+		content := fmt.Sprintf("%s  %s\n", sum, filepath.Base(archive))
+		if err = writeFile(checksumFile, []byte(content), permRWRR); err != nil {
+			return err
+		}
+	}
+
+	endStage := startStage("upload")
+	defer endStage()
+	for _, f := range []string{archive, checksumFile} {
+		name, args := dest.Command(f)
+		log.Infof("Uploading %s to %s://%s/%s", filepath.Base(f), dest.Scheme, dest.Bucket, dest.Key(f))
+		if err := runDeployCmd(os.Stdout, name, args...); err != nil {
+			return fmt.Errorf("%s: %s", name, err)
+		}
+	}
+
+	return nil
+}
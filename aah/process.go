@@ -0,0 +1,224 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"aahframework.org/log.v0"
+)
+
+// appStopTimeout is the time given to aah application to shutdown
+// gracefully, after that it's killed. It's configurable via
+// 'run.shutdown_timeout' in 'aah.project'.
+var appStopTimeout = 10 * time.Second
+
+type (
+	// appProcess is a running aah application binary started by the CLI.
+	appProcess struct {
+		Name    string
+		Index   int
+		Started time.Time
+		Stderr  *lineTail
+
+		cmd     *exec.Cmd
+		exited  chan struct{}
+		err     error
+		stopped bool
+	}
+
+	// prefixWriter writes the prefix at the beginning of every line, writers
+	// sharing the mutex don't interleave their lines.
+	prefixWriter struct {
+		prefix  []byte
+		w       io.Writer
+		mu      *sync.Mutex
+		midLine bool
+	}
+
+	// lineTail keeps the last N lines written to it.
+	lineTail struct {
+		mu      sync.Mutex
+		max     int
+		lines   []string
+		partial []byte
+	}
+)
+
+// startAppProcess method starts the aah application binary with given
+// arguments and additional environment variables 'KEY=VALUE'. Output goes
+// to given writers, nil means CLI stdout and stderr.
+func startAppProcess(binary string, args, env []string, stdout, stderr io.Writer) (*appProcess, error) {
+	p := &appProcess{exited: make(chan struct{}), Started: time.Now()}
+	if isDryRun() {
+		logDryRun("start %s %v", binary, args)
+		close(p.exited)
+		return p, nil
+	}
+
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+
+	p.cmd = exec.Command(binary, args...)
+	p.cmd.Stdout = stdout
+	p.cmd.Stderr = stderr
+	p.cmd.Stdin = os.Stdin
+	setProcAttr(p.cmd)
+	if len(env) > 0 {
+		p.cmd.Env = append(os.Environ(), env...)
+	}
+
+	if err := p.cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Application started, pid %d", p.cmd.Process.Pid)
+	go func() {
+		p.err = p.cmd.Wait()
+		close(p.exited)
+	}()
+	return p, nil
+}
+
+// idleAppProcess method returns the placeholder process for the time
+// application is not running, it never completes.
+func idleAppProcess() *appProcess {
+	return &appProcess{exited: make(chan struct{}), stopped: true}
+}
+
+// Done method returns the channel which is closed when the process exits.
+func (p *appProcess) Done() <-chan struct{} {
+	return p.exited
+}
+
+// Err method returns the process exit result, available after `Done`.
+func (p *appProcess) Err() error {
+	return p.err
+}
+
+// Stop method stops the application gracefully, SIGTERM on Unix and
+// CTRL_BREAK_EVENT on Windows, then waits for the application to exit. It's
+// killed if it doesn't exit within `appStopTimeout`.
+func (p *appProcess) Stop() {
+	if p == nil || p.stopped {
+		return
+	}
+	p.stopped = true
+
+	if p.cmd == nil || p.cmd.Process == nil {
+		return
+	}
+	select {
+	case <-p.exited:
+		return
+	default:
+	}
+
+	log.Debugf("Stopping application gracefully, pid %d", p.cmd.Process.Pid)
+	if err := interruptProcess(p.cmd.Process); err != nil {
+		log.Debugf("Unable to interrupt application, killing it: %s", err)
+		_ = p.cmd.Process.Kill()
+	}
+
+	select {
+	case <-p.exited:
+	case <-time.After(appStopTimeout):
+		logWarnf("Application did not stop within %s, killing it", appStopTimeout)
+		_ = p.cmd.Process.Kill()
+		<-p.exited
+	}
+}
+
+// Signal method sends the signal to the application process.
+func (p *appProcess) Signal(sig os.Signal) error {
+	if p.cmd == nil || p.cmd.Process == nil {
+		return nil
+	}
+	return p.cmd.Process.Signal(sig)
+}
+
+// Stopped method reports whether the process was stopped by the CLI.
+func (p *appProcess) Stopped() bool {
+	return p.stopped
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// prefixWriter methods
+//___________________________________
+
+func newPrefixWriter(prefix string, w io.Writer, mu *sync.Mutex) *prefixWriter {
+	return &prefixWriter{prefix: []byte(prefix), w: w, mu: mu}
+}
+
+func (pw *prefixWriter) Write(b []byte) (int, error) {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
+	buf := &bytes.Buffer{}
+	for _, line := range bytes.SplitAfter(b, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		if !pw.midLine {
+			buf.Write(pw.prefix)
+		}
+		buf.Write(line)
+		pw.midLine = line[len(line)-1] != '\n'
+	}
+
+	if _, err := pw.w.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// lineTail methods
+//___________________________________
+
+func newLineTail(max int) *lineTail {
+	return &lineTail{max: max}
+}
+
+func (t *lineTail) Write(b []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	data := append(t.partial, b...)
+	lines := bytes.Split(data, []byte("\n"))
+	t.partial = append([]byte{}, lines[len(lines)-1]...)
+	for _, l := range lines[:len(lines)-1] {
+		t.lines = append(t.lines, string(bytes.TrimRight(l, "\r")))
+	}
+
+	if len(t.lines) > t.max {
+		t.lines = t.lines[len(t.lines)-t.max:]
+	}
+	return len(b), nil
+}
+
+// Lines method returns the last lines including the incomplete line.
+func (t *lineTail) Lines() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	lines := append([]string{}, t.lines...)
+	if len(t.partial) > 0 {
+		lines = append(lines, string(t.partial))
+	}
+	if len(lines) > t.max {
+		lines = lines[len(lines)-t.max:]
+	}
+	return lines
+}
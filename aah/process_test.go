@@ -0,0 +1,33 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestProcessPrefixWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := newPrefixWriter("[1] ", buf, &sync.Mutex{})
+
+	_, _ = w.Write([]byte("server started\nlisten"))
+	_, _ = w.Write([]byte("ing on :8081\n"))
+	_, _ = w.Write([]byte("\n"))
+	assert.Equal(t, "[1] server started\n[1] listening on :8081\n[1] \n", buf.String())
+}
+
+func TestProcessLineTail(t *testing.T) {
+	tail := newLineTail(2)
+	_, _ = tail.Write([]byte("line 1\nline 2\r\nli"))
+	_, _ = tail.Write([]byte("ne 3\npanic: "))
+	assert.Equal(t, []string{"line 3", "panic: "}, tail.Lines())
+
+	_, _ = tail.Write([]byte("runtime error\n"))
+	assert.Equal(t, []string{"line 3", "panic: runtime error"}, tail.Lines())
+}
@@ -0,0 +1,36 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+// +build !windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+func setProcAttr(cmd *exec.Cmd) {}
+
+// interruptProcess method sends SIGTERM to the process, aah application
+// shuts down gracefully on it.
+func interruptProcess(p *os.Process) error {
+	return p.Signal(syscall.SIGTERM)
+}
+
+// parseSignal method returns the signal for given name, for e.g. 'SIGHUP'.
+func parseSignal(name string) (os.Signal, error) {
+	switch name {
+	case "SIGHUP":
+		return syscall.SIGHUP, nil
+	case "SIGUSR1":
+		return syscall.SIGUSR1, nil
+	case "SIGUSR2":
+		return syscall.SIGUSR2, nil
+	}
+	return nil, fmt.Errorf("unsupported signal '%s', choose either 'SIGHUP', 'SIGUSR1' or 'SIGUSR2'", name)
+}
@@ -0,0 +1,47 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcAttr method starts the process in new process group, so console
+// control event can be sent only to the application.
+func setProcAttr(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// interruptProcess method sends CTRL_BREAK_EVENT to the process group,
+// Go application receives it as 'os.Interrupt'. Windows does not support
+// sending interrupt signal via 'os.Process.Signal'.
+func interruptProcess(p *os.Process) error {
+	dll, err := syscall.LoadDLL("kernel32.dll")
+	if err != nil {
+		return err
+	}
+
+	proc, err := dll.FindProc("GenerateConsoleCtrlEvent")
+	if err != nil {
+		return err
+	}
+
+	if r, _, err := proc.Call(syscall.CTRL_BREAK_EVENT, uintptr(p.Pid)); r == 0 {
+		return err
+	}
+	return nil
+}
+
+// parseSignal method returns error, Windows does not support sending
+// signals to the process.
+func parseSignal(name string) (os.Signal, error) {
+	return nil, fmt.Errorf("signal '%s' is not supported on Windows", name)
+}
@@ -0,0 +1,178 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"aahframework.org/log.v0"
+)
+
+// progressTick is the spinner redraw interval on a terminal.
+const progressTick = 100 * time.Millisecond
+
+var (
+	progressFrames = []string{"|", "/", "-", "\\"}
+
+	// spinning is the progress redrawing the terminal line, nested progress,
+	// e.g. packaging within release build, doesn't spin or log the items.
+	spinning   *progress
+	spinningMu sync.Mutex
+)
+
+// progress reports the long operation with current item, counts, elapsed
+// time and ETA. On a terminal it redraws a spinner line, otherwise it logs
+// plain lines, at most ten for the operation. Nil progress does nothing, so
+// it's optional for the callers.
+//
+//	p := newProgress("Packaging", len(files))
+//	for _, f := range files {
+//		p.Start(f.Name)
+//		...
+//		p.Done()
+//	}
+//	p.Finish()
+type progress struct {
+	sync.Mutex
+	title   string
+	total   int
+	done    int
+	current string
+	start   time.Time
+	w       io.Writer
+	tty     bool
+	quiet   bool
+	logged  int
+	frame   int
+	stop    chan bool
+	stopped chan bool
+}
+
+// newProgress method creates the progress of total items and starts the
+// spinner if stdout is a terminal. JSON log mode logs plain lines.
+func newProgress(title string, total int) *progress {
+	p := &progress{title: title, total: total, start: time.Now(), w: cliStdout, tty: isTerminal(cliStdout) && !isJSONLog()}
+	if !p.tty || isDryRun() {
+		return p
+	}
+
+	spinningMu.Lock()
+	defer spinningMu.Unlock()
+	if spinning != nil {
+		p.quiet = true
+		return p
+	}
+	spinning = p
+	p.stop, p.stopped = make(chan bool), make(chan bool)
+	go p.spin()
+	return p
+}
+
+// Start method marks the item as current one.
+func (p *progress) Start(item string) {
+	if p == nil {
+		return
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	p.current = item
+	if p.stop == nil && !p.quiet && p.shouldLog() {
+		p.logged = p.done
+		log.Infof("%s [%d/%d] %s (%s elapsed)", p.title, p.done+1, p.total, item, formatElapsed(time.Since(p.start)))
+	}
+}
+
+// Done method marks the current item as completed.
+func (p *progress) Done() {
+	if p == nil {
+		return
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	p.done++
+}
+
+// Finish method stops the spinner and logs the summary if all the items
+// are completed, incomplete progress just clears the spinner line.
+func (p *progress) Finish() {
+	if p == nil {
+		return
+	}
+
+	if p.stop != nil {
+		close(p.stop)
+		<-p.stopped
+		p.stop = nil
+		fmt.Fprint(p.w, "\r\033[K")
+		spinningMu.Lock()
+		spinning = nil
+		spinningMu.Unlock()
+	}
+	if p.done == p.total {
+		log.Infof("%s completed, %d item(s) in %s", p.title, p.total, formatElapsed(time.Since(p.start)))
+	}
+}
+
+// Line method returns the progress line, e.g.
+// 'Packaging [3/10] app/controllers/app.go 00:02 ETA 00:05'.
+func (p *progress) Line() string {
+	p.Lock()
+	defer p.Unlock()
+	elapsed := time.Since(p.start)
+	line := fmt.Sprintf("%s [%d/%d] %s %s", p.title, p.done, p.total, p.current, formatElapsed(elapsed))
+	if p.done > 0 && p.done < p.total {
+		eta := time.Duration(int64(elapsed) / int64(p.done) * int64(p.total-p.done))
+		line += " ETA " + formatElapsed(eta)
+	}
+	return line
+}
+
+func (p *progress) spin() {
+	ticker := time.NewTicker(progressTick)
+	defer ticker.Stop()
+	defer close(p.stopped)
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.frame = (p.frame + 1) % len(progressFrames)
+			fmt.Fprintf(p.w, "\r\033[K%s %s", progressFrames[p.frame], p.Line())
+		}
+	}
+}
+
+// shouldLog method reports whether the plain line is logged for the item,
+// items are logged in steps of tenth of the total.
+func (p *progress) shouldLog() bool {
+	step := p.total / 10
+	return p.done == 0 || p.done-p.logged >= step
+}
+
+// isTerminal method reports whether the file is a terminal, 'TERM=dumb' is
+// not considered as a terminal.
+func isTerminal(f *os.File) bool {
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// formatElapsed method formats the duration as 'mm:ss', 'hh:mm:ss' if it's
+// an hour or more.
+func formatElapsed(d time.Duration) string {
+	secs := int64(d / time.Second)
+	if secs >= 3600 {
+		return fmt.Sprintf("%02d:%02d:%02d", secs/3600, secs%3600/60, secs%60)
+	}
+	return fmt.Sprintf("%02d:%02d", secs/60, secs%60)
+}
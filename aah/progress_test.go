@@ -0,0 +1,46 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestProgress(t *testing.T) {
+	assert.Equal(t, "00:00", formatElapsed(0))
+	assert.Equal(t, "01:05", formatElapsed(65*time.Second))
+	assert.Equal(t, "01:01:01", formatElapsed(3661*time.Second))
+
+	p := &progress{title: "Packaging", total: 4, start: time.Now().Add(-4 * time.Second)}
+	p.Start("app/controllers/app.go")
+	p.Done()
+	p.Done()
+	line := p.Line()
+	assert.True(t, strings.HasPrefix(line, "Packaging [2/4] app/controllers/app.go 00:04"))
+	assert.True(t, strings.HasSuffix(line, "ETA 00:04"))
+
+	p.Done()
+	p.Done()
+	assert.False(t, strings.Contains(p.Line(), "ETA"))
+	p.Finish()
+
+	// plain lines are logged in steps of tenth of the total
+	p = &progress{title: "Getting dependencies", total: 100}
+	assert.True(t, p.shouldLog())
+	p.done = 9
+	assert.False(t, p.shouldLog())
+	p.done = 10
+	assert.True(t, p.shouldLog())
+
+	// nil progress is no-op
+	var np *progress
+	np.Start("github.com/go-aah/aah")
+	np.Done()
+	np.Finish()
+}
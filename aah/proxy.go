@@ -0,0 +1,189 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/log.v0"
+)
+
+const (
+	proxyStartupWait   = 30 * time.Second
+	proxyDefaultHoldTO = "30s"
+)
+
+// devProxy is the reverse proxy in front of the application during
+// 'aah run'. It holds the incoming requests while the application is
+// recompiling or restarting, instead of failing with connection refused.
+// Requests are distributed round-robin when multiple instances are running.
+type devProxy struct {
+	Port     string
+	AppPorts []string
+	Docs     *swaggerUI
+
+	holdTimeout time.Duration
+	mu          sync.RWMutex
+	ready       chan struct{}
+	next        uint32
+	rp          *httputil.ReverseProxy
+}
+
+// newDevProxy method returns the proxy if it's enabled in 'aah.project',
+// otherwise nil. Proxy listens on the application 'server.port' and the
+// application instances are started on free ports.
+//
+//	proxy {
+//	  enable = true
+//	  hold_timeout = "30s"
+//	}
+func newDevProxy(cfg *config.Config, appPort string, instances int) (*devProxy, error) {
+	if !cfg.BoolDefault("proxy.enable", true) {
+		return nil, nil
+	}
+
+	holdTimeout, err := time.ParseDuration(cfg.StringDefault("proxy.hold_timeout", proxyDefaultHoldTO))
+	if err != nil {
+		return nil, fmt.Errorf("'proxy.hold_timeout' is invalid: %s", err)
+	}
+
+	var appPorts []string
+	for i := 0; i < instances; i++ {
+		port, err := freePort()
+		if err != nil {
+			return nil, err
+		}
+		appPorts = append(appPorts, port)
+	}
+
+	return newDevProxyWithPorts(appPort, appPorts, holdTimeout), nil
+}
+
+func newDevProxyWithPorts(port string, appPorts []string, holdTimeout time.Duration) *devProxy {
+	p := &devProxy{
+		Port:        port,
+		AppPorts:    appPorts,
+		holdTimeout: holdTimeout,
+		ready:       make(chan struct{}),
+	}
+	p.rp = &httputil.ReverseProxy{Director: p.director}
+	return p
+}
+
+// Start method starts listening on the proxy port, it's non-blocking.
+// Requests are held until `Release` is called.
+func (p *devProxy) Start() error {
+	l, err := net.Listen("tcp", ":"+p.Port)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := http.Serve(l, p); err != nil {
+			log.Errorf("Proxy server stopped: %s", err)
+		}
+	}()
+	return nil
+}
+
+// Hold method holds the incoming requests until `Release` is called.
+func (p *devProxy) Hold() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	select {
+	case <-p.ready:
+		p.ready = make(chan struct{})
+	default:
+		// already on hold
+	}
+}
+
+// Release method waits for the application instances to accept
+// connections and then lets the held requests through.
+func (p *devProxy) Release() {
+	for _, port := range p.AppPorts {
+		if !waitForPort("127.0.0.1:"+port, proxyStartupWait) {
+			logWarnf("Application is not accepting connections on port %s", port)
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	select {
+	case <-p.ready:
+	default:
+		close(p.ready)
+	}
+}
+
+// ServeHTTP method proxies the request to the application once it's ready.
+// Swagger UI is served by the proxy itself, if it's enabled.
+func (p *devProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if p.Docs != nil && p.Docs.Match(r.URL.Path) {
+		p.Docs.ServeHTTP(w, r)
+		return
+	}
+
+	p.mu.RLock()
+	ready := p.ready
+	p.mu.RUnlock()
+
+	select {
+	case <-ready:
+	case <-time.After(p.holdTimeout):
+		http.Error(w, "aah run: application is not ready, rebuild is in progress or failed",
+			http.StatusServiceUnavailable)
+		return
+	}
+
+	p.rp.ServeHTTP(w, r)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// director method routes the request to next application instance.
+func (p *devProxy) director(r *http.Request) {
+	n := atomic.AddUint32(&p.next, 1)
+	r.URL.Scheme = "http"
+	r.URL.Host = "127.0.0.1:" + p.AppPorts[int(n-1)%len(p.AppPorts)]
+	if _, ok := r.Header["User-Agent"]; !ok {
+		// explicitly disable User-Agent so it's not set to default value
+		r.Header.Set("User-Agent", "")
+	}
+}
+
+// freePort method returns the available TCP port on localhost.
+func freePort() (string, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = l.Close() }()
+
+	_, port, err := net.SplitHostPort(l.Addr().String())
+	return port, err
+}
+
+// waitForPort method waits until the address accepts TCP connections.
+func waitForPort(addr string, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if c, err := net.DialTimeout("tcp", addr, time.Second); err == nil {
+			_ = c.Close()
+			return true
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return false
+}
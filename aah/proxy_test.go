@@ -0,0 +1,71 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestProxyHoldRelease(t *testing.T) {
+	app := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer app.Close()
+
+	target, _ := url.Parse(app.URL)
+	_, port, _ := net.SplitHostPort(target.Host)
+	p := newDevProxyWithPorts("", []string{port}, 50*time.Millisecond)
+
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	p.Release()
+	w = httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ok", w.Body.String())
+
+	p.Hold()
+	w = httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestProxySwaggerUI(t *testing.T) {
+	p := newDevProxyWithPorts("", []string{"0"}, 10*time.Millisecond)
+	p.Docs = &swaggerUI{Path: "/_docs", spec: func() (*openAPIDoc, error) {
+		return &openAPIDoc{OpenAPI: "3.0.3", Info: &openAPIInfo{Title: "myapp", Version: "1.0.0"}}, nil
+	}}
+
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest("GET", "/_docs/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.Contains(w.Body.String(), `url: "\/_docs\/openapi.json"`))
+
+	w = httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest("GET", "/_docs/openapi.json", nil))
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.True(t, strings.Contains(w.Body.String(), `"title": "myapp"`))
+
+	w = httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest("POST", "/_docs", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	// other paths are proxied to the application, it's not ready
+	w = httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest("GET", "/_docsx", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	assert.Nil(t, newSwaggerUI(nil, "", "prod"))
+}
@@ -0,0 +1,248 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+const releaseChecksumsFile = "checksums.txt"
+
+var releaseCmd = &command{
+	Name:      "release",
+	UsageLine: "aah release [-bump] [-version] [-platforms] [-p | -profile] [-channel] [-publish] | <subcommand> [arguments]",
+	Flags:     releaseCmdFlags,
+	Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+	Short:     "release aah application and publish release artifacts",
+	Long: `
+Releases the application end-to-end, driven by 'release' section of
+'aah.project':
+
+    1. bumps the version of previous tag '<tag_prefix><version>' by 'bump'
+       part of 'build.version_scheme', or the '-version' is used
+    2. adds commit subjects since previous tag into 'changelog' file and
+       sets 'build.version' of 'aah.project', commits them as 'Release <tag>'
+    3. creates the annotated tag, signed if 'sign.enable' is true
+    4. packages the application for each of 'platforms' (refer 'aah package')
+       into '<app-base>/build', '<app-base>/build/<channel>' for pre-release
+    5. writes 'checksums.txt', release manifest and its signature
+       'checksums.txt.asc' via 'gpg' if 'sign.enable' is true
+    6. with '-publish' (or 'publish = true') pushes the commit and tag to
+       'git_remote' and publishes the GitHub release of 'github'
+
+With '-channel', pre-release version '<version>-<channel>.<N>' is released,
+e.g. 'v1.4.0-beta.3'. N is incremented per version and channel, and the
+upcoming version of previous pre-release is kept. Manifest is written as
+'<channel>.json' and GitHub release is marked as pre-release.
+
+Working tree must be clean. Without publish, release can be pushed and
+published later via 'aah release publish'.
+
+Subcommands publish the artifacts of the current git tag along with
+checksums and changelog.
+
+Example:
+    aah release
+
+    aah release -bump=minor -platforms=linux/amd64,darwin/arm64 -publish
+
+    aah -dry-run release -version=2.0.0-rc.1
+
+    aah release -channel=beta -publish
+
+    aah release publish -github=myorg/myapp
+
+    aah release brew -tap=../homebrew-tap
+
+    aah release manifest -url=https://downloads.example.com/myapp/v1.0.0
+`,
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// gitOutput method runs the read-only git command in given directory and
+// returns the trimmed output. It runs in dry-run mode too.
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), strings.TrimSpace(string(output)))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// releaseTag method returns the git tag of current commit.
+func releaseTag(baseDir string) (string, error) {
+	tag, err := gitOutput(baseDir, "describe", "--tags", "--exact-match", "HEAD")
+	if err != nil {
+		return "", errors.New("current commit is not tagged, create the release tag first")
+	}
+	return tag, nil
+}
+
+// releaseArtifacts method returns the package archives of given version in
+// the directory, i.e. '<app>-<version>-<os>-<arch>.<ext>'. Deploy artifacts
+// of providers are not release artifacts.
+func releaseArtifacts(dir, version string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var artifacts []string
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.Contains(name, "-"+version+"-") || strings.Contains(name, "-deploy.") {
+			continue
+		}
+		if isReleaseArtifact(name) {
+			artifacts = append(artifacts, filepath.Join(dir, name))
+		}
+	}
+
+	if len(artifacts) == 0 {
+		return nil, fmt.Errorf("no package archives found for version '%s' in '%s', run 'aah package' first", version, dir)
+	}
+	sort.Strings(artifacts)
+	return artifacts, nil
+}
+
+func isReleaseArtifact(name string) bool {
+	for _, ext := range []string{"." + packageFormatTarGz, "." + packageFormatZip} {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// artifactPlatform method returns the '<os>-<arch>' platform and archive
+// extension of the package archive name '<app>-<version>-<os>-<arch>.<ext>'.
+func artifactPlatform(name, version string) (string, string) {
+	idx := strings.Index(name, "-"+version+"-")
+	if idx < 0 {
+		return "", ""
+	}
+	for _, ext := range []string{packageFormatTarGz, packageFormatZip} {
+		if strings.HasSuffix(name, "."+ext) {
+			return strings.TrimSuffix(name[idx+len(version)+2:], "."+ext), ext
+		}
+	}
+	return "", ""
+}
+
+// releaseChecksums method returns the SHA256 checksums of the artifacts in
+// 'sha256sum' output format, same as aah CLI release 'checksums.txt'.
+func releaseChecksums(artifacts []string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	for _, a := range artifacts {
+		sum, err := fileSHA256(a)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(buf, "%s  %s\n", sum, filepath.Base(a))
+	}
+	return buf.Bytes(), nil
+}
+
+func fileSHA256(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer ess.CloseQuietly(f)
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// releaseChangelog method returns the changelog from given file otherwise
+// commit subjects since the previous tag.
+func releaseChangelog(baseDir, tag, file string) (string, error) {
+	if !ess.IsStrEmpty(file) {
+		b, err := ioutil.ReadFile(file)
+		return string(b), err
+	}
+
+	rng := tag
+	if prev, err := gitOutput(baseDir, "describe", "--tags", "--abbrev=0", tag+"^"); err == nil {
+		rng = prev + ".." + tag
+	}
+	return gitOutput(baseDir, "log", "--no-merges", "--pretty=format:- %s", rng)
+}
+
+// writeReleaseFiles method writes 'checksums.txt' of the artifacts, release
+// channel manifest if base URL is given and checksums signature if
+// 'release.sign.enable' is true. It returns the artifacts along with them.
+func writeReleaseFiles(buildCfg *config.Config, dir, tag, baseURL string, artifacts []string) ([]string, error) {
+	checksums, err := releaseChecksums(artifacts)
+	if err != nil {
+		return nil, err
+	}
+	checksumsFile := filepath.Join(dir, releaseChecksumsFile)
+	if err = writeFile(checksumsFile, checksums, permRWRR); err != nil {
+		return nil, err
+	}
+	files := append(append([]string{}, artifacts...), checksumsFile)
+
+	if !ess.IsStrEmpty(baseURL) {
+		manifestFile, err := writeReleaseManifest(dir, "", tag, baseURL, artifacts)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, manifestFile)
+	}
+
+	if buildCfg.BoolDefault("release.sign.enable", false) {
+		sigFile, err := signReleaseFile(buildCfg.StringDefault("release.sign.key", ""), checksumsFile)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, sigFile)
+	}
+	return files, nil
+}
+
+// signReleaseFile method creates the ASCII armored detached signature
+// '<file>.asc' via 'gpg', key is the default key of gpg if not given.
+func signReleaseFile(key, file string) (string, error) {
+	sigFile := file + ".asc"
+	args := []string{"--batch", "--yes", "--armor", "--detach-sign", "--output", sigFile}
+	if !ess.IsStrEmpty(key) {
+		args = append(args, "--local-user", key)
+	}
+	args = append(args, file)
+
+	if _, err := exec.LookPath("gpg"); err != nil && !isDryRun() {
+		return "", errors.New("'gpg' command is required to sign the release, install GnuPG")
+	}
+	buf := &bytes.Buffer{}
+	if err := runDeployCmd(buf, "gpg", args...); err != nil {
+		return "", fmt.Errorf("gpg: %s %s", err, strings.TrimSpace(buf.String()))
+	}
+	log.Infof("Signed %s", filepath.Base(file))
+	return sigFile, nil
+}
@@ -0,0 +1,225 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+var (
+	releaseBrewCmdFlags  = flag.NewFlagSet("brew", flag.ContinueOnError)
+	releaseBrewTapFlag   = releaseBrewCmdFlags.String("tap", "", "Tap directory, formula is written into '<tap>/Formula'. Default is stdout")
+	releaseBrewURLFlag   = releaseBrewCmdFlags.String("url", "", "Download base URL of the artifacts. Default is 'release.download_url' or GitHub release")
+	releaseBrewTagFlag   = releaseBrewCmdFlags.String("tag", "", "Release tag. Default is git tag of current commit")
+	releaseBrewDirFlag   = releaseBrewCmdFlags.String("dir", "", "Directory of package archives. Default is <app-base>/build, <app-base>/build/<channel> for pre-release")
+	releaseBrewForceFlag = releaseBrewCmdFlags.Bool("force", false, "Overwrite the existing formula")
+	releaseBrewCmd       = &command{
+		Name:      "brew",
+		UsageLine: "aah release brew [-tap] [-url] [-tag] [-dir] [-force]",
+		Flags:     releaseBrewCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "generate Homebrew formula of the release",
+		Long: `
+Generates the Homebrew formula of the release from 'darwin' and 'linux'
+package archives '<app>-<tag>-<os>-<arch>.tar.gz' with URL, SHA256 and
+version. Formula installs the package into 'libexec' and the wrapper
+script into 'bin', so the application runs from its package directory.
+
+Artifact URL is '<url>/<archive>', default URL is 'release.download_url'
+otherwise GitHub release download URL of 'release.github' in 'aah.project'.
+
+Example:
+    aah release brew
+
+    aah release brew -tap=../homebrew-tap
+
+    aah release brew -url=https://downloads.example.com/myapp/v1.0.0
+`,
+	}
+)
+
+type (
+	brewFormula struct {
+		Class    string
+		Name     string
+		Binary   string
+		Desc     string
+		Homepage string
+		Version  string
+		MacOS    []*brewArtifact
+		Linux    []*brewArtifact
+	}
+
+	brewArtifact struct {
+		Arm    bool
+		URL    string
+		SHA256 string
+	}
+)
+
+func releaseBrewRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	buildCfg := appProject.BuildCfg
+	tag := *releaseBrewTagFlag
+	if ess.IsStrEmpty(tag) {
+		var err error
+		if tag, err = releaseTag(appProject.BaseDir); err != nil {
+			fatal(err)
+			return
+		}
+	}
+
+	repo := buildCfg.StringDefault("release.github", "")
+	baseURL, err := releaseDownloadURL(buildCfg, *releaseBrewURLFlag, repo, tag)
+	if err != nil {
+		fatalExit(exitUsage, err)
+		return
+	}
+
+	dir := firstNonEmpty(getNonEmptyAbsPath(*releaseBrewDirFlag, ""), releaseDir(appProject.BaseDir, tag))
+	artifacts, err := releaseArtifacts(dir, tag)
+	if err != nil {
+		fatal(err)
+		return
+	}
+
+	homepage := buildCfg.StringDefault("package.homepage", "")
+	if ess.IsStrEmpty(homepage) && !ess.IsStrEmpty(repo) {
+		homepage = "https://github.com/" + repo
+	}
+
+	binary := buildCfg.StringDefault("build.binary_name", strings.Replace(aah.AppName(), " ", "_", -1))
+	formula, err := newBrewFormula(binary, tag, baseURL, artifacts)
+	if err != nil {
+		fatal(err)
+		return
+	}
+	formula.Desc = buildCfg.StringDefault("package.description", "aah application "+formula.Name)
+	formula.Homepage = homepage
+
+	if ess.IsStrEmpty(*releaseBrewTapFlag) {
+		if err = renderTmpl(os.Stdout, brewFormulaTemplate, formula); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	file := filepath.Join(*releaseBrewTapFlag, "Formula", formula.Name+".rb")
+	if err = writeGenFile(file, brewFormulaTemplate, formula, *releaseBrewForceFlag); err != nil {
+		fatal(err)
+	}
+}
+
+// newBrewFormula method creates the formula from 'darwin' and 'linux'
+// 'tar.gz' package archives.
+func newBrewFormula(binary, tag, baseURL string, artifacts []string) (*brewFormula, error) {
+	name := strings.ToLower(strings.Replace(binary, "_", "-", -1))
+	formula := &brewFormula{
+		Class:   toExported(name),
+		Name:    name,
+		Binary:  binary,
+		Version: strings.TrimPrefix(tag, "v"),
+	}
+
+	for _, a := range artifacts {
+		base := filepath.Base(a)
+		platform, ext := artifactPlatform(base, tag)
+		if ext != packageFormatTarGz {
+			continue
+		}
+
+		parts := strings.SplitN(platform, "-", 2)
+		if len(parts) != 2 || (parts[1] != "amd64" && parts[1] != "arm64") {
+			continue
+		}
+
+		sum, err := fileSHA256(a)
+		if err != nil {
+			return nil, err
+		}
+		artifact := &brewArtifact{Arm: parts[1] == "arm64", URL: strings.TrimSuffix(baseURL, "/") + "/" + base, SHA256: sum}
+		switch parts[0] {
+		case "darwin":
+			formula.MacOS = append(formula.MacOS, artifact)
+		case "linux":
+			formula.Linux = append(formula.Linux, artifact)
+		}
+	}
+
+	if len(formula.MacOS) == 0 && len(formula.Linux) == 0 {
+		return nil, errors.New("no 'darwin' or 'linux' tar.gz package archives found, run 'aah package' first")
+	}
+	return formula, nil
+}
+
+// BrewPlatform method renders the artifacts of the platform, CPU check is
+// added only if both 'amd64' and 'arm64' are available.
+func (b *brewFormula) BrewPlatform(artifacts []*brewArtifact) string {
+	buf := &bytes.Buffer{}
+	if len(artifacts) == 1 {
+		fmt.Fprintf(buf, "    url \"%s\"\n    sha256 \"%s\"\n", artifacts[0].URL, artifacts[0].SHA256)
+		return buf.String()
+	}
+
+	for i, a := range artifacts {
+		cond := "if Hardware::CPU.intel?"
+		if a.Arm {
+			cond = "if Hardware::CPU.arm?"
+		}
+		if i > 0 {
+			cond = "els" + cond
+		}
+		fmt.Fprintf(buf, "    %s\n      url \"%s\"\n      sha256 \"%s\"\n", cond, a.URL, a.SHA256)
+	}
+	buf.WriteString("    end\n")
+	return buf.String()
+}
+
+func init() {
+	releaseBrewCmd.Run = releaseBrewRun
+	releaseCmd.Subcommands = append(releaseCmd.Subcommands, releaseBrewCmd)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Generator templates
+//___________________________________
+
+const brewFormulaTemplate = `# Generated by 'aah release brew'.
+class {{ .Class }} < Formula
+  desc "{{ .Desc }}"{{ if .Homepage }}
+  homepage "{{ .Homepage }}"{{ end }}
+  version "{{ .Version }}"
+{{ if .MacOS }}
+  on_macos do
+{{ .BrewPlatform .MacOS }}  end
+{{ end }}{{ if .Linux }}
+  on_linux do
+{{ .BrewPlatform .Linux }}  end
+{{ end }}
+  def install
+    libexec.install Dir["*"]
+    (bin/"{{ .Binary }}").write <<~EOS
+      #!/bin/sh
+      cd "#{libexec}" && exec "#{libexec}/bin/{{ .Binary }}" "$@"
+    EOS
+  end
+
+  test do
+    assert_predicate libexec/"bin/{{ .Binary }}", :executable?
+  end
+end
+`
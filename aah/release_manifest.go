@@ -0,0 +1,237 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+var (
+	releaseManifestCmdFlags    = flag.NewFlagSet("manifest", flag.ContinueOnError)
+	releaseManifestChannelFlag = releaseManifestCmdFlags.String("channel", "", "Release channel name. Default is 'latest', pre-release identifier for pre-release tags e.g: beta")
+	releaseManifestURLFlag     = releaseManifestCmdFlags.String("url", "", "Download base URL of the artifacts. Default is 'release.download_url' or GitHub release")
+	releaseManifestTagFlag     = releaseManifestCmdFlags.String("tag", "", "Release tag. Default is git tag of current commit")
+	releaseManifestDirFlag     = releaseManifestCmdFlags.String("dir", "", "Directory of package archives. Default is <app-base>/build, <app-base>/build/<channel> for pre-release")
+	releaseManifestCmd         = &command{
+		Name:      "manifest",
+		UsageLine: "aah release manifest [-channel] [-url] [-tag] [-dir]",
+		Flags:     releaseManifestCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "generate release channel manifest",
+		Long: `
+Generates the release channel manifest '<channel>.json' into the package
+archives directory. Manifest has the version, release date and per platform
+download URL, SHA256 checksum and size of the package archives, so deployed
+applications or updaters discover the new version from a static file server.
+
+    {
+      "name": "myapp",
+      "channel": "latest",
+      "version": "v1.0.0",
+      "date": "2026-01-02T15:04:05Z",
+      "platforms": {
+        "linux-amd64": {
+          "url": "https://downloads.example.com/myapp/v1.0.0/myapp-v1.0.0-linux-amd64.tar.gz",
+          "sha256": "...",
+          "size": 8473621
+        }
+      }
+    }
+
+Artifact URL is '<url>/<archive>', default URL is 'release.download_url' in
+'aah.project' ('{tag}' is replaced with release tag) otherwise GitHub release
+download URL of 'release.github'. 'aah release publish' uploads the manifest
+along with the package archives.
+
+Example:
+    aah release manifest
+
+    aah release manifest -channel=beta -url=https://downloads.example.com/myapp/v1.0.0-beta.1
+`,
+	}
+)
+
+type (
+	releaseManifest struct {
+		Name      string                              `json:"name"`
+		Channel   string                              `json:"channel"`
+		Version   string                              `json:"version"`
+		Date      string                              `json:"date"`
+		Platforms map[string]*releaseManifestPlatform `json:"platforms"`
+	}
+
+	releaseManifestPlatform struct {
+		URL    string `json:"url"`
+		SHA256 string `json:"sha256"`
+		Size   int64  `json:"size"`
+	}
+)
+
+func releaseManifestRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	buildCfg := appProject.BuildCfg
+	tag := *releaseManifestTagFlag
+	if ess.IsStrEmpty(tag) {
+		var err error
+		if tag, err = releaseTag(appProject.BaseDir); err != nil {
+			fatal(err)
+			return
+		}
+	}
+
+	baseURL, err := releaseDownloadURL(buildCfg, *releaseManifestURLFlag, "", tag)
+	if err != nil {
+		fatalExit(exitUsage, err)
+		return
+	}
+
+	dir := firstNonEmpty(getNonEmptyAbsPath(*releaseManifestDirFlag, ""), releaseDir(appProject.BaseDir, tag))
+	artifacts, err := releaseArtifacts(dir, tag)
+	if err != nil {
+		fatal(err)
+		return
+	}
+
+	file, err := writeReleaseManifest(dir, *releaseManifestChannelFlag, tag, baseURL, artifacts)
+	if err != nil {
+		fatal(err)
+		return
+	}
+
+	recordArtifact(file)
+	log.Infof("Release manifest created: %s", file)
+}
+
+// newReleaseManifest method creates the channel manifest of the package
+// archives, platform is '<os>-<arch>' of the archive name.
+func newReleaseManifest(name, channel, tag, baseURL string, artifacts []string) (*releaseManifest, error) {
+	m := &releaseManifest{
+		Name:      name,
+		Channel:   firstNonEmpty(channel, releaseChannel(tag)),
+		Version:   tag,
+		Date:      getBuildDate(),
+		Platforms: make(map[string]*releaseManifestPlatform),
+	}
+
+	for _, a := range artifacts {
+		base := filepath.Base(a)
+		platform, _ := artifactPlatform(base, tag)
+		if ess.IsStrEmpty(platform) {
+			continue
+		}
+
+		fi, err := os.Stat(a)
+		if err != nil {
+			return nil, err
+		}
+		sum, err := fileSHA256(a)
+		if err != nil {
+			return nil, err
+		}
+
+		// 'tar.gz' is preferred if both archive formats exist for platform
+		if existing, found := m.Platforms[platform]; found && strings.HasSuffix(existing.URL, "."+packageFormatTarGz) {
+			continue
+		}
+		m.Platforms[platform] = &releaseManifestPlatform{
+			URL:    strings.TrimSuffix(baseURL, "/") + "/" + base,
+			SHA256: sum,
+			Size:   fi.Size(),
+		}
+	}
+
+	if len(m.Platforms) == 0 {
+		return nil, errors.New("no package archives found for release manifest, run 'aah package' first")
+	}
+	return m, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// writeReleaseManifest method creates the manifest '<dir>/<channel>.json'
+// and returns the file path.
+func writeReleaseManifest(dir, channel, tag, baseURL string, artifacts []string) (string, error) {
+	m, err := newReleaseManifest(aah.AppName(), channel, tag, baseURL, artifacts)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	file := filepath.Join(dir, m.Channel+".json")
+	return file, writeFile(file, append(b, '\n'), permRWRR)
+}
+
+// releaseChannel method returns the default channel of the tag, it's the
+// pre-release identifier e.g. 'v1.0.0-beta.1' is 'beta', pre-release
+// without identifier is 'prerelease' and other tags are 'latest'.
+func releaseChannel(tag string) string {
+	digit := strings.IndexFunc(tag, unicode.IsDigit)
+	idx := strings.Index(tag[digit+1:], "-")
+	if digit < 0 || idx < 0 {
+		return "latest"
+	}
+
+	pre := tag[digit+idx+2:]
+	end := strings.IndexFunc(pre, func(r rune) bool { return !unicode.IsLetter(r) })
+	if end < 0 {
+		end = len(pre)
+	}
+	if end == 0 {
+		return "prerelease"
+	}
+	return strings.ToLower(pre[:end])
+}
+
+// releaseDir method returns the package archives directory of the tag,
+// '<app-base>/build' for 'latest' channel otherwise
+// '<app-base>/build/<channel>'.
+func releaseDir(baseDir, tag string) string {
+	if channel := releaseChannel(tag); channel != "latest" {
+		return filepath.Join(baseDir, "build", channel)
+	}
+	return filepath.Join(baseDir, "build")
+}
+
+// releaseDownloadURL method returns the download base URL of the release
+// artifacts from flag, 'release.download_url' or GitHub release of repo.
+func releaseDownloadURL(buildCfg *config.Config, flagURL, repo, tag string) (string, error) {
+	if !ess.IsStrEmpty(flagURL) {
+		return flagURL, nil
+	}
+	if u := buildCfg.StringDefault("release.download_url", ""); !ess.IsStrEmpty(u) {
+		return strings.Replace(u, "{tag}", tag, -1), nil
+	}
+
+	repo = firstNonEmpty(repo, buildCfg.StringDefault("release.github", ""))
+	if ess.IsStrEmpty(repo) {
+		return "", errors.New("artifact download URL is required, supply '-url' flag or configure 'release.download_url' or 'release.github'")
+	}
+	return fmt.Sprintf("https://github.com/%s/releases/download/%s", repo, tag), nil
+}
+
+func init() {
+	releaseManifestCmd.Run = releaseManifestRun
+	releaseCmd.Subcommands = append(releaseCmd.Subcommands, releaseManifestCmd)
+}
@@ -0,0 +1,339 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+var (
+	releaseCmdFlags         = flag.NewFlagSet("release", flag.ContinueOnError)
+	releaseBumpFlag         = releaseCmdFlags.String("bump", "", "Version part to bump 'major', 'minor' or 'patch' of semver scheme. Default is 'release.bump' of aah.project or 'patch'")
+	releaseVersionFlag      = releaseCmdFlags.String("version", "", "Release version of 'build.version_scheme', e.g: 1.2.0. Default is bumped version of previous tag")
+	releasePlatformsFlag    = releaseCmdFlags.String("platforms", "", "Comma separated '<os>/<arch>' build matrix. Default is 'release.platforms' of aah.project")
+	releaseProfileFlag      = releaseCmdFlags.String("profile", "", "Environment profile name of startup script. Default is 'prod'")
+	releaseProfileShortFlag = releaseCmdFlags.String("p", "", "Environment profile name of startup script. Default is 'prod'")
+	releasePublishFlag      = releaseCmdFlags.Bool("publish", false, "Push the release commit and tag, publish to GitHub release. Default is 'release.publish' of aah.project")
+	releaseChannelFlag      = releaseCmdFlags.String("channel", "", "Pre-release channel name, e.g: alpha, beta, rc. Default is stable release")
+
+	buildSectionRegex   = regexp.MustCompile(`(?m)^build\s*\{`)
+	projectVersionRegex = regexp.MustCompile(`(?m)^(\s*version\s*=\s*)"[^"]*"`)
+	channelNameRegex    = regexp.MustCompile(`^[a-z]+$`)
+)
+
+func releaseRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	baseDir := appProject.BaseDir
+	buildCfg := appProject.BuildCfg
+	prefix := buildCfg.StringDefault("release.tag_prefix", "v")
+	publish := *releasePublishFlag || buildCfg.BoolDefault("release.publish", false)
+
+	scheme, err := appVersionScheme(buildCfg)
+	if err != nil {
+		fatalErr(newExitErr(exitConfigError, err))
+		return
+	}
+
+	platforms, err := releasePlatforms(buildCfg, *releasePlatformsFlag)
+	if err != nil {
+		fatalErr(newExitErr(exitUsage, err))
+		return
+	}
+
+	var repo, token string
+	if publish {
+		repo = buildCfg.StringDefault("release.github", "")
+		if strings.Count(repo, "/") != 1 {
+			fatalExitf(exitConfigError, "GitHub repository 'owner/repo' is required to publish, configure 'release.github'")
+			return
+		}
+		if token, err = githubToken(); err != nil {
+			fatalErr(newExitErr(exitConfigError, err))
+			return
+		}
+	}
+
+	if status, err := gitOutput(baseDir, "status", "--porcelain"); err != nil {
+		fatal(err)
+		return
+	} else if !ess.IsStrEmpty(status) {
+		fatalf("Working tree must be clean to release, commit or stash the changes:\n%s", status)
+		return
+	}
+
+	// version and tag
+	prevTag, _ := gitOutput(baseDir, "describe", "--tags", "--abbrev=0", "--match", prefix+"*")
+	prev := strings.TrimPrefix(prevTag, prefix)
+	explicit := strings.TrimPrefix(*releaseVersionFlag, prefix)
+	bump := firstNonEmpty(*releaseBumpFlag, buildCfg.StringDefault("release.bump", "patch"))
+	channel := strings.ToLower(*releaseChannelFlag)
+	if !ess.IsStrEmpty(channel) && strings.Contains(prev, "-") {
+		// pre-releases continue on the upcoming version of previous pre-release
+		bump = "patch"
+	}
+	version, err := releaseVersion(scheme, prev, explicit, bump, time.Now())
+	if err == nil && !ess.IsStrEmpty(channel) {
+		if ess.IsStrEmpty(explicit) {
+			tags, _ := gitOutput(baseDir, "tag", "--list", prefix+version+"-"+channel+".*")
+			version, err = releaseChannelVersion(prefix, version, channel, strings.Fields(tags))
+		} else if releaseChannel(explicit) != channel {
+			err = fmt.Errorf("version '%s' is not of channel '%s'", explicit, channel)
+		}
+	}
+	if err != nil {
+		fatalErr(newExitErr(exitUsage, err))
+		return
+	}
+	tag := prefix + version
+	if _, err = gitOutput(baseDir, "rev-parse", "-q", "--verify", "refs/tags/"+tag); err == nil {
+		fatalf("Tag '%s' already exists", tag)
+		return
+	}
+
+	rng := "HEAD"
+	if !ess.IsStrEmpty(prevTag) {
+		rng = prevTag + "..HEAD"
+	}
+	entries, _ := gitOutput(baseDir, "log", "--no-merges", "--pretty=format:- %s", rng)
+	if ess.IsStrEmpty(entries) {
+		fatalf("No commits since previous release '%s'", prevTag)
+		return
+	}
+	log.Infof("Releasing '%s' %s, previous release '%s'", appProject.ImportPath, tag, firstNonEmpty(prevTag, "none"))
+
+	// changelog, project version, commit and tag
+	changed, err := writeReleaseVersion(buildCfg, baseDir, tag, version, entries)
+	if err != nil {
+		fatal(err)
+		return
+	}
+	if err = releaseCommitAndTag(buildCfg, baseDir, tag, changed); err != nil {
+		fatalf("Unable to tag the release: %s", err)
+		return
+	}
+
+	// build matrix, package names are of release version
+	_ = os.Setenv("AAH_APP_VERSION", tag)
+	dir := releaseDir(baseDir, tag)
+	var artifacts []string
+	pg := newProgress("Building release", len(platforms))
+	for _, p := range platforms {
+		pg.Start(p)
+		parts := strings.SplitN(p, "/", 2)
+		archive, err := createPackage(&packageOptions{
+			OS:        parts[0],
+			Arch:      parts[1],
+			OutputDir: dir,
+			Profile:   firstNonEmpty(*releaseProfileFlag, *releaseProfileShortFlag),
+		})
+		if err != nil {
+			pg.Finish()
+			log.Errorf("Packaging %s failed, release tag '%s' is created locally, delete it via 'git tag -d %s' to retry", p, tag, tag)
+			fatalErr(err)
+			return
+		}
+		artifacts = append(artifacts, archive)
+		pg.Done()
+	}
+	pg.Finish()
+
+	// checksums, manifest and signature
+	baseURL, _ := releaseDownloadURL(buildCfg, "", repo, tag)
+	assets := artifacts
+	if isDryRun() {
+		logDryRun("write %s, manifest and signature into %s", releaseChecksumsFile, dir)
+	} else if assets, err = writeReleaseFiles(buildCfg, dir, tag, baseURL, artifacts); err != nil {
+		fatal(err)
+		return
+	}
+	for _, a := range assets {
+		recordArtifact(a)
+	}
+
+	if !publish {
+		log.Infof("Release %s is ready, %d artifact(s) in %s", tag, len(assets), dir)
+		log.Infof("Push the release via 'git push %s HEAD %s' and publish via 'aah release publish'",
+			buildCfg.StringDefault("release.git_remote", "origin"), tag)
+		return
+	}
+
+	remote := buildCfg.StringDefault("release.git_remote", "origin")
+	if err = runDeployCmd(os.Stdout, "git", "-C", baseDir, "push", remote, "HEAD", tag); err != nil {
+		fatalf("Unable to push the release to '%s': %s", remote, err)
+		return
+	}
+	if err = publishGitHubRelease(repo, tag, entries, token, assets); err != nil {
+		fatalf("Unable to publish GitHub release: %s", err)
+	}
+}
+
+// releasePlatforms method returns the '<os>/<arch>' build matrix from flag,
+// 'release.platforms' or current platform.
+func releasePlatforms(buildCfg *config.Config, flagValue string) ([]string, error) {
+	platforms := splitList(flagValue)
+	if len(platforms) == 0 {
+		platforms, _ = buildCfg.StringList("release.platforms")
+	}
+	if len(platforms) == 0 {
+		platforms = []string{getGOOS() + "/" + getGOARCH()}
+	}
+
+	for _, p := range platforms {
+		if parts := strings.Split(p, "/"); len(parts) != 2 || ess.IsStrEmpty(parts[0]) || ess.IsStrEmpty(parts[1]) {
+			return nil, fmt.Errorf("invalid platform '%s', e.g: linux/amd64", p)
+		}
+	}
+	return platforms, nil
+}
+
+// releaseVersion method returns the given version if supplied otherwise
+// the previous version bumped by the scheme.
+func releaseVersion(scheme versionScheme, prev, version, bump string, now time.Time) (string, error) {
+	if !ess.IsStrEmpty(version) {
+		if !scheme.Valid(version) {
+			return "", fmt.Errorf("version '%s' is not of version scheme '%s'", version, scheme.Name())
+		}
+		return version, nil
+	}
+	return scheme.Next(prev, bump, now)
+}
+
+// releaseChannelVersion method returns the pre-release version
+// '<version>-<channel>.<N>' of the channel, N is next to the highest number
+// of existing channel tags of the version.
+func releaseChannelVersion(prefix, version, channel string, tags []string) (string, error) {
+	if !channelNameRegex.MatchString(channel) || channel == "latest" {
+		return "", fmt.Errorf("channel name '%s' is invalid, use lowercase letters e.g: alpha, beta, rc", channel)
+	}
+
+	n := 0
+	tagPrefix := prefix + version + "-" + channel + "."
+	for _, t := range tags {
+		if !strings.HasPrefix(t, tagPrefix) {
+			continue
+		}
+		if v, err := strconv.Atoi(strings.TrimPrefix(t, tagPrefix)); err == nil && v > n {
+			n = v
+		}
+	}
+	return fmt.Sprintf("%s-%s.%d", version, channel, n+1), nil
+}
+
+// writeReleaseVersion method adds the release section into changelog file
+// 'release.changelog' and sets 'build.version' of aah.project. It returns
+// the changed files.
+func writeReleaseVersion(buildCfg *config.Config, baseDir, tag, version, entries string) ([]string, error) {
+	var changed []string
+	section := fmt.Sprintf("## %s (%s)\n\n%s\n", tag, time.Now().Format("2006-01-02"), entries)
+	if name := buildCfg.StringDefault("release.changelog", "CHANGELOG.md"); !ess.IsStrEmpty(name) {
+		file := filepath.Join(baseDir, name)
+		content, err := ioutil.ReadFile(file)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if err = writeFile(file, []byte(prependChangelog(string(content), section)), permRWRR); err != nil {
+			return nil, err
+		}
+		changed = append(changed, file)
+	}
+
+	projectFile := filepath.Join(baseDir, aahProjectIdentifier)
+	content, err := ioutil.ReadFile(projectFile)
+	if err != nil {
+		return nil, err
+	}
+	if updated, found := setProjectVersion(content, version); found && !bytes.Equal(updated, content) {
+		if err = writeFile(projectFile, updated, permRWRR); err != nil {
+			return nil, err
+		}
+		changed = append(changed, projectFile)
+	}
+	return changed, nil
+}
+
+// prependChangelog method inserts the release section on top of changelog
+// entries, below the title line '# ...' if exists.
+func prependChangelog(content, section string) string {
+	if strings.HasPrefix(content, "# ") {
+		title := content
+		rest := ""
+		if idx := strings.Index(content, "\n"); idx >= 0 {
+			title, rest = content[:idx], strings.TrimLeft(content[idx:], "\n")
+		}
+		return title + "\n\n" + section + "\n" + rest
+	}
+	if ess.IsStrEmpty(content) {
+		return "# Changelog\n\n" + section
+	}
+	return section + "\n" + content
+}
+
+// setProjectVersion method sets the 'version' of 'build' section in
+// aah.project content, it returns false if it's not found.
+func setProjectVersion(content []byte, version string) ([]byte, bool) {
+	loc := buildSectionRegex.FindIndex(content)
+	if loc == nil {
+		return content, false
+	}
+	m := projectVersionRegex.FindSubmatchIndex(content[loc[1]:])
+	if m == nil {
+		return content, false
+	}
+
+	start, end := loc[1]+m[0], loc[1]+m[1]
+	prefix := content[loc[1]+m[2] : loc[1]+m[3]]
+	updated := append([]byte{}, content[:start]...)
+	updated = append(updated, prefix...)
+	updated = append(updated, strconv.Quote(version)...)
+	return append(updated, content[end:]...), true
+}
+
+// releaseCommitAndTag method commits the changed files and creates the
+// annotated tag, tag is signed if 'release.sign.enable' is true.
+func releaseCommitAndTag(buildCfg *config.Config, baseDir, tag string, changed []string) error {
+	msg := "Release " + tag
+	if len(changed) > 0 {
+		if err := runDeployCmd(os.Stdout, "git", append([]string{"-C", baseDir, "add", "--"}, changed...)...); err != nil {
+			return err
+		}
+		if err := runDeployCmd(os.Stdout, "git", "-C", baseDir, "commit", "-q", "-m", msg); err != nil {
+			return err
+		}
+	}
+
+	args := []string{"-C", baseDir, "tag", "-a", tag, "-m", msg}
+	if buildCfg.BoolDefault("release.sign.enable", false) {
+		args[3] = "-s"
+		if key := buildCfg.StringDefault("release.sign.key", ""); !ess.IsStrEmpty(key) {
+			args = append(args, "-u", key)
+		}
+	}
+	if err := runDeployCmd(os.Stdout, "git", args...); err != nil {
+		return err
+	}
+	if !isDryRun() {
+		log.Infof("Tagged %s", tag)
+	}
+	return nil
+}
+
+func init() {
+	releaseCmd.Run = releaseRun
+}
@@ -0,0 +1,288 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+const githubAPIURL = "https://api.github.com"
+
+var (
+	releasePublishCmdFlags = flag.NewFlagSet("publish", flag.ContinueOnError)
+	releaseGitHubFlag      = releasePublishCmdFlags.String("github", "", "GitHub repository 'owner/repo'. Default is 'release.github' of 'aah.project'")
+	releaseTagFlag         = releasePublishCmdFlags.String("tag", "", "Release tag. Default is git tag of current commit")
+	releaseDirFlag         = releasePublishCmdFlags.String("dir", "", "Directory of package archives. Default is <app-base>/build, <app-base>/build/<channel> for pre-release")
+	releaseChangelogFlag   = releasePublishCmdFlags.String("changelog", "", "Changelog file. Default is commit subjects since previous tag")
+	releasePublishCmd      = &command{
+		Name:      "publish",
+		UsageLine: "aah release publish [-github] [-tag] [-dir] [-changelog]",
+		Flags:     releasePublishCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "publish release artifacts to GitHub Releases",
+		Long: `
+Publishes the package archives of the current git tag, 'checksums.txt',
+release channel manifest (refer 'aah release manifest') and changelog to
+the GitHub release of the tag. Release is created if it does not exist,
+assets with same name are replaced. Signature 'checksums.txt.asc' is
+published too if 'release.sign.enable' is true, refer 'aah help release'.
+
+Package archives '<app>-<tag>-<os>-<arch>.tar.gz' (or '.zip') are picked
+from the '-dir', so run 'aah package' for every platform on the tagged
+commit first.
+
+GitHub token is read from 'GITHUB_TOKEN' environment variable. For GitHub
+Enterprise set the API URL via 'AAH_GITHUB_API_URL' environment variable.
+
+Example:
+    aah release publish -github=myorg/myapp
+
+    aah release publish -github=myorg/myapp -changelog=CHANGELOG.md
+
+    aah -dry-run release publish -github=myorg/myapp
+`,
+	}
+)
+
+type (
+	githubClient struct {
+		apiURL string
+		token  string
+		client *http.Client
+	}
+
+	githubRelease struct {
+		ID        int64                 `json:"id"`
+		TagName   string                `json:"tag_name"`
+		HTMLURL   string                `json:"html_url"`
+		UploadURL string                `json:"upload_url"`
+		Assets    []*githubReleaseAsset `json:"assets"`
+	}
+
+	githubReleaseAsset struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+)
+
+var errGitHubNotFound = errors.New("github: not found")
+
+func releasePublishRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	buildCfg := appProject.BuildCfg
+	repo := firstNonEmpty(*releaseGitHubFlag, buildCfg.StringDefault("release.github", ""))
+	if strings.Count(repo, "/") != 1 {
+		fatalExitf(exitUsage, "GitHub repository 'owner/repo' is required, supply '-github' flag")
+		return
+	}
+
+	token, err := githubToken()
+	if err != nil {
+		fatalErr(newExitErr(exitConfigError, err))
+		return
+	}
+
+	tag := *releaseTagFlag
+	if ess.IsStrEmpty(tag) {
+		if tag, err = releaseTag(appProject.BaseDir); err != nil {
+			fatal(err)
+			return
+		}
+	}
+
+	dir := firstNonEmpty(getNonEmptyAbsPath(*releaseDirFlag, ""), releaseDir(appProject.BaseDir, tag))
+	artifacts, err := releaseArtifacts(dir, tag)
+	if err != nil {
+		fatal(err)
+		return
+	}
+
+	assets, err := writeReleaseFiles(buildCfg, dir, tag,
+		fmt.Sprintf("https://github.com/%s/releases/download/%s", repo, tag), artifacts)
+	if err != nil {
+		fatal(err)
+		return
+	}
+
+	changelog, err := releaseChangelog(appProject.BaseDir, tag, *releaseChangelogFlag)
+	if err != nil {
+		fatalf("Unable to read changelog: %s", err)
+		return
+	}
+
+	if err = publishGitHubRelease(repo, tag, changelog, token, assets); err != nil {
+		fatalf("Unable to publish GitHub release: %s", err)
+	}
+}
+
+// githubToken method returns the GitHub token of 'GITHUB_TOKEN' environment
+// variable, it's not required in dry-run mode.
+func githubToken() (string, error) {
+	token := os.Getenv("GITHUB_TOKEN")
+	if ess.IsStrEmpty(token) && !isDryRun() {
+		return "", errors.New("GitHub token is required, set 'GITHUB_TOKEN' environment variable")
+	}
+	return token, nil
+}
+
+// publishGitHubRelease method creates the GitHub release of the tag with
+// changelog and uploads the assets, in dry-run mode it just logs.
+func publishGitHubRelease(repo, tag, changelog, token string, assets []string) error {
+	if isDryRun() {
+		logDryRun("create GitHub release %s %s", repo, tag)
+		for _, a := range assets {
+			logDryRun("upload %s", a)
+		}
+		return nil
+	}
+
+	endStage := startStage("release")
+	gh := &githubClient{
+		apiURL: firstNonEmpty(os.Getenv("AAH_GITHUB_API_URL"), githubAPIURL),
+		token:  token,
+		client: newHTTPClient(cliDownloadTimeout),
+	}
+	release, err := gh.publish(repo, tag, changelog, assets)
+	endStage()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Release published, %d assets: %s", len(assets), release.HTMLURL)
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// githubClient methods
+//___________________________________
+
+// publish method gets or creates the release of the tag and uploads the
+// assets, existing assets with same name are replaced.
+func (g *githubClient) publish(repo, tag, body string, assets []string) (*githubRelease, error) {
+	release, err := g.releaseByTag(repo, tag)
+	if err == errGitHubNotFound {
+		log.Infof("Creating GitHub release %s %s", repo, tag)
+		release, err = g.createRelease(repo, tag, body)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range assets {
+		name := filepath.Base(a)
+		for _, existing := range release.Assets {
+			if existing.Name == name {
+				u := fmt.Sprintf("%s/repos/%s/releases/assets/%d", g.apiURL, repo, existing.ID)
+				if err = g.do(http.MethodDelete, u, nil, "", nil); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		log.Infof("Uploading %s", name)
+		if err = g.uploadAsset(release, a); err != nil {
+			return nil, err
+		}
+	}
+
+	return release, nil
+}
+
+func (g *githubClient) releaseByTag(repo, tag string) (*githubRelease, error) {
+	release := &githubRelease{}
+	u := fmt.Sprintf("%s/repos/%s/releases/tags/%s", g.apiURL, repo, url.PathEscape(tag))
+	return release, g.do(http.MethodGet, u, nil, "", release)
+}
+
+func (g *githubClient) createRelease(repo, tag, body string) (*githubRelease, error) {
+	b, err := json.Marshal(map[string]interface{}{
+		"tag_name":   tag,
+		"name":       tag,
+		"body":       body,
+		"prerelease": strings.Contains(tag, "-"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	release := &githubRelease{}
+	u := fmt.Sprintf("%s/repos/%s/releases", g.apiURL, repo)
+	return release, g.do(http.MethodPost, u, bytes.NewReader(b), "application/json", release)
+}
+
+func (g *githubClient) uploadAsset(release *githubRelease, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer ess.CloseQuietly(f)
+
+	// upload URL is a hypermedia template, e.g.: '.../assets{?name,label}'
+	u := release.UploadURL
+	if idx := strings.Index(u, "{"); idx >= 0 {
+		u = u[:idx]
+	}
+	u += "?name=" + url.QueryEscape(filepath.Base(file))
+	return g.do(http.MethodPost, u, f, "application/octet-stream", nil)
+}
+
+// do method sends the GitHub API request and decodes the JSON response
+// into out if it's not nil.
+func (g *githubClient) do(method, u string, body io.Reader, contentType string, out interface{}) error {
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("Authorization", "token "+g.token)
+	if !ess.IsStrEmpty(contentType) {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if f, ok := body.(*os.File); ok {
+		if fi, err := f.Stat(); err == nil {
+			req.ContentLength = fi.Size()
+		}
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer ess.CloseQuietly(resp.Body)
+
+	if resp.StatusCode == http.StatusNotFound && method == http.MethodGet {
+		return errGitHubNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s: %s %s", method, u, resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+func init() {
+	releasePublishCmd.Run = releasePublishRun
+	releaseCmd.Subcommands = append(releaseCmd.Subcommands, releasePublishCmd)
+}
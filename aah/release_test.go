@@ -0,0 +1,211 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestReleaseArtifacts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-release")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	for _, name := range []string{"myapp-v1.0.0-linux-amd64.tar.gz", "myapp-v1.0.0-windows-amd64.zip",
+		"myapp-v1.0.0-linux-amd64-deploy.zip", "myapp-v0.9.0-linux-amd64.tar.gz", "checksums.txt"} {
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(name), permRWRR))
+	}
+
+	artifacts, err := releaseArtifacts(dir, "v1.0.0")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "myapp-v1.0.0-linux-amd64.tar.gz"),
+		filepath.Join(dir, "myapp-v1.0.0-windows-amd64.zip")}, artifacts)
+
+	checksums, err := releaseChecksums(artifacts[:1])
+	assert.Nil(t, err)
+	sum, _ := fileSHA256(artifacts[0])
+	assert.Equal(t, sum+"  myapp-v1.0.0-linux-amd64.tar.gz\n", string(checksums))
+
+	_, err = releaseArtifacts(dir, "v2.0.0")
+	assert.NotNil(t, err)
+}
+
+func TestReleaseGitHubPublish(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-release")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	asset := filepath.Join(dir, "myapp-v1.0.0-linux-amd64.tar.gz")
+	assert.Nil(t, ioutil.WriteFile(asset, []byte("archive"), permRWRR))
+
+	var requests []string
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		switch {
+		case r.Method == http.MethodGet:
+			http.NotFound(w, r)
+		case r.URL.Path == "/repos/myorg/myapp/releases":
+			body := map[string]interface{}{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			assert.Equal(t, "v1.0.0", body["tag_name"])
+			assert.Equal(t, "- First release", body["body"])
+			fmt.Fprintf(w, `{"id":1,"tag_name":"v1.0.0","upload_url":"%s/uploads/1/assets{?name,label}"}`, ts.URL)
+		default:
+			b, _ := ioutil.ReadAll(r.Body)
+			assert.Equal(t, "archive", string(b))
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer ts.Close()
+
+	gh := &githubClient{apiURL: ts.URL, token: "secret", client: http.DefaultClient}
+	release, err := gh.publish("myorg/myapp", "v1.0.0", "- First release", []string{asset})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), release.ID)
+	assert.Equal(t, []string{
+		"GET /repos/myorg/myapp/releases/tags/v1.0.0",
+		"POST /repos/myorg/myapp/releases",
+		"POST /uploads/1/assets?name=myapp-v1.0.0-linux-amd64.tar.gz",
+	}, requests)
+}
+
+func TestReleaseBrewFormula(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-release")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	var artifacts []string
+	for _, name := range []string{"my_app-v1.0.0-darwin-amd64.tar.gz", "my_app-v1.0.0-darwin-arm64.tar.gz",
+		"my_app-v1.0.0-linux-amd64.tar.gz", "my_app-v1.0.0-windows-amd64.zip"} {
+		artifacts = append(artifacts, filepath.Join(dir, name))
+		assert.Nil(t, ioutil.WriteFile(artifacts[len(artifacts)-1], []byte(name), permRWRR))
+	}
+
+	formula, err := newBrewFormula("my_app", "v1.0.0", "https://example.com/dl/", artifacts)
+	assert.Nil(t, err)
+	assert.Equal(t, "MyApp", formula.Class)
+	assert.Equal(t, "my-app", formula.Name)
+	assert.Equal(t, "1.0.0", formula.Version)
+	assert.Equal(t, 2, len(formula.MacOS))
+	assert.Equal(t, 1, len(formula.Linux))
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, renderTmpl(buf, brewFormulaTemplate, formula))
+	out := buf.String()
+	assert.True(t, strings.Contains(out, "class MyApp < Formula\n"))
+	assert.True(t, strings.Contains(out, "    if Hardware::CPU.intel?\n      url \"https://example.com/dl/my_app-v1.0.0-darwin-amd64.tar.gz\"\n"))
+	assert.True(t, strings.Contains(out, "    elsif Hardware::CPU.arm?\n"))
+	assert.True(t, strings.Contains(out, "  on_linux do\n    url \"https://example.com/dl/my_app-v1.0.0-linux-amd64.tar.gz\"\n    sha256 \""+formula.Linux[0].SHA256+"\"\n  end\n"))
+
+	_, err = newBrewFormula("my_app", "v1.0.0", "https://example.com/dl", artifacts[3:])
+	assert.NotNil(t, err)
+}
+
+func TestReleaseManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-release")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	var artifacts []string
+	for _, name := range []string{"myapp-v1.0.0-linux-amd64.tar.gz", "myapp-v1.0.0-linux-amd64.zip",
+		"myapp-v1.0.0-windows-amd64.zip"} {
+		artifacts = append(artifacts, filepath.Join(dir, name))
+		assert.Nil(t, ioutil.WriteFile(artifacts[len(artifacts)-1], []byte(name), permRWRR))
+	}
+
+	m, err := newReleaseManifest("myapp", "", "v1.0.0", "https://example.com/dl/", artifacts)
+	assert.Nil(t, err)
+	assert.Equal(t, "latest", m.Channel)
+	assert.Equal(t, "v1.0.0", m.Version)
+	assert.Equal(t, 2, len(m.Platforms))
+
+	linux := m.Platforms["linux-amd64"]
+	sum, _ := fileSHA256(artifacts[0])
+	assert.Equal(t, "https://example.com/dl/myapp-v1.0.0-linux-amd64.tar.gz", linux.URL)
+	assert.Equal(t, sum, linux.SHA256)
+	assert.Equal(t, int64(len("myapp-v1.0.0-linux-amd64.tar.gz")), linux.Size)
+	assert.Equal(t, "https://example.com/dl/myapp-v1.0.0-windows-amd64.zip", m.Platforms["windows-amd64"].URL)
+
+	platform, ext := artifactPlatform("myapp-v1.0.0-beta.1-darwin-arm64.tar.gz", "v1.0.0-beta.1")
+	assert.Equal(t, "darwin-arm64", platform)
+	assert.Equal(t, packageFormatTarGz, ext)
+	assert.Equal(t, "beta", releaseChannel("v1.0.0-beta.1"))
+	assert.Equal(t, "rc", releaseChannel("release-2017.06.1-RC1"))
+	assert.Equal(t, "prerelease", releaseChannel("v1.0.0-1"))
+	assert.Equal(t, "latest", releaseChannel("release-1.0.0"))
+	assert.Equal(t, "myapp/build", filepath.ToSlash(releaseDir("myapp", "v1.0.0")))
+	assert.Equal(t, "myapp/build/beta", filepath.ToSlash(releaseDir("myapp", "v1.0.0-beta.1")))
+
+	_, err = newReleaseManifest("myapp", "stable", "v2.0.0", "https://example.com/dl", artifacts)
+	assert.NotNil(t, err)
+}
+
+func TestReleaseVersion(t *testing.T) {
+	now := time.Date(2017, 6, 1, 10, 0, 0, 0, time.UTC)
+	for _, c := range []struct{ prev, version, bump, expected string }{
+		{"", "", "patch", "0.0.1"},
+		{"1.2.3", "", "minor", "1.3.0"},
+		{"1.2.3", "2.0.0-beta.1", "patch", "2.0.0-beta.1"},
+	} {
+		v, err := releaseVersion(semverScheme{}, c.prev, c.version, c.bump, now)
+		assert.Nil(t, err)
+		assert.Equal(t, c.expected, v)
+	}
+
+	_, err := releaseVersion(semverScheme{}, "", "2.0", "patch", now)
+	assert.NotNil(t, err)
+
+	calver, err := parseCalverFormat("YYYY.0M.PATCH")
+	assert.Nil(t, err)
+	v, err := releaseVersion(calver, "2017.06.0", "", "patch", now)
+	assert.Nil(t, err)
+	assert.Equal(t, "2017.06.1", v)
+	_, err = releaseVersion(calver, "", "1.2.3", "patch", now)
+	assert.NotNil(t, err)
+}
+
+func TestReleaseChannelVersion(t *testing.T) {
+	v, err := releaseChannelVersion("v", "1.4.0", "beta", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "1.4.0-beta.1", v)
+
+	v, err = releaseChannelVersion("v", "1.4.0", "beta", []string{"v1.4.0-beta.1", "v1.4.0-beta.2", "v1.4.0-beta.x"})
+	assert.Nil(t, err)
+	assert.Equal(t, "1.4.0-beta.3", v)
+
+	_, err = releaseChannelVersion("v", "1.4.0", "latest", nil)
+	assert.NotNil(t, err)
+	_, err = releaseChannelVersion("v", "1.4.0", "beta.1", nil)
+	assert.NotNil(t, err)
+}
+
+func TestReleaseChangelogAndProjectVersion(t *testing.T) {
+	section := "## v1.1.0 (2017-06-01)\n\n- Add users API\n"
+	assert.Equal(t, "# Changelog\n\n"+section, prependChangelog("", section))
+	assert.Equal(t, "# Changelog\n\n"+section+"\n## v1.0.0 (2017-05-01)\n",
+		prependChangelog("# Changelog\n\n## v1.0.0 (2017-05-01)\n", section))
+	assert.Equal(t, section+"\n- Older\n", prependChangelog("- Older\n", section))
+
+	project := "name = \"myapp\"\n\nbuild {\n  version = \"1.0.0\"\n}\n\ndocker {\n  version = \"3\"\n}\n"
+	updated, found := setProjectVersion([]byte(project), "1.1.0")
+	assert.True(t, found)
+	assert.Equal(t, strings.Replace(project, "\"1.0.0\"", "\"1.1.0\"", 1), string(updated))
+
+	_, found = setProjectVersion([]byte("name = \"myapp\"\n"), "1.1.0")
+	assert.False(t, found)
+}
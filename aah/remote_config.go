@@ -0,0 +1,217 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+// remoteConfigFile is the remote config file name in the build artifact,
+// used by the startup script if external config is not supplied.
+const remoteConfigFile = "remote.conf"
+
+// remoteConfig is the external config source of 'config.remote' section of
+// 'aah.project', it's fetched during 'aah run' and 'aah build'.
+//
+//	config {
+//	  remote {
+//	    url = "consul://localhost:8500/myapp/config"
+//	    timeout = "10s"
+//	    cache_file = ".aah/config/remote.conf"
+//	    headers {
+//	      Authorization = "Bearer ${CONFIG_TOKEN}"
+//	    }
+//	  }
+//	}
+type remoteConfig struct {
+	URL       string
+	Headers   map[string]string
+	Timeout   time.Duration
+	CacheFile string
+}
+
+// loadRemoteConfig method reads the remote config source from 'aah.project',
+// it returns nil if it's not configured.
+func loadRemoteConfig(cfg *config.Config, baseDir string) (*remoteConfig, error) {
+	rawURL := cfg.StringDefault("config.remote.url", "")
+	if ess.IsStrEmpty(rawURL) {
+		return nil, nil
+	}
+
+	timeout, err := time.ParseDuration(cfg.StringDefault("config.remote.timeout", "10s"))
+	if err != nil {
+		return nil, fmt.Errorf("'config.remote.timeout' is invalid: %s", err)
+	}
+
+	rc := &remoteConfig{
+		URL:       rawURL,
+		Headers:   map[string]string{},
+		Timeout:   timeout,
+		CacheFile: cfg.StringDefault("config.remote.cache_file", filepath.Join(".aah", "config", remoteConfigFile)),
+	}
+	if !filepath.IsAbs(rc.CacheFile) {
+		rc.CacheFile = filepath.Join(baseDir, filepath.FromSlash(rc.CacheFile))
+	}
+
+	if headersCfg, found := cfg.GetSubConfig("config.remote.headers"); found {
+		for _, k := range headersCfg.Keys() {
+			rc.Headers[k] = headersCfg.StringDefault(k, "")
+		}
+	}
+	return rc, nil
+}
+
+// Fetch method fetches the remote config, validates and caches it. Cached
+// config is used if remote source is not reachable. It returns the cached
+// config file path.
+func (rc *remoteConfig) Fetch() (string, error) {
+	content, err := rc.fetch()
+	if err == nil {
+		if _, err = parseConfString(rc.URL, string(content)); err != nil {
+			return "", fmt.Errorf("remote config '%s' is invalid: %s", rc.Redacted(), err)
+		}
+		if err = mkDirAll(filepath.Dir(rc.CacheFile), permRWXRXRX); err != nil {
+			return "", err
+		}
+
+		// remote config may have secrets, so it's readable only by the owner
+		if err = writeFile(rc.CacheFile, content, 0600); err != nil {
+			return "", err
+		}
+		return rc.CacheFile, nil
+	}
+
+	if !ess.IsFileExists(rc.CacheFile) {
+		return "", fmt.Errorf("unable to fetch remote config '%s': %s", rc.Redacted(), err)
+	}
+	logWarnf("Unable to fetch remote config '%s', using cached config: %s", rc.Redacted(), err)
+	return rc.CacheFile, nil
+}
+
+// Redacted method returns the URL without user info.
+func (rc *remoteConfig) Redacted() string {
+	u, err := url.Parse(rc.URL)
+	if err != nil || u.User == nil {
+		return rc.URL
+	}
+	u.User = nil
+	return u.String()
+}
+
+// fetchRemoteConfig method fetches the remote config of 'aah.project' if
+// configured, it returns empty file path otherwise.
+func fetchRemoteConfig(cfg *config.Config, baseDir string) (string, error) {
+	rc, err := loadRemoteConfig(cfg, baseDir)
+	if err != nil || rc == nil {
+		return "", err
+	}
+
+	file, err := rc.Fetch()
+	if err != nil {
+		return "", err
+	}
+	log.Infof("Using remote config '%s'", rc.Redacted())
+	return file, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// fetch method fetches the config content from the source:
+//   - 'http://' and 'https://' URL, response body is the config
+//   - 'consul://host:port/key' value of Consul KV, token is read from
+//     'CONSUL_HTTP_TOKEN' environment variable
+//   - 'etcd://host:port/key' value of etcd v3 via its JSON gateway
+//
+// Consul and etcd use HTTPS with scheme 'consul+https' and 'etcd+https'.
+func (rc *remoteConfig) fetch() ([]byte, error) {
+	u, err := url.Parse(rc.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := "http"
+	if strings.HasSuffix(u.Scheme, "+https") {
+		scheme = "https"
+	}
+	key := strings.TrimPrefix(u.Path, "/")
+
+	var req *http.Request
+	switch strings.TrimSuffix(u.Scheme, "+https") {
+	case "http", "https":
+		req, err = http.NewRequest(http.MethodGet, rc.URL, nil)
+	case "consul":
+		req, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s://%s/v1/kv/%s?raw", scheme, u.Host, key), nil)
+		if err == nil && !ess.IsStrEmpty(os.Getenv("CONSUL_HTTP_TOKEN")) {
+			req.Header.Set("X-Consul-Token", os.Getenv("CONSUL_HTTP_TOKEN"))
+		}
+	case "etcd":
+		body, _ := json.Marshal(map[string]string{"key": base64.StdEncoding.EncodeToString([]byte(key))})
+		req, err = http.NewRequest(http.MethodPost, fmt.Sprintf("%s://%s/v3/kv/range", scheme, u.Host), bytes.NewReader(body))
+	default:
+		return nil, fmt.Errorf("unsupported scheme '%s', supported schemes are 'http', 'https', 'consul' and 'etcd'", u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if u.User != nil && u.Scheme != "http" && u.Scheme != "https" {
+		password, _ := u.User.Password()
+		req.SetBasicAuth(u.User.Username(), password)
+	}
+	for k, v := range rc.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := newHTTPClient(rc.Timeout).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer ess.CloseQuietly(resp.Body)
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status '%s'", resp.Status)
+	}
+
+	if strings.HasPrefix(u.Scheme, "etcd") {
+		return etcdRangeValue(content, key)
+	}
+	return content, nil
+}
+
+// etcdRangeValue method returns the value of etcd v3 range response.
+func etcdRangeValue(content []byte, key string) ([]byte, error) {
+	var result struct {
+		Kvs []struct {
+			Value string `json:"value"`
+		} `json:"kvs"`
+	}
+	if err := json.Unmarshal(content, &result); err != nil {
+		return nil, fmt.Errorf("invalid etcd response: %s", err)
+	}
+	if len(result.Kvs) == 0 {
+		return nil, fmt.Errorf("etcd key '%s' does not exists", key)
+	}
+	return base64.StdEncoding.DecodeString(result.Kvs[0].Value)
+}
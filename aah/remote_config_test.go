@@ -0,0 +1,88 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestRemoteConfigFetch(t *testing.T) {
+	content := "server { port = 9090 }\n"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/app.conf":
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(content))
+		case "/v1/kv/myapp/config":
+			_, _ = w.Write([]byte(content))
+		case "/v3/kv/range":
+			var req map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			key, _ := base64.StdEncoding.DecodeString(req["key"])
+			if string(key) != "myapp/config" {
+				_, _ = w.Write([]byte(`{"kvs":[]}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"kvs": []map[string]string{{"value": base64.StdEncoding.EncodeToString([]byte(content))}},
+			})
+		case "/invalid.conf":
+			_, _ = w.Write([]byte("server {"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "aah-remote-config")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	host := strings.TrimPrefix(ts.URL, "http://")
+	cacheFile := filepath.Join(dir, "remote.conf")
+	for _, u := range []string{ts.URL + "/app.conf", "consul://" + host + "/myapp/config", "etcd://" + host + "/myapp/config"} {
+		_ = os.Remove(cacheFile)
+		rc := &remoteConfig{URL: u, Headers: map[string]string{"Authorization": "Bearer token"},
+			Timeout: 5 * time.Second, CacheFile: cacheFile}
+		file, err := rc.Fetch()
+		assert.Nil(t, err)
+		assert.Equal(t, cacheFile, file)
+		b, _ := ioutil.ReadFile(file)
+		assert.Equal(t, content, string(b))
+	}
+
+	// cached config is used if the source fails
+	rc := &remoteConfig{URL: "etcd://" + host + "/other", Timeout: 5 * time.Second, CacheFile: cacheFile}
+	file, err := rc.Fetch()
+	assert.Nil(t, err)
+	assert.Equal(t, cacheFile, file)
+
+	rc.CacheFile = filepath.Join(dir, "none.conf")
+	_, err = rc.Fetch()
+	assert.Equal(t, "unable to fetch remote config 'etcd://"+host+"/other': etcd key 'other' does not exists", err.Error())
+
+	rc.URL = ts.URL + "/invalid.conf"
+	_, err = rc.Fetch()
+	assert.True(t, strings.HasPrefix(err.Error(), "remote config '"+ts.URL+"/invalid.conf' is invalid:"))
+
+	rc.URL = "ftp://user:pass@" + host + "/app.conf"
+	assert.Equal(t, "ftp://"+host+"/app.conf", rc.Redacted())
+	_, err = rc.Fetch()
+	assert.True(t, strings.Contains(err.Error(), "unsupported scheme 'ftp'"))
+}
@@ -0,0 +1,167 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+)
+
+var routesCmd = &command{
+	Name:      "routes",
+	UsageLine: "aah routes <subcommand> [arguments]",
+	Short:     "inspect application routes",
+	Long: `
+Inspects the application routes of 'config/routes.conf' along with the
+controllers of 'app/controllers'.
+
+Example:
+    aah routes list
+`,
+}
+
+// appRoute is the application route of 'config/routes.conf', route with
+// multiple HTTP methods becomes one route per method.
+type appRoute struct {
+	Domain     string
+	Host       string
+	Name       string
+	Path       string
+	Method     string
+	Controller string
+	Action     string
+	Auth       string
+}
+
+// loadAppRoutes method reads the application routes of all domains from
+// 'config/routes.conf', sorted by domain, path and method.
+func loadAppRoutes(baseDir string) ([]*appRoute, error) {
+	routesFile := filepath.Join(baseDir, "config", "routes.conf")
+	if !ess.IsFileExists(routesFile) {
+		return nil, fmt.Errorf("routes file '%s' does not exists", routesFile)
+	}
+
+	cfg, err := config.LoadFile(routesFile)
+	if err != nil {
+		return nil, fmt.Errorf("routes file '%s': %s", routesFile, err)
+	}
+
+	domains, found := cfg.GetSubConfig("domains")
+	if !found {
+		return nil, fmt.Errorf("routes file '%s': section 'domains' is missing", routesFile)
+	}
+
+	var routes []*appRoute
+	for _, domain := range domains.Keys() {
+		domainCfg, _ := domains.GetSubConfig(domain)
+		host := domainCfg.StringDefault("host", domain)
+		if routesCfg, found := domainCfg.GetSubConfig("routes"); found {
+			routes = append(routes, parseRoutesSection(routesCfg, domain, host, "", "",
+				domainCfg.StringDefault("default_auth", ""))...)
+		}
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Domain != routes[j].Domain {
+			return routes[i].Domain < routes[j].Domain
+		}
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes, nil
+}
+
+// parseRoutesSection method parses the 'routes' section, nested 'routes'
+// inherits the parent path prefix, controller and auth scheme.
+func parseRoutesSection(cfg *config.Config, domain, host, parentPath, parentCtrl, parentAuth string) []*appRoute {
+	var routes []*appRoute
+	for _, name := range cfg.Keys() {
+		routeCfg, found := cfg.GetSubConfig(name)
+		if !found {
+			continue
+		}
+
+		routePath := joinRoutePath(parentPath, routeCfg.StringDefault("path", ""))
+		ctrl := routeCfg.StringDefault("controller", parentCtrl)
+		auth := routeCfg.StringDefault("auth", parentAuth)
+		for _, method := range strings.Split(routeCfg.StringDefault("method", "GET"), ",") {
+			method = strings.ToUpper(strings.TrimSpace(method))
+			if ess.IsStrEmpty(method) {
+				continue
+			}
+
+			routes = append(routes, &appRoute{
+				Domain:     domain,
+				Host:       host,
+				Name:       name,
+				Path:       routePath,
+				Method:     method,
+				Controller: ctrl,
+				Action:     routeCfg.StringDefault("action", defaultRouteAction(method)),
+				Auth:       auth,
+			})
+		}
+
+		if nestedCfg, found := routeCfg.GetSubConfig("routes"); found {
+			routes = append(routes, parseRoutesSection(nestedCfg, domain, host, routePath, ctrl, auth)...)
+		}
+	}
+	return routes
+}
+
+func joinRoutePath(parent, p string) string {
+	if ess.IsStrEmpty(parent) {
+		return p
+	}
+	joined := path.Join(parent, p)
+	if strings.HasSuffix(p, "/") && !strings.HasSuffix(joined, "/") {
+		joined += "/"
+	}
+	return joined
+}
+
+// defaultRouteAction method returns the default controller action of the
+// HTTP method, same as aah router.
+func defaultRouteAction(method string) string {
+	switch method {
+	case "POST":
+		return "Create"
+	case "PUT", "PATCH":
+		return "Update"
+	case "DELETE":
+		return "Delete"
+	case "OPTIONS":
+		return "Options"
+	case "HEAD":
+		return "Head"
+	case "TRACE":
+		return "Trace"
+	default:
+		return "Index"
+	}
+}
+
+// routeSamplePath method fills the path parameters with sample values,
+// ':name' with '1' and '*name' with 'sample'.
+func routeSamplePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		switch {
+		case strings.HasPrefix(s, ":"):
+			segments[i] = "1"
+		case strings.HasPrefix(s, "*"):
+			segments[i] = "sample"
+		}
+	}
+	return strings.Join(segments, "/")
+}
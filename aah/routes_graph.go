@@ -0,0 +1,313 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+)
+
+const (
+	routesGraphFormatTree = "tree"
+	routesGraphFormatDOT  = "dot"
+)
+
+var (
+	routesGraphCmdFlags   = flag.NewFlagSet("graph", flag.ContinueOnError)
+	routesGraphFormatFlag = routesGraphCmdFlags.String("format", routesGraphFormatTree, "Output format 'tree' or 'dot'")
+	routesGraphDomainFlag = routesGraphCmdFlags.String("domain", "", "Graph the routes of given domain only")
+	routesGraphCmd        = &command{
+		Name:      "graph",
+		UsageLine: "aah routes graph [-format] [-domain] [route-name-or-path]",
+		Flags:     routesGraphCmdFlags,
+		MaxArgs:   1,
+		Hooks:     []hook{validateGopath, requireInsideApp},
+		Short:     "print the middleware and interceptor chain of routes",
+		Long: `
+Prints the request processing chain of every route in execution order, it
+helps to debug the ordering issues of middlewares and interceptors:
+
+    GET /users/:id (show_user)
+    ├── middleware  middleware.RequestTimer
+    ├── auth        form_auth
+    ├── interceptor User.Before
+    ├── interceptor User.BeforeShow
+    ├── action      User.Show
+    ├── interceptor User.After
+    ├── on panic    User.Panic
+    └── finally     User.Finally
+
+Middlewares are the 'aah.Middlewares(...)' registrations of 'app' package
+sources in order, auth is the auth scheme of the route ('auth' or domain
+'default_auth') and interceptors are the controller methods 'Before',
+'Before<Action>', 'After<Action>', 'After', 'Panic<Action>', 'Panic',
+'Finally<Action>' and 'Finally' including the embedded controllers.
+
+Format 'dot' prints Graphviz DOT graph, shared steps are merged so the
+graph shows which routes go through which middleware and interceptors:
+
+    aah routes graph -format=dot | dot -Tsvg -o routes.svg
+
+Argument filters the routes by route name or path prefix.
+
+Example(s):
+    aah routes graph
+
+    aah routes graph show_user
+
+    aah routes graph -domain=localhost /api
+`,
+	}
+)
+
+type (
+	// routeChain is the request processing chain of the route.
+	routeChain struct {
+		Route *routeListEntry
+		Steps []*routeChainStep
+	}
+
+	// routeChainStep is the step of route chain, ID identifies the step
+	// across routes.
+	routeChainStep struct {
+		ID    string
+		Kind  string
+		Label string
+	}
+)
+
+func routesGraphRun(args []string) {
+	format := strings.ToLower(*routesGraphFormatFlag)
+	if format != routesGraphFormatTree && format != routesGraphFormatDOT {
+		fatalExitf(exitUsage, "Unsupported format '%s', supported formats are 'tree' and 'dot'", *routesGraphFormatFlag)
+		return
+	}
+
+	routes, err := loadAppRoutes(appProject.BaseDir)
+	if err != nil {
+		fatalErr(newExitErr(exitRouteError, err))
+		return
+	}
+
+	appDir := filepath.Join(appProject.BaseDir, "app")
+	controllers, err := loadAppControllers(filepath.Join(appDir, "controllers"))
+	if err != nil {
+		fatalErr(newExitErr(exitRouteError, fmt.Errorf("controllers: %s", err)))
+		return
+	}
+
+	middlewares, err := loadAppMiddlewares(appDir)
+	if err != nil {
+		fatalErr(newExitErr(exitParseError, err))
+		return
+	}
+
+	var chains []*routeChain
+	for _, e := range routeListEntries(routes, controllers, appProject.BaseDir) {
+		if d := *routesGraphDomainFlag; !ess.IsStrEmpty(d) && e.Domain != d && e.Host != d {
+			continue
+		}
+		if len(args) > 0 && e.Name != args[0] && !strings.HasPrefix(e.Path, args[0]) {
+			continue
+		}
+		chains = append(chains, newRouteChain(e, middlewares))
+	}
+	if len(chains) == 0 {
+		fatalExit(exitRouteError, "No matching routes found in 'config/routes.conf'")
+		return
+	}
+
+	if format == routesGraphFormatDOT {
+		writeRoutesDOT(os.Stdout, chains)
+		return
+	}
+	writeRoutesTree(os.Stdout, chains)
+}
+
+// newRouteChain method creates the chain of route in execution order,
+// interceptors of the controller wrap the action.
+func newRouteChain(e *routeListEntry, middlewares []string) *routeChain {
+	rc := &routeChain{Route: e}
+	for _, mw := range middlewares {
+		rc.Steps = append(rc.Steps, &routeChainStep{ID: "middleware:" + mw, Kind: "middleware", Label: mw})
+	}
+	if !ess.IsStrEmpty(e.Auth) {
+		rc.Steps = append(rc.Steps, &routeChainStep{ID: "auth:" + e.Auth, Kind: "auth", Label: e.Auth})
+	}
+
+	action := &routeChainStep{ID: "action:" + e.Controller + "." + e.Action, Kind: "action", Label: e.Controller + "." + e.Action}
+	if !e.Implemented {
+		action.Label += " (missing)"
+	}
+	actionAdded := false
+	for _, name := range e.Interceptors {
+		kind := "interceptor"
+		switch {
+		case strings.HasPrefix(name, "Panic"):
+			kind = "on panic"
+		case strings.HasPrefix(name, "Finally"):
+			kind = "finally"
+		}
+		if !actionAdded && !strings.HasPrefix(name, "Before") {
+			rc.Steps = append(rc.Steps, action)
+			actionAdded = true
+		}
+		label := e.Controller + "." + name
+		rc.Steps = append(rc.Steps, &routeChainStep{ID: "interceptor:" + label, Kind: kind, Label: label})
+	}
+	if !actionAdded {
+		rc.Steps = append(rc.Steps, action)
+	}
+	return rc
+}
+
+// writeRoutesTree method writes the route chains as tree.
+func writeRoutesTree(w io.Writer, chains []*routeChain) {
+	width := 0
+	for _, rc := range chains {
+		for _, s := range rc.Steps {
+			if len(s.Kind) > width {
+				width = len(s.Kind)
+			}
+		}
+	}
+
+	for i, rc := range chains {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s %s (%s)\n", rc.Route.Method, rc.Route.Path, rc.Route.Name)
+		for j, s := range rc.Steps {
+			branch := "├──"
+			if j == len(rc.Steps)-1 {
+				branch = "└──"
+			}
+			fmt.Fprintf(w, "%s %-*s %s\n", branch, width, s.Kind, s.Label)
+		}
+	}
+}
+
+// writeRoutesDOT method writes the route chains as Graphviz DOT graph, same
+// steps of the routes are one node.
+func writeRoutesDOT(w io.Writer, chains []*routeChain) {
+	nodes := map[string]string{}
+	var nodeIDs []string
+	node := func(id, label, shape string) string {
+		if name, found := nodes[id]; found {
+			return name
+		}
+		name := "n" + strconv.Itoa(len(nodes))
+		nodes[id] = name
+		nodeIDs = append(nodeIDs, fmt.Sprintf("  %s [label=%s, shape=%s];", name, strconv.Quote(label), shape))
+		return name
+	}
+
+	shapes := map[string]string{"middleware": "box", "auth": "hexagon", "action": "doubleoctagon",
+		"interceptor": "ellipse", "on panic": "octagon", "finally": "ellipse"}
+	edges := map[string]bool{}
+	var edgeList []string
+	for _, rc := range chains {
+		prev := node("route:"+rc.Route.Domain+":"+rc.Route.Method+":"+rc.Route.Path,
+			rc.Route.Method+" "+rc.Route.Path, "note")
+		for _, s := range rc.Steps {
+			cur := node(s.ID, s.Label, shapes[s.Kind])
+			if edge := fmt.Sprintf("  %s -> %s;", prev, cur); !edges[edge] {
+				edges[edge] = true
+				edgeList = append(edgeList, edge)
+			}
+			prev = cur
+		}
+	}
+	sort.Strings(edgeList)
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("digraph routes {\n  rankdir=LR;\n  node [fontname=\"Helvetica\"];\n")
+	buf.WriteString(strings.Join(nodeIDs, "\n") + "\n")
+	buf.WriteString(strings.Join(edgeList, "\n") + "\n}\n")
+	_, _ = w.Write(buf.Bytes())
+}
+
+// loadAppMiddlewares method returns the middlewares registered via
+// 'aah.Middlewares(...)' in the application sources in file order,
+// controllers and test files are skipped.
+func loadAppMiddlewares(appDir string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(appDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if p != appDir && (info.Name() == "controllers" || strings.HasPrefix(info.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(p, ".go") && !strings.HasSuffix(p, "_test.go") {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var middlewares []string
+	fset := token.NewFileSet()
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		aahName := ""
+		for _, imp := range f.Imports {
+			if strings.Trim(imp.Path.Value, `"`) == "aahframework.org/aah.v0" {
+				aahName = "aah"
+				if imp.Name != nil {
+					aahName = imp.Name.Name
+				}
+			}
+		}
+		if ess.IsStrEmpty(aahName) {
+			continue
+		}
+
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "Middlewares" {
+				return true
+			}
+			if x, ok := sel.X.(*ast.Ident); ok && x.Name == aahName {
+				for _, arg := range call.Args {
+					middlewares = append(middlewares, goExprString(fset, arg))
+				}
+			}
+			return true
+		})
+	}
+	return middlewares, nil
+}
+
+func init() {
+	routesGraphCmd.Run = routesGraphRun
+	routesCmd.Subcommands = append(routesCmd.Subcommands, routesGraphCmd)
+}
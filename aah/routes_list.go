@@ -0,0 +1,202 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+)
+
+var (
+	routesListCmdFlags   = flag.NewFlagSet("list", flag.ContinueOnError)
+	routesListJSONFlag   = routesListCmdFlags.Bool("json", false, "Print the routes as JSON for tooling")
+	routesListDomainFlag = routesListCmdFlags.String("domain", "", "List the routes of given domain only")
+	routesListCmd        = &command{
+		Name:      "list",
+		UsageLine: "aah routes list [-json] [-domain]",
+		Flags:     routesListCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp},
+		Short:     "print the application routes",
+		Long: `
+Prints the routes of 'config/routes.conf' with the controller actions of
+'app/controllers', sorted by domain, path and method:
+
+    METHOD  PATH        CONTROLLER.ACTION  NAME   MIDDLEWARE
+    GET     /           App.Index          index  -
+    GET     /users/:id  v1/User.Show       show   auth:form_auth, Before, AfterShow
+
+Middleware lists the auth scheme of the route ('auth' or domain
+'default_auth') and the controller interceptors in execution order. Action
+which does not exist in the controller is marked with '(missing)'.
+
+Use flag '-json' to print the routes as JSON array for tooling.
+
+Example(s):
+    aah routes list
+
+    aah routes list -domain=localhost
+
+    aah routes list -json
+`,
+	}
+)
+
+// routeListEntry is the route of 'aah routes list' output.
+type routeListEntry struct {
+	Domain       string   `json:"domain"`
+	Host         string   `json:"host"`
+	Method       string   `json:"method"`
+	Path         string   `json:"path"`
+	Controller   string   `json:"controller"`
+	Action       string   `json:"action"`
+	Name         string   `json:"name"`
+	Auth         string   `json:"auth,omitempty"`
+	Interceptors []string `json:"interceptors"`
+	Implemented  bool     `json:"implemented"`
+	File         string   `json:"file,omitempty"`
+	Line         int      `json:"line,omitempty"`
+}
+
+func routesListRun(args []string) {
+	routes, err := loadAppRoutes(appProject.BaseDir)
+	if err != nil {
+		fatalErr(newExitErr(exitRouteError, err))
+		return
+	}
+
+	controllers, err := loadAppControllers(filepath.Join(appProject.BaseDir, "app", "controllers"))
+	if err != nil {
+		fatalErr(newExitErr(exitRouteError, fmt.Errorf("controllers: %s", err)))
+		return
+	}
+
+	entries := routeListEntries(routes, controllers, appProject.BaseDir)
+	if domain := *routesListDomainFlag; !ess.IsStrEmpty(domain) {
+		filtered := entries[:0]
+		for _, e := range entries {
+			if e.Domain == domain || e.Host == domain {
+				filtered = append(filtered, e)
+			}
+		}
+		entries = filtered
+	}
+
+	if *routesListJSONFlag {
+		if entries == nil {
+			entries = []*routeListEntry{}
+		}
+		b, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			fatal(err)
+			return
+		}
+		fmt.Println(string(b))
+		return
+	}
+	writeRoutesTable(os.Stdout, entries)
+}
+
+// routeListEntries method resolves the controller action and interceptors
+// of the routes, file path is relative to the base directory.
+func routeListEntries(routes []*appRoute, controllers map[string]*appController, baseDir string) []*routeListEntry {
+	var entries []*routeListEntry
+	for _, r := range routes {
+		e := &routeListEntry{
+			Domain:       r.Domain,
+			Host:         r.Host,
+			Method:       r.Method,
+			Path:         r.Path,
+			Controller:   r.Controller,
+			Action:       r.Action,
+			Name:         r.Name,
+			Auth:         r.Auth,
+			Interceptors: []string{},
+		}
+		if c := lookupController(controllers, r.Controller); c != nil {
+			if a := c.Action(r.Action); a != nil {
+				e.Implemented = true
+				e.File, e.Line = a.File, a.Line
+				if rel, err := filepath.Rel(baseDir, a.File); err == nil {
+					e.File = filepath.ToSlash(rel)
+				}
+			}
+			e.Interceptors = append(e.Interceptors, c.Interceptors(r.Action)...)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// writeRoutesTable method writes the routes as aligned table, domain header
+// is written if routes have more than one domain.
+func writeRoutesTable(w io.Writer, entries []*routeListEntry) {
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No routes found in 'config/routes.conf'")
+		return
+	}
+
+	header := []string{"METHOD", "PATH", "CONTROLLER.ACTION", "NAME", "MIDDLEWARE"}
+	widths := make([]int, len(header))
+	rows := make([][]string, 0, len(entries))
+	multiDomain := false
+	for _, e := range entries {
+		multiDomain = multiDomain || e.Domain != entries[0].Domain
+		rows = append(rows, e.columns())
+	}
+	for _, row := range append([][]string{header}, rows...) {
+		for i, col := range row {
+			if len(col) > widths[i] {
+				widths[i] = len(col)
+			}
+		}
+	}
+
+	domain := ""
+	for i, row := range append([][]string{header}, rows...) {
+		if i > 0 && multiDomain && entries[i-1].Domain != domain {
+			domain = entries[i-1].Domain
+			fmt.Fprintf(w, "\n[%s] %s\n", domain, entries[i-1].Host)
+		}
+		var line string
+		for j, col := range row {
+			if j == len(row)-1 {
+				line += col
+				break
+			}
+			line += fmt.Sprintf("%-*s  ", widths[j], col)
+		}
+		fmt.Fprintln(w, strings.TrimRight(line, " "))
+	}
+}
+
+// columns method returns the table columns of the route.
+func (e *routeListEntry) columns() []string {
+	action := e.Controller + "." + e.Action
+	if !e.Implemented {
+		action += " (missing)"
+	}
+
+	var middleware []string
+	if !ess.IsStrEmpty(e.Auth) {
+		middleware = append(middleware, "auth:"+e.Auth)
+	}
+	middleware = append(middleware, e.Interceptors...)
+	if len(middleware) == 0 {
+		middleware = []string{"-"}
+	}
+	return []string{e.Method, e.Path, action, e.Name, strings.Join(middleware, ", ")}
+}
+
+func init() {
+	routesListCmd.Run = routesListRun
+	routesCmd.Subcommands = append(routesCmd.Subcommands, routesListCmd)
+}
@@ -0,0 +1,223 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestRoutesValidate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-routes-validate")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "config"), 0755))
+
+	routes := `domains {
+  localhost {
+    host = "localhost"
+    static {
+      public { path = "/static", dir = "static" }
+    }
+    routes {
+      index { path = "/", controller = "App" }
+      assets { path = "/static/app.css", controller = "App" }
+      users {
+        path = "/users"
+        method = "GET, FETCH"
+        controller = "User"
+        routes {
+          show { path = "/:id" }
+          posts { path = "/:userId/posts" }
+          index { path = "/list" }
+        }
+      }
+      files { path = "/files/*path/meta", controller = "File" }
+      bad { path = "users/:id:name", controller = "User" }
+      create { path = "/users", method = "POST", controller = "User" }
+      create_again { path = "/users", method = "post", controller = "User" }
+      new_user { path = "/users/new", controller = "User" }
+    }
+  }
+}
+`
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "config", "routes.conf"), []byte(routes), 0644))
+
+	buf := &bytes.Buffer{}
+	errs, _ := printConfProblems(buf, dir, validateRoutes(dir))
+	assert.Equal(t, "config/routes.conf:9:16: error: route 'assets' path '/static/app.css' is unreachable, it's shadowed by catch-all route 'public' path '/static/*filepath'\n"+
+		"\thint: catch-all '*filepath' matches every path under '/static/', move route 'assets' out of it or move the catch-all to its own prefix\n"+
+		"config/routes.conf:12:9: error: route 'users' has invalid HTTP method 'FETCH'\n"+
+		"config/routes.conf:16:19: error: route 'posts' parameter ':userId' conflicts with ':id' of route 'show' at same position\n"+
+		"\thint: rename ':userId' to ':id' in path '/users/:userId/posts', parameters of same position must have same name\n"+
+		"config/routes.conf:17:11: error: duplicate route name 'index' in domain 'localhost', first defined at "+
+		filepath.Join(dir, "config", "routes.conf")+":8:7\n"+
+		"config/routes.conf:17:19: warning: route 'index' path '/users/list' overlaps parameter ':id' of route 'show' path '/users/:id', '/users/list' is never matched by ':id'\n"+
+		"\thint: if 'list' is a valid ':id' value, move route 'index' to a distinct prefix e.g. '/users/-/list' or route 'show' to e.g. '/users/id/:id'\n"+
+		"config/routes.conf:20:15: error: route 'files' path '/files/*path/meta' has catch-all parameter '*path' before the last segment\n"+
+		"config/routes.conf:21:13: error: route 'bad' path 'users/:id:name' must start with '/'\n"+
+		"config/routes.conf:21:13: error: route 'bad' path 'users/:id:name' has invalid parameter name ':id:name'\n"+
+		"config/routes.conf:23:22: error: route 'create_again' path '/users' conflicts with route 'create' path '/users'\n"+
+		"\thint: change the path or method of route 'create_again', or remove it if it's a duplicate of route 'create'\n"+
+		"config/routes.conf:24:18: warning: route 'new_user' path '/users/new' overlaps parameter ':id' of route 'show' path '/users/:id', '/users/new' is never matched by ':id'\n"+
+		"\thint: if 'new' is a valid ':id' value, move route 'new_user' to a distinct prefix e.g. '/users/-/new' or route 'show' to e.g. '/users/id/:id'\n",
+		buf.String())
+	assert.Equal(t, 8, errs)
+
+	err = checkRoutesConf(dir)
+	assert.NotNil(t, err)
+	assert.Equal(t, exitRouteError, exitCodeOf(err))
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "config", "routes.conf"),
+		[]byte(`domains { localhost { host = "localhost", routes { index { path = "/" } } } }`), 0644))
+	assert.Nil(t, checkRoutesConf(dir))
+}
+
+func TestRoutesList(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-routes-list")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	ctrlDir := filepath.Join(dir, "app", "controllers")
+	assert.Nil(t, os.MkdirAll(filepath.Join(ctrlDir, "v1"), 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(ctrlDir, "app.go"), []byte(`package controllers
+
+import "aahframework.org/aah.v0"
+
+// AppController is the home controller.
+type AppController struct {
+	*aah.Context
+}
+
+// Index method is the home page.
+func (c *AppController) Index() {}
+
+func (c *AppController) Before() {}
+
+func (c *AppController) helper() {}
+`), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(ctrlDir, "v1", "user.go"), []byte(`package v1
+
+type BaseController struct{}
+
+func (c *BaseController) Finally() {}
+
+func (c *BaseController) Index() {}
+
+type UserController struct {
+	BaseController
+}
+
+func (c *UserController) Show(id int64, format string) {}
+
+func (c *UserController) AfterShow() {}
+
+func (c *UserController) Count() int { return 0 }
+`), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(ctrlDir, "v1", "user_test.go"), []byte(`package v1
+
+type FakeController struct{}
+
+func (c *FakeController) Index() {}
+`), 0644))
+
+	controllers, err := loadAppControllers(ctrlDir)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(controllers))
+	assert.Nil(t, lookupController(controllers, "FakeController"))
+
+	user := lookupController(controllers, "UserController")
+	assert.Equal(t, "v1/UserController", user.Name)
+	assert.Equal(t, []string{"Index", "Show"}, user.Actions())
+	assert.Equal(t, []string{"AfterShow", "Finally"}, user.Interceptors("Show"))
+	assert.Equal(t, "format", user.Action("Show").Params[1].Name)
+	assert.Equal(t, "int64", user.Action("Show").Params[0].Type)
+	assert.Equal(t, "Index method is the home page.", lookupController(controllers, "AppController").Action("Index").Doc)
+
+	routes := []*appRoute{
+		{Domain: "localhost", Host: "localhost", Name: "index", Path: "/", Method: "GET", Controller: "AppController", Action: "Index"},
+		{Domain: "localhost", Host: "localhost", Name: "show_user", Path: "/v1/users/:id", Method: "GET", Controller: "v1/UserController", Action: "Show", Auth: "form_auth"},
+		{Domain: "localhost", Host: "localhost", Name: "delete_user", Path: "/v1/users/:id", Method: "DELETE", Controller: "v1/UserController", Action: "Delete", Auth: "form_auth"},
+	}
+	entries := routeListEntries(routes, controllers, dir)
+	assert.Equal(t, "app/controllers/v1/user.go", entries[1].File)
+	assert.Equal(t, 13, entries[1].Line)
+	assert.False(t, entries[2].Implemented)
+
+	buf := &bytes.Buffer{}
+	writeRoutesTable(buf, entries)
+	assert.Equal(t, "METHOD  PATH           CONTROLLER.ACTION                   NAME         MIDDLEWARE\n"+
+		"GET     /              AppController.Index                 index        Before\n"+
+		"GET     /v1/users/:id  v1/UserController.Show              show_user    auth:form_auth, AfterShow, Finally\n"+
+		"DELETE  /v1/users/:id  v1/UserController.Delete (missing)  delete_user  auth:form_auth, Finally\n", buf.String())
+}
+
+func TestRoutesGraph(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-routes-graph")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "controllers"), 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "init.go"), []byte(`package app
+
+import (
+	fw "aahframework.org/aah.v0"
+	"example.com/myapp/app/middleware"
+)
+
+func init() {
+	fw.Middlewares(middleware.RequestTimer, middleware.Gzip)
+}
+`), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "controllers", "app.go"), []byte(`package controllers
+
+import "aahframework.org/aah.v0"
+
+func init() { aah.Middlewares(ignored) }
+`), 0644))
+
+	middlewares, err := loadAppMiddlewares(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"middleware.RequestTimer", "middleware.Gzip"}, middlewares)
+
+	entries := []*routeListEntry{
+		{Domain: "localhost", Method: "GET", Path: "/users/:id", Name: "show_user", Controller: "User", Action: "Show",
+			Auth: "form_auth", Implemented: true, Interceptors: []string{"Before", "AfterShow", "Panic", "Finally"}},
+		{Domain: "localhost", Method: "GET", Path: "/", Name: "index", Controller: "App", Action: "Index",
+			Interceptors: []string{"Before"}},
+	}
+	chains := []*routeChain{newRouteChain(entries[0], middlewares), newRouteChain(entries[1], nil)}
+
+	buf := &bytes.Buffer{}
+	writeRoutesTree(buf, chains)
+	assert.Equal(t, "GET /users/:id (show_user)\n"+
+		"├── middleware  middleware.RequestTimer\n"+
+		"├── middleware  middleware.Gzip\n"+
+		"├── auth        form_auth\n"+
+		"├── interceptor User.Before\n"+
+		"├── action      User.Show\n"+
+		"├── interceptor User.AfterShow\n"+
+		"├── on panic    User.Panic\n"+
+		"└── finally     User.Finally\n"+
+		"\n"+
+		"GET / (index)\n"+
+		"├── interceptor App.Before\n"+
+		"└── action      App.Index (missing)\n", buf.String())
+
+	buf.Reset()
+	writeRoutesDOT(buf, []*routeChain{chains[0], newRouteChain(entries[1], middlewares)})
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "digraph routes {\n"))
+	assert.Equal(t, 1, strings.Count(out, `label="middleware.Gzip"`))
+	assert.True(t, strings.Contains(out, `n0 [label="GET /users/:id", shape=note];`))
+	assert.True(t, strings.Contains(out, "  n2 -> n3;\n"))
+	assert.True(t, strings.Contains(out, "  n9 -> n1;\n"))
+}
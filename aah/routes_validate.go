@@ -0,0 +1,340 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+)
+
+var (
+	routeMethods = map[string]bool{
+		"GET": true, "HEAD": true, "POST": true, "PUT": true, "PATCH": true,
+		"DELETE": true, "OPTIONS": true, "TRACE": true, "CONNECT": true,
+	}
+
+	routeParamNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	routeSectionRegex   = regexp.MustCompile(`^domains\.[^.]+\.routes\.[^.]+(?:\.routes\.[^.]+)*$`)
+	staticSectionRegex  = regexp.MustCompile(`^domains\.[^.]+\.static\.[^.]+$`)
+)
+
+// routeIssue is the conflict or overlap of two routes with the suggestion to
+// resolve it, it's reported at the route. Key identifies the overlap to
+// report it once.
+type routeIssue struct {
+	Route *routeDef
+	Key   string
+	Msg   string
+	Hint  string
+}
+
+// routeDef is the route definition of 'config/routes.conf' with its
+// position, the path includes the parent route path.
+type routeDef struct {
+	Domain  string
+	Name    string
+	Path    string
+	Methods []string
+	Entry   *confEntry
+}
+
+// validateRoutes method validates 'config/routes.conf' against the schema
+// and the router rules, so route errors surface at build time instead of
+// application start or first request.
+func validateRoutes(baseDir string) []*confProblem {
+	routesFile := filepath.Join(baseDir, "config", "routes.conf")
+	if !ess.IsFileExists(routesFile) {
+		return nil
+	}
+
+	entries, err := parseConfFile(routesFile)
+	if err != nil {
+		return []*confProblem{confErrorProblem(routesFile, err)}
+	}
+	problems := append(lintConf(routesFile, entries, routesConfSchema), checkRoutes(entries)...)
+	sortConfProblems(problems)
+	return problems
+}
+
+// checkRoutes method checks the route definitions:
+//   - HTTP methods are valid
+//   - path starts with '/', parameters ':name' and '*name' are valid, unique
+//     and catch-all is the last segment
+//   - route names are unique in the domain, it's used for reverse URL
+//   - routes don't conflict with each other and aren't shadowed by a
+//     catch-all route of the same method
+//   - static path segment overlapping parameter of other route is warned,
+//     e.g. '/users/new' and '/users/:id'
+//
+// Conflicts and overlaps are reported with a hint to resolve them.
+func checkRoutes(entries []*confEntry) []*confProblem {
+	var problems []*confProblem
+	report := func(e *confEntry, format string, args ...interface{}) {
+		problems = append(problems, &confProblem{File: e.File, Line: e.Line, Col: e.Col,
+			Severity: confSeverityError, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	defined := map[string]*confEntry{}
+	for _, e := range entries {
+		defined[e.Path] = e
+	}
+
+	var routes []*routeDef
+	names := map[string]*confEntry{}
+	for _, e := range entries {
+		if e.Kind != confSection {
+			continue
+		}
+
+		var r *routeDef
+		switch {
+		case routeSectionRegex.MatchString(e.Path):
+			r = newRouteDef(e, defined)
+			key := r.Domain + "." + r.Name
+			if first, found := names[key]; found {
+				report(e, "duplicate route name '%s' in domain '%s', first defined at %s", r.Name, r.Domain, first.Pos())
+				if first.Path == e.Path {
+					continue
+				}
+			} else {
+				names[key] = e
+			}
+		case staticSectionRegex.MatchString(e.Path):
+			r = newStaticRouteDef(e, defined)
+		default:
+			continue
+		}
+
+		if me, found := defined[e.Path+".method"]; found {
+			for _, m := range r.Methods {
+				if !routeMethods[m] {
+					report(me, "route '%s' has invalid HTTP method '%s'", r.Name, m)
+				}
+			}
+		}
+
+		// route without path is reported by schema validation
+		pe, found := defined[e.Path+".path"]
+		if !found || pe.Kind != confString {
+			continue
+		}
+		r.Entry = pe
+		if !strings.HasPrefix(pe.Value, "/") {
+			report(pe, "route '%s' path '%s' must start with '/'", r.Name, pe.Value)
+		}
+		if msg := checkRoutePath(r.Path); !ess.IsStrEmpty(msg) {
+			report(pe, "route '%s' path '%s' %s", r.Name, r.Path, msg)
+		}
+		routes = append(routes, r)
+	}
+
+	overlaps := map[string]bool{}
+	for i, r := range routes {
+		for _, other := range routes[:i] {
+			if r.Domain != other.Domain || !r.sameMethod(other) {
+				continue
+			}
+			if c := routeConflict(other, r); c != nil {
+				problems = append(problems, &confProblem{File: r.Entry.File, Line: r.Entry.Line, Col: r.Entry.Col,
+					Severity: confSeverityError, Msg: c.Msg, Hint: c.Hint})
+				break
+			}
+			if o := routeOverlap(other, r); o != nil && !overlaps[o.Key] {
+				overlaps[o.Key] = true
+				e := o.Route.Entry
+				problems = append(problems, &confProblem{File: e.File, Line: e.Line, Col: e.Col,
+					Severity: confSeverityWarning, Msg: o.Msg, Hint: o.Hint})
+			}
+		}
+	}
+	return problems
+}
+
+// checkRoutesConf method validates the routes and logs the problems, it
+// returns route error if any error is found.
+func checkRoutesConf(baseDir string) error {
+	buf := &bytes.Buffer{}
+	errs, warns := printConfProblems(buf, baseDir, validateRoutes(baseDir))
+	if errs == 0 {
+		if warns > 0 {
+			logWarnf("Routes configuration warnings:\n%s", buf.String())
+		}
+		return nil
+	}
+	return newExitErr(exitRouteError, fmt.Errorf("routes configuration has %d error(s):\n%s", errs, strings.TrimSpace(buf.String())))
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// newRouteDef method creates the route definition of the route section,
+// nested route path is joined with the parent route path.
+func newRouteDef(e *confEntry, defined map[string]*confEntry) *routeDef {
+	keys := strings.Split(e.Path, ".")
+	r := &routeDef{Domain: keys[1], Name: keys[len(keys)-1], Entry: e}
+
+	for i := 3; i < len(keys); i += 2 {
+		if pe, found := defined[strings.Join(keys[:i+1], ".")+".path"]; found {
+			r.Path = joinRoutePath(r.Path, pe.Value)
+		}
+	}
+
+	method := "GET"
+	if me, found := defined[e.Path+".method"]; found && me.Kind == confString {
+		method = me.Value
+	}
+	for _, m := range strings.Split(method, ",") {
+		if m = strings.ToUpper(strings.TrimSpace(m)); !ess.IsStrEmpty(m) {
+			r.Methods = append(r.Methods, m)
+		}
+	}
+	return r
+}
+
+// newStaticRouteDef method creates the route definition of the static
+// route, directory is served via catch-all path.
+func newStaticRouteDef(e *confEntry, defined map[string]*confEntry) *routeDef {
+	keys := strings.Split(e.Path, ".")
+	r := &routeDef{Domain: keys[1], Name: keys[len(keys)-1], Methods: []string{"GET", "HEAD"}, Entry: e}
+	if pe, found := defined[e.Path+".path"]; found {
+		r.Path = pe.Value
+	}
+	if _, found := defined[e.Path+".dir"]; found {
+		r.Path = strings.TrimSuffix(r.Path, "/") + "/*filepath"
+	}
+	return r
+}
+
+// checkRoutePath method returns the problem of path parameters, empty if
+// path is valid.
+func checkRoutePath(p string) string {
+	params := map[string]bool{}
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		if idx := strings.IndexAny(s, ":*"); idx > 0 {
+			return fmt.Sprintf("has parameter in the middle of segment '%s'", s)
+		} else if idx < 0 {
+			continue
+		}
+
+		name := s[1:]
+		if !routeParamNameRegex.MatchString(name) {
+			return fmt.Sprintf("has invalid parameter name '%s'", s)
+		}
+		if params[name] {
+			return fmt.Sprintf("has duplicate parameter '%s'", name)
+		}
+		params[name] = true
+
+		if s[0] == '*' && i != len(segments)-1 {
+			return fmt.Sprintf("has catch-all parameter '%s' before the last segment", s)
+		}
+	}
+	return ""
+}
+
+// routeConflict method returns the conflict of route r with the previously
+// defined route, nil if there is no conflict. Conflicting routes fail at
+// application start or never receive the requests.
+func routeConflict(prev, r *routeDef) *routeIssue {
+	prevSegs, segs := strings.Split(prev.Path, "/"), strings.Split(r.Path, "/")
+	for i := 0; i < len(prevSegs) && i < len(segs); i++ {
+		ps, s := prevSegs[i], segs[i]
+		switch {
+		case strings.HasPrefix(ps, "*"):
+			return &routeIssue{
+				Msg: fmt.Sprintf("route '%s' path '%s' is unreachable, it's shadowed by catch-all route '%s' path '%s'",
+					r.Name, r.Path, prev.Name, prev.Path),
+				Hint: fmt.Sprintf("catch-all '%s' matches every path under '%s/', move route '%s' out of it or move the catch-all to its own prefix",
+					ps, strings.Join(prevSegs[:i], "/"), r.Name),
+			}
+		case strings.HasPrefix(s, "*"):
+			return &routeIssue{
+				Msg: fmt.Sprintf("route '%s' catch-all path '%s' shadows route '%s' path '%s'",
+					r.Name, r.Path, prev.Name, prev.Path),
+				Hint: fmt.Sprintf("catch-all '%s' matches every path under '%s/', move route '%s' out of it or move the catch-all to its own prefix",
+					s, strings.Join(segs[:i], "/"), prev.Name),
+			}
+		case strings.HasPrefix(ps, ":") && strings.HasPrefix(s, ":"):
+			if ps != s {
+				return &routeIssue{
+					Msg: fmt.Sprintf("route '%s' parameter '%s' conflicts with '%s' of route '%s' at same position",
+						r.Name, s, ps, prev.Name),
+					Hint: fmt.Sprintf("rename '%s' to '%s' in path '%s', parameters of same position must have same name",
+						s, ps, r.Path),
+				}
+			}
+		case ps != s:
+			return nil
+		}
+	}
+
+	if len(prevSegs) == len(segs) {
+		return &routeIssue{
+			Msg: fmt.Sprintf("route '%s' path '%s' conflicts with route '%s' path '%s'", r.Name, r.Path, prev.Name, prev.Path),
+			Hint: fmt.Sprintf("change the path or method of route '%s', or remove it if it's a duplicate of route '%s'",
+				r.Name, prev.Name),
+		}
+	}
+	return nil
+}
+
+// routeOverlap method returns the overlap of static path segment and
+// parameter at same position of the routes, e.g. '/users/new' and
+// '/users/:id'. Static segment takes precedence and router does not fall back
+// to the parameter route, so parameter route paths under the static segment
+// end up in 404, e.g. '/users/new/edit' of '/users/:id/edit'.
+func routeOverlap(prev, r *routeDef) *routeIssue {
+	prevSegs, segs := strings.Split(prev.Path, "/"), strings.Split(r.Path, "/")
+	for i := 0; i < len(prevSegs) && i < len(segs); i++ {
+		ps, s := prevSegs[i], segs[i]
+		if ps == s {
+			continue
+		}
+
+		static, param := prev, r
+		staticSeg, paramSeg := ps, s
+		switch {
+		case isRouteParamSeg(ps) && !isRouteParamSeg(s):
+			static, param, staticSeg, paramSeg = r, prev, s, ps
+		case !isRouteParamSeg(ps) && isRouteParamSeg(s):
+		default:
+			return nil
+		}
+
+		prefix := strings.Join(segs[:i], "/")
+		staticPath := prefix + "/" + staticSeg
+		return &routeIssue{
+			Route: static,
+			Key:   static.Domain + ":" + static.Name + ":" + staticPath,
+			Msg: fmt.Sprintf("route '%s' path '%s' overlaps parameter '%s' of route '%s' path '%s', '%s' is never matched by '%s'",
+				static.Name, static.Path, paramSeg, param.Name, param.Path, staticPath, paramSeg),
+			Hint: fmt.Sprintf("if '%s' is a valid '%s' value, move route '%s' to a distinct prefix e.g. '%s/-/%s' or route '%s' to e.g. '%s/id/%s'",
+				staticSeg, paramSeg, static.Name, prefix, staticSeg, param.Name, prefix, paramSeg),
+		}
+	}
+	return nil
+}
+
+func isRouteParamSeg(s string) bool {
+	return strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*")
+}
+
+func (r *routeDef) sameMethod(other *routeDef) bool {
+	for _, m := range r.Methods {
+		for _, om := range other.Methods {
+			if m == om {
+				return true
+			}
+		}
+	}
+	return false
+}
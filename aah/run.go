@@ -6,6 +6,17 @@ package main
 
 import (
 	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"aahframework.org/aah.v0"
 	"aahframework.org/essentials.v0"
@@ -20,13 +31,59 @@ var (
 	runConfigShortFlag     = runCmdFlags.String("c", "", "External config for overriding aah.conf")
 	runProfileFlag         = runCmdFlags.String("profile", "", "Environment profile name to activate. e.g: dev, qa, prod")
 	runProfileShortFlag    = runCmdFlags.String("p", "", "Environment profile name to activate. e.g: dev, qa, prod")
+	runEnvFlag             = runCmdFlags.String("env", "", "Run environment name from 'run.env' section of aah.project. e.g: staging")
+	runEnvShortFlag        = runCmdFlags.String("e", "", "Run environment name from 'run.env' section of aah.project. e.g: staging")
+	runNoWatchFlag         = runCmdFlags.Bool("nowatch", false, "Disable the automatic rebuild and restart on file changes")
+	runInstancesFlag       = runCmdFlags.Int("instances", 1, "Number of application instances to run, each on its own port")
+	runDebugFlag           = runCmdFlags.Bool("debug", false, "Build without optimizations and run the application under delve debugger")
+	runDebugPortFlag       = runCmdFlags.Int("debugPort", 2345, "Headless delve debugger port, used with '-debug'")
 	runCmd                 = &command{
 		Name:      "run",
-		UsageLine: "aah run [-ip | -importPath] [-c | -config] [-p | -profile]",
-		ArgsCount: 3,
+		UsageLine: "aah run [-ip | -importPath] [-c | -config] [-p | -profile] [-e | -env] [-instances] [-nowatch] [-debug [-debugPort]]",
+		Flags:     runCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
 		Short:     "run aah framework application",
 		Long: `
-Run the aah framework web/api application.
+Run the aah framework web/api application. It builds the application, starts
+it and watches the directories 'app', 'config' and 'views' for changes. On
+change, application is rebuilt and restarted automatically. If the rebuild
+fails, running application is kept as-is until the next successful rebuild.
+
+Running application is stopped gracefully on restart, SIGTERM on Unix and
+CTRL_BREAK_EVENT on Windows. It's killed if it doesn't exit within
+'run.shutdown_timeout' (default '10s') of 'aah.project'.
+
+Watch directories, include/exclude patterns, debounce interval and polling
+(for NFS or Docker volumes) are configurable in 'watch' section of
+'aah.project', refer 'aah help aah.project'.
+
+Asset compilers of 'build.assets.compilers', such as Sass or TypeScript, run
+on start and on change of the files matching their 'watch' patterns, then
+browser is refreshed without application restart.
+
+In 'dev' profile, browser is refreshed after successful rebuild via live
+reload. Include the script in your view layout, 'aah new' web application
+already has it:
+    {{ with config "livereload.url" }}<script src="{{ . }}"></script>{{ end }}
+
+Application is fronted by a proxy on its 'server.port', it holds the
+requests while the application is rebuilding or restarting, including the
+backoff delay of crash restart. Application
+itself runs on a free internal port. Disable it via 'proxy.enable = false'
+in 'aah.project'. In 'dev' profile, proxy serves the Swagger UI of the
+application routes at '/_docs' if 'run.api_docs.enable = true'.
+
+Variables of '.env' and '.env.<profile>' files of the application base
+directory are set into the application environment, existing environment
+variables are not overridden. Disable it via 'run.dotenv.enable = false'
+in 'aah.project'. Precedence is run environment '-env', then secrets of
+'aah secrets', then '.env.<profile>' and '.env'.
+
+Environment variable placeholders '${VAR:-default}' of external config are
+replaced before it's passed to the application, refer 'aah help aah.project'.
+
+Remote config of 'config.remote' in 'aah.project' (HTTP(S), Consul or etcd)
+is fetched and used as external config, if '-config' is not supplied.
 
 Example(s) short and long flag:
     aah run
@@ -40,6 +97,14 @@ Example(s) short and long flag:
 		aah run -importPath=github.com/username/name -profile=qa
 		aah run -importPath=github.com/username/name -config=/path/to/config/external.conf -profile=qa
 
+    aah run -e=staging
+
+    aah run -instances=3
+
+    aah run -nowatch
+
+    aah run -debug -debugPort=2345
+
 Default aah application environment profile is 'dev'.
 
 Note: It is recommended to use build and deploy approach instead of
@@ -48,48 +113,560 @@ using 'aah run' for production use.
 	}
 )
 
-func runRun(args []string) {
-	if err := runCmdFlags.Parse(args); err != nil {
-		fatal(err)
-	}
+const (
+	// appPortEnv is the environment variable to override application
+	// 'server.port', it's applied by the generated main Go file.
+	appPortEnv = "AAH_SERVER_PORT"
+
+	// crashTailLines is the no. of last stderr lines shown on crash.
+	crashTailLines = 20
+
+	// crashStableTime is the uptime after which crash restart backoff is
+	// reset.
+	crashStableTime = 10 * time.Second
+)
+
+// devServer is the aah application run by 'aah run' along with file
+// watcher, live reload and proxy.
+type devServer struct {
+	args      []string
+	env       []string
+	opts      *compileOptions
+	dlv       string
+	debugPort int
+	instances int
+	appPort   string
+	binary    string
+	reloadSig os.Signal
+	procs     []*appProcess
+	exited    chan *appProcess
+	restart   chan *appProcess
+	crashes   map[int]int
+	maxDelay  time.Duration
+	logMu     sync.Mutex
+	lr        *liveReload
+	proxy     *devProxy
+}
 
-	importPath := firstNonEmpty(*runImportPathFlag, *runImportPathShortFlag)
-	if ess.IsStrEmpty(importPath) {
-		importPath = importPathRelwd()
+func runRun(args []string) {
+	env := &runEnv{}
+	if envName := firstNonEmpty(*runEnvFlag, *runEnvShortFlag); !ess.IsStrEmpty(envName) {
+		var err error
+		if env, err = loadRunEnv(appProject.BuildCfg, appProject.BaseDir, envName); err != nil {
+			fatalErr(newExitErr(exitConfigError, err))
+		}
+		log.Infof("Activating run environment '%s' with %d variable(s)", env.Name, len(env.Vars))
 	}
 
-	if !ess.IsImportPathExists(importPath) {
-		fatalf("Given import path '%s' does not exists", importPath)
+	secrets, err := secretsEnv(appProject.BaseDir)
+	if err != nil {
+		logWarnf("Secrets are not loaded: %s", err)
+	} else if len(secrets) > 0 {
+		log.Infof("Loaded %d secret(s) from '%s'", len(secrets), secretsFile(appProject.BaseDir))
 	}
 
 	appStartArgs := []string{}
-	configPath := getNonEmptyAbsPath(*runConfigFlag, *runConfigShortFlag)
+	configPath := firstNonEmpty(getNonEmptyAbsPath(*runConfigFlag, *runConfigShortFlag), env.Config)
+	if ess.IsStrEmpty(configPath) {
+		if configPath, err = fetchRemoteConfig(appProject.BuildCfg, appProject.BaseDir); err != nil {
+			fatalErr(newExitErr(exitConfigError, err))
+		}
+	}
 	if !ess.IsStrEmpty(configPath) {
+		var err error
+		configPath, err = interpolateConfigFile(configPath, appProject.BaseDir, filepath.Join(appProject.BaseDir, ".aah", "run"))
+		if err != nil {
+			fatalErr(newExitErr(exitConfigError, fmt.Errorf("external config: %s", err)))
+		}
 		appStartArgs = append(appStartArgs, "-config", configPath)
 	}
 
-	envProfile := firstNonEmpty(*runProfileFlag, *runProfileShortFlag)
+	envProfile := firstNonEmpty(*runProfileFlag, *runProfileShortFlag, env.Profile)
 	if !ess.IsStrEmpty(envProfile) {
 		appStartArgs = append(appStartArgs, "-profile", envProfile)
 	}
 
-	aah.Init(importPath)
+	var dotEnv []string
+	if appProject.BuildCfg.BoolDefault("run.dotenv.enable", true) {
+		var files []string
+		dotEnv, files, err = loadDotEnv(appProject.BaseDir, firstNonEmpty(envProfile, "dev"),
+			appProject.BuildCfg.BoolDefault("run.dotenv.override", false))
+		if err != nil {
+			fatalErr(newExitErr(exitConfigError, err))
+		}
+		if len(files) > 0 {
+			log.Infof("Loaded %d variable(s) from '%s'", len(dotEnv), strings.Join(files, "', '"))
+		}
+	}
 
-	buildCfg, err := loadAahProjectFile(aah.AppBaseDir())
+	shutdownTimeout, err := time.ParseDuration(appProject.BuildCfg.StringDefault("run.shutdown_timeout", "10s"))
 	if err != nil {
-		fatalf("aah project file error: %s", err)
+		fatalErr(newExitErr(exitConfigError, fmt.Errorf("'run.shutdown_timeout' is invalid: %s", err)))
 	}
+	appStopTimeout = shutdownTimeout
 
-	_ = log.SetLevel(buildCfg.StringDefault("build.log_level", "info"))
+	if *runInstancesFlag < 1 {
+		fatalExit(exitUsage, "'-instances' must be 1 or more")
+	}
+
+	ds := &devServer{
+		args:      appStartArgs,
+		env:       append(append(dotEnv, secrets...), env.Vars...),
+		opts:      &compileOptions{},
+		instances: *runInstancesFlag,
+		appPort:   aah.AppConfig().StringDefault("server.port", "8080"),
+		exited:    make(chan *appProcess),
+		restart:   make(chan *appProcess),
+		crashes:   map[int]int{},
+	}
 
-	appBinary, err := compileApp(buildCfg, false)
+	if appProject.BuildCfg.BoolDefault("run.crash_restart", true) {
+		if ds.maxDelay, err = time.ParseDuration(appProject.BuildCfg.StringDefault("run.crash_restart_max_delay", "30s")); err != nil {
+			fatalErr(newExitErr(exitConfigError, fmt.Errorf("'run.crash_restart_max_delay' is invalid: %s", err)))
+		}
+	}
+	if sigName := appProject.BuildCfg.StringDefault("run.reload_signal", ""); !ess.IsStrEmpty(sigName) {
+		if ds.reloadSig, err = parseSignal(sigName); err != nil {
+			fatalErr(newExitErr(exitConfigError, fmt.Errorf("'run.reload_signal' is invalid: %s", err)))
+		}
+	}
+
+	if *runDebugFlag {
+		if ds.instances > 1 {
+			fatalExit(exitUsage, "'-debug' cannot be used with multiple '-instances'")
+		}
+		ds.enableDebug(*runDebugPortFlag)
+	}
+
+	compilers, err := loadAssetCompilers(appProject.BuildCfg)
 	if err != nil {
-		fatal(err)
+		fatalErr(newExitErr(exitConfigError, err))
+	}
+	if err = runAssetCompilers(compilers, appProject.BaseDir); err != nil {
+		log.Errorf("Unable to compile assets: %s", err)
 	}
 
-	if _, err := execCmd(appBinary, appStartArgs, true); err != nil {
+	watch := !*runNoWatchFlag && !isDryRun()
+	if watch {
+		ds.startLiveReload(firstNonEmpty(envProfile, "dev"))
+		ds.startProxy(firstNonEmpty(envProfile, "dev"))
+	}
+
+	appBinary, err := compileApp(appProject.BuildCfg, ds.opts)
+	if err != nil {
+		fatalErr(err)
+	}
+
+	if err = ds.start(appBinary); err != nil {
 		fatal(err)
 	}
+
+	if !watch {
+		for range ds.procs {
+			if p := <-ds.exited; p.Err() != nil {
+				log.Errorf("%sApplication exited: %s", p.Name, p.Err())
+			}
+		}
+		return
+	}
+
+	watchCfg, err := loadWatchConfig(appProject.BuildCfg)
+	if err != nil {
+		ds.stop()
+		fatalErr(newExitErr(exitConfigError, err))
+	}
+
+	// asset compiler sources are watched along with the application
+	for _, c := range compilers {
+		watchCfg.Dirs = appendUnique(watchCfg.Dirs, c.watchDirs()...)
+		if len(watchCfg.Includes) > 0 {
+			watchCfg.Includes = append(watchCfg.Includes, c.Watch...)
+		}
+	}
+
+	// generated main Go files are not application changes
+	w, err := newWatcher(appProject.BaseDir, watchCfg,
+		generatedAppFiles(filepath.Join(appProject.BaseDir, "app")))
+	if err != nil {
+		ds.stop()
+		fatalf("Unable to start file watcher: %s", err)
+	}
+	w.Start()
+	defer w.Close()
+
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc, os.Interrupt, syscall.SIGTERM)
+	log.Infof("Watching for changes in %s (%s)", strings.Join(watchCfg.Dirs, ", "), w.Mode())
+
+	for {
+		select {
+		case files := <-w.Changes:
+			log.Infof("Change detected: %s", strings.Join(w.relPaths(files), ", "))
+			if files = ds.compileAssets(compilers, files); len(files) == 0 {
+				continue
+			}
+			if isSourceChange(files) {
+				ds.rebuild()
+			} else {
+				ds.reload()
+			}
+		case p := <-ds.exited:
+			if !p.Stopped() {
+				ds.crashed(p)
+			}
+		case p := <-ds.restart:
+			ds.restartInstance(p)
+		case <-sc:
+			log.Info("Stopping application")
+			ds.stop()
+			return
+		}
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// devServer methods
+//___________________________________
+
+func (ds *devServer) startLiveReload(profile string) {
+	if ds.lr = newLiveReload(appProject.BuildCfg, profile); ds.lr == nil {
+		return
+	}
+
+	if err := ds.lr.Start(); err != nil {
+		logWarnf("Live reload is disabled, unable to start: %s", err)
+		ds.lr = nil
+		return
+	}
+	_ = os.Setenv(livereloadEnvURL, ds.lr.ScriptURL())
+}
+
+// startProxy method starts the proxy on application 'server.port', the
+// application instances are started on internal ports supplied via
+// environment variable 'AAH_SERVER_PORT'. Proxy serves the Swagger UI if
+// it's enabled for the profile.
+func (ds *devServer) startProxy(profile string) {
+	proxy, err := newDevProxy(appProject.BuildCfg, ds.appPort, ds.instances)
+	if err != nil {
+		fatalErr(newExitErr(exitConfigError, err))
+	}
+
+	docs := newSwaggerUI(appProject.BuildCfg, appProject.BaseDir, profile)
+	if proxy == nil {
+		if docs != nil {
+			logWarn("API docs are not served, it requires 'proxy.enable = true'")
+		}
+		return
+	}
+	proxy.Docs = docs
+
+	if err = proxy.Start(); err != nil {
+		logWarnf("Proxy is disabled, unable to listen on port %s: %s", ds.appPort, err)
+		return
+	}
+
+	ds.proxy = proxy
+	log.Infof("Proxy listening on port %s, application runs on port(s) %s",
+		proxy.Port, strings.Join(proxy.AppPorts, ", "))
+	if docs != nil {
+		log.Infof("API docs (Swagger UI) available at http://localhost:%s%s", proxy.Port, docs.Path)
+	}
+}
+
+// enableDebug method builds the application without optimizations and
+// inlining, and runs it under headless delve debugger.
+func (ds *devServer) enableDebug(port int) {
+	dlv, err := exec.LookPath("dlv")
+	if err != nil {
+		fatalExit(exitDepsError, "Unable to find delve 'dlv' in PATH, install it via 'go get -u github.com/go-delve/delve/cmd/dlv'")
+		return
+	}
+
+	ds.dlv, ds.debugPort = dlv, port
+	ds.opts.BuildArgs = append(ds.opts.BuildArgs, "-gcflags", "all=-N -l")
+}
+
+// command method returns the executable and its arguments to start the
+// application binary, it's delve in debug mode.
+func (ds *devServer) command(appBinary string) (string, []string) {
+	if ess.IsStrEmpty(ds.dlv) {
+		return appBinary, ds.args
+	}
+
+	log.Infof("Debugger listening on 127.0.0.1:%d, attach via:", ds.debugPort)
+	log.Infof("    dlv connect 127.0.0.1:%d", ds.debugPort)
+	log.Info("    or your IDE remote debug configuration (e.g. VS Code 'remote' mode, GoLand 'Go Remote')")
+	return ds.dlv, append([]string{"exec", appBinary, "--headless", "--api-version=2",
+		"--accept-multiclient", fmt.Sprintf("--listen=127.0.0.1:%d", ds.debugPort), "--"}, ds.args...)
+}
+
+// start method starts the application instances and releases the held
+// requests once they accept connections.
+func (ds *devServer) start(appBinary string) error {
+	binary, args := ds.command(appBinary)
+	ds.binary = appBinary
+	ds.procs = nil
+	for i := 0; i < ds.instances; i++ {
+		p, err := ds.startInstance(i, binary, args)
+		if err != nil {
+			ds.stop()
+			return err
+		}
+		ds.procs = append(ds.procs, p)
+	}
+
+	if ds.proxy != nil {
+		go ds.proxy.Release()
+	}
+	return nil
+}
+
+// startInstance method starts the application instance with its port
+// override and log prefix, when multiple instances are running.
+func (ds *devServer) startInstance(i int, binary string, args []string) (*appProcess, error) {
+	env := ds.env
+	port := ""
+	if ds.proxy != nil {
+		port = ds.proxy.AppPorts[i]
+	} else if ds.instances > 1 {
+		base, err := strconv.Atoi(ds.appPort)
+		if err != nil {
+			return nil, fmt.Errorf("'server.port' value '%s' is not a number, required for '-instances'", ds.appPort)
+		}
+		port = strconv.Itoa(base + i)
+	}
+	if !ess.IsStrEmpty(port) {
+		env = append(append([]string{}, env...), appPortEnv+"="+port)
+	}
+
+	var stdout, stderr io.Writer = os.Stdout, os.Stderr
+	name := ""
+	if ds.instances > 1 {
+		name = fmt.Sprintf("[%d] ", i+1)
+		stdout = newPrefixWriter(name, os.Stdout, &ds.logMu)
+		stderr = newPrefixWriter(name, os.Stderr, &ds.logMu)
+		log.Infof("%sStarting application instance on port %s", name, port)
+	}
+
+	// last stderr lines are shown if application crashes
+	tail := newLineTail(crashTailLines)
+	p, err := startAppProcess(binary, args, env, stdout, io.MultiWriter(stderr, tail))
+	if err != nil {
+		return nil, err
+	}
+	p.Name, p.Index, p.Stderr = name, i, tail
+
+	go func() {
+		<-p.Done()
+		ds.exited <- p
+	}()
+	return p, nil
+}
+
+// crashed method reports the unexpected application exit with its last
+// stderr lines and schedules the restart with exponential backoff. Backoff
+// is reset if the application was running for `crashStableTime`.
+func (ds *devServer) crashed(p *appProcess) {
+	reason := "exited"
+	if p.Err() != nil {
+		reason = p.Err().Error()
+	}
+
+	log.Errorf("%s%s", p.Name, strings.Repeat("=", 60))
+	log.Errorf("%sApplication crashed: %s", p.Name, reason)
+	if lines := p.Stderr.Lines(); len(lines) > 0 {
+		log.Errorf("%sLast %d line(s) of stderr:", p.Name, len(lines))
+		for _, l := range lines {
+			log.Errorf("%s    %s", p.Name, l)
+		}
+	}
+	log.Errorf("%s%s", p.Name, strings.Repeat("=", 60))
+
+	if ds.maxDelay == 0 {
+		log.Info("Waiting for changes to rebuild and start the application")
+		return
+	}
+
+	if time.Since(p.Started) > crashStableTime {
+		ds.crashes[p.Index] = 0
+	}
+
+	delay := time.Second << uint(ds.crashes[p.Index])
+	if delay > ds.maxDelay || delay <= 0 {
+		delay = ds.maxDelay
+	} else {
+		ds.crashes[p.Index]++
+	}
+
+	// requests are held until the restarted application accepts connections
+	if ds.proxy != nil {
+		ds.proxy.Hold()
+	}
+
+	log.Infof("%sRestarting application in %s, or on next change", p.Name, delay)
+	time.AfterFunc(delay, func() { ds.restart <- p })
+}
+
+// restartInstance method starts the crashed application instance again,
+// unless it's already replaced by rebuild or restart.
+func (ds *devServer) restartInstance(p *appProcess) {
+	if p.Index >= len(ds.procs) || ds.procs[p.Index] != p || ess.IsStrEmpty(ds.binary) {
+		return
+	}
+
+	binary, args := ds.command(ds.binary)
+	newProc, err := ds.startInstance(p.Index, binary, args)
+	if ds.proxy != nil {
+		go ds.proxy.Release()
+	}
+	if err != nil {
+		log.Errorf("%sUnable to restart application: %s", p.Name, err)
+		return
+	}
+	ds.procs[p.Index] = newProc
+}
+
+// stop method stops all the application instances.
+func (ds *devServer) stop() {
+	for _, p := range ds.procs {
+		p.Stop()
+	}
+}
+
+// rebuild method compiles the application and restarts it. On compile error
+// the current application process is kept running. Browser is refreshed
+// after successful restart.
+func (ds *devServer) rebuild() {
+	if ds.proxy != nil {
+		ds.proxy.Hold()
+	}
+
+	// running executable cannot be replaced on Windows, new binary is built
+	// aside and swapped in after the application is stopped
+	opts := ds.opts
+	if isWindowsOS() && !ess.IsStrEmpty(ds.binary) {
+		opts = &compileOptions{BuildArgs: ds.opts.BuildArgs, Output: nextBinaryFile(ds.binary)}
+	}
+
+	appBinary, err := compileApp(appProject.BuildCfg, opts)
+	if err != nil {
+		log.Error(err)
+		log.Info("Rebuild failed, waiting for changes")
+		if ds.proxy != nil {
+			go ds.proxy.Release()
+		}
+		return
+	}
+
+	ds.stop()
+	if !ess.IsStrEmpty(opts.Output) {
+		if err = renameFile(appBinary, ds.binary); err != nil {
+			log.Errorf("Unable to replace application binary, starting the previous build: %s", err)
+		}
+		appBinary = ds.binary
+	}
+	if err = ds.start(appBinary); err != nil {
+		log.Errorf("Unable to start application: %s", err)
+		return
+	}
+
+	if ds.lr != nil {
+		ds.lr.Reload()
+	}
+}
+
+// reload method applies the config and view changes without recompiling.
+// Running application is signaled if 'run.reload_signal' is configured,
+// otherwise it's restarted with the current binary.
+func (ds *devServer) reload() {
+	if ess.IsStrEmpty(ds.binary) {
+		// application was never built successfully
+		ds.rebuild()
+		return
+	}
+
+	if ds.reloadSig != nil && len(ds.procs) > 0 && !ds.procs[0].Stopped() {
+		log.Infof("Reloading application via %s", ds.reloadSig)
+		for _, p := range ds.procs {
+			if err := p.Signal(ds.reloadSig); err != nil {
+				log.Errorf("%sUnable to signal application: %s", p.Name, err)
+			}
+		}
+	} else {
+		log.Info("Restarting application without recompile")
+		if ds.proxy != nil {
+			ds.proxy.Hold()
+		}
+
+		ds.stop()
+		if err := ds.start(ds.binary); err != nil {
+			log.Errorf("Unable to start application: %s", err)
+			return
+		}
+	}
+
+	if ds.lr != nil {
+		ds.lr.Reload()
+	}
+}
+
+// compileAssets method runs the asset compilers whose watch patterns match
+// the changed files, browser is refreshed if all the changes are compiler
+// sources. It returns the files not matched by any compiler.
+func (ds *devServer) compileAssets(compilers []*assetCompiler, files []string) []string {
+	matched := map[string]bool{}
+	var rest []string
+	for _, f := range files {
+		rel := relPath(appProject.BaseDir, f)
+		found := false
+		for _, c := range compilers {
+			if c.matches(rel) {
+				matched[c.Name] = true
+				found = true
+			}
+		}
+		if !found {
+			rest = append(rest, f)
+		}
+	}
+	if len(matched) == 0 {
+		return files
+	}
+
+	compiled := false
+	for _, c := range compilers {
+		if !matched[c.Name] {
+			continue
+		}
+		if err := c.run(appProject.BaseDir); err != nil {
+			log.Errorf("Asset compiler '%s' failed: %s", c.Name, err)
+			continue
+		}
+		compiled = true
+	}
+
+	if compiled && len(rest) == 0 && ds.lr != nil {
+		ds.lr.Reload()
+	}
+	return rest
+}
+
+// isSourceChange method reports whether any of the changed files is Go
+// source, which requires recompile. Config and view changes do not.
+// nextBinaryFile method returns the binary file path to build while the
+// application runs from the given binary, e.g. 'myapp.next.exe'.
+func nextBinaryFile(binary string) string {
+	ext := filepath.Ext(binary)
+	return strings.TrimSuffix(binary, ext) + ".next" + ext
+}
+
+func isSourceChange(files []string) bool {
+	for _, f := range files {
+		if strings.HasSuffix(f, ".go") {
+			return true
+		}
+	}
+	return false
 }
 
 func init() {
@@ -0,0 +1,49 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestRunCrashHoldsProxy(t *testing.T) {
+	app := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer app.Close()
+
+	target, _ := url.Parse(app.URL)
+	_, port, _ := net.SplitHostPort(target.Host)
+	proxy := newDevProxyWithPorts("", []string{port}, 50*time.Millisecond)
+	proxy.Release()
+
+	ds := &devServer{
+		proxy:    proxy,
+		restart:  make(chan *appProcess, 1),
+		crashes:  map[int]int{},
+		maxDelay: time.Hour,
+	}
+	ds.crashed(&appProcess{Started: time.Now(), Stderr: newLineTail(crashTailLines)})
+	assert.Equal(t, 1, ds.crashes[0])
+
+	w := httptest.NewRecorder()
+	proxy.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestRunNextBinaryFile(t *testing.T) {
+	assert.Equal(t, filepath.Join("build", "bin", "myapp.next.exe"),
+		nextBinaryFile(filepath.Join("build", "bin", "myapp.exe")))
+	assert.Equal(t, filepath.Join("build", "bin", "myapp.next"),
+		nextBinaryFile(filepath.Join("build", "bin", "myapp")))
+}
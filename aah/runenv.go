@@ -0,0 +1,160 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+)
+
+// envNameRegex is the valid environment variable name.
+var envNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// runEnv is the named run environment from 'run.env' section of
+// 'aah.project', it's activated via 'aah run -env <name>'.
+//
+//	run {
+//	  env {
+//	    staging {
+//	      # Default value is environment name
+//	      profile = "staging"
+//	      config = "config/external/staging.conf"
+//	      env_file = ".env.staging"
+//	      vars {
+//	        DB_URL = "postgres://localhost/app_staging"
+//	      }
+//	    }
+//	  }
+//	}
+type runEnv struct {
+	Name    string
+	Profile string
+	Config  string
+	Vars    []string
+}
+
+// loadRunEnv method reads the run environment from 'aah.project', relative
+// paths are resolved from the application base directory. Variables from
+// 'vars' take precedence over 'env_file'.
+func loadRunEnv(cfg *config.Config, baseDir, name string) (*runEnv, error) {
+	key := "run.env." + name
+	if !cfg.IsExists(key) {
+		return nil, fmt.Errorf("run environment '%s' is not defined in 'aah.project'", name)
+	}
+
+	env := &runEnv{
+		Name:    name,
+		Profile: cfg.StringDefault(key+".profile", name),
+		Config:  cfg.StringDefault(key+".config", ""),
+	}
+
+	if !ess.IsStrEmpty(env.Config) && !filepath.IsAbs(env.Config) {
+		env.Config = filepath.Join(baseDir, env.Config)
+	}
+
+	vars := map[string]string{}
+	if envFile := cfg.StringDefault(key+".env_file", ""); !ess.IsStrEmpty(envFile) {
+		if !filepath.IsAbs(envFile) {
+			envFile = filepath.Join(baseDir, envFile)
+		}
+
+		b, err := ioutil.ReadFile(envFile)
+		if err != nil {
+			return nil, fmt.Errorf("run environment '%s': %s", name, err)
+		}
+
+		if vars, err = parseEnvFile(b); err != nil {
+			return nil, fmt.Errorf("run environment '%s': %s: %s", name, envFile, err)
+		}
+	}
+
+	if varsCfg, found := cfg.GetSubConfig(key + ".vars"); found {
+		for _, k := range varsCfg.Keys() {
+			vars[k] = varsCfg.StringDefault(k, "")
+		}
+	}
+
+	for k, v := range vars {
+		env.Vars = append(env.Vars, k+"="+v)
+	}
+	sort.Strings(env.Vars)
+	return env, nil
+}
+
+// loadDotEnv method reads the '.env' and '.env.<profile>' files of the
+// application base directory, profile file values take precedence. Existing
+// environment variables are not overridden unless override is true. It
+// returns the variables and the loaded files.
+func loadDotEnv(baseDir, profile string, override bool) ([]string, []string, error) {
+	vars := map[string]string{}
+	var files []string
+	for _, name := range []string{".env", ".env." + profile} {
+		file := filepath.Join(baseDir, name)
+		if !ess.IsFileExists(file) {
+			continue
+		}
+
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		fileVars, err := parseEnvFile(b)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", name, err)
+		}
+		for k, v := range fileVars {
+			vars[k] = v
+		}
+		files = append(files, name)
+	}
+
+	var env []string
+	for k, v := range vars {
+		if _, found := os.LookupEnv(k); found && !override {
+			continue
+		}
+		env = append(env, k+"="+v)
+	}
+	sort.Strings(env)
+	return env, files, nil
+}
+
+// parseEnvFile method parses the 'KEY=VALUE' lines, empty lines and lines
+// starting with '#' are skipped. Value may be quoted.
+func parseEnvFile(b []byte) (map[string]string, error) {
+	vars := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if ess.IsStrEmpty(line) || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+		idx := strings.Index(line, "=")
+		if idx <= 0 {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNo)
+		}
+
+		k, v := strings.TrimSpace(line[:idx]), strings.TrimSpace(line[idx+1:])
+		if len(v) > 1 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+			v = v[1 : len(v)-1]
+		}
+		vars[k] = v
+	}
+	return vars, scanner.Err()
+}
@@ -0,0 +1,53 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestRunEnvParseEnvFile(t *testing.T) {
+	vars, err := parseEnvFile([]byte(`
+# staging environment
+DB_URL="postgres://localhost/app?sslmode=disable"
+export API_KEY = 'secret'
+EMPTY=
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"DB_URL":  "postgres://localhost/app?sslmode=disable",
+		"API_KEY": "secret",
+		"EMPTY":   "",
+	}, vars)
+
+	_, err = parseEnvFile([]byte("INVALID"))
+	assert.NotNil(t, err)
+}
+
+func TestRunEnvLoadDotEnv(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-dotenv")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, ".env"), []byte("DB_URL=postgres://localhost/app\nAAH_TEST_DOTENV=file\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, ".env.qa"), []byte("DB_URL=postgres://qa/app\n"), 0644))
+	_ = os.Setenv("AAH_TEST_DOTENV", "process")
+	defer func() { _ = os.Unsetenv("AAH_TEST_DOTENV") }()
+
+	env, files, err := loadDotEnv(dir, "qa", false)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{".env", ".env.qa"}, files)
+	assert.Equal(t, []string{"DB_URL=postgres://qa/app"}, env)
+
+	env, files, err = loadDotEnv(dir, "dev", true)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{".env"}, files)
+	assert.Equal(t, []string{"AAH_TEST_DOTENV=file", "DB_URL=postgres://localhost/app"}, env)
+}
@@ -0,0 +1,158 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+
+	sarifLevelError   = "error"
+	sarifLevelWarning = "warning"
+)
+
+type (
+	// sarifLog is the SARIF report of the findings, code scanning of GitHub
+	// and GitLab shows the results inline on the file locations.
+	sarifLog struct {
+		Schema  string      `json:"$schema"`
+		Version string      `json:"version"`
+		Runs    []*sarifRun `json:"runs"`
+
+		baseDir string
+	}
+
+	sarifRun struct {
+		Tool    sarifTool      `json:"tool"`
+		Results []*sarifResult `json:"results"`
+	}
+
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+
+	sarifDriver struct {
+		Name           string       `json:"name"`
+		Version        string       `json:"version"`
+		InformationURI string       `json:"informationUri"`
+		Rules          []*sarifRule `json:"rules"`
+	}
+
+	sarifRule struct {
+		ID               string       `json:"id"`
+		ShortDescription sarifMessage `json:"shortDescription"`
+	}
+
+	sarifResult struct {
+		RuleID    string           `json:"ruleId"`
+		Level     string           `json:"level"`
+		Message   sarifMessage     `json:"message"`
+		Locations []*sarifLocation `json:"locations"`
+	}
+
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           sarifRegion           `json:"region"`
+	}
+
+	sarifArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+
+	sarifRegion struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn,omitempty"`
+	}
+)
+
+// newSARIFLog method returns the SARIF report of the aah CLI tool command,
+// result file paths are made relative to the base directory.
+func newSARIFLog(command, baseDir string) *sarifLog {
+	return &sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []*sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "aah " + command,
+				Version:        Version,
+				InformationURI: "https://aahframework.org",
+				Rules:          []*sarifRule{},
+			}},
+			Results: []*sarifResult{},
+		}},
+		baseDir: baseDir,
+	}
+}
+
+// Add method adds the result of the rule at file position, rule is added
+// on first use. Line and column start from 1.
+func (l *sarifLog) Add(ruleID, ruleDesc, level, msg, file string, line, col int) {
+	run := l.Runs[0]
+	found := false
+	for _, r := range run.Tool.Driver.Rules {
+		if r.ID == ruleID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules,
+			&sarifRule{ID: ruleID, ShortDescription: sarifMessage{Text: ruleDesc}})
+	}
+
+	if line < 1 {
+		line = 1
+	}
+	run.Results = append(run.Results, &sarifResult{
+		RuleID:  ruleID,
+		Level:   level,
+		Message: sarifMessage{Text: msg},
+		Locations: []*sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: l.uri(file)},
+			Region:           sarifRegion{StartLine: line, StartColumn: col},
+		}}},
+	})
+}
+
+// Merge method appends the runs of other SARIF reports, each tool of the
+// command is a separate run, e.g. 'go vet' and 'config lint' of 'aah lint'.
+func (l *sarifLog) Merge(others ...*sarifLog) *sarifLog {
+	for _, o := range others {
+		l.Runs = append(l.Runs, o.Runs...)
+	}
+	return l
+}
+
+// Write method writes the SARIF report as indented JSON.
+func (l *sarifLog) Write(w io.Writer) error {
+	b, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+func (l *sarifLog) uri(file string) string {
+	if rel, err := filepath.Rel(l.baseDir, file); err == nil && !strings.HasPrefix(rel, "..") {
+		file = rel
+	}
+	return filepath.ToSlash(file)
+}
@@ -0,0 +1,476 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+const (
+	sbomFormatCycloneDX = "cyclonedx"
+	sbomFormatSPDX      = "spdx"
+)
+
+var (
+	sbomCmdFlags      = flag.NewFlagSet("sbom", flag.ContinueOnError)
+	sbomFormatFlag    = sbomCmdFlags.String("format", sbomFormatCycloneDX, "SBOM format 'cyclonedx' or 'spdx'")
+	sbomOutputFlag    = sbomCmdFlags.String("o", "", "SBOM file path. Default is 'build/<app>-<version>.<cdx|spdx>.json'")
+	sbomBinaryFlag    = sbomCmdFlags.String("binary", "", "Application binary path. Default is compiling the application")
+	sbomVersionSuffix = regexp.MustCompile(`\.(v[0-9]+)$`)
+	sbomCmd           = &command{
+		Name:      "sbom",
+		UsageLine: "aah sbom [-format] [-o] [-binary]",
+		Flags:     sbomCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "generate software bill of materials of the application binary",
+		Long: `
+Generates the software bill of materials (SBOM) of the application binary in
+CycloneDX 1.4 or SPDX 2.3 JSON format. SBOM is made of:
+
+    - application with version and SHA-256 hash of the binary
+    - third-party dependencies (refer 'aah deps') with version, package URL
+      'pkg:golang/...', license (refer 'aah deps licenses') and SHA-256 hash
+      of the dependency source files
+
+Dependency version is the git tag or commit of the dependency repository,
+otherwise the 'gopkg.in' version. Application is compiled unless the binary
+is given with '-binary', so the SBOM describes the exact artifact. Timestamp
+is the build date, set 'AAH_APP_BUILD_DATE' for reproducible SBOM.
+
+Example(s):
+    aah sbom
+
+    aah sbom -format=spdx -o=build/myapp.spdx.json
+
+    aah sbom -binary=build/bin/myapp
+`,
+	}
+)
+
+type (
+	// sbomInfo is the format independent data of the SBOM.
+	sbomInfo struct {
+		Name       string
+		Version    string
+		SHA256     string
+		Timestamp  string
+		Components []*sbomComponent
+	}
+
+	sbomComponent struct {
+		Name    string
+		Version string
+		License string
+		SHA256  string
+	}
+
+	cdxBOM struct {
+		BOMFormat    string           `json:"bomFormat"`
+		SpecVersion  string           `json:"specVersion"`
+		SerialNumber string           `json:"serialNumber"`
+		Version      int              `json:"version"`
+		Metadata     *cdxMetadata     `json:"metadata"`
+		Components   []*cdxComponent  `json:"components"`
+		Dependencies []*cdxDependency `json:"dependencies"`
+	}
+
+	cdxMetadata struct {
+		Timestamp string        `json:"timestamp"`
+		Tools     []*cdxTool    `json:"tools"`
+		Component *cdxComponent `json:"component"`
+	}
+
+	cdxTool struct {
+		Vendor  string `json:"vendor"`
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+
+	cdxComponent struct {
+		Type     string        `json:"type"`
+		BOMRef   string        `json:"bom-ref"`
+		Name     string        `json:"name"`
+		Version  string        `json:"version,omitempty"`
+		PURL     string        `json:"purl,omitempty"`
+		Hashes   []*cdxHash    `json:"hashes,omitempty"`
+		Licenses []*cdxLicense `json:"licenses,omitempty"`
+	}
+
+	cdxHash struct {
+		Alg     string `json:"alg"`
+		Content string `json:"content"`
+	}
+
+	cdxLicense struct {
+		License struct {
+			ID string `json:"id"`
+		} `json:"license"`
+	}
+
+	cdxDependency struct {
+		Ref       string   `json:"ref"`
+		DependsOn []string `json:"dependsOn,omitempty"`
+	}
+
+	spdxDocument struct {
+		SPDXVersion       string              `json:"spdxVersion"`
+		DataLicense       string              `json:"dataLicense"`
+		SPDXID            string              `json:"SPDXID"`
+		Name              string              `json:"name"`
+		DocumentNamespace string              `json:"documentNamespace"`
+		CreationInfo      *spdxCreationInfo   `json:"creationInfo"`
+		Packages          []*spdxPackage      `json:"packages"`
+		Relationships     []*spdxRelationship `json:"relationships"`
+	}
+
+	spdxCreationInfo struct {
+		Created  string   `json:"created"`
+		Creators []string `json:"creators"`
+	}
+
+	spdxPackage struct {
+		Name             string             `json:"name"`
+		SPDXID           string             `json:"SPDXID"`
+		VersionInfo      string             `json:"versionInfo,omitempty"`
+		DownloadLocation string             `json:"downloadLocation"`
+		FilesAnalyzed    bool               `json:"filesAnalyzed"`
+		Checksums        []*spdxChecksum    `json:"checksums,omitempty"`
+		LicenseConcluded string             `json:"licenseConcluded"`
+		LicenseDeclared  string             `json:"licenseDeclared"`
+		CopyrightText    string             `json:"copyrightText"`
+		ExternalRefs     []*spdxExternalRef `json:"externalRefs,omitempty"`
+	}
+
+	spdxChecksum struct {
+		Algorithm     string `json:"algorithm"`
+		ChecksumValue string `json:"checksumValue"`
+	}
+
+	spdxExternalRef struct {
+		ReferenceCategory string `json:"referenceCategory"`
+		ReferenceType     string `json:"referenceType"`
+		ReferenceLocator  string `json:"referenceLocator"`
+	}
+
+	spdxRelationship struct {
+		SPDXElementID      string `json:"spdxElementId"`
+		RelationshipType   string `json:"relationshipType"`
+		RelatedSPDXElement string `json:"relatedSpdxElement"`
+	}
+)
+
+func sbomRun(args []string) {
+	format := *sbomFormatFlag
+	if format != sbomFormatCycloneDX && format != sbomFormatSPDX {
+		fatalExitf(exitUsage, "Unsupported SBOM format '%s', choose 'cyclonedx' or 'spdx'", format)
+		return
+	}
+
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	buildCfg := appProject.BuildCfg
+	appBinary := getNonEmptyAbsPath(*sbomBinaryFlag, "")
+	if ess.IsStrEmpty(appBinary) {
+		var err error
+		if appBinary, err = compileApp(buildCfg, &compileOptions{Pack: true}); err != nil {
+			fatalErr(newExitErr(exitCompileError, err))
+			return
+		}
+	} else if !ess.IsFileExists(appBinary) {
+		fatalExitf(exitUsage, "Application binary '%s' does not exists", appBinary)
+		return
+	}
+
+	info := &sbomInfo{
+		Name:      ess.StripExt(filepath.Base(appBinary)),
+		Version:   getAppVersion(appProject.BaseDir, buildCfg),
+		Timestamp: getBuildDate(),
+	}
+
+	var err error
+	if info.SHA256, err = sha256File(appBinary); err != nil {
+		fatal(err)
+		return
+	}
+
+	deps, err := appDependencies(appProject.ImportPath)
+	if err != nil {
+		fatalErr(newExitErr(exitDepsError, err))
+		return
+	}
+	for _, d := range deps {
+		license, _ := detectLicense(d.Dir)
+		sum, err := sha256Dir(d.Dir)
+		if err != nil {
+			fatalf("Unable to hash dependency '%s': %s", d.ImportPath, err)
+			return
+		}
+		info.Components = append(info.Components, &sbomComponent{
+			Name:    d.ImportPath,
+			Version: dependencyVersion(d),
+			License: license,
+			SHA256:  sum,
+		})
+	}
+
+	var doc interface{}
+	ext := "cdx"
+	if format == sbomFormatSPDX {
+		doc, ext = newSPDXDocument(info), "spdx"
+	} else {
+		doc = newCycloneDXBOM(info)
+	}
+
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		fatal(err)
+		return
+	}
+
+	file := firstNonEmpty(getNonEmptyAbsPath(*sbomOutputFlag, ""),
+		filepath.Join(appProject.BaseDir, "build", fmt.Sprintf("%s-%s.%s.json", info.Name, info.Version, ext)))
+	if err = mkDirAll(filepath.Dir(file), permRWXRXRX); err != nil {
+		fatal(err)
+		return
+	}
+	if err = writeFile(file, append(b, '\n'), permRWRR); err != nil {
+		fatal(err)
+		return
+	}
+	recordArtifact(file)
+	log.Infof("SBOM of '%s' with %d dependencies written to %s", aah.AppName(), len(info.Components), file)
+}
+
+// newCycloneDXBOM method returns the CycloneDX 1.4 BOM of the SBOM info,
+// package URL is the 'bom-ref' of the component.
+func newCycloneDXBOM(info *sbomInfo) *cdxBOM {
+	app := &cdxComponent{
+		Type:    "application",
+		BOMRef:  sbomPURL(info.Name, info.Version),
+		Name:    info.Name,
+		Version: info.Version,
+		Hashes:  []*cdxHash{{Alg: "SHA-256", Content: info.SHA256}},
+	}
+
+	bom := &cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.4",
+		SerialNumber: "urn:uuid:" + newUUID(),
+		Version:      1,
+		Metadata: &cdxMetadata{
+			Timestamp: info.Timestamp,
+			Tools:     []*cdxTool{{Vendor: "aah framework", Name: "aah", Version: Version}},
+			Component: app,
+		},
+		Components: []*cdxComponent{},
+	}
+
+	appDep := &cdxDependency{Ref: app.BOMRef}
+	for _, c := range info.Components {
+		purl := sbomPURL(c.Name, c.Version)
+		comp := &cdxComponent{
+			Type:    "library",
+			BOMRef:  purl,
+			Name:    c.Name,
+			Version: c.Version,
+			PURL:    purl,
+			Hashes:  []*cdxHash{{Alg: "SHA-256", Content: c.SHA256}},
+		}
+		if isSPDXLicense(c.License) {
+			l := &cdxLicense{}
+			l.License.ID = c.License
+			comp.Licenses = []*cdxLicense{l}
+		}
+		bom.Components = append(bom.Components, comp)
+		appDep.DependsOn = append(appDep.DependsOn, purl)
+	}
+	bom.Dependencies = []*cdxDependency{appDep}
+	return bom
+}
+
+// newSPDXDocument method returns the SPDX 2.3 document of the SBOM info,
+// document describes the application package and it depends on the
+// dependency packages.
+func newSPDXDocument(info *sbomInfo) *spdxDocument {
+	doc := &spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              info.Name + "-" + info.Version,
+		DocumentNamespace: fmt.Sprintf("https://aahframework.org/spdxdocs/%s-%s-%s", info.Name, info.Version, newUUID()),
+		CreationInfo: &spdxCreationInfo{
+			Created:  info.Timestamp,
+			Creators: []string{"Tool: aah-" + Version},
+		},
+	}
+
+	app := newSPDXPackage("SPDXRef-Package-"+spdxIDString(info.Name), info.Name, info.Version, "", info.SHA256)
+	doc.Packages = append(doc.Packages, app)
+	doc.Relationships = append(doc.Relationships, &spdxRelationship{
+		SPDXElementID: doc.SPDXID, RelationshipType: "DESCRIBES", RelatedSPDXElement: app.SPDXID})
+
+	for i, c := range info.Components {
+		p := newSPDXPackage(fmt.Sprintf("SPDXRef-Package-%d-%s", i+1, spdxIDString(c.Name)), c.Name, c.Version, c.License, c.SHA256)
+		doc.Packages = append(doc.Packages, p)
+		doc.Relationships = append(doc.Relationships, &spdxRelationship{
+			SPDXElementID: app.SPDXID, RelationshipType: "DEPENDS_ON", RelatedSPDXElement: p.SPDXID})
+	}
+	return doc
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func newSPDXPackage(id, name, version, license, sum string) *spdxPackage {
+	p := &spdxPackage{
+		Name:             name,
+		SPDXID:           id,
+		VersionInfo:      version,
+		DownloadLocation: "NOASSERTION",
+		Checksums:        []*spdxChecksum{{Algorithm: "SHA256", ChecksumValue: sum}},
+		LicenseConcluded: "NOASSERTION",
+		LicenseDeclared:  "NOASSERTION",
+		CopyrightText:    "NOASSERTION",
+		ExternalRefs: []*spdxExternalRef{{ReferenceCategory: "PACKAGE-MANAGER",
+			ReferenceType: "purl", ReferenceLocator: sbomPURL(name, version)}},
+	}
+	if isSPDXLicense(license) {
+		p.LicenseDeclared = license
+	}
+	if strings.Contains(name, ".") {
+		p.DownloadLocation = "https://" + name
+	}
+	return p
+}
+
+// dependencyVersion method returns the version of the dependency, git tag
+// or commit of the repository, otherwise the 'gopkg.in' version suffix.
+func dependencyVersion(d *appDependency) string {
+	if top, err := gitOutput(d.Dir, "rev-parse", "--show-toplevel"); err == nil && isSameDir(top, d.Dir) {
+		if version, err := gitOutput(d.Dir, "describe", "--tags", "--always"); err == nil {
+			return version
+		}
+	}
+	if m := sbomVersionSuffix.FindStringSubmatch(d.ImportPath); len(m) == 2 {
+		return m[1]
+	}
+	return ""
+}
+
+// sbomPURL method returns the golang package URL, e.g.
+// 'pkg:golang/github.com/go-sql-driver/mysql@v1.3'.
+func sbomPURL(name, version string) string {
+	purl := "pkg:golang/" + name
+	if !ess.IsStrEmpty(version) {
+		purl += "@" + version
+	}
+	return purl
+}
+
+// sha256Dir method returns the SHA-256 hash of the directory files, it's
+// calculated over relative path and content of each file sorted by path.
+// VCS directories are skipped.
+func sha256Dir(dir string) (string, error) {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && path != dir && strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if info.Mode().IsRegular() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(files)
+
+	h := sha256.New()
+	for _, f := range files {
+		rel, _ := filepath.Rel(dir, f)
+		_, _ = io.WriteString(h, filepath.ToSlash(rel)+"\x00")
+		if err = copyFileTo(h, f); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func sha256File(file string) (string, error) {
+	h := sha256.New()
+	if err := copyFileTo(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func copyFileTo(w io.Writer, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+func isSameDir(a, b string) bool {
+	if p, err := filepath.EvalSymlinks(a); err == nil {
+		a = p
+	}
+	if p, err := filepath.EvalSymlinks(b); err == nil {
+		b = p
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
+
+func isSPDXLicense(license string) bool {
+	return !ess.IsStrEmpty(license) && license != licenseUnknown && license != licenseNone
+}
+
+// spdxIDString method returns the string with characters allowed in SPDX
+// identifier, i.e. letters, numbers, '.' and '-'.
+func spdxIDString(s string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '.' || r == '-' {
+			return r
+		}
+		return '-'
+	}, s)
+}
+
+// newUUID method returns the random UUID version 4.
+func newUUID() string {
+	b := make([]byte, 16)
+	_, _ = io.ReadFull(rand.Reader, b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func init() {
+	sbomCmd.Run = sbomRun
+}
@@ -0,0 +1,572 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"aahframework.org/aah.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+const (
+	secretsKeyEnv    = "AAH_SECRETS_KEY"
+	secretsKeySize   = 32
+	secretsValuePrfx = "enc:v1:"
+	secretsEnvFile   = "secrets.env"
+)
+
+var (
+	secretsKeygenCmdFlags  = flag.NewFlagSet("keygen", flag.ContinueOnError)
+	secretsKeygenForceFlag = secretsKeygenCmdFlags.Bool("force", false, "Overwrite the existing key file")
+	secretsKeygenCmd       = &command{
+		Name:      "keygen",
+		UsageLine: "aah secrets keygen [-force]",
+		Flags:     secretsKeygenCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "create the secrets key of application",
+		Long: `
+Creates the random 256-bit secrets key file, refer 'aah secrets' for the key
+file location. Share the key via password manager and set it in CI via
+'AAH_SECRETS_KEY' environment variable, never commit it.
+
+Example(s):
+    aah secrets keygen
+`,
+	}
+
+	secretsEncryptCmd = &command{
+		Name:      "encrypt",
+		UsageLine: "aah secrets encrypt <NAME> [value]",
+		MaxArgs:   2,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "encrypt the secret value into secrets file",
+		Long: `
+Encrypts the secret value and stores it into secrets file with given
+environment variable name, existing value is replaced. Value is read from
+standard input if not supplied, so it's not kept in shell history.
+
+Example(s):
+    aah secrets encrypt DB_PASSWORD
+
+    echo -n "s3cr3t" | aah secrets encrypt DB_PASSWORD
+
+    aah secrets encrypt SMTP_PASSWORD s3cr3t
+`,
+	}
+
+	secretsDecryptCmd = &command{
+		Name:      "decrypt",
+		UsageLine: "aah secrets decrypt [NAME]",
+		MaxArgs:   1,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "print the decrypted secrets",
+		Long: `
+Prints the decrypted value of given secret name, or all the secrets as
+'NAME=value' lines.
+
+Example(s):
+    aah secrets decrypt
+
+    aah secrets decrypt DB_PASSWORD
+`,
+	}
+
+	secretsEditCmd = &command{
+		Name:      "edit",
+		UsageLine: "aah secrets edit",
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "edit the secrets in editor",
+		Long: `
+Decrypts the secrets into temporary file as 'NAME=value' lines and opens it
+in '$VISUAL' or '$EDITOR' (default is 'vi', 'notepad' on Windows). Secrets
+are encrypted back into secrets file after the editor exits, temporary file
+is removed.
+
+Example(s):
+    aah secrets edit
+
+    EDITOR="code -w" aah secrets edit
+`,
+	}
+
+	secretsCmd = &command{
+		Name:        "secrets",
+		UsageLine:   "aah secrets <subcommand> [arguments]",
+		Short:       "manage encrypted application secrets",
+		Subcommands: commands{secretsKeygenCmd, secretsEncryptCmd, secretsDecryptCmd, secretsEditCmd},
+		Long: `
+Secrets are stored encrypted (AES-256-GCM) in 'config/secrets.conf', so
+the file can be committed and plaintext secrets stay out of the repository:
+
+    secrets {
+      DB_PASSWORD = "enc:v1:..."
+    }
+
+Secrets are decrypted into environment variables of the application by
+'aah run' and 'aah deploy ssh' (uploaded as 'secrets.env' of the release,
+sourced by 'aah.sh').
+
+Key is read from 'AAH_SECRETS_KEY' environment variable (base64), otherwise
+from the key file 'secrets.key_file' of 'aah.project', default is
+'~/.aah/secrets/<app-name>.key'. Secrets file location is configurable via
+'secrets.file'.
+
+Example:
+    aah secrets keygen
+    aah secrets encrypt DB_PASSWORD
+    aah secrets edit
+`,
+	}
+)
+
+// secretStore is the encrypted secrets of secrets file, name is the
+// environment variable name.
+type secretStore struct {
+	File   string
+	Values map[string]string
+}
+
+func secretsKeygenRun(args []string) {
+	keyFile := secretsKeyFile()
+	if ess.IsFileExists(keyFile) && !*secretsKeygenForceFlag {
+		fatalExitf(exitUsage, "Secrets key file '%s' already exists, use '-force' to overwrite, existing secrets can't be decrypted then", keyFile)
+		return
+	}
+
+	key := make([]byte, secretsKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		fatal(err)
+		return
+	}
+
+	if err := mkDirAll(filepath.Dir(keyFile), 0700); err != nil {
+		fatal(err)
+		return
+	}
+	if err := writeFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600); err != nil {
+		fatal(err)
+		return
+	}
+	log.Infof("Secrets key is created at '%s', keep it safe and never commit it", keyFile)
+	log.Infof("For CI, set environment variable '%s' with the key file content", secretsKeyEnv)
+}
+
+func secretsEncryptRun(args []string) {
+	if len(args) == 0 {
+		fatalExit(exitUsage, "Secret name is required, e.g: aah secrets encrypt DB_PASSWORD")
+		return
+	}
+
+	name := args[0]
+	if !envNameRegex.MatchString(name) {
+		fatalExitf(exitUsage, "Secret name '%s' is invalid, it's environment variable name e.g: DB_PASSWORD", name)
+		return
+	}
+
+	var value string
+	if len(args) > 1 {
+		value = args[1]
+	} else {
+		log.Infof("Enter the value of '%s' and press Ctrl+D:", name)
+		b, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			fatal(err)
+			return
+		}
+		value = strings.TrimRight(string(b), "\r\n")
+	}
+
+	key, store, err := loadSecrets(appProject.BaseDir)
+	if err != nil {
+		fatalErr(newExitErr(exitConfigError, err))
+		return
+	}
+	if store.Values[name], err = encryptSecret(key, name, value); err != nil {
+		fatal(err)
+		return
+	}
+	if err = store.Save(); err != nil {
+		fatal(err)
+		return
+	}
+	log.Infof("Secret '%s' is encrypted into '%s'", name, store.File)
+}
+
+func secretsDecryptRun(args []string) {
+	key, store, err := loadSecrets(appProject.BaseDir)
+	if err != nil {
+		fatalErr(newExitErr(exitConfigError, err))
+		return
+	}
+
+	if len(args) > 0 {
+		enc, found := store.Values[args[0]]
+		if !found {
+			fatalExitf(exitUsage, "Secret '%s' does not exists in '%s'", args[0], store.File)
+			return
+		}
+		value, err := decryptSecret(key, args[0], enc)
+		if err != nil {
+			fatalErr(newExitErr(exitConfigError, err))
+			return
+		}
+		fmt.Println(value)
+		return
+	}
+
+	values, err := store.Decrypt(key)
+	if err != nil {
+		fatalErr(newExitErr(exitConfigError, err))
+		return
+	}
+	for _, name := range store.Names() {
+		fmt.Printf("%s=%s\n", name, values[name])
+	}
+}
+
+func secretsEditRun(args []string) {
+	key, store, err := loadSecrets(appProject.BaseDir)
+	if err != nil {
+		fatalErr(newExitErr(exitConfigError, err))
+		return
+	}
+	values, err := store.Decrypt(key)
+	if err != nil {
+		fatalErr(newExitErr(exitConfigError, err))
+		return
+	}
+
+	for name, v := range values {
+		if strings.ContainsAny(v, "\r\n") {
+			fatalExitf(exitUsage, "Secret '%s' is multi-line, it can't be edited, use 'aah secrets encrypt %s'", name, name)
+			return
+		}
+	}
+
+	tmpFile, err := ioutil.TempFile("", "aah-secrets-")
+	if err != nil {
+		fatal(err)
+		return
+	}
+	defer func() { _ = os.Remove(tmpFile.Name()) }()
+
+	_, err = tmpFile.Write(secretsEditContent(store.Names(), values))
+	_ = tmpFile.Close()
+	if err != nil {
+		fatal(err)
+		return
+	}
+
+	if err = openEditor(tmpFile.Name()); err != nil {
+		fatalf("Unable to edit secrets: %s", err)
+		return
+	}
+
+	b, err := ioutil.ReadFile(tmpFile.Name())
+	if err != nil {
+		fatal(err)
+		return
+	}
+	edited, err := parseEnvFile(b)
+	if err != nil {
+		fatalExitf(exitUsage, "Secrets are not saved: %s", err)
+		return
+	}
+
+	var changed int
+	encValues := map[string]string{}
+	for name, value := range edited {
+		if !envNameRegex.MatchString(name) {
+			fatalExitf(exitUsage, "Secrets are not saved, name '%s' is invalid", name)
+			return
+		}
+		// unchanged secret keeps the encrypted value, so the diff is minimal
+		if old, found := values[name]; found && old == value {
+			encValues[name] = store.Values[name]
+			continue
+		}
+		changed++
+		if encValues[name], err = encryptSecret(key, name, value); err != nil {
+			fatal(err)
+			return
+		}
+	}
+	if changed == 0 && len(encValues) == len(values) {
+		log.Info("Secrets are not changed")
+		return
+	}
+
+	store.Values = encValues
+	if err = store.Save(); err != nil {
+		fatal(err)
+		return
+	}
+	log.Infof("Secrets are saved into '%s', %d secret(s)", store.File, len(encValues))
+}
+
+// secretsEnv method returns the decrypted secrets as environment variables
+// 'NAME=value', nil if there are no secrets.
+func secretsEnv(baseDir string) ([]string, error) {
+	store, err := loadSecretStore(secretsFile(baseDir))
+	if err != nil || len(store.Values) == 0 {
+		return nil, err
+	}
+
+	key, err := secretsKey()
+	if err != nil {
+		return nil, err
+	}
+	values, err := store.Decrypt(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var env []string
+	for _, name := range store.Names() {
+		env = append(env, name+"="+values[name])
+	}
+	return env, nil
+}
+
+// secretsEnvContent method returns the secrets environment variables as
+// shell script content, sourced by 'aah.sh'.
+func secretsEnvContent(env []string) []byte {
+	buf := &bytes.Buffer{}
+	for _, v := range env {
+		idx := strings.IndexByte(v, '=')
+		fmt.Fprintf(buf, "%s=%s\n", v[:idx], shellQuote(v[idx+1:]))
+	}
+	return buf.Bytes()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// secretStore methods
+//___________________________________
+
+// Names method returns the sorted secret names.
+func (s *secretStore) Names() []string {
+	names := make([]string, 0, len(s.Values))
+	for name := range s.Values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Decrypt method returns the decrypted secrets.
+func (s *secretStore) Decrypt(key []byte) (map[string]string, error) {
+	values := map[string]string{}
+	for name, enc := range s.Values {
+		v, err := decryptSecret(key, name, enc)
+		if err != nil {
+			return nil, err
+		}
+		values[name] = v
+	}
+	return values, nil
+}
+
+// Save method writes the secrets file, the file is managed by aah CLI.
+func (s *secretStore) Save() error {
+	buf := &bytes.Buffer{}
+	buf.WriteString("# Encrypted secrets of the application, managed by 'aah secrets'.\n")
+	buf.WriteString("# Secrets are decrypted into environment variables by 'aah run' and\n")
+	buf.WriteString("# 'aah deploy ssh'. Edit via 'aah secrets edit'.\n")
+	buf.WriteString("secrets {\n")
+	for _, name := range s.Names() {
+		fmt.Fprintf(buf, "  %s = \"%s\"\n", name, s.Values[name])
+	}
+	buf.WriteString("}\n")
+
+	if err := mkDirAll(filepath.Dir(s.File), permRWXRXRX); err != nil {
+		return err
+	}
+	return writeFile(s.File, buf.Bytes(), permRWRR)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func loadSecrets(baseDir string) ([]byte, *secretStore, error) {
+	key, err := secretsKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	store, err := loadSecretStore(secretsFile(baseDir))
+	return key, store, err
+}
+
+// loadSecretStore method reads the secrets file, it's empty store if file
+// does not exists.
+func loadSecretStore(file string) (*secretStore, error) {
+	store := &secretStore{File: file, Values: map[string]string{}}
+	if !ess.IsFileExists(file) {
+		return store, nil
+	}
+
+	entries, err := parseConfFile(file)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.Kind == confString && strings.HasPrefix(e.Path, "secrets.") {
+			store.Values[strings.TrimPrefix(e.Path, "secrets.")] = e.Value
+		}
+	}
+	return store, nil
+}
+
+func secretsFile(baseDir string) string {
+	file := filepath.FromSlash(appProject.BuildCfg.StringDefault("secrets.file", "config/secrets.conf"))
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(baseDir, file)
+}
+
+func secretsKeyFile() string {
+	file := appProject.BuildCfg.StringDefault("secrets.key_file", "")
+	switch {
+	case ess.IsStrEmpty(file):
+		return filepath.Join(aahUserDir(), "secrets", aah.AppName()+".key")
+	case strings.HasPrefix(file, "~/"):
+		return filepath.Join(filepath.Dir(aahUserDir()), filepath.FromSlash(file[2:]))
+	case !filepath.IsAbs(file):
+		return filepath.Join(appProject.BaseDir, filepath.FromSlash(file))
+	}
+	return file
+}
+
+// secretsKey method returns the secrets key from environment variable or
+// key file.
+func secretsKey() ([]byte, error) {
+	encKey, source := os.Getenv(secretsKeyEnv), secretsKeyEnv
+	if ess.IsStrEmpty(encKey) {
+		source = secretsKeyFile()
+		b, err := ioutil.ReadFile(source)
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("secrets key is not found, set '%s' environment variable or create key file '%s' via 'aah secrets keygen'",
+				secretsKeyEnv, source)
+		} else if err != nil {
+			return nil, err
+		}
+		encKey = string(b)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encKey))
+	if err != nil || len(key) != secretsKeySize {
+		return nil, fmt.Errorf("secrets key of '%s' is invalid, it must be base64 encoded %d bytes", source, secretsKeySize)
+	}
+	return key, nil
+}
+
+// encryptSecret method encrypts the value with AES-GCM, secret name is the
+// additional data, so the encrypted value can't be moved to another name.
+func encryptSecret(key []byte, name, value string) (string, error) {
+	gcm, err := newSecretsGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(value), []byte(name))
+	return secretsValuePrfx + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func decryptSecret(key []byte, name, enc string) (string, error) {
+	if !strings.HasPrefix(enc, secretsValuePrfx) {
+		return "", fmt.Errorf("secret '%s' is not encrypted, use 'aah secrets encrypt %s'", name, name)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(enc, secretsValuePrfx))
+	if err != nil {
+		return "", fmt.Errorf("secret '%s' is invalid: %s", name, err)
+	}
+
+	gcm, err := newSecretsGCM(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("secret '%s' is invalid", name)
+	}
+
+	value, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(name))
+	if err != nil {
+		return "", fmt.Errorf("secret '%s' can't be decrypted, secrets key is wrong or value is tampered", name)
+	}
+	return string(value), nil
+}
+
+func newSecretsGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// secretsEditContent method returns the secrets as 'NAME=value' lines for
+// editing, value with surrounding whitespace or quotes is quoted.
+func secretsEditContent(names []string, values map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteString("# Secrets as NAME=value lines, saved encrypted after the editor exits.\n")
+	buf.WriteString("# Remove the line to delete the secret.\n")
+	for _, name := range names {
+		v := values[name]
+		if v != strings.TrimSpace(v) || strings.HasPrefix(v, "\"") || strings.HasPrefix(v, "'") {
+			v = "\"" + v + "\""
+		}
+		fmt.Fprintf(buf, "%s=%s\n", name, v)
+	}
+	return buf.Bytes()
+}
+
+// openEditor method opens the file in the user editor and waits for it.
+func openEditor(file string) error {
+	editor := firstNonEmpty(os.Getenv("VISUAL"), os.Getenv("EDITOR"))
+	if ess.IsStrEmpty(editor) {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	fields := strings.Fields(editor)
+	cmd := exec.Command(fields[0], append(fields[1:], file)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return errors.New(editor + ": " + err.Error())
+	}
+	return nil
+}
+
+func init() {
+	secretsKeygenCmd.Run = secretsKeygenRun
+	secretsEncryptCmd.Run = secretsEncryptRun
+	secretsDecryptCmd.Run = secretsDecryptRun
+	secretsEditCmd.Run = secretsEditRun
+}
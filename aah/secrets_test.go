@@ -0,0 +1,82 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestSecretsEncryptDecrypt(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, secretsKeySize)
+
+	enc, err := encryptSecret(key, "DB_PASSWORD", "s3cr3t")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(enc, secretsValuePrfx))
+	assert.False(t, strings.Contains(enc, "s3cr3t"))
+
+	value, err := decryptSecret(key, "DB_PASSWORD", enc)
+	assert.Nil(t, err)
+	assert.Equal(t, "s3cr3t", value)
+
+	// value is bound to the name
+	_, err = decryptSecret(key, "SMTP_PASSWORD", enc)
+	assert.NotNil(t, err)
+
+	_, err = decryptSecret(bytes.Repeat([]byte{8}, secretsKeySize), "DB_PASSWORD", enc)
+	assert.NotNil(t, err)
+
+	_, err = decryptSecret(key, "DB_PASSWORD", "s3cr3t")
+	assert.Equal(t, "secret 'DB_PASSWORD' is not encrypted, use 'aah secrets encrypt DB_PASSWORD'", err.Error())
+
+	_ = os.Setenv(secretsKeyEnv, base64.StdEncoding.EncodeToString(key))
+	defer func() { _ = os.Unsetenv(secretsKeyEnv) }()
+	k, err := secretsKey()
+	assert.Nil(t, err)
+	assert.Equal(t, key, k)
+
+	_ = os.Setenv(secretsKeyEnv, "c2hvcnQ=")
+	_, err = secretsKey()
+	assert.NotNil(t, err)
+}
+
+func TestSecretsStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-secrets")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	key := bytes.Repeat([]byte{7}, secretsKeySize)
+	store, err := loadSecretStore(filepath.Join(dir, "secrets.conf"))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(store.Values))
+
+	for name, value := range map[string]string{"DB_PASSWORD": "it's s3cr3t", "API_TOKEN": " token "} {
+		store.Values[name], err = encryptSecret(key, name, value)
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, store.Save())
+
+	store, err = loadSecretStore(store.File)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"API_TOKEN", "DB_PASSWORD"}, store.Names())
+
+	values, err := store.Decrypt(key)
+	assert.Nil(t, err)
+	assert.Equal(t, "it's s3cr3t", values["DB_PASSWORD"])
+
+	edited, err := parseEnvFile(secretsEditContent(store.Names(), values))
+	assert.Nil(t, err)
+	assert.Equal(t, values, edited)
+
+	assert.Equal(t, "API_TOKEN=' token '\nDB_PASSWORD='it'\\''s s3cr3t'\n",
+		string(secretsEnvContent([]string{"API_TOKEN= token ", "DB_PASSWORD=it's s3cr3t"})))
+}
@@ -0,0 +1,109 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/log.v0"
+)
+
+const (
+	swaggerUIDefaultPath = "/_docs"
+	swaggerUISpecFile    = "openapi.json"
+	swaggerUIVersion     = "5.17.14"
+)
+
+// swaggerUI serves the Swagger UI and the OpenAPI document of the application
+// routes via 'aah run' proxy in 'dev' profile. Document is generated on every
+// request, so it's in sync with the sources same as the application.
+//
+//	run {
+//	  api_docs {
+//	    enable = true
+//	    path = "/_docs"
+//	  }
+//	}
+type swaggerUI struct {
+	Path string
+	spec func() (*openAPIDoc, error)
+}
+
+// newSwaggerUI method returns the Swagger UI handler if it's enabled in
+// 'aah.project' for the profile, otherwise nil.
+func newSwaggerUI(cfg *config.Config, baseDir, profile string) *swaggerUI {
+	if profile != "dev" || !cfg.BoolDefault("run.api_docs.enable", false) {
+		return nil
+	}
+
+	path := "/" + strings.Trim(cfg.StringDefault("run.api_docs.path", swaggerUIDefaultPath), "/")
+	return &swaggerUI{
+		Path: path,
+		spec: func() (*openAPIDoc, error) {
+			return appOpenAPIDoc(baseDir, "", nil)
+		},
+	}
+}
+
+// ServeHTTP method serves the Swagger UI page on the path and the OpenAPI
+// document on '<path>/openapi.json'.
+func (s *swaggerUI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch strings.TrimSuffix(r.URL.Path, "/") {
+	case s.Path:
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := swaggerUITmpl.Execute(w, map[string]string{
+			"SpecURL": s.Path + "/" + swaggerUISpecFile,
+			"Version": swaggerUIVersion,
+		}); err != nil {
+			log.Errorf("Swagger UI: %s", err)
+		}
+	case s.Path + "/" + swaggerUISpecFile:
+		doc, err := s.spec()
+		if err != nil {
+			log.Errorf("Swagger UI: unable to generate OpenAPI document: %s", err)
+			http.Error(w, fmt.Sprintf("aah run: unable to generate OpenAPI document: %s", err),
+				http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(doc)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// Match method reports whether the request path is served by Swagger UI.
+func (s *swaggerUI) Match(path string) bool {
+	return path == s.Path || strings.HasPrefix(path, s.Path+"/")
+}
+
+var swaggerUITmpl = template.Must(template.New("swagger-ui").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>API Docs - aah run</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@{{ .Version }}/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@{{ .Version }}/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "{{ .SpecURL }}", dom_id: "#swagger-ui", deepLinking: true });
+  </script>
+</body>
+</html>
+`))
@@ -0,0 +1,449 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+	"aahframework.org/tools.v0/golden"
+)
+
+const (
+	testStatusPass   = "ok"
+	testStatusFail   = "FAIL"
+	testStatusNoTest = "?"
+)
+
+var (
+	testCmdFlags         = flag.NewFlagSet("test", flag.ContinueOnError)
+	testConfigFlag       = testCmdFlags.String("config", "", "External config for overriding aah.conf")
+	testConfigShortFlag  = testCmdFlags.String("c", "", "External config for overriding aah.conf")
+	testProfileFlag      = testCmdFlags.String("profile", "", "Environment profile name to activate. Default is 'test'")
+	testProfileShortFlag = testCmdFlags.String("p", "", "Environment profile name to activate. Default is 'test'")
+	testEnvFlag          = testCmdFlags.String("env", "", "Run environment name from 'run.env' section of aah.project. e.g: ci")
+	testEnvShortFlag     = testCmdFlags.String("e", "", "Run environment name from 'run.env' section of aah.project. e.g: ci")
+	testRunFlag          = testCmdFlags.String("run", "", "Run only the tests matching the regular expression")
+	testRaceFlag         = testCmdFlags.Bool("race", false, "Enable data race detection. Default is 'test.race' of aah.project")
+	testVerboseFlag      = testCmdFlags.Bool("v", false, "Verbose output, log all tests as they are run")
+	testTimeoutFlag      = testCmdFlags.String("timeout", "", "Test binary timeout. Default is 'test.timeout' of aah.project or '10m'")
+	testCoverageFlag     = testCmdFlags.Bool("coverage", false, "Collect coverage, print the per package breakdown and create HTML report")
+	testUpdateGoldenFlag = testCmdFlags.Bool("update-golden", false, "Update the golden files with actual output instead of comparing")
+	testWatchFlag        = testCmdFlags.Bool("watch", false, "Re-run the tests of affected packages on file changes")
+	testFormatFlag       = testCmdFlags.String("format", testFormatText, "Test report format 'text' or 'junit'")
+	testJUnitFileFlag    = testCmdFlags.String("junit-file", "", "JUnit XML report file. Default is 'test.junit_file' of aah.project or '.aah/test/junit.xml'")
+	testCmd              = &command{
+		Name:      "test",
+		UsageLine: "aah test [-c | -config] [-p | -profile] [-e | -env] [-run] [-race] [-v] [-timeout] [-coverage] [-update-golden] [-watch] [-format] [-junit-file] [packages]",
+		Flags:     testCmdFlags,
+		Hooks:     []hook{validateGopath, requireInsideApp, loadProject},
+		Short:     "run aah application tests",
+		Long: `
+Runs the application tests via 'go test' with app-aware defaults and prints
+the summarized pass/fail table of packages, so tests run the same locally
+and in CI. Default packages are './app/...' or 'test.packages' of
+'aah.project'.
+
+Tests are run with '-count=1', so results are never cached. Environment
+variables are set for test helpers to initialize the application:
+    AAH_APP_IMPORT_PATH     application import path
+    AAH_APP_DIR             application base directory
+    AAH_APP_PROFILE         environment profile, default is 'test'
+    AAH_APP_CONFIG          external config file, if supplied
+
+Variables and profile of the run environment ('-env') are applied same as
+'aah run'. Additional 'go test' flags are configurable via 'test.flags'.
+
+With '-coverage', packages are tested one by one with cover profile, then
+the profiles are merged into '.aah/coverage/coverage.out'. Total coverage
+and per package breakdown are printed and the HTML report is created at
+'.aah/coverage/coverage.html'. If 'test.coverage_min' (percent) is
+configured, lower total coverage fails the command.
+
+Golden file helpers of package 'aahframework.org/tools.v0/golden' compare
+the output such as rendered views with 'testdata/golden/<name>.golden'.
+With '-update-golden', golden files are updated with the actual output,
+review the changes before commit.
+
+With '-watch', tests are run once and then re-run on file changes of
+'watch' section directories until Ctrl+C. Only the affected packages are
+run: the package of changed Go file and packages importing it directly or
+indirectly, test file change runs its package only. Other changes, such as
+config or views, run all the packages.
+
+With '-format=junit', tests are run verbose and the results are written as
+JUnit XML report for CI test reporting such as Jenkins and GitLab. Report
+file is '-junit-file', 'test.junit_file' of 'aah.project' or
+'.aah/test/junit.xml', it's written even if tests fail.
+
+Exit code is 'test' (refer 'aah help exit-codes') if any test fails or
+coverage is below the threshold.
+
+Example(s):
+    aah test
+
+    aah test -race -v
+
+    aah test -run=TestUserController ./app/controllers/...
+
+    aah test -e=ci
+
+    aah test -coverage
+
+    aah test -update-golden ./app/views/...
+
+    aah test -watch ./app/controllers/...
+
+    aah test -format=junit -junit-file=build/reports/junit.xml
+`,
+	}
+)
+
+type (
+	// testResult is the outcome of a package from 'go test' output.
+	testResult struct {
+		Status   string
+		Package  string
+		Duration string
+		Failed   []string
+		Tests    []*testCase
+	}
+
+	// testCase is the outcome of a test from 'go test -v' output.
+	testCase struct {
+		Name     string
+		Status   string
+		Duration string
+		Output   []string
+	}
+
+	// testOutput echoes 'go test' output and collects the package results.
+	testOutput struct {
+		w       io.Writer
+		buf     []byte
+		failed  []string
+		tests   []*testCase
+		current *testCase
+		results []*testResult
+	}
+)
+
+func testRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	testEnv, profile, err := appTestEnv(firstNonEmpty(*testEnvFlag, *testEnvShortFlag),
+		getNonEmptyAbsPath(*testConfigFlag, *testConfigShortFlag),
+		firstNonEmpty(*testProfileFlag, *testProfileShortFlag))
+	if err != nil {
+		fatalExit(exitConfigError, err)
+		return
+	}
+	if *testUpdateGoldenFlag {
+		testEnv = append(testEnv, golden.UpdateEnv+"=true")
+	}
+
+	junit := *testFormatFlag == testFormatJUnit
+	if !junit && *testFormatFlag != testFormatText {
+		fatalExitf(exitUsage, "Unsupported test format '%s', supported formats are 'text' and 'junit'", *testFormatFlag)
+		return
+	}
+
+	goArgs, pkgs := testArgs(args)
+	coverage := *testCoverageFlag
+	coverDir := filepath.Join(appProject.BaseDir, ".aah", "coverage")
+	if isDryRun() {
+		if coverage {
+			logDryRun("execute %s %s -coverprofile=%s per package", gocmd,
+				strings.Join(goArgs, " "), filepath.Join(coverDir, "<package>.out"))
+			return
+		}
+		logDryRun("execute %s %s", gocmd, strings.Join(append(goArgs, pkgs...), " "))
+		return
+	}
+
+	log.Infof("Running tests of '%s' with profile '%s'", appProject.ImportPath, profile)
+	if *testWatchFlag {
+		testWatch(goArgs, pkgs, testEnv)
+		return
+	}
+
+	out := &testOutput{w: os.Stdout}
+	endStage := startStage("test")
+	var profiles []string
+	if coverage {
+		profiles, err = runCoverageTests(goArgs, pkgs, testEnv, coverDir, out)
+	} else {
+		err = runGoTest(append(goArgs, pkgs...), testEnv, out)
+	}
+	endStage()
+	out.Flush()
+
+	failed := printTestSummary(os.Stdout, out.results)
+	if junit {
+		junitFile := testJUnitFile()
+		if err := writeJUnitReport(junitFile, out.results); err != nil {
+			log.Errorf("Unable to write JUnit report: %s", err)
+		} else {
+			recordArtifact(junitFile)
+			log.Infof("JUnit report: %s", junitFile)
+		}
+	}
+	if err != nil || failed > 0 {
+		if failed == 0 {
+			fatalExitf(exitTestError, "Tests failed: %s", err)
+			return
+		}
+		fatalExitf(exitTestError, "%d package(s) failed", failed)
+		return
+	}
+
+	if coverage {
+		if err = reportCoverage(os.Stdout, profiles, coverDir); err != nil {
+			fatalErr(err)
+		}
+	}
+}
+
+// appTestEnv method returns the environment variables of the tests and
+// the profile. Run environment is applied same as 'aah run'.
+func appTestEnv(envName, configPath, profile string) ([]string, string, error) {
+	env := &runEnv{}
+	if !ess.IsStrEmpty(envName) {
+		var err error
+		if env, err = loadRunEnv(appProject.BuildCfg, appProject.BaseDir, envName); err != nil {
+			return nil, "", err
+		}
+	}
+
+	configPath = firstNonEmpty(configPath, env.Config)
+	profile = firstNonEmpty(profile, env.Profile, "test")
+	testEnv := append(os.Environ(), env.Vars...)
+	testEnv = append(testEnv,
+		"AAH_APP_IMPORT_PATH="+appProject.ImportPath,
+		"AAH_APP_DIR="+appProject.BaseDir,
+		"AAH_APP_PROFILE="+profile,
+	)
+	if !ess.IsStrEmpty(configPath) {
+		testEnv = append(testEnv, "AAH_APP_CONFIG="+configPath)
+	}
+	return testEnv, profile, nil
+}
+
+// runGoTest method runs 'go test' in application base directory, output
+// is written to given test output.
+func runGoTest(args, env []string, out *testOutput) error {
+	cmd := exec.Command(gocmd, args...)
+	cmd.Dir = appProject.BaseDir
+	cmd.Env = env
+	cmd.Stdout = out
+	cmd.Stderr = out
+	log.Debug("Executing ", strings.Join(cmd.Args, " "))
+	return cmd.Run()
+}
+
+// testArgs method returns the 'go test' arguments and packages from flags
+// and 'test' section of 'aah.project'.
+func testArgs(pkgs []string) ([]string, []string) {
+	buildCfg := appProject.BuildCfg
+	args := []string{"test", "-count=1",
+		"-timeout=" + firstNonEmpty(*testTimeoutFlag, buildCfg.StringDefault("test.timeout", "10m"))}
+	if *testRaceFlag || buildCfg.BoolDefault("test.race", false) {
+		args = append(args, "-race")
+	}
+	if *testVerboseFlag || *testFormatFlag == testFormatJUnit {
+		args = append(args, "-v")
+	}
+	if !ess.IsStrEmpty(*testRunFlag) {
+		args = append(args, "-run="+*testRunFlag)
+	}
+	if flags, found := buildCfg.StringList("test.flags"); found {
+		args = append(args, flags...)
+	}
+
+	if len(pkgs) == 0 {
+		pkgs = []string{"./app/..."}
+		if packages, found := buildCfg.StringList("test.packages"); found && len(packages) > 0 {
+			pkgs = packages
+		}
+	}
+	return args, pkgs
+}
+
+// testJUnitFile method returns the JUnit report file path from flag or
+// 'test.junit_file' of 'aah.project', relative to application base directory.
+func testJUnitFile() string {
+	if file := getNonEmptyAbsPath(*testJUnitFileFlag, ""); !ess.IsStrEmpty(file) {
+		return file
+	}
+
+	file := filepath.FromSlash(appProject.BuildCfg.StringDefault("test.junit_file", ".aah/test/junit.xml"))
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(appProject.BaseDir, file)
+}
+
+// printTestSummary method prints the package results table and returns
+// the no. of failed packages.
+func printTestSummary(w io.Writer, results []*testResult) int {
+	if len(results) == 0 {
+		return 0
+	}
+
+	width := len("PACKAGE")
+	for _, r := range results {
+		if len(r.Package) > width {
+			width = len(r.Package)
+		}
+	}
+
+	var passed, failed, notest int
+	fmt.Fprintf(w, "\n%-6s  %-*s  %s\n", "STATUS", width, "PACKAGE", "TIME")
+	for _, r := range results {
+		fmt.Fprintf(w, "%-6s  %-*s  %s\n", r.Status, width, r.Package, r.Duration)
+		for _, t := range r.Failed {
+			fmt.Fprintf(w, "%-6s    --- %s\n", "", t)
+		}
+		switch r.Status {
+		case testStatusPass:
+			passed++
+		case testStatusFail:
+			failed++
+		default:
+			notest++
+		}
+	}
+	fmt.Fprintf(w, "\n%d passed, %d failed, %d without tests\n", passed, failed, notest)
+	return failed
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// testOutput methods
+//___________________________________
+
+func (t *testOutput) Write(p []byte) (int, error) {
+	if _, err := t.w.Write(p); err != nil {
+		return 0, err
+	}
+
+	t.buf = append(t.buf, p...)
+	for {
+		idx := bytes.IndexByte(t.buf, '\n')
+		if idx < 0 {
+			break
+		}
+		t.parseLine(string(t.buf[:idx]))
+		t.buf = t.buf[idx+1:]
+	}
+	return len(p), nil
+}
+
+// Flush method parses the remaining partial line.
+func (t *testOutput) Flush() {
+	if len(t.buf) > 0 {
+		t.parseLine(string(t.buf))
+		t.buf = nil
+	}
+}
+
+// parseLine method parses the package result lines of 'go test' output:
+//
+//	ok  	github.com/user/app/controllers	0.012s
+//	FAIL	github.com/user/app/models	0.020s
+//	FAIL	github.com/user/app/util [build failed]
+//	?   	github.com/user/app/views	[no test files]
+//
+// Failed tests '--- FAIL: TestName (0.00s)' are attributed to the next
+// failed package. Verbose output '=== RUN', '--- PASS|FAIL|SKIP' and the
+// indented test logs are collected as test cases of the next package.
+func (t *testOutput) parseLine(line string) {
+	line = strings.TrimRight(line, "\r")
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "--- FAIL: ") {
+		t.failed = append(t.failed, strings.TrimPrefix(trimmed, "--- FAIL: "))
+	}
+	if t.parseTestLine(line, trimmed) {
+		return
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return
+	}
+
+	r := &testResult{Status: fields[0], Package: fields[1]}
+	switch r.Status {
+	case testStatusPass:
+		r.Duration = strings.Join(fields[2:], " ")
+	case testStatusFail:
+		r.Duration = strings.Join(fields[2:], " ")
+		r.Failed, t.failed = t.failed, nil
+	case testStatusNoTest:
+		if !strings.Contains(line, "[no test files]") {
+			return
+		}
+		r.Status = "-"
+	default:
+		return
+	}
+	r.Tests, t.tests, t.current = t.tests, nil, nil
+	t.results = append(t.results, r)
+}
+
+// parseTestLine method parses the verbose test lines and reports whether
+// the line is consumed. Test logs are printed before the result line since
+// Go 1.14 and after it on earlier versions, so logs are attributed to the
+// most recently started or finished test.
+func (t *testOutput) parseTestLine(line, trimmed string) bool {
+	if strings.HasPrefix(trimmed, "=== RUN ") {
+		t.current = t.testCase(strings.TrimSpace(strings.TrimPrefix(trimmed, "=== RUN ")))
+		return true
+	}
+
+	for _, status := range []string{"PASS", "FAIL", "SKIP"} {
+		prefix := "--- " + status + ": "
+		if !strings.HasPrefix(trimmed, prefix) {
+			continue
+		}
+
+		name, duration := strings.TrimPrefix(trimmed, prefix), ""
+		if idx := strings.LastIndex(name, " ("); idx > 0 && strings.HasSuffix(name, ")") {
+			name, duration = name[:idx], name[idx+2:len(name)-1]
+		}
+		t.current = t.testCase(name)
+		t.current.Status, t.current.Duration = status, duration
+		return true
+	}
+
+	if t.current != nil && len(line) > len(trimmed) && len(trimmed) > 0 {
+		t.current.Output = append(t.current.Output, trimmed)
+		return true
+	}
+	return false
+}
+
+func (t *testOutput) testCase(name string) *testCase {
+	for _, tc := range t.tests {
+		if tc.Name == name {
+			return tc
+		}
+	}
+	tc := &testCase{Name: name}
+	t.tests = append(t.tests, tc)
+	return tc
+}
+
+func init() {
+	testCmd.Run = testRun
+}
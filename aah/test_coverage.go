@@ -0,0 +1,245 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+const (
+	coverProfileFile = "coverage.out"
+	coverHTMLFile    = "coverage.html"
+)
+
+type (
+	// coverProfile is the merged cover profile, blocks are kept in the
+	// order they are seen.
+	coverProfile struct {
+		Mode   string
+		blocks []string
+		counts map[string]int64
+		stmts  map[string]int64
+	}
+
+	// pkgCoverage is the statement coverage of the package.
+	pkgCoverage struct {
+		Package string
+		Stmts   int64
+		Covered int64
+	}
+)
+
+// runCoverageTests method runs 'go test' per package with cover profile,
+// since cover profile of multiple packages is not supported by older Go
+// versions. It returns the created profile files.
+func runCoverageTests(args, pkgs, env []string, coverDir string, out *testOutput) ([]string, error) {
+	pkgs, err := goListPackages(pkgs)
+	if err != nil {
+		return nil, err
+	}
+
+	deleteFiles(coverDir)
+	if err = mkDirAll(coverDir, permRWXRXRX); err != nil {
+		return nil, err
+	}
+
+	var profiles []string
+	var testErr error
+	for _, pkg := range pkgs {
+		profile := filepath.Join(coverDir, strings.Replace(pkg, "/", "_", -1)+".out")
+		if err = runGoTest(append(append([]string{}, args...), "-coverprofile="+profile, pkg), env, out); err != nil {
+			testErr = err
+			continue
+		}
+		if ess.IsFileExists(profile) {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles, testErr
+}
+
+// goListPackages method resolves the package patterns into import paths.
+func goListPackages(patterns []string) ([]string, error) {
+	cmd := exec.Command(gocmd, append([]string{"list"}, patterns...)...)
+	cmd.Dir = appProject.BaseDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("go list: %s", strings.TrimSpace(string(output)))
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// reportCoverage method merges the profiles, prints the total and per
+// package coverage, creates the HTML report and checks the coverage
+// threshold 'test.coverage_min'.
+func reportCoverage(w io.Writer, profiles []string, coverDir string) error {
+	merged := &coverProfile{counts: map[string]int64{}, stmts: map[string]int64{}}
+	for _, p := range profiles {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if err = merged.Parse(b); err != nil {
+			return fmt.Errorf("%s: %s", p, err)
+		}
+	}
+	if len(merged.blocks) == 0 {
+		log.Info("\nNo coverage data, packages have no tests")
+		return nil
+	}
+
+	profileFile := filepath.Join(coverDir, coverProfileFile)
+	if err := writeFile(profileFile, merged.Bytes(), permRWRR); err != nil {
+		return err
+	}
+
+	pkgs, total := merged.Coverage()
+	printCoverage(w, pkgs, total)
+
+	htmlFile := filepath.Join(coverDir, coverHTMLFile)
+	if _, err := execCmd(gocmd, []string{"tool", "cover", "-html=" + profileFile, "-o", htmlFile}, false); err != nil {
+		return fmt.Errorf("unable to create coverage HTML report: %s", err)
+	}
+	fmt.Fprintf(w, "Coverage report: %s\n", htmlFile)
+
+	minCfg := appProject.BuildCfg.StringDefault("test.coverage_min", "")
+	if ess.IsStrEmpty(minCfg) {
+		return nil
+	}
+	min, err := strconv.ParseFloat(strings.TrimSuffix(minCfg, "%"), 64)
+	if err != nil {
+		return newExitErr(exitConfigError, fmt.Errorf("invalid 'test.coverage_min' value '%s'", minCfg))
+	}
+	if pct := total.Percent(); pct < min {
+		return newExitErr(exitTestError, fmt.Errorf("coverage %.1f%% is below 'test.coverage_min' %.1f%%", pct, min))
+	}
+	return nil
+}
+
+func printCoverage(w io.Writer, pkgs []*pkgCoverage, total *pkgCoverage) {
+	width := len("PACKAGE")
+	for _, p := range pkgs {
+		if len(p.Package) > width {
+			width = len(p.Package)
+		}
+	}
+
+	fmt.Fprintf(w, "\n%-*s  %8s\n", width, "PACKAGE", "COVERAGE")
+	for _, p := range pkgs {
+		fmt.Fprintf(w, "%-*s  %7.1f%%\n", width, p.Package, p.Percent())
+	}
+	fmt.Fprintf(w, "\nTotal coverage: %.1f%% (%d of %d statements)\n", total.Percent(), total.Covered, total.Stmts)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// coverProfile methods
+//___________________________________
+
+// Parse method merges the cover profile into p. Profile line format is
+// 'file.go:startLine.startCol,endLine.endCol numStmt count'. Counts of same
+// block are added, 'set' mode keeps it as 0 or 1.
+func (p *coverProfile) Parse(b []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if ess.IsStrEmpty(line) {
+			continue
+		}
+		if strings.HasPrefix(line, "mode: ") {
+			mode := strings.TrimPrefix(line, "mode: ")
+			if !ess.IsStrEmpty(p.Mode) && p.Mode != mode {
+				return fmt.Errorf("cover mode '%s' mismatch with '%s'", mode, p.Mode)
+			}
+			p.Mode = mode
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 3 || !strings.Contains(fields[0], ":") {
+			return fmt.Errorf("invalid cover profile line '%s'", line)
+		}
+		stmts, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid cover profile line '%s'", line)
+		}
+		count, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid cover profile line '%s'", line)
+		}
+
+		block := fields[0]
+		if _, found := p.stmts[block]; !found {
+			p.blocks = append(p.blocks, block)
+			p.stmts[block] = stmts
+		}
+		p.counts[block] += count
+		if p.Mode == "set" && p.counts[block] > 1 {
+			p.counts[block] = 1
+		}
+	}
+	return scanner.Err()
+}
+
+// Bytes method returns the merged cover profile.
+func (p *coverProfile) Bytes() []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "mode: %s\n", p.Mode)
+	for _, block := range p.blocks {
+		fmt.Fprintf(buf, "%s %d %d\n", block, p.stmts[block], p.counts[block])
+	}
+	return buf.Bytes()
+}
+
+// Coverage method returns the per package coverage sorted by package name
+// and the total coverage.
+func (p *coverProfile) Coverage() ([]*pkgCoverage, *pkgCoverage) {
+	byPkg := map[string]*pkgCoverage{}
+	total := &pkgCoverage{Package: "total"}
+	for _, block := range p.blocks {
+		file := block[:strings.LastIndex(block, ":")]
+		pkg := path.Dir(file)
+		c, found := byPkg[pkg]
+		if !found {
+			c = &pkgCoverage{Package: pkg}
+			byPkg[pkg] = c
+		}
+
+		stmts := p.stmts[block]
+		c.Stmts += stmts
+		total.Stmts += stmts
+		if p.counts[block] > 0 {
+			c.Covered += stmts
+			total.Covered += stmts
+		}
+	}
+
+	pkgs := make([]*pkgCoverage, 0, len(byPkg))
+	for _, c := range byPkg {
+		pkgs = append(pkgs, c)
+	}
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].Package < pkgs[j].Package })
+	return pkgs, total
+}
+
+// Percent method returns the statement coverage percentage.
+func (c *pkgCoverage) Percent() float64 {
+	if c.Stmts == 0 {
+		return 0
+	}
+	return float64(c.Covered) * 100 / float64(c.Stmts)
+}
@@ -0,0 +1,142 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/xml"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	testFormatText  = "text"
+	testFormatJUnit = "junit"
+)
+
+type (
+	// junitTestSuites is the root element of JUnit XML report, understood by
+	// Jenkins, GitLab and most of the CI servers.
+	junitTestSuites struct {
+		XMLName  xml.Name          `xml:"testsuites"`
+		Tests    int               `xml:"tests,attr"`
+		Failures int               `xml:"failures,attr"`
+		Skipped  int               `xml:"skipped,attr"`
+		Time     string            `xml:"time,attr"`
+		Suites   []*junitTestSuite `xml:"testsuite"`
+	}
+
+	junitTestSuite struct {
+		Name      string           `xml:"name,attr"`
+		Tests     int              `xml:"tests,attr"`
+		Failures  int              `xml:"failures,attr"`
+		Skipped   int              `xml:"skipped,attr"`
+		Time      string           `xml:"time,attr"`
+		Timestamp string           `xml:"timestamp,attr"`
+		Cases     []*junitTestCase `xml:"testcase"`
+	}
+
+	junitTestCase struct {
+		ClassName string        `xml:"classname,attr"`
+		Name      string        `xml:"name,attr"`
+		Time      string        `xml:"time,attr"`
+		Failure   *junitMessage `xml:"failure,omitempty"`
+		Skipped   *junitMessage `xml:"skipped,omitempty"`
+		SystemOut string        `xml:"system-out,omitempty"`
+	}
+
+	junitMessage struct {
+		Message  string `xml:"message,attr"`
+		Contents string `xml:",chardata"`
+	}
+)
+
+// newJUnitReport method converts the package results of 'go test -v'
+// output into JUnit test suites, one test suite per package. Failed package
+// without failed tests, for e.g. build failure, is reported as failed test
+// case with package status.
+func newJUnitReport(results []*testResult, timestamp time.Time) *junitTestSuites {
+	report := &junitTestSuites{}
+	var total float64
+	for _, r := range results {
+		if r.Status != testStatusPass && r.Status != testStatusFail {
+			continue
+		}
+
+		suite := &junitTestSuite{
+			Name:      r.Package,
+			Time:      junitTime(r.Duration),
+			Timestamp: timestamp.UTC().Format("2006-01-02T15:04:05"),
+		}
+		for _, tc := range r.Tests {
+			jc := &junitTestCase{
+				ClassName: r.Package,
+				Name:      tc.Name,
+				Time:      junitTime(tc.Duration),
+			}
+			output := strings.Join(tc.Output, "\n")
+			switch tc.Status {
+			case "FAIL":
+				jc.Failure = &junitMessage{Message: "Failed", Contents: output}
+				suite.Failures++
+			case "SKIP":
+				jc.Skipped = &junitMessage{Message: output}
+				suite.Skipped++
+			default:
+				jc.SystemOut = output
+			}
+			suite.Cases = append(suite.Cases, jc)
+		}
+
+		if r.Status == testStatusFail && suite.Failures == 0 {
+			name := "[failed]"
+			if strings.HasPrefix(r.Duration, "[") {
+				name = r.Duration
+			}
+			suite.Failures++
+			suite.Cases = append(suite.Cases, &junitTestCase{
+				ClassName: r.Package,
+				Name:      name,
+				Time:      "0",
+				Failure:   &junitMessage{Message: "Failed", Contents: r.Package + " " + r.Duration},
+			})
+		}
+
+		suite.Tests = len(suite.Cases)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Skipped += suite.Skipped
+		if d, err := strconv.ParseFloat(suite.Time, 64); err == nil {
+			total += d
+		}
+		report.Suites = append(report.Suites, suite)
+	}
+	report.Time = strconv.FormatFloat(total, 'f', 3, 64)
+	return report
+}
+
+// writeJUnitReport method writes the JUnit XML report of the package
+// results into given file.
+func writeJUnitReport(file string, results []*testResult) error {
+	b, err := xml.MarshalIndent(newJUnitReport(results, time.Now()), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = mkDirAll(filepath.Dir(file), permRWXRXRX); err != nil {
+		return err
+	}
+	return writeFile(file, append([]byte(xml.Header), append(b, '\n')...), permRWRR)
+}
+
+// junitTime method returns the seconds of 'go test' duration such as
+// '0.012s', '0' for durations like '(cached)' and '[build failed]'.
+func junitTime(d string) string {
+	if dur, err := time.ParseDuration(strings.TrimSpace(d)); err == nil {
+		return strconv.FormatFloat(dur.Seconds(), 'f', 3, 64)
+	}
+	return "0"
+}
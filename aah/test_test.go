@@ -0,0 +1,167 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestTestOutputSummary(t *testing.T) {
+	echo := &bytes.Buffer{}
+	out := &testOutput{w: echo}
+	output := "--- FAIL: TestUserCreate (0.00s)\n" +
+		"    user_test.go:21: expected 201 got 500\n" +
+		"FAIL\n" +
+		"FAIL\tgithub.com/user/app/app/models\t0.020s\n" +
+		"ok  \tgithub.com/user/app/app/controllers\t0.012s\n" +
+		"?   \tgithub.com/user/app/app/views\t[no test files]\n" +
+		"FAIL\tgithub.com/user/app/app/util [build failed]"
+
+	// written in chunks, lines are split across writes
+	for i := 0; i < len(output); i += 7 {
+		end := i + 7
+		if end > len(output) {
+			end = len(output)
+		}
+		_, err := fmt.Fprint(out, output[i:end])
+		assert.Nil(t, err)
+	}
+	out.Flush()
+
+	assert.Equal(t, output, echo.String())
+	assert.Equal(t, 4, len(out.results))
+	assert.Equal(t, []string{"TestUserCreate (0.00s)"}, out.results[0].Failed)
+	assert.Equal(t, "0.012s", out.results[1].Duration)
+	assert.Equal(t, "-", out.results[2].Status)
+	assert.Equal(t, "[build failed]", out.results[3].Duration)
+
+	buf := &bytes.Buffer{}
+	assert.Equal(t, 2, printTestSummary(buf, out.results))
+	summary := buf.String()
+	assert.True(t, strings.Contains(summary, "STATUS  PACKAGE"))
+	assert.True(t, strings.Contains(summary, "          --- TestUserCreate (0.00s)\n"))
+	assert.True(t, strings.HasSuffix(summary, "\n1 passed, 2 failed, 1 without tests\n"))
+}
+
+func TestTestCoverProfileMerge(t *testing.T) {
+	p := &coverProfile{counts: map[string]int64{}, stmts: map[string]int64{}}
+	assert.Nil(t, p.Parse([]byte("mode: set\n"+
+		"github.com/user/app/app/models/user.go:10.2,12.3 2 1\n"+
+		"github.com/user/app/app/models/user.go:14.2,16.3 3 0\n")))
+	assert.Nil(t, p.Parse([]byte("mode: set\n"+
+		"github.com/user/app/app/models/user.go:14.2,16.3 3 1\n"+
+		"github.com/user/app/app/controllers/user.go:20.2,30.3 5 0\n")))
+
+	assert.Equal(t, "mode: set\n"+
+		"github.com/user/app/app/models/user.go:10.2,12.3 2 1\n"+
+		"github.com/user/app/app/models/user.go:14.2,16.3 3 1\n"+
+		"github.com/user/app/app/controllers/user.go:20.2,30.3 5 0\n", string(p.Bytes()))
+
+	pkgs, total := p.Coverage()
+	assert.Equal(t, 2, len(pkgs))
+	assert.Equal(t, "github.com/user/app/app/controllers", pkgs[0].Package)
+	assert.Equal(t, float64(0), pkgs[0].Percent())
+	assert.Equal(t, float64(100), pkgs[1].Percent())
+	assert.Equal(t, int64(5), total.Covered)
+	assert.Equal(t, float64(50), total.Percent())
+
+	buf := &bytes.Buffer{}
+	printCoverage(buf, pkgs, total)
+	assert.True(t, strings.Contains(buf.String(), "github.com/user/app/app/models         100.0%\n"))
+	assert.True(t, strings.HasSuffix(buf.String(), "Total coverage: 50.0% (5 of 10 statements)\n"))
+
+	assert.NotNil(t, p.Parse([]byte("mode: count\n")))
+	assert.NotNil(t, p.Parse([]byte("invalid line\n")))
+}
+
+func TestTestWatchAffected(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-test-watch")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	files := map[string]string{
+		"models/user.go":                  "package models\n",
+		"services/user.go":                "package services\nimport _ \"github.com/user/app/app/models\"\n",
+		"controllers/user.go":             "package controllers\nimport (\n_ \"fmt\"\n_ \"github.com/user/app/app/services\"\n)\n",
+		"controllers/user_test.go":        "package controllers\nimport _ \"github.com/user/app/app/util\"\n",
+		"util/util.go":                    "package util\n",
+		"views/helper.go":                 "package views\n",
+		"controllers/testdata/fixture.go": "package fixture\nimport _ \"github.com/user/app/app/models\"\n",
+	}
+	for name, content := range files {
+		f := filepath.Join(dir, filepath.FromSlash(name))
+		assert.Nil(t, os.MkdirAll(filepath.Dir(f), 0755))
+		assert.Nil(t, ioutil.WriteFile(f, []byte(content), 0644))
+	}
+
+	graph, err := buildImportGraph(dir, "github.com/user/app/app")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"github.com/user/app/app/services", "github.com/user/app/app/util"}, graph["github.com/user/app/app/controllers"])
+	assert.Equal(t, 5, len(graph))
+
+	pkg := func(names ...string) []string {
+		var pkgs []string
+		for _, n := range names {
+			pkgs = append(pkgs, "github.com/user/app/app/"+n)
+		}
+		return pkgs
+	}
+	scope := pkg("models", "services", "controllers", "util", "views")
+
+	assert.Equal(t, pkg("controllers", "models", "services"), graph.Affected(pkg("models"), nil, scope))
+	assert.Equal(t, pkg("controllers", "util"), graph.Affected(pkg("util"), nil, scope))
+	assert.Equal(t, pkg("views"), graph.Affected(nil, pkg("views"), scope))
+	assert.Equal(t, pkg("services"), graph.Affected(pkg("models"), nil, pkg("services")))
+	assert.Equal(t, scope, graph.Affected(nil, nil, scope))
+}
+
+func TestTestJUnitReport(t *testing.T) {
+	out := &testOutput{w: ioutil.Discard}
+	output := "=== RUN   TestUserList\n" +
+		"--- PASS: TestUserList (0.01s)\n" +
+		"=== RUN   TestUserCreate\n" +
+		"    user_test.go:21: expected 201 got 500\n" +
+		"--- FAIL: TestUserCreate (0.00s)\n" +
+		"=== RUN   TestUserDelete\n" +
+		"--- SKIP: TestUserDelete (0.00s)\n" +
+		"\tuser_test.go:30: TODO\n" +
+		"FAIL\n" +
+		"FAIL\tgithub.com/user/app/app/controllers\t0.020s\n" +
+		"FAIL\tgithub.com/user/app/app/util [build failed]\n" +
+		"?   \tgithub.com/user/app/app/views\t[no test files]\n"
+	_, err := out.Write([]byte(output))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(out.results))
+	assert.Equal(t, []string{"TestUserCreate (0.00s)"}, out.results[0].Failed)
+	assert.Equal(t, 3, len(out.results[0].Tests))
+	assert.Equal(t, []string{"user_test.go:21: expected 201 got 500"}, out.results[0].Tests[1].Output)
+	assert.Equal(t, []string{"user_test.go:30: TODO"}, out.results[0].Tests[2].Output)
+
+	report := newJUnitReport(out.results, time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC))
+	assert.Equal(t, 4, report.Tests)
+	assert.Equal(t, 2, report.Failures)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, "0.020", report.Time)
+
+	b, err := xml.MarshalIndent(report, "", "  ")
+	assert.Nil(t, err)
+	x := string(b)
+	assert.True(t, strings.Contains(x, `<testsuite name="github.com/user/app/app/controllers" tests="3" failures="1" skipped="1" time="0.020" timestamp="2018-01-02T03:04:05">`))
+	assert.True(t, strings.Contains(x, `<testcase classname="github.com/user/app/app/controllers" name="TestUserList" time="0.010"></testcase>`))
+	assert.True(t, strings.Contains(x, `<failure message="Failed">user_test.go:21: expected 201 got 500</failure>`))
+	assert.True(t, strings.Contains(x, `<skipped message="user_test.go:30: TODO"></skipped>`))
+	assert.True(t, strings.Contains(x, `name="[build failed]" time="0"`))
+	assert.False(t, strings.Contains(x, "app/views"))
+}
@@ -0,0 +1,197 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+// importGraph is the application packages and their imports within the
+// application, test file imports are included.
+type importGraph map[string][]string
+
+// testWatch method re-runs the test packages affected by the file changes
+// until interrupted. Go source change runs its package and the packages
+// which import it directly or indirectly, other changes run all packages.
+func testWatch(goArgs, pkgs, env []string) {
+	scope, err := goListPackages(pkgs)
+	if err != nil {
+		fatal(err)
+		return
+	}
+
+	watchCfg, err := loadWatchConfig(appProject.BuildCfg)
+	if err != nil {
+		fatalErr(newExitErr(exitConfigError, err))
+		return
+	}
+	watchCfg.Excludes = excludeAndCreateSlice(watchCfg.Excludes, "*_test.go")
+
+	w, err := newWatcher(appProject.BaseDir, watchCfg,
+		generatedAppFiles(filepath.Join(appProject.BaseDir, "app")))
+	if err != nil {
+		fatalf("Unable to start file watcher: %s", err)
+		return
+	}
+	w.Start()
+	defer w.Close()
+
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sc)
+
+	runWatchTests(goArgs, scope, env)
+	for {
+		log.Infof("\nWatching for changes in %s (%s), press Ctrl+C to stop", strings.Join(watchCfg.Dirs, ", "), w.Mode())
+		select {
+		case files := <-w.Changes:
+			log.Infof("Change detected: %s", strings.Join(w.relPaths(files), ", "))
+			graph, err := buildImportGraph(filepath.Join(appProject.BaseDir, "app"), appProject.ImportPath+"/app")
+			if err != nil {
+				log.Errorf("Unable to build import graph, running all packages: %s", err)
+				runWatchTests(goArgs, scope, env)
+				continue
+			}
+
+			changed, tests := changedPackages(files)
+			affected := graph.Affected(changed, tests, scope)
+			if len(affected) == 0 {
+				log.Info("No test packages affected")
+				continue
+			}
+			runWatchTests(goArgs, affected, env)
+		case <-sc:
+			return
+		}
+	}
+}
+
+func runWatchTests(goArgs, pkgs, env []string) {
+	out := &testOutput{w: os.Stdout}
+	_ = runGoTest(append(append([]string{}, goArgs...), pkgs...), env, out)
+	out.Flush()
+	printTestSummary(os.Stdout, out.results)
+}
+
+// changedPackages method returns the import paths of packages with changed
+// Go source files and packages with changed test files. Both are nil if any
+// non Go source file is changed.
+func changedPackages(files []string) ([]string, []string) {
+	appDir := filepath.Join(appProject.BaseDir, "app")
+	var pkgs, tests []string
+	for _, f := range files {
+		rel, err := filepath.Rel(appDir, filepath.Dir(f))
+		if !strings.HasSuffix(f, ".go") || err != nil || strings.HasPrefix(rel, "..") {
+			return nil, nil
+		}
+
+		pkg := path.Join(appProject.ImportPath, "app", filepath.ToSlash(rel))
+		if strings.HasSuffix(f, "_test.go") {
+			tests = append(tests, pkg)
+		} else {
+			pkgs = append(pkgs, pkg)
+		}
+	}
+	return pkgs, tests
+}
+
+// buildImportGraph method parses the imports of Go source and test files of
+// the application packages in dir.
+func buildImportGraph(dir, importPath string) (importGraph, error) {
+	graph := importGraph{}
+	err := ess.Walk(dir, func(srcPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if name := info.Name(); srcPath != dir && (strings.HasPrefix(name, ".") ||
+			strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor") {
+			return filepath.SkipDir
+		}
+
+		pkgs, err := parser.ParseDir(token.NewFileSet(), srcPath, nil, parser.ImportsOnly)
+		if err != nil {
+			return err
+		}
+
+		rel, _ := filepath.Rel(dir, srcPath)
+		pkg := path.Join(importPath, filepath.ToSlash(rel))
+		seen := map[string]bool{}
+		for _, p := range pkgs {
+			for _, f := range p.Files {
+				for _, imp := range f.Imports {
+					ip, _ := strconv.Unquote(imp.Path.Value)
+					if ip != pkg && !seen[ip] && (ip == importPath || strings.HasPrefix(ip, importPath+"/")) {
+						seen[ip] = true
+						graph[pkg] = append(graph[pkg], ip)
+					}
+				}
+			}
+		}
+		if len(pkgs) > 0 {
+			sort.Strings(graph[pkg])
+			if _, found := graph[pkg]; !found {
+				graph[pkg] = nil
+			}
+		}
+		return nil
+	})
+	return graph, err
+}
+
+// Affected method returns the packages of scope which are changed or
+// import the changed packages directly or indirectly. Test file changes
+// affect only its package. All packages of scope are affected if nothing
+// is given.
+func (g importGraph) Affected(changed, tests, scope []string) []string {
+	if changed == nil && tests == nil {
+		return scope
+	}
+
+	importers := map[string][]string{}
+	for pkg, imports := range g {
+		for _, ip := range imports {
+			importers[ip] = append(importers[ip], pkg)
+		}
+	}
+
+	affected := map[string]bool{}
+	queue := append([]string{}, changed...)
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+		if affected[pkg] {
+			continue
+		}
+		affected[pkg] = true
+		queue = append(queue, importers[pkg]...)
+	}
+	for _, pkg := range tests {
+		affected[pkg] = true
+	}
+
+	var result []string
+	for _, pkg := range scope {
+		if affected[pkg] {
+			result = append(result, pkg)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,443 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"aahframework.org/aah.v0"
+)
+
+// helpTopics are long-form documentation shown by 'aah help <topic>'. It
+// reuses the command structure, 'Long' is a template rendered with
+// `helpTopicData`. The order here is the order in which topics are
+// printed by 'aah help'.
+var helpTopics = commands{
+	{
+		Name:  "aah.project",
+		Short: "aah project file and build configuration",
+		Long:  aahProjectTopicTemplate,
+	},
+	{
+		Name:  "routes.conf",
+		Short: "routes configuration 'config/routes.conf'",
+		Long:  routesTopicTemplate,
+	},
+	{
+		Name:  "profiles",
+		Short: "environment profiles and external config",
+		Long:  profilesTopicTemplate,
+	},
+	{
+		Name:  "exit-codes",
+		Short: "exit codes of aah CLI tool",
+		Long:  exitCodesTopicTemplate,
+	},
+	{
+		Name:  "cli.conf",
+		Short: "aah CLI tool config '~/.aah/cli.conf'",
+		Long:  cliConfTopicTemplate,
+	},
+}
+
+func helpTopicData() map[string]interface{} {
+	return map[string]interface{}{
+		"AahVersion": aah.Version,
+		"CLIVersion": Version,
+		"ExitCodes":  exitCodes,
+	}
+}
+
+// printTopic method renders the help topic template into given writer.
+func printTopic(w io.Writer, topic *command) {
+	buf := &bytes.Buffer{}
+	if err := renderTmpl(buf, topic.Long, helpTopicData()); err != nil {
+		fatalf("Unable to render help topic '%s': %s", topic.Name, err)
+	}
+	fmt.Fprintf(w, "%v\n\n", strings.TrimSpace(buf.String()))
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Help topic templates
+//___________________________________
+
+const aahProjectTopicTemplate = `
+File 'aah.project' lives in the application base directory and it identifies
+the aah application. aah CLI tool reads it during 'aah run' and 'aah build'.
+
+Values may refer environment variables, variables are looked up from the
+environment and then '.env' file of the application base directory. It's
+applied to external config of 'aah run -config' too:
+    ${VAR}            value of VAR, empty if VAR is not set
+    ${VAR:-default}   default if VAR is not set or empty
+    ${VAR-default}    default if VAR is not set
+    ${VAR:?message}   error with message if VAR is not set or empty
+    $${VAR}           literal '${VAR}'
+
+Section 'project' attributes:
+    header_file      License header file, prepended to generated files and
+                     added to existing files via 'aah fmt headers'.
+
+Section 'build' attributes:
+    binary_name      Application binary name. Default is 'name' from 'aah.conf'.
+    version          Fallback version, used if 'AAH_APP_VERSION' environment
+                     value and 'git describe' are not available.
+    version_scheme   Version scheme of 'aah release' bump and validation,
+                     'semver' (MAJOR.MINOR.PATCH) or 'calver'. Default is
+                     'semver'.
+    calver_format    Format of 'calver' scheme with tokens 'YYYY', 'YY',
+                     '0Y', 'MM', '0M', 'DD', '0D' and 'PATCH' (release
+                     number within the date). Default is 'YYYY.MM.PATCH'.
+    version_metadata Build metadata appended to the version as
+                     '<version>+<metadata>' except 'AAH_APP_VERSION',
+                     '{commit}' and '{date}' are replaced, environment
+                     variables are expanded, e.g. '{commit}.${BUILD_ID}'.
+                     Docker image tag and Kubernetes label use '_' for '+'.
+    dep_get          Fetch missing dependencies via 'go get'. Default is false.
+    log_level        aah CLI tool log level. Default is 'info'.
+    flags            Additional 'go build' flags, e.g: ["-i"].
+    ldflags          Value for 'go build -ldflags'.
+    tags             Value for 'go build -tags'.
+    ast_excludes     Exclude patterns for controllers Go AST processing.
+    excludes         Exclude patterns for build artifact packaging.
+    embed_migrations Embed 'db/migrations' into the application binary, run
+                     the binary with '-migrate' flag to apply the pending
+                     migrations on startup. Default is false.
+    profiling        Serve 'net/http/pprof' and 'expvar' handlers on separate
+                     admin address 'profiling_address' (default is
+                     'localhost:6060'), the binary flag '-profiling-addr'
+                     overrides it and empty value disables it. Default is
+                     false.
+    metrics          Serve Prometheus metrics of request counts, durations
+                     and build info on separate address 'metrics_address'
+                     (default is ':9464') and path 'metrics_path' (default
+                     is '/metrics'), the binary flag '-metrics-addr'
+                     overrides the address and empty value disables it.
+                     Default is false.
+    max_binary_size  Size budget of the binary built by 'aah build' and 'aah
+                     package', e.g. "25MB" (binary multiples), the size
+                     report of top packages is printed when it exceeds.
+                     Default is no budget.
+    binary_size_action
+                     'fail' (exit code 'size') or 'warn' when the binary
+                     exceeds the budget. Default is 'fail'.
+    assets           Asset build stage of 'aah build' and 'aah package', JS and
+                     CSS files of 'static' are minified and packaged in place
+                     of the sources. Attributes 'enable' (default is false),
+                     'minify' (default is true), 'dist' directory (default is
+                     'build/dist') and 'excludes' patterns of files packaged
+                     as-is, '*.min.js' and '*.min.css' are always as-is.
+                     'optimize_images' (default is false) losslessly
+                     optimizes PNG and JPEG images, via 'optipng' and
+                     'jpegoptim' if they are in PATH.
+                     'fingerprint' (default is false) adds content hashed
+                     copy of static files and 'manifest' (default is
+                     'manifest.json' of 'static') at 'aah package', files
+                     matching 'fingerprint_excludes' are not fingerprinted.
+                     'compilers' are named external asset compilers, e.g.
+                     Sass or TypeScript, 'command' is run via shell in
+                     application base directory ahead of the stage, even if
+                     not enabled. 'aah run' runs it on change of the files
+                     matching 'watch' patterns.
+
+Section 'run' attributes, used by 'aah run':
+    shutdown_timeout Graceful shutdown time on restart. Default is '10s'.
+    reload_signal    Signal application on config and view changes instead of
+                     restart, e.g. "SIGHUP". Unix only.
+    crash_restart    Restart crashed application with backoff. Default is true.
+    crash_restart_max_delay
+                     Maximum restart backoff delay. Default is '30s'.
+    env              Named run environments for 'aah run -env <name>', each
+                     with 'profile', 'config', 'env_file' and 'vars'.
+    dotenv           Load '.env' and '.env.<profile>' files with 'enable'
+                     (default is true) and 'override' existing environment
+                     variables (default is false).
+    api_docs         Serve Swagger UI of 'aah generate openapi' document via
+                     proxy in 'dev' profile with 'enable' (default is false)
+                     and 'path' (default is '/_docs').
+
+Section 'watch' attributes, used by 'aah run':
+    dirs             Directories to watch. Default is ["app", "config", "views"].
+    includes         Watch only the matching files. Default is all files.
+    excludes         Ignore the matching files. Default is ["*_test.go",
+                     "*.tmp", "*.bak", "*.swp"].
+    debounce         Quiet period before rebuild. Default is '300ms'.
+    poll             Poll the modification time instead of file system
+                     notifications, e.g. NFS or Docker volumes. Default is false.
+    poll_interval    Polling interval. Default is '1s'.
+
+Section 'livereload' attributes, used by 'aah run' in 'dev' profile:
+    enable           Refresh the browser after successful rebuild. Default is true.
+    port             Live reload server port. Default is 35729.
+
+Section 'proxy' attributes, used by 'aah run':
+    enable           Hold the requests while rebuilding. Default is true.
+    hold_timeout     Maximum time a request is held. Default is '30s'.
+
+Section 'test' attributes, used by 'aah test':
+    packages         Packages to test. Default is './app/...'.
+    timeout          Test binary timeout. Default is '10m'.
+    race             Enable data race detection. Default is false.
+    flags            Additional 'go test' flags.
+    coverage_min     Minimum total coverage percent of 'aah test -coverage'.
+    junit_file       JUnit XML report file of 'aah test -format=junit'.
+                     Default is '.aah/test/junit.xml'.
+
+Section 'bench' attributes, used by 'aah bench':
+    count            Run each benchmark n times. Default is 1.
+    threshold        Maximum 'ns/op' regression percent. Default is no threshold.
+    startup_runs     Application launches of 'aah bench startup'. Default is 10.
+    startup_threshold
+                     Maximum mean startup time regression percent of 'aah
+                     bench startup'. Default is no threshold.
+
+Section 'migration' attributes, used by 'aah generate migration', 'aah generate models'
+and 'aah migrate':
+    dialect          SQL dialect 'postgres', 'mysql' or 'sqlite'. Default is
+                     'postgres'.
+    config_key       App config section of the database 'url' and 'driver'.
+                     Default is 'database'.
+    table            Applied migrations table. Default is 'schema_migrations'.
+    driver_import    Driver package of 'database/sql', default is per dialect
+                     'github.com/lib/pq', 'github.com/go-sql-driver/mysql' and
+                     'github.com/mattn/go-sqlite3'.
+
+Section 'compose' attributes, used by 'aah generate compose':
+    go_image         Go image of the application container. Default is 'golang:1.9'.
+    env              Environment variable name to app config key mapping.
+
+Section 'ci' attributes, used by 'aah generate ci' providers:
+    go_versions      Go versions of the test matrix, last one builds. '.x'
+                     is trimmed for 'golang' image tag of 'gitlab' and
+                     'jenkins' providers. Default is ["1.9.x"].
+    profiles         Environment profiles of test and build. Default is ["prod"].
+    platforms        Build matrix in '<os>/<arch>' format. Default is
+                     ["linux/amd64"].
+
+Section 'k8s' attributes, used by 'aah generate k8s' and 'aah generate helm':
+    image            Container image. Default is '<app-name>:<app-version>'.
+    namespace        Kubernetes namespace of the manifests.
+    replicas         Deployment replicas. Default is 1.
+    app_dir          Application directory in the container. Default is '/app'.
+    health_path      Health endpoint path of the probes. Default is '/'.
+    resources        Container 'requests' and 'limits' of 'cpu' and 'memory'.
+
+Section 'package' attributes, used by 'aah package -format deb|rpm':
+    dir              Install directory. Default is '/opt/<app>'.
+    user             Service user. Default is '<app>'.
+    maintainer       Package maintainer, e.g: 'Team <team@example.com>'.
+    description      Package description.
+    homepage         Package homepage URL.
+    secret_scan      Secret scan of package contents, used by 'aah package' and
+                     'aah deploy' with 'mode' 'fail' (default), 'warn' or
+                     'off', additional 'patterns', 'allow' patterns of
+                     findings and 'excludes' files.
+
+Section 'deploy' attributes, used by 'aah deploy':
+    os, arch         Target platform of the package. Default is 'linux/amd64'.
+    keep_releases    Releases kept on SSH targets for rollback. Default is 5.
+    targets          SSH targets with 'host', 'port', 'user', 'identity_file',
+                     'dir', 'restart' command and 'keep_releases'.
+    heroku           Provider 'heroku' with 'app'.
+    cloudfoundry     Provider 'cloudfoundry' with 'app', 'memory' and 'instances'.
+    elasticbeanstalk Provider 'elasticbeanstalk' with 'application',
+                     'environment', 'bucket' and 'region'.
+
+Section 'release' attributes, used by 'aah release':
+    github           GitHub repository 'owner/repo' of 'aah release publish'.
+    download_url     Download base URL of release artifacts for manifest and
+                     Homebrew formula, '{tag}' is replaced with release tag.
+    tag_prefix       Prefix of release tag. Default is 'v'.
+    bump             Version part bumped by 'aah release', 'major', 'minor'
+                     or 'patch' of 'semver' scheme, 'calver' scheme bumps
+                     by date. Default is 'patch'.
+    changelog        Changelog file updated by 'aah release', empty value
+                     skips it. Default is 'CHANGELOG.md'.
+    platforms        Build matrix of 'aah release', e.g. ["linux/amd64",
+                     "darwin/arm64"]. Default is current platform.
+    publish          Push and publish the GitHub release on 'aah release'.
+                     Default is false.
+    git_remote       Git remote of the release push. Default is 'origin'.
+    sign             Release signing via 'gpg' with attributes 'enable'
+                     (default is false) and 'key' (default is gpg default
+                     key), tag and 'checksums.txt' are signed.
+
+Section 'config.remote' attributes, used by 'aah run' and 'aah build':
+    url              Remote config source, 'http(s)://host/path',
+                     'consul://host:8500/key' or 'etcd://host:2379/key'. Use
+                     'consul+https' and 'etcd+https' for HTTPS.
+    timeout          Fetch timeout. Default is '10s'.
+    cache_file       Cached config, used if source is not reachable. Default
+                     is '.aah/config/remote.conf'.
+    headers          Request headers, e.g: 'Authorization'.
+
+Section 'secrets' attributes, used by 'aah secrets', 'aah run' and
+'aah deploy ssh':
+    file             Encrypted secrets file. Default is 'config/secrets.conf'.
+    key_file         Secrets key file, used if 'AAH_SECRETS_KEY' environment
+                     value is not set. Default is '~/.aah/secrets/<app-name>.key'.
+
+Section 'deps.licenses' attributes, used by 'aah deps licenses':
+    allow            Allowed SPDX license identifiers, e.g. ["MIT", "Apache-2.0"].
+                     Default is all licenses.
+    deny             Denied SPDX license identifiers, e.g. ["GPL-3.0"].
+    ignore           Dependencies skipped from the check, '/...' suffix
+                     matches the sub packages.
+    fail_on_unknown  Fail on missing or unrecognized license. Default is false.
+
+Exclude patterns are 'filepath.Match' patterns, refer https://golang.org/pkg/path/filepath/#Match
+
+aah framework v{{ .AahVersion }}, aah CLI tool v{{ .CLIVersion }}
+`
+
+const routesTopicTemplate = `
+File 'config/routes.conf' defines the application domains, static routes and
+application routes. Route configuration is validated during 'aah run' and
+'aah build'.
+
+Structure:
+    domains {
+      localhost {
+        host = "localhost"
+        static { ... }
+        routes {
+          route_name {
+            path = "/users/:id"
+            method = "GET"
+            controller = "User"
+            action = "Show"
+          }
+        }
+      }
+    }
+
+Route attributes:
+    path             URL path, supports ':name' named and '*name' catch-all
+                     parameters.
+    method           HTTP method(s), comma separated. Default is 'GET'.
+    controller       Controller name with or without package prefix, e.g: 'v1.User'.
+    action           Controller action. Default is derived from HTTP method.
+
+Routes are validated during compile, errors fail the build with exit code
+'route' (refer 'aah help exit-codes'):
+    - HTTP methods are valid
+    - path starts with '/', parameter names are valid and unique, and
+      catch-all '*name' is the last segment
+    - route names are unique in the domain, it's used for reverse URL
+    - routes of same method don't conflict, e.g. '/users/:id' and
+      '/users/:name', and aren't shadowed by a catch-all route or static
+      directory
+
+Actions configured in routes however not implemented in the Controller are
+reported by the CLI tool during compile.
+
+Go to https://docs.aahframework.org/routes-config.html to learn more.
+`
+
+const profilesTopicTemplate = `
+Environment profiles allows you to have configuration per environment such as
+dev, qa and prod. Profile configuration goes into 'config/env/<profile>.conf'
+and it gets merged on top of 'config/aah.conf'.
+
+Default profile is 'dev' for 'aah run' and 'prod' for 'aah build'.
+
+Activate a profile:
+    aah run -p=qa
+    aah build -profile=prod
+
+Supply an external config to override the application config:
+    aah run -c=/path/to/config/external.conf -p=qa
+
+Packaged application profile can be supplied to startup script:
+    ./aah.sh start qa /path/to/config/external.conf
+
+Go to https://docs.aahframework.org/app-config.html#section-env to learn more.
+`
+
+const exitCodesTopicTemplate = `
+Exit codes of aah CLI tool. These values are stable across releases, so
+CI scripts can branch on them.
+
+{{ range .ExitCodes }}    {{ printf "%-3d %-10s %s" .Code .Name .Desc }}
+{{ end }}
+Global flag '-status-file' writes the command status as JSON for CI steps to
+parse, it's written on success and failure:
+
+    {
+      "command": "release publish",
+      "args": ["release", "publish", "-github=myorg/myapp"],
+      "result": "failure",
+      "exit_code": 1,
+      "exit_name": "error",
+      "error": "Unable to publish GitHub release: ...",
+      "dry_run": false,
+      "started_at": "2017-06-01T10:00:00Z",
+      "duration_ms": 5230,
+      "stages": [{"name": "compile", "duration_ms": 4100}],
+      "warnings": ["Secrets are not loaded: ..."],
+      "artifacts": ["/home/user/myapp/build/myapp-v1.0.0-linux-amd64.tar.gz"]
+    }
+
+Example:
+    aah -status-file=aah-status.json build
+`
+
+const cliConfTopicTemplate = `
+aah CLI tool reads the user config '~/.aah/cli.conf' if exists, it's the
+same config syntax as the application config.
+
+log {
+  # CLI output format 'text' or 'json'. JSON format writes one log event per
+  # line on stdout, including the output of commands executed by it, e.g:
+  #   {"time":"...","level":"info","stage":"go build","message":"...",
+  #    "fields":{"command":"build","stream":"stderr"}}
+  # Stage completion and command completion events carry 'duration_ms',
+  # command completion event carries 'exit_code' too. Global flag
+  # '-log-format' overrides it.
+  # Default value is 'text'.
+  format = "text"
+
+  # Mirror the CLI output, including the commands executed by it e.g. go build,
+  # into log file. Global flag '-log-file' enables it for single run.
+  # Default value is 'false'.
+  enable = false
+
+  # Log file location, relative to current directory.
+  # Default value is '.aah/logs/aah.log'.
+  file = ".aah/logs/aah.log"
+
+  # Log file is rotated once it reaches the size, rotated files are
+  # 'aah.log.1', 'aah.log.2' and so on.
+  # Default value is '10'.
+  max_size_mb = 10
+
+  # No. of rotated files to keep.
+  # Default value is '5'.
+  max_backups = 5
+}
+
+network {
+  # Proxy for the network operations of aah CLI tool, e.g. 'aah update',
+  # 'aah release publish', remote config, and the commands executed by it
+  # such as 'go get' and 'git clone'. Environment values 'HTTP_PROXY',
+  # 'HTTPS_PROXY' and 'NO_PROXY' take precedence.
+  http_proxy = "http://proxy.example.com:3128"
+  https_proxy = "http://proxy.example.com:3128"
+  no_proxy = "localhost,127.0.0.1,.example.com"
+
+  # PEM file of the corporate CA certificates, it's trusted along with the
+  # system CA certificates. For the commands executed by aah CLI tool it's
+  # combined with system CA bundle into '~/.aah/certs/ca-bundle.pem' and
+  # exported as 'SSL_CERT_FILE' and 'GIT_SSL_CAINFO', unless they are set.
+  ca_bundle = "/etc/ssl/certs/example-corp.pem"
+}
+
+Example:
+    aah -log-file=.aah/logs/aah.log run
+    aah -log-format=json build
+`
@@ -0,0 +1,518 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+)
+
+const (
+	cliReleaseURL      = "https://api.github.com/repos/go-aah/tools/releases/latest"
+	cliReleasesURL     = "https://api.github.com/repos/go-aah/tools/releases"
+	cliChecksumsAsset  = "checksums.txt"
+	cliSignatureAsset  = "checksums.txt.asc"
+	cliDownloadTimeout = 5 * time.Minute
+)
+
+// cliSigningKey is the fingerprint of aah CLI release signing key, it's
+// pinned at release build via '-ldflags "-X main.cliSigningKey=<fingerprint>"'.
+// Environment value 'AAH_CLI_SIGNING_KEY' overrides it, e.g. for mirrors.
+var cliSigningKey = ""
+
+var (
+	updateCmdFlags     = flag.NewFlagSet("update", flag.ContinueOnError)
+	updateCheckFlag    = updateCmdFlags.Bool("check", false, "Check the latest aah CLI version without updating")
+	updateReleaseFlag  = updateCmdFlags.String("releaseURL", "", "Release metadata URL, for mirrors. Default is GitHub latest release")
+	updateReleaseShort = updateCmdFlags.String("ru", "", "Release metadata URL, for mirrors. Default is GitHub latest release")
+	updateChannelFlag  = updateCmdFlags.String("channel", "", "Release channel 'latest' or pre-release channel e.g: beta, rc. Default is 'AAH_CLI_CHANNEL' environment value or 'latest'")
+	updateCmd          = &command{
+		Name:      "update",
+		UsageLine: "aah update [-check] [-channel] [-ru | -releaseURL]",
+		Flags:     updateCmdFlags,
+		Short:     "update aah CLI tool to the latest released version",
+		Long: `
+Checks the latest released aah CLI tool version, downloads the binary for
+your platform, verifies its SHA256 checksum and atomically replaces the
+current executable.
+
+Checksums file 'checksums.txt' is verified against its detached signature
+'checksums.txt.asc' via 'gpg', signature must be made by the pinned aah CLI
+release signing key. Public key is fetched from the key server if it's not
+in the gpg keyring. Executable is not replaced if the signature is missing
+or invalid.
+
+Pre-release channel such as 'beta' also considers its pre-releases, e.g.
+'v0.11.0-beta.2', newer stable release is preferred over them. Channel can be
+set via 'AAH_CLI_CHANNEL' environment value.
+
+Example(s):
+    aah update -check
+
+    aah update
+
+    aah update -channel=beta
+
+    aah -dry-run update
+`,
+	}
+)
+
+type (
+	cliRelease struct {
+		TagName    string             `json:"tag_name"`
+		Prerelease bool               `json:"prerelease"`
+		Assets     []*cliReleaseAsset `json:"assets"`
+	}
+
+	cliReleaseAsset struct {
+		Name        string `json:"name"`
+		DownloadURL string `json:"browser_download_url"`
+	}
+)
+
+func updateRun(args []string) {
+	_ = log.SetPattern("%message")
+	defer func() { _ = log.SetPattern(log.DefaultPattern) }()
+
+	channel := strings.ToLower(firstNonEmpty(*updateChannelFlag, os.Getenv("AAH_CLI_CHANNEL"), "latest"))
+	defaultURL := cliReleaseURL
+	if channel != "latest" {
+		defaultURL = cliReleasesURL
+	}
+
+	releaseURL := firstNonEmpty(*updateReleaseFlag, *updateReleaseShort, os.Getenv("AAH_CLI_RELEASE_URL"), defaultURL)
+	release, err := fetchCLIRelease(releaseURL, channel)
+	if err != nil {
+		fatalf("Unable to check latest aah CLI version: %s", err)
+	}
+
+	latest := strings.TrimPrefix(release.TagName, "v")
+	if compareReleaseVersion(latest, Version) <= 0 {
+		log.Infof("aah CLI tool v%s is up-to-date", Version)
+		return
+	}
+
+	log.Infof("New aah CLI version available: v%s (current v%s)", latest, Version)
+	if *updateCheckFlag {
+		return
+	}
+
+	assetName := cliAssetName()
+	asset := release.Asset(assetName)
+	if asset == nil {
+		fatalf("Release v%s does not have binary '%s' for your platform", latest, assetName)
+	}
+
+	checksums := release.Asset(cliChecksumsAsset)
+	if checksums == nil {
+		fatalf("Release v%s does not have '%s', unable to verify download", latest, cliChecksumsAsset)
+	}
+
+	signature := release.Asset(cliSignatureAsset)
+	if signature == nil {
+		fatalf("Release v%s does not have '%s', unable to verify download", latest, cliSignatureAsset)
+	}
+
+	signingKey := normalizeFingerprint(firstNonEmpty(os.Getenv("AAH_CLI_SIGNING_KEY"), cliSigningKey))
+	if ess.IsStrEmpty(signingKey) {
+		fatal("aah CLI tool is built without release signing key, set 'AAH_CLI_SIGNING_KEY' to verify the download")
+	}
+	if _, err = exec.LookPath("gpg"); err != nil {
+		fatal("'gpg' command is required to verify the download, install GnuPG")
+	}
+
+	exePath, err := os.Executable()
+	if err != nil {
+		fatal(err)
+	}
+	if exePath, err = filepath.EvalSymlinks(exePath); err != nil {
+		fatal(err)
+	}
+
+	if isDryRun() {
+		logDryRun("verify %s signature with key %s", cliChecksumsAsset, signingKey)
+		logDryRun("download %s", asset.DownloadURL)
+		logDryRun("replace %s", exePath)
+		return
+	}
+
+	expected, err := fetchChecksum(checksums.DownloadURL, signature.DownloadURL, signingKey, assetName)
+	if err != nil {
+		fatalf("Unable to get checksum: %s", err)
+	}
+
+	log.Infof("Downloading %s", asset.DownloadURL)
+	newExe, err := downloadCLIBinary(asset.DownloadURL, filepath.Dir(exePath), expected)
+	if err != nil {
+		fatalf("Unable to download aah CLI: %s", err)
+	}
+
+	if err = replaceExecutable(exePath, newExe); err != nil {
+		ess.DeleteFiles(newExe)
+		fatalf("Unable to replace aah CLI executable: %s", err)
+	}
+
+	log.Infof("aah CLI tool updated successfully to v%s", latest)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// cliRelease methods
+//___________________________________
+
+func (r *cliRelease) Asset(name string) *cliReleaseAsset {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// fetchCLIRelease method gets the newest release of the channel, release
+// metadata is either a release or list of releases.
+func fetchCLIRelease(releaseURL, channel string) (*cliRelease, error) {
+	resp, err := httpGet(releaseURL)
+	if err != nil {
+		return nil, err
+	}
+	defer ess.CloseQuietly(resp.Body)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return parseCLIReleases(body, channel)
+}
+
+// parseCLIReleases method returns the newest release of the channel from
+// release metadata. Channel 'latest' considers stable releases only, other
+// channels consider stable releases and pre-releases of the channel.
+func parseCLIReleases(body []byte, channel string) (*cliRelease, error) {
+	var releases []*cliRelease
+	if b := bytes.TrimSpace(body); len(b) > 0 && b[0] == '[' {
+		if err := json.Unmarshal(b, &releases); err != nil {
+			return nil, err
+		}
+	} else {
+		release := &cliRelease{}
+		if err := json.Unmarshal(b, release); err != nil {
+			return nil, err
+		}
+		releases = append(releases, release)
+	}
+
+	var newest *cliRelease
+	for _, r := range releases {
+		if ess.IsStrEmpty(r.TagName) {
+			continue
+		}
+		if rc := releaseChannel(r.TagName); rc != "latest" && rc != channel {
+			continue
+		}
+		if newest == nil || compareReleaseVersion(strings.TrimPrefix(r.TagName, "v"),
+			strings.TrimPrefix(newest.TagName, "v")) > 0 {
+			newest = r
+		}
+	}
+	if newest == nil {
+		return nil, fmt.Errorf("release version is empty for channel '%s'", channel)
+	}
+	return newest, nil
+}
+
+// fetchChecksum method gets the SHA256 checksum of given asset from
+// checksums file, its format is same as 'sha256sum' output. Checksums file
+// is verified against its signature made by the signing key.
+func fetchChecksum(checksumsURL, signatureURL, signingKey, assetName string) (string, error) {
+	checksums, err := httpGetBytes(checksumsURL)
+	if err != nil {
+		return "", err
+	}
+	sig, err := httpGetBytes(signatureURL)
+	if err != nil {
+		return "", err
+	}
+	if err = verifySignature(checksums, sig, signingKey); err != nil {
+		return "", err
+	}
+	return parseChecksum(checksums, assetName)
+}
+
+// parseChecksum method returns the SHA256 checksum of given asset from the
+// checksums content.
+func parseChecksum(checksums []byte, assetName string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == assetName {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("checksum not found for '%s'", assetName)
+}
+
+// verifySignature method verifies the detached signature of the content via
+// 'gpg', signature must be valid and made by the signing key or its subkey.
+// Public key is fetched from the key server if it's not in the keyring.
+func verifySignature(content, sig []byte, signingKey string) error {
+	dir, err := ioutil.TempDir("", "aah-update-")
+	if err != nil {
+		return err
+	}
+	defer ess.DeleteFiles(dir)
+
+	file, sigFile := filepath.Join(dir, cliChecksumsAsset), filepath.Join(dir, cliSignatureAsset)
+	if err = ioutil.WriteFile(file, content, permRWRR); err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(sigFile, sig, permRWRR); err != nil {
+		return err
+	}
+
+	status := gpgVerify(sigFile, file)
+	if bytes.Contains(status, []byte("[GNUPG:] NO_PUBKEY")) {
+		log.Infof("Fetching aah CLI release signing key %s", signingKey)
+		if out, err := exec.Command("gpg", "--batch", "--recv-keys", signingKey).CombinedOutput(); err != nil {
+			return fmt.Errorf("unable to fetch signing key %s: %s", signingKey, strings.TrimSpace(string(out)))
+		}
+		status = gpgVerify(sigFile, file)
+	}
+
+	for _, fpr := range signatureFingerprints(status) {
+		if fpr == signingKey {
+			return nil
+		}
+	}
+	return fmt.Errorf("'%s' signature is not valid or not made by the aah CLI release signing key %s", cliChecksumsAsset, signingKey)
+}
+
+// gpgVerify method returns the machine readable status of 'gpg --verify'.
+func gpgVerify(sigFile, file string) []byte {
+	log.Debug("Executing gpg --verify ", sigFile)
+	out, _ := exec.Command("gpg", "--batch", "--status-fd", "1", "--verify", sigFile, file).Output()
+	return out
+}
+
+// signatureFingerprints method returns the signing key and primary key
+// fingerprints of the valid signatures from 'gpg --status-fd' output, e.g.:
+//
+//	[GNUPG:] VALIDSIG <fingerprint> <date> ... <primary-key-fingerprint>
+func signatureFingerprints(status []byte) []string {
+	var fprs []string
+	for _, line := range strings.Split(string(status), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[0] != "[GNUPG:]" || fields[1] != "VALIDSIG" {
+			continue
+		}
+		fprs = append(fprs, fields[2])
+		if len(fields) >= 12 {
+			fprs = append(fprs, fields[11])
+		}
+	}
+	return fprs
+}
+
+// normalizeFingerprint method returns the key fingerprint in upper case
+// without spaces and '0x' prefix, same as 'gpg --status-fd' output.
+func normalizeFingerprint(fpr string) string {
+	fpr = strings.ToUpper(strings.Replace(strings.TrimSpace(fpr), " ", "", -1))
+	return strings.TrimPrefix(fpr, "0X")
+}
+
+// downloadCLIBinary method downloads the binary into given directory and
+// verifies its checksum. Same directory as executable is used, so final
+// rename is atomic.
+func downloadCLIBinary(url, dir, checksum string) (string, error) {
+	resp, err := httpGet(url)
+	if err != nil {
+		return "", err
+	}
+	defer ess.CloseQuietly(resp.Body)
+
+	f, err := ioutil.TempFile(dir, ".aah-update-")
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(f, h), resp.Body)
+	ess.CloseQuietly(f)
+	if err != nil {
+		ess.DeleteFiles(f.Name())
+		return "", err
+	}
+
+	if actual := hex.EncodeToString(h.Sum(nil)); actual != checksum {
+		ess.DeleteFiles(f.Name())
+		return "", fmt.Errorf("checksum mismatch, expected %s got %s", checksum, actual)
+	}
+
+	if err = os.Chmod(f.Name(), permRWXRXRX); err != nil {
+		ess.DeleteFiles(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// replaceExecutable method replaces the executable with new one. Running
+// executable cannot be overwritten on Windows, so it's moved aside first.
+func replaceExecutable(exePath, newExe string) error {
+	oldExe := exePath + ".old"
+	ess.DeleteFiles(oldExe)
+	if err := os.Rename(exePath, oldExe); err != nil {
+		return err
+	}
+
+	if err := os.Rename(newExe, exePath); err != nil {
+		// restore the current executable
+		_ = os.Rename(oldExe, exePath)
+		return err
+	}
+
+	if runtime.GOOS != "windows" {
+		ess.DeleteFiles(oldExe)
+	}
+	return nil
+}
+
+func httpGetBytes(url string) ([]byte, error) {
+	resp, err := httpGet(url)
+	if err != nil {
+		return nil, err
+	}
+	defer ess.CloseQuietly(resp.Body)
+	return ioutil.ReadAll(resp.Body)
+}
+
+func httpGet(url string) (*http.Response, error) {
+	resp, err := newHTTPClient(cliDownloadTimeout).Get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		ess.CloseQuietly(resp.Body)
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return resp, nil
+}
+
+// cliAssetName method returns the release binary name for current platform,
+// for e.g.: aah-linux-amd64, aah-windows-amd64.exe
+func cliAssetName() string {
+	name := fmt.Sprintf("aah-%s-%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// compareVersion method compares the dot separated numeric versions, it
+// returns -1, 0 or 1. Non-numeric suffix of the segment is ignored.
+func compareVersion(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		av, bv := versionSegment(as, i), versionSegment(bs, i)
+		if av < bv {
+			return -1
+		}
+		if av > bv {
+			return 1
+		}
+	}
+	return 0
+}
+
+// compareReleaseVersion method compares the versions with pre-release
+// precedence, it returns -1, 0 or 1. Stable version is higher than its
+// pre-releases, pre-release identifiers are compared numerically if both
+// are numeric otherwise lexically, e.g. '1.0.0-beta.2' < '1.0.0-beta.10'.
+func compareReleaseVersion(a, b string) int {
+	a, b = strings.SplitN(a, "+", 2)[0], strings.SplitN(b, "+", 2)[0]
+	ap, bp := strings.SplitN(a, "-", 2), strings.SplitN(b, "-", 2)
+	if c := compareVersion(ap[0], bp[0]); c != 0 {
+		return c
+	}
+	switch {
+	case len(ap) == 1 && len(bp) == 1:
+		return 0
+	case len(ap) == 1:
+		return 1
+	case len(bp) == 1:
+		return -1
+	}
+
+	as, bs := strings.Split(ap[1], "."), strings.Split(bp[1], ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil && an != bn:
+			if an < bn {
+				return -1
+			}
+			return 1
+		case aErr == nil && bErr != nil:
+			return -1
+		case aErr != nil && bErr == nil:
+			return 1
+		case as[i] != bs[i]:
+			if as[i] < bs[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
+
+func versionSegment(segments []string, i int) int {
+	if i >= len(segments) {
+		return 0
+	}
+
+	s := segments[i]
+	if idx := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' }); idx >= 0 {
+		s = s[:idx]
+	}
+	v, _ := strconv.Atoi(s)
+	return v
+}
+
+func init() {
+	updateCmd.Run = updateRun
+}
@@ -32,11 +32,11 @@ func loadAahProjectFile(baseDir string) (*config.Config, error) {
 	// read build config from 'aah.project'
 	aahProjectFile := filepath.Join(baseDir, aahProjectIdentifier)
 	if !ess.IsFileExists(aahProjectFile) {
-		fatal("Missing 'aah.project' file, not a valid aah framework application.")
+		fatalExit(exitConfigError, "Missing 'aah.project' file, not a valid aah framework application.")
 	}
 
 	log.Infof("Loading aah project file: %s", aahProjectFile)
-	return config.LoadFile(aahProjectFile)
+	return loadProjectConfig(aahProjectFile, baseDir)
 }
 
 func getNonEmptyAbsPath(patha, pathb string) string {
@@ -62,6 +62,25 @@ func firstNonEmpty(values ...string) string {
 	return ""
 }
 
+// appendUnique method appends the values not present in the slice, it
+// doesn't modify the given slice.
+func appendUnique(slice []string, values ...string) []string {
+	result := append([]string{}, slice...)
+	for _, v := range values {
+		found := false
+		for _, s := range result {
+			if s == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
 // getAppVersion method returns the aah application version, which used to display
 // version from compiled bnary
 // 		$ appname version
@@ -70,6 +89,9 @@ func firstNonEmpty(values ...string) string {
 // 		1. Env variable - AAH_APP_VERSION
 // 		2. git describe
 // 		3. version number from aah.project file
+//
+// Build metadata of 'build.version_metadata' is appended to the git describe
+// and aah.project version, e.g. '1.2.0+3f2a9c1'.
 func getAppVersion(appBaseDir string, cfg *config.Config) string {
 	// From env variable
 	if version := os.Getenv("AAH_APP_VERSION"); !ess.IsStrEmpty(version) {
@@ -83,20 +105,20 @@ func getAppVersion(appBaseDir string, cfg *config.Config) string {
 	if gitcmd, err := exec.LookPath("git"); err == nil {
 		appGitDir := filepath.Join(appBaseDir, ".git")
 		if !ess.IsFileExists(appGitDir) {
-			return version
+			return withVersionMetadata(version, versionMetadata(cfg, appBaseDir))
 		}
 
 		_ = os.Chdir(appBaseDir)
 		gitArgs := []string{fmt.Sprintf("--git-dir=%s", appGitDir), "describe", "--always", "--dirty"}
-		output, err := execCmd(gitcmd, gitArgs, false)
+		output, err := execReadCmd(gitcmd, gitArgs)
 		if err != nil {
-			return version
+			return withVersionMetadata(version, versionMetadata(cfg, appBaseDir))
 		}
 
 		version = strings.TrimSpace(output)
 	}
 
-	return version
+	return withVersionMetadata(version, versionMetadata(cfg, appBaseDir))
 }
 
 // getBuildDate method returns application build date, which used to display
@@ -115,7 +137,23 @@ func getBuildDate() string {
 	return time.Now().Format(time.RFC3339)
 }
 
+// execCmd method executes the command, which changes the state, so it's
+// skipped in dry-run mode. Use execReadCmd for read-only commands.
 func execCmd(cmdName string, args []string, stdout bool) (string, error) {
+	if isDryRun() {
+		logDryRun("execute %s", strings.Join(append([]string{cmdName}, args...), " "))
+		return "", nil
+	}
+	return execCommand(cmdName, args, stdout)
+}
+
+// execReadCmd method executes the read-only command and returns its output,
+// it's executed in dry-run mode too, e.g. 'git describe', 'go list'.
+func execReadCmd(cmdName string, args []string) (string, error) {
+	return execCommand(cmdName, args, false)
+}
+
+func execCommand(cmdName string, args []string, stdout bool) (string, error) {
 	cmd := exec.Command(cmdName, args...)
 	log.Debug("Executing ", strings.Join(cmd.Args, " "))
 
@@ -195,6 +233,40 @@ func excludeAndCreateSlice(arr []string, str string) []string {
 	return result
 }
 
+// aahUserDir method returns the aah CLI user directory '~/.aah'.
+func aahUserDir() string {
+	home := firstNonEmpty(os.Getenv("HOME"), os.Getenv("USERPROFILE"))
+	if ess.IsStrEmpty(home) {
+		home = os.TempDir()
+	}
+	return filepath.Join(home, ".aah")
+}
+
+func isStringInSlice(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
 func isAahProject(file string) bool {
 	return strings.HasSuffix(file, aahProjectIdentifier)
 }
+
+// findAppBaseDir method returns the base directory of the aah application
+// from import path or current directory, empty if it's not an aah
+// application.
+func findAppBaseDir(importPath string) string {
+	var dir string
+	if ess.IsStrEmpty(importPath) {
+		dir, _ = os.Getwd()
+	} else if !ess.IsStrEmpty(gosrcDir) {
+		dir = filepath.Join(gosrcDir, filepath.FromSlash(importPath))
+	}
+	if ess.IsStrEmpty(dir) || !ess.IsFileExists(filepath.Join(dir, aahProjectIdentifier)) {
+		return ""
+	}
+	return dir
+}
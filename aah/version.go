@@ -30,7 +30,6 @@ var (
 		Name:      "version",
 		UsageLine: "aah version [-all]",
 		Flags:     versionCmdFlags,
-		ArgsCount: 1,
 		Short:     "print aah framework version, go version and library versions",
 		Long: `
 	  Prints the aah framework, modules version and go version. With '-all' flag it will print all the library versions too.
@@ -44,10 +43,6 @@ var (
 )
 
 func versionRun(args []string) {
-	if err := versionCmdFlags.Parse(args); err != nil {
-		fatal(err)
-	}
-
 	fmt.Printf("Version Info:\n")
 	printVersion("aah framework", aah.Version)
 	printVersion("aah cli tool", Version)
@@ -0,0 +1,273 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+)
+
+// versionScheme is the application version scheme of 'build.version_scheme',
+// it's used by 'aah release' to validate and bump the version. Versions are
+// without tag prefix and build metadata.
+type versionScheme interface {
+	// Name returns the scheme name used in 'build.version_scheme'.
+	Name() string
+
+	// Valid reports whether the version is of the scheme.
+	Valid(version string) bool
+
+	// Next returns the version after previous version by bump part 'major',
+	// 'minor' or 'patch' at given time. Previous version is empty for the
+	// initial release.
+	Next(prev, bump string, now time.Time) (string, error)
+}
+
+// versionPrerelease is the pre-release suffix expression of the schemes.
+const versionPrerelease = `(-[0-9A-Za-z.-]+)?`
+
+var (
+	versionSchemes = map[string]func(cfg *config.Config) (versionScheme, error){}
+
+	semverRegex      = regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)` + versionPrerelease + `$`)
+	calverTokenRegex = regexp.MustCompile(`YYYY|0Y|YY|0M|MM|0D|DD|PATCH`)
+)
+
+// registerVersionScheme method adds the scheme constructor to the registry,
+// it's called from scheme's init.
+func registerVersionScheme(name string, fn func(cfg *config.Config) (versionScheme, error)) {
+	versionSchemes[name] = fn
+}
+
+func versionSchemeNames() []string {
+	var names []string
+	for name := range versionSchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// appVersionScheme method returns the version scheme configured in
+// 'build.version_scheme', default is 'semver'.
+func appVersionScheme(cfg *config.Config) (versionScheme, error) {
+	name := cfg.StringDefault("build.version_scheme", "semver")
+	fn, found := versionSchemes[name]
+	if !found {
+		return nil, fmt.Errorf("'build.version_scheme' value '%s' is not supported, choose one of %s",
+			name, strings.Join(versionSchemeNames(), ", "))
+	}
+	return fn(cfg)
+}
+
+// versionMetadata method returns the build metadata of
+// 'build.version_metadata', placeholders '{commit}' short commit hash and
+// '{date}' UTC date 'YYYYMMDD' are replaced and environment variables are
+// expanded, e.g. '{commit}.${BUILD_NUMBER}'.
+func versionMetadata(cfg *config.Config, baseDir string) string {
+	meta := cfg.StringDefault("build.version_metadata", "")
+	if ess.IsStrEmpty(meta) {
+		return ""
+	}
+
+	if strings.Contains(meta, "{commit}") {
+		commit, _ := gitOutput(baseDir, "rev-parse", "--short", "HEAD")
+		meta = strings.Replace(meta, "{commit}", commit, -1)
+	}
+	meta = strings.Replace(meta, "{date}", time.Now().UTC().Format("20060102"), -1)
+	return strings.Trim(os.ExpandEnv(meta), ".")
+}
+
+// withVersionMetadata method appends the build metadata to the version as
+// '<version>+<metadata>', version with metadata is returned as-is.
+func withVersionMetadata(version, meta string) string {
+	if ess.IsStrEmpty(version) || ess.IsStrEmpty(meta) || strings.Contains(version, "+") {
+		return version
+	}
+	return version + "+" + meta
+}
+
+// versionLabel method returns the version usable as Docker image tag and
+// Kubernetes label value, build metadata separator '+' is not allowed there.
+func versionLabel(version string) string {
+	return strings.Replace(version, "+", "_", -1)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// semver scheme
+//___________________________________
+
+// semverScheme is the semantic version 'MAJOR.MINOR.PATCH[-PRERELEASE]'.
+type semverScheme struct{}
+
+func (semverScheme) Name() string {
+	return "semver"
+}
+
+func (semverScheme) Valid(version string) bool {
+	return semverRegex.MatchString(version)
+}
+
+// Next method bumps the previous version, patch bump of pre-release version
+// releases it, e.g. '1.2.0-rc.1' becomes '1.2.0'. Initial previous version
+// is '0.0.0'.
+func (semverScheme) Next(prev, bump string, now time.Time) (string, error) {
+	m := semverRegex.FindStringSubmatch(firstNonEmpty(prev, "0.0.0"))
+	if m == nil {
+		return "", fmt.Errorf("previous release version '%s' is not semantic version, supply '-version'", prev)
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	patch, _ := strconv.Atoi(m[3])
+	switch bump {
+	case "major":
+		major, minor, patch = major+1, 0, 0
+	case "minor":
+		minor, patch = minor+1, 0
+	case "patch":
+		if ess.IsStrEmpty(m[4]) {
+			patch++
+		}
+	default:
+		return "", fmt.Errorf("version part '%s' is not supported, choose one of 'major', 'minor' or 'patch'", bump)
+	}
+	return fmt.Sprintf("%d.%d.%d", major, minor, patch), nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// calver scheme
+//___________________________________
+
+// calverScheme is the calendar version of format 'build.calver_format',
+// default is 'YYYY.MM.PATCH'. Format tokens are of https://calver.org:
+//
+//	YYYY   full year, e.g. 2017
+//	YY     short year, e.g. 17
+//	0Y     zero-padded short year, e.g. 07
+//	MM     month, e.g. 6
+//	0M     zero-padded month, e.g. 06
+//	DD     day, e.g. 1
+//	0D     zero-padded day, e.g. 01
+//	PATCH  release number within the date, starts from 0
+type calverScheme struct {
+	format string
+	regex  *regexp.Regexp
+	tokens []string
+}
+
+func newCalverScheme(cfg *config.Config) (versionScheme, error) {
+	c, err := parseCalverFormat(cfg.StringDefault("build.calver_format", "YYYY.MM.PATCH"))
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// parseCalverFormat method creates the calver scheme of the format, it
+// requires at least one date token before 'PATCH'.
+func parseCalverFormat(format string) (*calverScheme, error) {
+	tokens := calverTokenRegex.FindAllString(format, -1)
+	if len(tokens) == 0 || tokens[0] == "PATCH" {
+		return nil, fmt.Errorf("'build.calver_format' value '%s' requires date tokens, e.g: YYYY.MM.PATCH", format)
+	}
+
+	var expr string
+	parts := calverTokenRegex.Split(format, -1)
+	for i, p := range parts {
+		expr += regexp.QuoteMeta(p)
+		if i < len(tokens) {
+			switch tokens[i] {
+			case "YYYY":
+				expr += `(\d{4})`
+			case "0Y", "0M", "0D":
+				expr += `(\d{2})`
+			case "PATCH":
+				expr += `(\d+)`
+			default:
+				expr += `([1-9]\d*)`
+			}
+		}
+	}
+
+	return &calverScheme{
+		format: format,
+		regex:  regexp.MustCompile("^" + expr + versionPrerelease + "$"),
+		tokens: tokens,
+	}, nil
+}
+
+func (c *calverScheme) Name() string {
+	return "calver"
+}
+
+func (c *calverScheme) Valid(version string) bool {
+	return c.regex.MatchString(version)
+}
+
+// Next method returns the version of the given date, bump part is not
+// applicable. PATCH is incremented if the previous version is of same date
+// otherwise it starts from 0. Pre-release version of same date is released
+// with same PATCH, e.g. '2017.06.1-rc.1' becomes '2017.06.1'.
+func (c *calverScheme) Next(prev, bump string, now time.Time) (string, error) {
+	values := c.values(now)
+	patch := 0
+	if !ess.IsStrEmpty(prev) {
+		m := c.regex.FindStringSubmatch(prev)
+		if m == nil {
+			return "", fmt.Errorf("previous release version '%s' is not of calver format '%s', supply '-version'", prev, c.format)
+		}
+
+		sameDate, prevPatch := true, -1
+		for i, t := range c.tokens {
+			if t == "PATCH" {
+				prevPatch, _ = strconv.Atoi(m[i+1])
+			} else if m[i+1] != values[t] {
+				sameDate = false
+			}
+		}
+		prerelease := !ess.IsStrEmpty(m[len(m)-1])
+		if sameDate && prevPatch < 0 && !prerelease {
+			return "", fmt.Errorf("version '%s' is already released, add 'PATCH' to 'build.calver_format'", prev)
+		}
+		if sameDate && prevPatch >= 0 {
+			patch = prevPatch
+			if !prerelease {
+				patch++
+			}
+		}
+	}
+
+	values["PATCH"] = strconv.Itoa(patch)
+	return calverTokenRegex.ReplaceAllStringFunc(c.format, func(t string) string {
+		return values[t]
+	}), nil
+}
+
+func (c *calverScheme) values(now time.Time) map[string]string {
+	return map[string]string{
+		"YYYY": strconv.Itoa(now.Year()),
+		"YY":   strconv.Itoa(now.Year() % 100),
+		"0Y":   fmt.Sprintf("%02d", now.Year()%100),
+		"MM":   strconv.Itoa(int(now.Month())),
+		"0M":   fmt.Sprintf("%02d", int(now.Month())),
+		"DD":   strconv.Itoa(now.Day()),
+		"0D":   fmt.Sprintf("%02d", now.Day()),
+	}
+}
+
+func init() {
+	registerVersionScheme("semver", func(cfg *config.Config) (versionScheme, error) {
+		return semverScheme{}, nil
+	})
+	registerVersionScheme("calver", newCalverScheme)
+}
@@ -9,9 +9,9 @@
 package main
 
 import (
-	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"aahframework.org/test.v0/assert"
 )
@@ -22,8 +22,123 @@ func TestVersion(t *testing.T) {
 	main()
 
 	*allFlag = false
-	fatal = func(v ...interface{}) {
-		assert.Equal(t, "bad flag syntax: ---all", fmt.Sprint(v...))
+	var exitCode int
+	exit = func(code int) { exitCode = code }
+	versionCmd.Exec([]string{"---all"})
+	assert.Equal(t, exitUsage, exitCode)
+}
+
+func TestVersionCompare(t *testing.T) {
+	assert.Equal(t, 0, compareVersion("0.6", "0.6.0"))
+	assert.Equal(t, 1, compareVersion("0.7", "0.6"))
+	assert.Equal(t, -1, compareVersion("0.6.1", "0.10"))
+	assert.Equal(t, 1, compareVersion("1.0.0-beta.1", "0.9.9"))
+	assert.Equal(t, 0, compareVersion("1.2.3-rc1", "1.2.3"))
+
+	assert.Equal(t, 1, compareReleaseVersion("1.2.3", "1.2.3-rc.1"))
+	assert.Equal(t, -1, compareReleaseVersion("1.2.3-beta.2", "1.2.3-beta.10"))
+	assert.Equal(t, -1, compareReleaseVersion("1.2.3-beta.2", "1.2.3-rc.1"))
+	assert.Equal(t, 1, compareReleaseVersion("1.2.3-beta.2", "1.2.3-beta"))
+	assert.Equal(t, 1, compareReleaseVersion("1.3.0-beta.1", "1.2.3"))
+	assert.Equal(t, 0, compareReleaseVersion("1.2.3+abc", "1.2.3"))
+}
+
+func TestVersionUpdateChannel(t *testing.T) {
+	releases := []byte(`[
+		{"tag_name": "v0.11.0-rc.1", "prerelease": true},
+		{"tag_name": "v0.10.1"},
+		{"tag_name": "v0.11.0-beta.2", "prerelease": true},
+		{"tag_name": "v0.11.0-beta.1", "prerelease": true}
+	]`)
+
+	r, err := parseCLIReleases(releases, "latest")
+	assert.Nil(t, err)
+	assert.Equal(t, "v0.10.1", r.TagName)
+
+	r, err = parseCLIReleases(releases, "beta")
+	assert.Nil(t, err)
+	assert.Equal(t, "v0.11.0-beta.2", r.TagName)
+
+	r, err = parseCLIReleases([]byte(`{"tag_name": "v0.10.1"}`), "beta")
+	assert.Nil(t, err)
+	assert.Equal(t, "v0.10.1", r.TagName)
+
+	_, err = parseCLIReleases([]byte(`[{"tag_name": "v0.11.0-rc.1"}]`), "latest")
+	assert.NotNil(t, err)
+}
+
+func TestVersionSchemes(t *testing.T) {
+	now := time.Date(2017, 6, 1, 10, 0, 0, 0, time.UTC)
+	semver := semverScheme{}
+	for _, c := range []struct{ prev, bump, expected string }{
+		{"", "patch", "0.0.1"},
+		{"1.2.3", "patch", "1.2.4"},
+		{"1.2.3", "minor", "1.3.0"},
+		{"1.2.3", "major", "2.0.0"},
+		{"1.3.0-rc.1", "patch", "1.3.0"},
+	} {
+		v, err := semver.Next(c.prev, c.bump, now)
+		assert.Nil(t, err)
+		assert.Equal(t, c.expected, v)
 	}
-	versionRun([]string{"---all"})
+	_, err := semver.Next("1.2.3", "build", now)
+	assert.NotNil(t, err)
+	_, err = semver.Next("2017.06", "patch", now)
+	assert.NotNil(t, err)
+	assert.True(t, semver.Valid("1.2.3-rc.1"))
+	assert.False(t, semver.Valid("1.2"))
+
+	calver, err := parseCalverFormat("YYYY.0M.PATCH")
+	assert.Nil(t, err)
+	for _, c := range []struct{ prev, expected string }{
+		{"", "2017.06.0"},
+		{"2017.05.3", "2017.06.0"},
+		{"2017.06.0", "2017.06.1"},
+		{"2017.06.2-rc.1", "2017.06.2"},
+	} {
+		v, err := calver.Next(c.prev, "patch", now)
+		assert.Nil(t, err)
+		assert.Equal(t, c.expected, v)
+	}
+	assert.True(t, calver.Valid("2017.06.12"))
+	assert.False(t, calver.Valid("2017.6.12"))
+	_, err = calver.Next("1.2.3", "patch", now)
+	assert.NotNil(t, err)
+
+	daily, err := parseCalverFormat("YY.MM.DD")
+	assert.Nil(t, err)
+	v, err := daily.Next("", "patch", now)
+	assert.Nil(t, err)
+	assert.Equal(t, "17.6.1", v)
+	_, err = daily.Next("17.6.1", "patch", now)
+	assert.NotNil(t, err)
+
+	_, err = parseCalverFormat("PATCH.YYYY")
+	assert.NotNil(t, err)
+
+	assert.Equal(t, "1.2.0+3f2a9c1", withVersionMetadata("1.2.0", "3f2a9c1"))
+	assert.Equal(t, "1.2.0+build.1", withVersionMetadata("1.2.0+build.1", "3f2a9c1"))
+	assert.Equal(t, "1.2.0", withVersionMetadata("1.2.0", ""))
+	assert.Equal(t, "1.2.0_3f2a9c1", versionLabel("1.2.0+3f2a9c1"))
+}
+
+func TestVersionUpdateSignature(t *testing.T) {
+	checksums := []byte("3f2a9c1e  aah-linux-amd64\n9b8c7d6e  *aah-windows-amd64.exe\n")
+	sum, err := parseChecksum(checksums, "aah-windows-amd64.exe")
+	assert.Nil(t, err)
+	assert.Equal(t, "9b8c7d6e", sum)
+
+	_, err = parseChecksum(checksums, "aah-darwin-amd64")
+	assert.Equal(t, "checksum not found for 'aah-darwin-amd64'", err.Error())
+
+	status := []byte(`[GNUPG:] NEWSIG
+[GNUPG:] GOODSIG 1A2B3C4D5E6F7A8B aah framework <release@aahframework.org>
+[GNUPG:] VALIDSIG 0123456789ABCDEF0123456789ABCDEF01234567 2017-06-01 1496300000 0 4 0 1 8 00 FEDCBA9876543210FEDCBA9876543210FEDCBA98
+[GNUPG:] TRUST_UNDEFINED 0 pgp
+`)
+	assert.Equal(t, []string{"0123456789ABCDEF0123456789ABCDEF01234567", "FEDCBA9876543210FEDCBA9876543210FEDCBA98"},
+		signatureFingerprints(status))
+	assert.Nil(t, signatureFingerprints([]byte("[GNUPG:] BADSIG 1A2B3C4D5E6F7A8B aah framework\n")))
+
+	assert.Equal(t, "FEDCBA9876543210FEDCBA9876543210FEDCBA98", normalizeFingerprint(" 0xfedc ba98 7654 3210 FEDC BA98 7654 3210 FEDC BA98 "))
 }
@@ -0,0 +1,370 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"aahframework.org/config.v0"
+	"aahframework.org/essentials.v0"
+	"aahframework.org/log.v0"
+	"gopkg.in/fsnotify.v1"
+)
+
+var (
+	defaultWatchDirs     = []string{"app", "config", "views"}
+	defaultWatchExcludes = []string{"*_test.go", "*.tmp", "*.bak", "*.swp"}
+)
+
+type (
+	// watchConfig is the 'watch' section of 'aah.project'.
+	watchConfig struct {
+		Dirs         []string
+		Includes     []string
+		Excludes     []string
+		Debounce     time.Duration
+		Poll         bool
+		PollInterval time.Duration
+	}
+
+	// watcher watches the application directories recursively and delivers
+	// the changed files in batches via `Changes`. It uses file system
+	// notifications, or polls the modification time when 'watch.poll' is
+	// enabled, for e.g. on NFS or Docker volumes where inotify doesn't fire.
+	watcher struct {
+		Changes chan []string
+
+		cfg     *watchConfig
+		baseDir string
+		ignores []string
+		events  chan string
+		fsw     *fsnotify.Watcher
+		modTime map[string]time.Time
+		done    chan struct{}
+	}
+)
+
+// loadWatchConfig method reads the 'watch' section from 'aah.project'.
+//
+//	watch {
+//	  dirs = ["app", "config", "views"]
+//	  includes = ["*.go", "*.conf", "*.html"]
+//	  excludes = ["*_test.go", "app/generated/*"]
+//	  debounce = "300ms"
+//	  poll = false
+//	  poll_interval = "1s"
+//	}
+func loadWatchConfig(cfg *config.Config) (*watchConfig, error) {
+	wc := &watchConfig{Dirs: defaultWatchDirs, Excludes: defaultWatchExcludes}
+	if dirs, found := cfg.StringList("watch.dirs"); found {
+		wc.Dirs = dirs
+	}
+	wc.Includes, _ = cfg.StringList("watch.includes")
+	if excludes, found := cfg.StringList("watch.excludes"); found {
+		wc.Excludes = excludes
+	}
+	wc.Poll = cfg.BoolDefault("watch.poll", false)
+
+	var err error
+	if wc.Debounce, err = time.ParseDuration(cfg.StringDefault("watch.debounce", "300ms")); err != nil {
+		return nil, fmt.Errorf("'watch.debounce' is invalid: %s", err)
+	}
+	if wc.PollInterval, err = time.ParseDuration(cfg.StringDefault("watch.poll_interval", "1s")); err != nil {
+		return nil, fmt.Errorf("'watch.poll_interval' is invalid: %s", err)
+	}
+	if wc.PollInterval <= 0 {
+		return nil, fmt.Errorf("'watch.poll_interval' must be positive")
+	}
+
+	for _, p := range append(append([]string{}, wc.Includes...), wc.Excludes...) {
+		if _, err = filepath.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("'watch' pattern '%s' is invalid: %s", p, err)
+		}
+	}
+	return wc, nil
+}
+
+// newWatcher method creates the watcher for the configured directories
+// relative to the base directory. Ignored files, such as generated sources,
+// are not reported.
+func newWatcher(baseDir string, cfg *watchConfig, ignores []string) (*watcher, error) {
+	w := &watcher{
+		Changes: make(chan []string),
+		cfg:     cfg,
+		baseDir: baseDir,
+		ignores: ignores,
+		events:  make(chan string),
+		modTime: map[string]time.Time{},
+		done:    make(chan struct{}),
+	}
+
+	if cfg.Poll {
+		w.scan(false)
+		return w, nil
+	}
+
+	var err error
+	if w.fsw, err = fsnotify.NewWatcher(); err != nil {
+		return nil, err
+	}
+
+	for _, dir := range w.dirs() {
+		if err = w.addDir(dir); err != nil {
+			_ = w.fsw.Close()
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// Start method starts delivering the file changes, it's non-blocking.
+func (w *watcher) Start() {
+	if w.cfg.Poll {
+		go w.poll()
+	} else {
+		go w.notify()
+	}
+	go w.loop()
+}
+
+// Close method stops the watcher.
+func (w *watcher) Close() {
+	close(w.done)
+	if w.fsw != nil {
+		_ = w.fsw.Close()
+	}
+}
+
+// Mode method returns the watch mode description.
+func (w *watcher) Mode() string {
+	if w.cfg.Poll {
+		return fmt.Sprintf("polling every %s", w.cfg.PollInterval)
+	}
+	return "file system notifications"
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// loop method debounces the file change events and delivers them.
+func (w *watcher) loop() {
+	changed := map[string]bool{}
+	var timer <-chan time.Time
+	for {
+		select {
+		case <-w.done:
+			return
+		case f := <-w.events:
+			changed[f] = true
+			timer = time.After(w.cfg.Debounce)
+		case <-timer:
+			files := make([]string, 0, len(changed))
+			for f := range changed {
+				files = append(files, f)
+			}
+			sort.Strings(files)
+			changed, timer = map[string]bool{}, nil
+
+			select {
+			case w.Changes <- files:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+func (w *watcher) notify() {
+	for {
+		select {
+		case <-w.done:
+			return
+		case e, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+
+			if e.Op&fsnotify.Create == fsnotify.Create {
+				if fi, err := os.Stat(e.Name); err == nil && fi.IsDir() {
+					if err = w.addDir(e.Name); err != nil {
+						log.Errorf("Unable to watch directory '%s': %s", e.Name, err)
+					}
+					continue
+				}
+			}
+
+			if e.Op == fsnotify.Chmod || !w.isWatched(e.Name) {
+				continue
+			}
+
+			log.Tracef("File change: %s", e)
+			w.emit(e.Name)
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			log.Errorf("File watcher error: %s", err)
+		}
+	}
+}
+
+func (w *watcher) poll() {
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.scan(true)
+		}
+	}
+}
+
+// scan method compares the modification time of watched files with
+// previous scan, new, modified and deleted files are emitted.
+func (w *watcher) scan(emit bool) {
+	seen := map[string]bool{}
+	for _, dir := range w.dirs() {
+		_ = ess.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+
+			if info.IsDir() {
+				if w.isExcluded(path) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if !w.isWatched(path) {
+				return nil
+			}
+
+			seen[path] = true
+			if mt, found := w.modTime[path]; !found || !mt.Equal(info.ModTime()) {
+				w.modTime[path] = info.ModTime()
+				if emit {
+					w.emit(path)
+				}
+			}
+			return nil
+		})
+	}
+
+	for path := range w.modTime {
+		if !seen[path] {
+			delete(w.modTime, path)
+			if emit {
+				w.emit(path)
+			}
+		}
+	}
+}
+
+func (w *watcher) emit(path string) {
+	select {
+	case w.events <- path:
+	case <-w.done:
+	}
+}
+
+func (w *watcher) dirs() []string {
+	var dirs []string
+	for _, d := range w.cfg.Dirs {
+		if dir := filepath.Join(w.baseDir, filepath.FromSlash(d)); ess.IsFileExists(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// addDir method adds the directory and its sub-directories to the watcher,
+// fsnotify is not recursive.
+func (w *watcher) addDir(dir string) error {
+	return w.walkDirs(dir, w.fsw.Add)
+}
+
+// walkDirs method calls fn for the directory and its sub-directories which
+// are not excluded.
+func (w *watcher) walkDirs(dir string, fn func(path string) error) error {
+	return ess.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return err
+		}
+
+		if w.isExcluded(path) {
+			return filepath.SkipDir
+		}
+		return fn(path)
+	})
+}
+
+// isWatched method reports whether the file change should be delivered,
+// file must match one of the includes (if any) and none of the excludes.
+func (w *watcher) isWatched(path string) bool {
+	if w.isExcluded(path) {
+		return false
+	}
+
+	if len(w.cfg.Includes) == 0 {
+		return true
+	}
+	return w.match(w.cfg.Includes, path)
+}
+
+func (w *watcher) isExcluded(path string) bool {
+	name := filepath.Base(path)
+	if strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~") {
+		return true
+	}
+
+	for _, f := range w.ignores {
+		if path == f {
+			return true
+		}
+	}
+	return w.match(w.cfg.Excludes, path)
+}
+
+// match method matches the patterns against the file name and the path
+// relative to the base directory.
+func (w *watcher) match(patterns []string, file string) bool {
+	name := filepath.Base(file)
+	rel := w.relPath(file)
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, name); ok {
+			return true
+		}
+		if ok, _ := path.Match(p, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *watcher) relPath(path string) string {
+	if r, err := filepath.Rel(w.baseDir, path); err == nil {
+		path = r
+	}
+	return filepath.ToSlash(path)
+}
+
+// relPaths method returns the files relative to the base directory.
+func (w *watcher) relPaths(files []string) []string {
+	rel := make([]string, 0, len(files))
+	for _, f := range files {
+		rel = append(rel, w.relPath(f))
+	}
+	return rel
+}
@@ -0,0 +1,37 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+func TestWatchMatch(t *testing.T) {
+	baseDir := filepath.FromSlash("/src/github.com/user/app")
+	w := &watcher{
+		baseDir: baseDir,
+		ignores: []string{filepath.Join(baseDir, "app", "aah.go")},
+		cfg: &watchConfig{
+			Includes: []string{"*.go", "*.conf"},
+			Excludes: []string{"*_test.go", "app/generated/*"},
+		},
+	}
+
+	file := func(p string) string { return filepath.Join(baseDir, filepath.FromSlash(p)) }
+	assert.True(t, w.isWatched(file("app/controllers/app.go")))
+	assert.True(t, w.isWatched(file("config/routes.conf")))
+	assert.False(t, w.isWatched(file("views/index.html")))
+	assert.False(t, w.isWatched(file("app/controllers/app_test.go")))
+	assert.False(t, w.isWatched(file("app/generated/models.go")))
+	assert.False(t, w.isWatched(file("app/aah.go")))
+	assert.False(t, w.isWatched(file("app/.app.go.swp")))
+
+	w.cfg.Includes = nil
+	assert.True(t, w.isWatched(file("views/index.html")))
+	assert.Equal(t, []string{"config/routes.conf"}, w.relPaths([]string{file("config/routes.conf")}))
+}
@@ -0,0 +1,130 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package golden provides golden file test helpers for aah application,
+// typically to compare the rendered view output. Golden files are stored at
+// 'testdata/golden/<name>.golden' of the test package and updated via
+// 'aah test -update-golden'.
+//
+//	func TestUserProfileView(t *testing.T) {
+//		buf := &bytes.Buffer{}
+//		if err := tmpl.Execute(buf, data); err != nil {
+//			t.Fatal(err)
+//		}
+//		golden.Assert(t, "user_profile.html", buf.Bytes())
+//	}
+package golden
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// UpdateEnv is the environment variable to update the golden files instead
+// of comparing, it's set by 'aah test -update-golden'.
+const UpdateEnv = "AAH_UPDATE_GOLDEN"
+
+// Dir is the golden files directory relative to the test package.
+var Dir = filepath.Join("testdata", "golden")
+
+// TestingT is the subset of 'testing.TB' used by the helpers.
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+// Update method returns true if golden files are to be updated.
+func Update() bool {
+	v := strings.ToLower(os.Getenv(UpdateEnv))
+	return v == "true" || v == "1"
+}
+
+// Path method returns the golden file path of given name.
+func Path(name string) string {
+	return filepath.Join(Dir, filepath.FromSlash(name)+".golden")
+}
+
+// Assert method compares the actual output with golden file of given name,
+// line endings are normalized. In update mode golden file is written with
+// actual output.
+func Assert(t TestingT, name string, actual []byte) {
+	helper(t)
+	file := Path(name)
+	actual = normalize(actual)
+
+	if Update() {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatalf("golden: %s", err)
+		}
+		if err := ioutil.WriteFile(file, actual, 0644); err != nil {
+			t.Fatalf("golden: %s", err)
+		}
+		return
+	}
+
+	expected, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			t.Fatalf("golden: file '%s' does not exist, run 'aah test -update-golden' to create it", file)
+			return
+		}
+		t.Fatalf("golden: %s", err)
+		return
+	}
+
+	if msg := diff(normalize(expected), actual); msg != "" {
+		t.Errorf("golden: output mismatch with '%s', run 'aah test -update-golden' if it's expected\n%s", file, msg)
+	}
+}
+
+// AssertString method is same as 'Assert' for string output.
+func AssertString(t TestingT, name, actual string) {
+	helper(t)
+	Assert(t, name, []byte(actual))
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// helper method marks the caller as test helper on go1.9 and above.
+func helper(t TestingT) {
+	if h, ok := t.(interface {
+		Helper()
+	}); ok {
+		h.Helper()
+	}
+}
+
+func normalize(b []byte) []byte {
+	return bytes.Replace(b, []byte("\r\n"), []byte("\n"), -1)
+}
+
+// diff method returns the first differing line of expected and actual,
+// empty if both are same.
+func diff(expected, actual []byte) string {
+	if bytes.Equal(expected, actual) {
+		return ""
+	}
+
+	el := strings.Split(string(expected), "\n")
+	al := strings.Split(string(actual), "\n")
+	for i := 0; i < len(el) || i < len(al); i++ {
+		var e, a string
+		if i < len(el) {
+			e = el[i]
+		}
+		if i < len(al) {
+			a = al[i]
+		}
+		if e != a || i >= len(el) || i >= len(al) {
+			return fmt.Sprintf("line %d:\n  expected: %q\n    actual: %q", i+1, e, a)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,67 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// go-aah/tools source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package golden
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframework.org/test.v0/assert"
+)
+
+type recorder struct {
+	errors []string
+	fatal  bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.fatal = true
+	r.Errorf(format, args...)
+}
+
+func TestGoldenAssert(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aah-golden")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	defaultDir := Dir
+	Dir = filepath.Join(dir, "golden")
+	defer func() { Dir = defaultDir }()
+
+	r := &recorder{}
+	AssertString(r, "views/user.html", "<h1>User</h1>\n")
+	assert.True(t, r.fatal)
+	assert.True(t, strings.Contains(r.errors[0], "run 'aah test -update-golden'"))
+
+	_ = os.Setenv(UpdateEnv, "true")
+	r = &recorder{}
+	AssertString(r, "views/user.html", "<h1>User</h1>\r\n<p>Jeeva</p>\r\n")
+	_ = os.Unsetenv(UpdateEnv)
+	assert.Nil(t, r.errors)
+
+	b, err := ioutil.ReadFile(filepath.Join(Dir, "views", "user.html.golden"))
+	assert.Nil(t, err)
+	assert.Equal(t, "<h1>User</h1>\n<p>Jeeva</p>\n", string(b))
+
+	r = &recorder{}
+	AssertString(r, "views/user.html", "<h1>User</h1>\n<p>Jeeva</p>\n")
+	assert.Nil(t, r.errors)
+
+	AssertString(r, "views/user.html", "<h1>User</h1>\n<p>aah</p>\n")
+	assert.Equal(t, 1, len(r.errors))
+	assert.True(t, strings.Contains(r.errors[0], "line 2:\n  expected: \"<p>Jeeva</p>\"\n    actual: \"<p>aah</p>\""))
+
+	assert.Equal(t, "line 3:\n  expected: \"\"\n    actual: \"extra\"", diff([]byte("a\nb\n"), []byte("a\nb\nextra")))
+}